@@ -129,7 +129,11 @@ kubernetes controller which manages devices so that the device metadata/status d
 				// By default, IptablesManager manages tunnel port related iptables rules
 				// The internal mode will share the host network, forward to the stream port.
 				streamPort := int(config.Modules.CloudStream.StreamPort)
-				go iptables.NewIptablesManager(config.KubeAPIConfig, streamPort).Run(ctx)
+				backend := v1alpha1.IptablesBackend
+				if config.Modules.IptablesManager != nil && config.Modules.IptablesManager.Backend != "" {
+					backend = config.Modules.IptablesManager.Backend
+				}
+				go iptables.NewIptablesManager(config.KubeAPIConfig, streamPort, backend).Run(ctx)
 			}
 
 			// Start all modules
@@ -18,12 +18,15 @@ package options
 
 import (
 	cliflag "k8s.io/component-base/cli/flag"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
 )
 
 // IptablesManagerOptions config
 type IptablesManagerOptions struct {
 	KubeConfig  string
 	ForwardPort int
+	Backend     string
 }
 
 // NewIptablesManagerOptions returns options object
@@ -31,6 +34,7 @@ func NewIptablesManagerOptions() *IptablesManagerOptions {
 	return &IptablesManagerOptions{
 		KubeConfig:  "",
 		ForwardPort: 10003,
+		Backend:     string(v1alpha1.IptablesBackend),
 	}
 }
 
@@ -39,5 +43,6 @@ func (o *IptablesManagerOptions) Flags() (fss cliflag.NamedFlagSets) {
 	fs := fss.FlagSet("IptablesManager")
 	fs.StringVar(&o.KubeConfig, "kubeconfig", o.KubeConfig, "The KubeConfig path. Flags override values in this file.")
 	fs.IntVar(&o.ForwardPort, "forwardport", o.ForwardPort, "The forward port, default is the stream port, 10003.")
+	fs.StringVar(&o.Backend, "backend", o.Backend, "The backend programming the rules, valid backend can use iptables or nftables.")
 	return
 }
@@ -46,6 +46,12 @@ func NewIptablesManagerCommand() *cobra.Command {
 			verflag.PrintAndExitIfRequested()
 			flag.PrintFlags(cmd.Flags())
 
+			backend := v1alpha1.IptablesMgrBackend(opts.Backend)
+			if backend != v1alpha1.IptablesBackend && backend != v1alpha1.NftablesBackend {
+				klog.Exitf("invalid backend %q, valid backend can use %s or %s", opts.Backend,
+					v1alpha1.IptablesBackend, v1alpha1.NftablesBackend)
+			}
+
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
 
@@ -57,7 +63,7 @@ func NewIptablesManagerCommand() *cobra.Command {
 				Burst:       constants.DefaultKubeBurst,
 				KubeConfig:  opts.KubeConfig,
 			}
-			go iptables.NewIptablesManager(kubeAPIConfig, opts.ForwardPort).Run(ctx)
+			go iptables.NewIptablesManager(kubeAPIConfig, opts.ForwardPort, backend).Run(ctx)
 
 			c := make(chan os.Signal, 1)
 			signal.Notify(c, syscall.SIGINT, syscall.SIGHUP, syscall.SIGTERM,
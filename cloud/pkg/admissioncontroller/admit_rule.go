@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/klog/v2"
 
 	rulesv1 "github.com/kubeedge/api/apis/rules/v1"
@@ -15,6 +16,8 @@ var (
 		{rulesv1.RuleEndpointTypeRest, rulesv1.RuleEndpointTypeEventBus},
 		{rulesv1.RuleEndpointTypeRest, rulesv1.RuleEndpointTypeServiceBus},
 		{rulesv1.RuleEndpointTypeEventBus, rulesv1.RuleEndpointTypeRest},
+		{rulesv1.RuleEndpointTypeEventBus, rulesv1.RuleEndpointTypeEventBus},
+		{rulesv1.RuleEndpointTypeEventBus, rulesv1.RuleEndpointTypeServiceBus},
 	}
 )
 
@@ -102,17 +105,23 @@ func validateSourceRuleEndpoint(ruleEndpoint *rulesv1.RuleEndpoint, sourceResour
 		if !exist {
 			return fmt.Errorf("\"topic\" property missed in sourceResource when ruleEndpoint is \"eventbus\"")
 		}
-		_, exist = sourceResource["node_name"]
-		if !exist {
-			return fmt.Errorf("\"node_name\" property missed in sourceResource when ruleEndpoint is \"eventbus\"")
+		_, nodeExist := sourceResource["node_name"]
+		_, groupExist := sourceResource["node_group"]
+		if !nodeExist && !groupExist {
+			return fmt.Errorf("\"node_name\" or \"node_group\" property missed in sourceResource when ruleEndpoint is \"eventbus\"")
+		}
+		if nodeExist && groupExist {
+			return fmt.Errorf("\"node_name\" and \"node_group\" properties can't be both set in sourceResource when ruleEndpoint is \"eventbus\"")
 		}
 		rules, err := controller.listRule(ruleEndpoint.Namespace)
 		if err != nil {
 			return err
 		}
 		for _, r := range rules {
-			if sourceResource["topic"] == r.Spec.SourceResource["topic"] && sourceResource["node_name"] == r.Spec.SourceResource["node_name"] {
-				return fmt.Errorf("source properties exist in Rule %s/%s. Node_name: %s, topic: %s", r.Namespace, r.Name, sourceResource["node_name"], sourceResource["topic"])
+			if sourceResource["topic"] == r.Spec.SourceResource["topic"] && sourceResource["node_name"] == r.Spec.SourceResource["node_name"] &&
+				sourceResource["node_group"] == r.Spec.SourceResource["node_group"] {
+				return fmt.Errorf("source properties exist in Rule %s/%s. Node_name: %s, node_group: %s, topic: %s", r.Namespace, r.Name,
+					sourceResource["node_name"], sourceResource["node_group"], sourceResource["topic"])
 			}
 		}
 	}
@@ -120,6 +129,11 @@ func validateSourceRuleEndpoint(ruleEndpoint *rulesv1.RuleEndpoint, sourceResour
 }
 
 func validateTargetRuleEndpoint(ruleEndpoint *rulesv1.RuleEndpoint, targetResource map[string]string) error {
+	if selector, exist := targetResource["node_selector"]; exist {
+		if _, err := labels.Parse(selector); err != nil {
+			return fmt.Errorf("invalid \"node_selector\" property in targetResource: %v", err)
+		}
+	}
 	switch ruleEndpoint.Spec.RuleEndpointType {
 	case rulesv1.RuleEndpointTypeRest:
 		_, exist := targetResource["resource"]
@@ -19,21 +19,19 @@ package authorization
 import (
 	"context"
 	"crypto/tls"
-	stdx509 "crypto/x509"
-	"encoding/pem"
 	"fmt"
 	"net/http"
 
 	"k8s.io/apiserver/pkg/authentication/request/x509"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	"k8s.io/apiserver/pkg/endpoints/request"
-	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/cmd/kubeadm/app/constants"
 
 	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
 	cloudhubmodel "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
 	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/revocation"
 	"github.com/kubeedge/viaduct/pkg/conn"
 )
 
@@ -41,6 +39,8 @@ type cloudhubAuthorizer struct {
 	enabled bool
 	debug   bool
 	authz   authorizer.Authorizer
+	// revocation is checked even if the authorizer is disabled or in debug mode
+	revocation *revocation.List
 }
 
 func (r *cloudhubAuthorizer) AdmitMessage(message beehivemodel.Message, hubInfo cloudhubmodel.HubInfo) error {
@@ -61,6 +61,11 @@ func (r *cloudhubAuthorizer) AdmitMessage(message beehivemodel.Message, hubInfo
 }
 
 func (r *cloudhubAuthorizer) AuthenticateConnection(connection conn.Connection) error {
+	if err := r.checkRevocation(connection); err != nil {
+		klog.Error(err.Error())
+		return err
+	}
+
 	if !r.enabled {
 		return nil
 	}
@@ -100,6 +105,20 @@ func (r *cloudhubAuthorizer) admitMessage(message beehivemodel.Message, hubInfo
 	return nil
 }
 
+// checkRevocation rejects the connection if the client certificate is revoked
+func (r *cloudhubAuthorizer) checkRevocation(connection conn.Connection) error {
+	if r.revocation == nil {
+		return nil
+	}
+	nodeID := connection.ConnectionState().Headers.Get("node_id")
+	for _, cert := range connection.ConnectionState().PeerCertificates {
+		if err := r.revocation.CheckCertificate(nodeID, cert); err != nil {
+			return fmt.Errorf("node %q: %v", nodeID, err)
+		}
+	}
+	return nil
+}
+
 // authenticateConnection authenticates the new connection by certificates
 func (r *cloudhubAuthorizer) authenticateConnection(connection conn.Connection) error {
 	peerCerts := connection.ConnectionState().PeerCertificates
@@ -115,9 +134,12 @@ func (r *cloudhubAuthorizer) authenticateConnection(connection conn.Connection)
 	}
 
 	options := x509.DefaultVerifyOptions()
-	// ca cloud be available util CloudHub starts
-	options.Roots = stdx509.NewCertPool()
-	options.Roots.AppendCertsFromPEM(pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: hubconfig.Config.Ca}))
+	// ca cloud be available util CloudHub starts, and it is replaced by CA rotation
+	roots, err := hubconfig.Config.CACertPool()
+	if err != nil {
+		return fmt.Errorf("node %q: unable to parse CA certificates: %v", nodeID, err)
+	}
+	options.Roots = roots
 
 	authenticator := x509.New(options, x509.CommonNameUserConversion)
 	resp, ok, err := authenticator.AuthenticateRequest(&http.Request{TLS: &tls.ConnectionState{PeerCertificates: peerCerts}})
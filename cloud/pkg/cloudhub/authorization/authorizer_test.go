@@ -33,6 +33,9 @@ import (
 	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
 	cloudhubmodel "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
 	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/revocation"
+	commonconstants "github.com/kubeedge/kubeedge/common/constants"
+	pkgrevocation "github.com/kubeedge/kubeedge/pkg/security/revocation"
 	"github.com/kubeedge/viaduct/pkg/conn"
 )
 
@@ -66,6 +69,14 @@ func TestAdmitMessage(t *testing.T) {
 			message: beehivemodel.Message{Router: beehivemodel.MessageRoute{Operation: beehivemodel.QueryOperation, Resource: "ns/configmap/test"}},
 			allow:   true,
 		},
+		{
+			name:  "digest accepted by kubeedge resource authorizer",
+			authz: cloudhubAuthorizer{enabled: true, authz: kubeedgeResourceAuthorizer{}},
+			message: beehivemodel.Message{Router: beehivemodel.MessageRoute{Source: "metaManager", Group: "resource",
+				Operation: beehivemodel.UpdateOperation, Resource: commonconstants.ResourceTypeDigest}},
+			hubInfo: cloudhubmodel.HubInfo{NodeID: "test"},
+			allow:   true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -87,6 +98,12 @@ func TestAuthenticateConnection(t *testing.T) {
 
 	headers := http.Header{}
 	headers.Add("node_id", testNodeName)
+	revokedAt, err := pkgrevocation.NewEntry(time.Now().Add(time.Minute), "test").Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	revocationList := revocation.NewList(nil, nil)
+	revocationList.Update(map[string]string{testNodeName: revokedAt})
 	tests := []struct {
 		name      string
 		authz     cloudhubAuthorizer
@@ -121,6 +138,15 @@ func TestAuthenticateConnection(t *testing.T) {
 			},
 			allow: true,
 		},
+		{
+			name:  "revoked certificate rejected even if authz is disabled",
+			authz: cloudhubAuthorizer{enabled: false, revocation: revocationList},
+			connState: conn.ConnectionState{
+				Headers:          headers,
+				PeerCertificates: []*x509.Certificate{cert},
+			},
+			allow: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -32,6 +32,7 @@ import (
 
 	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
 	cloudhubmodel "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/revocation"
 	"github.com/kubeedge/viaduct/pkg/conn"
 )
 
@@ -50,6 +51,8 @@ type Config struct {
 	Debug                    bool
 	AuthorizationModes       []string
 	VersionedInformerFactory informers.SharedInformerFactory
+	// Revocation rejects connections with revoked certificates
+	Revocation *revocation.List
 }
 
 // New creates new Authorizer
@@ -71,9 +74,10 @@ func (c Config) New() (Authorizer, error) {
 		return nil, err
 	}
 	return &cloudhubAuthorizer{
-		enabled: c.Enabled,
-		debug:   c.Debug,
-		authz:   authz,
+		enabled:    c.Enabled,
+		debug:      c.Debug,
+		authz:      authz,
+		revocation: c.Revocation,
 	}, nil
 }
 
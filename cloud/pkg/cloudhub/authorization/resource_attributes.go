@@ -87,6 +87,10 @@ func isKubeedgeResourceMessage(router beehivemodel.MessageRoute) bool {
 	if router.Resource == beehivemodel.ResourceTypeK8sCA || common.IsVolumeResource(router.Resource) {
 		return true
 	}
+	// the digest of the objects cached at edge is compared with the objects synced to the node
+	if router.Resource == commonconstants.ResourceTypeDigest {
+		return true
+	}
 
 	_, resourceType, resourceName := splitResource(router.Resource)
 	switch resourceType {
@@ -23,6 +23,7 @@ import (
 
 	"github.com/kubeedge/beehive/pkg/core/model"
 	cloudhubmodel "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
+	commonconstants "github.com/kubeedge/kubeedge/common/constants"
 )
 
 func TestGetBuiltinResourceAttributes(t *testing.T) {
@@ -124,6 +125,11 @@ func TestIsKubeedgeResourceMessage(t *testing.T) {
 			router: model.MessageRoute{Resource: model.ResourceTypeK8sCA},
 			result: true,
 		},
+		{
+			name:   "digest message",
+			router: model.MessageRoute{Operation: model.UpdateOperation, Resource: commonconstants.ResourceTypeDigest},
+			result: true,
+		},
 		{
 			name:   "rule status message",
 			router: model.MessageRoute{Resource: "ns/rulestatus/rs"},
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/kubeapiserver/authorizer/modes"
@@ -16,6 +17,7 @@ import (
 	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/dispatcher"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/handler"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/revocation"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/udsserver"
@@ -34,6 +36,8 @@ type cloudHub struct {
 
 	messageHandler handler.Handler
 	dispatcher     dispatcher.MessageDispatcher
+	revocationList *revocation.List
+	caRotator      *httpserver.CARotator
 }
 
 var _ core.Module = (*cloudHub)(nil)
@@ -50,7 +54,20 @@ func newCloudHub(enable bool) *cloudHub {
 		sessionManager, objectSyncInformer.Lister(),
 		clusterObjectSyncInformer.Lister(), client.GetCRDClient())
 
+	// the certificate revocation list is kept in a ConfigMap, revoked nodes are disconnected immediately
+	configMapInformer := informers.GetInformersManager().GetKubeInformerFactory().Core().V1().ConfigMaps()
+	// the CA rotation waits for all registered edge nodes, including the offline ones
+	edgeNodeInformer := informers.GetInformersManager().EdgeNode()
+	revocationList := revocation.NewList(client.GetKubeClient(), func(nodeName string) {
+		if nodeSession, ok := sessionManager.GetSession(nodeName); ok {
+			klog.Infof("disconnect node %s whose certificates are revoked", nodeName)
+			nodeSession.Terminating()
+		}
+	})
+	revocationList.AddEventHandler(configMapInformer.Informer())
+
 	config := getAuthConfig()
+	config.Revocation = revocationList
 	authorizer, err := config.New()
 	if err != nil {
 		panic(fmt.Sprintf("unable to create new authorizer for CloudHub: %v", err))
@@ -62,14 +79,21 @@ func newCloudHub(enable bool) *cloudHub {
 		messageDispatcher, authorizer)
 	sessionMgr = sessionManager
 
+	caRotator := httpserver.NewCARotator(client.GetKubeClient(), configMapInformer.Lister(),
+		corelisters.NewNodeLister(edgeNodeInformer.GetIndexer()), sessionManager)
+
 	ch := &cloudHub{
 		enable:         enable,
 		dispatcher:     messageDispatcher,
 		messageHandler: messageHandler,
+		revocationList: revocationList,
+		caRotator:      caRotator,
 	}
 
 	ch.informersSyncedFuncs = append(ch.informersSyncedFuncs, clusterObjectSyncInformer.Informer().HasSynced)
 	ch.informersSyncedFuncs = append(ch.informersSyncedFuncs, objectSyncInformer.Informer().HasSynced)
+	ch.informersSyncedFuncs = append(ch.informersSyncedFuncs, configMapInformer.Informer().HasSynced)
+	ch.informersSyncedFuncs = append(ch.informersSyncedFuncs, edgeNodeInformer.HasSynced)
 
 	return ch
 }
@@ -116,9 +140,12 @@ func (ch *cloudHub) Start() {
 		klog.Exit(err)
 	}
 
+	// follow the rotation of the CA
+	go ch.caRotator.Run(ctx)
+
 	// HttpServer mainly used to issue certificates for the edge
 	go func() {
-		if err := httpserver.StartHTTPServer(); err != nil {
+		if err := httpserver.StartHTTPServer(ch.revocationList); err != nil {
 			klog.Exit(err)
 		}
 	}()
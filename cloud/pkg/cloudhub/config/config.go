@@ -1,8 +1,13 @@
 package config
 
 import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"sync"
 
+	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
@@ -12,11 +17,29 @@ import (
 var Config Configure
 var once sync.Once
 
+// caLock guards the CA and certificates replaced at runtime by CA rotation
+var caLock sync.RWMutex
+
+// parsed caches the parsed CAs and serving certificate for the TLS handshakes,
+// they are parsed again only when the CA or the certificate is replaced
+var parsed struct {
+	sync.Mutex
+	caBundle []byte
+	caPool   *x509.CertPool
+	cert     []byte
+	key      []byte
+	tlsCert  *tls.Certificate
+}
+
 type Configure struct {
 	v1alpha1.CloudHub
 	KubeAPIConfig *v1alpha1.KubeAPIConfig
 	Ca            []byte
 	CaKey         []byte
+	// RetiringCa and RetiringCaKey are the previous CA during CA rotation,
+	// certificates and tokens signed by it are trusted until it is retired
+	RetiringCa    []byte
+	RetiringCaKey []byte
 	Cert          []byte
 	Key           []byte
 }
@@ -80,6 +103,8 @@ func InitConfigure(hub *v1alpha1.CloudHub) {
 }
 
 func (c *Configure) UpdateCA(ca, caKey []byte) {
+	caLock.Lock()
+	defer caLock.Unlock()
 	if ca != nil {
 		c.Ca = ca
 	}
@@ -89,6 +114,8 @@ func (c *Configure) UpdateCA(ca, caKey []byte) {
 }
 
 func (c *Configure) UpdateCerts(cert, key []byte) {
+	caLock.Lock()
+	defer caLock.Unlock()
 	if cert != nil {
 		c.Cert = cert
 	}
@@ -96,3 +123,92 @@ func (c *Configure) UpdateCerts(cert, key []byte) {
 		c.Key = key
 	}
 }
+
+// RotateCA replaces the CA, the retiring CA is still trusted and is nil if there is no CA rotation in progress
+func (c *Configure) RotateCA(ca, caKey, retiringCa, retiringCaKey []byte) {
+	caLock.Lock()
+	defer caLock.Unlock()
+	c.Ca, c.CaKey = ca, caKey
+	c.RetiringCa, c.RetiringCaKey = retiringCa, retiringCaKey
+}
+
+// RotatingCA returns true if a CA rotation is in progress
+func (c *Configure) RotatingCA() bool {
+	caLock.RLock()
+	defer caLock.RUnlock()
+	return c.RetiringCa != nil
+}
+
+// SigningCA returns the CA used to sign certificates and tokens
+func (c *Configure) SigningCA() (ca, caKey []byte) {
+	caLock.RLock()
+	defer caLock.RUnlock()
+	return c.Ca, c.CaKey
+}
+
+// CABundle returns the DER of the trusted CAs, the signing CA is the first one
+func (c *Configure) CABundle() []byte {
+	caLock.RLock()
+	defer caLock.RUnlock()
+	if c.RetiringCa == nil {
+		return c.Ca
+	}
+	return bytes.Join([][]byte{c.Ca, c.RetiringCa}, nil)
+}
+
+// CACertPool returns the pool of the trusted CAs, the pool is shared and must not be modified
+func (c *Configure) CACertPool() (*x509.CertPool, error) {
+	bundle := c.CABundle()
+	parsed.Lock()
+	defer parsed.Unlock()
+	if parsed.caPool != nil && bytes.Equal(parsed.caBundle, bundle) {
+		return parsed.caPool, nil
+	}
+	cas, err := x509.ParseCertificates(bundle)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	for _, ca := range cas {
+		pool.AddCert(ca)
+	}
+	parsed.caBundle, parsed.caPool = bundle, pool
+	return pool, nil
+}
+
+// TokenKeys returns the keys to verify tokens, tokens signed by the retiring CA are still valid
+func (c *Configure) TokenKeys() [][]byte {
+	caLock.RLock()
+	defer caLock.RUnlock()
+	if c.RetiringCaKey == nil {
+		return [][]byte{c.CaKey}
+	}
+	return [][]byte{c.CaKey, c.RetiringCaKey}
+}
+
+// ServingCert returns the certificate and key of cloudcore
+func (c *Configure) ServingCert() (cert, key []byte) {
+	caLock.RLock()
+	defer caLock.RUnlock()
+	return c.Cert, c.Key
+}
+
+// TLSCertificate returns the serving certificate of cloudcore for the TLS handshakes,
+// the certificate is shared and must not be modified
+func (c *Configure) TLSCertificate() (*tls.Certificate, error) {
+	cert, key := c.ServingCert()
+	parsed.Lock()
+	defer parsed.Unlock()
+	if parsed.tlsCert != nil && bytes.Equal(parsed.cert, cert) && bytes.Equal(parsed.key, key) {
+		return parsed.tlsCert, nil
+	}
+	tlsCert, err := tls.X509KeyPair(
+		pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: cert}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	parsed.cert, parsed.key, parsed.tlsCert = cert, key, &tlsCert
+	return &tlsCert, nil
+}
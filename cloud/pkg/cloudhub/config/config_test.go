@@ -1,8 +1,11 @@
 package config
 
 import (
+	"encoding/pem"
 	"reflect"
 	"testing"
+
+	certutil "k8s.io/client-go/util/cert"
 )
 
 func TestUpdateConfig(t *testing.T) {
@@ -23,3 +26,67 @@ func TestUpdateConfig(t *testing.T) {
 		t.Errorf("UpdateCerts(): got %v, want %v", Config.Key, []byte("key"))
 	}
 }
+
+func TestRotateCA(t *testing.T) {
+	Config.RotateCA([]byte("new-ca"), []byte("new-key"), []byte("old-ca"), []byte("old-key"))
+	if !Config.RotatingCA() {
+		t.Error("RotatingCA(): expect a CA rotation in progress")
+	}
+	if got, want := Config.CABundle(), []byte("new-caold-ca"); !reflect.DeepEqual(got, want) {
+		t.Errorf("CABundle(): got %s, want %s", got, want)
+	}
+	if got, want := Config.TokenKeys(), [][]byte{[]byte("new-key"), []byte("old-key")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TokenKeys(): got %s, want %s", got, want)
+	}
+
+	Config.RotateCA([]byte("new-ca"), []byte("new-key"), nil, nil)
+	if Config.RotatingCA() {
+		t.Error("RotatingCA(): expect the CA rotation completed")
+	}
+	if got, want := Config.CABundle(), []byte("new-ca"); !reflect.DeepEqual(got, want) {
+		t.Errorf("CABundle(): got %s, want %s", got, want)
+	}
+	if got, want := Config.TokenKeys(), [][]byte{[]byte("new-key")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TokenKeys(): got %s, want %s", got, want)
+	}
+}
+
+func TestParsedCertsCache(t *testing.T) {
+	newCertKey := func(host string) ([]byte, []byte) {
+		certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey(host, nil, nil)
+		if err != nil {
+			t.Fatalf("failed to generate certificate: %v", err)
+		}
+		certBlock, _ := pem.Decode(certPEM)
+		keyBlock, _ := pem.Decode(keyPEM)
+		return certBlock.Bytes, keyBlock.Bytes
+	}
+	ca, caKey := newCertKey("ca")
+	Config.RotateCA(ca, caKey, nil, nil)
+	Config.UpdateCerts(ca, caKey)
+
+	pool, err := Config.CACertPool()
+	if err != nil {
+		t.Fatalf("CACertPool() error = %v", err)
+	}
+	if again, _ := Config.CACertPool(); again != pool {
+		t.Error("CACertPool() parsed the unchanged CA again")
+	}
+	cert, err := Config.TLSCertificate()
+	if err != nil {
+		t.Fatalf("TLSCertificate() error = %v", err)
+	}
+	if again, _ := Config.TLSCertificate(); again != cert {
+		t.Error("TLSCertificate() parsed the unchanged certificate again")
+	}
+
+	newCa, newCaKey := newCertKey("new-ca")
+	Config.RotateCA(newCa, newCaKey, ca, caKey)
+	Config.UpdateCerts(newCa, newCaKey)
+	if again, _ := Config.CACertPool(); again == pool {
+		t.Error("CACertPool() returned the pool of the replaced CA")
+	}
+	if again, _ := Config.TLSCertificate(); again == cert || !reflect.DeepEqual(again.Certificate[0], newCa) {
+		t.Error("TLSCertificate() returned the replaced certificate")
+	}
+}
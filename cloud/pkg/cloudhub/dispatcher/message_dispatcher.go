@@ -194,6 +194,10 @@ func (md *messageDispatcher) DispatchUpstream(message *beehivemodel.Message, inf
 			BuildRouter(modules.CloudHubModuleName, "resource", fmt.Sprintf("node/%s/%s", info.NodeID, message.GetResource()), beehivemodel.ResponseOperation)
 		beehivecontext.Send(modules.CloudHubModuleName, *respMsg)
 
+	case message.GetResource() == commonconst.ResourceTypeDigest:
+		message.Router.Resource = fmt.Sprintf("node/%s/%s", info.NodeID, message.Router.Resource)
+		beehivecontext.SendToGroup(modules.SyncControllerModuleGroup, *message)
+
 	default:
 		err := md.PubToController(info, message)
 		if err != nil {
@@ -454,6 +458,8 @@ func noAckRequired(msg *beehivemodel.Message) bool {
 		return true
 	case strings.Contains(msgResource, beehivemodel.ResourceTypeK8sCA):
 		return true
+	case msg.GetSource() == modules.SyncControllerModuleName && strings.HasSuffix(msgResource, commonconst.ResourceTypeDigest):
+		return true
 	case isVolumeOperation(msg.GetOperation()):
 		return true
 	case msg.Router.Operation == metaserver.ApplicationResp:
@@ -493,10 +499,21 @@ func noAckRequired(msg *beehivemodel.Message) bool {
 }
 
 func isVolumeOperation(op string) bool {
-	return op == commonconst.CSIOperationTypeCreateVolume ||
-		op == commonconst.CSIOperationTypeDeleteVolume ||
-		op == commonconst.CSIOperationTypeControllerPublishVolume ||
-		op == commonconst.CSIOperationTypeControllerUnpublishVolume
+	switch op {
+	case commonconst.CSIOperationTypeCreateVolume,
+		commonconst.CSIOperationTypeDeleteVolume,
+		commonconst.CSIOperationTypeControllerPublishVolume,
+		commonconst.CSIOperationTypeControllerUnpublishVolume,
+		commonconst.CSIOperationTypeControllerExpandVolume,
+		commonconst.CSIOperationTypeControllerGetVolume,
+		commonconst.CSIOperationTypeListVolumes,
+		commonconst.CSIOperationTypeGetCapacity,
+		commonconst.CSIOperationTypeCreateSnapshot,
+		commonconst.CSIOperationTypeDeleteSnapshot,
+		commonconst.CSIOperationTypeListSnapshots:
+		return true
+	}
+	return false
 }
 
 // GetNodeMessagePool returns the message pool for given node
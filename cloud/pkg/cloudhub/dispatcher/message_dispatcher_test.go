@@ -92,6 +92,11 @@ func TestNoAckRequired(t *testing.T) {
 			message: beehivemodel.NewMessage("").SetResourceOperation("node/edge-node/default/node/edge-node", "response").FillBody(fmt.Errorf("error")),
 			want:    true,
 		},
+		{
+			name:    "digest result message",
+			message: beehivemodel.NewMessage("").SetResourceOperation("node/edge-node/digest", "update").SetRoute("synccontroller", "resource"),
+			want:    true,
+		},
 		{
 			name:    "normal pod update",
 			message: beehivemodel.NewMessage("").SetResourceOperation("node/edge-node/default/pod/test-pod", "update").SetRoute("edgecontroller", "resource"),
@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revocation
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/revocation"
+)
+
+// List is the revocation list of edge node certificates kept in sync with the revocation ConfigMap.
+// Pending revocations are stamped with the clock of cloudcore, which issues the certificates as well.
+type List struct {
+	mu      sync.RWMutex
+	entries map[string]revocation.Entry
+	// onRevoke is called with the node name when the certificates of a node are revoked
+	onRevoke func(nodeName string)
+	// client stamps the pending revocations in the ConfigMap, they are not stamped if it is nil
+	client kubernetes.Interface
+}
+
+// NewList creates an empty revocation list, onRevoke is used to disconnect revoked nodes
+func NewList(client kubernetes.Interface, onRevoke func(nodeName string)) *List {
+	return &List{
+		entries:  make(map[string]revocation.Entry),
+		onRevoke: onRevoke,
+		client:   client,
+	}
+}
+
+// AddEventHandler keeps the list in sync with the revocation ConfigMap watched by the informer
+func (l *List) AddEventHandler(informer cache.SharedIndexInformer) {
+	_, err := informer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			cm, ok := obj.(*corev1.ConfigMap)
+			return ok && cm.Namespace == constants.SystemNamespace && cm.Name == revocation.ConfigMapName
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				l.Update(obj.(*corev1.ConfigMap).Data)
+			},
+			UpdateFunc: func(_, newObj interface{}) {
+				l.Update(newObj.(*corev1.ConfigMap).Data)
+			},
+			DeleteFunc: func(interface{}) {
+				l.Update(nil)
+			},
+		},
+	})
+	if err != nil {
+		klog.Errorf("failed to add event handler of certificate revocation list: %v", err)
+	}
+}
+
+// Update replaces the revocation list with the data of the ConfigMap,
+// nodes revoked or revoked again are disconnected by onRevoke
+func (l *List) Update(data map[string]string) {
+	entries, errs := revocation.Parse(data)
+	for _, err := range errs {
+		klog.Error(err)
+	}
+
+	var revoked, pending []string
+	l.mu.Lock()
+	for node, entry := range entries {
+		if entry.Pending() {
+			pending = append(pending, node)
+		}
+		// stamping a pending revocation does not revoke the node again
+		if old, ok := l.entries[node]; !ok || (!old.Pending() && !old.RevokedAt.Equal(entry.RevokedAt)) {
+			revoked = append(revoked, node)
+		}
+	}
+	l.entries = entries
+	l.mu.Unlock()
+
+	if len(pending) > 0 && l.client != nil {
+		go func() {
+			if err := l.stamp(context.Background(), pending, time.Now()); err != nil {
+				klog.Errorf("failed to stamp the revocation of nodes %v: %v", pending, err)
+			}
+		}()
+	}
+
+	for _, node := range revoked {
+		klog.Infof("certificates of node %s are revoked", node)
+		if l.onRevoke != nil {
+			l.onRevoke(node)
+		}
+	}
+}
+
+// stamp sets the revocation time of the pending revocations of nodes to now
+func (l *List) stamp(ctx context.Context, nodes []string, now time.Time) error {
+	configMaps := l.client.CoreV1().ConfigMaps(constants.SystemNamespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(ctx, revocation.ConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		entries, _ := revocation.Parse(cm.Data)
+		stamped := false
+		for _, node := range nodes {
+			// the revocation may be removed or stamped by another cloudcore meanwhile
+			entry, ok := entries[node]
+			if !ok || !entry.Pending() {
+				continue
+			}
+			value, err := revocation.NewEntry(now, entry.Reason).Encode()
+			if err != nil {
+				return err
+			}
+			cm.Data[node] = value
+			stamped = true
+		}
+		if !stamped {
+			return nil
+		}
+		_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// CheckCertificate returns an error if the certificate of the node is revoked.
+// The node named in the common name of the certificate is checked as well,
+// so a revoked certificate can not be used in the name of another node.
+func (l *List) CheckCertificate(nodeName string, cert *x509.Certificate) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, node := range []string{nodeName, revocation.NodeNameOf(cert)} {
+		if node == "" {
+			continue
+		}
+		if entry, ok := l.entries[node]; ok && entry.Revokes(cert) {
+			if entry.Pending() {
+				return fmt.Errorf("certificate of node %q is revoked: %s", node, entry.Reason)
+			}
+			return fmt.Errorf("certificate of node %q is revoked at %s: %s", node, entry.RevokedAt, entry.Reason)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revocation
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/revocation"
+)
+
+func TestList(t *testing.T) {
+	var disconnected []string
+	list := NewList(nil, func(nodeName string) {
+		disconnected = append(disconnected, nodeName)
+	})
+
+	now := time.Now()
+	value, err := revocation.NewEntry(now, "node is lost").Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	list.Update(map[string]string{"edge-1": value})
+	if len(disconnected) != 1 || disconnected[0] != "edge-1" {
+		t.Fatalf("expected edge-1 disconnected, got %v", disconnected)
+	}
+	list.Update(map[string]string{"edge-1": value})
+	if len(disconnected) != 1 {
+		t.Errorf("expected a node not disconnected again by an unchanged revocation, got %v", disconnected)
+	}
+
+	oldCert := &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "system:node:edge-1"},
+		NotBefore: now.Add(-time.Hour),
+	}
+	newCert := &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "system:node:edge-1"},
+		NotBefore: now.Add(time.Minute),
+	}
+	if err := list.CheckCertificate("edge-1", oldCert); err == nil {
+		t.Error("expected the old certificate of edge-1 revoked")
+	}
+	if err := list.CheckCertificate("edge-2", oldCert); err == nil {
+		t.Error("expected the old certificate of edge-1 revoked when used by edge-2")
+	}
+	if err := list.CheckCertificate("edge-1", newCert); err != nil {
+		t.Errorf("expected the re-enrolled certificate of edge-1 accepted, got %v", err)
+	}
+
+	list.Update(nil)
+	if err := list.CheckCertificate("edge-1", oldCert); err != nil {
+		t.Errorf("expected the certificate accepted after the revocation is removed, got %v", err)
+	}
+}
+
+func TestStampPendingRevocation(t *testing.T) {
+	pending, err := revocation.NewPendingEntry("node is lost").Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: revocation.ConfigMapName, Namespace: constants.SystemNamespace},
+		Data:       map[string]string{"edge-1": pending},
+	})
+	var disconnected []string
+	list := NewList(client, func(nodeName string) {
+		disconnected = append(disconnected, nodeName)
+	})
+	before := time.Now().UTC().Truncate(time.Second)
+	list.Update(map[string]string{"edge-1": pending})
+
+	// all certificates are revoked until the revocation is stamped
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "system:node:edge-1"}, NotBefore: time.Now().Add(time.Hour)}
+	if err := list.CheckCertificate("edge-1", cert); err == nil {
+		t.Error("expected the certificate revoked by the pending revocation")
+	}
+
+	// the revocation is stamped by the list in the background
+	var cm *corev1.ConfigMap
+	var entry revocation.Entry
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		cm, err = client.CoreV1().ConfigMaps(constants.SystemNamespace).Get(context.Background(), revocation.ConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries, _ := revocation.Parse(cm.Data)
+		if entry = entries["edge-1"]; !entry.Pending() {
+			break
+		}
+	}
+	if entry.Pending() || entry.RevokedAt.Before(before) || entry.RevokedAt.After(time.Now()) || entry.Reason != "node is lost" {
+		t.Fatalf("expected the revocation stamped by cloudcore, got %+v", entry)
+	}
+	cert.NotBefore = entry.RevokedAt.Add(time.Second)
+
+	list.Update(cm.Data)
+	if len(disconnected) != 1 {
+		t.Errorf("expected a node not disconnected again when its revocation is stamped, got %v", disconnected)
+	}
+	if err := list.CheckCertificate("edge-1", cert); err != nil {
+		t.Errorf("expected the certificate issued after the revocation accepted, got %v", err)
+	}
+}
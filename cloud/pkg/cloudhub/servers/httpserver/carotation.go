@@ -0,0 +1,288 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package httpserver
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/session"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/carotation"
+)
+
+const (
+	// caRotationSyncPeriod is the period to sync the progress of CA rotation
+	caRotationSyncPeriod = 30 * time.Second
+	// minCARotationPeriod is the min duration of a CA rotation before the retiring CA is removed,
+	// so every cloudcore instance has reported the progress of its nodes
+	minCARotationPeriod = 3 * caRotationSyncPeriod
+)
+
+// CARotator follows the CA rotation recorded in the status ConfigMap. It loads the new CA when a rotation
+// starts, reports whether the nodes connected to this cloudcore use certificates signed by the new CA,
+// records the registered nodes not connected to any cloudcore as offline, and retires the old CA once
+// all the registered nodes have rotated their certificates.
+type CARotator struct {
+	kubeClient     kubernetes.Interface
+	lister         corelisters.ConfigMapLister
+	nodeLister     corelisters.NodeLister
+	sessionManager *session.Manager
+	// reported is the nodes whose progress is reported by this cloudcore
+	reported map[string]bool
+}
+
+// NewCARotator creates a CARotator, the lister must list the ConfigMaps of the kubeedge namespace
+// and the nodeLister must list the registered edge nodes
+func NewCARotator(kubeClient kubernetes.Interface, lister corelisters.ConfigMapLister,
+	nodeLister corelisters.NodeLister, sessionManager *session.Manager) *CARotator {
+	return &CARotator{
+		kubeClient:     kubeClient,
+		lister:         lister,
+		nodeLister:     nodeLister,
+		sessionManager: sessionManager,
+		reported:       make(map[string]bool),
+	}
+}
+
+// Run syncs the CA rotation periodically until ctx is done
+func (r *CARotator) Run(ctx context.Context) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := r.sync(ctx); err != nil {
+			klog.Errorf("failed to sync CA rotation: %v", err)
+		}
+	}, caRotationSyncPeriod)
+}
+
+func (r *CARotator) sync(ctx context.Context) error {
+	cm, err := r.lister.ConfigMaps(constants.SystemNamespace).Get(carotation.ConfigMapName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	status, err := carotation.StatusFromConfigMap(cm)
+	if err != nil {
+		return err
+	}
+
+	switch status.Phase {
+	case carotation.PhaseRotating:
+		if !hubconfig.Config.RotatingCA() {
+			// the rotation is started after cloudcore starts
+			if err := r.loadCA(ctx); err != nil {
+				return err
+			}
+		}
+		return r.reportProgress(ctx)
+	case carotation.PhaseCompleted:
+		if hubconfig.Config.RotatingCA() {
+			// the rotation is completed by another cloudcore
+			return r.loadCA(ctx)
+		}
+	}
+	return nil
+}
+
+// loadCA loads the CA and the certificate of cloudcore from secrets
+func (r *CARotator) loadCA(ctx context.Context) error {
+	caSecret, err := client.GetSecret(ctx, CaSecretName, constants.SystemNamespace)
+	if err != nil {
+		return fmt.Errorf("failed to get the CA secret: %v", err)
+	}
+	ca, caKey := caSecret.Data[CaDataName], caSecret.Data[CaKeyDataName]
+	retiringCa, retiringCaKey := caSecret.Data[carotation.RetiringCaDataName], caSecret.Data[carotation.RetiringCaKeyDataName]
+	if retiringCa == nil {
+		// the certificate of cloudcore is signed by the new CA when the rotation completes
+		cloudSecret, err := client.GetSecret(ctx, CloudCoreSecretName, constants.SystemNamespace)
+		if err != nil {
+			return fmt.Errorf("failed to get the CloudCore secret: %v", err)
+		}
+		hubconfig.Config.UpdateCerts(cloudSecret.Data[CloudCoreCertName], cloudSecret.Data[CloudCoreKeyDataName])
+	}
+	hubconfig.Config.RotateCA(ca, caKey, retiringCa, retiringCaKey)
+	klog.Infof("CA is loaded, CA rotation in progress: %v", retiringCa != nil)
+
+	// the shared token is verified against the hash of the new CA
+	return saveToken(ctx)
+}
+
+// reportProgress records the progress of the nodes connected to this cloudcore and the registered nodes
+// not connected to any cloudcore, and retires the old CA once all the nodes use certificates signed by the new CA
+func (r *CARotator) reportProgress(ctx context.Context) error {
+	ca, _ := hubconfig.Config.SigningCA()
+	signingCA, err := x509.ParseCertificate(ca)
+	if err != nil {
+		return fmt.Errorf("failed to parse the signing CA: %v", err)
+	}
+	nodes := r.nodeProgress(signingCA)
+	registered, err := r.registeredNodes()
+	if err != nil {
+		return fmt.Errorf("failed to list edge nodes: %v", err)
+	}
+
+	status, err := r.updateStatus(ctx, func(status *carotation.Status) bool {
+		if status.Phase != carotation.PhaseRotating {
+			return false
+		}
+		changed := false
+		for node := range r.reported {
+			if _, ok := nodes[node]; !ok {
+				delete(status.Nodes, node)
+				changed = true
+			}
+		}
+		for node, state := range nodes {
+			if status.Nodes[node] != state {
+				status.Nodes[node] = state
+				changed = true
+			}
+		}
+		// the nodes connected to other cloudcores are reported by them
+		for node := range registered {
+			if _, ok := status.Nodes[node]; !ok {
+				status.Nodes[node] = carotation.NodeOffline
+				changed = true
+			}
+		}
+		for node, state := range status.Nodes {
+			if _, ok := registered[node]; !ok && state == carotation.NodeOffline {
+				delete(status.Nodes, node)
+				changed = true
+			}
+		}
+		return changed
+	})
+	if err != nil {
+		return err
+	}
+	r.reported = make(map[string]bool, len(nodes))
+	for node := range nodes {
+		r.reported[node] = true
+	}
+
+	if status.Phase != carotation.PhaseRotating {
+		return nil
+	}
+	if !status.Complete() || time.Since(status.StartedAt) < minCARotationPeriod {
+		klog.V(2).Infof("CA rotation is waiting for nodes %v, offline nodes %v", status.PendingNodes(), status.OfflineNodes())
+		return nil
+	}
+	if offline := status.OfflineNodes(); len(offline) > 0 {
+		klog.Warningf("CA rotation is forced, offline nodes %v have to join again", offline)
+	}
+	if err := r.retire(ctx); err != nil {
+		return fmt.Errorf("failed to retire the old CA: %v", err)
+	}
+	_, err = r.updateStatus(ctx, func(status *carotation.Status) bool {
+		status.Phase = carotation.PhaseCompleted
+		status.CompletedAt = time.Now()
+		return true
+	})
+	return err
+}
+
+// nodeProgress returns whether the nodes connected to this cloudcore use certificates signed by the CA
+func (r *CARotator) nodeProgress(ca *x509.Certificate) map[string]string {
+	nodes := make(map[string]string)
+	r.sessionManager.NodeSessions.Range(func(key, value interface{}) bool {
+		nodeSession, ok := value.(*session.NodeSession)
+		if !ok {
+			return true
+		}
+		state := carotation.NodePending
+		if cert := nodeSession.PeerCertificate(); cert != nil && cert.CheckSignatureFrom(ca) == nil {
+			state = carotation.NodeRotated
+		}
+		nodes[key.(string)] = state
+		return true
+	})
+	return nodes
+}
+
+// registeredNodes returns the names of the registered edge nodes
+func (r *CARotator) registeredNodes() (map[string]bool, error) {
+	nodes, err := r.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	registered := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		registered[node.Name] = true
+	}
+	return registered, nil
+}
+
+// retire removes the old CA and signs the certificate of cloudcore by the new CA
+func (r *CARotator) retire(ctx context.Context) error {
+	ca, caKey := hubconfig.Config.SigningCA()
+	hubconfig.Config.RotateCA(ca, caKey, nil, nil)
+	if err := client.SaveSecret(ctx, createCaSecret(ca, caKey, nil, nil), constants.SystemNamespace); err != nil {
+		return err
+	}
+
+	certDER, keyDER, err := signCloudCoreCert()
+	if err != nil {
+		return err
+	}
+	if err := client.SaveSecret(ctx, createCloudCoreSecret(certDER, keyDER), constants.SystemNamespace); err != nil {
+		return err
+	}
+	hubconfig.Config.UpdateCerts(certDER, keyDER)
+	klog.Info("the old CA is retired, the certificate of CloudCore is signed by the new CA")
+
+	return saveToken(ctx)
+}
+
+// updateStatus updates the status by mutate, it is saved if mutate returns true
+func (r *CARotator) updateStatus(ctx context.Context, mutate func(status *carotation.Status) bool) (*carotation.Status, error) {
+	configMaps := r.kubeClient.CoreV1().ConfigMaps(constants.SystemNamespace)
+	var status *carotation.Status
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(ctx, carotation.ConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if status, err = carotation.StatusFromConfigMap(cm); err != nil {
+			return err
+		}
+		if !mutate(status) {
+			return nil
+		}
+		newCM, err := status.ToConfigMap(constants.SystemNamespace)
+		if err != nil {
+			return err
+		}
+		newCM.ResourceVersion = cm.ResourceVersion
+		_, err = configMaps.Update(ctx, newCM, metav1.UpdateOptions{})
+		return err
+	})
+	return status, err
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	crdClientset "github.com/kubeedge/api/client/clientset/versioned"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/token"
+)
+
+// verifyBootstrapToken checks the node is allowed to join with the bootstrap token,
+// the token is not consumed until useBootstrapToken is called
+func verifyBootstrapToken(ctx context.Context, kubeClient kubernetes.Interface, crdClient crdClientset.Interface,
+	id, nodeName string) error {
+	_, _, err := getAllowedBootstrapToken(ctx, kubeClient, crdClient, id, nodeName)
+	return err
+}
+
+// useBootstrapToken checks the node is still allowed to join with the bootstrap token and
+// increases the usage count of the token, so a single-use token can not enroll another node.
+// It is called after the certificate of the node is signed, so a failed enrollment does not use up the token.
+func useBootstrapToken(ctx context.Context, kubeClient kubernetes.Interface, crdClient crdClientset.Interface,
+	id, nodeName string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, bt, err := getAllowedBootstrapToken(ctx, kubeClient, crdClient, id, nodeName)
+		if err != nil {
+			return err
+		}
+		secret.Data[token.BootstrapTokenUsageCountKey] = []byte(strconv.Itoa(bt.UsageCount + 1))
+		if _, err := kubeClient.CoreV1().Secrets(constants.SystemNamespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		klog.Infof("edge node %s uses bootstrap token %s (%d/%d)", nodeName, id, bt.UsageCount+1, bt.UsageLimit)
+		return nil
+	})
+}
+
+// getAllowedBootstrapToken returns the bootstrap token and its secret if the node is allowed to join with it
+func getAllowedBootstrapToken(ctx context.Context, kubeClient kubernetes.Interface, crdClient crdClientset.Interface,
+	id, nodeName string) (*corev1.Secret, *token.BootstrapToken, error) {
+	secret, err := kubeClient.CoreV1().Secrets(constants.SystemNamespace).Get(ctx, token.BootstrapTokenSecretName(id), metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get bootstrap token %s, err: %v", id, err)
+	}
+	bt, err := token.BootstrapTokenFromSecret(secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := bt.Allows(nodeName, time.Now()); err != nil {
+		return nil, nil, err
+	}
+	if bt.NodeGroup != "" {
+		if err := verifyNodeGroupMember(ctx, crdClient, bt.NodeGroup, nodeName); err != nil {
+			return nil, nil, err
+		}
+	}
+	return secret, bt, nil
+}
+
+// verifyNodeGroupMember verifies the node is listed in the nodes of the node group.
+// The labels of a joining node are unknown, so nodes selected by labels are not allowed.
+func verifyNodeGroupMember(ctx context.Context, crdClient crdClientset.Interface, nodeGroup, nodeName string) error {
+	ng, err := crdClient.AppsV1alpha1().NodeGroups().Get(ctx, nodeGroup, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get node group %s, err: %v", nodeGroup, err)
+	}
+	for _, node := range ng.Spec.Nodes {
+		if node == nodeName {
+			return nil
+		}
+	}
+	return fmt.Errorf("node %s is not a member of node group %s", nodeName, nodeGroup)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+
+	appsv1alpha1 "github.com/kubeedge/api/apis/apps/v1alpha1"
+	fakecrd "github.com/kubeedge/api/client/clientset/versioned/fake"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/token"
+)
+
+func TestUseBootstrapToken(t *testing.T) {
+	ctx := context.Background()
+	nodeToken := &token.BootstrapToken{
+		ID:              "node",
+		Expiration:      time.Now().Add(time.Hour),
+		UsageLimit:      1,
+		NodeNamePattern: "edge-*",
+	}
+	groupToken := &token.BootstrapToken{
+		ID:         "group",
+		Expiration: time.Now().Add(time.Hour),
+		UsageLimit: 2,
+		NodeGroup:  "beijing",
+	}
+	expiredToken := &token.BootstrapToken{
+		ID:         "expired",
+		Expiration: time.Now().Add(-time.Minute),
+		UsageLimit: 1,
+	}
+	kubeClient := fakekube.NewSimpleClientset(
+		nodeToken.ToSecret(constants.SystemNamespace),
+		groupToken.ToSecret(constants.SystemNamespace),
+		expiredToken.ToSecret(constants.SystemNamespace),
+	)
+	crdClient := fakecrd.NewSimpleClientset(&appsv1alpha1.NodeGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "beijing"},
+		Spec:       appsv1alpha1.NodeGroupSpec{Nodes: []string{"bj-1", "bj-2"}},
+	})
+
+	cases := []struct {
+		name          string
+		id            string
+		nodeName      string
+		containsError string
+	}{
+		{name: "node name not match", id: "node", nodeName: "cloud-1", containsError: "does not match"},
+		{name: "node token", id: "node", nodeName: "edge-1"},
+		{name: "node token used up", id: "node", nodeName: "edge-2", containsError: "has been used 1 times"},
+		{name: "not a member of node group", id: "group", nodeName: "sh-1", containsError: "not a member"},
+		{name: "node group token", id: "group", nodeName: "bj-1"},
+		{name: "expired token", id: "expired", nodeName: "edge-3", containsError: "expired"},
+		{name: "unknown token", id: "unknown", nodeName: "edge-4", containsError: "failed to get bootstrap token"},
+	}
+	// verifying a token does not use it up
+	require.NoError(t, verifyBootstrapToken(ctx, kubeClient, crdClient, "node", "edge-1"))
+	require.NoError(t, verifyBootstrapToken(ctx, kubeClient, crdClient, "node", "edge-2"))
+	require.ErrorContains(t, verifyBootstrapToken(ctx, kubeClient, crdClient, "node", "cloud-1"), "does not match")
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := useBootstrapToken(ctx, kubeClient, crdClient, c.id, c.nodeName)
+			if c.containsError != "" {
+				require.ErrorContains(t, err, c.containsError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(constants.SystemNamespace).Get(ctx,
+		token.BootstrapTokenSecretName("group"), metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "1", string(secret.Data[token.BootstrapTokenUsageCountKey]))
+}
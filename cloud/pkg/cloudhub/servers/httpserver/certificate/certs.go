@@ -16,6 +16,7 @@ limitations under the License.
 package certificate
 
 import (
+	"context"
 	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
@@ -27,20 +28,20 @@ import (
 	"time"
 
 	"github.com/emicklei/go-restful"
-	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 
 	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/common/constants"
 	"github.com/kubeedge/kubeedge/common/types"
 	"github.com/kubeedge/kubeedge/pkg/security/certs"
 	"github.com/kubeedge/kubeedge/pkg/security/token"
 )
 
-// GetCA returns the caCertDER
+// GetCA returns the caCertDER, during CA rotation it is the trust bundle of the new CA followed by the old one
 func GetCA(_ *restful.Request, response *restful.Response) {
-	resps.OK(response, hubconfig.Config.Ca)
+	resps.OK(response, hubconfig.Config.CABundle())
 }
 
 // EdgeCoreClientCert will verify the certificate of EdgeCore or token then create EdgeCoreCert and return it
@@ -48,6 +49,7 @@ func EdgeCoreClientCert(request *restful.Request, response *restful.Response) {
 	r := request.Request
 	nodeName := r.Header.Get(types.HeaderNodeName)
 
+	var bootstrapTokenID string
 	if cert := r.TLS.PeerCertificates; len(cert) > 0 {
 		if err := verifyCert(cert[0], nodeName); err != nil {
 			message := fmt.Sprintf("failed to verify the certificate for edgenode: %s, err: %v", nodeName, err)
@@ -57,11 +59,13 @@ func EdgeCoreClientCert(request *restful.Request, response *restful.Response) {
 		}
 	} else {
 		authorization := r.Header.Get(types.HeaderAuthorization)
-		if code, err := verifyAuthorization(authorization); err != nil {
+		id, code, err := verifyAuthorization(r.Context(), authorization, nodeName)
+		if err != nil {
 			klog.Error(err)
 			resps.Error(response, code, err)
 			return
 		}
+		bootstrapTokenID = id
 	}
 
 	usagesStr := r.Header.Get(types.HeaderExtKeyUsages)
@@ -73,18 +77,23 @@ func EdgeCoreClientCert(request *restful.Request, response *restful.Response) {
 		resps.ErrorMessage(response, http.StatusInternalServerError, message)
 		return
 	}
+	// the bootstrap token is consumed only after the certificate is signed
+	if bootstrapTokenID != "" {
+		if err := useBootstrapToken(r.Context(), client.GetKubeClient(), client.GetCRDClient(), bootstrapTokenID, nodeName); err != nil {
+			message := fmt.Sprintf("token validation failure, err: %v", err)
+			klog.Error(message)
+			resps.ErrorMessage(response, http.StatusUnauthorized, message)
+			return
+		}
+	}
 	resps.OK(response, certBlock.Bytes)
 }
 
 // verifyCert verifies the edge certificate by CA certificate when edge certificates rotate.
 func verifyCert(cert *x509.Certificate, nodeName string) error {
-	roots := x509.NewCertPool()
-	ok := roots.AppendCertsFromPEM(pem.EncodeToMemory(&pem.Block{
-		Type:  certutil.CertificateBlockType,
-		Bytes: hubconfig.Config.Ca,
-	}))
-	if !ok {
-		return fmt.Errorf("failed to parse root certificate")
+	roots, err := hubconfig.Config.CACertPool()
+	if err != nil {
+		return fmt.Errorf("failed to parse root certificate: %v", err)
 	}
 	opts := x509.VerifyOptions{
 		Roots:     roots,
@@ -110,24 +119,47 @@ func verifyCertSubject(cert *x509.Certificate, nodeName string) error {
 	return fmt.Errorf("request node name is not match with the certificate")
 }
 
-// verifyAuthorization verifies the token from EdgeCore CSR
-func verifyAuthorization(authorization string) (int, error) {
+// verifyAuthorization verifies the token from EdgeCore CSR, a bootstrap token is also checked against
+// the node. It returns the id of the bootstrap token, which is to be used once the certificate is signed.
+func verifyAuthorization(ctx context.Context, authorization, nodeName string) (string, int, error) {
 	klog.V(4).Info("authorization token is: ", authorization)
 	if authorization == "" {
-		return http.StatusUnauthorized, errors.New("token validation failure, token is empty")
+		return "", http.StatusUnauthorized, errors.New("token validation failure, token is empty")
 	}
 	bearerToken := strings.Split(authorization, " ")
 	if len(bearerToken) != 2 {
-		return http.StatusUnauthorized, errors.New("token validation failure, token cannot be splited")
+		return "", http.StatusUnauthorized, errors.New("token validation failure, token cannot be splited")
 	}
-	valid, err := token.Verify(bearerToken[1], hubconfig.Config.CaKey)
+	id, err := verifyToken(bearerToken[1])
 	if err != nil {
-		return http.StatusUnauthorized, fmt.Errorf("token validation failure, err: %v", err)
+		return "", http.StatusUnauthorized, fmt.Errorf("token validation failure, err: %v", err)
 	}
-	if !valid {
-		return http.StatusUnauthorized, errors.New("token validation failure, valid is false")
+	if id == "" {
+		if hubconfig.Config.CloudHub.RequireBootstrapToken {
+			return "", http.StatusUnauthorized, errors.New("token validation failure, a bootstrap token is required")
+		}
+		return "", http.StatusOK, nil
+	}
+	if err := verifyBootstrapToken(ctx, client.GetKubeClient(), client.GetCRDClient(), id, nodeName); err != nil {
+		return "", http.StatusUnauthorized, fmt.Errorf("token validation failure, err: %v", err)
+	}
+	return id, http.StatusOK, nil
+}
+
+// verifyToken verifies the token by the keys of the trusted CAs and returns the id of the bootstrap token.
+// The error of the signing CA is returned if none of the keys verifies the token.
+func verifyToken(bearerToken string) (string, error) {
+	var firstErr error
+	for _, key := range hubconfig.Config.TokenKeys() {
+		id, err := token.VerifyAndGetID(bearerToken, key)
+		if err == nil {
+			return id, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
 	}
-	return http.StatusOK, nil
+	return "", firstErr
 }
 
 // signEdgeCert signs the CSR from EdgeCore
@@ -147,11 +179,12 @@ func signEdgeCert(r io.ReadCloser, usagesStr string) (*pem.Block, error) {
 		return nil, fmt.Errorf("fail to read file when signing the cert, err: %v", err)
 	}
 	edgeCertSigningDuration := hubconfig.Config.CloudHub.EdgeCertSigningDuration * time.Hour * 24
+	ca, caKey := hubconfig.Config.SigningCA()
 	h := certs.GetHandler(certs.HandlerTypeX509)
 	certBlock, err := h.SignCerts(certs.SignCertsOptionsWithCSR(
 		payload,
-		hubconfig.Config.Ca,
-		hubconfig.Config.CaKey,
+		ca,
+		caKey,
 		usages,
 		edgeCertSigningDuration,
 	))
@@ -1,6 +1,7 @@
 package certificate
 
 import (
+	"context"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/pem"
@@ -82,7 +83,7 @@ func TestVerifyAuthorization(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			code, err := verifyAuthorization(c.token)
+			_, code, err := verifyAuthorization(context.Background(), c.token, "edge-node")
 			require.Equal(t, c.wantCode, code)
 			if c.containsError != "" {
 				require.Error(t, err)
@@ -92,6 +93,16 @@ func TestVerifyAuthorization(t *testing.T) {
 			}
 		})
 	}
+
+	// tokens signed by the retiring CA are accepted during CA rotation
+	hubconfig.Config.RotateCA(nil, []byte("new-ca-key"), []byte("retiring-ca"), cakeyDer)
+	defer hubconfig.Config.RotateCA(nil, cakeyDer, nil, nil)
+	_, code, err := verifyAuthorization(context.Background(), "Bearer "+passedToken, "edge-node")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	_, code, err = verifyAuthorization(context.Background(), "Bearer "+expiredToken, "edge-node")
+	require.ErrorContains(t, err, "token validation failure")
+	require.Equal(t, http.StatusUnauthorized, code)
 }
 
 const (
@@ -30,6 +30,7 @@ import (
 	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/carotation"
 	"github.com/kubeedge/kubeedge/pkg/security/certs"
 	"github.com/kubeedge/kubeedge/pkg/security/token"
 )
@@ -79,6 +80,9 @@ func createCAToSecret(ctx context.Context) error {
 		} else {
 			caDER = caSecret.Data[CaDataName]
 			keyDER = caSecret.Data[CaKeyDataName]
+			// a CA rotation is in progress, the retiring CA is still trusted
+			hubconfig.Config.RotateCA(caDER, keyDER,
+				caSecret.Data[carotation.RetiringCaDataName], caSecret.Data[carotation.RetiringCaKeyDataName])
 		}
 
 		hubconfig.Config.UpdateCA(caDER, keyDER)
@@ -88,7 +92,8 @@ func createCAToSecret(ctx context.Context) error {
 		keyDER = hubconfig.Config.CaKey
 	}
 
-	if err := client.SaveSecret(ctx, createCaSecret(caDER, keyDER), constants.SystemNamespace); err != nil {
+	if err := client.SaveSecret(ctx, createCaSecret(caDER, keyDER, hubconfig.Config.RetiringCa, hubconfig.Config.RetiringCaKey),
+		constants.SystemNamespace); err != nil {
 		return fmt.Errorf("failed to create ca to secrets, error: %v", err)
 	}
 
@@ -96,7 +101,6 @@ func createCAToSecret(ctx context.Context) error {
 }
 
 func createCertsToSecret(ctx context.Context) error {
-	const year100 = time.Hour * 24 * 364 * 100
 	var certDER, keyDER []byte
 
 	// Check whether the CloudCore certificates exist in the local directory
@@ -107,37 +111,9 @@ func createCertsToSecret(ctx context.Context) error {
 		cloudSecret, err := client.GetSecret(ctx, CloudCoreSecretName, constants.SystemNamespace)
 		if err != nil {
 			klog.Info("CloudCoreCert and key don't exist in the secret, and will be signed by CA")
-
-			ips := make([]net.IP, 0, len(hubconfig.Config.AdvertiseAddress))
-			for _, addr := range hubconfig.Config.AdvertiseAddress {
-				ips = append(ips, net.ParseIP(addr))
-			}
-			h := certs.GetHandler(certs.HandlerTypeX509)
-
-			keywrap, err := h.GenPrivateKey()
-			if err != nil {
-				return fmt.Errorf("faield to generate the private key, err: %v", err)
-			}
-			key, err := keywrap.Signer()
-			if err != nil {
-				return fmt.Errorf("failed parse the priavte key, err: %v", err)
-			}
-
-			opts := certs.SignCertsOptionsWithCA(certutil.Config{
-				CommonName:   constants.ProjectName,
-				Organization: []string{constants.ProjectName},
-				Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
-				AltNames: certutil.AltNames{
-					DNSNames: hubconfig.Config.DNSNames,
-					IPs:      ips,
-				},
-			}, hubconfig.Config.Ca, hubconfig.Config.CaKey, key.Public(), year100)
-			certPEM, err := h.SignCerts(opts)
-			if err != nil {
-				return fmt.Errorf("failed to sign the certificate, err: %v", err)
+			if certDER, keyDER, err = signCloudCoreCert(); err != nil {
+				return err
 			}
-			keyDER = keywrap.DER()
-			certDER = certPEM.Bytes
 		} else {
 			certDER = cloudSecret.Data[CloudCoreCertName]
 			keyDER = cloudSecret.Data[CloudCoreKeyDataName]
@@ -157,16 +133,46 @@ func createCertsToSecret(ctx context.Context) error {
 	return nil
 }
 
-// GenerateAndRefreshToken creates a token and save it to secret, then craete a timer to refresh the token.
-func GenerateAndRefreshToken(ctx context.Context) error {
-	caHashToken, err := token.Create(hubconfig.Config.Ca, hubconfig.Config.CaKey,
-		hubconfig.Config.CloudHub.TokenRefreshDuration)
+// signCloudCoreCert signs the certificate of CloudCore by the signing CA
+func signCloudCoreCert() ([]byte, []byte, error) {
+	const year100 = time.Hour * 24 * 364 * 100
+
+	ips := make([]net.IP, 0, len(hubconfig.Config.AdvertiseAddress))
+	for _, addr := range hubconfig.Config.AdvertiseAddress {
+		ips = append(ips, net.ParseIP(addr))
+	}
+	h := certs.GetHandler(certs.HandlerTypeX509)
+
+	keywrap, err := h.GenPrivateKey()
 	if err != nil {
-		return fmt.Errorf("failed to generate the token for edgecore register, err: %v", err)
+		return nil, nil, fmt.Errorf("faield to generate the private key, err: %v", err)
 	}
-	// save caHashAndToken to secret
-	if err := client.SaveSecret(ctx, createTokenSecret([]byte(caHashToken)), constants.SystemNamespace); err != nil {
-		return fmt.Errorf("failed to create tokenSecret, err: %v", err)
+	key, err := keywrap.Signer()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed parse the priavte key, err: %v", err)
+	}
+
+	ca, caKey := hubconfig.Config.SigningCA()
+	opts := certs.SignCertsOptionsWithCA(certutil.Config{
+		CommonName:   constants.ProjectName,
+		Organization: []string{constants.ProjectName},
+		Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		AltNames: certutil.AltNames{
+			DNSNames: hubconfig.Config.DNSNames,
+			IPs:      ips,
+		},
+	}, ca, caKey, key.Public(), year100)
+	certPEM, err := h.SignCerts(opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to sign the certificate, err: %v", err)
+	}
+	return certPEM.Bytes, keywrap.DER(), nil
+}
+
+// GenerateAndRefreshToken creates a token and save it to secret, then craete a timer to refresh the token.
+func GenerateAndRefreshToken(ctx context.Context) error {
+	if err := saveToken(ctx); err != nil {
+		return err
 	}
 
 	t := time.NewTicker(time.Hour * hubconfig.Config.CloudHub.TokenRefreshDuration)
@@ -174,8 +180,8 @@ func GenerateAndRefreshToken(ctx context.Context) error {
 		for {
 			select {
 			case <-t.C:
-				caHashToken, err = token.Create(hubconfig.Config.Ca, hubconfig.Config.CaKey,
-					hubconfig.Config.CloudHub.TokenRefreshDuration)
+				ca, caKey := hubconfig.Config.SigningCA()
+				_, err := token.Create(ca, caKey, hubconfig.Config.CloudHub.TokenRefreshDuration)
 				if err != nil {
 					klog.Errorf("failed to refresh the token for edgecore register, err: %v", err)
 				}
@@ -188,6 +194,20 @@ func GenerateAndRefreshToken(ctx context.Context) error {
 	return nil
 }
 
+// saveToken creates a token signed by the signing CA and saves it to secret
+func saveToken(ctx context.Context) error {
+	ca, caKey := hubconfig.Config.SigningCA()
+	caHashToken, err := token.Create(ca, caKey, hubconfig.Config.CloudHub.TokenRefreshDuration)
+	if err != nil {
+		return fmt.Errorf("failed to generate the token for edgecore register, err: %v", err)
+	}
+	// save caHashAndToken to secret
+	if err := client.SaveSecret(ctx, createTokenSecret([]byte(caHashToken)), constants.SystemNamespace); err != nil {
+		return fmt.Errorf("failed to create tokenSecret, err: %v", err)
+	}
+	return nil
+}
+
 func createTokenSecret(caHashAndToken []byte) *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -202,8 +222,8 @@ func createTokenSecret(caHashAndToken []byte) *corev1.Secret {
 	}
 }
 
-func createCaSecret(certDER, key []byte) *corev1.Secret {
-	return &corev1.Secret{
+func createCaSecret(certDER, key, retiringCertDER, retiringKey []byte) *corev1.Secret {
+	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      CaSecretName,
 			Namespace: constants.SystemNamespace,
@@ -215,6 +235,11 @@ func createCaSecret(certDER, key []byte) *corev1.Secret {
 		StringData: map[string]string{},
 		Type:       "Opaque",
 	}
+	if retiringCertDER != nil && retiringKey != nil {
+		secret.Data[carotation.RetiringCaDataName] = retiringCertDER
+		secret.Data[carotation.RetiringCaKeyDataName] = retiringKey
+	}
+	return secret
 }
 
 func createCloudCoreSecret(certDER, key []byte) *corev1.Secret {
@@ -17,43 +17,92 @@ package httpserver
 
 import (
 	"crypto/tls"
-	"encoding/pem"
+	"crypto/x509"
 	"fmt"
 	"net/http"
+	"sync"
 
 	"github.com/emicklei/go-restful"
-	certutil "k8s.io/client-go/util/cert"
 
 	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/revocation"
 	certshandler "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/certificate"
 	nodetaskhandler "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/nodetask"
 	"github.com/kubeedge/kubeedge/common/constants"
 )
 
-// StartHTTPServer starts the http service
-func StartHTTPServer() error {
+var (
+	webServicesLock sync.Mutex
+	// webServices are the APIs served by the http service besides the certificate and task APIs
+	webServices []*restful.WebService
+)
+
+// AddWebService adds the API served by the http service, it must be called before the http service starts.
+// The API authenticates its requests itself, the http service only rejects revoked client certificates.
+func AddWebService(ws *restful.WebService) {
+	webServicesLock.Lock()
+	defer webServicesLock.Unlock()
+	webServices = append(webServices, ws)
+}
+
+// StartHTTPServer starts the http service, client certificates revoked by the revocation list are rejected
+func StartHTTPServer(revocationList *revocation.List) error {
 	serverContainer := restful.NewContainer()
 	serverContainer.Add(routes())
+	webServicesLock.Lock()
+	for _, ws := range webServices {
+		serverContainer.Add(ws)
+	}
+	webServicesLock.Unlock()
 	addr := fmt.Sprintf("%s:%d", hubconfig.Config.HTTPS.Address, hubconfig.Config.HTTPS.Port)
-	cert, err := tls.X509KeyPair(
-		pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: hubconfig.Config.Cert}),
-		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: hubconfig.Config.Key}),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create a x509 tls certificate")
+	// check the certificate before serving, it is loaded for each handshake because CA rotation replaces it
+	if _, err := servingCertificate(); err != nil {
+		return err
 	}
 
 	server := &http.Server{
 		Addr:    addr,
 		Handler: serverContainer,
 		TLSConfig: &tls.Config{
-			Certificates: []tls.Certificate{cert},
-			ClientAuth:   tls.RequestClientCert,
+			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+				return servingCertificate()
+			},
+			ClientAuth: tls.RequestClientCert,
+			VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+				return checkRevocation(revocationList, rawCerts)
+			},
 		},
 	}
 	return server.ListenAndServeTLS("", "")
 }
 
+// servingCertificate returns the current certificate of cloudcore
+func servingCertificate() (*tls.Certificate, error) {
+	cert, err := hubconfig.Config.TLSCertificate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a x509 tls certificate")
+	}
+	return cert, nil
+}
+
+// checkRevocation rejects the TLS handshake if the client certificate is revoked,
+// so revoked edge nodes can neither rotate their certificates nor report tasks
+func checkRevocation(revocationList *revocation.List, rawCerts [][]byte) error {
+	if revocationList == nil {
+		return nil
+	}
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("failed to parse client certificate: %v", err)
+		}
+		if err := revocationList.CheckCertificate("", cert); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func routes() *restful.WebService {
 	ws := new(restful.WebService)
 	ws.Path("/")
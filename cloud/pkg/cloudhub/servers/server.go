@@ -2,11 +2,8 @@ package servers
 
 import (
 	"crypto/tls"
-	"crypto/x509"
-	"encoding/pem"
 	"fmt"
 
-	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 
 	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
@@ -27,36 +24,49 @@ func StartCloudHub(messageHandler handler.Handler) {
 	}
 }
 
-func createTLSConfig(ca, cert, key []byte) tls.Config {
+// createTLSConfig creates the TLS config of cloudhub servers. The CA and the certificate
+// are replaced by CA rotation, so every handshake uses the current ones, they are parsed
+// again only after they are replaced.
+func createTLSConfig() *tls.Config {
+	tlsConfig, err := newTLSConfig()
+	if err != nil {
+		panic(err)
+	}
+	tlsConfig.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return newTLSConfig()
+	}
+	return tlsConfig
+}
+
+func newTLSConfig() (*tls.Config, error) {
 	// init certificate
-	pool := x509.NewCertPool()
-	ok := pool.AppendCertsFromPEM(pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: ca}))
-	if !ok {
-		panic(fmt.Errorf("fail to load ca content"))
+	pool, err := hubconfig.Config.CACertPool()
+	if err != nil {
+		return nil, fmt.Errorf("fail to load ca content: %v", err)
 	}
 
-	certificate, err := tls.X509KeyPair(pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: cert}), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}))
+	certificate, err := hubconfig.Config.TLSCertificate()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return tls.Config{
+	return &tls.Config{
 		ClientCAs:    pool,
 		ClientAuth:   tls.RequireAndVerifyClientCert,
-		Certificates: []tls.Certificate{certificate},
+		Certificates: []tls.Certificate{*certificate},
 		MinVersion:   tls.VersionTLS12,
 		// has to match cipher used by NewPrivateKey method, currently is ECDSA
 		CipherSuites: []uint16{
 			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
 			tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256,
 		},
-	}
+	}, nil
 }
 
 func startWebsocketServer(messageHandler handler.Handler) {
-	tlsConfig := createTLSConfig(hubconfig.Config.Ca, hubconfig.Config.Cert, hubconfig.Config.Key)
+	tlsConfig := createTLSConfig()
 	svc := server.Server{
 		Type:               api.ProtocolTypeWS,
-		TLSConfig:          &tlsConfig,
+		TLSConfig:          tlsConfig,
 		AutoRoute:          true,
 		ConnNotify:         messageHandler.HandleConnection,
 		OnReadTransportErr: messageHandler.OnReadTransportErr,
@@ -68,10 +78,10 @@ func startWebsocketServer(messageHandler handler.Handler) {
 }
 
 func startQuicServer(messageHandler handler.Handler) {
-	tlsConfig := createTLSConfig(hubconfig.Config.Ca, hubconfig.Config.Cert, hubconfig.Config.Key)
+	tlsConfig := createTLSConfig()
 	svc := server.Server{
 		Type:               api.ProtocolTypeQuic,
-		TLSConfig:          &tlsConfig,
+		TLSConfig:          tlsConfig,
 		AutoRoute:          true,
 		ConnNotify:         messageHandler.HandleConnection,
 		OnReadTransportErr: messageHandler.OnReadTransportErr,
@@ -18,6 +18,7 @@ package session
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"sync"
@@ -242,6 +243,14 @@ func (ns *NodeSession) Terminating() {
 	})
 }
 
+// PeerCertificate returns the client certificate of the node connection, it is nil if there is none
+func (ns *NodeSession) PeerCertificate() *x509.Certificate {
+	if certs := ns.connection.ConnectionState().PeerCertificates; len(certs) > 0 {
+		return certs[0]
+	}
+	return nil
+}
+
 func (ns *NodeSession) SetTerminateErr(terminateErr int32) {
 	if atomic.LoadInt32(&ns.terminateErr) != NoErr {
 		return
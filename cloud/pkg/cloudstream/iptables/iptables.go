@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -25,13 +26,21 @@ import (
 	"github.com/kubeedge/kubeedge/common/constants"
 )
 
+// Backend programs the rules forwarding the tunnel ports to the stream port of cloudcore
+type Backend interface {
+	// Flush deletes all the tunnel port rules
+	Flush() error
+	// Sync makes the tunnel port rules match the latest record,
+	// the rules of the previous record which are not in the latest record are deleted.
+	Sync(previous, latest *TunnelPortRecord) error
+}
+
 type Manager struct {
-	iptables              utiliptables.Interface
+	backend               Backend
 	sharedInformerFactory k8sinformer.SharedInformerFactory
 	cmLister              v1.ConfigMapLister
 	cmListerSynced        cache.InformerSynced
 	preTunnelPortRecord   *TunnelPortRecord
-	streamPort            int
 }
 
 type TunnelPortRecord struct {
@@ -60,19 +69,22 @@ var (
 	kubeClient *kubernetes.Clientset
 )
 
-func NewIptablesManager(config *cloudcoreConfig.KubeAPIConfig, streamPort int) *Manager {
-	protocol := utiliptables.ProtocolIPv4
+func NewIptablesManager(config *cloudcoreConfig.KubeAPIConfig, streamPort int, backend cloudcoreConfig.IptablesMgrBackend) *Manager {
 	exec := utilexec.New()
-	iptInterface := utiliptables.New(exec, protocol)
 
 	iptablesMgr := &Manager{
-		iptables: iptInterface,
 		preTunnelPortRecord: &TunnelPortRecord{
 			IPTunnelPort: make(map[string]int),
 			Port:         make(map[int]bool),
 		},
-		streamPort: streamPort,
 	}
+	switch backend {
+	case cloudcoreConfig.NftablesBackend:
+		iptablesMgr.backend = newNftablesBackend(exec, streamPort)
+	default:
+		iptablesMgr.backend = newIptablesBackend(utiliptables.New(exec, utiliptables.ProtocolIPv4), streamPort)
+	}
+	klog.Infof("iptables manager uses %s backend", backend)
 
 	if kubeClient == nil {
 		kubeConfig, err := clientcmd.BuildConfigFromFlags(config.Master, config.KubeConfig)
@@ -106,79 +118,97 @@ func (im *Manager) Run(ctx context.Context) {
 		return
 	}
 
-	err := im.iptables.FlushChain(utiliptables.TableNAT, tunnelPortChain)
-	if err != nil {
-		klog.Warningf("failed to delete all rules in tunnel port iptables chain: %v", err)
+	if err := im.backend.Flush(); err != nil {
+		klog.Warningf("failed to delete all tunnel port rules: %v", err)
 	}
 
 	go wait.Until(im.reconcile, 10*time.Second, ctx.Done())
 }
 
 func (im *Manager) reconcile() {
+	latestRecord, err := im.getLatestTunnelPortRecords()
+	if err != nil {
+		klog.Errorf("failed to get latest tunnel port record in iptables manager: %v", err)
+		return
+	}
+
+	if err := im.backend.Sync(im.preTunnelPortRecord, latestRecord); err != nil {
+		klog.Errorf("failed to sync tunnel port rules in iptables manager: %v", err)
+		return
+	}
+	im.preTunnelPortRecord = latestRecord
+}
+
+// iptablesBackend programs the tunnel port rules in the TUNNEL-PORT chain of the iptables nat table
+type iptablesBackend struct {
+	iptables   utiliptables.Interface
+	streamPort int
+}
+
+func newIptablesBackend(iptables utiliptables.Interface, streamPort int) *iptablesBackend {
+	return &iptablesBackend{
+		iptables:   iptables,
+		streamPort: streamPort,
+	}
+}
+
+func (b *iptablesBackend) Flush() error {
+	return b.iptables.FlushChain(utiliptables.TableNAT, tunnelPortChain)
+}
+
+func (b *iptablesBackend) Sync(previous, latest *TunnelPortRecord) error {
 	// Create and link the tunnel port chains to OUTPUT and PREROUTING chain.
 	for _, jump := range iptablesJumpChains {
-		if _, err := im.iptables.EnsureChain(jump.table, jump.dstChain); err != nil {
-			klog.ErrorS(err, "Failed to ensure chain exists", "table", jump.table, "chain", jump.dstChain)
-			return
+		if _, err := b.iptables.EnsureChain(jump.table, jump.dstChain); err != nil {
+			return fmt.Errorf("failed to ensure chain %s exists in table %s: %v", jump.dstChain, jump.table, err)
 		}
 		args := append(jump.extraArgs,
 			"-m", "comment", "--comment", jump.comment,
 			"-j", string(jump.dstChain),
 		)
-		if _, err := im.iptables.EnsureRule(utiliptables.Append, jump.table, jump.srcChain, args...); err != nil {
-			klog.ErrorS(err, "Failed to ensure chain jumps", "table", jump.table, "srcChain", jump.srcChain, "dstChain", jump.dstChain)
-			return
+		if _, err := b.iptables.EnsureRule(utiliptables.Append, jump.table, jump.srcChain, args...); err != nil {
+			return fmt.Errorf("failed to ensure chain %s jumps to %s in table %s: %v", jump.srcChain, jump.dstChain, jump.table, err)
 		}
 	}
 
-	addedIPPort, deletedIPPort, err := im.getAddedAndDeletedCloudCoreIPPort()
-	if err != nil {
-		klog.Errorf("failed to get added and deleted cloudcore ip and port in iptables manager: %v", err)
-		return
-	}
+	addedIPPort, deletedIPPort := getAddedAndDeletedCloudCoreIPPort(previous, latest)
 
 	for _, ipports := range addedIPPort {
 		ipport := strings.Split(ipports, ":")
 		ip, port := ipport[0], ipport[1]
-		args := append([]string{"-p", "tcp", "-j", "DNAT", "--dport", port, "--to", ip + ":" + strconv.Itoa(im.streamPort)})
-		if _, err := im.iptables.EnsureRule(utiliptables.Append, utiliptables.TableNAT, tunnelPortChain, args...); err != nil {
-			klog.ErrorS(err, "Failed to ensure rules", "table", utiliptables.TableNAT, "chain", tunnelPortChain)
-			return
+		args := []string{"-p", "tcp", "-j", "DNAT", "--dport", port, "--to", ip + ":" + strconv.Itoa(b.streamPort)}
+		if _, err := b.iptables.EnsureRule(utiliptables.Append, utiliptables.TableNAT, tunnelPortChain, args...); err != nil {
+			return fmt.Errorf("failed to ensure rules in chain %s of table %s: %v", tunnelPortChain, utiliptables.TableNAT, err)
 		}
 	}
 
 	for _, ipports := range deletedIPPort {
 		ipport := strings.Split(ipports, ":")
 		ip, port := ipport[0], ipport[1]
-		args := append([]string{"-p", "tcp", "-j", "DNAT", "--dport", port, "--to", ip + ":" + strconv.Itoa(im.streamPort)})
-		if err := im.iptables.DeleteRule(utiliptables.TableNAT, tunnelPortChain, args...); err != nil {
-			klog.ErrorS(err, "Failed to delete rules", "table", utiliptables.TableNAT, "chain", tunnelPortChain)
-			return
+		args := []string{"-p", "tcp", "-j", "DNAT", "--dport", port, "--to", ip + ":" + strconv.Itoa(b.streamPort)}
+		if err := b.iptables.DeleteRule(utiliptables.TableNAT, tunnelPortChain, args...); err != nil {
+			return fmt.Errorf("failed to delete rules in chain %s of table %s: %v", tunnelPortChain, utiliptables.TableNAT, err)
 		}
 	}
+	return nil
 }
 
-func (im *Manager) getAddedAndDeletedCloudCoreIPPort() ([]string, []string, error) {
-	latestRecord, err := im.getLatestTunnelPortRecords()
-	if err != nil {
-		return nil, nil, err
-	}
-
+// getAddedAndDeletedCloudCoreIPPort returns the ip:port of the latest record,
+// and the ip:port of the previous record which are not in the latest record
+func getAddedAndDeletedCloudCoreIPPort(previous, latest *TunnelPortRecord) ([]string, []string) {
 	addedIPPorts := []string{}
-	for ip, port := range latestRecord.IPTunnelPort {
+	for ip, port := range latest.IPTunnelPort {
 		addedIPPorts = append(addedIPPorts, strings.Join([]string{ip, strconv.Itoa(port)}, ":"))
 	}
 
 	deletedIPPorts := []string{}
-	for ip, port := range im.preTunnelPortRecord.IPTunnelPort {
-		if _, ok := latestRecord.IPTunnelPort[ip]; !ok {
+	for ip, port := range previous.IPTunnelPort {
+		if latestPort, ok := latest.IPTunnelPort[ip]; !ok || latestPort != port {
 			deletedIPPorts = append(deletedIPPorts, strings.Join([]string{ip, strconv.Itoa(port)}, ":"))
 		}
 	}
 
-	im.preTunnelPortRecord = latestRecord
-
-	return addedIPPorts, deletedIPPorts, nil
+	return addedIPPorts, deletedIPPorts
 }
 
 func (im *Manager) getLatestTunnelPortRecords() (*TunnelPortRecord, error) {
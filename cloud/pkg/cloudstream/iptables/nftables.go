@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iptables
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	utilexec "k8s.io/utils/exec"
+)
+
+const (
+	// nftCommand is the command to program nftables
+	nftCommand = "nft"
+	// nftTable is the nftables table holding the tunnel port rules
+	nftTable = "ip kubeedge"
+	// nftTunnelPortChain is the chain holding the DNAT rules of tunnel ports
+	nftTunnelPortChain = "tunnel-port"
+	// nftNATPriority is the priority of the nat chains, it equals to dstnat
+	// which is not supported by old nft commands
+	nftNATPriority = -100
+)
+
+// nftJumpChains are the base chains jumping to the tunnel port chain, like iptablesJumpChains
+var nftJumpChains = []string{"prerouting", "output"}
+
+// nftablesBackend programs the tunnel port rules in the kubeedge table of nftables.
+// Every sync replaces the whole table in one transaction, so the rules are never partially applied.
+type nftablesBackend struct {
+	exec       utilexec.Interface
+	streamPort int
+}
+
+func newNftablesBackend(exec utilexec.Interface, streamPort int) *nftablesBackend {
+	return &nftablesBackend{
+		exec:       exec,
+		streamPort: streamPort,
+	}
+}
+
+func (b *nftablesBackend) Flush() error {
+	return b.run(nftDeleteTableScript())
+}
+
+// Sync replaces the table with the rules of the latest record, rules of the previous record are
+// deleted along with the table, so the previous record is not needed.
+func (b *nftablesBackend) Sync(_, latest *TunnelPortRecord) error {
+	return b.run(nftSyncScript(latest, b.streamPort))
+}
+
+func (b *nftablesBackend) run(script string) error {
+	cmd := b.exec.Command(nftCommand, "-f", "-")
+	cmd.SetStdin(strings.NewReader(script))
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to run %s: %v, output: %s", nftCommand, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// nftDeleteTableScript deletes the table, the table is added first so deleting does not fail if it does not exist
+func nftDeleteTableScript() string {
+	return fmt.Sprintf("add table %s\ndelete table %s\n", nftTable, nftTable)
+}
+
+// nftSyncScript recreates the table with the DNAT rules of the record, rules are sorted by ip
+// so the same record always generates the same script
+func nftSyncScript(record *TunnelPortRecord, streamPort int) string {
+	var sb strings.Builder
+	sb.WriteString(nftDeleteTableScript())
+	fmt.Fprintf(&sb, "add table %s\n", nftTable)
+	fmt.Fprintf(&sb, "add chain %s %s\n", nftTable, nftTunnelPortChain)
+	for _, chain := range nftJumpChains {
+		fmt.Fprintf(&sb, "add chain %s %s { type nat hook %s priority %d ; policy accept ; }\n",
+			nftTable, chain, chain, nftNATPriority)
+		fmt.Fprintf(&sb, "add rule %s %s jump %s\n", nftTable, chain, nftTunnelPortChain)
+	}
+
+	ips := make([]string, 0, len(record.IPTunnelPort))
+	for ip := range record.IPTunnelPort {
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+	for _, ip := range ips {
+		fmt.Fprintf(&sb, "add rule %s %s tcp dport %d dnat to %s:%d\n",
+			nftTable, nftTunnelPortChain, record.IPTunnelPort[ip], ip, streamPort)
+	}
+	return sb.String()
+}
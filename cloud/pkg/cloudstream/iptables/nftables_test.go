@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iptables
+
+import (
+	"testing"
+)
+
+func TestNftSyncScript(t *testing.T) {
+	record := &TunnelPortRecord{
+		IPTunnelPort: map[string]int{
+			"192.168.0.2": 10352,
+			"192.168.0.1": 10351,
+		},
+		Port: map[int]bool{10351: true, 10352: true},
+	}
+
+	expected := `add table ip kubeedge
+delete table ip kubeedge
+add table ip kubeedge
+add chain ip kubeedge tunnel-port
+add chain ip kubeedge prerouting { type nat hook prerouting priority -100 ; policy accept ; }
+add rule ip kubeedge prerouting jump tunnel-port
+add chain ip kubeedge output { type nat hook output priority -100 ; policy accept ; }
+add rule ip kubeedge output jump tunnel-port
+add rule ip kubeedge tunnel-port tcp dport 10351 dnat to 192.168.0.1:10003
+add rule ip kubeedge tunnel-port tcp dport 10352 dnat to 192.168.0.2:10003
+`
+	if script := nftSyncScript(record, 10003); script != expected {
+		t.Errorf("expected script:\n%s\ngot:\n%s", expected, script)
+	}
+	if nftSyncScript(record, 10003) != expected {
+		t.Error("expected the same record to generate the same script")
+	}
+}
+
+func TestGetAddedAndDeletedCloudCoreIPPort(t *testing.T) {
+	previous := &TunnelPortRecord{IPTunnelPort: map[string]int{
+		"192.168.0.1": 10351,
+		"192.168.0.2": 10352,
+		"192.168.0.3": 10353,
+	}}
+	latest := &TunnelPortRecord{IPTunnelPort: map[string]int{
+		"192.168.0.1": 10351,
+		"192.168.0.2": 10354,
+	}}
+
+	added, deleted := getAddedAndDeletedCloudCoreIPPort(previous, latest)
+	if len(added) != 2 {
+		t.Errorf("expected rules of 2 ports ensured, got %v", added)
+	}
+	deletedSet := map[string]bool{}
+	for _, ipport := range deleted {
+		deletedSet[ipport] = true
+	}
+	if len(deleted) != 2 || !deletedSet["192.168.0.2:10352"] || !deletedSet["192.168.0.3:10353"] {
+		t.Errorf("expected rules of the changed and removed ports deleted, got %v", deleted)
+	}
+}
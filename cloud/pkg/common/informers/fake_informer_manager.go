@@ -85,7 +85,9 @@ func (fm *fakeManager) Start(<-chan struct{}) {
 // GetInformerPair return InformerPair for the given GVR
 func (fm *fakeManager) GetInformerPair(gvr schema.GroupVersionResource) (*InformerPair, error) {
 	switch gvr {
-	case schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}:
+	case schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"},
+		schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"},
+		schema.GroupVersionResource{Group: "", Version: "v1", Resource: "secrets"}:
 		fm.lock.Lock()
 		defer fm.lock.Unlock()
 
@@ -94,13 +96,13 @@ func (fm *fakeManager) GetInformerPair(gvr schema.GroupVersionResource) (*Inform
 			return informer, nil
 		}
 
-		podInformer, err := fm.kubeInformerFactory.ForResource(gvr)
+		genericInformer, err := fm.kubeInformerFactory.ForResource(gvr)
 		if err != nil {
 			return nil, err
 		}
 		fm.informersByGVR[gvr] = &InformerPair{
-			Lister:   podInformer.Lister(),
-			Informer: podInformer.Informer(),
+			Lister:   genericInformer.Lister(),
+			Informer: genericInformer.Informer(),
 		}
 
 		return fm.informersByGVR[gvr], nil
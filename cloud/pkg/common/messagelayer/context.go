@@ -111,3 +111,11 @@ func PolicyControllerMessageLayer() MessageLayer {
 		ResponseModuleName: modules.CloudHubModuleName,
 	}
 }
+
+func SyncControllerMessageLayer() MessageLayer {
+	return &ContextMessageLayer{
+		SendModuleName:     modules.CloudHubModuleName,
+		ReceiveModuleName:  modules.SyncControllerModuleName,
+		ResponseModuleName: modules.CloudHubModuleName,
+	}
+}
@@ -43,6 +43,8 @@ const (
 	ResourceTypeTwinEdgeUpdated  = "twin/edge_updated"
 	ResourceTypeMembershipDetail = "membership/detail"
 	ResourceDeviceStateUpdated   = "state/update"
+	ResourceTypeTwinEdgeHistory  = "twin/edge_history"
+	ResourceTypeMapperStatus     = "mapper/status"
 )
 
 // BuildResource return a string as "beehive/pkg/core/model".Message.Router.Resource
@@ -146,6 +148,10 @@ func GetResourceTypeForDevice(resource string) (string, error) {
 		return ResourceTypeMembershipDetail, nil
 	} else if strings.Contains(resource, ResourceDeviceStateUpdated) {
 		return ResourceDeviceStateUpdated, nil
+	} else if strings.Contains(resource, ResourceTypeTwinEdgeHistory) {
+		return ResourceTypeTwinEdgeHistory, nil
+	} else if strings.Contains(resource, ResourceTypeMapperStatus) {
+		return ResourceTypeMapperStatus, nil
 	}
 	return "", fmt.Errorf("unknown resource, found: %s", resource)
 }
@@ -188,6 +188,22 @@ func TestGetResourceTypeForDevice(t *testing.T) {
 			ResourceTypeMembershipDetail,
 			nil,
 		},
+		{
+			"GetResourceTypeForDevice() ResourceTypeTwinEdgeHistory: success",
+			args{
+				resource: fmt.Sprintf("node/%s/%s", "nid", ResourceTypeTwinEdgeHistory),
+			},
+			ResourceTypeTwinEdgeHistory,
+			nil,
+		},
+		{
+			"GetResourceTypeForDevice() ResourceTypeMapperStatus: success",
+			args{
+				resource: fmt.Sprintf("node/%s/%s", "nid", ResourceTypeMapperStatus),
+			},
+			ResourceTypeMapperStatus,
+			nil,
+		},
 		{
 			"GetResourceTypeForDevice() Case 2: no resourceType",
 			args{
@@ -70,6 +70,14 @@ func setupControllers(ctx context.Context, mgr manager.Manager) error {
 				&overridemanager.ArgsOverrider{},
 				&overridemanager.EnvOverrider{},
 				&overridemanager.ResourcesOverrider{},
+				&overridemanager.LabelsAnnotationsOverrider{},
+				&overridemanager.TolerationsOverrider{},
+				&overridemanager.VolumesOverrider{},
+				&overridemanager.VolumeMountsOverrider{},
+				&overridemanager.AffinityOverrider{},
+				// JSONPatchOverrider must be the last one, so that the patches
+				// are applied on the result of all the other overriders.
+				&overridemanager.JSONPatchOverrider{},
 			},
 		},
 	}
@@ -5,6 +5,16 @@ import "k8s.io/apimachinery/pkg/runtime/schema"
 const (
 	LastAppliedTemplateAnnotationKey    = "apps.kubeedge.io/last-applied-template"
 	LastContainedResourcesAnnotationKey = "apps.kubeedge.io/last-contained-resources"
+	// ReadyConditionTypeAnnotationKey is the annotation of EdgeApplication to set the condition type
+	// which represents that the custom resources in its manifests are available, defaults to Ready.
+	ReadyConditionTypeAnnotationKey = "apps.kubeedge.io/ready-condition-type"
+	DefaultReadyConditionType       = "Ready"
+	// EdgeApplicationNameLabelKey is the label of the ControllerRevisions of EdgeApplication,
+	// its value is the name of the EdgeApplication.
+	EdgeApplicationNameLabelKey = "apps.kubeedge.io/edgeapplication-name"
+	// RevisionAnnotationKey is the annotation of the resources deployed to node groups,
+	// its value is the name of the ControllerRevision that the resource is generated from.
+	RevisionAnnotationKey = "apps.kubeedge.io/revision"
 )
 
 var OverriderTargetGVK = map[schema.GroupVersionKind]struct{}{
@@ -21,3 +31,26 @@ var DeploymentGVK = schema.GroupVersionKind{
 	Version: "v1",
 	Kind:    "Deployment",
 }
+
+var StatefulSetGVK = schema.GroupVersionKind{
+	Group:   "apps",
+	Version: "v1",
+	Kind:    "StatefulSet",
+}
+
+var DaemonSetGVK = schema.GroupVersionKind{
+	Group:   "apps",
+	Version: "v1",
+	Kind:    "DaemonSet",
+}
+
+var JobGVK = schema.GroupVersionKind{
+	Group:   "batch",
+	Version: "v1",
+	Kind:    "Job",
+}
+
+var EndpointsGVK = schema.GroupVersionKind{
+	Version: "v1",
+	Kind:    "Endpoints",
+}
@@ -95,12 +95,28 @@ func (c *Controller) SetupWithManager(mgr controllerruntime.Manager) error {
 }
 
 func (c *Controller) syncEdgeApplication(ctx context.Context, edgeApp *appsv1alpha1.EdgeApplication) (controllerruntime.Result, error) {
-	// 1. get manifests, set ownerReference and apply overrides to all target resources
+	// 1. record the spec as a revision, and restore the spec from the revision to roll back to
+	revisions, err := c.listRevisions(ctx, edgeApp)
+	if err != nil {
+		klog.Errorf("failed to get revisions of EdgeApplication %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
+		return controllerruntime.Result{Requeue: true}, err
+	}
+	updateRevision, err := c.syncRevision(ctx, edgeApp, revisions)
+	if err != nil {
+		klog.Errorf("failed to sync revision of EdgeApplication %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
+		return controllerruntime.Result{Requeue: true}, err
+	}
+	if edgeApp.Spec.RollbackTo != nil {
+		// the restored spec will be synced in the next reconciliation
+		return controllerruntime.Result{}, c.rollback(ctx, edgeApp, revisions, updateRevision)
+	}
+
+	// 2. get manifests, set ownerReference and apply overrides to all target resources
 	// It will traverse all templates in EdgeApplication. If error occurs during traverse,
-	// it will log the error and continue.
+	// it will log the error and continue. The templates that need override are generated
+	// for each nodegroup from the revision planned by the rollout strategy.
 	modifiedTmplInfos := []*utils.TemplateInfo{}
 	errs := []error{}
-	overriderInfos := utils.GetAllOverriders(edgeApp)
 	tmplInfos, err := utils.GetTemplatesInfosOfEdgeApp(edgeApp, c.Serializer)
 	if err != nil {
 		klog.Errorf("failed to get all templates from edgeapp %s/%s, %v, continue with what got", edgeApp.Namespace, edgeApp.Name, err)
@@ -121,32 +137,18 @@ func (c *Controller) syncEdgeApplication(ctx context.Context, edgeApp *appsv1alp
 			modifiedTmplInfos = append(modifiedTmplInfos, tmplInfo)
 			continue
 		}
-
-		// apply overriders
-		//
-		// TODO: consider the situation that not all the overrides have been applied successfully
-		// If one succeeded and another failed, the status of edgeApp will only contain the successful
-		// one, and have no status about the failed one.
-		for _, info := range overriderInfos {
-			tmplCopy := tmpl.DeepCopy()
-			klog.V(4).Infof("override obj %s/%s of gvk %s, for nodegroup %s", tmplCopy.GetNamespace(), tmplCopy.GetName(), tmplCopy.GroupVersionKind(), info.TargetNodeGroup)
-			if err := c.Overrider.ApplyOverrides(tmplCopy, info); err != nil {
-				klog.Errorf("failed to apply override of nodegroup %s to obj %s/%s of gvk %s, %v",
-					info.TargetNodeGroup, tmplCopy.GetNamespace(), tmplCopy.GetName(), tmplCopy.GroupVersionKind(), err)
-				errs = append(errs, err)
-				continue
-			}
-			modifiedTmplInfos = append(modifiedTmplInfos, &utils.TemplateInfo{Ordinal: tmplInfo.Ordinal, Template: tmplCopy})
-		}
 	}
+	overriddenTmplInfos, nodeGroupRevisions, completed, overrideErrs := c.rollout(ctx, edgeApp, tmplInfos, revisions, updateRevision.Name)
+	modifiedTmplInfos = append(modifiedTmplInfos, overriddenTmplInfos...)
+	errs = append(errs, overrideErrs...)
 
-	// 2. remove status that do not need
-	if err := c.updateStatus(ctx, edgeApp, modifiedTmplInfos); err != nil {
+	// 3. remove status that do not need
+	if err := c.updateStatus(ctx, edgeApp, modifiedTmplInfos, updateRevision.Name, nodeGroupRevisions); err != nil {
 		klog.Errorf("failed to update status for EdgeApplication %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
 		errs = append(errs, err)
 	}
 
-	// 3. apply all templates
+	// 4. apply all templates
 	// It will create/update the resource in the template and notify the status manager
 	// to monitor its status.
 	for _, tmplInfo := range modifiedTmplInfos {
@@ -159,18 +161,32 @@ func (c *Controller) syncEdgeApplication(ctx context.Context, edgeApp *appsv1alp
 		klog.V(4).Infof("successfully applied overridden template of EdgeApplication %s/%s, template: %v", edgeApp.Namespace, edgeApp.Name, tmpl)
 	}
 
-	// 4. delete resources that have been removed from the manifests
+	// 5. delete resources that have been removed from the manifests
 	if err := c.deleteRedundantResources(ctx, edgeApp, modifiedTmplInfos); err != nil {
 		klog.Errorf("failed to delete redundant resource for EdgeApplication %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
 		errs = append(errs, err)
 	}
 
-	// 5. update the LastContainedResourcesAnnotation
+	// 6. update the LastContainedResourcesAnnotation
 	if err := c.addOrUpdateLastContainedResourcesAnnotation(ctx, edgeApp, modifiedTmplInfos); err != nil {
 		klog.Errorf("failed to update annotation of EdgeApplication %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
 		errs = append(errs, err)
 	}
 
+	// 7. delete the old revisions that are not used by any nodegroup
+	inUse := map[string]struct{}{updateRevision.Name: {}}
+	for _, revision := range nodeGroupRevisions {
+		inUse[revision] = struct{}{}
+	}
+	if err := c.truncateHistory(ctx, edgeApp, revisions, inUse); err != nil {
+		klog.Errorf("failed to truncate revision history of EdgeApplication %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
+		errs = append(errs, err)
+	}
+
+	if !completed && !isRolloutPaused(edgeApp) {
+		// wait for the nodegroups in the current wave to be available
+		return controllerruntime.Result{RequeueAfter: rolloutRequeueInterval}, errors.NewAggregate(errs)
+	}
 	return controllerruntime.Result{}, errors.NewAggregate(errs)
 }
 
@@ -198,7 +214,8 @@ func (c *Controller) deleteRedundantResources(ctx context.Context, edgeApp *apps
 	return nil
 }
 
-func (c *Controller) updateStatus(ctx context.Context, edgeApp *appsv1alpha1.EdgeApplication, tmplInfos []*utils.TemplateInfo) error {
+func (c *Controller) updateStatus(ctx context.Context, edgeApp *appsv1alpha1.EdgeApplication, tmplInfos []*utils.TemplateInfo,
+	updateRevision string, nodeGroupRevisions map[string]string) error {
 	newStatus := []appsv1alpha1.ManifestStatus{}
 	tmplMap := map[int][]*utils.TemplateInfo{}
 	for _, tmplInfo := range tmplInfos {
@@ -273,14 +290,19 @@ func (c *Controller) updateStatus(ctx context.Context, edgeApp *appsv1alpha1.Edg
 		return newStatus[i].Identifier.Name < newStatus[j].Identifier.Name
 	})
 
-	if equality.Semantic.DeepEqual(newStatus, edgeApp.Status.WorkloadStatus) {
+	newEdgeApp := edgeApp.DeepCopy()
+	newEdgeApp.Status.WorkloadStatus = newStatus
+	newEdgeApp.Status.NodeGroupStatus = utils.AggregateNodeGroupStatus(newEdgeApp)
+	newEdgeApp.Status.UpdateRevision = updateRevision
+	for i := range newEdgeApp.Status.NodeGroupStatus {
+		status := &newEdgeApp.Status.NodeGroupStatus[i]
+		status.Revision = nodeGroupRevisions[status.NodeGroup]
+	}
+	if equality.Semantic.DeepEqual(newEdgeApp.Status, edgeApp.Status) {
 		klog.V(4).Infof("newStatus is same as the current status in edgeApp %s/%s, skip update status",
 			edgeApp.Namespace, edgeApp.Name)
 		return nil
 	}
-
-	newEdgeApp := edgeApp.DeepCopy()
-	newEdgeApp.Status.WorkloadStatus = newStatus
 	return c.Client.Status().Patch(ctx, newEdgeApp, client.MergeFrom(edgeApp))
 }
 
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overridemanager
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/apps/v1alpha1"
+)
+
+type AffinityOverrider struct{}
+
+func (o *AffinityOverrider) ApplyOverrides(rawObj *unstructured.Unstructured, overriders OverriderInfo) error {
+	affinityOverrider := overriders.Overriders.AffinityOverrider
+	podSpecPath := podSpecPathOf(rawObj.GetKind())
+	if affinityOverrider == nil || podSpecPath == "" {
+		return nil
+	}
+
+	affinityPath := podSpecPath + "/affinity"
+	var affinity interface{}
+	exists, err := nestedTypedField(rawObj, &affinity, affinityPath)
+	if err != nil {
+		return err
+	}
+
+	var patch overrideOption
+	switch affinityOverrider.Operator {
+	case v1alpha1.OverriderOpReplace:
+		if affinityOverrider.Value == nil {
+			return fmt.Errorf("the value of affinity overrider must not be empty when operator is %s", affinityOverrider.Operator)
+		}
+		patch = setFieldOption(exists, affinityPath, affinityOverrider.Value)
+	case v1alpha1.OverriderOpRemove:
+		if !exists {
+			return nil
+		}
+		patch = overrideOption{
+			Op:   string(v1alpha1.OverriderOpRemove),
+			Path: affinityPath,
+		}
+	default:
+		klog.V(4).Infof("[AffinityOverrider], op: %s , op not supported, ignored.", affinityOverrider.Operator)
+		return nil
+	}
+
+	klog.V(4).Infof("Parsed JSON patches by AffinityOverrider(%+v): %+v", affinityOverrider, patch)
+	return applyJSONPatch(rawObj, []overrideOption{patch})
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overridemanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/apps/v1alpha1"
+)
+
+// immutablePaths are the paths which cannot be patched by JSONPatchOverrider,
+// the identity of the object is maintained by the EdgeApplication controller.
+var immutablePaths = []string{
+	"/apiVersion",
+	"/kind",
+	"/metadata/name",
+	"/metadata/namespace",
+	"/metadata/ownerReferences",
+}
+
+type JSONPatchOverrider struct{}
+
+func (o *JSONPatchOverrider) ApplyOverrides(rawObj *unstructured.Unstructured, overriders OverriderInfo) error {
+	jsonPatchOverriders := overriders.Overriders.JSONPatchOverriders
+	if len(jsonPatchOverriders) == 0 {
+		return nil
+	}
+
+	patches := make([]overrideOption, 0, len(jsonPatchOverriders))
+	for index := range jsonPatchOverriders {
+		patch, err := buildJSONPatch(&jsonPatchOverriders[index])
+		if err != nil {
+			return fmt.Errorf("invalid json patch overrider %d for node group %s, %v", index, overriders.TargetNodeGroup, err)
+		}
+		patches = append(patches, patch)
+	}
+
+	klog.V(4).Infof("Parsed JSON patches by JSONPatchOverrider(%+v): %+v", jsonPatchOverriders, patches)
+	patchedObj := rawObj.DeepCopy()
+	if err := applyJSONPatch(patchedObj, patches); err != nil {
+		return fmt.Errorf("failed to apply json patches on %s %s/%s, %v",
+			rawObj.GetKind(), rawObj.GetNamespace(), rawObj.GetName(), err)
+	}
+	if err := validatePatchedObject(rawObj, patchedObj); err != nil {
+		return fmt.Errorf("invalid %s %s/%s after applying json patches, %v",
+			rawObj.GetKind(), rawObj.GetNamespace(), rawObj.GetName(), err)
+	}
+	rawObj.Object = patchedObj.Object
+	return nil
+}
+
+func buildJSONPatch(overrider *v1alpha1.JSONPatchOverrider) (overrideOption, error) {
+	if err := validateJSONPatchPath(overrider.Path); err != nil {
+		return overrideOption{}, err
+	}
+	patch := overrideOption{
+		Op:   string(overrider.Operator),
+		Path: overrider.Path,
+	}
+
+	switch overrider.Operator {
+	case v1alpha1.JSONPatchOpAdd, v1alpha1.JSONPatchOpReplace, v1alpha1.JSONPatchOpTest:
+		if overrider.Value == nil || len(overrider.Value.Raw) == 0 {
+			return overrideOption{}, fmt.Errorf("value must not be empty when operator is %s", overrider.Operator)
+		}
+		patch.Value = json.RawMessage(overrider.Value.Raw)
+	case v1alpha1.JSONPatchOpMove:
+		// the source field is removed by move
+		if err := validateJSONPatchPath(overrider.From); err != nil {
+			return overrideOption{}, fmt.Errorf("invalid from, %v", err)
+		}
+		patch.From = overrider.From
+	case v1alpha1.JSONPatchOpCopy:
+		if !strings.HasPrefix(overrider.From, pathSplit) {
+			return overrideOption{}, fmt.Errorf("from %q should start with / character", overrider.From)
+		}
+		patch.From = overrider.From
+	case v1alpha1.JSONPatchOpRemove:
+	default:
+		return overrideOption{}, fmt.Errorf("unsupported operator %s", overrider.Operator)
+	}
+	return patch, nil
+}
+
+func validateJSONPatchPath(path string) error {
+	if !strings.HasPrefix(path, pathSplit) {
+		return fmt.Errorf("path %q should start with / character", path)
+	}
+	for _, immutablePath := range immutablePaths {
+		// the parents of the immutable paths, such as /metadata, cannot be patched as a whole either
+		if path == immutablePath || strings.HasPrefix(path, immutablePath+pathSplit) ||
+			strings.HasPrefix(immutablePath, path+pathSplit) {
+			return fmt.Errorf("path %q cannot be patched", path)
+		}
+	}
+	return nil
+}
+
+// validatePatchedObject makes sure that the identity of the object is not changed by
+// the patches, and the patched object is still valid for the kinds known by the scheme.
+func validatePatchedObject(origin, patched *unstructured.Unstructured) error {
+	if patched.GroupVersionKind() != origin.GroupVersionKind() ||
+		patched.GetName() != origin.GetName() || patched.GetNamespace() != origin.GetNamespace() {
+		return fmt.Errorf("the apiVersion, kind, name and namespace cannot be changed")
+	}
+	if !equality.Semantic.DeepEqual(patched.GetOwnerReferences(), origin.GetOwnerReferences()) {
+		return fmt.Errorf("the ownerReferences cannot be changed")
+	}
+
+	typedObj, err := scheme.Scheme.New(patched.GroupVersionKind())
+	if err != nil {
+		// the schema of the object is unknown, such as custom resources,
+		// leave it to the apiserver
+		return nil
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructuredWithValidation(patched.Object, typedObj, true)
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overridemanager
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubeedge/api/apis/apps/v1alpha1"
+)
+
+func newTestDeployment() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "nginx",
+			"namespace": "default",
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":  "nginx",
+							"image": "nginx",
+						},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func TestJSONPatchOverrider(t *testing.T) {
+	raw := func(value string) *runtime.RawExtension {
+		return &runtime.RawExtension{Raw: []byte(value)}
+	}
+	cases := []struct {
+		name       string
+		overriders []v1alpha1.JSONPatchOverrider
+		expectErr  bool
+		check      func(obj *unstructured.Unstructured) bool
+	}{
+		{
+			name: "copy the value to the field of another type",
+			overriders: []v1alpha1.JSONPatchOverrider{
+				{Operator: v1alpha1.JSONPatchOpCopy, From: "/spec/template/spec/containers/0/image", Path: "/metadata/annotations"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "add fields",
+			overriders: []v1alpha1.JSONPatchOverrider{
+				{Operator: v1alpha1.JSONPatchOpAdd, Path: "/spec/template/spec/hostNetwork", Value: raw("true")},
+				{Operator: v1alpha1.JSONPatchOpAdd, Path: "/spec/template/spec/containers/0/securityContext", Value: raw(`{"privileged":true}`)},
+			},
+			check: func(obj *unstructured.Unstructured) bool {
+				hostNetwork, _, _ := unstructured.NestedBool(obj.Object, "spec", "template", "spec", "hostNetwork")
+				return hostNetwork
+			},
+		},
+		{
+			name: "the identity of the object cannot be patched",
+			overriders: []v1alpha1.JSONPatchOverrider{
+				{Operator: v1alpha1.JSONPatchOpReplace, Path: "/metadata/name", Value: raw(`"foo"`)},
+			},
+			expectErr: true,
+		},
+		{
+			name: "the parent of the identity of the object cannot be patched",
+			overriders: []v1alpha1.JSONPatchOverrider{
+				{Operator: v1alpha1.JSONPatchOpReplace, Path: "/metadata", Value: raw(`{"name":"nginx","namespace":"default","ownerReferences":[]}`)},
+			},
+			expectErr: true,
+		},
+		{
+			name: "the labels can be patched",
+			overriders: []v1alpha1.JSONPatchOverrider{
+				{Operator: v1alpha1.JSONPatchOpAdd, Path: "/metadata/labels", Value: raw(`{"app":"nginx"}`)},
+			},
+			check: func(obj *unstructured.Unstructured) bool {
+				return obj.GetLabels()["app"] == "nginx"
+			},
+		},
+		{
+			name: "the field unknown by the manifest",
+			overriders: []v1alpha1.JSONPatchOverrider{
+				{Operator: v1alpha1.JSONPatchOpAdd, Path: "/spec/template/spec/hostNetwrok", Value: raw("true")},
+			},
+			expectErr: true,
+		},
+		{
+			name: "the value of the wrong type",
+			overriders: []v1alpha1.JSONPatchOverrider{
+				{Operator: v1alpha1.JSONPatchOpAdd, Path: "/spec/template/spec/hostNetwork", Value: raw(`"yes"`)},
+			},
+			expectErr: true,
+		},
+		{
+			name: "the value is required",
+			overriders: []v1alpha1.JSONPatchOverrider{
+				{Operator: v1alpha1.JSONPatchOpReplace, Path: "/spec/replicas"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "remove the field which does not exist",
+			overriders: []v1alpha1.JSONPatchOverrider{
+				{Operator: v1alpha1.JSONPatchOpRemove, Path: "/spec/replicas"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			obj := newTestDeployment()
+			origin := obj.DeepCopy()
+			err := (&JSONPatchOverrider{}).ApplyOverrides(obj, OverriderInfo{
+				TargetNodeGroup: "ng1",
+				Overriders:      &v1alpha1.Overriders{JSONPatchOverriders: c.overriders},
+			})
+			if (err != nil) != c.expectErr {
+				t.Fatalf("expected error %v, got %v", c.expectErr, err)
+			}
+			if err != nil {
+				if !equalObjects(obj, origin) {
+					t.Errorf("expected the object unchanged on error, got %v", obj.Object)
+				}
+				return
+			}
+			if !c.check(obj) {
+				t.Errorf("unexpected object after applying json patches %v", obj.Object)
+			}
+		})
+	}
+}
+
+func equalObjects(a, b *unstructured.Unstructured) bool {
+	aj, _ := a.MarshalJSON()
+	bj, _ := b.MarshalJSON()
+	return string(aj) == string(bj)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overridemanager
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/apps/v1alpha1"
+)
+
+type LabelsAnnotationsOverrider struct{}
+
+func (o *LabelsAnnotationsOverrider) ApplyOverrides(rawObj *unstructured.Unstructured, overriders OverriderInfo) error {
+	if len(overriders.Overriders.LabelsOverriders) != 0 {
+		labels := rawObj.GetLabels()
+		for index := range overriders.Overriders.LabelsOverriders {
+			labels = overrideLabelsAnnotations(labels, &overriders.Overriders.LabelsOverriders[index])
+		}
+		rawObj.SetLabels(labels)
+	}
+	if len(overriders.Overriders.AnnotationsOverriders) != 0 {
+		annotations := rawObj.GetAnnotations()
+		for index := range overriders.Overriders.AnnotationsOverriders {
+			annotations = overrideLabelsAnnotations(annotations, &overriders.Overriders.AnnotationsOverriders[index])
+		}
+		rawObj.SetAnnotations(annotations)
+	}
+	return nil
+}
+
+func overrideLabelsAnnotations(cur map[string]string, overrider *v1alpha1.LabelAnnotationOverrider) map[string]string {
+	switch overrider.Operator {
+	case v1alpha1.OverriderOpAdd, v1alpha1.OverriderOpReplace:
+		if cur == nil {
+			cur = make(map[string]string, len(overrider.Value))
+		}
+		for k, v := range overrider.Value {
+			cur[k] = v
+		}
+	case v1alpha1.OverriderOpRemove:
+		for k := range overrider.Value {
+			delete(cur, k)
+		}
+	default:
+		klog.V(4).Infof("[overrideLabelsAnnotations], op: %s , op not supported, ignored.", overrider.Operator)
+	}
+	return cur
+}
@@ -3,6 +3,7 @@ package overridemanager
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -15,6 +16,7 @@ import (
 type overrideOption struct {
 	Op    string      `json:"op"`
 	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
 	Value interface{} `json:"value,omitempty"`
 }
 
@@ -67,3 +69,46 @@ func applyJSONPatch(obj *unstructured.Unstructured, overrides []overrideOption)
 	err = obj.UnmarshalJSON(patchedObjectJSONBytes)
 	return err
 }
+
+// podSpecPathOf returns the path of the pod spec in the object of the given kind,
+// the path is empty if the kind has no pod spec.
+func podSpecPathOf(kind string) string {
+	switch kind {
+	case PodKind:
+		return podSpecPrefix
+	case ReplicaSetKind, DeploymentKind, DaemonSetKind, JobKind, StatefulSetKind:
+		return podTemplatePrefix
+	}
+	return ""
+}
+
+// nestedTypedField gets the field at the given path of the object and converts it to out,
+// it returns false if the field does not exist.
+func nestedTypedField(obj *unstructured.Unstructured, out interface{}, path string) (bool, error) {
+	val, ok, err := unstructured.NestedFieldNoCopy(obj.Object, strings.Split(strings.TrimPrefix(path, pathSplit), pathSplit)...)
+	if err != nil || !ok {
+		return false, err
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return false, fmt.Errorf("failed to convert the field %s, %v", path, err)
+	}
+	return true, nil
+}
+
+// setFieldOption returns the JSONPatch option which sets the field at the given path
+// to value whether or not the field exists.
+func setFieldOption(exists bool, path string, value interface{}) overrideOption {
+	op := appsv1alpha1.OverriderOpAdd
+	if exists {
+		op = appsv1alpha1.OverriderOpReplace
+	}
+	return overrideOption{
+		Op:    string(op),
+		Path:  path,
+		Value: value,
+	}
+}
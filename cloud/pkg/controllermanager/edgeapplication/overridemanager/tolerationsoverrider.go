@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overridemanager
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/apps/v1alpha1"
+)
+
+type TolerationsOverrider struct{}
+
+func (o *TolerationsOverrider) ApplyOverrides(rawObj *unstructured.Unstructured, overriders OverriderInfo) error {
+	tolerationsOverriders := overriders.Overriders.TolerationsOverriders
+	podSpecPath := podSpecPathOf(rawObj.GetKind())
+	if len(tolerationsOverriders) == 0 || podSpecPath == "" {
+		return nil
+	}
+
+	tolerationsPath := podSpecPath + "/tolerations"
+	for index := range tolerationsOverriders {
+		var tolerations []corev1.Toleration
+		exists, err := nestedTypedField(rawObj, &tolerations, tolerationsPath)
+		if err != nil {
+			return err
+		}
+		patch := setFieldOption(exists, tolerationsPath, overrideTolerations(tolerations, &tolerationsOverriders[index]))
+
+		klog.V(4).Infof("Parsed JSON patches by TolerationsOverrider(%+v): %+v", tolerationsOverriders[index], patch)
+		if err = applyJSONPatch(rawObj, []overrideOption{patch}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func overrideTolerations(cur []corev1.Toleration, overrider *v1alpha1.TolerationsOverrider) []corev1.Toleration {
+	newTolerations := make([]corev1.Toleration, 0, len(cur)+len(overrider.Value))
+	switch overrider.Operator {
+	case v1alpha1.OverriderOpAdd:
+		newTolerations = append(newTolerations, cur...)
+		for _, toleration := range overrider.Value {
+			if !containsToleration(newTolerations, toleration) {
+				newTolerations = append(newTolerations, toleration)
+			}
+		}
+	case v1alpha1.OverriderOpRemove:
+		keys := sets.NewString()
+		for _, toleration := range overrider.Value {
+			keys.Insert(toleration.Key)
+		}
+		for _, toleration := range cur {
+			if !keys.Has(toleration.Key) {
+				newTolerations = append(newTolerations, toleration)
+			}
+		}
+	case v1alpha1.OverriderOpReplace:
+		newTolerations = append(newTolerations, overrider.Value...)
+	default:
+		klog.V(4).Infof("[overrideTolerations], op: %s , op not supported, ignored.", overrider.Operator)
+		newTolerations = append(newTolerations, cur...)
+	}
+	return newTolerations
+}
+
+func containsToleration(tolerations []corev1.Toleration, toleration corev1.Toleration) bool {
+	for i := range tolerations {
+		if equality.Semantic.DeepEqual(tolerations[i], toleration) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overridemanager
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/apps/v1alpha1"
+)
+
+type VolumesOverrider struct{}
+
+func (o *VolumesOverrider) ApplyOverrides(rawObj *unstructured.Unstructured, overriders OverriderInfo) error {
+	volumesOverriders := overriders.Overriders.VolumesOverriders
+	podSpecPath := podSpecPathOf(rawObj.GetKind())
+	if len(volumesOverriders) == 0 || podSpecPath == "" {
+		return nil
+	}
+
+	volumesPath := podSpecPath + "/volumes"
+	for index := range volumesOverriders {
+		var volumes []corev1.Volume
+		exists, err := nestedTypedField(rawObj, &volumes, volumesPath)
+		if err != nil {
+			return err
+		}
+		newVolumes := overrideNamedItems(volumes, volumesOverriders[index].Operator, volumesOverriders[index].Value,
+			func(volume corev1.Volume) string { return volume.Name })
+		patch := setFieldOption(exists, volumesPath, newVolumes)
+
+		klog.V(4).Infof("Parsed JSON patches by VolumesOverrider(%+v): %+v", volumesOverriders[index], patch)
+		if err = applyJSONPatch(rawObj, []overrideOption{patch}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type VolumeMountsOverrider struct{}
+
+func (o *VolumeMountsOverrider) ApplyOverrides(rawObj *unstructured.Unstructured, overriders OverriderInfo) error {
+	volumeMountsOverriders := overriders.Overriders.VolumeMountsOverriders
+	podSpecPath := podSpecPathOf(rawObj.GetKind())
+	if len(volumeMountsOverriders) == 0 || podSpecPath == "" {
+		return nil
+	}
+
+	containersPath := podSpecPath + "/containers"
+	for index := range volumeMountsOverriders {
+		overrider := &volumeMountsOverriders[index]
+		var containers []corev1.Container
+		if _, err := nestedTypedField(rawObj, &containers, containersPath); err != nil {
+			return err
+		}
+
+		patches := make([]overrideOption, 0)
+		for i := range containers {
+			if containers[i].Name != overrider.ContainerName {
+				continue
+			}
+			newVolumeMounts := overrideNamedItems(containers[i].VolumeMounts, overrider.Operator, overrider.Value,
+				func(volumeMount corev1.VolumeMount) string { return volumeMount.MountPath })
+			volumeMountsPath := fmt.Sprintf("%s/%d/volumeMounts", containersPath, i)
+			patches = append(patches, setFieldOption(containers[i].VolumeMounts != nil, volumeMountsPath, newVolumeMounts))
+		}
+
+		klog.V(4).Infof("Parsed JSON patches by VolumeMountsOverrider(%+v): %+v", *overrider, patches)
+		if err := applyJSONPatch(rawObj, patches); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// overrideNamedItems overrides the items which are identified by the key, the items
+// with the same key are replaced in place and the new ones are appended.
+func overrideNamedItems[T any](cur []T, operator v1alpha1.OverriderOperator, value []T, keyFunc func(T) string) []T {
+	values := make(map[string]T, len(value))
+	for _, item := range value {
+		values[keyFunc(item)] = item
+	}
+
+	newItems := make([]T, 0, len(cur)+len(value))
+	switch operator {
+	case v1alpha1.OverriderOpAdd, v1alpha1.OverriderOpReplace:
+		for _, item := range cur {
+			if override, ok := values[keyFunc(item)]; ok {
+				item = override
+				delete(values, keyFunc(item))
+			}
+			newItems = append(newItems, item)
+		}
+		for _, item := range value {
+			if _, ok := values[keyFunc(item)]; ok {
+				newItems = append(newItems, values[keyFunc(item)])
+				delete(values, keyFunc(item))
+			}
+		}
+	case v1alpha1.OverriderOpRemove:
+		for _, item := range cur {
+			if _, ok := values[keyFunc(item)]; !ok {
+				newItems = append(newItems, item)
+			}
+		}
+	default:
+		klog.V(4).Infof("[overrideNamedItems], op: %s , op not supported, ignored.", operator)
+		newItems = append(newItems, cur...)
+	}
+	return newItems
+}
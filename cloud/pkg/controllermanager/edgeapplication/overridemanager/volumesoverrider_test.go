@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overridemanager
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	"github.com/kubeedge/api/apis/apps/v1alpha1"
+)
+
+func TestPodSpecOverriders(t *testing.T) {
+	hostPath := func(name, path string) corev1.Volume {
+		return corev1.Volume{
+			Name:         name,
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: path}},
+		}
+	}
+	affinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "serial", Operator: corev1.NodeSelectorOpExists},
+					},
+				}},
+			},
+		},
+	}
+	overriders := &v1alpha1.Overriders{
+		LabelsOverriders: []v1alpha1.LabelAnnotationOverrider{
+			{Operator: v1alpha1.OverriderOpAdd, Value: map[string]string{"site": "a", "tmp": "x"}},
+			{Operator: v1alpha1.OverriderOpRemove, Value: map[string]string{"tmp": ""}},
+		},
+		TolerationsOverriders: []v1alpha1.TolerationsOverrider{
+			{Operator: v1alpha1.OverriderOpAdd, Value: []corev1.Toleration{{Key: "edge", Operator: corev1.TolerationOpExists}}},
+		},
+		VolumesOverriders: []v1alpha1.VolumesOverrider{
+			{Operator: v1alpha1.OverriderOpAdd, Value: []corev1.Volume{hostPath("serial", "/dev/ttyUSB0"), hostPath("data", "/data")}},
+			{Operator: v1alpha1.OverriderOpReplace, Value: []corev1.Volume{hostPath("serial", "/dev/ttyS0")}},
+			{Operator: v1alpha1.OverriderOpRemove, Value: []corev1.Volume{{Name: "data"}}},
+		},
+		VolumeMountsOverriders: []v1alpha1.VolumeMountsOverrider{
+			{ContainerName: "nginx", Operator: v1alpha1.OverriderOpAdd, Value: []corev1.VolumeMount{{Name: "serial", MountPath: "/dev/serial"}}},
+			{ContainerName: "unknown", Operator: v1alpha1.OverriderOpAdd, Value: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}}},
+		},
+		AffinityOverrider: &v1alpha1.AffinityOverrider{Operator: v1alpha1.OverriderOpReplace, Value: affinity},
+	}
+
+	obj := newTestDeployment()
+	manager := &OverrideManager{
+		Overriders: []Overrider{
+			&LabelsAnnotationsOverrider{},
+			&TolerationsOverrider{},
+			&VolumesOverrider{},
+			&VolumeMountsOverrider{},
+			&AffinityOverrider{},
+		},
+	}
+	if err := manager.ApplyOverrides(obj, OverriderInfo{TargetNodeGroup: "ng1", Overriders: overriders}); err != nil {
+		t.Fatal(err)
+	}
+
+	deployment, err := ConvertToDeployment(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equality.Semantic.DeepEqual(deployment.Labels, map[string]string{"site": "a"}) {
+		t.Errorf("unexpected labels %v", deployment.Labels)
+	}
+	podSpec := deployment.Spec.Template.Spec
+	if !equality.Semantic.DeepEqual(podSpec.Tolerations, overriders.TolerationsOverriders[0].Value) {
+		t.Errorf("unexpected tolerations %v", podSpec.Tolerations)
+	}
+	if expected := []corev1.Volume{hostPath("serial", "/dev/ttyS0")}; !equality.Semantic.DeepEqual(podSpec.Volumes, expected) {
+		t.Errorf("expected volumes %v, got %v", expected, podSpec.Volumes)
+	}
+	if !equality.Semantic.DeepEqual(podSpec.Containers[0].VolumeMounts, overriders.VolumeMountsOverriders[0].Value) {
+		t.Errorf("unexpected volumeMounts %v", podSpec.Containers[0].VolumeMounts)
+	}
+	if !equality.Semantic.DeepEqual(podSpec.Affinity, affinity) {
+		t.Errorf("unexpected affinity %v", podSpec.Affinity)
+	}
+
+	// remove the affinity
+	overriders = &v1alpha1.Overriders{
+		AffinityOverrider: &v1alpha1.AffinityOverrider{Operator: v1alpha1.OverriderOpRemove},
+	}
+	if err := manager.ApplyOverrides(obj, OverriderInfo{TargetNodeGroup: "ng1", Overriders: overriders}); err != nil {
+		t.Fatal(err)
+	}
+	if deployment, err = ConvertToDeployment(obj); err != nil || deployment.Spec.Template.Spec.Affinity != nil {
+		t.Errorf("expected affinity removed, got %v, %v", deployment.Spec.Template.Spec.Affinity, err)
+	}
+}
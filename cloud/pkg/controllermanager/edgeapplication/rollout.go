@@ -0,0 +1,436 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edgeapplication
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/kubeedge/api/apis/apps/v1alpha1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/edgeapplication/constants"
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/edgeapplication/overridemanager"
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/edgeapplication/statusmanager"
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/edgeapplication/utils"
+)
+
+const (
+	defaultRevisionHistoryLimit = 10
+	defaultMaxUnavailableGroups = 1
+	// rolloutRequeueInterval is the interval to check the progress of the rolling update
+	// in case that the status changes of the workloads are missed.
+	rolloutRequeueInterval = 10 * time.Second
+)
+
+// newRevision returns the ControllerRevision which records the templates and the target node groups
+// in the spec of the edgeApp. The name of the revision is the hash of the recorded spec and the collision
+// count of the edgeApp, so the same spec is always recorded in the same revision until a hash collision.
+func newRevision(edgeApp *appsv1alpha1.EdgeApplication, revision int64) (*appsv1.ControllerRevision, error) {
+	data, err := json.Marshal(appsv1alpha1.EdgeApplicationSpec{
+		WorkloadTemplate: edgeApp.Spec.WorkloadTemplate,
+		WorkloadScope:    edgeApp.Spec.WorkloadScope,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal spec of edgeapp %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
+	}
+	hasher := fnv.New32a()
+	hasher.Write(data)
+	// add the collision count in the hash if it exists, like what the Deployment and DaemonSet do
+	if edgeApp.Status.CollisionCount != nil {
+		collisionCountBytes := make([]byte, 8)
+		binary.LittleEndian.PutUint32(collisionCountBytes, uint32(*edgeApp.Status.CollisionCount))
+		hasher.Write(collisionCountBytes)
+	}
+	hash := rand.SafeEncodeString(strconv.FormatUint(uint64(hasher.Sum32()), 10))
+
+	return &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: edgeApp.Namespace,
+			Name:      fmt.Sprintf("%s-%s", edgeApp.Name, hash),
+			Labels:    map[string]string{constants.EdgeApplicationNameLabelKey: edgeApp.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(edgeApp, appsv1alpha1.SchemeGroupVersion.WithKind("EdgeApplication")),
+			},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: revision,
+	}, nil
+}
+
+func specOfRevision(revision *appsv1.ControllerRevision) (*appsv1alpha1.EdgeApplicationSpec, error) {
+	spec := &appsv1alpha1.EdgeApplicationSpec{}
+	if err := json.Unmarshal(revision.Data.Raw, spec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal spec in revision %s/%s, %v", revision.Namespace, revision.Name, err)
+	}
+	return spec, nil
+}
+
+// listRevisions returns the revisions of the edgeApp sorted by the revision number.
+func (c *Controller) listRevisions(ctx context.Context, edgeApp *appsv1alpha1.EdgeApplication) ([]*appsv1.ControllerRevision, error) {
+	list := &appsv1.ControllerRevisionList{}
+	if err := c.Client.List(ctx, list, client.InNamespace(edgeApp.Namespace),
+		client.MatchingLabels{constants.EdgeApplicationNameLabelKey: edgeApp.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list revisions of edgeapp %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
+	}
+	revisions := make([]*appsv1.ControllerRevision, 0, len(list.Items))
+	for i := range list.Items {
+		if metav1.IsControlledBy(&list.Items[i], edgeApp) {
+			revisions = append(revisions, &list.Items[i])
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+	return revisions, nil
+}
+
+// syncRevision returns the revision of the current spec. It creates a new revision if the spec has
+// changed, or bumps the revision number of the existing one if the spec is changed back to it.
+// If the name of the new revision is taken by a revision with different data, the collision count
+// in the status of the edgeApp is increased and an error is returned to compute the name again.
+func (c *Controller) syncRevision(ctx context.Context, edgeApp *appsv1alpha1.EdgeApplication, revisions []*appsv1.ControllerRevision) (*appsv1.ControllerRevision, error) {
+	next := int64(1)
+	if len(revisions) != 0 {
+		next = revisions[len(revisions)-1].Revision + 1
+	}
+	updateRevision, err := newRevision(edgeApp, next)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, revision := range revisions {
+		if revision.Name != updateRevision.Name {
+			continue
+		}
+		if !bytes.Equal(revision.Data.Raw, updateRevision.Data.Raw) {
+			return nil, c.increaseCollisionCount(ctx, edgeApp, revision)
+		}
+		return c.bumpRevision(ctx, revision, next)
+	}
+
+	err = c.Client.Create(ctx, updateRevision)
+	if apierrors.IsAlreadyExists(err) {
+		// the revision may be created by a previous reconciliation which is not observed yet
+		existing := &appsv1.ControllerRevision{}
+		if err := c.Client.Get(ctx, client.ObjectKeyFromObject(updateRevision), existing); err != nil {
+			return nil, fmt.Errorf("failed to get revision %s/%s, %v", updateRevision.Namespace, updateRevision.Name, err)
+		}
+		if metav1.IsControlledBy(existing, edgeApp) && bytes.Equal(existing.Data.Raw, updateRevision.Data.Raw) {
+			return c.bumpRevision(ctx, existing, next)
+		}
+		return nil, c.increaseCollisionCount(ctx, edgeApp, existing)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create revision %s/%s, %v", updateRevision.Namespace, updateRevision.Name, err)
+	}
+	klog.Infof("created revision %s/%s of edgeapp %s/%s, revision number: %d",
+		updateRevision.Namespace, updateRevision.Name, edgeApp.Namespace, edgeApp.Name, updateRevision.Revision)
+	return updateRevision, nil
+}
+
+// bumpRevision makes the revision the latest one with the revision number next.
+func (c *Controller) bumpRevision(ctx context.Context, revision *appsv1.ControllerRevision, next int64) (*appsv1.ControllerRevision, error) {
+	if revision.Revision >= next-1 {
+		// it is already the latest revision
+		return revision, nil
+	}
+	revision = revision.DeepCopy()
+	revision.Revision = next
+	if err := c.Client.Update(ctx, revision); err != nil {
+		return nil, fmt.Errorf("failed to update revision %s/%s, %v", revision.Namespace, revision.Name, err)
+	}
+	return revision, nil
+}
+
+// increaseCollisionCount increases the collision count in the status of the edgeApp, so a different
+// name will be computed for the revision in the next reconciliation.
+func (c *Controller) increaseCollisionCount(ctx context.Context, edgeApp *appsv1alpha1.EdgeApplication, existing *appsv1.ControllerRevision) error {
+	newEdgeApp := edgeApp.DeepCopy()
+	if newEdgeApp.Status.CollisionCount == nil {
+		newEdgeApp.Status.CollisionCount = new(int32)
+	}
+	*newEdgeApp.Status.CollisionCount++
+	if err := c.Client.Status().Patch(ctx, newEdgeApp, client.MergeFrom(edgeApp)); err != nil {
+		return fmt.Errorf("failed to update collision count of edgeapp %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
+	}
+	return fmt.Errorf("found a hash collision with revision %s/%s for edgeapp %s/%s, collision count: %d",
+		existing.Namespace, existing.Name, edgeApp.Namespace, edgeApp.Name, *newEdgeApp.Status.CollisionCount)
+}
+
+// rollback restores the templates and the target node groups in the spec from the revision specified by
+// RollbackTo, and clears RollbackTo. The restored spec will be rolled out to the node groups according to
+// the rollout strategy, like any other changes.
+func (c *Controller) rollback(ctx context.Context, edgeApp *appsv1alpha1.EdgeApplication,
+	revisions []*appsv1.ControllerRevision, updateRevision *appsv1.ControllerRevision) error {
+	var toRevision *appsv1.ControllerRevision
+	for _, revision := range revisions {
+		if revision.Name == updateRevision.Name {
+			continue
+		}
+		if edgeApp.Spec.RollbackTo.Revision == 0 || revision.Revision == edgeApp.Spec.RollbackTo.Revision {
+			// the revisions are sorted, so the last one is the revision before the current one
+			toRevision = revision
+		}
+	}
+
+	newEdgeApp := edgeApp.DeepCopy()
+	newEdgeApp.Spec.RollbackTo = nil
+	if toRevision == nil {
+		klog.Errorf("cannot find revision %d of edgeapp %s/%s to roll back to, skip rollback",
+			edgeApp.Spec.RollbackTo.Revision, edgeApp.Namespace, edgeApp.Name)
+	} else {
+		spec, err := specOfRevision(toRevision)
+		if err != nil {
+			return err
+		}
+		newEdgeApp.Spec.WorkloadTemplate = spec.WorkloadTemplate
+		newEdgeApp.Spec.WorkloadScope = spec.WorkloadScope
+		klog.Infof("roll back edgeapp %s/%s to revision %d", edgeApp.Namespace, edgeApp.Name, toRevision.Revision)
+	}
+	if err := c.Client.Update(ctx, newEdgeApp); err != nil {
+		return fmt.Errorf("failed to roll back edgeapp %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
+	}
+	return nil
+}
+
+// truncateHistory deletes the oldest revisions beyond the RevisionHistoryLimit. The revisions
+// that are still applied to some node groups are kept.
+func (c *Controller) truncateHistory(ctx context.Context, edgeApp *appsv1alpha1.EdgeApplication,
+	revisions []*appsv1.ControllerRevision, inUse map[string]struct{}) error {
+	limit := defaultRevisionHistoryLimit
+	if strategy := edgeApp.Spec.RolloutStrategy; strategy != nil && strategy.RevisionHistoryLimit != nil {
+		limit = int(*strategy.RevisionHistoryLimit)
+	}
+
+	history := make([]*appsv1.ControllerRevision, 0, len(revisions))
+	for _, revision := range revisions {
+		if _, ok := inUse[revision.Name]; !ok {
+			history = append(history, revision)
+		}
+	}
+	for i := 0; i < len(history)-limit; i++ {
+		if err := c.Client.Delete(ctx, history[i]); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete revision %s/%s, %v", history[i].Namespace, history[i].Name, err)
+		}
+		klog.V(4).Infof("deleted revision %s/%s of edgeapp %s/%s", history[i].Namespace, history[i].Name, edgeApp.Namespace, edgeApp.Name)
+	}
+	return nil
+}
+
+// planRollout returns the revision to apply to each target node group, and whether all the node groups
+// have been updated to the update revision. The node groups that have never been deployed are deployed
+// with the update revision directly. The others are updated according to the rollout strategy, available
+// reports whether the workloads of the node group generated from the update revision are available.
+func planRollout(edgeApp *appsv1alpha1.EdgeApplication, updateRevision string, available func(nodeGroup string) bool) (map[string]string, bool) {
+	current := make(map[string]string, len(edgeApp.Status.NodeGroupStatus))
+	for _, status := range edgeApp.Status.NodeGroupStatus {
+		current[status.NodeGroup] = status.Revision
+	}
+	strategy := edgeApp.Spec.RolloutStrategy
+	rolling := strategy != nil && strategy.Type == appsv1alpha1.RollingUpdateRolloutStrategyType
+	paused := strategy != nil && strategy.Paused
+	maxUnavailable := defaultMaxUnavailableGroups
+	if rolling && strategy.RollingUpdate != nil && strategy.RollingUpdate.MaxUnavailableGroups != nil {
+		maxUnavailable = int(*strategy.RollingUpdate.MaxUnavailableGroups)
+	}
+
+	targetNodeGroups := edgeApp.Spec.WorkloadScope.TargetNodeGroups
+	completed := true
+	// the node groups that are being updated in the current wave
+	unavailable := 0
+	if rolling {
+		for _, nodeGroup := range targetNodeGroups {
+			if current[nodeGroup.Name] == updateRevision && !available(nodeGroup.Name) {
+				unavailable++
+				completed = false
+			}
+		}
+	}
+
+	revisions := make(map[string]string, len(targetNodeGroups))
+	for _, nodeGroup := range targetNodeGroups {
+		revision := current[nodeGroup.Name]
+		switch {
+		case revision == "" || revision == updateRevision:
+			revisions[nodeGroup.Name] = updateRevision
+		case paused:
+			revisions[nodeGroup.Name] = revision
+			completed = false
+		case !rolling:
+			revisions[nodeGroup.Name] = updateRevision
+		case unavailable < maxUnavailable:
+			// start to update the node group in the current wave
+			revisions[nodeGroup.Name] = updateRevision
+			unavailable++
+			completed = false
+		default:
+			revisions[nodeGroup.Name] = revision
+			completed = false
+		}
+	}
+	return revisions, completed
+}
+
+// rollout returns the overridden templates of all the target node groups, the templates of each node group
+// are generated from the revision planned for it. It also returns the revision of each node group and
+// whether the rollout is completed.
+func (c *Controller) rollout(ctx context.Context, edgeApp *appsv1alpha1.EdgeApplication, tmplInfos []*utils.TemplateInfo,
+	revisions []*appsv1.ControllerRevision, updateRevision string) ([]*utils.TemplateInfo, map[string]string, bool, []error) {
+	errs := []error{}
+	overriderInfos := utils.GetAllOverriders(edgeApp)
+	updateTmplInfos := make(map[string][]*utils.TemplateInfo, len(overriderInfos))
+	for _, info := range overriderInfos {
+		infos, overrideErrs := c.overrideTemplates(edgeApp, tmplInfos, info, updateRevision)
+		updateTmplInfos[info.TargetNodeGroup] = infos
+		errs = append(errs, overrideErrs...)
+	}
+
+	nodeGroupRevisions, completed := planRollout(edgeApp, updateRevision, func(nodeGroup string) bool {
+		return c.nodeGroupAvailable(ctx, edgeApp, updateTmplInfos[nodeGroup], updateRevision)
+	})
+
+	modifiedTmplInfos := []*utils.TemplateInfo{}
+	for _, info := range overriderInfos {
+		revision := nodeGroupRevisions[info.TargetNodeGroup]
+		if revision == updateRevision {
+			modifiedTmplInfos = append(modifiedTmplInfos, updateTmplInfos[info.TargetNodeGroup]...)
+			continue
+		}
+		infos, err := c.overrideTemplatesOfRevision(edgeApp, revisions, revision, info.TargetNodeGroup)
+		if err != nil {
+			klog.Errorf("failed to get templates of revision %s for nodegroup %s of edgeapp %s/%s, use the update revision instead, %v",
+				revision, info.TargetNodeGroup, edgeApp.Namespace, edgeApp.Name, err)
+			nodeGroupRevisions[info.TargetNodeGroup] = updateRevision
+			modifiedTmplInfos = append(modifiedTmplInfos, updateTmplInfos[info.TargetNodeGroup]...)
+			continue
+		}
+		modifiedTmplInfos = append(modifiedTmplInfos, infos...)
+	}
+	return modifiedTmplInfos, nodeGroupRevisions, completed, errs
+}
+
+// overrideTemplates applies the overriders of the node group to the templates that need override.
+//
+// TODO: consider the situation that not all the overrides have been applied successfully
+// If one succeeded and another failed, the status of edgeApp will only contain the successful
+// one, and have no status about the failed one.
+func (c *Controller) overrideTemplates(edgeApp *appsv1alpha1.EdgeApplication, tmplInfos []*utils.TemplateInfo,
+	info overridemanager.OverriderInfo, revision string) ([]*utils.TemplateInfo, []error) {
+	errs := []error{}
+	modifiedTmplInfos := []*utils.TemplateInfo{}
+	for _, tmplInfo := range tmplInfos {
+		if !needOverride(tmplInfo.Template) {
+			continue
+		}
+		tmplCopy := tmplInfo.Template.DeepCopy()
+		setOwnerReference(tmplCopy, edgeApp)
+		klog.V(4).Infof("override obj %s/%s of gvk %s, for nodegroup %s", tmplCopy.GetNamespace(), tmplCopy.GetName(), tmplCopy.GroupVersionKind(), info.TargetNodeGroup)
+		if err := c.Overrider.ApplyOverrides(tmplCopy, info); err != nil {
+			klog.Errorf("failed to apply override of nodegroup %s to obj %s/%s of gvk %s, %v",
+				info.TargetNodeGroup, tmplCopy.GetNamespace(), tmplCopy.GetName(), tmplCopy.GroupVersionKind(), err)
+			errs = append(errs, err)
+			continue
+		}
+		annotations := tmplCopy.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[constants.RevisionAnnotationKey] = revision
+		tmplCopy.SetAnnotations(annotations)
+		modifiedTmplInfos = append(modifiedTmplInfos, &utils.TemplateInfo{Ordinal: tmplInfo.Ordinal, Template: tmplCopy})
+	}
+	return modifiedTmplInfos, errs
+}
+
+// overrideTemplatesOfRevision returns the overridden templates of the node group generated from the spec
+// recorded in the revision.
+func (c *Controller) overrideTemplatesOfRevision(edgeApp *appsv1alpha1.EdgeApplication, revisions []*appsv1.ControllerRevision,
+	revisionName, nodeGroup string) ([]*utils.TemplateInfo, error) {
+	var revision *appsv1.ControllerRevision
+	for i := range revisions {
+		if revisions[i].Name == revisionName {
+			revision = revisions[i]
+			break
+		}
+	}
+	if revision == nil {
+		return nil, fmt.Errorf("revision %s not found", revisionName)
+	}
+	spec, err := specOfRevision(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	oldEdgeApp := edgeApp.DeepCopy()
+	oldEdgeApp.Spec = *spec
+	for _, info := range utils.GetAllOverriders(oldEdgeApp) {
+		if info.TargetNodeGroup != nodeGroup {
+			continue
+		}
+		tmplInfos, err := utils.GetTemplatesInfosOfEdgeApp(oldEdgeApp, c.Serializer)
+		if err != nil {
+			return nil, err
+		}
+		modifiedTmplInfos, errs := c.overrideTemplates(edgeApp, tmplInfos, info, revisionName)
+		if len(errs) != 0 {
+			return nil, errs[0]
+		}
+		return modifiedTmplInfos, nil
+	}
+	return nil, fmt.Errorf("nodegroup %s not found in revision %s", nodeGroup, revisionName)
+}
+
+// nodeGroupAvailable checks whether all the workloads of the node group have been updated to the
+// revision and are available.
+func (c *Controller) nodeGroupAvailable(ctx context.Context, edgeApp *appsv1alpha1.EdgeApplication,
+	tmplInfos []*utils.TemplateInfo, revision string) bool {
+	for _, tmplInfo := range tmplInfos {
+		exists, curObj, err := c.ifObjExists(ctx, tmplInfo.Template)
+		if err != nil {
+			klog.Errorf("failed to check the existence of obj %s/%s, %v", tmplInfo.Template.GetNamespace(), tmplInfo.Template.GetName(), err)
+			return false
+		}
+		if !exists || curObj.GetAnnotations()[constants.RevisionAnnotationKey] != revision {
+			return false
+		}
+		available, err := statusmanager.IsAvailable(ctx, c.Client, edgeApp, utils.GetResourceInfoOfTemplateInfo(tmplInfo))
+		if err != nil {
+			klog.Errorf("failed to check the availability of obj %s/%s, %v", tmplInfo.Template.GetNamespace(), tmplInfo.Template.GetName(), err)
+			return false
+		}
+		if !available {
+			return false
+		}
+	}
+	return true
+}
+
+func isRolloutPaused(edgeApp *appsv1alpha1.EdgeApplication) bool {
+	return edgeApp.Spec.RolloutStrategy != nil && edgeApp.Spec.RolloutStrategy.Paused
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edgeapplication
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/kubeedge/api/apis/apps/v1alpha1"
+)
+
+func newRolloutEdgeApp(strategy *appsv1alpha1.RolloutStrategy, revisions map[string]string) *appsv1alpha1.EdgeApplication {
+	edgeApp := &appsv1alpha1.EdgeApplication{
+		Spec: appsv1alpha1.EdgeApplicationSpec{
+			RolloutStrategy: strategy,
+		},
+	}
+	for _, name := range []string{"ng1", "ng2", "ng3", "ng4"} {
+		edgeApp.Spec.WorkloadScope.TargetNodeGroups = append(edgeApp.Spec.WorkloadScope.TargetNodeGroups,
+			appsv1alpha1.TargetNodeGroup{Name: name})
+		if revision, ok := revisions[name]; ok {
+			edgeApp.Status.NodeGroupStatus = append(edgeApp.Status.NodeGroupStatus,
+				appsv1alpha1.NodeGroupWorkloadStatus{NodeGroup: name, Revision: revision})
+		}
+	}
+	return edgeApp
+}
+
+func TestPlanRollout(t *testing.T) {
+	rolling := func(maxUnavailable int32, paused bool) *appsv1alpha1.RolloutStrategy {
+		return &appsv1alpha1.RolloutStrategy{
+			Type:          appsv1alpha1.RollingUpdateRolloutStrategyType,
+			RollingUpdate: &appsv1alpha1.RollingUpdateNodeGroups{MaxUnavailableGroups: pointer.Int32(maxUnavailable)},
+			Paused:        paused,
+		}
+	}
+	allOld := map[string]string{"ng1": "r1", "ng2": "r1", "ng3": "r1", "ng4": "r1"}
+
+	cases := []struct {
+		name              string
+		strategy          *appsv1alpha1.RolloutStrategy
+		current           map[string]string
+		available         map[string]bool
+		expected          map[string]string
+		expectedCompleted bool
+	}{
+		{
+			name:              "all at once by default",
+			current:           allOld,
+			expected:          map[string]string{"ng1": "r2", "ng2": "r2", "ng3": "r2", "ng4": "r2"},
+			expectedCompleted: true,
+		},
+		{
+			name:              "new nodegroups are deployed directly",
+			strategy:          rolling(1, true),
+			current:           map[string]string{"ng1": "r1"},
+			expected:          map[string]string{"ng1": "r1", "ng2": "r2", "ng3": "r2", "ng4": "r2"},
+			expectedCompleted: false,
+		},
+		{
+			name:     "the first wave starts",
+			strategy: rolling(2, false),
+			current:  allOld,
+			expected: map[string]string{"ng1": "r2", "ng2": "r2", "ng3": "r1", "ng4": "r1"},
+		},
+		{
+			name:      "wait for the nodegroups in the current wave",
+			strategy:  rolling(2, false),
+			current:   map[string]string{"ng1": "r2", "ng2": "r2", "ng3": "r1", "ng4": "r1"},
+			available: map[string]bool{"ng1": true},
+			expected:  map[string]string{"ng1": "r2", "ng2": "r2", "ng3": "r2", "ng4": "r1"},
+		},
+		{
+			name:      "the next wave starts after the current wave is available",
+			strategy:  rolling(2, false),
+			current:   map[string]string{"ng1": "r2", "ng2": "r2", "ng3": "r1", "ng4": "r1"},
+			available: map[string]bool{"ng1": true, "ng2": true},
+			expected:  map[string]string{"ng1": "r2", "ng2": "r2", "ng3": "r2", "ng4": "r2"},
+		},
+		{
+			name:      "paused",
+			strategy:  rolling(1, true),
+			current:   map[string]string{"ng1": "r2", "ng2": "r1", "ng3": "r1", "ng4": "r1"},
+			available: map[string]bool{"ng1": true},
+			expected:  map[string]string{"ng1": "r2", "ng2": "r1", "ng3": "r1", "ng4": "r1"},
+		},
+		{
+			name:              "completed",
+			strategy:          rolling(1, false),
+			current:           map[string]string{"ng1": "r2", "ng2": "r2", "ng3": "r2", "ng4": "r2"},
+			available:         map[string]bool{"ng1": true, "ng2": true, "ng3": true, "ng4": true},
+			expected:          map[string]string{"ng1": "r2", "ng2": "r2", "ng3": "r2", "ng4": "r2"},
+			expectedCompleted: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			edgeApp := newRolloutEdgeApp(c.strategy, c.current)
+			revisions, completed := planRollout(edgeApp, "r2", func(nodeGroup string) bool { return c.available[nodeGroup] })
+			if !reflect.DeepEqual(revisions, c.expected) {
+				t.Errorf("expected revisions %v, got %v", c.expected, revisions)
+			}
+			if completed != c.expectedCompleted {
+				t.Errorf("expected completed %v, got %v", c.expectedCompleted, completed)
+			}
+		})
+	}
+}
+
+func TestRevisionHistory(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := appsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	edgeApp := &appsv1alpha1.EdgeApplication{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app", UID: "uid"},
+		Spec: appsv1alpha1.EdgeApplicationSpec{
+			WorkloadScope: appsv1alpha1.WorkloadScope{
+				TargetNodeGroups: []appsv1alpha1.TargetNodeGroup{{Name: "ng1"}},
+			},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(edgeApp).Build()
+	c := &Controller{Client: cli}
+	ctx := context.Background()
+
+	sync := func() string {
+		revisions, err := c.listRevisions(ctx, edgeApp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		revision, err := c.syncRevision(ctx, edgeApp, revisions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return revision.Name
+	}
+	first := sync()
+	if again := sync(); again != first {
+		t.Errorf("expected the same spec recorded in the same revision, got %s and %s", first, again)
+	}
+	edgeApp.Spec.WorkloadScope.TargetNodeGroups = append(edgeApp.Spec.WorkloadScope.TargetNodeGroups, appsv1alpha1.TargetNodeGroup{Name: "ng2"})
+	second := sync()
+	if second == first {
+		t.Fatal("expected a new revision for the changed spec")
+	}
+
+	// roll back to the last revision
+	current := &appsv1alpha1.EdgeApplication{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(edgeApp), current); err != nil {
+		t.Fatal(err)
+	}
+	current.Spec = *edgeApp.Spec.DeepCopy()
+	current.Spec.RollbackTo = &appsv1alpha1.RollbackConfig{}
+	if err := cli.Update(ctx, current); err != nil {
+		t.Fatal(err)
+	}
+	revisions, err := c.listRevisions(ctx, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updateRevision, err := c.syncRevision(ctx, current, revisions)
+	if err != nil || updateRevision.Name != second {
+		t.Fatalf("unexpected revision %v: %v", updateRevision, err)
+	}
+	if err := c.rollback(ctx, current, revisions, updateRevision); err != nil {
+		t.Fatal(err)
+	}
+	rolledBack := &appsv1alpha1.EdgeApplication{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(edgeApp), rolledBack); err != nil {
+		t.Fatal(err)
+	}
+	if rolledBack.Spec.RollbackTo != nil || len(rolledBack.Spec.WorkloadScope.TargetNodeGroups) != 1 {
+		t.Errorf("unexpected spec after rollback %+v", rolledBack.Spec)
+	}
+
+	// the rolled back spec reuses its revision with a new revision number
+	edgeApp = rolledBack
+	if name := sync(); name != first {
+		t.Errorf("expected the revision %s to be reused, got %s", first, name)
+	}
+	revisions, err = c.listRevisions(ctx, edgeApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 2 || revisions[1].Name != first || revisions[1].Revision != 3 {
+		t.Errorf("unexpected revisions after rollback %v", revisions)
+	}
+
+	// the revisions in use are never deleted
+	edgeApp.Spec.RolloutStrategy = &appsv1alpha1.RolloutStrategy{RevisionHistoryLimit: pointer.Int32(0)}
+	if err := c.truncateHistory(ctx, edgeApp, revisions, map[string]struct{}{first: {}}); err != nil {
+		t.Fatal(err)
+	}
+	if revisions, _ = c.listRevisions(ctx, edgeApp); len(revisions) != 1 || revisions[0].Name != first {
+		t.Errorf("unexpected revisions after truncating history %v", revisions)
+	}
+}
+
+func TestRevisionCollision(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := appsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	edgeApp := &appsv1alpha1.EdgeApplication{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app", UID: "uid"},
+		Spec: appsv1alpha1.EdgeApplicationSpec{
+			WorkloadScope: appsv1alpha1.WorkloadScope{
+				TargetNodeGroups: []appsv1alpha1.TargetNodeGroup{{Name: "ng1"}},
+			},
+		},
+	}
+	// the revision created by a previous reconciliation which is not observed yet
+	observed, err := newRevision(edgeApp, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(edgeApp, observed).
+		WithStatusSubresource(edgeApp).Build()
+	c := &Controller{Client: cli}
+	ctx := context.Background()
+
+	revision, err := c.syncRevision(ctx, edgeApp, nil)
+	if err != nil || revision.Name != observed.Name {
+		t.Fatalf("expected the existing revision with the same data to be used, got %v: %v", revision, err)
+	}
+
+	// the name of the revision for the changed spec is taken by a revision with different data
+	edgeApp.Spec.WorkloadScope.TargetNodeGroups = append(edgeApp.Spec.WorkloadScope.TargetNodeGroups, appsv1alpha1.TargetNodeGroup{Name: "ng2"})
+	colliding, err := newRevision(edgeApp, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	colliding.Data.Raw = observed.Data.Raw
+	if err := cli.Delete(ctx, observed); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.Create(ctx, colliding); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.syncRevision(ctx, edgeApp, nil); err == nil {
+		t.Fatal("expected an error for the hash collision")
+	}
+	current := &appsv1alpha1.EdgeApplication{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(edgeApp), current); err != nil {
+		t.Fatal(err)
+	}
+	if current.Status.CollisionCount == nil || *current.Status.CollisionCount != 1 {
+		t.Fatalf("expected collision count 1, got %v", current.Status.CollisionCount)
+	}
+	current.Spec = *edgeApp.Spec.DeepCopy()
+	revision, err = c.syncRevision(ctx, current, nil)
+	if err != nil || revision.Name == colliding.Name {
+		t.Errorf("expected a new revision name after the collision, got %v: %v", revision, err)
+	}
+}
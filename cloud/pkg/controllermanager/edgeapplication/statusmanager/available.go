@@ -0,0 +1,300 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statusmanager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/kubeedge/api/apis/apps/v1alpha1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/edgeapplication/constants"
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/edgeapplication/utils"
+)
+
+// available checks whether the obj of the resource info is available, it also returns
+// the replica counts of the obj if the obj is a workload with replicas.
+type available interface {
+	IsAvailable(context.Context, client.Client, utils.ResourceInfo) (bool, *appsv1alpha1.WorkloadReplicas, error)
+}
+
+var _ available = availableIfExists{}
+var _ available = deploymentAvailable{}
+var _ available = statefulSetAvailable{}
+var _ available = daemonSetAvailable{}
+var _ available = jobAvailable{}
+var _ available = serviceAvailable{}
+var _ available = conditionAvailable{}
+
+// availableFor returns the availability evaluator of the gvk. The custom resources are
+// available when their ready condition is true, the condition type can be set by the
+// annotation of the EdgeApplication. The other built-in resources without status, such
+// as ConfigMap, are available once they exist.
+func availableFor(gvk schema.GroupVersionKind, edgeApp *appsv1alpha1.EdgeApplication) available {
+	switch gvk {
+	case constants.DeploymentGVK:
+		return deploymentAvailable{}
+	case constants.StatefulSetGVK:
+		return statefulSetAvailable{}
+	case constants.DaemonSetGVK:
+		return daemonSetAvailable{}
+	case constants.JobGVK:
+		return jobAvailable{}
+	case constants.ServiceGVK:
+		return serviceAvailable{}
+	}
+	if isBuiltinGroup(gvk.Group) {
+		return availableIfExists{}
+	}
+	conditionType := edgeApp.GetAnnotations()[constants.ReadyConditionTypeAnnotationKey]
+	if conditionType == "" {
+		conditionType = constants.DefaultReadyConditionType
+	}
+	return conditionAvailable{conditionType: conditionType}
+}
+
+// IsAvailable checks whether the obj of the resource info in the manifests of the edgeApp is available.
+func IsAvailable(ctx context.Context, client client.Client, edgeApp *appsv1alpha1.EdgeApplication, info utils.ResourceInfo) (bool, error) {
+	gvk := schema.GroupVersionKind{Group: info.Group, Version: info.Version, Kind: info.Kind}
+	isAvailable, _, err := availableFor(gvk, edgeApp).IsAvailable(ctx, client, info)
+	return isAvailable, err
+}
+
+// isBuiltinGroup returns true if the group is the core group, a legacy group without domain
+// such as apps and batch, or a group ends with .k8s.io.
+func isBuiltinGroup(group string) bool {
+	return !strings.Contains(group, ".") || strings.HasSuffix(group, ".k8s.io")
+}
+
+type availableIfExists struct{}
+
+func (e availableIfExists) IsAvailable(ctx context.Context, client client.Client, info utils.ResourceInfo) (bool, *appsv1alpha1.WorkloadReplicas, error) {
+	obj, err := getObjAccordingToResourceInfo(ctx, client, info)
+	if err != nil {
+		return false, nil, err
+	}
+	if obj == nil {
+		return false, nil, nil
+	}
+	return true, nil, nil
+}
+
+// deploymentAvailable checks whether the rollout of the deployment is completed,
+// it follows the same rules as kubectl rollout status.
+type deploymentAvailable struct{}
+
+func (d deploymentAvailable) IsAvailable(ctx context.Context, client client.Client, info utils.ResourceInfo) (bool, *appsv1alpha1.WorkloadReplicas, error) {
+	deploy := &appsv1.Deployment{}
+	exists, err := getTypedObjAccordingToResourceInfo(ctx, client, info, deploy)
+	if err != nil || !exists {
+		return false, nil, err
+	}
+
+	desired := int32(1)
+	if deploy.Spec.Replicas != nil {
+		desired = *deploy.Spec.Replicas
+	}
+	replicas := &appsv1alpha1.WorkloadReplicas{
+		Replicas:          desired,
+		ReadyReplicas:     deploy.Status.ReadyReplicas,
+		AvailableReplicas: deploy.Status.AvailableReplicas,
+	}
+	status := deploy.Status
+	isAvailable := status.ObservedGeneration >= deploy.Generation &&
+		status.UpdatedReplicas == desired &&
+		status.Replicas == status.UpdatedReplicas &&
+		status.AvailableReplicas == status.UpdatedReplicas &&
+		status.ReadyReplicas >= desired
+	return isAvailable, replicas, nil
+}
+
+// statefulSetAvailable checks whether all the replicas of the statefulset are ready and
+// updated to the current revision, or the replicas out of the partition are updated.
+type statefulSetAvailable struct{}
+
+func (s statefulSetAvailable) IsAvailable(ctx context.Context, client client.Client, info utils.ResourceInfo) (bool, *appsv1alpha1.WorkloadReplicas, error) {
+	sts := &appsv1.StatefulSet{}
+	exists, err := getTypedObjAccordingToResourceInfo(ctx, client, info, sts)
+	if err != nil || !exists {
+		return false, nil, err
+	}
+
+	desired := int32(1)
+	if sts.Spec.Replicas != nil {
+		desired = *sts.Spec.Replicas
+	}
+	replicas := &appsv1alpha1.WorkloadReplicas{
+		Replicas:          desired,
+		ReadyReplicas:     sts.Status.ReadyReplicas,
+		AvailableReplicas: sts.Status.AvailableReplicas,
+	}
+	status := sts.Status
+	if status.ObservedGeneration < sts.Generation || status.ReadyReplicas < desired {
+		return false, replicas, nil
+	}
+	if sts.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		return true, replicas, nil
+	}
+	if ru := sts.Spec.UpdateStrategy.RollingUpdate; ru != nil && ru.Partition != nil && *ru.Partition > 0 {
+		return status.UpdatedReplicas >= desired-*ru.Partition, replicas, nil
+	}
+	return status.UpdateRevision == status.CurrentRevision, replicas, nil
+}
+
+// daemonSetAvailable checks whether the daemon pods on all the scheduled nodes are updated
+// and available.
+type daemonSetAvailable struct{}
+
+func (d daemonSetAvailable) IsAvailable(ctx context.Context, client client.Client, info utils.ResourceInfo) (bool, *appsv1alpha1.WorkloadReplicas, error) {
+	ds := &appsv1.DaemonSet{}
+	exists, err := getTypedObjAccordingToResourceInfo(ctx, client, info, ds)
+	if err != nil || !exists {
+		return false, nil, err
+	}
+
+	status := ds.Status
+	replicas := &appsv1alpha1.WorkloadReplicas{
+		Replicas:          status.DesiredNumberScheduled,
+		ReadyReplicas:     status.NumberReady,
+		AvailableReplicas: status.NumberAvailable,
+	}
+	if status.ObservedGeneration < ds.Generation || status.NumberAvailable < status.DesiredNumberScheduled {
+		return false, replicas, nil
+	}
+	if ds.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType {
+		return true, replicas, nil
+	}
+	return status.UpdatedNumberScheduled >= status.DesiredNumberScheduled, replicas, nil
+}
+
+// jobAvailable checks whether the job is completed.
+type jobAvailable struct{}
+
+func (j jobAvailable) IsAvailable(ctx context.Context, client client.Client, info utils.ResourceInfo) (bool, *appsv1alpha1.WorkloadReplicas, error) {
+	job := &batchv1.Job{}
+	exists, err := getTypedObjAccordingToResourceInfo(ctx, client, info, job)
+	if err != nil || !exists {
+		return false, nil, err
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobComplete && cond.Status == corev1.ConditionTrue {
+			return true, nil, nil
+		}
+	}
+	return false, nil, nil
+}
+
+// serviceAvailable checks whether the service has ready endpoints. The ExternalName
+// services are available once they exist.
+type serviceAvailable struct{}
+
+func (s serviceAvailable) IsAvailable(ctx context.Context, client client.Client, info utils.ResourceInfo) (bool, *appsv1alpha1.WorkloadReplicas, error) {
+	svc := &corev1.Service{}
+	exists, err := getTypedObjAccordingToResourceInfo(ctx, client, info, svc)
+	if err != nil || !exists {
+		return false, nil, err
+	}
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return true, nil, nil
+	}
+
+	endpointsInfo := info
+	endpointsInfo.Group, endpointsInfo.Version, endpointsInfo.Kind =
+		constants.EndpointsGVK.Group, constants.EndpointsGVK.Version, constants.EndpointsGVK.Kind
+	endpoints := &corev1.Endpoints{}
+	exists, err = getTypedObjAccordingToResourceInfo(ctx, client, endpointsInfo, endpoints)
+	if err != nil || !exists {
+		return false, nil, err
+	}
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return true, nil, nil
+		}
+	}
+	return false, nil, nil
+}
+
+// conditionAvailable checks whether the condition of conditionType in status.conditions
+// of the obj is true. The condition is ignored if it is observed from an earlier generation.
+type conditionAvailable struct {
+	conditionType string
+}
+
+func (c conditionAvailable) IsAvailable(ctx context.Context, client client.Client, info utils.ResourceInfo) (bool, *appsv1alpha1.WorkloadReplicas, error) {
+	obj, err := getObjAccordingToResourceInfo(ctx, client, info)
+	if err != nil || obj == nil {
+		return false, nil, err
+	}
+
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to get conditions of %s/%s, %v", info.Namespace, info.Name, err)
+	}
+	if !found {
+		return false, nil, nil
+	}
+	for _, item := range conditions {
+		cond, ok := item.(map[string]interface{})
+		if !ok || cond["type"] != c.conditionType {
+			continue
+		}
+		if generation, found, _ := unstructured.NestedInt64(cond, "observedGeneration"); found && generation < obj.GetGeneration() {
+			return false, nil, nil
+		}
+		return cond["status"] == string(corev1.ConditionTrue), nil, nil
+	}
+	return false, nil, nil
+}
+
+// getTypedObjAccordingToResourceInfo gets the obj of the resource info and converts it to the typed obj,
+// it returns false if the obj does not exist.
+func getTypedObjAccordingToResourceInfo(ctx context.Context, client client.Client, info utils.ResourceInfo, typed interface{}) (bool, error) {
+	obj, err := getObjAccordingToResourceInfo(ctx, client, info)
+	if err != nil || obj == nil {
+		return false, err
+	}
+	if err := client.Scheme().Convert(obj, typed, nil); err != nil {
+		return false, fmt.Errorf("failed to convert unstructured to %s for %s/%s, %v", info.Kind, info.Namespace, info.Name, err)
+	}
+	return true, nil
+}
+
+func getObjAccordingToResourceInfo(ctx context.Context, client client.Client, info utils.ResourceInfo) (*unstructured.Unstructured, error) {
+	gvk := schema.GroupVersionKind{Group: info.Group, Version: info.Version, Kind: info.Kind}
+	curObj := &unstructured.Unstructured{}
+	curObj.SetGroupVersionKind(gvk)
+	if err := client.Get(ctx, types.NamespacedName{Namespace: info.Namespace, Name: info.Name}, curObj); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).Infof("cannot find obj %s/%s of gvk %s", info.Namespace, info.Name, gvk)
+			return nil, nil
+		}
+		klog.Errorf("failed to get obj %s/%s, gvk: %s, %v", info.Namespace, info.Name, gvk, err)
+		return nil, err
+	}
+	return curObj, nil
+}
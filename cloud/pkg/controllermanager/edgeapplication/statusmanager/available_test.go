@@ -0,0 +1,206 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statusmanager
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/kubeedge/api/apis/apps/v1alpha1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/edgeapplication/constants"
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/edgeapplication/utils"
+)
+
+var widgetGVK = schema.GroupVersionKind{Group: "example.io", Version: "v1", Kind: "Widget"}
+
+func infoOf(gvk schema.GroupVersionKind, name string) utils.ResourceInfo {
+	return utils.ResourceInfo{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Namespace: "default", Name: name}
+}
+
+func newWidget(name string, conditions ...interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(widgetGVK)
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetGeneration(2)
+	if len(conditions) != 0 {
+		_ = unstructured.SetNestedSlice(obj.Object, conditions, "status", "conditions")
+	}
+	return obj
+}
+
+func TestAvailableFor(t *testing.T) {
+	edgeApp := &appsv1alpha1.EdgeApplication{}
+	cases := map[schema.GroupVersionKind]available{
+		constants.DeploymentGVK:            deploymentAvailable{},
+		constants.StatefulSetGVK:           statefulSetAvailable{},
+		constants.DaemonSetGVK:             daemonSetAvailable{},
+		constants.JobGVK:                   jobAvailable{},
+		constants.ServiceGVK:               serviceAvailable{},
+		{Version: "v1", Kind: "ConfigMap"}: availableIfExists{},
+		{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"}: availableIfExists{},
+		widgetGVK: conditionAvailable{conditionType: "Ready"},
+	}
+	for gvk, expected := range cases {
+		if got := availableFor(gvk, edgeApp); got != expected {
+			t.Errorf("unexpected evaluator %#v for %s", got, gvk)
+		}
+	}
+
+	edgeApp.Annotations = map[string]string{constants.ReadyConditionTypeAnnotationKey: "Synced"}
+	if got := availableFor(widgetGVK, edgeApp); got != (conditionAvailable{conditionType: "Synced"}) {
+		t.Errorf("expected the condition type to be set by annotation, got %#v", got)
+	}
+}
+
+func TestIsAvailable(t *testing.T) {
+	objs := []client.Object{
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ready", Generation: 1},
+			Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(2)},
+			Status: appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2,
+				ReadyReplicas: 2, AvailableReplicas: 2},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "rolling", Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(2)},
+			Status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 1,
+				ReadyReplicas: 3, AvailableReplicas: 3},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ready"},
+			Spec:       appsv1.StatefulSetSpec{Replicas: pointer.Int32(3)},
+			Status: appsv1.StatefulSetStatus{ReadyReplicas: 3, AvailableReplicas: 3,
+				CurrentRevision: "r1", UpdateRevision: "r1"},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "updating"},
+			Spec:       appsv1.StatefulSetSpec{Replicas: pointer.Int32(3)},
+			Status: appsv1.StatefulSetStatus{ReadyReplicas: 3, AvailableReplicas: 3,
+				CurrentRevision: "r1", UpdateRevision: "r2"},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "partitioned"},
+			Spec: appsv1.StatefulSetSpec{Replicas: pointer.Int32(3), UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: pointer.Int32(2)},
+			}},
+			Status: appsv1.StatefulSetStatus{ReadyReplicas: 3, UpdatedReplicas: 1,
+				CurrentRevision: "r1", UpdateRevision: "r2"},
+		},
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ready"},
+			Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, NumberReady: 2, NumberAvailable: 2,
+				UpdatedNumberScheduled: 2},
+		},
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "unavailable"},
+			Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, NumberReady: 1, NumberAvailable: 1,
+				UpdatedNumberScheduled: 2},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "complete"},
+			Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+			}},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "failed"},
+			Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+			}},
+		},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ready"}},
+		&corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ready"},
+			Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}},
+		},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "no-endpoints"}},
+		&corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "no-endpoints"},
+			Subsets:    []corev1.EndpointSubset{{NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.2"}}}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "external"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "example.com"},
+		},
+		newWidget("ready", map[string]interface{}{"type": "Ready", "status": "True"}),
+		newWidget("not-ready", map[string]interface{}{"type": "Ready", "status": "False"}),
+		newWidget("stale", map[string]interface{}{"type": "Ready", "status": "True", "observedGeneration": int64(1)}),
+		newWidget("no-condition"),
+	}
+	cli := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(objs...).Build()
+
+	cases := []struct {
+		name      string
+		available available
+		info      utils.ResourceInfo
+		expected  bool
+		replicas  *appsv1alpha1.WorkloadReplicas
+	}{
+		{"deployment ready", deploymentAvailable{}, infoOf(constants.DeploymentGVK, "ready"), true,
+			&appsv1alpha1.WorkloadReplicas{Replicas: 2, ReadyReplicas: 2, AvailableReplicas: 2}},
+		{"deployment rolling", deploymentAvailable{}, infoOf(constants.DeploymentGVK, "rolling"), false,
+			&appsv1alpha1.WorkloadReplicas{Replicas: 2, ReadyReplicas: 3, AvailableReplicas: 3}},
+		{"deployment not found", deploymentAvailable{}, infoOf(constants.DeploymentGVK, "not-found"), false, nil},
+		{"statefulset ready", statefulSetAvailable{}, infoOf(constants.StatefulSetGVK, "ready"), true,
+			&appsv1alpha1.WorkloadReplicas{Replicas: 3, ReadyReplicas: 3, AvailableReplicas: 3}},
+		{"statefulset updating", statefulSetAvailable{}, infoOf(constants.StatefulSetGVK, "updating"), false,
+			&appsv1alpha1.WorkloadReplicas{Replicas: 3, ReadyReplicas: 3, AvailableReplicas: 3}},
+		{"statefulset partitioned", statefulSetAvailable{}, infoOf(constants.StatefulSetGVK, "partitioned"), true,
+			&appsv1alpha1.WorkloadReplicas{Replicas: 3, ReadyReplicas: 3}},
+		{"daemonset ready", daemonSetAvailable{}, infoOf(constants.DaemonSetGVK, "ready"), true,
+			&appsv1alpha1.WorkloadReplicas{Replicas: 2, ReadyReplicas: 2, AvailableReplicas: 2}},
+		{"daemonset unavailable", daemonSetAvailable{}, infoOf(constants.DaemonSetGVK, "unavailable"), false,
+			&appsv1alpha1.WorkloadReplicas{Replicas: 2, ReadyReplicas: 1, AvailableReplicas: 1}},
+		{"job complete", jobAvailable{}, infoOf(constants.JobGVK, "complete"), true, nil},
+		{"job failed", jobAvailable{}, infoOf(constants.JobGVK, "failed"), false, nil},
+		{"service ready", serviceAvailable{}, infoOf(constants.ServiceGVK, "ready"), true, nil},
+		{"service without ready endpoints", serviceAvailable{}, infoOf(constants.ServiceGVK, "no-endpoints"), false, nil},
+		{"service external name", serviceAvailable{}, infoOf(constants.ServiceGVK, "external"), true, nil},
+		{"condition ready", conditionAvailable{"Ready"}, infoOf(widgetGVK, "ready"), true, nil},
+		{"condition not ready", conditionAvailable{"Ready"}, infoOf(widgetGVK, "not-ready"), false, nil},
+		{"condition of another type", conditionAvailable{"Synced"}, infoOf(widgetGVK, "ready"), false, nil},
+		{"condition of earlier generation", conditionAvailable{"Ready"}, infoOf(widgetGVK, "stale"), false, nil},
+		{"condition not reported", conditionAvailable{"Ready"}, infoOf(widgetGVK, "no-condition"), false, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			isAvailable, replicas, err := c.available.IsAvailable(context.Background(), cli, c.info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if isAvailable != c.expected {
+				t.Errorf("expected available %v, got %v", c.expected, isAvailable)
+			}
+			if (replicas == nil) != (c.replicas == nil) || replicas != nil && *replicas != *c.replicas {
+				t.Errorf("expected replicas %+v, got %+v", c.replicas, replicas)
+			}
+		})
+	}
+}
@@ -5,12 +5,10 @@ import (
 	"fmt"
 	"sort"
 
-	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -60,8 +58,9 @@ func (r *statusReconciler) sync(ctx context.Context, edgeApp *appsv1alpha1.EdgeA
 			// it's not managed by this reconciler
 			continue
 		}
+		available := availableFor(gvk, edgeApp)
 		if _, ok := constants.OverriderTargetGVK[gvk]; !ok {
-			if err := r.updateStatus(ctx, edgeApp, tmplInfo, availableIfExists{}); err != nil {
+			if err := r.updateStatus(ctx, edgeApp, tmplInfo, "", available); err != nil {
 				klog.Errorf("failed to update status for edgeApp %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
 				return controllerruntime.Result{Requeue: true}, err
 			}
@@ -76,12 +75,8 @@ func (r *statusReconciler) sync(ctx context.Context, edgeApp *appsv1alpha1.EdgeA
 						tmplCopy.GetNamespace(), tmplCopy.GetName(), gvk, edgeApp.Namespace, edgeApp.Name, err)
 					continue
 				}
-				// TODO:
-				// Currently, only deployment will be override. So, we just use the deploymentAvailable here.
-				// It a temporary strategy for convenience. When we need to support more GVK, a generic strategy
-				// is needed.
 				newTmplInfo := &utils.TemplateInfo{Ordinal: tmplInfo.Ordinal, Template: tmplCopy}
-				if err := r.updateStatus(ctx, edgeApp, newTmplInfo, deploymentAvailable{}); err != nil {
+				if err := r.updateStatus(ctx, edgeApp, newTmplInfo, overrideInfo.TargetNodeGroup, available); err != nil {
 					klog.Errorf("failed to update status for edgeApp %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
 					return controllerruntime.Result{Requeue: true}, err
 				}
@@ -100,23 +95,26 @@ func (r *statusReconciler) updateStatus(
 	ctx context.Context,
 	edgeApp *appsv1alpha1.EdgeApplication,
 	tmplInfo *utils.TemplateInfo,
+	nodeGroup string,
 	available available) error {
 	info := utils.GetResourceInfoOfTemplateInfo(tmplInfo)
-	isAvailable, err := available.IsAvailable(ctx, r.Client, info)
+	isAvailable, replicas, err := available.IsAvailable(ctx, r.Client, info)
 	if err != nil {
 		klog.Errorf("failed to check the availability of obj %s/%s, %s/%s, kind: %s, %v",
 			info.Namespace, info.Name, info.Group, info.Version, info.Kind, err)
 	}
 	if isAvailable {
-		return r.update(ctx, edgeApp, info, appsv1alpha1.EdgeAppAvailable)
+		return r.update(ctx, edgeApp, info, nodeGroup, replicas, appsv1alpha1.EdgeAppAvailable)
 	}
-	return r.update(ctx, edgeApp, info, appsv1alpha1.EdgeAppProcessing)
+	return r.update(ctx, edgeApp, info, nodeGroup, replicas, appsv1alpha1.EdgeAppProcessing)
 }
 
 func (r *statusReconciler) update(
 	ctx context.Context,
 	edgeApp *appsv1alpha1.EdgeApplication,
 	info utils.ResourceInfo,
+	nodeGroup string,
+	replicas *appsv1alpha1.WorkloadReplicas,
 	status appsv1alpha1.ManifestCondition) error {
 	newStatus := appsv1alpha1.ManifestStatus{
 		Identifier: appsv1alpha1.ResourceIdentifier{
@@ -128,6 +126,8 @@ func (r *statusReconciler) update(
 			Name:      info.Name,
 		},
 		Condition: status,
+		NodeGroup: nodeGroup,
+		Replicas:  replicas,
 	}
 
 	var statusInEdgeApp *appsv1alpha1.ManifestStatus
@@ -143,10 +143,10 @@ func (r *statusReconciler) update(
 	if statusInEdgeApp == nil {
 		// not found, add a new entry for it
 		edgeApp.Status.WorkloadStatus = append(edgeApp.Status.WorkloadStatus, newStatus)
-	} else if utils.IsInitStatus(statusInEdgeApp) || statusInEdgeApp.Condition != status {
+	} else if utils.IsInitStatus(statusInEdgeApp) || !equality.Semantic.DeepEqual(*statusInEdgeApp, newStatus) {
 		// the existing status needs to be updated
 		*statusInEdgeApp = newStatus
-	} else {
+	} else if nodeGroupStatus := utils.AggregateNodeGroupStatus(edgeApp); equality.Semantic.DeepEqual(nodeGroupStatus, edgeApp.Status.NodeGroupStatus) {
 		// no need to update status
 		klog.V(4).Infof("obj %s/%s of gvk %s/%s, %s has same status as what in edgeapp %s/%s, skip update status",
 			info.Namespace, info.Name, info.Group, info.Version, info.Kind, edgeApp.Namespace, edgeApp.Name)
@@ -156,6 +156,7 @@ func (r *statusReconciler) update(
 	sort.Slice(edgeApp.Status.WorkloadStatus, func(i, j int) bool {
 		return edgeApp.Status.WorkloadStatus[i].Identifier.Ordinal < edgeApp.Status.WorkloadStatus[j].Identifier.Ordinal
 	})
+	edgeApp.Status.NodeGroupStatus = utils.AggregateNodeGroupStatus(edgeApp)
 	if err := r.Client.Status().Update(ctx, edgeApp); err != nil {
 		return fmt.Errorf("failed to update status of EdgeApplication %s/%s, %v", edgeApp.Namespace, edgeApp.Name, err)
 	}
@@ -163,57 +164,3 @@ func (r *statusReconciler) update(
 		edgeApp.Namespace, edgeApp.Name, info.Namespace, info.Name, info.Group, info.Version, info.Kind, status)
 	return nil
 }
-
-type available interface {
-	IsAvailable(context.Context, client.Client, utils.ResourceInfo) (bool, error)
-}
-
-var _ available = availableIfExists{}
-var _ available = deploymentAvailable{}
-
-type availableIfExists struct{}
-
-func (e availableIfExists) IsAvailable(ctx context.Context, client client.Client, info utils.ResourceInfo) (bool, error) {
-	obj, err := getObjAccordingToResourceInfo(ctx, client, info)
-	if err != nil {
-		return false, err
-	}
-	if obj == nil {
-		return false, nil
-	}
-	return true, nil
-}
-
-type deploymentAvailable struct{}
-
-func (d deploymentAvailable) IsAvailable(ctx context.Context, client client.Client, info utils.ResourceInfo) (bool, error) {
-	obj, err := getObjAccordingToResourceInfo(ctx, client, info)
-	if err != nil {
-		return false, err
-	}
-	if obj == nil {
-		return false, err
-	}
-
-	deploy := &appsv1.Deployment{}
-	if err := client.Scheme().Convert(obj, deploy, nil); err != nil {
-		return false, fmt.Errorf("failed to convert unstructured to deployment for %s/%s, %v", info.Namespace, info.Name, err)
-	}
-
-	return deploy.Status.ReadyReplicas == *deploy.Spec.Replicas, nil
-}
-
-func getObjAccordingToResourceInfo(ctx context.Context, client client.Client, info utils.ResourceInfo) (*unstructured.Unstructured, error) {
-	gvk := schema.GroupVersionKind{Group: info.Group, Version: info.Version, Kind: info.Kind}
-	curObj := &unstructured.Unstructured{}
-	curObj.SetGroupVersionKind(gvk)
-	if err := client.Get(ctx, types.NamespacedName{Namespace: info.Namespace, Name: info.Name}, curObj); err != nil {
-		if apierrors.IsNotFound(err) {
-			klog.V(4).Infof("cannot find obj %s/%s of gvk %s", info.Namespace, info.Name, gvk)
-			return nil, nil
-		}
-		klog.Errorf("failed to get obj %s/%s, gvk: %s, %v", info.Namespace, info.Name, gvk, err)
-		return nil, err
-	}
-	return curObj, nil
-}
@@ -6,9 +6,12 @@ import (
 	"sync"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -16,9 +19,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	appsv1alpha1 "github.com/kubeedge/api/apis/apps/v1alpha1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/edgeapplication/constants"
 	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/edgeapplication/overridemanager"
 	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/edgeapplication/utils"
 )
@@ -187,6 +192,13 @@ func (s *statusManager) startToWatch(ctx context.Context, gvk schema.GroupVersio
 		klog.Errorf("failed to add delete event watch to controller for gvk: %s, %v", gvk, err)
 		return err
 	}
+	if gvk == constants.ServiceGVK {
+		// the availability of services depends on their endpoints, which are not owned by the EdgeApplication
+		if err := controller.Watch(source.Kind(s.mgr.GetCache(), &corev1.Endpoints{}), handler.EnqueueRequestsFromMapFunc(edgeAppOfEndpoints(s.client))); err != nil {
+			klog.Errorf("failed to add endpoints watch to controller for gvk: %s, %v", gvk, err)
+			return err
+		}
+	}
 
 	go func() {
 		if err := controller.Start(ctx); err != nil {
@@ -233,6 +245,21 @@ func (s *statusManager) watchControllersGC() {
 	}
 }
 
+// edgeAppOfEndpoints maps the endpoints to the EdgeApplication owning the service of the same name
+func edgeAppOfEndpoints(c client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		svc := &corev1.Service{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, svc); err != nil {
+			return nil
+		}
+		owner := metav1.GetControllerOf(svc)
+		if owner == nil || owner.Kind != "EdgeApplication" || owner.APIVersion != appsv1alpha1.SchemeGroupVersion.String() {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: svc.Namespace, Name: owner.Name}}}
+	}
+}
+
 func infoToGVK(info utils.ResourceInfo) schema.GroupVersionKind {
 	return schema.GroupVersionKind{
 		Group:   info.Group,
@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statusmanager
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1alpha1 "github.com/kubeedge/api/apis/apps/v1alpha1"
+)
+
+func TestEdgeAppOfEndpoints(t *testing.T) {
+	serviceOwnedBy := func(name string, owner metav1.OwnerReference) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            name,
+			OwnerReferences: []metav1.OwnerReference{owner},
+		}}
+	}
+	cli := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(
+		serviceOwnedBy("owned", metav1.OwnerReference{
+			APIVersion: appsv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "EdgeApplication",
+			Name:       "app",
+			Controller: pointer.Bool(true),
+		}),
+		serviceOwnedBy("other-owner", metav1.OwnerReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       "app",
+			Controller: pointer.Bool(true),
+		}),
+	).Build()
+	mapFunc := edgeAppOfEndpoints(cli)
+
+	cases := map[string][]reconcile.Request{
+		"owned":       {{NamespacedName: types.NamespacedName{Namespace: "default", Name: "app"}}},
+		"other-owner": nil,
+		"no-service":  nil,
+	}
+	for name, expected := range cases {
+		endpoints := &corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+		if requests := mapFunc(context.Background(), endpoints); !reflect.DeepEqual(requests, expected) {
+			t.Errorf("endpoints %s: expected requests %v, got %v", name, expected, requests)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"sort"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -104,3 +105,53 @@ func IsIdentifierSameAsResourceInfo(identifier appsv1alpha1.ResourceIdentifier,
 		identifier.Namespace == info.Namespace &&
 		identifier.Name == info.Name
 }
+
+// AggregateNodeGroupStatus aggregates the workload statuses of the edgeApp for each target node group
+// in the spec. A node group is ready if all the manifests deployed to it, including the ones shared by
+// all the node groups, are available.
+func AggregateNodeGroupStatus(edgeApp *appsv1alpha1.EdgeApplication) []appsv1alpha1.NodeGroupWorkloadStatus {
+	targetNodeGroups := edgeApp.Spec.WorkloadScope.TargetNodeGroups
+	if len(targetNodeGroups) == 0 {
+		return nil
+	}
+	sharedReady := true
+	for _, status := range edgeApp.Status.WorkloadStatus {
+		if status.NodeGroup == "" && status.Condition != appsv1alpha1.EdgeAppAvailable {
+			sharedReady = false
+			break
+		}
+	}
+
+	// the revisions are maintained by the edgeapplication controller
+	revisions := make(map[string]string, len(edgeApp.Status.NodeGroupStatus))
+	for _, status := range edgeApp.Status.NodeGroupStatus {
+		revisions[status.NodeGroup] = status.Revision
+	}
+
+	nodeGroupStatus := make([]appsv1alpha1.NodeGroupWorkloadStatus, 0, len(targetNodeGroups))
+	for _, nodeGroup := range targetNodeGroups {
+		aggregated := appsv1alpha1.NodeGroupWorkloadStatus{
+			NodeGroup: nodeGroup.Name,
+			Ready:     sharedReady,
+			Revision:  revisions[nodeGroup.Name],
+		}
+		for _, status := range edgeApp.Status.WorkloadStatus {
+			if status.NodeGroup != nodeGroup.Name {
+				continue
+			}
+			if status.Condition != appsv1alpha1.EdgeAppAvailable {
+				aggregated.Ready = false
+			}
+			if status.Replicas != nil {
+				aggregated.Replicas += status.Replicas.Replicas
+				aggregated.ReadyReplicas += status.Replicas.ReadyReplicas
+				aggregated.AvailableReplicas += status.Replicas.AvailableReplicas
+			}
+		}
+		nodeGroupStatus = append(nodeGroupStatus, aggregated)
+	}
+	sort.Slice(nodeGroupStatus, func(i, j int) bool {
+		return nodeGroupStatus[i].NodeGroup < nodeGroupStatus[j].NodeGroup
+	})
+	return nodeGroupStatus
+}
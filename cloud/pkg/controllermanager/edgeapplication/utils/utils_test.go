@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	appsv1alpha1 "github.com/kubeedge/api/apis/apps/v1alpha1"
+)
+
+func TestAggregateNodeGroupStatus(t *testing.T) {
+	replicas := func(desired, ready int32) *appsv1alpha1.WorkloadReplicas {
+		return &appsv1alpha1.WorkloadReplicas{Replicas: desired, ReadyReplicas: ready, AvailableReplicas: ready}
+	}
+	edgeApp := &appsv1alpha1.EdgeApplication{
+		Spec: appsv1alpha1.EdgeApplicationSpec{
+			WorkloadScope: appsv1alpha1.WorkloadScope{
+				TargetNodeGroups: []appsv1alpha1.TargetNodeGroup{{Name: "site-b"}, {Name: "site-a"}, {Name: "site-c"}},
+			},
+		},
+		Status: appsv1alpha1.EdgeApplicationStatus{
+			WorkloadStatus: []appsv1alpha1.ManifestStatus{
+				{Condition: appsv1alpha1.EdgeAppAvailable, NodeGroup: "site-a", Replicas: replicas(2, 2)},
+				{Condition: appsv1alpha1.EdgeAppAvailable, NodeGroup: "site-a", Replicas: replicas(1, 1)},
+				{Condition: appsv1alpha1.EdgeAppProcessing, NodeGroup: "site-b", Replicas: replicas(3, 1)},
+				{Condition: appsv1alpha1.EdgeAppAvailable},
+				// the node group which has been removed from the spec
+				{Condition: appsv1alpha1.EdgeAppAvailable, NodeGroup: "site-d", Replicas: replicas(1, 1)},
+			},
+		},
+	}
+
+	expected := []appsv1alpha1.NodeGroupWorkloadStatus{
+		{NodeGroup: "site-a", Ready: true, Replicas: 3, ReadyReplicas: 3, AvailableReplicas: 3},
+		{NodeGroup: "site-b", Ready: false, Replicas: 3, ReadyReplicas: 1, AvailableReplicas: 1},
+		{NodeGroup: "site-c", Ready: true},
+	}
+	if got := AggregateNodeGroupStatus(edgeApp); !equality.Semantic.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+
+	// the shared manifests are required by all the node groups
+	edgeApp.Status.WorkloadStatus[3].Condition = appsv1alpha1.EdgeAppProcessing
+	for _, status := range AggregateNodeGroupStatus(edgeApp) {
+		if status.Ready {
+			t.Errorf("expected node group %s not ready when the shared manifest is processing", status.NodeGroup)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
@@ -150,7 +151,8 @@ func (c *Controller) syncNodeGroup(ctx context.Context, nodeGroup *appsv1alpha1.
 		nodeStatusList = append(nodeStatusList, nodeStatus)
 	}
 	// update status for nodes that do not exist but specified by node name.
-	nonExistingNodes := sets.NewString(nodeGroup.Spec.Nodes...).Difference(existingNodes)
+	nonExistingNodes := sets.NewString(nodeGroup.Spec.Nodes...).Difference(existingNodes).
+		Difference(sets.NewString(nodeGroup.Spec.ExcludeNodes...))
 	for node := range nonExistingNodes {
 		nodeStatusList = append(nodeStatusList, appsv1alpha1.NodeStatus{
 			NodeName:              node,
@@ -162,12 +164,13 @@ func (c *Controller) syncNodeGroup(ctx context.Context, nodeGroup *appsv1alpha1.
 	sort.Slice(nodeStatusList, func(i, j int) bool {
 		return nodeStatusList[i].NodeName < nodeStatusList[j].NodeName
 	})
-	if equality.Semantic.DeepEqual(nodeGroup.Status.NodeStatuses, nodeStatusList) {
+	newStatus := summarizeNodeGroupStatus(newNodes, nodeStatusList)
+	if equality.Semantic.DeepEqual(nodeGroup.Status, newStatus) {
 		klog.V(4).Infof("status of nodegroup is unchanged, skip update")
 		return controllerruntime.Result{}, nil
 	}
-	klog.V(4).Infof("status of nodegroup has changed, old: %v, new: %v", nodeGroup.Status.NodeStatuses, nodeStatusList)
-	nodeGroup.Status.NodeStatuses = nodeStatusList
+	klog.V(4).Infof("status of nodegroup has changed, old: %v, new: %v", nodeGroup.Status, newStatus)
+	nodeGroup.Status = newStatus
 	if err := c.Status().Update(ctx, nodeGroup); err != nil {
 		klog.Errorf("failed to update status for nodegroup %s, %s", nodeGroup.Name, err)
 		return controllerruntime.Result{Requeue: true}, nil
@@ -262,8 +265,17 @@ func (c *Controller) getNodesSelectedBy(ctx context.Context, nodeGroup *appsv1al
 		errs = append(errs, err)
 	}
 	klog.V(4).Infof("get %d nodes that specified by name in nodegroup %s", len(nodesByName), nodeGroup.Name)
-	// remove duplicate nodes
-	return nodesUnion(nodesByLabel, nodesByName), utilerrors.NewAggregate(errs)
+
+	nodesBySelector, err := c.getNodesByLabelSelector(ctx, nodeGroup.Spec.LabelSelector)
+	if err != nil {
+		klog.Errorf("failed to get nodes by LabelSelector %v, %s", nodeGroup.Spec.LabelSelector, err)
+		errs = append(errs, err)
+	}
+	klog.V(4).Infof("get %d nodes that match label selector in nodegroup %s", len(nodesBySelector), nodeGroup.Name)
+
+	// remove duplicate nodes and excluded nodes
+	nodes := nodesExclude(nodesUnion(nodesUnion(nodesByLabel, nodesByName), nodesBySelector), nodeGroup.Spec.ExcludeNodes)
+	return nodes, utilerrors.NewAggregate(errs)
 }
 
 // We can assume that one node can only be in one of following conditions:
@@ -330,6 +342,22 @@ func (c *Controller) getNodesByLabels(ctx context.Context, matchLabels map[strin
 	return nodeList.Items, nil
 }
 
+// getNodesByLabelSelector can get all nodes selected by the label selector.
+func (c *Controller) getNodesByLabelSelector(ctx context.Context, labelSelector *metav1.LabelSelector) ([]corev1.Node, error) {
+	if labelSelector == nil {
+		return []corev1.Node{}, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector, %v", err)
+	}
+	nodeList := &corev1.NodeList{}
+	if err := c.Client.List(ctx, nodeList, &client.ListOptions{LabelSelector: selector}); err != nil {
+		return nil, err
+	}
+	return nodeList.Items, nil
+}
+
 // getNodesByNodeName can get all nodes specified by node names.
 func (c *Controller) getNodesByNodeName(ctx context.Context, nodeNames []string) ([]corev1.Node, error) {
 	errs := []error{}
@@ -374,6 +402,12 @@ func (c *Controller) addOrUpdateNodeLabel(ctx context.Context, node *corev1.Node
 
 // IfMatchNodeGroup will check if the node is selected by the nodegroup.
 func IfMatchNodeGroup(node *corev1.Node, nodegroup *appsv1alpha1.NodeGroup) bool {
+	// check if nodename is in the nodegroup.Spec.ExcludeNodes
+	for _, nodeName := range nodegroup.Spec.ExcludeNodes {
+		if nodeName == node.Name {
+			return false
+		}
+	}
 	// check if nodename is in the nodegroup.Spec.Nodes
 	for _, nodeName := range nodegroup.Spec.Nodes {
 		if nodeName == node.Name {
@@ -381,8 +415,61 @@ func IfMatchNodeGroup(node *corev1.Node, nodegroup *appsv1alpha1.NodeGroup) bool
 		}
 	}
 	// check if labels of this node selected by nodegroup.Spec.MatchLabels
-	selector := labels.SelectorFromSet(nodegroup.Spec.MatchLabels)
-	return selector.Matches(labels.Set(node.Labels))
+	if nodegroup.Spec.MatchLabels != nil &&
+		labels.SelectorFromSet(nodegroup.Spec.MatchLabels).Matches(labels.Set(node.Labels)) {
+		return true
+	}
+	// check if labels of this node selected by nodegroup.Spec.LabelSelector
+	if nodegroup.Spec.LabelSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(nodegroup.Spec.LabelSelector)
+		if err != nil {
+			klog.Errorf("invalid label selector of nodegroup %s, %s", nodegroup.Name, err)
+			return false
+		}
+		return selector.Matches(labels.Set(node.Labels))
+	}
+	return false
+}
+
+// summarizeNodeGroupStatus summarizes the status of the nodegroup from the member nodes.
+// Only the allocatable cpu and memory of ready nodes are aggregated.
+func summarizeNodeGroupStatus(nodes []corev1.Node, nodeStatusList []appsv1alpha1.NodeStatus) appsv1alpha1.NodeGroupStatus {
+	status := appsv1alpha1.NodeGroupStatus{
+		NodeStatuses: nodeStatusList,
+	}
+	nodesMap := make(map[string]*corev1.Node, len(nodes))
+	for i := range nodes {
+		nodesMap[nodes[i].Name] = &nodes[i]
+	}
+
+	allocatable := corev1.ResourceList{}
+	for _, nodeStatus := range nodeStatusList {
+		if nodeStatus.SelectionStatus != appsv1alpha1.SucceededSelection {
+			continue
+		}
+		status.TotalNodes++
+		if nodeStatus.ReadyStatus != appsv1alpha1.NodeReady {
+			continue
+		}
+		status.ReadyNodes++
+		node, ok := nodesMap[nodeStatus.NodeName]
+		if !ok {
+			continue
+		}
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			quantity, ok := node.Status.Allocatable[name]
+			if !ok {
+				continue
+			}
+			sum := allocatable[name]
+			sum.Add(quantity)
+			allocatable[name] = sum
+		}
+	}
+	if len(allocatable) != 0 {
+		status.Allocatable = allocatable
+	}
+	return status
 }
 
 func getNodeReadyConditionFromNode(node *corev1.Node) (corev1.ConditionStatus, bool) {
@@ -414,6 +501,20 @@ func nodesDiff(oldNodes []corev1.Node, newNodes []corev1.Node) ([]corev1.Node, [
 	return nodesDeleted, nodesAdded
 }
 
+func nodesExclude(nodes []corev1.Node, excludeNodes []string) []corev1.Node {
+	if len(excludeNodes) == 0 {
+		return nodes
+	}
+	excluded := sets.NewString(excludeNodes...)
+	result := []corev1.Node{}
+	for _, node := range nodes {
+		if !excluded.Has(node.Name) {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
 func nodesUnion(a []corev1.Node, b []corev1.Node) []corev1.Node {
 	nodesMap := map[string]*corev1.Node{}
 	for i := range a {
@@ -1,12 +1,20 @@
 package nodegroup
 
 import (
+	"context"
 	"sort"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/kubeedge/api/apis/apps/v1alpha1"
 )
 
 func TestNodesUnion(t *testing.T) {
@@ -124,3 +132,134 @@ func TestNodesUnion(t *testing.T) {
 		}
 	}
 }
+
+func newTestNode(name string, nodeLabels map[string]string, ready corev1.ConditionStatus, cpu, memory string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   name,
+			Labels: nodeLabels,
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+func TestIfMatchNodeGroup(t *testing.T) {
+	node := newTestNode("node1", map[string]string{"site": "a", "arch": "arm64"}, corev1.ConditionTrue, "1", "1Gi")
+	cases := map[string]struct {
+		spec appsv1alpha1.NodeGroupSpec
+		want bool
+	}{
+		"empty spec": {
+			spec: appsv1alpha1.NodeGroupSpec{},
+			want: false,
+		},
+		"by node name": {
+			spec: appsv1alpha1.NodeGroupSpec{Nodes: []string{"node1"}},
+			want: true,
+		},
+		"by match labels": {
+			spec: appsv1alpha1.NodeGroupSpec{MatchLabels: map[string]string{"site": "a"}},
+			want: true,
+		},
+		"by match expressions": {
+			spec: appsv1alpha1.NodeGroupSpec{LabelSelector: &v1.LabelSelector{
+				MatchExpressions: []v1.LabelSelectorRequirement{
+					{Key: "site", Operator: v1.LabelSelectorOpIn, Values: []string{"a", "b"}},
+					{Key: "arch", Operator: v1.LabelSelectorOpNotIn, Values: []string{"amd64"}},
+				},
+			}},
+			want: true,
+		},
+		"not matched by match expressions": {
+			spec: appsv1alpha1.NodeGroupSpec{LabelSelector: &v1.LabelSelector{
+				MatchExpressions: []v1.LabelSelectorRequirement{
+					{Key: "gpu", Operator: v1.LabelSelectorOpExists},
+				},
+			}},
+			want: false,
+		},
+		"excluded": {
+			spec: appsv1alpha1.NodeGroupSpec{
+				MatchLabels:  map[string]string{"site": "a"},
+				ExcludeNodes: []string{"node1"},
+			},
+			want: false,
+		},
+	}
+	for n, c := range cases {
+		if got := IfMatchNodeGroup(node, &appsv1alpha1.NodeGroup{Spec: c.spec}); got != c.want {
+			t.Errorf("failed at case: %s, want: %v, got: %v", n, c.want, got)
+		}
+	}
+}
+
+func TestSyncNodeGroupStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := appsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	nodeGroup := &appsv1alpha1.NodeGroup{
+		ObjectMeta: v1.ObjectMeta{Name: "site-a"},
+		Spec: appsv1alpha1.NodeGroupSpec{
+			Nodes: []string{"node4"},
+			LabelSelector: &v1.LabelSelector{
+				MatchExpressions: []v1.LabelSelectorRequirement{
+					{Key: "site", Operator: v1.LabelSelectorOpIn, Values: []string{"a"}},
+				},
+			},
+			ExcludeNodes: []string{"node3"},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&appsv1alpha1.NodeGroup{}).
+		WithObjects(
+			nodeGroup,
+			newTestNode("node1", map[string]string{"site": "a"}, corev1.ConditionTrue, "2", "4Gi"),
+			newTestNode("node2", map[string]string{"site": "a"}, corev1.ConditionFalse, "2", "4Gi"),
+			newTestNode("node3", map[string]string{"site": "a"}, corev1.ConditionTrue, "2", "4Gi"),
+			newTestNode("node4", nil, corev1.ConditionTrue, "500m", "1Gi"),
+			newTestNode("node5", map[string]string{"site": "b"}, corev1.ConditionTrue, "2", "4Gi"),
+		).Build()
+	c := &Controller{Client: cli}
+	ctx := context.Background()
+
+	if _, err := c.syncNodeGroup(ctx, nodeGroup); err != nil {
+		t.Fatal(err)
+	}
+	got := &appsv1alpha1.NodeGroup{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(nodeGroup), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.TotalNodes != 3 || got.Status.ReadyNodes != 2 {
+		t.Errorf("expected 3 total nodes and 2 ready nodes, got %d and %d", got.Status.TotalNodes, got.Status.ReadyNodes)
+	}
+	expectedAllocatable := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("2500m"),
+		corev1.ResourceMemory: resource.MustParse("5Gi"),
+	}
+	if !equality.Semantic.DeepEqual(got.Status.Allocatable, expectedAllocatable) {
+		t.Errorf("expected allocatable %v, got %v", expectedAllocatable, got.Status.Allocatable)
+	}
+	for _, nodeStatus := range got.Status.NodeStatuses {
+		if nodeStatus.NodeName == "node3" || nodeStatus.NodeName == "node5" {
+			t.Errorf("unexpected node %s in nodegroup", nodeStatus.NodeName)
+		}
+	}
+
+	node3 := &corev1.Node{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: "node3"}, node3); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := node3.Labels[LabelBelongingTo]; ok {
+		t.Errorf("expected the excluded node not labeled")
+	}
+}
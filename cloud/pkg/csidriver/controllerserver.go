@@ -24,6 +24,7 @@ import (
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
 	"github.com/google/uuid"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
@@ -47,6 +48,12 @@ func newControllerServer(nodeID, kubeEdgeEndpoint string) *controllerServer {
 			[]csi.ControllerServiceCapability_RPC_Type{
 				csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
 				csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
+				csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
+				csi.ControllerServiceCapability_RPC_GET_VOLUME,
+				csi.ControllerServiceCapability_RPC_LIST_VOLUMES,
+				csi.ControllerServiceCapability_RPC_GET_CAPACITY,
+				csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+				csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
 			}),
 		nodeID:           nodeID,
 		kubeEdgeEndpoint: kubeEdgeEndpoint,
@@ -437,30 +444,158 @@ func getControllerServiceCapabilities(cl []csi.ControllerServiceCapability_RPC_T
 	return csc
 }
 
-func (cs *controllerServer) GetCapacity(context.Context, *csi.GetCapacityRequest) (*csi.GetCapacityResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "")
+// GetCapacity issues get capacity func
+func (cs *controllerServer) GetCapacity(_ context.Context, req *csi.GetCapacityRequest) (*csi.GetCapacityResponse, error) {
+	getCapacityResponse := &csi.GetCapacityResponse{}
+	if err := cs.sendToEdge(uuid.New().String(), constants.CSIOperationTypeGetCapacity, req, getCapacityResponse); err != nil {
+		return nil, err
+	}
+	return getCapacityResponse, nil
+}
+
+// ListVolumes issues list volumes func
+func (cs *controllerServer) ListVolumes(_ context.Context, req *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
+	listVolumesResponse := &csi.ListVolumesResponse{}
+	if err := cs.sendToEdge(uuid.New().String(), constants.CSIOperationTypeListVolumes, req, listVolumesResponse); err != nil {
+		return nil, err
+	}
+	return listVolumesResponse, nil
 }
 
-func (cs *controllerServer) ListVolumes(context.Context, *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "")
+// ControllerExpandVolume issues controller expand volume func
+func (cs *controllerServer) ControllerExpandVolume(_ context.Context, req *csi.ControllerExpandVolumeRequest) (*csi.ControllerExpandVolumeResponse, error) {
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+	if req.GetCapacityRange() == nil {
+		return nil, status.Error(codes.InvalidArgument, "Capacity range missing in request")
+	}
+
+	controllerExpandVolumeResponse := &csi.ControllerExpandVolumeResponse{}
+	if err := cs.sendToEdge(req.GetVolumeId(), constants.CSIOperationTypeControllerExpandVolume, req, controllerExpandVolumeResponse); err != nil {
+		return nil, err
+	}
+	return controllerExpandVolumeResponse, nil
 }
 
-func (cs *controllerServer) ControllerExpandVolume(context.Context, *csi.ControllerExpandVolumeRequest) (*csi.ControllerExpandVolumeResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "ControllerExpandVolume is not yet implemented")
+// CreateSnapshot issues create snapshot func
+func (cs *controllerServer) CreateSnapshot(_ context.Context, req *csi.CreateSnapshotRequest) (*csi.CreateSnapshotResponse, error) {
+	if len(req.GetName()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Name missing in request")
+	}
+	if len(req.GetSourceVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Source volume ID missing in request")
+	}
+
+	createSnapshotResponse := &csi.CreateSnapshotResponse{}
+	if err := cs.sendToEdge(uuid.New().String(), constants.CSIOperationTypeCreateSnapshot, req, createSnapshotResponse); err != nil {
+		return nil, err
+	}
+	return createSnapshotResponse, nil
 }
 
-func (cs *controllerServer) CreateSnapshot(context.Context, *csi.CreateSnapshotRequest) (*csi.CreateSnapshotResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "CreateSnapshot is not yet implemented")
+// DeleteSnapshot issues delete snapshot func
+func (cs *controllerServer) DeleteSnapshot(_ context.Context, req *csi.DeleteSnapshotRequest) (*csi.DeleteSnapshotResponse, error) {
+	if len(req.GetSnapshotId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Snapshot ID missing in request")
+	}
+
+	deleteSnapshotResponse := &csi.DeleteSnapshotResponse{}
+	if err := cs.sendToEdge(req.GetSnapshotId(), constants.CSIOperationTypeDeleteSnapshot, req, deleteSnapshotResponse); err != nil {
+		return nil, err
+	}
+	return deleteSnapshotResponse, nil
 }
 
-func (cs *controllerServer) DeleteSnapshot(context.Context, *csi.DeleteSnapshotRequest) (*csi.DeleteSnapshotResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "DeleteSnapshot is not yet implemented")
+// ListSnapshots issues list snapshots func
+func (cs *controllerServer) ListSnapshots(_ context.Context, req *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
+	listSnapshotsResponse := &csi.ListSnapshotsResponse{}
+	if err := cs.sendToEdge(uuid.New().String(), constants.CSIOperationTypeListSnapshots, req, listSnapshotsResponse); err != nil {
+		return nil, err
+	}
+	return listSnapshotsResponse, nil
 }
 
-func (cs *controllerServer) ListSnapshots(context.Context, *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "ListSnapshots is not yet implemented")
+// ControllerGetVolume issues controller get volume func
+func (cs *controllerServer) ControllerGetVolume(_ context.Context, req *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+
+	controllerGetVolumeResponse := &csi.ControllerGetVolumeResponse{}
+	if err := cs.sendToEdge(req.GetVolumeId(), constants.CSIOperationTypeControllerGetVolume, req, controllerGetVolumeResponse); err != nil {
+		return nil, err
+	}
+	return controllerGetVolumeResponse, nil
 }
 
-func (cs *controllerServer) ControllerGetVolume(context.Context, *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "ControllerGetVolume is not yet implemented")
+// sendToEdge sends the request of the operation to the edge node and unmarshals the response of the edge csi driver.
+// resourceID is the id of the volume or snapshot of the request, or a random id if the request is not for one of them.
+func (cs *controllerServer) sendToEdge(resourceID, operation string, req proto.Message, response interface{}) error {
+	// Build message struct
+	resource, err := buildResource(cs.nodeID,
+		DefaultNamespace,
+		constants.CSIResourceTypeVolume,
+		resourceID)
+	if err != nil {
+		klog.Errorf("build message resource failed with error: %s", err)
+		return err
+	}
+
+	m := jsonpb.Marshaler{}
+	js, err := m.MarshalToString(req)
+	if err != nil {
+		klog.Errorf("failed to marshal to string with error: %s", err)
+		return err
+	}
+	klog.V(4).Infof("%s marshal to string: %s", operation, js)
+	msg := model.NewMessage("").
+		BuildRouter(DefaultReceiveModuleName, GroupResource, resource, operation).
+		FillBody(js)
+
+	// Marshal message
+	reqData, err := json.Marshal(msg)
+	if err != nil {
+		klog.Errorf("marshal request failed with error: %v", err)
+		return err
+	}
+
+	// Send message to KubeEdge
+	resdata, err := sendToKubeEdge(string(reqData), cs.kubeEdgeEndpoint)
+	if err != nil {
+		klog.Errorf("send to kubeedge failed with error: %v", err)
+		return err
+	}
+
+	// Unmarshal message
+	result, err := extractMessage(resdata)
+	if err != nil {
+		klog.Errorf("unmarshal response failed with error: %v", err)
+		return err
+	}
+
+	klog.V(4).Infof("%s result: %v", operation, result)
+	data, ok := result.GetContent().(string)
+	if !ok {
+		klog.Errorf("content is not string type: %v", result.GetContent())
+		return fmt.Errorf("content type %T is not string", result.GetContent())
+	}
+
+	if result.GetOperation() == model.ResponseErrorOperation {
+		klog.Errorf("%s with error: %s", operation, data)
+		return errors.New(data)
+	}
+
+	decodeBytes, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		klog.Errorf("%s decode with error: %v", operation, err)
+		return err
+	}
+
+	if err := json.Unmarshal(decodeBytes, response); err != nil {
+		klog.Errorf("%s unmarshal with error: %v", operation, err)
+		return err
+	}
+	klog.V(4).Infof("%s response: %v", operation, response)
+	return nil
 }
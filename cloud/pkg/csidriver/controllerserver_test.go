@@ -42,6 +42,12 @@ func TestNewControllerServer(t *testing.T) {
 		[]csi.ControllerServiceCapability_RPC_Type{
 			csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
 			csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
+			csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
+			csi.ControllerServiceCapability_RPC_GET_VOLUME,
+			csi.ControllerServiceCapability_RPC_LIST_VOLUMES,
+			csi.ControllerServiceCapability_RPC_GET_CAPACITY,
+			csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+			csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
 		})
 	assert.Equal(expectedCaps, cs.caps)
 
@@ -171,3 +177,39 @@ func TestGetControllerServiceCapabilities(t *testing.T) {
 			"Capability %d should be %v", i, capType)
 	}
 }
+
+func TestControllerRequestValidation(t *testing.T) {
+	assert := assert.New(t)
+
+	cs := &controllerServer{
+		nodeID:           "test-node",
+		kubeEdgeEndpoint: "http://localhost:8080/test",
+	}
+	ctx := context.Background()
+
+	_, err := cs.ControllerExpandVolume(ctx, &csi.ControllerExpandVolumeRequest{
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 1 << 30},
+	})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	assert.Contains(err.Error(), "Volume ID missing in request")
+
+	_, err = cs.ControllerExpandVolume(ctx, &csi.ControllerExpandVolumeRequest{VolumeId: "vol-1"})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	assert.Contains(err.Error(), "Capacity range missing in request")
+
+	_, err = cs.CreateSnapshot(ctx, &csi.CreateSnapshotRequest{SourceVolumeId: "vol-1"})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	assert.Contains(err.Error(), "Name missing in request")
+
+	_, err = cs.CreateSnapshot(ctx, &csi.CreateSnapshotRequest{Name: "snap-1"})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	assert.Contains(err.Error(), "Source volume ID missing in request")
+
+	_, err = cs.DeleteSnapshot(ctx, &csi.DeleteSnapshotRequest{})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	assert.Contains(err.Error(), "Snapshot ID missing in request")
+
+	_, err = cs.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{})
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+	assert.Contains(err.Error(), "Volume ID missing in request")
+}
@@ -5,6 +5,11 @@ const (
 	ResourceTypeTwinEdgeUpdated  = "twin/edge_updated"
 	ResourceTypeMembershipDetail = "membership/detail"
 	ResourceDeviceStateUpdated   = "state/update"
+	ResourceTypeTwinEdgeHistory  = "twin/edge_history"
+	ResourceTypeMapperStatus     = "mapper/status"
+
+	// MapperStatusAnnotation is the annotation of node recording the status of mappers on the node
+	MapperStatusAnnotation = "devices.kubeedge.io/mapper-status"
 
 	// Group
 	GroupTwin     = "twin"
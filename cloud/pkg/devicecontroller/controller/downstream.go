@@ -38,6 +38,7 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
 	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
 	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/manager"
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/twinhistory"
 	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/types"
 	"github.com/kubeedge/kubeedge/pkg/util"
 )
@@ -49,6 +50,8 @@ type DownstreamController struct {
 
 	deviceManager      *manager.DeviceManager
 	deviceModelManager *manager.DeviceModelManager
+	// twinHistory is nil if twin history is disabled
+	twinHistory twinhistory.Store
 }
 
 // syncDeviceModel is used to get events from informer
@@ -250,6 +253,11 @@ func isDeviceUpdated(oldTwin *v1beta1.Device, newTwin *v1beta1.Device) bool {
 func (dc *DownstreamController) deviceDeleted(device *v1beta1.Device) {
 	deviceID := util.GetResourceID(device.Namespace, device.Name)
 	dc.deviceManager.Device.Delete(deviceID)
+	if dc.twinHistory != nil {
+		if err := dc.twinHistory.Delete(twinhistory.DeviceKey{Namespace: device.Namespace, Name: device.Name}); err != nil {
+			klog.Warningf("Failed to delete twin history of device %s: %v", deviceID, err)
+		}
+	}
 
 	if device.Spec.NodeName != "" {
 		edgeDevice := createDevice(device)
@@ -399,7 +407,7 @@ func (dc *DownstreamController) Start() error {
 }
 
 // NewDownstreamController create a DownstreamController from config
-func NewDownstreamController(crdInformerFactory crdinformers.SharedInformerFactory) (*DownstreamController, error) {
+func NewDownstreamController(crdInformerFactory crdinformers.SharedInformerFactory, twinHistory twinhistory.Store) (*DownstreamController, error) {
 	deviceManager, err := manager.NewDeviceManager(crdInformerFactory.Devices().V1beta1().Devices().Informer())
 	if err != nil {
 		klog.Warningf("Create device manager failed with error: %s", err)
@@ -417,6 +425,7 @@ func NewDownstreamController(crdInformerFactory crdinformers.SharedInformerFacto
 		deviceManager:      deviceManager,
 		deviceModelManager: deviceModelManager,
 		messageLayer:       messagelayer.DeviceControllerMessageLayer(),
+		twinHistory:        twinHistory,
 	}
 	return dc, nil
 }
@@ -20,7 +20,11 @@ import (
 	"context"
 	"encoding/json"
 	"strconv"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/api/apis/devices/v1beta1"
@@ -33,6 +37,7 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
 	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/twinhistory"
 	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/types"
 	commonconst "github.com/kubeedge/kubeedge/common/constants"
 )
@@ -52,11 +57,16 @@ const (
 // UpstreamController subscribe messages from edge and sync to k8s api server
 type UpstreamController struct {
 	crdClient    crdClientset.Interface
+	kubeClient   kubernetes.Interface
 	messageLayer messagelayer.MessageLayer
 	// deviceTwinsChan message channel
 	deviceTwinsChan chan model.Message
 	// deviceStates message channel
 	deviceStatesChan chan model.Message
+	// twinHistory message channel
+	twinHistoryChan chan model.Message
+	// mapperStatus message channel
+	mapperStatusChan chan model.Message
 	// downstream controller to update device status in cache
 	dc *DownstreamController
 }
@@ -67,6 +77,8 @@ func (uc *UpstreamController) Start() error {
 
 	uc.deviceTwinsChan = make(chan model.Message, config.Config.Buffer.UpdateDeviceTwins)
 	uc.deviceStatesChan = make(chan model.Message, config.Config.Buffer.UpdateDeviceStates)
+	uc.twinHistoryChan = make(chan model.Message, config.Config.Buffer.UpdateDeviceTwins)
+	uc.mapperStatusChan = make(chan model.Message, config.Config.Buffer.UpdateDeviceStates)
 	go uc.dispatchMessage()
 
 	for i := 0; i < int(config.Config.Load.UpdateDeviceStatusWorkers); i++ {
@@ -103,6 +115,10 @@ func (uc *UpstreamController) dispatchMessage() {
 			uc.deviceTwinsChan <- msg
 		case constants.ResourceDeviceStateUpdated:
 			uc.deviceStatesChan <- msg
+		case constants.ResourceTypeTwinEdgeHistory:
+			uc.twinHistoryChan <- msg
+		case constants.ResourceTypeMapperStatus:
+			uc.mapperStatusChan <- msg
 		case constants.ResourceTypeMembershipDetail:
 		default:
 			klog.Warningf("Message: %s, with resource type: %s not intended for device controller", msg.GetID(), resourceType)
@@ -142,6 +158,7 @@ func (uc *UpstreamController) updateDeviceStatus() {
 			// Store the status in cache so that when update is received by informer, it is not processed by downstream controller
 			cacheDevice.Status.State = msgState.Device.State
 			cacheDevice.Status.LastOnlineTime = msgState.Device.LastOnlineTime
+			cacheDevice.Status.Reason = msgState.Device.Reason
 			uc.dc.deviceManager.Device.Store(deviceID, cacheDevice)
 
 			body, err := json.Marshal(cacheDevice.Status)
@@ -157,25 +174,7 @@ func (uc *UpstreamController) updateDeviceStatus() {
 			}
 
 			//send confirm message to edge twin
-			resMsg := model.NewMessage(msg.GetID())
-			nodeID, err := messagelayer.GetNodeID(msg)
-			if err != nil {
-				klog.Warningf("Message: %s process failure, get node id failed with error: %s", msg.GetID(), err)
-				continue
-			}
-			resource, err := messagelayer.BuildResourceForDevice(nodeID, "twin", "")
-			if err != nil {
-				klog.Warningf("Message: %s process failure, build message resource failed with error: %s", msg.GetID(), err)
-				continue
-			}
-			resMsg.BuildRouter(modules.DeviceControllerModuleName, constants.GroupTwin, resource, model.ResponseOperation)
-			resMsg.Content = commonconst.MessageSuccessfulContent
-			err = uc.messageLayer.Response(*resMsg)
-			if err != nil {
-				klog.Warningf("Message: %s process failure, response failed with error: %s", msg.GetID(), err)
-				continue
-			}
-			klog.Infof("Message: %s process successfully", msg.GetID())
+			uc.sendConfirm(msg)
 		case msg := <-uc.deviceTwinsChan:
 			klog.Infof("Message: %s, operation is: %s, and resource is: %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
 			msgTwin, err := uc.unmarshalDeviceStatusMessage(msg)
@@ -213,6 +212,7 @@ func (uc *UpstreamController) updateDeviceStatus() {
 							reported.Metadata["type"] = twin.Metadata.Type
 						}
 						deviceTwin.Reported = reported
+						uc.recordTwinHistory(cacheDevice, twinName, twin)
 					}
 
 					if twin.Expected != nil && twin.Expected.Value != nil {
@@ -245,29 +245,140 @@ func (uc *UpstreamController) updateDeviceStatus() {
 				continue
 			}
 			//send confirm message to edge twin
-			resMsg := model.NewMessage(msg.GetID())
-			nodeID, err := messagelayer.GetNodeID(msg)
-			if err != nil {
-				klog.Warningf("Message: %s process failure, get node id failed with error: %s", msg.GetID(), err)
-				continue
-			}
-			resource, err := messagelayer.BuildResourceForDevice(nodeID, "twin", "")
-			if err != nil {
-				klog.Warningf("Message: %s process failure, build message resource failed with error: %s", msg.GetID(), err)
-				continue
-			}
-			resMsg.BuildRouter(modules.DeviceControllerModuleName, constants.GroupTwin, resource, model.ResponseOperation)
-			resMsg.Content = commonconst.MessageSuccessfulContent
-			err = uc.messageLayer.Response(*resMsg)
-			if err != nil {
-				klog.Warningf("Message: %s process failure, response failed with error: %s", msg.GetID(), err)
-				continue
+			uc.sendConfirm(msg)
+		case msg := <-uc.twinHistoryChan:
+			klog.Infof("Message: %s, operation is: %s, and resource is: %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
+			uc.appendTwinHistory(msg)
+			// always confirm, otherwise edge keeps reporting the history of devices unknown to cloud
+			uc.sendConfirm(msg)
+		case msg := <-uc.mapperStatusChan:
+			klog.Infof("Message: %s, operation is: %s, and resource is: %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
+			uc.updateMapperStatus(msg)
+		}
+	}
+}
+
+// updateMapperStatus records the status of mappers reported by edge in the annotation of the node.
+// Edge reports the status of all mappers every time, so the annotation is simply replaced.
+func (uc *UpstreamController) updateMapperStatus(msg model.Message) {
+	contentData, err := msg.GetContentData()
+	if err != nil {
+		klog.Warningf("Message: %s get content failed with error: %v", msg.GetID(), err)
+		return
+	}
+	statusUpdate := &types.MapperStatusUpdate{}
+	if err := json.Unmarshal(contentData, statusUpdate); err != nil {
+		klog.Warningf("Message: %s unmarshal mapper status failed with error: %v", msg.GetID(), err)
+		return
+	}
+	nodeID, err := messagelayer.GetNodeID(msg)
+	if err != nil {
+		klog.Warningf("Message: %s get node id failed with error: %v", msg.GetID(), err)
+		return
+	}
+	mappers, err := json.Marshal(statusUpdate.Mappers)
+	if err != nil {
+		klog.Errorf("Failed to marshal mapper status of node %s: %v", nodeID, err)
+		return
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{constants.MapperStatusAnnotation: string(mappers)},
+		},
+	})
+	if err != nil {
+		klog.Errorf("Failed to marshal mapper status patch of node %s: %v", nodeID, err)
+		return
+	}
+	_, err = uc.kubeClient.CoreV1().Nodes().Patch(utilcontext.FromMessage(context.Background(), msg), nodeID,
+		k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		klog.Errorf("Failed to patch mapper status of node %s, err: %v", nodeID, err)
+	}
+}
+
+// sendConfirm sends the confirm message of msg to edge twin
+func (uc *UpstreamController) sendConfirm(msg model.Message) {
+	resMsg := model.NewMessage(msg.GetID())
+	nodeID, err := messagelayer.GetNodeID(msg)
+	if err != nil {
+		klog.Warningf("Message: %s process failure, get node id failed with error: %s", msg.GetID(), err)
+		return
+	}
+	resource, err := messagelayer.BuildResourceForDevice(nodeID, "twin", "")
+	if err != nil {
+		klog.Warningf("Message: %s process failure, build message resource failed with error: %s", msg.GetID(), err)
+		return
+	}
+	resMsg.BuildRouter(modules.DeviceControllerModuleName, constants.GroupTwin, resource, model.ResponseOperation)
+	resMsg.Content = commonconst.MessageSuccessfulContent
+	err = uc.messageLayer.Response(*resMsg)
+	if err != nil {
+		klog.Warningf("Message: %s process failure, response failed with error: %s", msg.GetID(), err)
+		return
+	}
+	klog.Infof("Message: %s process successfully", msg.GetID())
+}
+
+// appendTwinHistory appends the twin values buffered by edge while disconnected to the twin history store
+func (uc *UpstreamController) appendTwinHistory(msg model.Message) {
+	if uc.dc.twinHistory == nil {
+		return
+	}
+	contentData, err := msg.GetContentData()
+	if err != nil {
+		klog.Warningf("Message: %s get content failed with error: %v", msg.GetID(), err)
+		return
+	}
+	history := &types.DeviceTwinHistory{}
+	if err := json.Unmarshal(contentData, history); err != nil {
+		klog.Warningf("Message: %s unmarshal twin history failed with error: %v", msg.GetID(), err)
+		return
+	}
+	deviceID, err := messagelayer.GetDeviceID(msg.GetResource())
+	if err != nil {
+		klog.Warning("Failed to get device id")
+		return
+	}
+	device, ok := uc.dc.deviceManager.Device.Load(deviceID)
+	if !ok {
+		klog.Warningf("Device %s does not exist in upstream controller", deviceID)
+		return
+	}
+	cacheDevice, ok := device.(*v1beta1.Device)
+	if !ok {
+		klog.Warning("Failed to assert to CacheDevice type")
+		return
+	}
+	key := twinhistory.DeviceKey{Namespace: cacheDevice.Namespace, Name: cacheDevice.Name}
+	for twinName, samples := range history.Twin {
+		for _, s := range samples {
+			sample := twinhistory.Sample{Timestamp: time.UnixMilli(s.Timestamp), Value: s.Value, Type: s.Type}
+			if err := uc.dc.twinHistory.Append(key, twinName, sample); err != nil {
+				klog.Warningf("Failed to append twin history of device %s property %s: %v", key, twinName, err)
 			}
-			klog.Infof("Message: %s process successfully", msg.GetID())
 		}
 	}
 }
 
+// recordTwinHistory appends the reported value of the twin to the twin history store if it is enabled
+func (uc *UpstreamController) recordTwinHistory(device *v1beta1.Device, twinName string, twin *types.MsgTwin) {
+	if uc.dc.twinHistory == nil {
+		return
+	}
+	sample := twinhistory.Sample{Timestamp: time.Now(), Value: *twin.Actual.Value}
+	if twin.Actual.Metadata != nil && twin.Actual.Metadata.Timestamp > 0 {
+		sample.Timestamp = time.UnixMilli(twin.Actual.Metadata.Timestamp)
+	}
+	if twin.Metadata != nil {
+		sample.Type = twin.Metadata.Type
+	}
+	key := twinhistory.DeviceKey{Namespace: device.Namespace, Name: device.Name}
+	if err := uc.dc.twinHistory.Append(key, twinName, sample); err != nil {
+		klog.Warningf("Failed to record twin history of device %s property %s: %v", key, twinName, err)
+	}
+}
+
 func (uc *UpstreamController) unmarshalDeviceStatusMessage(msg model.Message) (*types.DeviceTwinUpdate, error) {
 	contentData, err := msg.GetContentData()
 	if err != nil {
@@ -298,6 +409,7 @@ func (uc *UpstreamController) unmarshalDeviceStatesMessage(msg model.Message) (*
 func NewUpstreamController(dc *DownstreamController) (*UpstreamController, error) {
 	uc := &UpstreamController{
 		crdClient:    keclient.GetCRDClient(),
+		kubeClient:   keclient.GetKubeClient(),
 		messageLayer: messagelayer.DeviceControllerMessageLayer(),
 		dc:           dc,
 	}
@@ -7,10 +7,13 @@ import (
 
 	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
 	"github.com/kubeedge/beehive/pkg/core"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
 	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/controller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/twinhistory"
 )
 
 // DeviceController use beehive context message layer
@@ -26,7 +29,17 @@ func newDeviceController(enable bool) *DeviceController {
 	if !enable {
 		return &DeviceController{enable: enable}
 	}
-	downstream, err := controller.NewDownstreamController(informers.GetInformersManager().GetKubeEdgeInformerFactory())
+	var history twinhistory.Store
+	if h := config.Config.TwinHistory; h != nil && h.Enable {
+		var err error
+		history, err = twinhistory.NewStore(h)
+		if err != nil {
+			klog.Exitf("New twin history store failed with error: %s", err)
+		}
+		// the history is queried through the https server of cloudhub
+		httpserver.AddWebService(twinhistory.NewServer(history, client.GetKubeClient()).WebService())
+	}
+	downstream, err := controller.NewDownstreamController(informers.GetInformersManager().GetKubeEdgeInformerFactory(), history)
 	if err != nil {
 		klog.Exitf("New downstream controller failed with error: %s", err)
 	}
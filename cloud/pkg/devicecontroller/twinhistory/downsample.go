@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package twinhistory
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Aggregation defines how the samples in a downsampling bucket are combined
+type Aggregation string
+
+const (
+	AggregationLast  Aggregation = "last"
+	AggregationFirst Aggregation = "first"
+	AggregationAvg   Aggregation = "avg"
+	AggregationMin   Aggregation = "min"
+	AggregationMax   Aggregation = "max"
+)
+
+// ParseAggregation validates the aggregation, empty value defaults to AggregationLast
+func ParseAggregation(s string) (Aggregation, error) {
+	switch a := Aggregation(s); a {
+	case "":
+		return AggregationLast, nil
+	case AggregationLast, AggregationFirst, AggregationAvg, AggregationMin, AggregationMax:
+		return a, nil
+	default:
+		return "", fmt.Errorf("unsupported aggregation %q", s)
+	}
+}
+
+// Downsample groups samples sorted by time into buckets of step aligned to the unix epoch
+// and combines each bucket into one sample stamped with the bucket start.
+// Numeric aggregations fall back to the last value if a bucket contains non-numeric values.
+func Downsample(samples []Sample, step time.Duration, aggregation Aggregation) []Sample {
+	if step <= 0 || len(samples) == 0 {
+		return samples
+	}
+	var result []Sample
+	begin := 0
+	for i := 1; i <= len(samples); i++ {
+		if i < len(samples) && samples[i].Timestamp.Truncate(step).Equal(samples[begin].Timestamp.Truncate(step)) {
+			continue
+		}
+		result = append(result, aggregate(samples[begin:i], step, aggregation))
+		begin = i
+	}
+	return result
+}
+
+func aggregate(bucket []Sample, step time.Duration, aggregation Aggregation) Sample {
+	last := bucket[len(bucket)-1]
+	result := Sample{Timestamp: bucket[0].Timestamp.Truncate(step), Value: last.Value, Type: last.Type}
+	switch aggregation {
+	case AggregationFirst:
+		result.Value, result.Type = bucket[0].Value, bucket[0].Type
+		return result
+	case AggregationAvg, AggregationMin, AggregationMax:
+	default:
+		return result
+	}
+
+	var sum, min, max float64
+	for i, s := range bucket {
+		v, err := strconv.ParseFloat(s.Value, 64)
+		if err != nil {
+			return result
+		}
+		if i == 0 || v < min {
+			min = v
+		}
+		if i == 0 || v > max {
+			max = v
+		}
+		sum += v
+	}
+	var v float64
+	switch aggregation {
+	case AggregationAvg:
+		v = sum / float64(len(bucket))
+	case AggregationMin:
+		v = min
+	case AggregationMax:
+		v = max
+	}
+	result.Value = strconv.FormatFloat(v, 'f', -1, 64)
+	return result
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package twinhistory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+)
+
+// MemoryStoreName is the name of the in-memory ring store
+const MemoryStoreName = "memory"
+
+func init() {
+	RegisterStore(MemoryStoreName, func(config *v1alpha1.DeviceTwinHistory) (Store, error) {
+		return NewMemoryStore(int(config.MaxSamples)), nil
+	})
+}
+
+// ring is a fixed size ring buffer of samples
+type ring struct {
+	samples []Sample
+	// next is the index the next sample is written to
+	next int
+	full bool
+}
+
+func (r *ring) add(s Sample) {
+	r.samples[r.next] = s
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// list returns the samples in insertion order
+func (r *ring) list() []Sample {
+	if !r.full {
+		return append([]Sample(nil), r.samples[:r.next]...)
+	}
+	result := make([]Sample, 0, len(r.samples))
+	result = append(result, r.samples[r.next:]...)
+	return append(result, r.samples[:r.next]...)
+}
+
+// MemoryStore keeps the latest samples of each device twin property in memory,
+// history is lost when cloudcore restarts
+type MemoryStore struct {
+	lock       sync.RWMutex
+	maxSamples int
+	// devices, key is the device, value is a map of property name to ring
+	devices map[DeviceKey]map[string]*ring
+}
+
+// NewMemoryStore creates a MemoryStore retaining at most maxSamples samples per property
+func NewMemoryStore(maxSamples int) *MemoryStore {
+	if maxSamples <= 0 {
+		maxSamples = 1
+	}
+	return &MemoryStore{
+		maxSamples: maxSamples,
+		devices:    make(map[DeviceKey]map[string]*ring),
+	}
+}
+
+func (m *MemoryStore) Append(device DeviceKey, property string, sample Sample) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	properties, ok := m.devices[device]
+	if !ok {
+		properties = make(map[string]*ring)
+		m.devices[device] = properties
+	}
+	r, ok := properties[property]
+	if !ok {
+		r = &ring{samples: make([]Sample, m.maxSamples)}
+		properties[property] = r
+	}
+	r.add(sample)
+	return nil
+}
+
+func (m *MemoryStore) Query(query Query) (map[string][]Sample, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	result := make(map[string][]Sample)
+	for property, r := range m.devices[query.Device] {
+		if query.Property != "" && query.Property != property {
+			continue
+		}
+		var samples []Sample
+		for _, s := range r.list() {
+			if query.Contains(s.Timestamp) {
+				samples = append(samples, s)
+			}
+		}
+		// edge timestamps are not guaranteed to be monotonic
+		sort.SliceStable(samples, func(i, j int) bool {
+			return samples[i].Timestamp.Before(samples[j].Timestamp)
+		})
+		result[property] = samples
+	}
+	return result, nil
+}
+
+func (m *MemoryStore) Delete(device DeviceKey) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.devices, device)
+	return nil
+}
+
+func (m *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package twinhistory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// HistoryRootPath is the root path of the twin history query API
+	HistoryRootPath = "/apis/devices/v1beta1"
+	// HistoryPath is the path of the twin history query API under HistoryRootPath
+	HistoryPath = "/namespaces/{namespace}/devices/{name}/twinhistory"
+)
+
+// Query parameters of the twin history query API
+const (
+	ParamProperty    = "property"
+	ParamStart       = "start"
+	ParamEnd         = "end"
+	ParamStep        = "step"
+	ParamAggregation = "aggregation"
+)
+
+// reviewTimeout is the timeout to authenticate and authorize a request by kube-apiserver
+const reviewTimeout = 10 * time.Second
+
+// HistoryResponse is the response of the twin history query API
+type HistoryResponse struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Properties, key is the twin property name, value is the samples sorted by time
+	Properties map[string][]Sample `json:"properties"`
+}
+
+// Server serves the twin history query API. The requests are authenticated by the bearer tokens
+// of Kubernetes users, who must be allowed to get the device whose history is queried.
+type Server struct {
+	store      Store
+	kubeClient kubernetes.Interface
+}
+
+// NewServer creates a Server querying the store, kubeClient reviews the tokens and permissions of the requests
+func NewServer(store Store, kubeClient kubernetes.Interface) *Server {
+	return &Server{store: store, kubeClient: kubeClient}
+}
+
+// WebService returns the routes of the twin history query API
+func (s *Server) WebService() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Path(HistoryRootPath).Produces(restful.MIME_JSON)
+	ws.Route(ws.GET(HistoryPath).To(s.queryHistory).
+		Param(ws.QueryParameter(ParamProperty, "twin property name, all properties if empty")).
+		Param(ws.QueryParameter(ParamStart, "start of the time range, RFC3339 or unix milliseconds")).
+		Param(ws.QueryParameter(ParamEnd, "end of the time range, RFC3339 or unix milliseconds")).
+		Param(ws.QueryParameter(ParamStep, "downsampling step, e.g. 1m")).
+		Param(ws.QueryParameter(ParamAggregation, "downsampling aggregation: last, first, avg, min or max")))
+	return ws
+}
+
+// authorize checks the bearer token of the request is allowed to get the device,
+// it returns the http status and the error if it is not
+func (s *Server) authorize(request *restful.Request, device DeviceKey) (int, error) {
+	token, ok := strings.CutPrefix(request.HeaderParameter("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return http.StatusUnauthorized, fmt.Errorf("bearer token is required")
+	}
+	ctx, cancel := context.WithTimeout(request.Request.Context(), reviewTimeout)
+	defer cancel()
+
+	tokenReview, err := s.kubeClient.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to review the token: %v", err)
+	}
+	if !tokenReview.Status.Authenticated {
+		return http.StatusUnauthorized, fmt.Errorf("invalid bearer token")
+	}
+
+	user := tokenReview.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	accessReview, err := s.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: device.Namespace,
+				Verb:      "get",
+				Group:     "devices.kubeedge.io",
+				Resource:  "devices",
+				Name:      device.Name,
+			},
+			User:   user.Username,
+			Groups: user.Groups,
+			UID:    user.UID,
+			Extra:  extra,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to review the permission: %v", err)
+	}
+	if !accessReview.Status.Allowed {
+		return http.StatusForbidden, fmt.Errorf("user %q is not allowed to get device %s", user.Username, device)
+	}
+	return http.StatusOK, nil
+}
+
+func (s *Server) queryHistory(request *restful.Request, response *restful.Response) {
+	query := Query{
+		Device: DeviceKey{
+			Namespace: request.PathParameter("namespace"),
+			Name:      request.PathParameter("name"),
+		},
+		Property: request.QueryParameter(ParamProperty),
+	}
+	if status, err := s.authorize(request, query.Device); err != nil {
+		writeError(response, status, err)
+		return
+	}
+	var err error
+	if query.Start, err = parseTime(request.QueryParameter(ParamStart)); err != nil {
+		writeError(response, http.StatusBadRequest, fmt.Errorf("invalid %s: %v", ParamStart, err))
+		return
+	}
+	if query.End, err = parseTime(request.QueryParameter(ParamEnd)); err != nil {
+		writeError(response, http.StatusBadRequest, fmt.Errorf("invalid %s: %v", ParamEnd, err))
+		return
+	}
+	var step time.Duration
+	if v := request.QueryParameter(ParamStep); v != "" {
+		if step, err = time.ParseDuration(v); err != nil || step <= 0 {
+			writeError(response, http.StatusBadRequest, fmt.Errorf("invalid %s %q", ParamStep, v))
+			return
+		}
+	}
+	aggregation, err := ParseAggregation(request.QueryParameter(ParamAggregation))
+	if err != nil {
+		writeError(response, http.StatusBadRequest, err)
+		return
+	}
+
+	properties, err := s.store.Query(query)
+	if err != nil {
+		writeError(response, http.StatusInternalServerError, err)
+		return
+	}
+	for property, samples := range properties {
+		properties[property] = Downsample(samples, step, aggregation)
+	}
+	result := HistoryResponse{
+		Namespace:  query.Device.Namespace,
+		Name:       query.Device.Name,
+		Properties: properties,
+	}
+	if err := response.WriteAsJson(result); err != nil {
+		klog.Errorf("Failed to write twin history response of device %s: %v", query.Device, err)
+	}
+}
+
+// parseTime parses RFC3339 time or unix milliseconds, empty value returns zero time
+func parseTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+func writeError(response *restful.Response, status int, err error) {
+	if werr := response.WriteErrorString(status, err.Error()); werr != nil {
+		klog.Errorf("Failed to write twin history error response: %v", werr)
+	}
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package twinhistory
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const testToken = "valid-token"
+
+// newFakeKubeClient authenticates testToken as user alice, who is allowed to get the devices if allowed is true
+func newFakeKubeClient(allowed bool) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if review.Spec.Token == testToken {
+			review.Status.Authenticated = true
+			review.Status.User = authenticationv1.UserInfo{Username: "alice"}
+		}
+		return true, review, nil
+	})
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		review.Status.Allowed = allowed && review.Spec.User == "alice" && attrs.Verb == "get" &&
+			attrs.Group == "devices.kubeedge.io" && attrs.Resource == "devices" &&
+			attrs.Namespace == testDevice.Namespace && attrs.Name == testDevice.Name
+		return true, review, nil
+	})
+	return client
+}
+
+func TestDownsample(t *testing.T) {
+	base := time.Unix(1700000040, 0)
+	samples := []Sample{
+		{Timestamp: base, Value: "1"},
+		{Timestamp: base.Add(10 * time.Second), Value: "3"},
+		{Timestamp: base.Add(70 * time.Second), Value: "5"},
+	}
+	tests := []struct {
+		aggregation Aggregation
+		want        []string
+	}{
+		{AggregationLast, []string{"3", "5"}},
+		{AggregationFirst, []string{"1", "5"}},
+		{AggregationAvg, []string{"2", "5"}},
+		{AggregationMin, []string{"1", "5"}},
+		{AggregationMax, []string{"3", "5"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.aggregation), func(t *testing.T) {
+			result := Downsample(samples, time.Minute, tt.aggregation)
+			var values []string
+			for _, s := range result {
+				if !s.Timestamp.Equal(s.Timestamp.Truncate(time.Minute)) {
+					t.Errorf("sample timestamp %v is not aligned to step", s.Timestamp)
+				}
+				values = append(values, s.Value)
+			}
+			if !reflect.DeepEqual(values, tt.want) {
+				t.Errorf("Downsample() = %v, want %v", values, tt.want)
+			}
+		})
+	}
+
+	// non-numeric values fall back to the last value
+	result := Downsample([]Sample{{Timestamp: base, Value: "on"}, {Timestamp: base, Value: "off"}}, time.Minute, AggregationAvg)
+	if len(result) != 1 || result[0].Value != "off" {
+		t.Errorf("Downsample() of non-numeric values = %v, want off", result)
+	}
+}
+
+func TestQueryHistory(t *testing.T) {
+	store := NewMemoryStore(10)
+	base := time.UnixMilli(1700000040000)
+	for i, v := range []string{"1", "3", "5"} {
+		if err := store.Append(testDevice, "temperature", Sample{Timestamp: base.Add(time.Duration(i) * 20 * time.Second), Value: v}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+	container := restful.NewContainer()
+	container.Add(NewServer(store, newFakeKubeClient(true)).WebService())
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantValues []string
+	}{
+		{
+			name:       "all samples",
+			query:      "",
+			wantStatus: http.StatusOK,
+			wantValues: []string{"1", "3", "5"},
+		},
+		{
+			name:       "time range in unix milliseconds",
+			query:      "?property=temperature&start=1700000050000",
+			wantStatus: http.StatusOK,
+			wantValues: []string{"3", "5"},
+		},
+		{
+			name:       "downsampling",
+			query:      "?step=1m&aggregation=max",
+			wantStatus: http.StatusOK,
+			wantValues: []string{"5"},
+		},
+		{
+			name:       "invalid step",
+			query:      "?step=abc",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid aggregation",
+			query:      "?aggregation=sum",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet,
+				"/apis/devices/v1beta1/namespaces/default/devices/sensor/twinhistory"+tt.query, nil)
+			req.Header.Set("Authorization", "Bearer "+testToken)
+			rec := httptest.NewRecorder()
+			container.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var resp HistoryResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			var values []string
+			for _, s := range resp.Properties["temperature"] {
+				values = append(values, s.Value)
+			}
+			if !reflect.DeepEqual(values, tt.wantValues) {
+				t.Errorf("values = %v, want %v", values, tt.wantValues)
+			}
+		})
+	}
+}
+
+func TestQueryHistoryAuthorization(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		allowed    bool
+		wantStatus int
+	}{
+		{
+			name:       "no token",
+			allowed:    true,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid token",
+			token:      "invalid-token",
+			allowed:    true,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "not allowed to get the device",
+			token:      testToken,
+			allowed:    false,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "allowed to get the device",
+			token:      testToken,
+			allowed:    true,
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			container := restful.NewContainer()
+			container.Add(NewServer(NewMemoryStore(10), newFakeKubeClient(tt.allowed)).WebService())
+			req := httptest.NewRequest(http.MethodGet, "/apis/devices/v1beta1/namespaces/default/devices/sensor/twinhistory", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			container.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d, body: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
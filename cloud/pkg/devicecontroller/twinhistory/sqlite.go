@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package twinhistory
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	// register sqlite driver
+	_ "github.com/mattn/go-sqlite3"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+)
+
+// SQLiteStoreName is the name of the sqlite store, it requires cloudcore to be built with cgo
+const SQLiteStoreName = "sqlite"
+
+const (
+	createTableSQL = `CREATE TABLE IF NOT EXISTS twin_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		namespace TEXT NOT NULL,
+		device TEXT NOT NULL,
+		property TEXT NOT NULL,
+		timestamp INTEGER NOT NULL,
+		value TEXT NOT NULL,
+		type TEXT NOT NULL DEFAULT ''
+	)`
+	createIndexSQL = `CREATE INDEX IF NOT EXISTS twin_history_device
+		ON twin_history (namespace, device, property, timestamp)`
+	insertSQL = `INSERT INTO twin_history (namespace, device, property, timestamp, value, type) VALUES (?, ?, ?, ?, ?, ?)`
+	// pruneSQL keeps the latest maxSamples rows of every device twin property
+	pruneSQL = `DELETE FROM twin_history WHERE id IN (SELECT id FROM (
+		SELECT id, ROW_NUMBER() OVER (PARTITION BY namespace, device, property ORDER BY id DESC) AS rn
+		FROM twin_history) WHERE rn > ?)`
+	deleteSQL = `DELETE FROM twin_history WHERE namespace = ? AND device = ?`
+)
+
+// sqlitePruneInterval is the interval to delete the samples beyond maxSamples from the database,
+// samples not pruned yet are hidden from queries
+const sqlitePruneInterval = time.Minute
+
+func init() {
+	RegisterStore(SQLiteStoreName, func(config *v1alpha1.DeviceTwinHistory) (Store, error) {
+		return NewSQLiteStore(config.DataSource, int(config.MaxSamples))
+	})
+}
+
+// SQLiteStore persists the history of device twins in a sqlite database
+type SQLiteStore struct {
+	db         *sql.DB
+	maxSamples int
+	stopCh     chan struct{}
+}
+
+// NewSQLiteStore opens or creates the sqlite database at dataSource
+func NewSQLiteStore(dataSource string, maxSamples int) (*SQLiteStore, error) {
+	if maxSamples <= 0 {
+		maxSamples = 1
+	}
+	if err := os.MkdirAll(filepath.Dir(dataSource), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create directory of %s: %v", dataSource, err)
+	}
+	db, err := sql.Open("sqlite3", dataSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open twin history database %s: %v", dataSource, err)
+	}
+	// sqlite does not support concurrent writers
+	db.SetMaxOpenConns(1)
+	for _, stmt := range []string{createTableSQL, createIndexSQL} {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to init twin history database %s: %v", dataSource, err)
+		}
+	}
+	s := &SQLiteStore{db: db, maxSamples: maxSamples, stopCh: make(chan struct{})}
+	go s.runPrune()
+	return s, nil
+}
+
+// runPrune deletes the samples beyond maxSamples periodically until the store is closed
+func (s *SQLiteStore) runPrune() {
+	ticker := time.NewTicker(sqlitePruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			if err := s.prune(); err != nil {
+				klog.Warningf("failed to prune twin history: %v", err)
+			}
+		}
+	}
+}
+
+func (s *SQLiteStore) prune() error {
+	_, err := s.db.Exec(pruneSQL, s.maxSamples)
+	return err
+}
+
+func (s *SQLiteStore) Append(device DeviceKey, property string, sample Sample) error {
+	_, err := s.db.Exec(insertSQL, device.Namespace, device.Name, property,
+		sample.Timestamp.UnixMilli(), sample.Value, sample.Type)
+	return err
+}
+
+func (s *SQLiteStore) Query(query Query) (map[string][]Sample, error) {
+	// the latest maxSamples rows of every property are selected, rows not pruned yet are skipped
+	inner := `SELECT property, timestamp, value, type, id,
+		ROW_NUMBER() OVER (PARTITION BY property ORDER BY id DESC) AS rn
+		FROM twin_history WHERE namespace = ? AND device = ?`
+	args := []interface{}{query.Device.Namespace, query.Device.Name}
+	if query.Property != "" {
+		inner += ` AND property = ?`
+		args = append(args, query.Property)
+	}
+	stmt := `SELECT property, timestamp, value, type FROM (` + inner + `) WHERE rn <= ?`
+	args = append(args, s.maxSamples)
+	if !query.Start.IsZero() {
+		stmt += ` AND timestamp >= ?`
+		args = append(args, query.Start.UnixMilli())
+	}
+	if !query.End.IsZero() {
+		stmt += ` AND timestamp <= ?`
+		args = append(args, query.End.UnixMilli())
+	}
+	stmt += ` ORDER BY timestamp, id`
+
+	rows, err := s.db.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string][]Sample)
+	for rows.Next() {
+		var property string
+		var timestamp int64
+		var sample Sample
+		if err := rows.Scan(&property, &timestamp, &sample.Value, &sample.Type); err != nil {
+			return nil, err
+		}
+		sample.Timestamp = time.UnixMilli(timestamp)
+		result[property] = append(result[property], sample)
+	}
+	return result, rows.Err()
+}
+
+func (s *SQLiteStore) Delete(device DeviceKey) error {
+	_, err := s.db.Exec(deleteSQL, device.Namespace, device.Name)
+	return err
+}
+
+func (s *SQLiteStore) Close() error {
+	close(s.stopCh)
+	return s.db.Close()
+}
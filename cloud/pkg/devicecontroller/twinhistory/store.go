@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package twinhistory
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+)
+
+// Sample is a reported value of a device twin property at a point in time
+type Sample struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     string    `json:"value"`
+	// Type is the data type of the value reported by the edge, e.g. int, float, string
+	Type string `json:"type,omitempty"`
+}
+
+// DeviceKey identifies a device
+type DeviceKey struct {
+	Namespace string
+	Name      string
+}
+
+func (k DeviceKey) String() string {
+	return k.Namespace + "/" + k.Name
+}
+
+// Query selects the samples of a device.
+// An empty Property selects all properties, zero Start or End leaves the time range open.
+type Query struct {
+	Device   DeviceKey
+	Property string
+	Start    time.Time
+	End      time.Time
+}
+
+// Contains returns true if t is in the time range of the query
+func (q Query) Contains(t time.Time) bool {
+	if !q.Start.IsZero() && t.Before(q.Start) {
+		return false
+	}
+	if !q.End.IsZero() && t.After(q.End) {
+		return false
+	}
+	return true
+}
+
+// Store retains a bounded history of reported twin values per device
+type Store interface {
+	// Append records a sample of the device twin property
+	Append(device DeviceKey, property string, sample Sample) error
+	// Query returns samples matched by the query sorted by time, keyed by property name
+	Query(query Query) (map[string][]Sample, error)
+	// Delete drops the whole history of the device
+	Delete(device DeviceKey) error
+	// Close releases resources held by the store
+	Close() error
+}
+
+// StoreFactory builds a Store from the twin history config
+type StoreFactory func(config *v1alpha1.DeviceTwinHistory) (Store, error)
+
+var (
+	factoriesLock sync.RWMutex
+	factories     = make(map[string]StoreFactory)
+)
+
+// RegisterStore registers a store backend, external TSDB adapters can be plugged in by
+// registering their factory before deviceController starts
+func RegisterStore(name string, factory StoreFactory) {
+	factoriesLock.Lock()
+	defer factoriesLock.Unlock()
+	factories[name] = factory
+	klog.V(4).Infof("twin history store %s registered", name)
+}
+
+// NewStore builds the store configured by the twin history config
+func NewStore(config *v1alpha1.DeviceTwinHistory) (Store, error) {
+	factoriesLock.RLock()
+	factory, ok := factories[config.Store]
+	factoriesLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("twin history store %q is not registered", config.Store)
+	}
+	return factory(config)
+}
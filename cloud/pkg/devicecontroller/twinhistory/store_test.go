@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package twinhistory
+
+import (
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var testDevice = DeviceKey{Namespace: "default", Name: "sensor"}
+
+func testStore(t *testing.T, store Store) {
+	base := time.UnixMilli(1700000000000)
+	// append out of order to check results are sorted by time
+	for _, i := range []int{0, 2, 1, 3, 4} {
+		if err := store.Append(testDevice, "temperature", Sample{Timestamp: base.Add(time.Duration(i) * time.Second), Value: strconv.Itoa(i)}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+	if err := store.Append(testDevice, "humidity", Sample{Timestamp: base, Value: "50"}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	// only the latest 3 samples of temperature are retained
+	result, err := store.Query(Query{Device: testDevice, Property: "temperature"})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("Query() returned %d properties, want 1", len(result))
+	}
+	var values []string
+	for _, s := range result["temperature"] {
+		values = append(values, s.Value)
+	}
+	if want := []string{"1", "3", "4"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("Query() values = %v, want %v", values, want)
+	}
+
+	result, err = store.Query(Query{Device: testDevice, Start: base.Add(3 * time.Second), End: base.Add(3 * time.Second)})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(result["temperature"]) != 1 || result["temperature"][0].Value != "3" {
+		t.Errorf("Query() with time range = %v, want single sample 3", result["temperature"])
+	}
+	if len(result["humidity"]) != 0 {
+		t.Errorf("Query() with time range returned humidity samples %v", result["humidity"])
+	}
+
+	if err := store.Delete(testDevice); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	result, err = store.Query(Query{Device: testDevice})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(result) != 0 {
+		t.Errorf("Query() after Delete() = %v, want empty", result)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore(3))
+}
+
+func TestSQLiteStore(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "twinhistory.db"), 3)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer store.Close()
+	testStore(t, store)
+}
+
+func TestSQLiteStorePrune(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "twinhistory.db"), 3)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer store.Close()
+	base := time.UnixMilli(1700000000000)
+	for i := 0; i < 5; i++ {
+		for _, property := range []string{"temperature", "humidity"} {
+			if err := store.Append(testDevice, property, Sample{Timestamp: base.Add(time.Duration(i) * time.Second), Value: strconv.Itoa(i)}); err != nil {
+				t.Fatalf("Append() error = %v", err)
+			}
+		}
+	}
+	if err := store.prune(); err != nil {
+		t.Fatalf("prune() error = %v", err)
+	}
+	var count int
+	if err := store.db.QueryRow(`SELECT COUNT(*) FROM twin_history`).Scan(&count); err != nil {
+		t.Fatalf("failed to count rows: %v", err)
+	}
+	if count != 6 {
+		t.Errorf("rows after prune() = %d, want 6", count)
+	}
+	result, err := store.Query(Query{Device: testDevice, Property: "humidity"})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	var values []string
+	for _, s := range result["humidity"] {
+		values = append(values, s.Value)
+	}
+	if want := []string{"2", "3", "4"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("Query() values after prune() = %v, want %v", values, want)
+	}
+}
@@ -7,6 +7,7 @@ type Device struct {
 	Description    string              `json:"description,omitempty"`
 	State          string              `json:"state,omitempty"`
 	LastOnlineTime string              `json:"lastOnlineTime,omitempty"`
+	Reason         string              `json:"reason,omitempty"`
 	Attributes     map[string]*MsgAttr `json:"attributes,omitempty"`
 	Twin           map[string]*MsgTwin `json:"twin,omitempty"`
 }
@@ -107,8 +108,36 @@ type DeviceTwinUpdate struct {
 	Twin map[string]*MsgTwin `json:"twin"`
 }
 
+// TwinHistorySample the struct of a reported twin value at a point in time
+type TwinHistorySample struct {
+	Value     string `json:"value"`
+	Type      string `json:"type,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// DeviceTwinHistory the struct of twin values buffered by edge while disconnected from cloud
+type DeviceTwinHistory struct {
+	BaseMessage
+	Twin map[string][]TwinHistorySample `json:"twin"`
+}
+
 // DeviceStateUpdate the struct of device state update
 type DeviceStateUpdate struct {
 	BaseMessage
 	Device Device
 }
+
+// MapperStatus the struct of the liveness and health of a mapper tracked by edge
+type MapperStatus struct {
+	Name              string `json:"name"`
+	Protocol          string `json:"protocol,omitempty"`
+	State             string `json:"state"`
+	Reason            string `json:"reason,omitempty"`
+	LastHeartbeatTime int64  `json:"lastHeartbeatTime,omitempty"`
+}
+
+// MapperStatusUpdate the struct of the status of all mappers on an edge node
+type MapperStatusUpdate struct {
+	BaseMessage
+	Mappers []MapperStatus `json:"mappers"`
+}
@@ -7,6 +7,8 @@ const (
 	ServicebusProvider string = "servicebus"
 	TargetURL          string = "target_url"
 	NodeName           string = "node_name"
+	NodeGroup          string = "node_group"
+	NodeSelector       string = "node_selector"
 	Topic              string = "topic"
 	Path               string = "path"
 	Resource           string = "resource"
@@ -0,0 +1,200 @@
+package edgenodes
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/nodegroup"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/constants"
+)
+
+var (
+	nodeLister corelisters.NodeLister
+
+	watchersLock  sync.Mutex
+	watchers      = make(map[int]func())
+	nextWatcherID int
+)
+
+// InitNodeLister sets the edge node informer used to resolve node groups and node selectors.
+// Watchers are notified when edge nodes are added, deleted or relabeled.
+func InitNodeLister(informer cache.SharedIndexInformer) {
+	nodeLister = corelisters.NewNodeLister(informer.GetIndexer())
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(interface{}) { notifyWatchers() },
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNode, ok := oldObj.(*v1.Node)
+			if !ok {
+				return
+			}
+			newNode, ok := newObj.(*v1.Node)
+			if !ok {
+				return
+			}
+			// node group membership is kept in node labels as well
+			if !reflect.DeepEqual(oldNode.Labels, newNode.Labels) {
+				notifyWatchers()
+			}
+		},
+		DeleteFunc: func(interface{}) { notifyWatchers() },
+	})
+	if err != nil {
+		klog.Errorf("failed to add edge node event handler: %v", err)
+	}
+}
+
+// Watch registers fn to be called whenever the set of edge nodes or their labels change,
+// so that selectors can be resolved again. The returned function removes the registration.
+func Watch(fn func()) (cancel func()) {
+	watchersLock.Lock()
+	defer watchersLock.Unlock()
+	id := nextWatcherID
+	nextWatcherID++
+	watchers[id] = fn
+	return func() {
+		watchersLock.Lock()
+		defer watchersLock.Unlock()
+		delete(watchers, id)
+	}
+}
+
+func notifyWatchers() {
+	watchersLock.Lock()
+	fns := make([]func(), 0, len(watchers))
+	for _, fn := range watchers {
+		fns = append(fns, fn)
+	}
+	watchersLock.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// Selector describes the edge nodes addressed by the resource of a rule endpoint.
+// NodeNames, NodeGroup and LabelSelector are ORed together.
+type Selector struct {
+	NodeNames     []string
+	NodeGroup     string
+	LabelSelector labels.Selector
+}
+
+// ParseSelector builds a Selector from "node_name", "node_group" and "node_selector"
+// attributes of a rule resource. "node_name" may contain a comma separated list of nodes,
+// "node_selector" uses the kubernetes label selector syntax.
+// It returns nil if the resource does not address any edge node.
+func ParseSelector(resource map[string]string) (*Selector, error) {
+	s := &Selector{}
+	if names, ok := resource[constants.NodeName]; ok {
+		for _, name := range strings.Split(names, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				s.NodeNames = append(s.NodeNames, name)
+			}
+		}
+	}
+	s.NodeGroup = strings.TrimSpace(resource[constants.NodeGroup])
+	if expr, ok := resource[constants.NodeSelector]; ok && strings.TrimSpace(expr) != "" {
+		selector, err := labels.Parse(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", constants.NodeSelector, expr, err)
+		}
+		s.LabelSelector = selector
+	}
+	if s.Empty() {
+		return nil, nil
+	}
+	return s, nil
+}
+
+// Empty returns true if the selector does not address any edge node
+func (s *Selector) Empty() bool {
+	return s == nil || (len(s.NodeNames) == 0 && s.NodeGroup == "" && s.LabelSelector == nil)
+}
+
+// IsSingleNode returns true if the selector addresses exactly one node by name
+func (s *Selector) IsSingleNode() bool {
+	return s != nil && len(s.NodeNames) == 1 && s.NodeGroup == "" && s.LabelSelector == nil
+}
+
+// Resolve returns the sorted names of edge nodes addressed by the selector.
+// Explicit node names are returned as is, node groups and label selectors are
+// resolved against the edge node cache.
+func Resolve(s *Selector) ([]string, error) {
+	if s.Empty() {
+		return nil, nil
+	}
+	result := sets.New[string](s.NodeNames...)
+	if s.NodeGroup != "" || s.LabelSelector != nil {
+		if nodeLister == nil {
+			return nil, fmt.Errorf("edge node lister is not initialized")
+		}
+		if s.NodeGroup != "" {
+			selector := labels.SelectorFromSet(labels.Set{nodegroup.LabelBelongingTo: s.NodeGroup})
+			nodes, err := nodeLister.List(selector)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list nodes of nodegroup %s: %v", s.NodeGroup, err)
+			}
+			for _, node := range nodes {
+				result.Insert(node.Name)
+			}
+		}
+		if s.LabelSelector != nil {
+			nodes, err := nodeLister.List(s.LabelSelector)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list nodes by selector %s: %v", s.LabelSelector.String(), err)
+			}
+			for _, node := range nodes {
+				result.Insert(node.Name)
+			}
+		}
+	}
+	nodeNames := result.UnsortedList()
+	sort.Strings(nodeNames)
+	return nodeNames, nil
+}
+
+// TargetNodes returns the nodes a message should be delivered to. If the target selector is
+// empty the message goes to requestedNode only, which may be the source node of the message,
+// e.g. the rules from eventbus to eventbus of the same node. Otherwise it is fanned out to every
+// node addressed by the selector except sourceNode, so that an edge node is never relayed its
+// own message back by a fan-out.
+func TargetNodes(s *Selector, requestedNode, sourceNode string) ([]string, error) {
+	if s.Empty() {
+		if requestedNode == "" {
+			return nil, fmt.Errorf("no target node specified")
+		}
+		return []string{requestedNode}, nil
+	}
+	nodeNames, err := Resolve(s)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]string, 0, len(nodeNames))
+	for _, name := range nodeNames {
+		if name != sourceNode {
+			targets = append(targets, name)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no edge node matches the target")
+	}
+	return targets, nil
+}
+
+// NodeNameFromResource returns the node name of an edge message resource like "node/{nodeName}/..."
+func NodeNameFromResource(resource string) string {
+	parts := strings.Split(resource, "/")
+	if len(parts) < 2 || parts[0] != "node" {
+		return ""
+	}
+	return parts[1]
+}
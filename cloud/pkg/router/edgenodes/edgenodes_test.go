@@ -0,0 +1,147 @@
+package edgenodes
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/nodegroup"
+)
+
+func newNode(name string, labels map[string]string) *v1.Node {
+	return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func initFakeNodes(t *testing.T, nodes ...*v1.Node) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, node := range nodes {
+		if err := indexer.Add(node); err != nil {
+			t.Fatalf("failed to add node: %v", err)
+		}
+	}
+	nodeLister = corelisters.NewNodeLister(indexer)
+}
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		name      string
+		resource  map[string]string
+		wantNil   bool
+		wantNames []string
+		wantErr   bool
+	}{
+		{
+			name:     "no node attributes",
+			resource: map[string]string{"topic": "a"},
+			wantNil:  true,
+		},
+		{
+			name:      "comma separated node names",
+			resource:  map[string]string{"node_name": "node1, node2,"},
+			wantNames: []string{"node1", "node2"},
+		},
+		{
+			name:     "invalid node selector",
+			resource: map[string]string{"node_selector": "a in (b"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseSelector(tt.resource)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (s == nil) != tt.wantNil {
+				t.Fatalf("ParseSelector() = %v, wantNil %v", s, tt.wantNil)
+			}
+			if s != nil && !reflect.DeepEqual(s.NodeNames, tt.wantNames) {
+				t.Errorf("ParseSelector() node names = %v, want %v", s.NodeNames, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestTargetNodes(t *testing.T) {
+	initFakeNodes(t,
+		newNode("node1", map[string]string{nodegroup.LabelBelongingTo: "group1", "line": "a"}),
+		newNode("node2", map[string]string{nodegroup.LabelBelongingTo: "group1"}),
+		newNode("node3", map[string]string{nodegroup.LabelBelongingTo: "group2", "line": "a"}),
+	)
+
+	tests := []struct {
+		name          string
+		resource      map[string]string
+		requestedNode string
+		sourceNode    string
+		want          []string
+		wantErr       bool
+	}{
+		{
+			name:          "no selector uses requested node",
+			resource:      map[string]string{},
+			requestedNode: "node1",
+			want:          []string{"node1"},
+		},
+		{
+			name:          "no selector delivers to the source node",
+			resource:      map[string]string{},
+			requestedNode: "node1",
+			sourceNode:    "node1",
+			want:          []string{"node1"},
+		},
+		{
+			name:     "no selector and no requested node",
+			resource: map[string]string{},
+			wantErr:  true,
+		},
+		{
+			name:       "node group excludes source node",
+			resource:   map[string]string{"node_group": "group1"},
+			sourceNode: "node1",
+			want:       []string{"node2"},
+		},
+		{
+			name:     "node group and node selector are merged",
+			resource: map[string]string{"node_group": "group1", "node_selector": "line=a"},
+			want:     []string{"node1", "node2", "node3"},
+		},
+		{
+			name:       "only source node matches",
+			resource:   map[string]string{"node_name": "node3"},
+			sourceNode: "node3",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseSelector(tt.resource)
+			if err != nil {
+				t.Fatalf("ParseSelector() error = %v", err)
+			}
+			got, err := TargetNodes(s, tt.requestedNode, tt.sourceNode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TargetNodes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TargetNodes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeNameFromResource(t *testing.T) {
+	if got := NodeNameFromResource("node/edge-node/default/topic"); got != "edge-node" {
+		t.Errorf("NodeNameFromResource() = %s, want edge-node", got)
+	}
+	if got := NodeNameFromResource("rule/default/test"); got != "" {
+		t.Errorf("NodeNameFromResource() = %s, want empty", got)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"path"
 	"strings"
+	"sync"
 
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	v1 "github.com/kubeedge/api/apis/rules/v1"
@@ -13,6 +16,7 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/constants"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/edgenodes"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/listener"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/provider"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/message"
@@ -27,8 +31,18 @@ type eventbusFactory struct{}
 type EventBus struct {
 	pubTopic  string
 	subTopic  string
-	nodeName  string
 	namespace string
+	// sourceNodes addresses the edge nodes whose messages are subscribed, it is
+	// resolved again whenever edge nodes join, leave or are relabeled
+	sourceNodes *edgenodes.Selector
+	// targetNodes is set when the target eventbus addresses edge nodes itself,
+	// messages are fanned out to all of them
+	targetNodes *edgenodes.Selector
+
+	lock        sync.Mutex
+	handle      listener.Handle
+	subscribed  sets.Set[string]
+	stopWatcher func()
 }
 
 func init() {
@@ -47,35 +61,84 @@ func (factory *eventbusFactory) GetSource(ep *v1.RuleEndpoint, sourceResource ma
 		klog.Errorf("source resource attributes \"topic\" does not exist")
 		return nil
 	}
-	nodeName, exist := sourceResource[constants.NodeName]
-	if !exist {
-		klog.Errorf("source resource attributes \"node_name\" does not exist")
+	selector, err := edgenodes.ParseSelector(sourceResource)
+	if err != nil {
+		klog.Errorf("invalid source resource: %v", err)
+		return nil
+	}
+	if selector.Empty() {
+		klog.Errorf("source resource attributes \"node_name\" or \"node_group\" does not exist")
 		return nil
 	}
 	cli := &EventBus{
-		subTopic:  subTopic,
-		namespace: ep.Namespace,
-		nodeName:  nodeName,
+		subTopic:    subTopic,
+		namespace:   ep.Namespace,
+		sourceNodes: selector,
 	}
 
 	return cli
 }
 
 func (eb *EventBus) RegisterListener(handle listener.Handle) error {
-	listener.MessageHandlerInstance.AddListener(path.Join("bus/node", eb.nodeName, eb.namespace, eb.subTopic), handle)
-	msg := model.NewMessage("")
-	msg.SetResourceOperation(path.Join("node", eb.nodeName, eb.namespace, eb.subTopic), message.OperationSubscribe)
-	msg.SetRoute(modules.RouterSourceEventBus, modules.UserGroup)
-	beehiveContext.Send(modules.CloudHubModuleName, *msg)
+	nodeNames, err := edgenodes.Resolve(eb.sourceNodes)
+	if err != nil {
+		return fmt.Errorf("failed to resolve source nodes: %v", err)
+	}
+	eb.lock.Lock()
+	eb.handle = handle
+	eb.subscribed = sets.New[string]()
+	eb.syncListeners(nodeNames)
+	eb.lock.Unlock()
+
+	// nodes joining the node group or starting to match the selector later are subscribed as well
+	eb.stopWatcher = edgenodes.Watch(eb.resync)
 	return nil
 }
 
 func (eb *EventBus) UnregisterListener() {
-	msg := model.NewMessage("")
-	msg.SetResourceOperation(path.Join("node", eb.nodeName, eb.namespace, eb.subTopic), message.OperationUnsubscribe)
-	msg.SetRoute(modules.RouterSourceEventBus, modules.UserGroup)
-	beehiveContext.Send(modules.CloudHubModuleName, *msg)
-	listener.MessageHandlerInstance.RemoveListener(path.Join("bus/node", eb.nodeName, eb.namespace, eb.subTopic))
+	if eb.stopWatcher != nil {
+		eb.stopWatcher()
+	}
+	eb.lock.Lock()
+	defer eb.lock.Unlock()
+	eb.syncListeners(nil)
+	eb.handle = nil
+}
+
+// resync resolves the source nodes again and updates the subscriptions accordingly
+func (eb *EventBus) resync() {
+	nodeNames, err := edgenodes.Resolve(eb.sourceNodes)
+	if err != nil {
+		klog.Errorf("failed to resolve source nodes of topic %s: %v", eb.subTopic, err)
+		return
+	}
+	eb.lock.Lock()
+	defer eb.lock.Unlock()
+	if eb.handle == nil {
+		return
+	}
+	eb.syncListeners(nodeNames)
+}
+
+// syncListeners subscribes the topic on nodeNames and unsubscribes it on the other nodes,
+// it must be called with eb.lock held
+func (eb *EventBus) syncListeners(nodeNames []string) {
+	desired := sets.New[string](nodeNames...)
+	for _, nodeName := range sets.List(desired.Difference(eb.subscribed)) {
+		listener.MessageHandlerInstance.AddListener(path.Join("bus/node", nodeName, eb.namespace, eb.subTopic), eb.handle)
+		msg := model.NewMessage("")
+		msg.SetResourceOperation(path.Join("node", nodeName, eb.namespace, eb.subTopic), message.OperationSubscribe)
+		msg.SetRoute(modules.RouterSourceEventBus, modules.UserGroup)
+		beehiveContext.Send(modules.CloudHubModuleName, *msg)
+	}
+	for _, nodeName := range sets.List(eb.subscribed.Difference(desired)) {
+		msg := model.NewMessage("")
+		msg.SetResourceOperation(path.Join("node", nodeName, eb.namespace, eb.subTopic), message.OperationUnsubscribe)
+		msg.SetRoute(modules.RouterSourceEventBus, modules.UserGroup)
+		beehiveContext.Send(modules.CloudHubModuleName, *msg)
+		listener.MessageHandlerInstance.RemoveListener(path.Join("bus/node", nodeName, eb.namespace, eb.subTopic))
+	}
+	eb.subscribed = desired
 }
 
 func (factory *eventbusFactory) GetTarget(ep *v1.RuleEndpoint, targetResource map[string]string) provider.Target {
@@ -84,9 +147,15 @@ func (factory *eventbusFactory) GetTarget(ep *v1.RuleEndpoint, targetResource ma
 		klog.Errorf("target resource attributes \"topic\" does not exist")
 		return nil
 	}
+	selector, err := edgenodes.ParseSelector(targetResource)
+	if err != nil {
+		klog.Errorf("invalid target resource: %v", err)
+		return nil
+	}
 	cli := &EventBus{
-		pubTopic:  pubTopic,
-		namespace: ep.Namespace,
+		pubTopic:    pubTopic,
+		namespace:   ep.Namespace,
+		targetNodes: selector,
 	}
 	return cli
 }
@@ -107,6 +176,10 @@ func (*EventBus) Forward(target provider.Target, data interface{}) (response int
 		klog.Errorf("get message %s content err: %v", message.GetID(), err)
 		return nil, fmt.Errorf("get message %s content err: %v", message.GetID(), err)
 	}
+	sourceNode := edgenodes.NodeNameFromResource(message.GetResource())
+	res["messageID"] = message.GetID()
+	res["nodeName"] = sourceNode
+	res["sourceNodeName"] = sourceNode
 	res["data"] = content
 	resp, err := target.GoToTarget(res, nil)
 	if err != nil {
@@ -133,26 +206,45 @@ func (eb *EventBus) GoToTarget(data map[string]interface{}, _ chan struct{}) (in
 	// use zero value if not found param
 	param, _ := data["param"].(string)
 
-	msg := model.NewMessage("")
-	msg.BuildHeader(messageID, "", msg.GetTimestamp())
-	resource := "node/" + nodeName + "/"
-	if param == "" {
-		resource = resource + eb.pubTopic
-	} else {
-		resource = resource + strings.TrimSuffix(eb.pubTopic, "/") + "/" + strings.TrimPrefix(param, "/")
-	}
-	msg.SetResourceOperation(resource, publishOperation)
-	msg.FillBody(string(body))
-	msg.SetRoute(modules.RouterSourceEventBus, modules.UserGroup)
+	// use zero value if message does not come from an edge node
+	sourceNode, _ := data["sourceNodeName"].(string)
 
+	nodeNames, err := edgenodes.TargetNodes(eb.targetNodes, nodeName, sourceNode)
+	if err != nil {
+		return nil, err
+	}
 	sessionMgr, err := cloudhub.GetSessionManager()
 	if err != nil {
 		return nil, err
 	}
-	if _, exists := sessionMgr.GetSession(nodeName); !exists {
-		return nil, fmt.Errorf("cloudcore doesn't have session for node:%s", nodeName)
+
+	resource := eb.pubTopic
+	if param != "" {
+		resource = strings.TrimSuffix(eb.pubTopic, "/") + "/" + strings.TrimPrefix(param, "/")
+	}
+	var errs []error
+	for _, target := range nodeNames {
+		if _, exists := sessionMgr.GetSession(target); !exists {
+			errs = append(errs, fmt.Errorf("cloudcore doesn't have session for node:%s", target))
+			continue
+		}
+		// copies of a fanned out message keep the original message id as parent id,
+		// so that responses can be correlated with it
+		msg := model.NewMessage(messageID)
+		if len(nodeNames) == 1 {
+			msg.BuildHeader(messageID, "", msg.GetTimestamp())
+		}
+		msg.SetResourceOperation("node/"+target+"/"+resource, publishOperation)
+		msg.FillBody(string(body))
+		msg.SetRoute(modules.RouterSourceEventBus, modules.UserGroup)
+		beehiveContext.Send(modules.CloudHubModuleName, *msg)
+	}
+	if len(errs) == len(nodeNames) {
+		return nil, utilerrors.NewAggregate(errs)
+	}
+	for _, err := range errs {
+		klog.Warningf("message %s is not delivered: %v", messageID, err)
 	}
-	beehiveContext.Send(modules.CloudHubModuleName, *msg)
 	return nil, nil
 }
 
@@ -1,9 +1,27 @@
 package eventbus
 
 import (
+	"context"
 	"fmt"
 	"path"
 	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	v1 "github.com/kubeedge/api/apis/rules/v1"
+	"github.com/kubeedge/beehive/pkg/common"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/controllermanager/nodegroup"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/constants"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/edgenodes"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/message"
 )
 
 func TestPathJoin(t *testing.T) {
@@ -20,3 +38,65 @@ func TestPathJoin(t *testing.T) {
 		t.Fatalf("expected: %s, actual: %s", s1, s2)
 	}
 }
+
+func TestSourceResolvesNewNodes(t *testing.T) {
+	beehiveContext.InitContext([]string{common.MsgCtxTypeChannel})
+	beehiveContext.AddModule(&common.ModuleInfo{
+		ModuleName: modules.CloudHubModuleName,
+		ModuleType: common.MsgCtxTypeChannel,
+	})
+
+	client := fake.NewSimpleClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: "node1", Labels: map[string]string{nodegroup.LabelBelongingTo: "group1"}}})
+	factory := informers.NewSharedInformerFactory(client, 0)
+	informer := factory.Core().V1().Nodes().Informer()
+	edgenodes.InitNodeLister(informer)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	factory.Start(stopCh)
+	cache.WaitForCacheSync(stopCh, informer.HasSynced)
+
+	source := (&eventbusFactory{}).GetSource(&v1.RuleEndpoint{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}},
+		map[string]string{constants.Topic: "temperature", constants.NodeGroup: "group1"})
+	if source == nil {
+		t.Fatal("GetSource() returned nil")
+	}
+	if err := source.RegisterListener(func(interface{}) (interface{}, error) { return nil, nil }); err != nil {
+		t.Fatalf("RegisterListener() error = %v", err)
+	}
+	defer source.UnregisterListener()
+	expectMessage(t, "node/node1/default/temperature", message.OperationSubscribe)
+
+	// node2 joins the node group after the rule is created
+	_, err := client.CoreV1().Nodes().Create(context.TODO(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: "node2", Labels: map[string]string{nodegroup.LabelBelongingTo: "group1"}}}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	expectMessage(t, "node/node2/default/temperature", message.OperationSubscribe)
+
+	// node1 leaves the node group
+	if err := client.CoreV1().Nodes().Delete(context.TODO(), "node1", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete node: %v", err)
+	}
+	expectMessage(t, "node/node1/default/temperature", message.OperationUnsubscribe)
+}
+
+func expectMessage(t *testing.T, resource, operation string) {
+	t.Helper()
+	ch := make(chan model.Message, 1)
+	go func() {
+		msg, err := beehiveContext.Receive(modules.CloudHubModuleName)
+		if err == nil {
+			ch <- msg
+		}
+	}()
+	select {
+	case msg := <-ch:
+		if msg.GetResource() != resource || msg.GetOperation() != operation {
+			t.Fatalf("got message %s %s, want %s %s", msg.GetOperation(), msg.GetResource(), operation, resource)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %s %s", operation, resource)
+	}
+}
@@ -1,13 +1,13 @@
 package servicebus
 
 import (
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"path"
 	"strings"
 
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 
 	v1 "github.com/kubeedge/api/apis/rules/v1"
@@ -16,6 +16,7 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/constants"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/edgenodes"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/listener"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/provider"
 	commonconstants "github.com/kubeedge/kubeedge/common/constants"
@@ -29,6 +30,9 @@ type ServiceBus struct {
 	servicePort string
 	nodeName    string
 	TargetURL   string
+	// targetNodes is set when the target servicebus addresses edge nodes itself,
+	// requests are fanned out to all of them
+	targetNodes *edgenodes.Selector
 }
 
 func init() {
@@ -114,9 +118,15 @@ func (sf *servicebusFactory) GetTarget(ep *v1.RuleEndpoint, targetResource map[s
 		klog.Errorf("target resource attributes \"targetPath\" does not exist")
 		return nil
 	}
+	selector, err := edgenodes.ParseSelector(targetResource)
+	if err != nil {
+		klog.Errorf("invalid target resource: %v", err)
+		return nil
+	}
 	cli := &ServiceBus{
 		targetPath:  targetPath,
 		servicePort: ep.Spec.Properties["service_port"],
+		targetNodes: selector,
 	}
 	return cli
 }
@@ -124,34 +134,72 @@ func (sf *servicebusFactory) GetTarget(ep *v1.RuleEndpoint, targetResource map[s
 func (sb *ServiceBus) GoToTarget(data map[string]interface{}, stop chan struct{}) (interface{}, error) {
 	var response *model.Message
 	messageID, ok := data["messageID"].(string)
-	param, ok := data["param"].(string)
+	if !ok {
+		return nil, buildAndLogError("messageID")
+	}
 	nodeName, ok := data["nodeName"].(string)
+	if !ok {
+		return nil, buildAndLogError("nodeName")
+	}
 	request := commonType.HTTPRequest{}
-	request.Method, ok = data["method"].(string)
-	request.Header, ok = data["header"].(http.Header)
 	request.Body, ok = data["data"].([]byte)
 	if !ok {
-		err := errors.New("data transform failed")
-		klog.Error(err.Error())
+		return nil, buildAndLogError("data body")
+	}
+	// use zero value if not found param
+	param, _ := data["param"].(string)
+	// messages from edge eventbus carry no http method and header
+	request.Header, _ = data["header"].(http.Header)
+	request.Method, _ = data["method"].(string)
+	if request.Method == "" {
+		request.Method = http.MethodPost
+	}
+	// use zero value if message does not come from an edge node
+	sourceNode, _ := data["sourceNodeName"].(string)
+
+	nodeNames, err := edgenodes.TargetNodes(sb.targetNodes, nodeName, sourceNode)
+	if err != nil {
 		return nil, err
 	}
+	sessionMgr, err := cloudhub.GetSessionManager()
+	if err != nil {
+		return nil, err
+	}
+
+	targetPath := sb.targetPath
+	if param != "" {
+		targetPath = strings.TrimSuffix(sb.targetPath, "/") + "/" + strings.TrimPrefix(param, "/")
+	}
+	if len(nodeNames) > 1 {
+		// responses of fanned out requests are not collected
+		var errs []error
+		for _, target := range nodeNames {
+			if _, exists := sessionMgr.GetSession(target); !exists {
+				errs = append(errs, fmt.Errorf("cloudcore doesn't have session for node:%s", target))
+				continue
+			}
+			msg := model.NewMessage("")
+			msg.SetResourceOperation("node/"+target+"/"+sb.servicePort+":"+targetPath, request.Method)
+			msg.FillBody(request)
+			msg.SetRoute(modules.RouterSourceServiceBus, modules.UserGroup)
+			beehiveContext.Send(modules.CloudHubModuleName, *msg)
+		}
+		if len(errs) == len(nodeNames) {
+			return nil, utilerrors.NewAggregate(errs)
+		}
+		for _, err := range errs {
+			klog.Warningf("message %s is not delivered: %v", messageID, err)
+		}
+		return nil, nil
+	}
 
+	nodeName = nodeNames[0]
 	msg := model.NewMessage("")
 	msg.BuildHeader(messageID, "", msg.GetTimestamp())
-	resource := "node/" + nodeName + "/" + sb.servicePort + ":"
-	if !ok || param == "" {
-		resource = resource + sb.targetPath
-	} else {
-		resource = resource + strings.TrimSuffix(sb.targetPath, "/") + "/" + strings.TrimPrefix(param, "/")
-	}
-	msg.SetResourceOperation(resource, request.Method)
+	msg.SetResourceOperation("node/"+nodeName+"/"+sb.servicePort+":"+targetPath, request.Method)
 	msg.FillBody(request)
 	msg.SetRoute(modules.RouterSourceServiceBus, modules.UserGroup)
 
-	sessionMgr, err := cloudhub.GetSessionManager()
-	if err != nil {
-		return nil, err
-	}
 	if _, exists := sessionMgr.GetSession(nodeName); !exists {
 		return nil, fmt.Errorf("cloudcore doesn't have session for node:%s", nodeName)
 	}
@@ -166,3 +214,9 @@ func (sb *ServiceBus) GoToTarget(data map[string]interface{}, stop chan struct{}
 	}
 	return response, nil
 }
+
+func buildAndLogError(key string) error {
+	err := fmt.Errorf("data transform failed, %s type is not matched or value is nil", key)
+	klog.Error(err.Error())
+	return err
+}
@@ -5,8 +5,10 @@ import (
 
 	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
 	"github.com/kubeedge/beehive/pkg/core"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
 	routerconfig "github.com/kubeedge/kubeedge/cloud/pkg/router/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/router/edgenodes"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router/listener"
 	// init eventbus
 	_ "github.com/kubeedge/kubeedge/cloud/pkg/router/provider/eventbus"
@@ -32,6 +34,10 @@ func newRouter(enable bool) *router {
 
 func Register(router *v1alpha1.Router) {
 	routerconfig.InitConfigure(router)
+	if router.Enable {
+		// edge nodes are used to resolve node groups and node selectors of rules
+		edgenodes.InitNodeLister(informers.GetInformersManager().EdgeNode())
+	}
 	core.Register(newRouter(router.Enable))
 }
 
@@ -17,6 +17,9 @@ import (
 var (
 	rules         sync.Map
 	ruleEndpoints sync.Map
+	// ruleSources stores the sources registered for rules, sources addressing node groups
+	// must be unregistered from the same nodes they were registered to
+	ruleSources sync.Map
 )
 
 func init() {
@@ -132,6 +135,7 @@ func addRule(rule *routerv1.Rule) error {
 	}
 
 	rules.Store(ruleKey, rule)
+	ruleSources.Store(ruleKey, source)
 	klog.Infof("add rule success: %+v", rule)
 	return nil
 }
@@ -146,7 +150,13 @@ func delRule(namespace, name string) {
 	}
 	rule := v.(*routerv1.Rule)
 
-	source, err := getSourceOfRule(rule)
+	var source provider.Source
+	var err error
+	if s, ok := ruleSources.LoadAndDelete(ruleKey); ok {
+		source = s.(provider.Source)
+	} else {
+		source, err = getSourceOfRule(rule)
+	}
 	// if source not exist, skip UnregisterListener
 	if err == nil {
 		klog.V(4).Infof("delRule: source of rule:%s exist, do UnregisterListener", rule.Spec.Source)
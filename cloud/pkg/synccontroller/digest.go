@@ -0,0 +1,268 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package synccontroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	edgectrconst "github.com/kubeedge/kubeedge/cloud/pkg/edgecontroller/constants"
+	commonconst "github.com/kubeedge/kubeedge/common/constants"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+)
+
+// digestResources are the resource types whose digests are compared with the cluster
+var digestResources = map[string]schema.GroupVersionResource{
+	model.ResourceTypeConfigmap: {Version: "v1", Resource: "configmaps"},
+	model.ResourceTypeSecret:    {Version: "v1", Resource: "secrets"},
+}
+
+// nodeDigests keeps the last full digests of the edge nodes, the digests carrying only
+// the checksums are compared with them
+type nodeDigests struct {
+	lock    sync.RWMutex
+	digests map[string]map[string]commontypes.ResourceDigest
+}
+
+func newNodeDigests() *nodeDigests {
+	return &nodeDigests{
+		digests: make(map[string]map[string]commontypes.ResourceDigest),
+	}
+}
+
+func (d *nodeDigests) get(nodeID string) map[string]commontypes.ResourceDigest {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.digests[nodeID]
+}
+
+func (d *nodeDigests) set(nodeID string, digests map[string]commontypes.ResourceDigest) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.digests[nodeID] = digests
+}
+
+func (d *nodeDigests) delete(nodeID string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	delete(d.digests, nodeID)
+}
+
+// receiveDigests receives the digests sent by the edge nodes and replies with the results
+func (sctl *SyncController) receiveDigests() {
+	for {
+		select {
+		case <-beehiveContext.Done():
+			klog.Info("stop receiving digests")
+			return
+		default:
+		}
+		msg, err := sctl.messageLayer.Receive()
+		if err != nil {
+			klog.Warningf("receive message failed, %v", err)
+			continue
+		}
+		if !strings.HasSuffix(msg.GetResource(), commonconst.ResourceTypeDigest) {
+			klog.Warningf("synccontroller does not support message resource %s", msg.GetResource())
+			continue
+		}
+		sctl.handleDigest(msg)
+	}
+}
+
+func (sctl *SyncController) handleDigest(msg model.Message) {
+	nodeID, err := messagelayer.GetNodeID(msg)
+	if err != nil {
+		klog.Warningf("failed to get node of digest message %s: %v", msg.GetID(), err)
+		return
+	}
+	data, err := msg.GetContentData()
+	if err != nil {
+		klog.Warningf("failed to get content of digest message from node %s: %v", nodeID, err)
+		return
+	}
+	var digest commontypes.MetaDigest
+	if err := json.Unmarshal(data, &digest); err != nil {
+		klog.Warningf("failed to unmarshal digest from node %s: %v", nodeID, err)
+		return
+	}
+
+	result := sctl.compareDigest(nodeID, &digest)
+	if result.Missing+result.Stale+result.Extra > 0 {
+		klog.Infof("node %s drifted from cluster, missing: %d, stale: %d, extra: %d",
+			nodeID, result.Missing, result.Stale, result.Extra)
+	}
+
+	resource := fmt.Sprintf("%s/%s/%s", messagelayer.ResourceNode, nodeID, commonconst.ResourceTypeDigest)
+	resp := model.NewMessage("").
+		BuildRouter(modules.SyncControllerModuleName, edgectrconst.GroupResource, resource, model.UpdateOperation).
+		FillBody(result)
+	if err := sctl.messageLayer.Send(*resp); err != nil {
+		klog.Warningf("failed to send digest result to node %s: %v", nodeID, err)
+	}
+}
+
+// compareDigest compares the digest of the node with the cluster and pushes
+// the missing and stale objects to the node and deletes the extra ones
+func (sctl *SyncController) compareDigest(nodeID string, digest *commontypes.MetaDigest) commontypes.MetaDigestResult {
+	var result commontypes.MetaDigestResult
+	if digest.Full {
+		sctl.digests.set(nodeID, digest.Digests)
+	}
+	stored := sctl.digests.get(nodeID)
+	if !digest.Full {
+		for resourceType, d := range digest.Digests {
+			if s, ok := stored[resourceType]; !ok || s.Checksum != d.Checksum {
+				result.FullRequired = true
+				return result
+			}
+		}
+	}
+
+	for resourceType := range digest.Digests {
+		missing, stale, extra := sctl.reconcileDigest(nodeID, resourceType, stored[resourceType].Objects)
+		result.Missing += missing
+		result.Stale += stale
+		result.Extra += extra
+	}
+	return result
+}
+
+func (sctl *SyncController) reconcileDigest(nodeID, resourceType string, objects []commontypes.ObjectDigest) (missing, stale, extra int) {
+	gvr, ok := digestResources[resourceType]
+	if !ok {
+		klog.Warningf("digest of resource type %s from node %s is not supported", resourceType, nodeID)
+		return
+	}
+	lister, err := sctl.informerManager.GetLister(gvr)
+	if err != nil {
+		klog.Errorf("failed to get lister of %v: %v", gvr, err)
+		return
+	}
+
+	cached := make(map[string]bool, len(objects))
+	for _, o := range objects {
+		cached[o.Namespace+"/"+o.Name] = true
+		ret, err := lister.ByNamespace(o.Namespace).Get(o.Name)
+		if apierrors.IsNotFound(err) {
+			sctl.deleteDriftedObject(nodeID, resourceType, o)
+			extra++
+			continue
+		} else if err != nil || ret == nil {
+			klog.Errorf("failed to get obj(gvr:%v,namespace:%v,name:%v), %v", gvr, o.Namespace, o.Name, err)
+			continue
+		}
+		object, err := meta.Accessor(ret)
+		if err != nil {
+			continue
+		}
+		if !isDigestStale(o, object) {
+			continue
+		}
+		// the node gets only the objects cloud has sent to it, not any object it names in the digest
+		if !sctl.isSyncedTo(nodeID, resourceType, object) {
+			klog.V(4).Infof("%s %s/%s in the digest of node %s is not synced to the node, skip it",
+				resourceType, o.Namespace, o.Name, nodeID)
+			continue
+		}
+		sctl.sendDriftedObject(nodeID, resourceType, object.GetNamespace(), object.GetName(), ret)
+		stale++
+	}
+
+	// the objects cloud has sent to the node but not cached at edge
+	syncs, err := sctl.objectSyncLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list all the ObjectSyncs: %v", err)
+		return
+	}
+	for _, sync := range syncs {
+		if getNodeName(sync.Name) != nodeID || strings.ToLower(sync.Spec.ObjectKind) != resourceType ||
+			cached[sync.Namespace+"/"+sync.Spec.ObjectName] {
+			continue
+		}
+		// the objects deleted from the cluster are handled by reconcileObjectSync
+		ret, err := lister.ByNamespace(sync.Namespace).Get(sync.Spec.ObjectName)
+		if err != nil || ret == nil {
+			continue
+		}
+		object, err := meta.Accessor(ret)
+		if err != nil || string(object.GetUID()) != getObjectUID(sync.Name) {
+			continue
+		}
+		sctl.sendDriftedObject(nodeID, resourceType, sync.Namespace, sync.Spec.ObjectName, ret)
+		missing++
+	}
+	return
+}
+
+// isSyncedTo returns whether there is the ObjectSync of the object for the node
+func (sctl *SyncController) isSyncedTo(nodeID, resourceType string, object metav1.Object) bool {
+	sync, err := sctl.objectSyncLister.ObjectSyncs(object.GetNamespace()).Get(BuildObjectSyncName(nodeID, string(object.GetUID())))
+	if err != nil {
+		return false
+	}
+	return strings.ToLower(sync.Spec.ObjectKind) == resourceType && sync.Spec.ObjectName == object.GetName()
+}
+
+// isDigestStale returns whether the object cached at edge is older than the one in the cluster
+func isDigestStale(o commontypes.ObjectDigest, object metav1.Object) bool {
+	if o.UID != "" && o.UID != string(object.GetUID()) {
+		return true
+	}
+	edgeVersion, err := strconv.ParseUint(o.ResourceVersion, 10, 64)
+	if err != nil {
+		return o.ResourceVersion != object.GetResourceVersion()
+	}
+	cloudVersion, err := strconv.ParseUint(object.GetResourceVersion(), 10, 64)
+	if err != nil {
+		return o.ResourceVersion != object.GetResourceVersion()
+	}
+	return cloudVersion > edgeVersion
+}
+
+func (sctl *SyncController) sendDriftedObject(nodeID, resourceType, namespace, name string, obj interface{}) {
+	if msg := buildEdgeControllerMessage(nodeID, namespace, resourceType, name, model.UpdateOperation, obj); msg != nil {
+		beehiveContext.Send(commonconst.DefaultContextSendModuleName, *msg)
+	}
+}
+
+func (sctl *SyncController) deleteDriftedObject(nodeID, resourceType string, o commontypes.ObjectDigest) {
+	klog.V(4).Infof("%s %s/%s cached by node %s has been deleted in K8s, send the delete event", resourceType, o.Namespace, o.Name, nodeID)
+	object := &unstructured.Unstructured{}
+	object.SetNamespace(o.Namespace)
+	object.SetName(o.Name)
+	object.SetUID(types.UID(o.UID))
+	if msg := buildEdgeControllerMessage(nodeID, o.Namespace, resourceType, o.Name, model.DeleteOperation, object); msg != nil {
+		beehiveContext.Send(commonconst.DefaultContextSendModuleName, *msg)
+	}
+}
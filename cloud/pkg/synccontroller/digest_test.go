@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package synccontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubeedge/beehive/pkg/common"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	tf "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/testing"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+)
+
+func newTestConfigMap(name, uid, resourceVersion string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       tf.TestNamespace,
+			UID:             types.UID(uid),
+			ResourceVersion: resourceVersion,
+		},
+	}
+}
+
+func TestIsDigestStale(t *testing.T) {
+	object := newTestConfigMap("cm", "uid", "10")
+	cases := []struct {
+		name   string
+		digest commontypes.ObjectDigest
+		want   bool
+	}{
+		{name: "same version", digest: commontypes.ObjectDigest{UID: "uid", ResourceVersion: "10"}, want: false},
+		{name: "newer at edge", digest: commontypes.ObjectDigest{UID: "uid", ResourceVersion: "11"}, want: false},
+		{name: "older at edge", digest: commontypes.ObjectDigest{UID: "uid", ResourceVersion: "9"}, want: true},
+		{name: "recreated", digest: commontypes.ObjectDigest{UID: "old", ResourceVersion: "10"}, want: true},
+		{name: "bad version", digest: commontypes.ObjectDigest{UID: "uid", ResourceVersion: "x"}, want: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isDigestStale(c.digest, object); got != c.want {
+				t.Errorf("isDigestStale() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestCompareDigest(t *testing.T) {
+	beehiveContext.InitContext([]string{common.MsgCtxTypeChannel})
+	beehiveContext.AddModule(&common.ModuleInfo{
+		ModuleName: modules.CloudHubModuleName,
+		ModuleType: common.MsgCtxTypeChannel,
+	})
+	beehiveContext.AddModuleGroup(modules.CloudHubModuleName, modules.CloudHubModuleGroup)
+	client.DefaultGetRestMapper = func() (mapper meta.RESTMapper, err error) { return nil, nil }
+
+	testController := newSyncController(true)
+	testController.informerManager = informers.NewFakeInformerManager()
+	pair, err := testController.informerManager.GetInformerPair(digestResources[model.ResourceTypeConfigmap])
+	if err != nil {
+		t.Fatalf("failed to get configmap informer: %v", err)
+	}
+	stale := newTestConfigMap("stale", "stale-uid", "5")
+	missing := newTestConfigMap("missing", "missing-uid", "3")
+	synced := newTestConfigMap("synced", "synced-uid", "2")
+	// the object which is not sent to the node is never sent for the digest
+	unsynced := newTestConfigMap("unsynced", "unsynced-uid", "7")
+	for _, cm := range []*corev1.ConfigMap{stale, missing, synced, unsynced} {
+		if err := pair.Informer.GetStore().Add(cm); err != nil {
+			t.Fatalf("failed to add configmap: %v", err)
+		}
+	}
+	objectSyncs := testController.informerManager.GetKubeEdgeInformerFactory().Reliablesyncs().V1alpha1().ObjectSyncs()
+	for _, cm := range []*corev1.ConfigMap{stale, missing, synced} {
+		if err := objectSyncs.Informer().GetIndexer().Add(tf.NewObjectSync(cm, "ConfigMap")); err != nil {
+			t.Fatalf("failed to add objectSync: %v", err)
+		}
+	}
+	testController.objectSyncLister = objectSyncs.Lister()
+
+	objects := []commontypes.ObjectDigest{
+		{Namespace: tf.TestNamespace, Name: "stale", UID: "stale-uid", ResourceVersion: "4"},
+		{Namespace: tf.TestNamespace, Name: "synced", UID: "synced-uid", ResourceVersion: "2"},
+		{Namespace: tf.TestNamespace, Name: "extra", UID: "extra-uid", ResourceVersion: "1"},
+		{Namespace: tf.TestNamespace, Name: "unsynced", UID: "unsynced-uid", ResourceVersion: "1"},
+	}
+	digests := map[string]commontypes.ResourceDigest{
+		model.ResourceTypeConfigmap: {Checksum: commontypes.DigestChecksum(objects)},
+	}
+
+	result := testController.compareDigest(tf.TestNodeID, &commontypes.MetaDigest{Digests: digests})
+	if !result.FullRequired {
+		t.Fatalf("expected full digest to be required without the last full digest, got %+v", result)
+	}
+
+	full := map[string]commontypes.ResourceDigest{
+		model.ResourceTypeConfigmap: {Checksum: digests[model.ResourceTypeConfigmap].Checksum, Objects: objects},
+	}
+	result = testController.compareDigest(tf.TestNodeID, &commontypes.MetaDigest{Digests: full, Full: true})
+	expected := commontypes.MetaDigestResult{Missing: 1, Stale: 1, Extra: 1}
+	if result != expected {
+		t.Errorf("expected result %+v, got %+v", expected, result)
+	}
+
+	operations := make(map[string]string)
+	for i := 0; i < 3; i++ {
+		msg, err := receiveWithTimeout(modules.CloudHubModuleName)
+		if err != nil {
+			t.Fatalf("failed to receive message %d: %v", i, err)
+		}
+		operations[msg.GetResource()] = msg.GetOperation()
+	}
+	for name, operation := range map[string]string{"stale": model.UpdateOperation, "missing": model.UpdateOperation, "extra": model.DeleteOperation} {
+		resource := "node/" + tf.TestNodeID + "/" + tf.TestNamespace + "/configmap/" + name
+		if operations[resource] != operation {
+			t.Errorf("expected %s of %s, got messages %v", operation, resource, operations)
+		}
+	}
+
+	// the checksums same as the last full digest are compared without the objects
+	result = testController.compareDigest(tf.TestNodeID, &commontypes.MetaDigest{Digests: digests})
+	if result.FullRequired || result.Stale != 1 {
+		t.Errorf("expected the last full digest to be compared, got %+v", result)
+	}
+}
+
+func receiveWithTimeout(module string) (model.Message, error) {
+	type received struct {
+		msg model.Message
+		err error
+	}
+	ch := make(chan received, 1)
+	go func() {
+		msg, err := beehiveContext.Receive(module)
+		ch <- received{msg, err}
+	}()
+	select {
+	case r := <-ch:
+		return r.msg, r.err
+	case <-time.After(5 * time.Second):
+		return model.Message{}, context.DeadlineExceeded
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -22,6 +23,7 @@ import (
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
 	keclient "github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
 	"github.com/kubeedge/kubeedge/cloud/pkg/synccontroller/config"
 )
@@ -42,6 +44,10 @@ type SyncController struct {
 	informersSyncedFuncs []cache.InformerSynced
 
 	informerManager informers.Manager
+
+	messageLayer messagelayer.MessageLayer
+	// digests are the last full digests of the edge nodes
+	digests *nodeDigests
 }
 
 var _ core.Module = (*SyncController)(nil)
@@ -52,6 +58,8 @@ func newSyncController(enable bool) *SyncController {
 		crdclient:       keclient.GetCRDClient(),
 		kubeclient:      keclient.GetDynamicClient(),
 		informerManager: informers.GetInformersManager(),
+		messageLayer:    messagelayer.SyncControllerMessageLayer(),
+		digests:         newNodeDigests(),
 	}
 	// informer factory
 	k8sInformerFactory := informers.GetInformersManager().GetKubeInformerFactory()
@@ -63,6 +71,9 @@ func newSyncController(enable bool) *SyncController {
 	_, err := nodesInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		DeleteFunc: func(obj interface{}) {
 			sctl.deleteObjectSyncs()
+			if node, ok := obj.(*corev1.Node); ok {
+				sctl.digests.delete(node.Name)
+			}
 		},
 	})
 	if err != nil {
@@ -114,6 +125,8 @@ func (sctl *SyncController) Start() {
 	go wait.Until(sctl.reconcileObjectSyncs, 5*time.Second, beehiveContext.Done())
 
 	go wait.Until(sctl.reconcileClusterObjectSyncs, 5*time.Second, beehiveContext.Done())
+
+	go sctl.receiveDigests()
 }
 
 // reconcileObjectSyncs compare the version of the resource that has been sent to the
@@ -254,10 +254,11 @@ func (ndc *ImagePrePullController) imagePrePullJobAdded(imagePrePull *v1alpha1.I
 func (ndc *ImagePrePullController) processPrePull(imagePrePull *v1alpha1.ImagePrePullJob) {
 	imagePrePullTemplateInfo := imagePrePull.Spec.ImagePrePullTemplate
 	imagePrePullRequest := commontypes.ImagePrePullJobRequest{
-		Images:     imagePrePullTemplateInfo.Images,
-		Secret:     imagePrePullTemplateInfo.ImageSecret,
-		RetryTimes: imagePrePullTemplateInfo.RetryTimes,
-		CheckItems: imagePrePullTemplateInfo.CheckItems,
+		Images:             imagePrePullTemplateInfo.Images,
+		Secret:             imagePrePullTemplateInfo.ImageSecret,
+		RetryTimes:         imagePrePullTemplateInfo.RetryTimes,
+		CheckItems:         imagePrePullTemplateInfo.CheckItems,
+		VerificationPolicy: imagePrePullTemplateInfo.VerificationPolicy,
 	}
 	tolerate, err := strconv.ParseFloat(imagePrePull.Spec.ImagePrePullTemplate.FailureTolerate, 64)
 	if err != nil {
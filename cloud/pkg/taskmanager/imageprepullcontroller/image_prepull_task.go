@@ -60,19 +60,27 @@ func updatePrePullNodeState(id, nodeName string, state v1alpha12.State, event fs
 	status := newTask.Status.DeepCopy()
 	for i, nodeStatus := range status.Status {
 		if nodeStatus.NodeName == nodeName {
+			var previous []v1alpha1.CheckResult
+			if nodeStatus.TaskStatus != nil {
+				previous = nodeStatus.TaskStatus.CheckResults
+			}
+			// the external message of the Check event carries the check results instead of images status
 			var imagesStatus []v1alpha1.ImageStatus
-			err := json.Unmarshal([]byte(event.ExternalMessage), &imagesStatus)
-			if err != nil {
-				klog.Warningf("Failed to unmarshal images status: %v", err)
+			if event.Type != util.TaskEventCheck {
+				err := json.Unmarshal([]byte(event.ExternalMessage), &imagesStatus)
+				if err != nil {
+					klog.Warningf("Failed to unmarshal images status: %v", err)
+				}
 			}
 			status.Status[i] = v1alpha1.ImagePrePullStatus{
 				TaskStatus: &v1alpha1.TaskStatus{
-					NodeName: nodeName,
-					State:    state,
-					Event:    event.Type,
-					Action:   event.Action,
-					Time:     time.Now().Format(util.ISO8601UTC),
-					Reason:   event.Msg,
+					NodeName:     nodeName,
+					State:        state,
+					Event:        event.Type,
+					Action:       event.Action,
+					Time:         time.Now().Format(util.ISO8601UTC),
+					Reason:       event.Msg,
+					CheckResults: util.NodeCheckResults(previous, event.Type, event.ExternalMessage),
 				},
 				ImageStatus: imagesStatus,
 			}
@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageprepullcontroller
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/revocation"
+)
+
+// Seed is the node of a node group pulling the images of an ImagePrePullJob from the registry through
+// its image mirror, the other nodes of the job in the node group pull the images from the image mirror
+type Seed struct {
+	// Node is the name of the seed node
+	Node string
+	// Address is the internal IP of the seed node the image mirror listens on
+	Address string
+	// Clients are the other nodes of the job in the node group
+	Clients []string
+}
+
+// SeedOf returns the seed of the node group of the edge node among the nodes of the ImagePrePullJob.
+// Nil is returned if the edge node pulls images from the registry, that is the ImagePrePullJob is
+// not in P2P mode, or the edge node is not in a node group, or no seed node is found.
+func (ndc *ImagePrePullController) SeedOf(taskID, nodeName string, jobNodes []string) *Seed {
+	v, ok := ndc.TaskManager.CacheMap.Load(taskID)
+	if !ok {
+		return nil
+	}
+	template := v.(*v1alpha1.ImagePrePullJob).Spec.ImagePrePullTemplate
+	if template.DistributionMode != v1alpha1.ImageDistributionP2P {
+		return nil
+	}
+
+	lister := ndc.Informer.Core().V1().Nodes().Lister()
+	node, err := lister.Get(nodeName)
+	if err != nil {
+		klog.Warningf("failed to get node %s: %v", nodeName, err)
+		return nil
+	}
+	nodeGroup := node.Labels[util.NodeGroupLabel]
+	if nodeGroup == "" {
+		return nil
+	}
+	nodes, err := lister.List(labels.SelectorFromSet(labels.Set{util.NodeGroupLabel: nodeGroup}))
+	if err != nil {
+		klog.Warningf("failed to list nodes of node group %s: %v", nodeGroup, err)
+		return nil
+	}
+	// only the nodes of the job pull the images, so the seed and the clients are chosen among them
+	inJob := make(map[string]bool, len(jobNodes))
+	for _, name := range jobNodes {
+		inJob[name] = true
+	}
+	var members []*v1.Node
+	for _, member := range nodes {
+		if inJob[member.Name] {
+			members = append(members, member)
+		}
+	}
+	seed := selectSeed(template.SeedNodes[nodeGroup], members)
+	if seed == nil {
+		klog.Warningf("no seed node found in node group %s, node %s pulls images from the registry", nodeGroup, nodeName)
+		return nil
+	}
+	address := nodeInternalIP(seed)
+	if address == "" {
+		klog.Warningf("seed node %s of node group %s has no internal ip", seed.Name, nodeGroup)
+		return nil
+	}
+	result := &Seed{Node: seed.Name, Address: address}
+	for _, member := range members {
+		if member.Name != seed.Name {
+			result.Clients = append(result.Clients, member.Name)
+		}
+	}
+	sort.Strings(result.Clients)
+	return result
+}
+
+// RevokedAt returns the times the certificates of the nodes are revoked at, the certificates of a node
+// whose revocation is not stamped yet are all revoked, so they are revoked at now
+func (ndc *ImagePrePullController) RevokedAt(nodes []string) (map[string]time.Time, error) {
+	cm, err := ndc.KubeClient.CoreV1().ConfigMaps(constants.SystemNamespace).Get(context.TODO(), revocation.ConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries, errs := revocation.Parse(cm.Data)
+	for _, err := range errs {
+		klog.Warning(err)
+	}
+	revoked := map[string]time.Time{}
+	for _, node := range nodes {
+		entry, ok := entries[node]
+		if !ok {
+			continue
+		}
+		if entry.Pending() {
+			revoked[node] = time.Now().UTC()
+			continue
+		}
+		revoked[node] = entry.RevokedAt
+	}
+	return revoked, nil
+}
+
+// selectSeed returns the designated seed node if it is a member of the node group,
+// otherwise the first member by name
+func selectSeed(designated string, members []*v1.Node) *v1.Node {
+	if len(members) == 0 {
+		return nil
+	}
+	if designated != "" {
+		for _, member := range members {
+			if member.Name == designated {
+				return member
+			}
+		}
+		klog.Warningf("designated seed node %s is not in the node group", designated)
+	}
+	sorted := make([]*v1.Node, len(members))
+	copy(sorted, members)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted[0]
+}
+
+func nodeInternalIP(node *v1.Node) string {
+	for _, address := range node.Status.Addresses {
+		if address.Type == v1.NodeInternalIP {
+			return address.Address
+		}
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageprepullcontroller
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/revocation"
+)
+
+func TestSelectSeed(t *testing.T) {
+	node := func(name string) *v1.Node {
+		return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+	members := []*v1.Node{node("edge-c"), node("edge-a"), node("edge-b")}
+
+	cases := []struct {
+		name       string
+		designated string
+		members    []*v1.Node
+		expected   string
+	}{
+		{name: "designated seed", designated: "edge-b", members: members, expected: "edge-b"},
+		{name: "no designated seed", members: members, expected: "edge-a"},
+		{name: "designated seed not in group", designated: "edge-x", members: members, expected: "edge-a"},
+		{name: "empty group", designated: "edge-a"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			seed := selectSeed(c.designated, c.members)
+			if c.expected == "" {
+				if seed != nil {
+					t.Errorf("expected no seed, got %s", seed.Name)
+				}
+				return
+			}
+			if seed == nil || seed.Name != c.expected {
+				t.Errorf("expected seed %s, got %v", c.expected, seed)
+			}
+		})
+	}
+	if members[0].Name != "edge-c" {
+		t.Error("expected the members not to be reordered")
+	}
+}
+
+func TestRevokedAt(t *testing.T) {
+	ndc := &ImagePrePullController{BaseController: &controller.BaseController{KubeClient: fake.NewSimpleClientset()}}
+	revoked, err := ndc.RevokedAt([]string{"edge-a"})
+	if err != nil || len(revoked) != 0 {
+		t.Fatalf("expected no revocation without the revocation list, got %v %v", revoked, err)
+	}
+
+	revokedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	stamped, _ := revocation.NewEntry(revokedAt, "lost").Encode()
+	pending, _ := revocation.NewPendingEntry("lost").Encode()
+	ndc.KubeClient = fake.NewSimpleClientset(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: revocation.ConfigMapName, Namespace: constants.SystemNamespace},
+		Data:       map[string]string{"edge-a": stamped, "edge-b": pending, "edge-x": stamped},
+	})
+	before := time.Now()
+	revoked, err = ndc.RevokedAt([]string{"edge-a", "edge-b", "edge-c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revoked) != 2 || !revoked["edge-a"].Equal(revokedAt) || revoked["edge-b"].Before(before.Truncate(time.Second)) {
+		t.Errorf("unexpected revocation %v", revoked)
+	}
+}
@@ -19,6 +19,7 @@ package manager
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -34,6 +35,7 @@ import (
 	"github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/imageprepullcontroller"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/nodeupgradecontroller"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
@@ -138,13 +140,13 @@ func (e *Executor) HandleMessage(status v1alpha1.TaskStatus) error {
 	return nil
 }
 
-func (e *Executor) initMessage(node v1alpha1.TaskStatus) *model.Message {
+func (e *Executor) initMessage(node v1alpha1.TaskStatus) (*model.Message, error) {
 	// delete it in 1.18
 	if e.task.Type == util.TaskUpgrade {
 		msg := e.initHistoryMessage(node)
 		if msg != nil {
 			klog.Warningf("send history message to node")
-			return msg
+			return msg, nil
 		}
 	}
 
@@ -157,14 +159,124 @@ func (e *Executor) initMessage(node v1alpha1.TaskStatus) *model.Message {
 		State:  string(node.State),
 	}
 	taskReq.Item = e.task.Msg
+	if req, ok := e.task.Msg.(commontypes.ImagePrePullJobRequest); ok {
+		prePullController, ok := e.controller.(*imageprepullcontroller.ImagePrePullController)
+		if !ok {
+			return nil, fmt.Errorf("controller of task %s is %T, not the image prepull controller", e.task.Name, e.controller)
+		}
+		if seed := prePullController.SeedOf(e.task.Name, node.NodeName, e.nodeNames()); seed != nil {
+			req.SeedNode, req.Mirror = seed.Node, seed.Address
+			if seed.Node == node.NodeName {
+				req.MirrorClients = seed.Clients
+				revoked, err := prePullController.RevokedAt(seed.Clients)
+				if err != nil {
+					// the image mirror serves no other node if the revoked certificates are unknown
+					klog.Errorf("failed to get the revocation of the nodes of node group of seed node %s: %v", seed.Node, err)
+					req.MirrorClients = nil
+				}
+				req.RevokedClients = revoked
+			}
+		}
+		taskReq.Item = req
+	}
 	if node.State == api.TaskChecking {
 		taskReq.Item = commontypes.NodePreCheckRequest{
 			CheckItem: e.task.CheckItem,
+			Images:    taskImages(e.task.Msg),
 		}
 	}
 	msg.BuildRouter(modules.TaskManagerModuleName, modules.TaskManagerModuleGroup, resource, e.task.Type).
 		FillBody(taskReq)
-	return msg
+	return msg, nil
+}
+
+// nodeNames returns the names of the nodes of the task
+func (e *Executor) nodeNames() []string {
+	names := make([]string, len(e.nodes))
+	for i, node := range e.nodes {
+		names[i] = node.NodeName
+	}
+	return names
+}
+
+// seedOf returns the seed node pulling the images of the node in P2P image prepull tasks, empty otherwise
+func (e *Executor) seedOf(nodeName string) string {
+	prePullController, ok := e.controller.(*imageprepullcontroller.ImagePrePullController)
+	if !ok {
+		return ""
+	}
+	if seed := prePullController.SeedOf(e.task.Name, nodeName, e.nodeNames()); seed != nil {
+		return seed.Node
+	}
+	return ""
+}
+
+// orderSeedsFirst moves the seed nodes of P2P image prepull tasks before the other nodes,
+// so the seed nodes are started first and the other nodes wait for them in initWorker
+func (e *Executor) orderSeedsFirst() {
+	seeds := map[string]bool{}
+	for _, node := range e.nodes {
+		if seed := e.seedOf(node.NodeName); seed != "" {
+			seeds[seed] = true
+		}
+	}
+	if len(seeds) == 0 {
+		return
+	}
+	sort.SliceStable(e.nodes, func(i, j int) bool {
+		return seeds[e.nodes[i].NodeName] && !seeds[e.nodes[j].NodeName]
+	})
+}
+
+// waitsForSeed returns true if the seed node of the node has not completed the current stage,
+// the node pulls the images from the image mirror on the seed node, which are granted by the seed node
+func (e *Executor) waitsForSeed(nodeName string) bool {
+	seed := e.seedOf(nodeName)
+	if seed == "" || seed == nodeName {
+		return false
+	}
+	for _, node := range e.nodes {
+		if node.NodeName == seed {
+			return !e.controller.StageCompleted(e.task.Name, node.State)
+		}
+	}
+	return false
+}
+
+// releaseMirrors tells the seed nodes of P2P image prepull tasks that the task is finished,
+// so their image mirrors stop serving the images of the task
+func (e *Executor) releaseMirrors(state api.State) {
+	seeds := map[string]bool{}
+	for _, node := range e.nodes {
+		seed := e.seedOf(node.NodeName)
+		if seed == "" || seeds[seed] {
+			continue
+		}
+		seeds[seed] = true
+		msg := model.NewMessage("")
+		msg.BuildRouter(modules.TaskManagerModuleName, modules.TaskManagerModuleGroup, buildTaskResource(e.task.Type, e.task.Name, seed), e.task.Type).
+			FillBody(commontypes.NodeTaskRequest{
+				TaskID: e.task.Name,
+				Type:   e.task.Type,
+				State:  string(state),
+			})
+		executorMachine.downStreamChan <- *msg
+	}
+}
+
+// taskImages returns the images used by the task message
+func taskImages(msg interface{}) []string {
+	switch req := msg.(type) {
+	case commontypes.NodeUpgradeJobRequest:
+		if req.Image == "" {
+			return nil
+		}
+		return []string{req.Image}
+	case commontypes.ImagePrePullJobRequest:
+		return req.Images
+	default:
+		return nil
+	}
 }
 
 func (e *Executor) initHistoryMessage(node v1alpha1.TaskStatus) *model.Message {
@@ -235,6 +347,7 @@ func initExecutor(message util.TaskMessage) (*Executor, error) {
 			Mutex:        sync.Mutex{},
 		},
 	}
+	e.orderSeedsFirst()
 	go e.start()
 	executorMachine.executors[fmt.Sprintf("%s::%s", message.Type, message.Name)] = e
 	return e, nil
@@ -283,6 +396,7 @@ func (e *Executor) start() {
 					break
 				}
 				if fsm.TaskFinish(state) {
+					e.releaseMirrors(state)
 					DeleteExecutor(e.task)
 					klog.Infof("task %s is finish", e.task.Name)
 					return
@@ -353,6 +467,10 @@ func (e *Executor) initWorker(index int) (int, error) {
 			}
 			continue
 		}
+		if e.waitsForSeed(node.NodeName) {
+			klog.V(4).Infof("node %s waits for its seed node to complete the stage", node.NodeName)
+			break
+		}
 		err := e.workers.addJob(node, index, e)
 		if err != nil {
 			klog.V(4).Info(err.Error())
@@ -380,7 +498,14 @@ func (w *workers) addJob(node v1alpha1.TaskStatus, index int, e *Executor) error
 	}
 	w.jobs[node.NodeName] = index
 	w.Unlock()
-	msg := e.initMessage(node)
+	msg, err := e.initMessage(node)
+	if err != nil {
+		w.Lock()
+		delete(w.jobs, node.NodeName)
+		w.Unlock()
+		klog.Errorf("failed to init the message of node %s: %v", node.NodeName, err)
+		return err
+	}
 	go e.handelTimeOutJob(index)
 	executorMachine.downStreamChan <- *msg
 	return nil
@@ -58,12 +58,13 @@ func updateUpgradeNodeState(id, nodeName string, state v1alpha12.State, event fs
 	for i, nodeStatus := range status.Status {
 		if nodeStatus.NodeName == nodeName {
 			status.Status[i] = v1alpha1.TaskStatus{
-				NodeName: nodeName,
-				State:    state,
-				Event:    event.Type,
-				Action:   event.Action,
-				Time:     time.Now().Format(util.ISO8601UTC),
-				Reason:   event.Msg,
+				NodeName:     nodeName,
+				State:        state,
+				Event:        event.Type,
+				Action:       event.Action,
+				Time:         time.Now().Format(util.ISO8601UTC),
+				Reason:       event.Msg,
+				CheckResults: util.NodeCheckResults(nodeStatus.CheckResults, event.Type, event.ExternalMessage),
 			}
 			break
 		}
@@ -17,6 +17,7 @@ limitations under the License.
 package util
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -43,6 +44,12 @@ const (
 	TaskPrePull  = "prepull"
 
 	ISO8601UTC = "2006-01-02T15:04:05Z"
+
+	// TaskEventCheck is the type of the event reported by edge nodes after the pre-check
+	TaskEventCheck = "Check"
+
+	// NodeGroupLabel is the label of nodes indicating the node group they belong to
+	NodeGroupLabel = "apps.kubeedge.io/belonging-to"
 )
 
 type TaskMessage struct {
@@ -179,3 +186,20 @@ func NodeUpdated(old, new v1alpha1.TaskStatus) bool {
 	}
 	return true
 }
+
+// NodeCheckResults returns the check results of the node after the event. The results are
+// reported by the edge node with the Check event, and kept during the following stages.
+func NodeCheckResults(previous []v1alpha1.CheckResult, eventType, externalMessage string) []v1alpha1.CheckResult {
+	if eventType != TaskEventCheck {
+		return previous
+	}
+	if externalMessage == "" {
+		return nil
+	}
+	var results []v1alpha1.CheckResult
+	if err := json.Unmarshal([]byte(externalMessage), &results); err != nil {
+		klog.Warningf("failed to unmarshal check results: %v", err)
+		return nil
+	}
+	return results
+}
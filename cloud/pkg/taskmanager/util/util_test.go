@@ -19,6 +19,8 @@ package util
 import (
 	"reflect"
 	"testing"
+
+	"github.com/kubeedge/api/apis/operations/v1alpha1"
 )
 
 func TestFilterVersion(t *testing.T) {
@@ -169,3 +171,43 @@ func TestGetImageRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeCheckResults(t *testing.T) {
+	previous := []v1alpha1.CheckResult{{Name: "cpu", Result: v1alpha1.CheckResultPassed}}
+	tests := []struct {
+		name            string
+		eventType       string
+		externalMessage string
+		expected        []v1alpha1.CheckResult
+	}{
+		{
+			name:            "check event reports results",
+			eventType:       TaskEventCheck,
+			externalMessage: `[{"name":"disk","result":"Failed","message":"full"}]`,
+			expected:        []v1alpha1.CheckResult{{Name: "disk", Result: v1alpha1.CheckResultFailed, Message: "full"}},
+		},
+		{
+			name:      "check event without results",
+			eventType: TaskEventCheck,
+		},
+		{
+			name:            "check event with invalid results",
+			eventType:       TaskEventCheck,
+			externalMessage: "invalid",
+		},
+		{
+			name:            "later event keeps results",
+			eventType:       "Pull",
+			externalMessage: `[{"image":"nginx"}]`,
+			expected:        previous,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := NodeCheckResults(previous, test.eventType, test.externalMessage)
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("Got = %v, Want = %v", got, test.expected)
+			}
+		})
+	}
+}
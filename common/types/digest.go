@@ -0,0 +1,60 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+)
+
+// MetaDigest is Message.Content which comes from edge, it digests the objects cached in the
+// edge database so that cloud can find the objects drifted from the cluster
+type MetaDigest struct {
+	// Digests are keyed by resource type
+	Digests map[string]ResourceDigest
+	// Full indicates whether the objects are listed, otherwise only the checksums are sent
+	// and cloud compares them with the last full digest of the node
+	Full bool
+}
+
+// ResourceDigest is the digest of the objects of a resource type
+type ResourceDigest struct {
+	Checksum string
+	Objects  []ObjectDigest `json:",omitempty"`
+}
+
+// ObjectDigest identifies the version of an object cached at edge
+type ObjectDigest struct {
+	Namespace       string
+	Name            string
+	UID             string
+	ResourceVersion string
+}
+
+// MetaDigestResult is Message.Content which comes from cloud, it is the result of comparing a MetaDigest
+type MetaDigestResult struct {
+	// FullRequired indicates the checksums differ from the last full digest cloud has,
+	// edge should send the full digest
+	FullRequired bool
+	// Missing, Stale and Extra are the number of objects cloud pushed or deleted to fix the drift
+	Missing int
+	Stale   int
+	Extra   int
+}
+
+// DigestChecksum returns the checksum of the objects regardless of their order
+func DigestChecksum(objects []ObjectDigest) string {
+	sorted := make([]ObjectDigest, len(objects))
+	copy(sorted, objects)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Namespace != sorted[j].Namespace {
+			return sorted[i].Namespace < sorted[j].Namespace
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	h := sha256.New()
+	for _, o := range sorted {
+		h.Write([]byte(o.Namespace + "/" + o.Name + "/" + o.UID + "/" + o.ResourceVersion + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
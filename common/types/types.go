@@ -1,6 +1,8 @@
 package types
 
 import (
+	"time"
+
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -54,6 +56,8 @@ type NodeUpgradeJobResponse struct {
 // NodePreCheckRequest is pre-check msg coming from cloud to edge
 type NodePreCheckRequest struct {
 	CheckItem []string
+	// Images are the images used by the task, the registries of them are checked by the registry check item
+	Images []string
 }
 
 type NodeTaskRequest struct {
@@ -96,6 +100,19 @@ type ImagePrePullJobRequest struct {
 	Secret     string
 	RetryTimes int32
 	CheckItems []string
+	// SeedNode is the seed node of the node group of the edge node in P2P distribution mode
+	SeedNode string
+	// Mirror is the internal IP of the seed node in P2P distribution mode, the edge node pulls images from
+	// the image mirror listening on it with the port of its image mirror config, or from the registry if it is empty
+	Mirror string
+	// MirrorClients are the other nodes of the task in the node group of the seed node, the image mirror
+	// only serves the images of the task to them. It is only sent to the seed node.
+	MirrorClients []string
+	// RevokedClients are the times the certificates of the mirror clients are revoked at,
+	// the image mirror rejects the certificates issued not after them
+	RevokedClients map[string]time.Time
+	// VerificationPolicy is verified on the edge node before each image is pulled
+	VerificationPolicy *v1alpha1.ImageVerificationPolicy
 }
 
 // ImagePrePullJobResponse is used to report status msg to cloudhub https service from each node
@@ -30,6 +30,8 @@ import (
 
 type EdgeCoreOptions struct {
 	ConfigFile string
+	// MigrateMetaToBolt indicates to copy the metadata from sqlite to bbolt and exit
+	MigrateMetaToBolt bool
 }
 
 var edgeCoreOptions *EdgeCoreOptions
@@ -53,6 +55,9 @@ func NewEdgeCoreOptions() *EdgeCoreOptions {
 func (o *EdgeCoreOptions) Flags() (fss cliflag.NamedFlagSets) {
 	fs := fss.FlagSet("global")
 	fs.StringVar(&o.ConfigFile, "config", o.ConfigFile, "The path to the configuration file. Flags override values in this file.")
+	fs.BoolVar(&o.MigrateMetaToBolt, "migrate-meta-to-bolt", o.MigrateMetaToBolt,
+		"Copy the metadata from the sqlite database to the bbolt database of the configuration file and exit. "+
+			"Run it once with edgecore stopped before setting dataBase.metaBackend to bbolt.")
 	return
 }
 
@@ -20,13 +20,17 @@ import (
 	"github.com/kubeedge/beehive/pkg/core"
 	"github.com/kubeedge/kubeedge/common/constants"
 	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	"github.com/kubeedge/kubeedge/edge/pkg/admin"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/msgqueue"
 	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin"
 	"github.com/kubeedge/kubeedge/edge/pkg/edged"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/certificate"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgestream"
 	"github.com/kubeedge/kubeedge/edge/pkg/eventbus"
+	"github.com/kubeedge/kubeedge/edge/pkg/extension"
+	"github.com/kubeedge/kubeedge/edge/pkg/imagemirror"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager"
 	"github.com/kubeedge/kubeedge/edge/pkg/servicebus"
 	"github.com/kubeedge/kubeedge/edge/test"
@@ -102,6 +106,13 @@ offering HTTP client capabilities to components of cloud to reach HTTP servers r
 			// To help debugging, immediately log version
 			klog.Infof("Version: %+v", version.Get())
 
+			if opts.MigrateMetaToBolt {
+				if err := metamanager.MigrateToBolt(config.DataBase); err != nil {
+					klog.Exit(fmt.Errorf("failed to migrate metadata to bbolt: %v", err))
+				}
+				return
+			}
+
 			// Force skip check if enable metaserver
 			skipCheck := os.Getenv("CHECK_EDGECORE_ENVIRONMENT") == "false" || config.Modules.MetaManager.MetaServer.Enable
 			// Check running environment before run edge core
@@ -121,6 +132,8 @@ offering HTTP client capabilities to components of cloud to reach HTTP servers r
 				klog.Infof("Get IP address by custom interface successfully, %s: %s", config.Modules.Edged.CustomInterfaceName, config.Modules.Edged.NodeIP)
 			}
 
+			// the message channels are created when the modules start, configure them first
+			msgqueue.Configure(config.MessageQueues)
 			registerModules(config)
 
 			// enable module auto-restart feature
@@ -203,7 +216,12 @@ func registerModules(c *v1alpha2.EdgeCoreConfig) {
 	metamanager.Register(c.Modules.MetaManager)
 	servicebus.Register(c.Modules.ServiceBus)
 	edgestream.Register(c.Modules.EdgeStream, c.Modules.Edged.HostnameOverride, c.Modules.Edged.NodeIP)
+	imagemirror.Register(c.Modules.ImageMirror, c.Modules.EdgeHub, c.Modules.Edged.HostnameOverride, c.Modules.Edged.NodeIP)
+	admin.Register(c.Modules.Admin)
 	test.Register(c.Modules.DBTest)
+	// extension modules are registered after the built-in ones so that their names can not be taken over
+	extension.Register(c.Modules.ExtensionModules)
 	// Note: Need to put it to the end, and wait for all models to register before executing
 	dbm.InitDBConfig(c.DataBase.DriverName, c.DataBase.AliasName, c.DataBase.DataSource)
+	metamanager.InitStorage(c.DataBase)
 }
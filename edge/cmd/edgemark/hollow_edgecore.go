@@ -119,6 +119,7 @@ func run(config *hollowEdgeNodeConfig) {
 	metamanager.Register(c.Modules.MetaManager)
 
 	dbm.InitDBConfig(c.DataBase.DriverName, c.DataBase.AliasName, c.DataBase.DataSource)
+	metamanager.InitStorage(c.DataBase)
 
 	// start all modules
 	core.Run()
@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/beehive/pkg/core"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/kubeedge/edge/pkg/admin/config"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+)
+
+// admin serves the local admin API, which exposes the status of edgecore and the
+// actions for the operators on site without going through cloud
+type admin struct {
+	enable bool
+}
+
+var _ core.Module = (*admin)(nil)
+
+func newAdmin(enable bool) *admin {
+	return &admin{
+		enable: enable,
+	}
+}
+
+// Register register admin
+func Register(a *v1alpha2.Admin) {
+	if a == nil {
+		a = &v1alpha2.Admin{}
+	}
+	config.InitConfigure(a)
+	core.Register(newAdmin(a.Enable))
+}
+
+func (*admin) Name() string {
+	return modules.AdminModuleName
+}
+
+func (*admin) Group() string {
+	return modules.AdminGroup
+}
+
+func (a *admin) Enable() bool {
+	return a.enable
+}
+
+func (a *admin) Start() {
+	handler := newHandler(&edgecoreBackend{})
+	var servers []*http.Server
+
+	if config.Config.UnixSocket != "" {
+		listener, err := listenUnix(config.Config.UnixSocket)
+		if err != nil {
+			klog.Errorf("failed to listen on admin socket %s: %v", config.Config.UnixSocket, err)
+		} else {
+			server := newServer(handler)
+			servers = append(servers, server)
+			go serve(server, listener, config.Config.UnixSocket)
+		}
+	}
+
+	if config.Config.Address != "" {
+		tlsConfig, err := newTLSConfig(config.Config.TLSCAFile, config.Config.TLSCertFile, config.Config.TLSPrivateKeyFile)
+		if err != nil {
+			klog.Errorf("failed to load the certificates of admin API: %v", err)
+		} else if listener, err := net.Listen("tcp", config.Config.Address); err != nil {
+			klog.Errorf("failed to listen on admin address %s: %v", config.Config.Address, err)
+		} else {
+			server := newServer(handler)
+			servers = append(servers, server)
+			go serve(server, tls.NewListener(listener, tlsConfig), config.Config.Address)
+		}
+	}
+
+	<-beehiveContext.Done()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, server := range servers {
+		if err := server.Shutdown(ctx); err != nil {
+			klog.Warningf("failed to shutdown admin API: %v", err)
+		}
+	}
+}
+
+func newServer(handler http.Handler) *http.Server {
+	return &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+func serve(server *http.Server, listener net.Listener, address string) {
+	klog.Infof("admin API listens on %s", address)
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		klog.Errorf("admin API on %s stopped: %v", address, err)
+	}
+}
+
+// listenUnix listens on the socket which is accessible only by the owner,
+// the socket left by the previous process is removed
+func listenUnix(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// newTLSConfig requires the clients to present a certificate signed by the CA
+func newTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificate found in %s", caFile)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
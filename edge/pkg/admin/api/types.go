@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package api defines the paths and the payloads of the local admin API of edgecore,
+// it is shared by edgecore and the clients like keadm ctl.
+package api
+
+import (
+	"time"
+
+	"github.com/kubeedge/beehive/pkg/core"
+	"github.com/kubeedge/beehive/pkg/core/channel"
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/types"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
+)
+
+// paths of the admin API
+const (
+	StatusPath     = "/api/v1/status"
+	QueuesPath     = "/api/v1/queues"
+	DevicesPath    = "/api/v1/devices"
+	MappersPath    = "/api/v1/mappers"
+	ReconnectPath  = "/api/v1/actions/reconnect"
+	ResyncPath     = "/api/v1/actions/resync"
+	FlushCachePath = "/api/v1/actions/flush-cache"
+	ReconcilePath  = "/api/v1/actions/reconcile"
+	// BackupBoltPath serves a snapshot of the bbolt database of the metadata
+	BackupBoltPath = "/api/v1/backup/bolt"
+)
+
+// Status is the status of edgecore
+type Status struct {
+	Modules    []core.ModuleStatus              `json:"modules"`
+	Connection cloudconnection.ConnectionStatus `json:"connection"`
+	// PendingUpstream is the number of messages waiting to be sent to cloud,
+	// they are sent once the connection to cloud is recovered
+	PendingUpstream int `json:"pendingUpstream"`
+	// LastErrors are the last errors of the cloud connection and the modules
+	LastErrors []LastError `json:"lastErrors,omitempty"`
+	// Drift is the state of the drift detection between the database and cloud, nil if it is not enabled
+	Drift *DriftStatus `json:"drift,omitempty"`
+}
+
+// DriftStatus is the state of the drift detection
+type DriftStatus struct {
+	LastSentTime time.Time `json:"lastSentTime"`
+	// LastFull indicates whether the last digest sent lists the objects
+	LastFull       bool                          `json:"lastFull"`
+	LastResultTime time.Time                     `json:"lastResultTime"`
+	LastResult     *commontypes.MetaDigestResult `json:"lastResult,omitempty"`
+}
+
+// LastError is the last error of a component
+type LastError struct {
+	Component string    `json:"component"`
+	Error     string    `json:"error"`
+	Time      time.Time `json:"time"`
+}
+
+// Queues is the states of the message channels of the modules
+type Queues []channel.QueueStats
+
+// Device is the status of a device stored by DeviceTwin
+type Device struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	State      string `json:"state"`
+	LastOnline string `json:"lastOnline,omitempty"`
+}
+
+// Mappers is the status of the mappers registered to DeviceTwin
+type Mappers []types.MapperStatus
+
+// ActionResult is the result of an action
+type ActionResult struct {
+	Message string `json:"message"`
+	// Succeeded and Failed are the number of the objects the action succeeded or failed on
+	Succeeded int `json:"succeeded,omitempty"`
+	Failed    int `json:"failed,omitempty"`
+}
+
+// ErrorResponse is returned with the error status code
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core"
+	"github.com/kubeedge/beehive/pkg/core/channel"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/edge/pkg/admin/api"
+	connect "github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dmiserver"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtclient"
+	"github.com/kubeedge/kubeedge/edge/pkg/edgehub"
+	"github.com/kubeedge/kubeedge/edge/pkg/imagemirror"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager"
+	metaManagerConfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/config"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/digest"
+)
+
+// resyncResourceTypes are the resources stored at edge which are queried from cloud again on resync
+var resyncResourceTypes = []string{model.ResourceTypeConfigmap, model.ResourceTypeSecret}
+
+// edgecoreBackend is the backend reading the status from the running modules of edgecore
+type edgecoreBackend struct{}
+
+var _ backend = (*edgecoreBackend)(nil)
+
+func (*edgecoreBackend) Status() api.Status {
+	status := api.Status{
+		Modules:    core.GetModuleStatus(),
+		Connection: connect.GetStatus(),
+	}
+	for _, queue := range channel.NewChannelContext().QueueStats() {
+		if queue.Module == modules.EdgeHubModuleName {
+			status.PendingUpstream = queue.Depth + int(queue.Spilled)
+		}
+	}
+	if status.Connection.LastError != "" {
+		status.LastErrors = append(status.LastErrors, api.LastError{
+			Component: modules.EdgeHubModuleName,
+			Error:     status.Connection.LastError,
+			Time:      status.Connection.LastErrorTime,
+		})
+	}
+	if drift, ok := digest.GetStatus(); ok {
+		status.Drift = &api.DriftStatus{
+			LastSentTime:   drift.LastSentTime,
+			LastFull:       drift.LastFull,
+			LastResultTime: drift.LastResultTime,
+			LastResult:     drift.LastResult,
+		}
+	}
+	for _, module := range status.Modules {
+		if module.LastError != "" {
+			status.LastErrors = append(status.LastErrors, api.LastError{
+				Component: module.Name,
+				Error:     module.LastError,
+				Time:      module.ExitTime,
+			})
+		}
+	}
+	return status
+}
+
+func (*edgecoreBackend) Queues() api.Queues {
+	return channel.NewChannelContext().QueueStats()
+}
+
+func (*edgecoreBackend) Devices() ([]api.Device, error) {
+	devices, err := dtclient.QueryDeviceAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query devices: %v", err)
+	}
+	result := make([]api.Device, 0, len(*devices))
+	for _, device := range *devices {
+		result = append(result, api.Device{
+			ID:         device.ID,
+			Name:       device.Name,
+			State:      device.State,
+			LastOnline: device.LastOnline,
+		})
+	}
+	return result, nil
+}
+
+func (*edgecoreBackend) Mappers() api.Mappers {
+	return dmiserver.MapperStatuses()
+}
+
+func (*edgecoreBackend) Reconnect() error {
+	return edgehub.ForceReconnect()
+}
+
+// Resync asks MetaManager to query the resources stored at edge from cloud again,
+// MetaManager updates them in the database with the responses
+func (*edgecoreBackend) Resync(ctx context.Context) (api.ActionResult, error) {
+	result := api.ActionResult{Message: "resources resynced from cloud"}
+	if !connect.IsConnected() {
+		return result, errors.New("edgehub is not connected to cloud")
+	}
+	timeout := time.Duration(metaManagerConfig.Config.RemoteQueryTimeout) * time.Second
+	for _, resType := range resyncResourceTypes {
+		metas, err := dao.QueryAllMeta("type", resType)
+		if err != nil {
+			return result, fmt.Errorf("failed to query %s: %v", resType, err)
+		}
+		for _, meta := range *metas {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			message := model.NewMessage("").BuildRouter(modules.AdminModuleName, modules.MetaGroup, meta.Key, model.QueryOperation)
+			resp, err := beehiveContext.SendSync(modules.MetaManagerModuleName, *message, timeout)
+			if err == nil && resp.GetOperation() == model.ResponseErrorOperation {
+				err = fmt.Errorf("%v", resp.GetContent())
+			}
+			if err != nil {
+				klog.Warningf("failed to resync %s from cloud: %v", meta.Key, err)
+				result.Failed++
+				continue
+			}
+			result.Succeeded++
+		}
+	}
+	return result, nil
+}
+
+func (*edgecoreBackend) FlushCache(ctx context.Context) (int, error) {
+	return imagemirror.FlushCache(ctx)
+}
+
+func (*edgecoreBackend) BackupBolt(w io.Writer) (int64, error) {
+	return metamanager.BackupBolt(w)
+}
+
+// Reconcile sends the full digests to cloud, cloud fixes the drifted objects with them
+func (*edgecoreBackend) Reconcile() error {
+	return digest.RequestFull()
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/admin/api"
+)
+
+// backend provides the status and the actions served by the admin API
+type backend interface {
+	Status() api.Status
+	Queues() api.Queues
+	Devices() ([]api.Device, error)
+	Mappers() api.Mappers
+	Reconnect() error
+	Resync(ctx context.Context) (api.ActionResult, error)
+	FlushCache(ctx context.Context) (int, error)
+	Reconcile() error
+	BackupBolt(w io.Writer) (int64, error)
+}
+
+type handler struct {
+	backend backend
+}
+
+func newHandler(b backend) http.Handler {
+	h := &handler{backend: b}
+	mux := http.NewServeMux()
+	mux.HandleFunc(api.StatusPath, get(h.status))
+	mux.HandleFunc(api.QueuesPath, get(h.queues))
+	mux.HandleFunc(api.DevicesPath, get(h.devices))
+	mux.HandleFunc(api.MappersPath, get(h.mappers))
+	mux.HandleFunc(api.ReconnectPath, post(h.reconnect))
+	mux.HandleFunc(api.ResyncPath, post(h.resync))
+	mux.HandleFunc(api.FlushCachePath, post(h.flushCache))
+	mux.HandleFunc(api.ReconcilePath, post(h.reconcile))
+	mux.HandleFunc(api.BackupBoltPath, get(h.backupBolt))
+	return mux
+}
+
+func get(f http.HandlerFunc) http.HandlerFunc {
+	return allow(http.MethodGet, f)
+}
+
+func post(f http.HandlerFunc) http.HandlerFunc {
+	return allow(http.MethodPost, f)
+}
+
+func allow(method string, f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+			return
+		}
+		f(w, r)
+	}
+}
+
+func (h *handler) status(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, h.backend.Status())
+}
+
+func (h *handler) queues(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, h.backend.Queues())
+}
+
+func (h *handler) devices(w http.ResponseWriter, _ *http.Request) {
+	devices, err := h.backend.Devices()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, devices)
+}
+
+func (h *handler) mappers(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, h.backend.Mappers())
+}
+
+func (h *handler) reconnect(w http.ResponseWriter, _ *http.Request) {
+	if err := h.backend.Reconnect(); err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, api.ActionResult{Message: "reconnecting to cloud"})
+}
+
+func (h *handler) resync(w http.ResponseWriter, r *http.Request) {
+	result, err := h.backend.Resync(r.Context())
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *handler) flushCache(w http.ResponseWriter, r *http.Request) {
+	flushed, err := h.backend.FlushCache(r.Context())
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, api.ActionResult{
+		Message:   "image mirror cache flushed",
+		Succeeded: flushed,
+	})
+}
+
+func (h *handler) reconcile(w http.ResponseWriter, _ *http.Request) {
+	if err := h.backend.Reconcile(); err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, api.ActionResult{Message: "digests are sent to cloud to fix the drifted objects"})
+}
+
+// backupBolt streams a snapshot of the bbolt database, the client checks the snapshot
+// because an error can not be reported once the snapshot is partially written
+func (h *handler) backupBolt(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/octet-stream")
+	written, err := h.backend.BackupBolt(w)
+	if err == nil {
+		return
+	}
+	if written == 0 {
+		w.Header().Del("Content-Type")
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	klog.Errorf("failed to write the snapshot of the bbolt database: %v", err)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		klog.Errorf("failed to write admin API response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, api.ErrorResponse{Error: err.Error()})
+}
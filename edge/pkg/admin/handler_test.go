@@ -0,0 +1,228 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubeedge/beehive/pkg/core"
+	"github.com/kubeedge/beehive/pkg/core/channel"
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/types"
+	"github.com/kubeedge/kubeedge/edge/pkg/admin/api"
+)
+
+type fakeBackend struct {
+	reconnectErr error
+	reconnected  bool
+}
+
+func (*fakeBackend) Status() api.Status {
+	return api.Status{
+		Modules:         []core.ModuleStatus{{Name: "websocket", Running: true}},
+		PendingUpstream: 3,
+	}
+}
+
+func (*fakeBackend) Queues() api.Queues {
+	return api.Queues{{Module: "websocket", Depth: 3}}
+}
+
+func (*fakeBackend) Devices() ([]api.Device, error) {
+	return []api.Device{{ID: "dev1", Name: "dev1", State: "online"}}, nil
+}
+
+func (*fakeBackend) Mappers() api.Mappers {
+	return api.Mappers{types.MapperStatus{Name: "modbus", State: "online"}}
+}
+
+func (*fakeBackend) BackupBolt(w io.Writer) (int64, error) {
+	n, err := w.Write([]byte("snapshot"))
+	return int64(n), err
+}
+
+func (b *fakeBackend) Reconnect() error {
+	if b.reconnectErr != nil {
+		return b.reconnectErr
+	}
+	b.reconnected = true
+	return nil
+}
+
+func (*fakeBackend) Resync(context.Context) (api.ActionResult, error) {
+	return api.ActionResult{Succeeded: 2, Failed: 1}, nil
+}
+
+func (*fakeBackend) FlushCache(context.Context) (int, error) {
+	return 0, errors.New("image mirror is not running")
+}
+
+func (*fakeBackend) Reconcile() error {
+	return nil
+}
+
+func TestHandler(t *testing.T) {
+	b := &fakeBackend{}
+	server := httptest.NewServer(newHandler(b))
+	defer server.Close()
+
+	cases := []struct {
+		name     string
+		method   string
+		path     string
+		code     int
+		response interface{}
+		expected interface{}
+	}{
+		{
+			name:     "status",
+			method:   http.MethodGet,
+			path:     api.StatusPath,
+			code:     http.StatusOK,
+			response: &api.Status{},
+			expected: func() *api.Status { s := b.Status(); return &s }(),
+		},
+		{
+			name:     "queues",
+			method:   http.MethodGet,
+			path:     api.QueuesPath,
+			code:     http.StatusOK,
+			response: &api.Queues{},
+			expected: &api.Queues{channel.QueueStats{Module: "websocket", Depth: 3}},
+		},
+		{
+			name:     "devices",
+			method:   http.MethodGet,
+			path:     api.DevicesPath,
+			code:     http.StatusOK,
+			response: &[]api.Device{},
+			expected: &[]api.Device{{ID: "dev1", Name: "dev1", State: "online"}},
+		},
+		{
+			name:     "mappers",
+			method:   http.MethodGet,
+			path:     api.MappersPath,
+			code:     http.StatusOK,
+			response: &api.Mappers{},
+			expected: &api.Mappers{{Name: "modbus", State: "online"}},
+		},
+		{
+			name:     "reconnect",
+			method:   http.MethodPost,
+			path:     api.ReconnectPath,
+			code:     http.StatusAccepted,
+			response: &api.ActionResult{},
+			expected: &api.ActionResult{Message: "reconnecting to cloud"},
+		},
+		{
+			name:     "resync",
+			method:   http.MethodPost,
+			path:     api.ResyncPath,
+			code:     http.StatusOK,
+			response: &api.ActionResult{},
+			expected: &api.ActionResult{Succeeded: 2, Failed: 1},
+		},
+		{
+			name:     "flush cache failed",
+			method:   http.MethodPost,
+			path:     api.FlushCachePath,
+			code:     http.StatusConflict,
+			response: &api.ErrorResponse{},
+			expected: &api.ErrorResponse{Error: "image mirror is not running"},
+		},
+		{
+			name:     "reconcile",
+			method:   http.MethodPost,
+			path:     api.ReconcilePath,
+			code:     http.StatusAccepted,
+			response: &api.ActionResult{},
+			expected: &api.ActionResult{Message: "digests are sent to cloud to fix the drifted objects"},
+		},
+		{
+			name:     "method not allowed",
+			method:   http.MethodGet,
+			path:     api.ReconnectPath,
+			code:     http.StatusMethodNotAllowed,
+			response: &api.ErrorResponse{},
+			expected: &api.ErrorResponse{Error: "method GET is not allowed"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req, err := http.NewRequest(c.method, server.URL+c.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != c.code {
+				t.Errorf("expected status code %d, got %d", c.code, resp.StatusCode)
+			}
+			if err := json.NewDecoder(resp.Body).Decode(c.response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			expected, _ := json.Marshal(c.expected)
+			got, _ := json.Marshal(c.response)
+			if string(expected) != string(got) {
+				t.Errorf("expected response %s, got %s", expected, got)
+			}
+		})
+	}
+
+	if !b.reconnected {
+		t.Errorf("expected the backend to reconnect")
+	}
+
+	resp, err := http.Get(server.URL + api.BackupBoltPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	snapshot, err := io.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != http.StatusOK || string(snapshot) != "snapshot" {
+		t.Errorf("expected the snapshot of the bbolt database, got %d %q %v", resp.StatusCode, snapshot, err)
+	}
+}
+
+func TestListenUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "admin", "admin.sock")
+	for i := 0; i < 2; i++ {
+		// the socket left by the previous listener is replaced
+		listener, err := listenUnix(path)
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.Mode().Perm() != 0600 {
+			t.Errorf("expected the socket to be accessible only by the owner: %v, %v", info, err)
+		}
+		listener.(*net.UnixListener).SetUnlinkOnClose(false)
+		listener.Close()
+	}
+}
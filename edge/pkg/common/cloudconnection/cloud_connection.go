@@ -19,6 +19,7 @@ package cloudconnection
 import (
 	"errors"
 	"sync"
+	"time"
 )
 
 // constants for cloud connection
@@ -28,9 +29,8 @@ const (
 )
 
 var (
-	// isCloudConnected indicate Whether the connection was
-	// successfully established between edge and cloud
-	isCloudConnected = false
+	// status indicates the state of the connection between edge and cloud
+	status ConnectionStatus
 
 	lock sync.RWMutex
 
@@ -38,18 +38,49 @@ var (
 	ErrConnectionLost = errors.New("connection lost between EdgeCore and CloudCore")
 )
 
-// SetConnected set isCloudConnected value
+// ConnectionStatus is the state of the connection between EdgeCore and CloudCore
+type ConnectionStatus struct {
+	// Connected indicates whether the connection is established
+	Connected            bool      `json:"connected"`
+	LastConnectedTime    time.Time `json:"lastConnectedTime"`
+	LastDisconnectedTime time.Time `json:"lastDisconnectedTime"`
+	// LastError is the last error failing or breaking the connection
+	LastError     string    `json:"lastError,omitempty"`
+	LastErrorTime time.Time `json:"lastErrorTime"`
+}
+
+// SetConnected set the connection state
 // true indicates edge and cloud establish connection successfully
 // false indicates edge and cloud connection interrupted.
 func SetConnected(isConnected bool) {
 	lock.Lock()
 	defer lock.Unlock()
-	isCloudConnected = isConnected
+	if isConnected {
+		status.LastConnectedTime = time.Now()
+	} else if status.Connected {
+		status.LastDisconnectedTime = time.Now()
+	}
+	status.Connected = isConnected
 }
 
-// IsConnected return isCloudConnected
+// IsConnected return whether edge and cloud are connected
 func IsConnected() bool {
 	lock.RLock()
 	defer lock.RUnlock()
-	return isCloudConnected
+	return status.Connected
+}
+
+// SetLastError records the error failing or breaking the connection
+func SetLastError(err error) {
+	lock.Lock()
+	defer lock.Unlock()
+	status.LastError = err.Error()
+	status.LastErrorTime = time.Now()
+}
+
+// GetStatus returns the state of the connection
+func GetStatus() ConnectionStatus {
+	lock.RLock()
+	defer lock.RUnlock()
+	return status
 }
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepolicy
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"k8s.io/klog/v2"
+)
+
+// maxCacheEntries bounds the verified cache, entries of old policies are dropped when it is exceeded
+const maxCacheEntries = 4096
+
+var (
+	cachesLock sync.Mutex
+	caches     = map[string]*verifiedCache{}
+)
+
+// verifiedCache maps the verified image references to their digests, it is persisted
+// in a file shared by all verifiers of the process
+type verifiedCache struct {
+	lock    sync.Mutex
+	file    string
+	loaded  bool
+	entries map[string]string
+}
+
+func getVerifiedCache(file string) *verifiedCache {
+	cachesLock.Lock()
+	defer cachesLock.Unlock()
+	c, ok := caches[file]
+	if !ok {
+		c = &verifiedCache{file: file, entries: map[string]string{}}
+		caches[file] = c
+	}
+	return c
+}
+
+// load reads the cache file once, the caller must hold the lock
+func (c *verifiedCache) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+	data, err := os.ReadFile(c.file)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			klog.Warningf("failed to read image verification cache %s: %v", c.file, err)
+		}
+		return
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		klog.Warningf("failed to parse image verification cache %s: %v", c.file, err)
+		c.entries = map[string]string{}
+	}
+}
+
+func (c *verifiedCache) get(key string) (string, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.load()
+	value, ok := c.entries[key]
+	return value, ok
+}
+
+func (c *verifiedCache) put(entries map[string]string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.load()
+	changed := false
+	for key, value := range entries {
+		if c.entries[key] != value {
+			c.entries[key] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	for key := range c.entries {
+		if len(c.entries) <= maxCacheEntries {
+			break
+		}
+		if _, ok := entries[key]; !ok {
+			delete(c.entries, key)
+		}
+	}
+	if err := c.save(); err != nil {
+		klog.Warningf("failed to save image verification cache %s: %v", c.file, err)
+	}
+}
+
+// save writes the cache file atomically, the caller must hold the lock
+func (c *verifiedCache) save() error {
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.file), 0700); err != nil {
+		return err
+	}
+	tmp := c.file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.file)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepolicy
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/containerd/containerd/remotes"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// CosignSignatureAnnotation is the annotation of the signature layers holding the base64 encoded signature
+	CosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// maxSignatureSize is the max size of the signature manifest and payloads
+	maxSignatureSize = 1 << 20
+)
+
+// simpleSigning is the payload signed by cosign
+type simpleSigning struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// cosignSignatureTag returns the tag cosign stores the signatures of the image digest in
+func cosignSignatureTag(dgst digest.Digest) string {
+	return fmt.Sprintf("%s-%s.sig", dgst.Algorithm(), dgst.Encoded())
+}
+
+// verifySignature checks one of the cosign signatures of the image digest is signed by one of the keys
+func (v *Verifier) verifySignature(ctx context.Context, resolver remotes.Resolver, name string, dgst digest.Digest) error {
+	ref := name + ":" + cosignSignatureTag(dgst)
+	_, desc, err := resolver.Resolve(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("no cosign signature found: %v", err)
+	}
+	fetcher, err := resolver.Fetcher(ctx, ref)
+	if err != nil {
+		return err
+	}
+	data, err := fetchVerified(ctx, fetcher, desc)
+	if err != nil {
+		return fmt.Errorf("failed to fetch signature manifest: %v", err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("invalid signature manifest: %v", err)
+	}
+
+	for _, layer := range manifest.Layers {
+		encoded, ok := layer.Annotations[CosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		signature, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			continue
+		}
+		payload, err := fetchVerified(ctx, fetcher, layer)
+		if err != nil {
+			return fmt.Errorf("failed to fetch signature payload: %v", err)
+		}
+		var signing simpleSigning
+		if err := json.Unmarshal(payload, &signing); err != nil || signing.Critical.Image.DockerManifestDigest != dgst.String() {
+			continue
+		}
+		for _, key := range v.keys {
+			if verifyPayload(key, payload, signature) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("no signature of digest %s is verified by the cosign public keys", dgst)
+}
+
+// fetchVerified fetches the content and checks it matches the digest of the descriptor
+func fetchVerified(ctx context.Context, fetcher remotes.Fetcher, desc ocispec.Descriptor) ([]byte, error) {
+	if desc.Size > maxSignatureSize {
+		return nil, fmt.Errorf("size %d of %s exceeds the limit %d", desc.Size, desc.Digest, maxSignatureSize)
+	}
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxSignatureSize))
+	if err != nil {
+		return nil, err
+	}
+	if desc.Digest.Algorithm().Available() && desc.Digest.Algorithm().FromBytes(data) != desc.Digest {
+		return nil, fmt.Errorf("content of %s mismatches its digest", desc.Digest)
+	}
+	return data, nil
+}
+
+// verifyPayload verifies the signature of the payload, the same as cosign does for each key type
+func verifyPayload(key crypto.PublicKey, payload, signature []byte) bool {
+	hash := sha256.Sum256(payload)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, hash[:], signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], signature) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, payload, signature)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepolicy
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/api/apis/operations/v1alpha1"
+)
+
+const (
+	// DefaultCacheFile is the file the verified images are cached in, so the images verified
+	// before are still allowed when the registries are unreachable
+	DefaultCacheFile = "/var/lib/kubeedge/imagepolicy/verified.json"
+	// verifyTimeout is the timeout to verify the signature of an image
+	verifyTimeout = 30 * time.Second
+)
+
+// Policy specifies how images are verified before they are used
+type Policy struct {
+	// RequireDigest requires images to be referenced by digest
+	RequireDigest bool `json:"requireDigest,omitempty"`
+	// AllowedRegistries are the registry hosts or registry hosts with repository prefixes
+	// images are allowed to come from, images from any registry are allowed if it is empty
+	AllowedRegistries []string `json:"allowedRegistries,omitempty"`
+	// CosignPublicKeys are the PEM encoded public keys, images must have a cosign signature
+	// verified by one of them if it is not empty
+	CosignPublicKeys []string `json:"cosignPublicKeys,omitempty"`
+}
+
+// Empty returns true if the policy allows all images
+func (p Policy) Empty() bool {
+	return !p.RequireDigest && len(p.AllowedRegistries) == 0 && len(p.CosignPublicKeys) == 0
+}
+
+// FromJobPolicy returns the policy of the verification policy of ImagePrePullJob
+func FromJobPolicy(p *v1alpha1.ImageVerificationPolicy) Policy {
+	if p == nil {
+		return Policy{}
+	}
+	return Policy{
+		RequireDigest:     p.RequireDigest,
+		AllowedRegistries: p.AllowedRegistries,
+		CosignPublicKeys:  p.CosignPublicKeys,
+	}
+}
+
+// FromEdgedConfig returns the policy of the image verification config of edged, the public keys are read from the files
+func FromEdgedConfig(c *v1alpha2.ImageVerification) (Policy, error) {
+	if c == nil {
+		return Policy{}, nil
+	}
+	policy := Policy{
+		RequireDigest:     c.RequireDigest,
+		AllowedRegistries: c.AllowedRegistries,
+	}
+	for _, file := range c.CosignPublicKeyFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return Policy{}, fmt.Errorf("failed to read cosign public key: %v", err)
+		}
+		policy.CosignPublicKeys = append(policy.CosignPublicKeys, string(data))
+	}
+	return policy, nil
+}
+
+// Credentials returns the username and secret of the registry host, the same as docker.WithAuthCreds
+type Credentials func(host string) (string, string, error)
+
+// Verifier verifies images against a policy
+type Verifier struct {
+	policy Policy
+	keys   []crypto.PublicKey
+	// policyHash distinguishes the cache entries of different policies
+	policyHash string
+	cache      *verifiedCache
+	// newResolver returns the resolver of the registries
+	newResolver func(creds Credentials) remotes.Resolver
+}
+
+// NewVerifier returns a verifier of the policy caching the verified images in cacheFile
+func NewVerifier(policy Policy, cacheFile string) (*Verifier, error) {
+	keys := make([]crypto.PublicKey, 0, len(policy.CosignPublicKeys))
+	for i, data := range policy.CosignPublicKeys {
+		key, err := parsePublicKey([]byte(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cosign public key %d: %v", i, err)
+		}
+		keys = append(keys, key)
+	}
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return &Verifier{
+		policy:     policy,
+		keys:       keys,
+		policyHash: hex.EncodeToString(sum[:8]),
+		cache:      getVerifiedCache(cacheFile),
+		newResolver: func(creds Credentials) remotes.Resolver {
+			var opts []docker.RegistryOpt
+			if creds != nil {
+				opts = append(opts, docker.WithAuthorizer(docker.NewDockerAuthorizer(docker.WithAuthCreds(creds))))
+			}
+			return docker.NewResolver(docker.ResolverOptions{Hosts: docker.ConfigureDefaultRegistries(opts...)})
+		},
+	}, nil
+}
+
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// Verify verifies the image, creds is used to access the registry of the image and may be nil.
+// It returns the image to use, which is pinned to the digest whose signature is verified, so the image
+// pulled is the one verified even if the tag is moved after verification.
+func (v *Verifier) Verify(ctx context.Context, image string, creds Credentials) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("invalid image reference %s: %v", image, err)
+	}
+	if !v.registryAllowed(named) {
+		return "", fmt.Errorf("registry of image %s is not in the allowed registries %v", image, v.policy.AllowedRegistries)
+	}
+	canonical, pinned := named.(reference.Canonical)
+	if v.policy.RequireDigest && !pinned {
+		return "", fmt.Errorf("image %s is not referenced by digest", image)
+	}
+	if len(v.keys) == 0 {
+		return image, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, verifyTimeout)
+	defer cancel()
+	resolver := v.newResolver(creds)
+	var dgst digest.Digest
+	if pinned {
+		dgst = canonical.Digest()
+	} else {
+		tagged := reference.TagNameOnly(named)
+		_, desc, err := resolver.Resolve(ctx, tagged.String())
+		if err != nil {
+			// the tag can not be resolved, e.g. the edge node is offline, the digest verified before is still allowed
+			if cached, ok := v.cache.get(v.cacheKey(tagged.String())); ok {
+				if dgst, parseErr := digest.Parse(cached); parseErr == nil && v.verified(named, dgst) {
+					klog.Warningf("failed to resolve image %s, use the digest %s verified before: %v", image, dgst, err)
+					return pin(tagged, dgst)
+				}
+			}
+			return "", fmt.Errorf("failed to resolve image %s: %v", image, err)
+		}
+		dgst = desc.Digest
+	}
+
+	if !v.verified(named, dgst) {
+		if err := v.verifySignature(ctx, resolver, named.Name(), dgst); err != nil {
+			return "", fmt.Errorf("failed to verify the signature of image %s: %v", image, err)
+		}
+	}
+	// the verified images are cached by digest, the tags only map to the digests verified for them
+	entries := map[string]string{v.cacheKey(named.Name() + "@" + dgst.String()): dgst.String()}
+	if pinned {
+		v.cache.put(entries)
+		return image, nil
+	}
+	tagged := reference.TagNameOnly(named)
+	entries[v.cacheKey(tagged.String())] = dgst.String()
+	v.cache.put(entries)
+	return pin(tagged, dgst)
+}
+
+// verified returns whether the digest of the repository is verified before
+func (v *Verifier) verified(named reference.Named, dgst digest.Digest) bool {
+	_, ok := v.cache.get(v.cacheKey(named.Name() + "@" + dgst.String()))
+	return ok
+}
+
+// pin returns the image of the tag pinned to the digest, the tag is kept for readability
+func pin(tagged reference.Named, dgst digest.Digest) (string, error) {
+	canonical, err := reference.WithDigest(tagged, dgst)
+	if err != nil {
+		return "", err
+	}
+	return reference.FamiliarString(canonical), nil
+}
+
+// registryAllowed checks the image comes from one of the allowed registries
+func (v *Verifier) registryAllowed(named reference.Named) bool {
+	if len(v.policy.AllowedRegistries) == 0 {
+		return true
+	}
+	for _, allowed := range v.policy.AllowedRegistries {
+		allowed = strings.TrimSuffix(allowed, "/")
+		if allowed == reference.Domain(named) || allowed == named.Name() || strings.HasPrefix(named.Name(), allowed+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *Verifier) cacheKey(ref string) string {
+	return v.policyHash + "|" + ref
+}
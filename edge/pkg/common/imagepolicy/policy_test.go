@@ -0,0 +1,196 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepolicy
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func newKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// fakeRegistry serves the image app:v1, and the cosign signature of it if signer is not nil
+func fakeRegistry(t *testing.T, signer *ecdsa.PrivateKey) (*httptest.Server, digest.Digest) {
+	manifest := []byte(`{"schemaVersion":2,"mediaType":"` + ocispec.MediaTypeImageManifest + `"}`)
+	manifestDigest := digest.FromBytes(manifest)
+
+	blobs := map[string][]byte{}
+	manifests := map[string][]byte{"v1": manifest, manifestDigest.String(): manifest}
+	if signer != nil {
+		payload := []byte(`{"critical":{"identity":{"docker-reference":"app"},"image":{"docker-manifest-digest":"` +
+			manifestDigest.String() + `"},"type":"cosign container image signature"},"optional":null}`)
+		hash := sha256.Sum256(payload)
+		signature, err := ecdsa.SignASN1(rand.Reader, signer, hash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		payloadDigest := digest.FromBytes(payload)
+		blobs[payloadDigest.String()] = payload
+		sigManifest, _ := json.Marshal(ocispec.Manifest{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Layers: []ocispec.Descriptor{{
+				MediaType:   "application/vnd.dev.cosign.simplesigning.v1+json",
+				Digest:      payloadDigest,
+				Size:        int64(len(payload)),
+				Annotations: map[string]string{CosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature)},
+			}},
+		})
+		manifests[cosignSignatureTag(manifestDigest)] = sigManifest
+		manifests[digest.FromBytes(sigManifest).String()] = sigManifest
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ref, ok := strings.CutPrefix(r.URL.Path, "/v2/app/manifests/"); ok {
+			if data, ok := manifests[ref]; ok {
+				w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+				w.Header().Set("Docker-Content-Digest", digest.FromBytes(data).String())
+				w.Write(data)
+				return
+			}
+		}
+		if ref, ok := strings.CutPrefix(r.URL.Path, "/v2/app/blobs/"); ok {
+			if data, ok := blobs[ref]; ok {
+				w.Write(data)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}))
+	return server, manifestDigest
+}
+
+func newTestVerifier(t *testing.T, policy Policy, cacheFile string) *Verifier {
+	v, err := NewVerifier(policy, cacheFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.newResolver = func(Credentials) remotes.Resolver {
+		return docker.NewResolver(docker.ResolverOptions{
+			Hosts: docker.ConfigureDefaultRegistries(docker.WithPlainHTTP(docker.MatchAllHosts)),
+		})
+	}
+	return v
+}
+
+func TestVerifyPolicy(t *testing.T) {
+	v := newTestVerifier(t, Policy{
+		RequireDigest:     true,
+		AllowedRegistries: []string{"docker.io/library", "registry.example.com"},
+	}, filepath.Join(t.TempDir(), "verified.json"))
+	dgst := digest.FromString("image").String()
+
+	cases := map[string]bool{
+		"nginx@" + dgst:                          true,
+		"docker.io/library/nginx@" + dgst:        true,
+		"registry.example.com/team/app@" + dgst:  true,
+		"nginx:1.25":                             false,
+		"docker.io/someone/nginx@" + dgst:        false,
+		"registry.example.com.evil/app@" + dgst:  false,
+		"registry.example.com:5000/app@" + dgst:  false,
+		"invalid image reference@sha256:" + dgst: false,
+	}
+	for image, allowed := range cases {
+		_, err := v.Verify(context.Background(), image, nil)
+		if allowed && err != nil {
+			t.Errorf("expected image %s to be allowed, got %v", image, err)
+		}
+		if !allowed && err == nil {
+			t.Errorf("expected image %s to be rejected", image)
+		}
+	}
+}
+
+func TestVerifyCosignSignature(t *testing.T) {
+	signer, publicKey := newKey(t)
+	_, otherKey := newKey(t)
+	cacheFile := filepath.Join(t.TempDir(), "verified.json")
+
+	signed, dgst := fakeRegistry(t, signer)
+	defer signed.Close()
+	host := strings.TrimPrefix(signed.URL, "http://")
+
+	v := newTestVerifier(t, Policy{CosignPublicKeys: []string{publicKey}}, cacheFile)
+	// the tag is pinned to the verified digest
+	pinned := host + "/app:v1@" + dgst.String()
+	if image, err := v.Verify(context.Background(), host+"/app:v1", nil); err != nil || image != pinned {
+		t.Fatalf("expected the signed image to be verified and pinned to %s, got %s %v", pinned, image, err)
+	}
+	if image, err := v.Verify(context.Background(), host+"/app@"+dgst.String(), nil); err != nil || image != host+"/app@"+dgst.String() {
+		t.Fatalf("expected the signed image digest to be verified, got %s %v", image, err)
+	}
+
+	other := newTestVerifier(t, Policy{CosignPublicKeys: []string{otherKey}}, cacheFile)
+	if _, err := other.Verify(context.Background(), host+"/app:v1", nil); err == nil {
+		t.Error("expected the image signed by another key to be rejected")
+	}
+
+	unsigned, _ := fakeRegistry(t, nil)
+	defer unsigned.Close()
+	if _, err := v.Verify(context.Background(), strings.TrimPrefix(unsigned.URL, "http://")+"/app:v1", nil); err == nil {
+		t.Error("expected the unsigned image to be rejected")
+	}
+
+	// the verified digest is still allowed when the registry is unreachable, also after edgecore restarts
+	signed.Close()
+	caches = map[string]*verifiedCache{}
+	v = newTestVerifier(t, Policy{CosignPublicKeys: []string{publicKey}}, cacheFile)
+	if image, err := v.Verify(context.Background(), host+"/app:v1", nil); err != nil || image != pinned {
+		t.Errorf("expected the verified image to be allowed offline and pinned to %s, got %s %v", pinned, image, err)
+	}
+	if _, err := v.Verify(context.Background(), host+"/app:v2", nil); err == nil {
+		t.Error("expected the image never verified to be rejected offline")
+	}
+
+	// a tag mapped to a digest which is not verified is rejected offline
+	v.cache.put(map[string]string{v.cacheKey(host + "/app:v3"): digest.FromString("other").String()})
+	if _, err := v.Verify(context.Background(), host+"/app:v3", nil); err == nil {
+		t.Error("expected the tag of the digest never verified to be rejected offline")
+	}
+}
+
+func TestNewVerifierInvalidKey(t *testing.T) {
+	if _, err := NewVerifier(Policy{CosignPublicKeys: []string{"not a key"}}, ""); err == nil {
+		t.Error("expected the invalid key to fail")
+	}
+}
@@ -15,4 +15,8 @@ const (
 	UserGroup = "user"
 	// StreamGroup group
 	StreamGroup = "edgestream"
+	// ImageMirrorGroup group
+	ImageMirrorGroup = "imagemirror"
+	// AdminGroup group
+	AdminGroup = "admin"
 )
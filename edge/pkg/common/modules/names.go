@@ -15,4 +15,8 @@ const (
 	EdgeHubModuleName = "websocket"
 	// MetaManagerModuleName metamanager module name
 	MetaManagerModuleName = "metamanager"
+	// ImageMirrorModuleName name
+	ImageMirrorModuleName = "imagemirror"
+	// AdminModuleName name
+	AdminModuleName = "admin"
 )
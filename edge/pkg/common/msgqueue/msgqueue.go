@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package msgqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/beehive/pkg/core/channel"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+)
+
+// QueuesPath is the path of the debug endpoint which dumps the states of the message channels
+const QueuesPath = "/debug/beehive/queues"
+
+// Configure sets the message channel configs of the modules, it must be called before the modules start.
+// The debug endpoint is started if DebugAddress is set.
+func Configure(c *v1alpha2.MessageQueues) {
+	if c == nil {
+		return
+	}
+	ctx := channel.NewChannelContext()
+	ctx.SetDefaultQueueConfig(toQueueConfig(c.Default, c.SpillDir))
+	for module, queue := range c.Modules {
+		ctx.SetQueueConfig(module, toQueueConfig(queue, c.SpillDir))
+	}
+
+	if c.DebugAddress != "" {
+		go serveDebug(c.DebugAddress, ctx)
+	}
+}
+
+func toQueueConfig(q v1alpha2.MessageQueue, spillDir string) channel.QueueConfig {
+	return channel.QueueConfig{
+		Capacity:     int(q.Capacity),
+		Policy:       channel.OverflowPolicy(q.OverflowPolicy),
+		BlockTimeout: time.Duration(q.BlockTimeout) * time.Second,
+		SpillDir:     spillDir,
+		MaxSpillSize: int64(q.MaxSpillSize) * 1024 * 1024,
+	}
+}
+
+func newDebugHandler(ctx *channel.Context) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(QueuesPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(ctx.QueueStats()); err != nil {
+			klog.Errorf("failed to write queue states: %v", err)
+		}
+	})
+	return mux
+}
+
+func serveDebug(address string, ctx *channel.Context) {
+	server := &http.Server{
+		Addr:              address,
+		Handler:           newDebugHandler(ctx),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-beehiveContext.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			klog.Errorf("failed to shutdown message queue debug server: %v", err)
+		}
+	}()
+	klog.Infof("message queue debug endpoint listening on %s%s", address, QueuesPath)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		klog.Errorf("message queue debug server exited: %v", err)
+	}
+}
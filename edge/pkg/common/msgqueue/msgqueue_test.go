@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package msgqueue
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/beehive/pkg/common"
+	"github.com/kubeedge/beehive/pkg/core/channel"
+	"github.com/kubeedge/beehive/pkg/core/model"
+)
+
+func TestConfigureAndDump(t *testing.T) {
+	Configure(&v1alpha2.MessageQueues{
+		Default: v1alpha2.MessageQueue{Capacity: 16},
+		Modules: map[string]v1alpha2.MessageQueue{
+			"msgqueue-test": {Capacity: 1, OverflowPolicy: v1alpha2.OverflowPolicyDropNewest, BlockTimeout: 1},
+		},
+	})
+
+	ctx := channel.NewChannelContext()
+	ctx.AddModule(&common.ModuleInfo{ModuleName: "msgqueue-test", ModuleType: common.MsgCtxTypeChannel})
+	ctx.AddModule(&common.ModuleInfo{ModuleName: "msgqueue-default", ModuleType: common.MsgCtxTypeChannel})
+	defer ctx.Cleanup("msgqueue-test")
+	defer ctx.Cleanup("msgqueue-default")
+	for i := 0; i < 3; i++ {
+		ctx.Send("msgqueue-test", *model.NewMessage(""))
+	}
+
+	recorder := httptest.NewRecorder()
+	newDebugHandler(ctx).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, QueuesPath, nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d", recorder.Code)
+	}
+	var stats []channel.QueueStats
+	if err := json.Unmarshal(recorder.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("failed to decode queue states: %v", err)
+	}
+
+	expected := map[string]channel.QueueStats{
+		"msgqueue-default": {Module: "msgqueue-default", Policy: channel.OverflowBlock, Capacity: 16},
+		"msgqueue-test": {Module: "msgqueue-test", Policy: channel.OverflowDropNewest, Capacity: 1,
+			Depth: 1, Enqueued: 1, Dropped: 2},
+	}
+	for _, s := range stats {
+		want, ok := expected[s.Module]
+		if !ok {
+			continue
+		}
+		s.AvgEnqueueWait, s.MaxEnqueueWait = 0, 0
+		if s != want {
+			t.Errorf("expected %+v, got %+v", want, s)
+		}
+		delete(expected, s.Module)
+	}
+	if len(expected) != 0 {
+		t.Errorf("missing queue states: %v", expected)
+	}
+}
+
+func TestToQueueConfig(t *testing.T) {
+	got := toQueueConfig(v1alpha2.MessageQueue{
+		Capacity:       10,
+		OverflowPolicy: v1alpha2.OverflowPolicySpillToDisk,
+		BlockTimeout:   3,
+		MaxSpillSize:   2,
+	}, "/var/lib/kubeedge/beehive")
+	expected := channel.QueueConfig{
+		Capacity:     10,
+		Policy:       channel.OverflowSpillToDisk,
+		BlockTimeout: 3 * time.Second,
+		SpillDir:     "/var/lib/kubeedge/beehive",
+		MaxSpillSize: 2 * 1024 * 1024,
+	}
+	if got != expected {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
@@ -26,12 +26,15 @@ import (
 type DeviceTwinUpdate struct {
 	types.BaseMessage
 	Twin map[string]*types.MsgTwin `json:"twin"`
+	// History, the samples of the twins reported before the values in Twin, sorted by time
+	History map[string][]types.TwinHistorySample `json:"history,omitempty"`
 }
 
 // DeviceStateUpdate the structure of device state update.
 type DeviceStateUpdate struct {
 	types.BaseMessage
-	State string
+	State  string
+	Reason string `json:"reason,omitempty"`
 }
 
 // getTimestamp get current timestamp.
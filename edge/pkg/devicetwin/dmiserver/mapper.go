@@ -0,0 +1,240 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dmiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/context"
+	"k8s.io/klog/v2"
+
+	pb "github.com/kubeedge/api/apis/dmi/v1beta1"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	beehiveModel "github.com/kubeedge/beehive/pkg/core/model"
+	deviceconst "github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/types"
+	connect "github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtcommon"
+	"github.com/kubeedge/kubeedge/pkg/util"
+)
+
+const (
+	// MapperStateOnline means the mapper sends heartbeats and is healthy
+	MapperStateOnline = "online"
+	// MapperStateDegraded means the mapper sends heartbeats but reports it is not healthy
+	MapperStateDegraded = "degraded"
+	// MapperStateOffline means the mapper misses heartbeats
+	MapperStateOffline = "offline"
+
+	// DefaultMapperHeartbeatInterval is the heartbeat interval of a mapper not reporting its interval
+	DefaultMapperHeartbeatInterval = 10 * time.Second
+	// MapperHeartbeatTolerance is the number of missed heartbeats before a mapper is offline
+	MapperHeartbeatTolerance = 3
+
+	// mapperHealthOK is the state reported by a healthy mapper
+	mapperHealthOK = "ok"
+	// mapperCheckPeriod is the period to check the heartbeats of mappers
+	mapperCheckPeriod = time.Second
+)
+
+// monitoredCache is the cache of the running MonitorMappers, it serves MapperStatuses
+var monitoredCache atomic.Pointer[DMICache]
+
+// MapperStatus is the liveness and health of a mapper maintained by its heartbeats
+type MapperStatus struct {
+	types.MapperStatus
+	// Interval is the heartbeat interval of the mapper
+	Interval time.Duration
+}
+
+// MapperHeartbeat updates the status of the mapper, a mapper unknown to edgecore is asked to register again.
+// Heartbeats are not rate limited like the other requests, otherwise a burst of requests could make mappers offline.
+func (s *server) MapperHeartbeat(_ context.Context, in *pb.MapperHeartbeatRequest) (*pb.MapperHeartbeatResponse, error) {
+	if in.MapperName == "" {
+		return nil, errors.New("mapper name is empty")
+	}
+
+	s.dmiCache.MapperMu.Lock()
+	mapper, ok := s.dmiCache.MapperList[in.MapperName]
+	if !ok {
+		s.dmiCache.MapperMu.Unlock()
+		klog.Warningf("receive heartbeat of unregistered mapper %s", in.MapperName)
+		return &pb.MapperHeartbeatResponse{Reregister: true}, nil
+	}
+	changed := s.dmiCache.updateMapperStatus(mapper, in, time.Now())
+	s.dmiCache.MapperMu.Unlock()
+
+	if changed {
+		reportMapperStatus(s.dmiCache)
+	}
+	return &pb.MapperHeartbeatResponse{}, nil
+}
+
+// updateMapperStatus records the heartbeat of the mapper, it returns true if the state of the mapper changes.
+// MapperMu must be held by the caller.
+func (c *DMICache) updateMapperStatus(mapper *pb.MapperInfo, in *pb.MapperHeartbeatRequest, now time.Time) bool {
+	state, reason := MapperStateOnline, ""
+	if in.State != "" && in.State != mapperHealthOK {
+		state, reason = MapperStateDegraded, in.Reason
+		if reason == "" {
+			reason = fmt.Sprintf("mapper reports state %s", in.State)
+		}
+	}
+	interval := DefaultMapperHeartbeatInterval
+	if in.IntervalSeconds > 0 {
+		interval = time.Duration(in.IntervalSeconds) * time.Second
+	}
+
+	status, ok := c.MapperStatusList[mapper.Name]
+	if !ok {
+		status = &MapperStatus{MapperStatus: types.MapperStatus{Name: mapper.Name}}
+		c.MapperStatusList[mapper.Name] = status
+	}
+	changed := !ok || status.State != state || status.Reason != reason
+	if changed {
+		klog.Infof("mapper %s is %s %s", mapper.Name, state, reason)
+	}
+	status.Protocol = mapper.Protocol
+	status.State = state
+	status.Reason = reason
+	status.LastHeartbeatTime = now.UnixMilli()
+	status.Interval = interval
+	return changed
+}
+
+// expireMappers marks the mappers missing MapperHeartbeatTolerance heartbeats offline and returns them.
+// Only mappers that have sent heartbeats are tracked, so mappers not supporting heartbeat are never offline.
+// MapperMu must be held by the caller.
+func (c *DMICache) expireMappers(now time.Time) []types.MapperStatus {
+	var expired []types.MapperStatus
+	for _, status := range c.MapperStatusList {
+		if status.State == MapperStateOffline {
+			continue
+		}
+		timeout := MapperHeartbeatTolerance * status.Interval
+		if now.Sub(time.UnixMilli(status.LastHeartbeatTime)) <= timeout {
+			continue
+		}
+		status.State = MapperStateOffline
+		status.Reason = fmt.Sprintf("no heartbeat received from mapper in %v", timeout)
+		klog.Warningf("mapper %s is offline: %s", status.Name, status.Reason)
+		expired = append(expired, status.MapperStatus)
+	}
+	return expired
+}
+
+// mapperStatusList returns the status of all tracked mappers sorted by name
+func (c *DMICache) mapperStatusList() []types.MapperStatus {
+	c.MapperMu.Lock()
+	defer c.MapperMu.Unlock()
+	list := make([]types.MapperStatus, 0, len(c.MapperStatusList))
+	for _, status := range c.MapperStatusList {
+		list = append(list, status.MapperStatus)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// MapperStatuses returns the status of the mappers sending heartbeats sorted by name,
+// it returns nil if DeviceTwin is not running
+func MapperStatuses() []types.MapperStatus {
+	cache := monitoredCache.Load()
+	if cache == nil {
+		return nil
+	}
+	return cache.mapperStatusList()
+}
+
+// MonitorMappers checks the heartbeats of mappers periodically until beehive is stopped.
+// Devices served by a mapper which becomes offline are marked offline, and the status of
+// mappers is reported to cloud when it changes or the connection to cloud is recovered.
+func MonitorMappers(cache *DMICache) {
+	monitoredCache.Store(cache)
+	ticker := time.NewTicker(mapperCheckPeriod)
+	defer ticker.Stop()
+	connected := false
+	for {
+		select {
+		case <-beehiveContext.Done():
+			klog.Info("stop monitoring mappers")
+			return
+		case <-ticker.C:
+		}
+
+		cache.MapperMu.Lock()
+		expired := cache.expireMappers(time.Now())
+		cache.MapperMu.Unlock()
+		for _, mapper := range expired {
+			markMapperDevicesOffline(cache, mapper)
+		}
+
+		reconnected := !connected && connect.IsConnected()
+		connected = connect.IsConnected()
+		if len(expired) > 0 || reconnected {
+			reportMapperStatus(cache)
+		}
+	}
+}
+
+// markMapperDevicesOffline marks the devices served by the offline mapper offline with the reason
+func markMapperDevicesOffline(cache *DMICache, mapper types.MapperStatus) {
+	reason := fmt.Sprintf("mapper %s is offline: %s", mapper.Name, mapper.Reason)
+	cache.DeviceMu.Lock()
+	defer cache.DeviceMu.Unlock()
+	for _, device := range cache.DeviceList {
+		if device.Spec.Protocol.ProtocolName != mapper.Protocol {
+			continue
+		}
+		in := &pb.ReportDeviceStatesRequest{
+			DeviceName:      device.Name,
+			DeviceNamespace: device.Namespace,
+			State:           dtcommon.DeviceStatusOffline,
+		}
+		msg, err := json.Marshal(DeviceStateUpdate{
+			BaseMessage: types.BaseMessage{Timestamp: getTimestamp()},
+			State:       in.State,
+			Reason:      reason,
+		})
+		if err != nil {
+			klog.Errorf("fail to create state message data of device %s with err: %v", device.Name, err)
+			continue
+		}
+		klog.Infof("mark device %s offline because %s", util.GetResourceID(device.Namespace, device.Name), reason)
+		handleDeviceState(in, msg)
+	}
+}
+
+// reportMapperStatus sends the status of all mappers to cloud, it is skipped while disconnected
+// and sent again by MonitorMappers after the connection is recovered
+func reportMapperStatus(cache *DMICache) {
+	if !connect.IsConnected() {
+		return
+	}
+	content := types.MapperStatusUpdate{
+		BaseMessage: types.BaseMessage{Timestamp: getTimestamp()},
+		Mappers:     cache.mapperStatusList(),
+	}
+	message := beehiveModel.NewMessage("").BuildRouter(modules.TwinGroup, deviceconst.GroupResource,
+		deviceconst.ResourceTypeMapperStatus, beehiveModel.UpdateOperation).FillBody(content)
+	beehiveContext.Send(dtcommon.HubModule, *message)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dmiserver
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/kubeedge/api/apis/dmi/v1beta1"
+)
+
+func TestMapperStatusTransitions(t *testing.T) {
+	cache := &DMICache{
+		MapperMu:         &sync.Mutex{},
+		MapperStatusList: make(map[string]*MapperStatus),
+	}
+	mapper := &pb.MapperInfo{Name: "modbus-mapper", Protocol: "modbus"}
+	// heartbeat time is recorded in milliseconds
+	now := time.UnixMilli(time.Now().UnixMilli())
+
+	if !cache.updateMapperStatus(mapper, &pb.MapperHeartbeatRequest{MapperName: mapper.Name, State: "ok", IntervalSeconds: 5}, now) {
+		t.Fatal("expected the first heartbeat to change the mapper state")
+	}
+	status := cache.MapperStatusList[mapper.Name]
+	if status.State != MapperStateOnline || status.Protocol != "modbus" || status.Interval != 5*time.Second {
+		t.Errorf("expected mapper online with interval 5s, got %+v", status)
+	}
+	if cache.updateMapperStatus(mapper, &pb.MapperHeartbeatRequest{MapperName: mapper.Name, IntervalSeconds: 5}, now.Add(5*time.Second)) {
+		t.Error("expected a heartbeat of an online mapper not to change the mapper state")
+	}
+
+	if !cache.updateMapperStatus(mapper, &pb.MapperHeartbeatRequest{MapperName: mapper.Name, State: "unhealthy", IntervalSeconds: 5}, now.Add(10*time.Second)) {
+		t.Fatal("expected an unhealthy heartbeat to change the mapper state")
+	}
+	if status.State != MapperStateDegraded || status.Reason == "" {
+		t.Errorf("expected mapper degraded with reason, got %+v", status)
+	}
+
+	if expired := cache.expireMappers(now.Add(25 * time.Second)); len(expired) != 0 {
+		t.Errorf("expected no mapper expired within %d intervals, got %+v", MapperHeartbeatTolerance, expired)
+	}
+	expired := cache.expireMappers(now.Add(26 * time.Second))
+	if len(expired) != 1 || expired[0].Name != mapper.Name || expired[0].State != MapperStateOffline {
+		t.Fatalf("expected mapper offline after missing %d heartbeats, got %+v", MapperHeartbeatTolerance, expired)
+	}
+	if expired := cache.expireMappers(now.Add(60 * time.Second)); len(expired) != 0 {
+		t.Errorf("expected an offline mapper not to expire again, got %+v", expired)
+	}
+
+	if !cache.updateMapperStatus(mapper, &pb.MapperHeartbeatRequest{MapperName: mapper.Name, IntervalSeconds: 5}, now.Add(61*time.Second)) {
+		t.Fatal("expected a heartbeat to bring the offline mapper back")
+	}
+	if status.State != MapperStateOnline || status.Reason != "" {
+		t.Errorf("expected mapper online without reason, got %+v", status)
+	}
+
+	list := cache.mapperStatusList()
+	if len(list) != 1 || list[0].Name != mapper.Name {
+		t.Errorf("expected status of 1 mapper, got %+v", list)
+	}
+}
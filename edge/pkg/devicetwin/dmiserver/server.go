@@ -59,12 +59,14 @@ type server struct {
 }
 
 type DMICache struct {
-	MapperMu        *sync.Mutex
-	DeviceMu        *sync.Mutex
-	DeviceModelMu   *sync.Mutex
-	MapperList      map[string]*pb.MapperInfo
-	DeviceModelList map[string]*v1beta1.DeviceModel
-	DeviceList      map[string]*v1beta1.Device
+	MapperMu      *sync.Mutex
+	DeviceMu      *sync.Mutex
+	DeviceModelMu *sync.Mutex
+	MapperList    map[string]*pb.MapperInfo
+	// MapperStatusList is the status of mappers sending heartbeats, guarded by MapperMu
+	MapperStatusList map[string]*MapperStatus
+	DeviceModelList  map[string]*v1beta1.DeviceModel
+	DeviceList       map[string]*v1beta1.Device
 }
 
 func (s *server) MapperRegister(_ context.Context, in *pb.MapperRegisterRequest) (*pb.MapperRegisterResponse, error) {
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dmiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	pb "github.com/kubeedge/api/apis/dmi/v1beta1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/types"
+	"github.com/kubeedge/kubeedge/pkg/util"
+)
+
+// MaxStreamBatchSize is the max number of items in a batch of ReportDeviceStatusStream
+const MaxStreamBatchSize = 1000
+
+// batchedTwinUpdate is the twin update of a device merged from the items of a batch
+type batchedTwinUpdate struct {
+	request *pb.ReportDeviceStatusRequest
+	update  *DeviceTwinUpdate
+}
+
+// ReportDeviceStatusStream receives batches of device status from the mapper and acknowledges
+// every batch after it is handled, so the mapper can bound the unacknowledged batches in flight.
+// Batches are not rate limited like ReportDeviceStatus, the acknowledgement is the backpressure.
+func (s *server) ReportDeviceStatusStream(stream pb.DeviceManagerService_ReportDeviceStatusStreamServer) error {
+	var lastSequence uint64
+	for {
+		batch, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		ack := &pb.ReportDeviceStatusAck{Sequence: batch.Sequence}
+		switch {
+		case batch.Sequence <= lastSequence:
+			klog.Warningf("drop device status batch %d because batch %d has been handled", batch.Sequence, lastSequence)
+			ack.Error = fmt.Sprintf("batch %d has been handled", batch.Sequence)
+		case len(batch.Items) > MaxStreamBatchSize:
+			ack.Error = fmt.Sprintf("batch has %d items, exceeds the max batch size %d", len(batch.Items), MaxStreamBatchSize)
+		default:
+			if lastSequence != 0 && batch.Sequence != lastSequence+1 {
+				klog.Warningf("device status batches from %d to %d are missing", lastSequence+1, batch.Sequence-1)
+			}
+			lastSequence = batch.Sequence
+			handleDeviceStatusBatch(batch, ack)
+		}
+
+		if err := stream.Send(ack); err != nil {
+			return err
+		}
+	}
+}
+
+// handleDeviceStatusBatch sends the merged twin updates of the batch to devicetwin in order
+func handleDeviceStatusBatch(batch *pb.ReportDeviceStatusBatch, ack *pb.ReportDeviceStatusAck) {
+	updates, accepted, errs := mergeDeviceStatusBatch(batch)
+	for _, u := range updates {
+		payload, err := json.Marshal(u.update)
+		if err != nil {
+			klog.Errorf("fail to create message data of device %s with err: %v", u.request.DeviceName, err)
+			errs = append(errs, err.Error())
+			continue
+		}
+		handleDeviceTwin(u.request, payload)
+	}
+	ack.Accepted = int32(accepted)
+	ack.Error = strings.Join(errs, "; ")
+}
+
+// mergeDeviceStatusBatch merges the twins reported in the batch into a single twin update per device.
+// The latest sample of a property is the value of the twin, the samples it supersedes are kept in
+// the history of the update in the order they were reported, so no sample is dropped.
+// It returns the updates, the number of accepted items and the reasons of rejected items.
+func mergeDeviceStatusBatch(batch *pb.ReportDeviceStatusBatch) ([]*batchedTwinUpdate, int, []string) {
+	var updates []*batchedTwinUpdate
+	var errs []string
+	accepted := 0
+	devices := make(map[string]*batchedTwinUpdate)
+	for _, item := range batch.Items {
+		if item == nil || item.ReportedDevice == nil || len(item.ReportedDevice.Twins) == 0 {
+			errs = append(errs, fmt.Sprintf("status of device %s does not have twin data", item.GetDeviceName()))
+			continue
+		}
+		deviceID := util.GetResourceID(item.DeviceNamespace, item.DeviceName)
+		u, ok := devices[deviceID]
+		if !ok {
+			u = &batchedTwinUpdate{
+				request: item,
+				update:  &DeviceTwinUpdate{Twin: map[string]*types.MsgTwin{}},
+			}
+			u.update.BaseMessage.Timestamp = getTimestamp()
+			devices[deviceID] = u
+			updates = append(updates, u)
+		}
+		for _, twin := range item.ReportedDevice.Twins {
+			if twin == nil || twin.Reported == nil {
+				continue
+			}
+			if merged, ok := u.update.Twin[twin.PropertyName]; ok {
+				u.addHistory(twin.PropertyName, merged)
+			}
+			u.update.Twin[twin.PropertyName] = convertStreamTwin(twin)
+		}
+		accepted++
+	}
+	return updates, accepted, errs
+}
+
+// addHistory keeps the superseded value of the twin in the history of the update
+func (u *batchedTwinUpdate) addHistory(name string, superseded *types.MsgTwin) {
+	sample := types.TwinHistorySample{
+		Value:     *superseded.Actual.Value,
+		Timestamp: u.update.BaseMessage.Timestamp,
+	}
+	if superseded.Actual.Metadata != nil {
+		sample.Timestamp = superseded.Actual.Metadata.Timestamp
+	}
+	if u.update.History == nil {
+		u.update.History = make(map[string][]types.TwinHistorySample)
+	}
+	u.update.History[name] = append(u.update.History[name], sample)
+}
+
+// convertStreamTwin converts the twin reported by the mapper, keeping the time the value was collected
+func convertStreamTwin(twin *pb.Twin) *types.MsgTwin {
+	reported := twin.Reported.Value
+	msgTwin := &types.MsgTwin{Actual: &types.TwinValue{Value: &reported}}
+	if ts, err := strconv.ParseInt(twin.Reported.Metadata["timestamp"], 10, 64); err == nil && ts > 0 {
+		msgTwin.Actual.Metadata = &types.ValueMetadata{Timestamp: ts}
+	}
+	if twin.ObservedDesired != nil {
+		expected := twin.ObservedDesired.Value
+		msgTwin.Expected = &types.TwinValue{Value: &expected}
+	}
+	return msgTwin
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dmiserver
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	pb "github.com/kubeedge/api/apis/dmi/v1beta1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/types"
+)
+
+func reportedTwin(name, value, timestamp string) *pb.Twin {
+	return &pb.Twin{
+		PropertyName: name,
+		Reported: &pb.TwinProperty{
+			Value:    value,
+			Metadata: map[string]string{"timestamp": timestamp},
+		},
+	}
+}
+
+func TestMergeDeviceStatusBatch(t *testing.T) {
+	batch := &pb.ReportDeviceStatusBatch{
+		Sequence: 1,
+		Items: []*pb.ReportDeviceStatusRequest{
+			{
+				DeviceName:      "sensor-a",
+				DeviceNamespace: "default",
+				ReportedDevice:  &pb.DeviceStatus{Twins: []*pb.Twin{reportedTwin("vibration", "1.5", "1000")}},
+			},
+			{
+				DeviceName:      "sensor-b",
+				DeviceNamespace: "default",
+				ReportedDevice:  &pb.DeviceStatus{Twins: []*pb.Twin{reportedTwin("vibration", "0.2", "1000")}},
+			},
+			{
+				DeviceName:      "sensor-a",
+				DeviceNamespace: "default",
+				ReportedDevice: &pb.DeviceStatus{Twins: []*pb.Twin{
+					reportedTwin("vibration", "1.7", "1001"),
+					reportedTwin("temperature", "30", "invalid"),
+				}},
+			},
+			{
+				DeviceName:      "sensor-c",
+				DeviceNamespace: "default",
+			},
+		},
+	}
+
+	updates, accepted, errs := mergeDeviceStatusBatch(batch)
+	if accepted != 3 {
+		t.Errorf("expected 3 accepted items, got %d", accepted)
+	}
+	if len(errs) != 1 {
+		t.Errorf("expected 1 rejected item, got %v", errs)
+	}
+	if len(updates) != 2 {
+		t.Fatalf("expected 2 updates, got %d", len(updates))
+	}
+	if updates[0].request.DeviceName != "sensor-a" || updates[1].request.DeviceName != "sensor-b" {
+		t.Errorf("expected updates of sensor-a and sensor-b, got %s and %s",
+			updates[0].request.DeviceName, updates[1].request.DeviceName)
+	}
+
+	wantHistory := map[string][]types.TwinHistorySample{"vibration": {{Value: "1.5", Timestamp: 1000}}}
+	if history := updates[0].update.History; !reflect.DeepEqual(history, wantHistory) {
+		t.Errorf("expected the superseded vibration value 1.5 at 1000 in history, got %+v", history)
+	}
+	if history := updates[1].update.History; history != nil {
+		t.Errorf("expected no history of sensor-b, got %+v", history)
+	}
+	twins := updates[0].update.Twin
+	if len(twins) != 2 {
+		t.Fatalf("expected 2 twins in the update of sensor-a, got %d", len(twins))
+	}
+	vibration := twins["vibration"]
+	if *vibration.Actual.Value != "1.7" || vibration.Actual.Metadata == nil || vibration.Actual.Metadata.Timestamp != 1001 {
+		t.Errorf("expected the latest vibration value 1.7 at 1001, got %+v", vibration.Actual)
+	}
+	temperature := twins["temperature"]
+	if *temperature.Actual.Value != "30" || temperature.Actual.Metadata != nil {
+		t.Errorf("expected temperature 30 without timestamp, got %+v", temperature.Actual)
+	}
+	if temperature.Expected != nil {
+		t.Errorf("expected no observed desired value of temperature, got %+v", temperature.Expected)
+	}
+}
+
+func TestMergeDeviceStatusBatchKeepsEverySample(t *testing.T) {
+	batch := &pb.ReportDeviceStatusBatch{Sequence: 1}
+	for i, value := range []string{"1", "2", "3", "4"} {
+		twins := []*pb.Twin{reportedTwin("vibration", value, strconv.Itoa(1000+i))}
+		if i%2 == 0 {
+			twins = append(twins, reportedTwin("temperature", "2"+value, strconv.Itoa(1000+i)))
+		}
+		batch.Items = append(batch.Items, &pb.ReportDeviceStatusRequest{
+			DeviceName:      "sensor-a",
+			DeviceNamespace: "default",
+			ReportedDevice:  &pb.DeviceStatus{Twins: twins},
+		})
+	}
+
+	updates, accepted, errs := mergeDeviceStatusBatch(batch)
+	if accepted != 4 || len(errs) != 0 {
+		t.Fatalf("expected 4 accepted items without error, got %d and %v", accepted, errs)
+	}
+	if len(updates) != 1 {
+		t.Fatalf("expected a single update of the device, got %d", len(updates))
+	}
+	update := updates[0].update
+	for name, want := range map[string][]string{"vibration": {"1", "2", "3", "4"}, "temperature": {"21", "23"}} {
+		var values []string
+		for _, sample := range update.History[name] {
+			values = append(values, sample.Value)
+		}
+		values = append(values, *update.Twin[name].Actual.Value)
+		if !reflect.DeepEqual(values, want) {
+			t.Errorf("expected every %s sample in order, got %v", name, values)
+		}
+	}
+}
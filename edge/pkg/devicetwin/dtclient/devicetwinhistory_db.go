@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dtclient
+
+import (
+	"context"
+
+	"github.com/beego/beego/v2/client/orm"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
+)
+
+// historyInsertBulk the max number of samples inserted by one statement
+const historyInsertBulk = 100
+
+// DeviceTwinHistory the struct of a reported twin value at a point in time
+type DeviceTwinHistory struct {
+	ID       int64  `orm:"column(id);size(64);auto;pk"`
+	DeviceID string `orm:"column(deviceid);index;type(text)"`
+	Name     string `orm:"column(name);type(text)"`
+	Value    string `orm:"column(value);null;type(text)"`
+	AttrType string `orm:"column(attr_type);null;type(text)"`
+	// Timestamp is the unix milliseconds the value was reported at
+	Timestamp int64 `orm:"column(timestamp);type(integer)"`
+	// Synced indicates whether the value has been reported to cloud
+	Synced bool `orm:"column(synced);type(integer)"`
+}
+
+// SaveDeviceTwinHistories save the samples of device twins in one transaction
+func SaveDeviceTwinHistories(o orm.Ormer, docs []DeviceTwinHistory) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	err := o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		_, e := txOrm.InsertMulti(historyInsertBulk, docs)
+		return e
+	})
+	if err != nil {
+		klog.Errorf("Something wrong when insert DeviceTwinHistory data: %v", err)
+		return err
+	}
+	klog.V(4).Infof("insert %d DeviceTwinHistory data successfully", len(docs))
+	return nil
+}
+
+// PruneDeviceTwinHistory drop the oldest samples of every twin beyond maxSamples
+func PruneDeviceTwinHistory(o orm.Ormer, maxSamples int) error {
+	// the newest sample beyond maxSamples of a twin and all samples before it are dropped
+	_, err := o.Raw("DELETE FROM "+DeviceTwinHistoryTableName+" WHERE id <= (SELECT h.id FROM "+DeviceTwinHistoryTableName+
+		" h WHERE h.deviceid = "+DeviceTwinHistoryTableName+".deviceid AND h.name = "+DeviceTwinHistoryTableName+
+		".name ORDER BY h.id DESC LIMIT 1 OFFSET ?)", maxSamples).Exec()
+	if err != nil {
+		klog.Errorf("Something wrong when pruning DeviceTwinHistory data: %v", err)
+		return err
+	}
+	klog.V(4).Info("Prune DeviceTwinHistory data successfully")
+	return nil
+}
+
+// DeleteDeviceTwinHistoryByDeviceID delete the history of all twins of the device
+func DeleteDeviceTwinHistoryByDeviceID(o orm.Ormer, deviceID string) error {
+	_, err := o.QueryTable(DeviceTwinHistoryTableName).Filter("deviceid", deviceID).Delete()
+	if err != nil {
+		klog.Errorf("Something wrong when deleting DeviceTwinHistory data: %v", err)
+		return err
+	}
+	klog.V(4).Info("Delete DeviceTwinHistory data successfully")
+	return nil
+}
+
+// QueryDeviceTwinHistory query the samples of the device sorted by time,
+// empty name selects all twins and zero start or end leaves the time range open
+func QueryDeviceTwinHistory(deviceID string, name string, start int64, end int64) (*[]DeviceTwinHistory, error) {
+	history := new([]DeviceTwinHistory)
+	qs := dbm.DBAccess.QueryTable(DeviceTwinHistoryTableName).Filter("deviceid", deviceID)
+	if name != "" {
+		qs = qs.Filter("name", name)
+	}
+	if start > 0 {
+		qs = qs.Filter("timestamp__gte", start)
+	}
+	if end > 0 {
+		qs = qs.Filter("timestamp__lte", end)
+	}
+	_, err := qs.OrderBy("timestamp", "id").All(history)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// QueryUnsyncedDeviceTwinHistory query at most limit samples not reported to cloud yet, oldest first
+func QueryUnsyncedDeviceTwinHistory(limit int) (*[]DeviceTwinHistory, error) {
+	history := new([]DeviceTwinHistory)
+	_, err := dbm.DBAccess.QueryTable(DeviceTwinHistoryTableName).Filter("synced", false).
+		OrderBy("id").Limit(limit).All(history)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// UpdateDeviceTwinHistorySynced mark the samples as reported to cloud
+func UpdateDeviceTwinHistorySynced(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	num, err := dbm.DBAccess.QueryTable(DeviceTwinHistoryTableName).Filter("id__in", ids).
+		Update(map[string]interface{}{"synced": true})
+	klog.V(4).Infof("Update affected Num: %d, %v", num, err)
+	return err
+}
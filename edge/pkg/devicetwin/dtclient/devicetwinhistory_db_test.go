@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dtclient
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/kubeedge/kubeedge/edge/mocks/beego"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
+)
+
+// TestSaveDeviceTwinHistories is function to test SaveDeviceTwinHistories
+func TestSaveDeviceTwinHistories(t *testing.T) {
+	ormerMock, cases := GetCasesSave(t)
+
+	// run the test cases
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			ormerMock.EXPECT().DoTx(gomock.Any()).Return(test.doTXReturnErr).Times(1)
+			err := SaveDeviceTwinHistories(dbm.DBAccess, []DeviceTwinHistory{{}, {}})
+			if test.doTXReturnErr != err {
+				t.Errorf("Save Device Twin History Case failed: wanted error %v and got error %v", test.doTXReturnErr, err)
+			}
+		})
+	}
+}
+
+// TestPruneDeviceTwinHistory is function to test PruneDeviceTwinHistory
+func TestPruneDeviceTwinHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ormerMock := beego.NewMockOrmer(mockCtrl)
+	rawSeterMock := beego.NewMockRawSeter(mockCtrl)
+	dbm.DBAccess = ormerMock
+
+	cases := []struct {
+		name      string
+		returnErr error
+	}{{
+		name:      "SuccessCase",
+		returnErr: nil,
+	}, {
+		name:      "FailureCase",
+		returnErr: errFailedDBOperation,
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			rawSeterMock.EXPECT().Exec().Return(nil, test.returnErr).Times(1)
+			ormerMock.EXPECT().Raw(gomock.Any(), 10).Return(rawSeterMock).Times(1)
+			err := PruneDeviceTwinHistory(dbm.DBAccess, 10)
+			if test.returnErr != err {
+				t.Errorf("Prune Device Twin History Case failed: wanted error %v and got error %v", test.returnErr, err)
+			}
+		})
+	}
+}
+
+// TestDeleteDeviceTwinHistoryByDeviceID is function to test DeleteDeviceTwinHistoryByDeviceID
+func TestDeleteDeviceTwinHistoryByDeviceID(t *testing.T) {
+	ormerMock, querySeterMock, cases := GetCasesDelete(t)
+
+	// run the test cases
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			querySeterMock.EXPECT().Filter(gomock.Any(), gomock.Any()).Return(test.filterReturn).Times(1)
+			querySeterMock.EXPECT().Delete().Return(test.deleteReturnInt, test.deleteReturnErr).Times(1)
+			ormerMock.EXPECT().QueryTable(gomock.Any()).Return(test.queryTableReturn).Times(1)
+			err := DeleteDeviceTwinHistoryByDeviceID(dbm.DBAccess, "test")
+			if test.deleteReturnErr != err {
+				t.Errorf("DeleteDeviceTwinHistoryByDeviceID Case failed: wanted error %v and got error %v", test.deleteReturnErr, err)
+			}
+		})
+	}
+}
+
+// TestQueryDeviceTwinHistory is function to test QueryDeviceTwinHistory
+func TestQueryDeviceTwinHistory(t *testing.T) {
+	ormerMock, querySeterMock, cases := GetCasesQuery(t)
+
+	// fakeHistory is used to set the argument of All function
+	fakeHistory := []DeviceTwinHistory{{DeviceID: "test", Name: "temperature", Value: "20", Timestamp: 1}}
+
+	// run the test cases
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			querySeterMock.EXPECT().All(gomock.Any()).SetArg(0, fakeHistory).Return(test.allReturnInt, test.allReturnErr).Times(1)
+			// filter by device id, name, start and end
+			querySeterMock.EXPECT().Filter(gomock.Any(), gomock.Any()).Return(test.filterReturn).Times(4)
+			querySeterMock.EXPECT().OrderBy(gomock.Any()).Return(test.filterReturn).Times(1)
+			ormerMock.EXPECT().QueryTable(gomock.Any()).Return(test.queryTableReturn).Times(1)
+			history, err := QueryDeviceTwinHistory("test", "temperature", 1, 2)
+			if test.allReturnErr != err {
+				t.Errorf("QueryDeviceTwinHistory Case failed: wanted error %v and got error %v", test.allReturnErr, err)
+			}
+
+			if err == nil {
+				if len(*history) != 1 {
+					t.Errorf("QueryDeviceTwinHistory Case failed: wanted length 1 and got length %v", len(*history))
+				}
+			}
+		})
+	}
+}
+
+// TestQueryUnsyncedDeviceTwinHistory is function to test QueryUnsyncedDeviceTwinHistory
+func TestQueryUnsyncedDeviceTwinHistory(t *testing.T) {
+	ormerMock, querySeterMock, cases := GetCasesQuery(t)
+
+	// fakeHistory is used to set the argument of All function
+	fakeHistory := []DeviceTwinHistory{{DeviceID: "test", Name: "temperature", Value: "20", Timestamp: 1}}
+
+	// run the test cases
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			querySeterMock.EXPECT().All(gomock.Any()).SetArg(0, fakeHistory).Return(test.allReturnInt, test.allReturnErr).Times(1)
+			querySeterMock.EXPECT().Filter(gomock.Any(), gomock.Any()).Return(test.filterReturn).Times(1)
+			querySeterMock.EXPECT().OrderBy(gomock.Any()).Return(test.filterReturn).Times(1)
+			querySeterMock.EXPECT().Limit(gomock.Any()).Return(test.filterReturn).Times(1)
+			ormerMock.EXPECT().QueryTable(gomock.Any()).Return(test.queryTableReturn).Times(1)
+			history, err := QueryUnsyncedDeviceTwinHistory(10)
+			if test.allReturnErr != err {
+				t.Errorf("QueryUnsyncedDeviceTwinHistory Case failed: wanted error %v and got error %v", test.allReturnErr, err)
+			}
+
+			if err == nil {
+				if len(*history) != 1 {
+					t.Errorf("QueryUnsyncedDeviceTwinHistory Case failed: wanted length 1 and got length %v", len(*history))
+				}
+			}
+		})
+	}
+}
+
+// TestUpdateDeviceTwinHistorySynced is function to test UpdateDeviceTwinHistorySynced
+func TestUpdateDeviceTwinHistorySynced(t *testing.T) {
+	ormerMock, querySeterMock, cases := GetCasesUpdate(t)
+
+	// run the test cases
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			querySeterMock.EXPECT().Filter(gomock.Any(), gomock.Any()).Return(test.filterReturn).Times(1)
+			querySeterMock.EXPECT().Update(gomock.Any()).Return(test.updateReturnInt, test.updateReturnErr).Times(1)
+			ormerMock.EXPECT().QueryTable(gomock.Any()).Return(test.queryTableReturn).Times(1)
+			err := UpdateDeviceTwinHistorySynced([]int64{1, 2})
+			if test.updateReturnErr != err {
+				t.Errorf("UpdateDeviceTwinHistorySynced Case failed: wanted error %v and got error %v", test.updateReturnErr, err)
+			}
+		})
+	}
+
+	// no sample to update, the database is not touched
+	if err := UpdateDeviceTwinHistorySynced(nil); err != nil {
+		t.Errorf("UpdateDeviceTwinHistorySynced Case failed: wanted no error and got error %v", err)
+	}
+}
@@ -14,6 +14,8 @@ const (
 	DeviceAttrTableName = "device_attr"
 	//DeviceTwinTableName device table
 	DeviceTwinTableName = "device_twin"
+	//DeviceTwinHistoryTableName device twin history table
+	DeviceTwinHistoryTableName = "device_twin_history"
 )
 
 // InitDBTable create table
@@ -27,4 +29,5 @@ func InitDBTable(module core.Module) {
 	orm.RegisterModel(new(Device))
 	orm.RegisterModel(new(DeviceAttr))
 	orm.RegisterModel(new(DeviceTwin))
+	orm.RegisterModel(new(DeviceTwinHistory))
 }
@@ -40,6 +40,8 @@ const (
 	TwinETCloudSyncSuffix = "/twin/cloud_updated"
 	// TwinETEdgeSyncSuffix the topic suffix for twin sync event
 	TwinETEdgeSyncSuffix = "/twin/edge_updated"
+	// TwinETEdgeHistorySuffix the topic suffix for buffered twin history reported to cloud
+	TwinETEdgeHistorySuffix = "/twin/edge_history"
 	// TwinETDeltaSuffix the topic suffix for twin delta event
 	TwinETDeltaSuffix = "/twin/update/delta"
 	// TwinETDocumentSuffix the topic suffix for twin document event
@@ -105,7 +105,8 @@ func dealLifeCycle(context *dtcontext.DTContext, _ string, msg interface{}) erro
 	}
 	connectedInfo, _ := message.Content.(string)
 	if strings.Compare(connectedInfo, connect.CloudConnected) == 0 {
-		if strings.Compare(context.State, dtcommon.Disconnected) == 0 {
+		reconnected := strings.Compare(context.State, dtcommon.Disconnected) == 0
+		if reconnected {
 			err := detailRequest(context)
 			if err != nil {
 				klog.Errorf("detail request: %v", err)
@@ -113,6 +114,11 @@ func dealLifeCycle(context *dtcontext.DTContext, _ string, msg interface{}) erro
 			}
 		}
 		context.State = dtcommon.Connected
+		if reconnected {
+			if err := reportTwinHistory(context); err != nil {
+				klog.Errorf("report twin history: %v", err)
+			}
+		}
 	} else if strings.Compare(connectedInfo, connect.CloudDisconnected) == 0 {
 		context.State = dtcommon.Disconnected
 	}
@@ -126,6 +132,7 @@ func dealConfirm(context *dtcontext.DTContext, _ string, msg interface{}) error
 		parentMsgID := value.GetParentID()
 		klog.Infof("CommModule deal confirm msgID %s", parentMsgID)
 		context.ConfirmMap.Delete(parentMsgID)
+		confirmTwinHistory(context, parentMsgID)
 	} else {
 		return errors.New("CommModule deal confirm, type not correct")
 	}
@@ -172,4 +179,8 @@ func (cw CommWorker) checkConfirm(context *dtcontext.DTContext) {
 		}
 		return true
 	})
+	// reports again the twin history not confirmed in time
+	if err := reportTwinHistory(context); err != nil {
+		klog.Errorf("report twin history: %v", err)
+	}
 }
@@ -118,6 +118,7 @@ func dealDeviceStateUpdate(context *dtcontext.DTContext, resource string, msg in
 	}
 	device.State = updatedDevice.State
 	device.LastOnline = lastOnline
+	device.Reason = updatedDevice.Reason
 	payload, err := dttype.BuildDeviceCloudMsgState(dttype.BuildBaseMessage(), *device)
 	if err != nil {
 		return err
@@ -49,12 +49,13 @@ type DMIWorker struct {
 
 func (dw *DMIWorker) init() {
 	dw.dmiCache = &dmiserver.DMICache{
-		MapperMu:        &sync.Mutex{},
-		DeviceMu:        &sync.Mutex{},
-		DeviceModelMu:   &sync.Mutex{},
-		MapperList:      make(map[string]*pb.MapperInfo),
-		DeviceList:      make(map[string]*v1beta1.Device),
-		DeviceModelList: make(map[string]*v1beta1.DeviceModel),
+		MapperMu:         &sync.Mutex{},
+		DeviceMu:         &sync.Mutex{},
+		DeviceModelMu:    &sync.Mutex{},
+		MapperList:       make(map[string]*pb.MapperInfo),
+		MapperStatusList: make(map[string]*dmiserver.MapperStatus),
+		DeviceList:       make(map[string]*v1beta1.Device),
+		DeviceModelList:  make(map[string]*v1beta1.DeviceModel),
 	}
 
 	dw.initDMIActionCallBack()
@@ -69,6 +70,7 @@ func (dw DMIWorker) Start() {
 	dw.init()
 
 	go dmiserver.StartDMIServer(dw.dmiCache)
+	go dmiserver.MonitorMappers(dw.dmiCache)
 
 	for {
 		select {
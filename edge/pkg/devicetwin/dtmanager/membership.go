@@ -281,6 +281,7 @@ func removeDevice(context *dtcontext.DTContext, toRemove []dttype.Device, baseMe
 			}
 			time.Sleep(dtcommon.RetryInterval)
 		}
+		deleteTwinHistory(device.ID)
 		//todo
 		context.DeviceList.Delete(device.ID)
 		context.DeviceMutex.Delete(device.ID)
@@ -161,7 +161,12 @@ func Updated(context *dtcontext.DTContext, deviceID string, payload []byte) {
 	}
 	klog.Infof("Begin to update twin of the device %s", deviceID)
 	eventID := msg.EventID
-	DealDeviceTwin(context, deviceID, eventID, msg.Twin, RestDealType)
+	if err := DealDeviceTwin(context, deviceID, eventID, msg.Twin, RestDealType); err != nil {
+		return
+	}
+	if device, ok := context.GetDevice(deviceID); ok {
+		recordSupersededTwinHistory(deviceID, device, msg.History)
+	}
 }
 
 // DealDeviceTwin deal device twin
@@ -213,6 +218,7 @@ func DealDeviceTwin(context *dtcontext.DTContext, deviceID string, eventID strin
 		if err != nil {
 			return err
 		}
+		recordTwinHistory(deviceID, device, msgTwin)
 	}
 	if len(dealTwinResult.Document) > 0 {
 		dealDocument(context, deviceID, dttype.BaseMessage{EventID: eventID, Timestamp: now}, dealTwinResult.Document)
@@ -0,0 +1,284 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dtmanager
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	connect "github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
+	deviceconfig "github.com/kubeedge/kubeedge/edge/pkg/devicetwin/config"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtclient"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtcommon"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtcontext"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dttype"
+)
+
+const (
+	// twinHistoryPruneInterval is the interval the samples beyond MaxSamples are dropped at
+	twinHistoryPruneInterval = 5 * time.Minute
+	// maxBufferedTwinHistory bounds the samples kept in memory while the database can't be written
+	maxBufferedTwinHistory = 10000
+	// twinHistoryConfirmTimeout is the time a reported message waits for the confirmation of cloud,
+	// the samples of the messages not confirmed in time are reported again in the next batch
+	twinHistoryConfirmTimeout = 5 * time.Minute
+)
+
+// twinHistoryBuffer holds the samples recorded since the last flush, they are written to the
+// database in one transaction per flush period to limit the writes to the storage of edge devices
+var twinHistoryBuffer struct {
+	sync.Mutex
+	samples []dtclient.DeviceTwinHistory
+}
+
+// pendingTwinHistory holds the twin history messages waiting for the confirmation of cloud,
+// key is the message id, value is the *pendingTwinHistoryReport of the message
+var pendingTwinHistory sync.Map
+
+type pendingTwinHistoryReport struct {
+	// ids are the ids of the samples reported by the message
+	ids    []int64
+	sentAt time.Time
+}
+
+// twinHistoryConfig returns the twin history config, nil if twin history is disabled
+func twinHistoryConfig() *v1alpha2.DeviceTwinHistory {
+	c := deviceconfig.Get().TwinHistory
+	if c == nil || !c.Enable {
+		return nil
+	}
+	return c
+}
+
+// recordTwinHistory buffers the actual values of the twins updated by the device for the local history.
+// Values reported while connected are sent to cloud by the twin sync, values reported while
+// disconnected are kept unsynced and reported to cloud after reconnecting.
+func recordTwinHistory(deviceID string, device *dttype.Device, msgTwin map[string]*dttype.MsgTwin) {
+	c := twinHistoryConfig()
+	if c == nil {
+		return
+	}
+	synced := connect.IsConnected()
+	now := time.Now().UnixNano() / 1e6
+	twinHistoryBuffer.Lock()
+	defer twinHistoryBuffer.Unlock()
+	for name, twin := range msgTwin {
+		if twin == nil || twin.Actual == nil || twin.Actual.Value == nil {
+			continue
+		}
+		sample := dtclient.DeviceTwinHistory{
+			DeviceID:  deviceID,
+			Name:      name,
+			Value:     *twin.Actual.Value,
+			Timestamp: now,
+			Synced:    synced,
+		}
+		if twin.Actual.Metadata != nil && twin.Actual.Metadata.Timestamp > 0 {
+			sample.Timestamp = twin.Actual.Metadata.Timestamp
+		}
+		if deviceTwin, ok := device.Twin[name]; ok && deviceTwin != nil && deviceTwin.Metadata != nil {
+			sample.AttrType = deviceTwin.Metadata.Type
+		}
+		twinHistoryBuffer.samples = append(twinHistoryBuffer.samples, sample)
+	}
+}
+
+// recordSupersededTwinHistory buffers the samples superseded by the values of a merged twin update.
+// They are not sent to cloud by the twin sync, so they are kept unsynced and reported with the history.
+func recordSupersededTwinHistory(deviceID string, device *dttype.Device, history map[string][]dttype.TwinHistorySample) {
+	if twinHistoryConfig() == nil || len(history) == 0 {
+		return
+	}
+	twinHistoryBuffer.Lock()
+	defer twinHistoryBuffer.Unlock()
+	for name, samples := range history {
+		attrType := ""
+		if deviceTwin, ok := device.Twin[name]; ok && deviceTwin != nil && deviceTwin.Metadata != nil {
+			attrType = deviceTwin.Metadata.Type
+		}
+		for _, s := range samples {
+			twinHistoryBuffer.samples = append(twinHistoryBuffer.samples, dtclient.DeviceTwinHistory{
+				DeviceID:  deviceID,
+				Name:      name,
+				Value:     s.Value,
+				Timestamp: s.Timestamp,
+				AttrType:  attrType,
+			})
+		}
+	}
+}
+
+// flushTwinHistory writes the buffered samples to the database in one transaction,
+// the samples are kept buffered if the write fails
+func flushTwinHistory() error {
+	twinHistoryBuffer.Lock()
+	samples := twinHistoryBuffer.samples
+	twinHistoryBuffer.samples = nil
+	twinHistoryBuffer.Unlock()
+
+	err := dtclient.SaveDeviceTwinHistories(dbm.DBAccess, samples)
+	if err == nil {
+		return nil
+	}
+	twinHistoryBuffer.Lock()
+	defer twinHistoryBuffer.Unlock()
+	twinHistoryBuffer.samples = append(samples, twinHistoryBuffer.samples...)
+	if dropped := len(twinHistoryBuffer.samples) - maxBufferedTwinHistory; dropped > 0 {
+		klog.Warningf("Drop %d oldest buffered twin history samples", dropped)
+		twinHistoryBuffer.samples = twinHistoryBuffer.samples[dropped:]
+	}
+	return err
+}
+
+// RunTwinHistoryWriter flushes the buffered samples every flush period and drops the samples
+// beyond MaxSamples periodically, until beehive context is done
+func RunTwinHistoryWriter() {
+	c := twinHistoryConfig()
+	if c == nil {
+		return
+	}
+	flushTicker := time.NewTicker(time.Duration(c.FlushPeriod) * time.Second)
+	defer flushTicker.Stop()
+	pruneTicker := time.NewTicker(twinHistoryPruneInterval)
+	defer pruneTicker.Stop()
+	for {
+		select {
+		case <-beehiveContext.Done():
+			if err := flushTwinHistory(); err != nil {
+				klog.Errorf("Flush twin history failed: %v", err)
+			}
+			return
+		case <-flushTicker.C:
+			if err := flushTwinHistory(); err != nil {
+				klog.Errorf("Flush twin history failed: %v", err)
+			}
+		case <-pruneTicker.C:
+			if err := dtclient.PruneDeviceTwinHistory(dbm.DBAccess, int(c.MaxSamples)); err != nil {
+				klog.Errorf("Prune twin history failed: %v", err)
+			}
+		}
+	}
+}
+
+// deleteTwinHistory deletes the local history of the removed device
+func deleteTwinHistory(deviceID string) {
+	if twinHistoryConfig() == nil {
+		return
+	}
+	twinHistoryBuffer.Lock()
+	samples := twinHistoryBuffer.samples[:0]
+	for _, sample := range twinHistoryBuffer.samples {
+		if sample.DeviceID != deviceID {
+			samples = append(samples, sample)
+		}
+	}
+	twinHistoryBuffer.samples = samples
+	twinHistoryBuffer.Unlock()
+	if err := dtclient.DeleteDeviceTwinHistoryByDeviceID(dbm.DBAccess, deviceID); err != nil {
+		klog.Errorf("Delete twin history of device %s failed: %v", deviceID, err)
+	}
+}
+
+// reportTwinHistory reports a batch of unsynced samples to cloud, one message per device.
+// The next batch is reported after all messages of the current batch are confirmed or expired.
+func reportTwinHistory(context *dtcontext.DTContext) error {
+	c := twinHistoryConfig()
+	if c == nil || context.State == dtcommon.Disconnected || hasPendingTwinHistory(context) {
+		return nil
+	}
+	// samples buffered while disconnected are reported as well
+	if err := flushTwinHistory(); err != nil {
+		return err
+	}
+	history, err := dtclient.QueryUnsyncedDeviceTwinHistory(int(c.ReportBatchSize))
+	if err != nil {
+		return err
+	}
+	if len(*history) == 0 {
+		return nil
+	}
+
+	reports := make(map[string]*dttype.DeviceTwinHistory)
+	ids := make(map[string][]int64)
+	for _, sample := range *history {
+		report, ok := reports[sample.DeviceID]
+		if !ok {
+			report = &dttype.DeviceTwinHistory{
+				BaseMessage: dttype.BuildBaseMessage(),
+				Twin:        make(map[string][]dttype.TwinHistorySample),
+			}
+			reports[sample.DeviceID] = report
+		}
+		report.Twin[sample.Name] = append(report.Twin[sample.Name], dttype.TwinHistorySample{
+			Value:     sample.Value,
+			Type:      sample.AttrType,
+			Timestamp: sample.Timestamp,
+		})
+		ids[sample.DeviceID] = append(ids[sample.DeviceID], sample.ID)
+	}
+
+	klog.Infof("Report %d buffered twin history samples of %d devices to cloud", len(*history), len(reports))
+	for deviceID, report := range reports {
+		resource := "device/" + deviceID + dtcommon.TwinETEdgeHistorySuffix
+		message := context.BuildModelMessage("resource", "", resource, model.UpdateOperation, *report)
+		pendingTwinHistory.Store(message.GetID(), &pendingTwinHistoryReport{ids: ids[deviceID], sentAt: time.Now()})
+		if err := dealSendToCloud(context, "", message); err != nil {
+			pendingTwinHistory.Delete(message.GetID())
+			return err
+		}
+	}
+	return nil
+}
+
+// confirmTwinHistory marks the samples reported by the confirmed message as synced,
+// and reports the next batch once the whole batch is confirmed
+func confirmTwinHistory(context *dtcontext.DTContext, msgID string) {
+	value, ok := pendingTwinHistory.LoadAndDelete(msgID)
+	if !ok {
+		return
+	}
+	if err := dtclient.UpdateDeviceTwinHistorySynced(value.(*pendingTwinHistoryReport).ids); err != nil {
+		klog.Errorf("Mark twin history of message %s as synced failed: %v", msgID, err)
+	}
+	if err := reportTwinHistory(context); err != nil {
+		klog.Errorf("Report twin history failed: %v", err)
+	}
+}
+
+// hasPendingTwinHistory returns whether reported messages are waiting for the confirmation of cloud.
+// Messages not confirmed within twinHistoryConfirmTimeout are dropped and not resent any more,
+// their samples are still unsynced and reported again in the next batch.
+func hasPendingTwinHistory(context *dtcontext.DTContext) bool {
+	pending := false
+	pendingTwinHistory.Range(func(key, value interface{}) bool {
+		if time.Since(value.(*pendingTwinHistoryReport).sentAt) < twinHistoryConfirmTimeout {
+			pending = true
+			return true
+		}
+		klog.Warningf("Twin history message %s is not confirmed by cloud in %v, report its samples again", key, twinHistoryConfirmTimeout)
+		pendingTwinHistory.Delete(key)
+		context.ConfirmMap.Delete(key)
+		return true
+	})
+	return pending
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dtmanager
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	deviceconfig "github.com/kubeedge/kubeedge/edge/pkg/devicetwin/config"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtcontext"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dttype"
+	"github.com/kubeedge/kubeedge/pkg/testtools"
+)
+
+func TestFlushTwinHistory(t *testing.T) {
+	deviceconfig.Get().TwinHistory = &v1alpha2.DeviceTwinHistory{Enable: true, MaxSamples: 10, ReportBatchSize: 10, FlushPeriod: 10}
+	defer func() { deviceconfig.Get().TwinHistory = nil }()
+	mockOrmer, mockQuerySeter := testtools.InitOrmerMock(t)
+
+	value := "20"
+	device := &dttype.Device{Twin: map[string]*dttype.MsgTwin{}}
+	msgTwin := map[string]*dttype.MsgTwin{
+		"temperature": {Actual: &dttype.TwinValue{Value: &value}},
+		"humidity":    {Actual: &dttype.TwinValue{Value: &value}},
+	}
+	recordTwinHistory(deviceA, device, msgTwin)
+	recordTwinHistory(deviceB, device, msgTwin)
+	if got := len(twinHistoryBuffer.samples); got != 4 {
+		t.Fatalf("buffered samples = %d, want 4", got)
+	}
+
+	// samples are kept buffered when the transaction fails
+	mockOrmer.EXPECT().DoTx(gomock.Any()).Return(errors.New("database is locked")).Times(1)
+	if err := flushTwinHistory(); err == nil {
+		t.Fatal("flushTwinHistory() expected error")
+	}
+	if got := len(twinHistoryBuffer.samples); got != 4 {
+		t.Fatalf("buffered samples after failed flush = %d, want 4", got)
+	}
+
+	// buffered samples of deleted devices are dropped
+	mockOrmer.EXPECT().QueryTable(gomock.Any()).Return(mockQuerySeter).Times(1)
+	mockQuerySeter.EXPECT().Filter(gomock.Any(), deviceB).Return(mockQuerySeter).Times(1)
+	mockQuerySeter.EXPECT().Delete().Return(int64(2), nil).Times(1)
+	deleteTwinHistory(deviceB)
+	if got := len(twinHistoryBuffer.samples); got != 2 {
+		t.Fatalf("buffered samples after deleting device = %d, want 2", got)
+	}
+
+	// all buffered samples are written in one transaction
+	mockOrmer.EXPECT().DoTx(gomock.Any()).Return(nil).Times(1)
+	if err := flushTwinHistory(); err != nil {
+		t.Fatalf("flushTwinHistory() error = %v", err)
+	}
+	if got := len(twinHistoryBuffer.samples); got != 0 {
+		t.Fatalf("buffered samples after flush = %d, want 0", got)
+	}
+	// nothing is written without buffered samples
+	if err := flushTwinHistory(); err != nil {
+		t.Fatalf("flushTwinHistory() error = %v", err)
+	}
+}
+
+func TestHasPendingTwinHistory(t *testing.T) {
+	context := &dtcontext.DTContext{ConfirmMap: &sync.Map{}}
+	defer pendingTwinHistory.Range(func(key, _ interface{}) bool {
+		pendingTwinHistory.Delete(key)
+		return true
+	})
+
+	pendingTwinHistory.Store("expired", &pendingTwinHistoryReport{ids: []int64{1}, sentAt: time.Now().Add(-twinHistoryConfirmTimeout)})
+	context.ConfirmMap.Store("expired", &dttype.DTMessage{})
+	if hasPendingTwinHistory(context) {
+		t.Fatal("hasPendingTwinHistory() = true with only expired messages, want false")
+	}
+	if _, ok := pendingTwinHistory.Load("expired"); ok {
+		t.Error("expired message is still pending")
+	}
+	if _, ok := context.ConfirmMap.Load("expired"); ok {
+		t.Error("expired message is still resent")
+	}
+
+	pendingTwinHistory.Store("pending", &pendingTwinHistoryReport{ids: []int64{2}, sentAt: time.Now()})
+	if !hasPendingTwinHistory(context) {
+		t.Error("hasPendingTwinHistory() = false with a pending message, want true")
+	}
+}
+
+func TestRecordSupersededTwinHistory(t *testing.T) {
+	deviceconfig.Get().TwinHistory = &v1alpha2.DeviceTwinHistory{Enable: true, MaxSamples: 10, ReportBatchSize: 10, FlushPeriod: 10}
+	defer func() {
+		deviceconfig.Get().TwinHistory = nil
+		twinHistoryBuffer.samples = nil
+	}()
+
+	device := &dttype.Device{Twin: map[string]*dttype.MsgTwin{
+		"temperature": {Metadata: &dttype.TypeMetadata{Type: "float"}},
+	}}
+	recordSupersededTwinHistory(deviceA, device, map[string][]dttype.TwinHistorySample{
+		"temperature": {{Value: "20", Timestamp: 1000}, {Value: "21", Timestamp: 1001}},
+	})
+	samples := twinHistoryBuffer.samples
+	if len(samples) != 2 {
+		t.Fatalf("buffered samples = %d, want 2", len(samples))
+	}
+	for i, want := range []string{"20", "21"} {
+		if s := samples[i]; s.Value != want || s.AttrType != "float" || s.Synced || s.DeviceID != deviceA {
+			t.Errorf("buffered sample %d = %+v, want unsynced float value %s of %s", i, s, want, deviceA)
+		}
+	}
+}
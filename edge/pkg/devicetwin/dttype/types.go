@@ -19,6 +19,7 @@ type Device struct {
 	Description string              `json:"description,omitempty"`
 	State       string              `json:"state,omitempty"`
 	LastOnline  string              `json:"last_online,omitempty"`
+	Reason      string              `json:"reason,omitempty"`
 	Attributes  map[string]*MsgAttr `json:"attributes,omitempty"`
 	Twin        map[string]*MsgTwin `json:"twin,omitempty"`
 }
@@ -30,6 +31,7 @@ type DeviceCloudMsg struct {
 	Description    string              `json:"description,omitempty"`
 	State          string              `json:"state,omitempty"`
 	LastOnlineTime string              `json:"lastOnlineTime,omitempty"`
+	Reason         string              `json:"reason,omitempty"`
 	Attributes     map[string]*MsgAttr `json:"attributes,omitempty"`
 	Twin           map[string]*MsgTwin `json:"twin,omitempty"`
 }
@@ -244,6 +246,21 @@ type TwinDoc struct {
 type DeviceTwinUpdate struct {
 	BaseMessage
 	Twin map[string]*MsgTwin `json:"twin"`
+	// History, the samples of the twins reported before the values in Twin, sorted by time
+	History map[string][]TwinHistorySample `json:"history,omitempty"`
+}
+
+// TwinHistorySample the struct of a reported twin value at a point in time
+type TwinHistorySample struct {
+	Value     string `json:"value"`
+	Type      string `json:"type,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// DeviceTwinHistory the struct of twin values buffered while disconnected from cloud
+type DeviceTwinHistory struct {
+	BaseMessage
+	Twin map[string][]TwinHistorySample `json:"twin"`
 }
 
 // UnmarshalDeviceTwinDocument unmarshal device twin document
@@ -163,7 +163,8 @@ func BuildDeviceCloudMsgState(baseMessage BaseMessage, device Device) ([]byte, e
 		DeviceCloudMsg: DeviceCloudMsg{
 			Name:           device.Name,
 			State:          device.State,
-			LastOnlineTime: device.LastOnline}}
+			LastOnlineTime: device.LastOnline,
+			Reason:         device.Reason}}
 	payload, err := json.Marshal(result)
 	if err != nil {
 		return []byte(""), err
@@ -264,6 +265,7 @@ func BuildErrorResult(para Parameter) ([]byte, error) {
 type DeviceUpdate struct {
 	BaseMessage
 	State      string              `json:"state,omitempty"`
+	Reason     string              `json:"reason,omitempty"`
 	Attributes map[string]*MsgAttr `json:"attributes"`
 }
 
@@ -16,6 +16,7 @@ import (
 	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtclient"
 	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtcommon"
 	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtcontext"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtmanager"
 	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtmodule"
 	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dttype"
 )
@@ -293,6 +294,7 @@ func (dt *DeviceTwin) runDeviceTwin() {
 		dt.RegisterDTModule(v)
 		go dt.DTModules[v].Start()
 	}
+	go dtmanager.RunTwinHistoryWriter()
 	go func() {
 		for {
 			select {
@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edged
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+	registerapi "k8s.io/kubelet/pkg/apis/pluginregistration/v1"
+)
+
+const (
+	// csiDriverName is the name of the edge csi driver, it is the same driver
+	// the lite kubelet proxies the create, delete, publish and unpublish requests to
+	csiDriverName = "csi-hostpath"
+	// pluginsRegistryDir is the directory under the kubelet root directory where plugins register
+	pluginsRegistryDir = "plugins_registry"
+	// csiTimeout is the timeout of a request to the edge csi driver
+	csiTimeout = 2 * time.Minute
+)
+
+// csiController sends the controller requests the lite kubelet does not support to the edge csi driver,
+// such as volume expansion and snapshots. The endpoint of the driver is found the same way kubelet does,
+// by asking the plugins registered in the plugins registry directory.
+type csiController struct {
+	driverName  string
+	registryDir string
+}
+
+func newCSIController(rootDirectory string) *csiController {
+	return &csiController{
+		driverName:  csiDriverName,
+		registryDir: filepath.Join(rootDirectory, pluginsRegistryDir),
+	}
+}
+
+// call calls the controller service of the driver if the driver has the capability
+func (c *csiController) call(capability csi.ControllerServiceCapability_RPC_Type,
+	fn func(ctx context.Context, client csi.ControllerClient) (interface{}, error)) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), csiTimeout)
+	defer cancel()
+
+	endpoint, err := c.endpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dialUnix(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to csi driver %s: %v", c.driverName, err)
+	}
+	defer conn.Close()
+
+	client := csi.NewControllerClient(conn)
+	res, err := client.ControllerGetCapabilities(ctx, &csi.ControllerGetCapabilitiesRequest{})
+	if err != nil {
+		klog.Errorf("failed to ControllerGetCapabilities: %v", err)
+		return nil, err
+	}
+	for _, cap := range res.Capabilities {
+		if capability == cap.GetRpc().GetType() {
+			return fn(ctx, client)
+		}
+	}
+	return nil, status.Errorf(codes.Unimplemented, "csi driver %s does not support %s", c.driverName, capability)
+}
+
+// endpoint returns the endpoint of the driver registered in the plugins registry directory
+func (c *csiController) endpoint(ctx context.Context) (string, error) {
+	entries, err := os.ReadDir(c.registryDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read plugins registry directory %s: %v", c.registryDir, err)
+	}
+	for _, entry := range entries {
+		if entry.Type()&os.ModeSocket == 0 {
+			continue
+		}
+		info, err := getPluginInfo(ctx, filepath.Join(c.registryDir, entry.Name()))
+		if err != nil {
+			klog.V(4).Infof("failed to get info of plugin %s: %v", entry.Name(), err)
+			continue
+		}
+		if info.Type == registerapi.CSIPlugin && info.Name == c.driverName {
+			return info.Endpoint, nil
+		}
+	}
+	return "", fmt.Errorf("csi driver %s is not registered in %s", c.driverName, c.registryDir)
+}
+
+// getPluginInfo gets the info of the plugin through its registration socket
+func getPluginInfo(ctx context.Context, socket string) (*registerapi.PluginInfo, error) {
+	conn, err := dialUnix(socket)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return registerapi.NewRegistrationClient(conn).GetInfo(ctx, &registerapi.InfoRequest{})
+}
+
+func dialUnix(endpoint string) (*grpc.ClientConn, error) {
+	addr := strings.TrimPrefix(endpoint, "unix://")
+	return grpc.Dial(addr,
+		grpc.WithAuthority("localhost"),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, target string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", target)
+		}),
+	)
+}
@@ -54,6 +54,7 @@ import (
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
 	"github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/imagepolicy"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/util"
 	edgedconfig "github.com/kubeedge/kubeedge/edge/pkg/edged/config"
@@ -92,6 +93,11 @@ type edged struct {
 	context       context.Context
 	nodeName      string
 	namespace     string
+	csiController *csiController
+	// imageVerifier verifies the images of pods before they are created, it is nil if images are not verified
+	imageVerifier *imagepolicy.Verifier
+	// imagePins are the images of the admitted pods pinned to the verified digests
+	imagePins *podImagePins
 }
 
 var _ core.Module = (*edged)(nil)
@@ -222,6 +228,11 @@ func newEdged(enable bool, nodeName, namespace string) (*edged, error) {
 	// source of all configuration
 	kubeletDeps.PodConfig = config.NewPodConfig(config.PodConfigNotificationIncremental, kubeletDeps.Recorder, kubeletDeps.PodStartupLatencyTracker)
 
+	imageVerifier, err := newImageVerifier()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create image verifier: %v", err)
+	}
+
 	ed = &edged{
 		enable:        true,
 		context:       context.Background(),
@@ -230,6 +241,9 @@ func newEdged(enable bool, nodeName, namespace string) (*edged, error) {
 		FeatureGate:   utilfeature.DefaultFeatureGate,
 		nodeName:      nodeName,
 		namespace:     namespace,
+		csiController: newCSIController(kubeletFlags.RootDirectory),
+		imageVerifier: imageVerifier,
+		imagePins:     newPodImagePins(podPinsFile),
 	}
 
 	return ed, nil
@@ -297,6 +311,7 @@ func (e *edged) syncPod(podCfg *config.PodConfig) {
 			res, err := e.handleVolume(op, content)
 			if err != nil {
 				klog.Errorf("handle volume failed: %v", err)
+				beehiveContext.SendResp(*model.NewErrorMessage(&result, err.Error()))
 			} else {
 				resp := result.NewRespByMessage(&result, res)
 				beehiveContext.SendResp(*resp)
@@ -345,9 +360,13 @@ func (e *edged) handlePod(op string, content []byte, updatesChan chan<- interfac
 		switch op {
 		case model.InsertOperation, model.UpdateOperation:
 			klog.V(4).InfoS("Receive message of add/update pods", "operation", op, "pods", klog.KObjSlice(pods))
+			if !e.admitPod(&pod, updatesChan) {
+				return nil
+			}
 			podOp = kubelettypes.UPDATE
 		case model.DeleteOperation:
 			klog.V(4).InfoS("Receive message of deleting pods", "pods", klog.KObjSlice(pods))
+			e.imagePins.forget(pod.UID)
 			podOp = kubelettypes.REMOVE
 		}
 		updates := &kubelettypes.PodUpdate{Op: podOp, Pods: pods, Source: kubelettypes.ApiserverSource}
@@ -376,6 +395,7 @@ func (e *edged) handlePodListFromMetaManager(content []byte, updatesChan chan<-
 			pods = append(pods, &pod)
 		}
 	}
+	pods = e.admitPods(pods, updatesChan)
 
 	updates := &kubelettypes.PodUpdate{Op: kubelettypes.SET, Pods: pods, Source: kubelettypes.ApiserverSource}
 	updatesChan <- *updates
@@ -390,11 +410,12 @@ func (e *edged) handlePodListFromEdgeController(content []byte, updatesChan chan
 		return err
 	}
 
-	for _, pod := range podLists {
-		if filterPodByNodeName(&pod, e.nodeName) {
-			pods = append(pods, &pod)
+	for i := range podLists {
+		if filterPodByNodeName(&podLists[i], e.nodeName) {
+			pods = append(pods, &podLists[i])
 		}
 	}
+	pods = e.admitPods(pods, updatesChan)
 	updates := &kubelettypes.PodUpdate{Op: kubelettypes.SET, Pods: pods, Source: kubelettypes.ApiserverSource}
 	updatesChan <- *updates
 
@@ -411,6 +432,20 @@ func (e *edged) handleVolume(op string, content []byte) (interface{}, error) {
 		return e.controllerPublishVolume(content)
 	case constants.CSIOperationTypeControllerUnpublishVolume:
 		return e.controllerUnpublishVolume(content)
+	case constants.CSIOperationTypeControllerExpandVolume:
+		return e.controllerExpandVolume(content)
+	case constants.CSIOperationTypeControllerGetVolume:
+		return e.controllerGetVolume(content)
+	case constants.CSIOperationTypeListVolumes:
+		return e.listVolumes(content)
+	case constants.CSIOperationTypeGetCapacity:
+		return e.getCapacity(content)
+	case constants.CSIOperationTypeCreateSnapshot:
+		return e.createSnapshot(content)
+	case constants.CSIOperationTypeDeleteSnapshot:
+		return e.deleteSnapshot(content)
+	case constants.CSIOperationTypeListSnapshots:
+		return e.listSnapshots(content)
 	}
 	return nil, nil
 }
@@ -488,6 +523,146 @@ func (e *edged) controllerUnpublishVolume(content []byte) (interface{}, error) {
 	return res, nil
 }
 
+func (e *edged) controllerExpandVolume(content []byte) (interface{}, error) {
+	req := &csi.ControllerExpandVolumeRequest{}
+	err := jsonpb.Unmarshal(bytes.NewReader(content), req)
+	if err != nil {
+		klog.Errorf("unmarshal controller expand volume req error: %v", err)
+		return nil, err
+	}
+	klog.V(4).Infof("start controller expand volume: %s", req.VolumeId)
+	res, err := e.csiController.call(csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
+		func(ctx context.Context, client csi.ControllerClient) (interface{}, error) {
+			return client.ControllerExpandVolume(ctx, req)
+		})
+	if err != nil {
+		klog.Errorf("controller expand volume error: %v", err)
+		return nil, err
+	}
+	klog.V(4).Infof("end controller expand volume: %s result: %v", req.VolumeId, res)
+	return res, nil
+}
+
+func (e *edged) controllerGetVolume(content []byte) (interface{}, error) {
+	req := &csi.ControllerGetVolumeRequest{}
+	err := jsonpb.Unmarshal(bytes.NewReader(content), req)
+	if err != nil {
+		klog.Errorf("unmarshal controller get volume req error: %v", err)
+		return nil, err
+	}
+	klog.V(4).Infof("start controller get volume: %s", req.VolumeId)
+	res, err := e.csiController.call(csi.ControllerServiceCapability_RPC_GET_VOLUME,
+		func(ctx context.Context, client csi.ControllerClient) (interface{}, error) {
+			return client.ControllerGetVolume(ctx, req)
+		})
+	if err != nil {
+		klog.Errorf("controller get volume error: %v", err)
+		return nil, err
+	}
+	klog.V(4).Infof("end controller get volume: %s result: %v", req.VolumeId, res)
+	return res, nil
+}
+
+func (e *edged) listVolumes(content []byte) (interface{}, error) {
+	req := &csi.ListVolumesRequest{}
+	err := jsonpb.Unmarshal(bytes.NewReader(content), req)
+	if err != nil {
+		klog.Errorf("unmarshal list volumes req error: %v", err)
+		return nil, err
+	}
+	klog.V(4).Infof("start list volumes from: %s", req.StartingToken)
+	res, err := e.csiController.call(csi.ControllerServiceCapability_RPC_LIST_VOLUMES,
+		func(ctx context.Context, client csi.ControllerClient) (interface{}, error) {
+			return client.ListVolumes(ctx, req)
+		})
+	if err != nil {
+		klog.Errorf("list volumes error: %v", err)
+		return nil, err
+	}
+	klog.V(4).Infof("end list volumes result: %v", res)
+	return res, nil
+}
+
+func (e *edged) getCapacity(content []byte) (interface{}, error) {
+	req := &csi.GetCapacityRequest{}
+	err := jsonpb.Unmarshal(bytes.NewReader(content), req)
+	if err != nil {
+		klog.Errorf("unmarshal get capacity req error: %v", err)
+		return nil, err
+	}
+	klog.V(4).Infof("start get capacity: %v", req.Parameters)
+	res, err := e.csiController.call(csi.ControllerServiceCapability_RPC_GET_CAPACITY,
+		func(ctx context.Context, client csi.ControllerClient) (interface{}, error) {
+			return client.GetCapacity(ctx, req)
+		})
+	if err != nil {
+		klog.Errorf("get capacity error: %v", err)
+		return nil, err
+	}
+	klog.V(4).Infof("end get capacity result: %v", res)
+	return res, nil
+}
+
+func (e *edged) createSnapshot(content []byte) (interface{}, error) {
+	req := &csi.CreateSnapshotRequest{}
+	err := jsonpb.Unmarshal(bytes.NewReader(content), req)
+	if err != nil {
+		klog.Errorf("unmarshal create snapshot req error: %v", err)
+		return nil, err
+	}
+	klog.V(4).Infof("start create snapshot: %s of volume: %s", req.Name, req.SourceVolumeId)
+	res, err := e.csiController.call(csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+		func(ctx context.Context, client csi.ControllerClient) (interface{}, error) {
+			return client.CreateSnapshot(ctx, req)
+		})
+	if err != nil {
+		klog.Errorf("create snapshot error: %v", err)
+		return nil, err
+	}
+	klog.V(4).Infof("end create snapshot: %s result: %v", req.Name, res)
+	return res, nil
+}
+
+func (e *edged) deleteSnapshot(content []byte) (interface{}, error) {
+	req := &csi.DeleteSnapshotRequest{}
+	err := jsonpb.Unmarshal(bytes.NewReader(content), req)
+	if err != nil {
+		klog.Errorf("unmarshal delete snapshot req error: %v", err)
+		return nil, err
+	}
+	klog.V(4).Infof("start delete snapshot: %s", req.SnapshotId)
+	res, err := e.csiController.call(csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+		func(ctx context.Context, client csi.ControllerClient) (interface{}, error) {
+			return client.DeleteSnapshot(ctx, req)
+		})
+	if err != nil {
+		klog.Errorf("delete snapshot error: %v", err)
+		return nil, err
+	}
+	klog.V(4).Infof("end delete snapshot: %s result: %v", req.SnapshotId, res)
+	return res, nil
+}
+
+func (e *edged) listSnapshots(content []byte) (interface{}, error) {
+	req := &csi.ListSnapshotsRequest{}
+	err := jsonpb.Unmarshal(bytes.NewReader(content), req)
+	if err != nil {
+		klog.Errorf("unmarshal list snapshots req error: %v", err)
+		return nil, err
+	}
+	klog.V(4).Infof("start list snapshots of volume: %s", req.SourceVolumeId)
+	res, err := e.csiController.call(csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+		func(ctx context.Context, client csi.ControllerClient) (interface{}, error) {
+			return client.ListSnapshots(ctx, req)
+		})
+	if err != nil {
+		klog.Errorf("list snapshots error: %v", err)
+		return nil, err
+	}
+	klog.V(4).Infof("end list snapshots result: %v", res)
+	return res, nil
+}
+
 func filterPodByNodeName(pod *v1.Pod, nodeName string) bool {
 	return pod.Spec.NodeName == nodeName
 }
@@ -0,0 +1,334 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edged
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/distribution/reference"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/credentialprovider"
+	credentialprovidersecrets "k8s.io/kubernetes/pkg/credentialprovider/secrets"
+	kubelettypes "k8s.io/kubernetes/pkg/kubelet/types"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/common/imagepolicy"
+	edgedconfig "github.com/kubeedge/kubeedge/edge/pkg/edged/config"
+)
+
+// ReasonImageVerificationFailed is the reason of the events and status of the pods rejected by the image verification
+const ReasonImageVerificationFailed = "ImageVerificationFailed"
+
+// admitTimeout bounds the verification of the images of a pod, so unreachable registries
+// do not hold the admission of the pod forever
+const admitTimeout = 30 * time.Second
+
+// podPinsFile persists the images of the admitted pods pinned to the verified digests
+var podPinsFile = filepath.Join(filepath.Dir(imagepolicy.DefaultCacheFile), "pods.json")
+
+// newImageVerifier returns the verifier of the images of pods, it returns nil if the images are not verified
+func newImageVerifier() (*imagepolicy.Verifier, error) {
+	policy, err := imagepolicy.FromEdgedConfig(edgedconfig.Config.ImageVerification)
+	if err != nil {
+		return nil, err
+	}
+	if policy.Empty() {
+		return nil, nil
+	}
+	return imagepolicy.NewVerifier(policy, imagepolicy.DefaultCacheFile)
+}
+
+// podImagePins are the images of the admitted pods pinned to the verified digests by pod UID.
+// A pod is pinned to the digests of its first admission, so its containers are not restarted
+// when the tags are moved, and they are persisted to survive the restart of edgecore.
+type podImagePins struct {
+	lock sync.Mutex
+	file string
+	pins map[types.UID]map[string]string
+	// generations are increased on every admission of a pod, the verification of an admission
+	// superseded by a later one or by the deletion of the pod is dropped
+	generations map[types.UID]uint64
+}
+
+func newPodImagePins(file string) *podImagePins {
+	p := &podImagePins{file: file, pins: map[types.UID]map[string]string{}, generations: map[types.UID]uint64{}}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			klog.Warningf("failed to read pinned images of pods %s: %v", file, err)
+		}
+		return p
+	}
+	if err := json.Unmarshal(data, &p.pins); err != nil {
+		klog.Warningf("failed to parse pinned images of pods %s: %v", file, err)
+		p.pins = map[types.UID]map[string]string{}
+	}
+	return p
+}
+
+// admit starts an admission of the pod, it returns the generation of the admission
+// and the images of the pod pinned before, nil if any image of the pod is not pinned
+func (p *podImagePins) admit(pod *v1.Pod) (uint64, map[string]string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.generations[pod.UID]++
+	pins := p.pins[pod.UID]
+	for _, image := range podImages(pod) {
+		if _, ok := pins[*image]; !ok {
+			return p.generations[pod.UID], nil
+		}
+	}
+	return p.generations[pod.UID], pins
+}
+
+// pin records the pinned images of the admission, it returns false if the admission is superseded
+func (p *podImagePins) pin(uid types.UID, generation uint64, pins map[string]string) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.generations[uid] != generation {
+		return false
+	}
+	p.pins[uid] = pins
+	p.save()
+	return true
+}
+
+// forget drops the pinned images of the pods, the running verifications of them are dropped as well
+func (p *podImagePins) forget(uids ...types.UID) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	changed := false
+	for _, uid := range uids {
+		// the generations start from 1, so the running verifications never match the dropped generation
+		delete(p.generations, uid)
+		if _, ok := p.pins[uid]; ok {
+			delete(p.pins, uid)
+			changed = true
+		}
+	}
+	if changed {
+		p.save()
+	}
+}
+
+// known returns the UIDs of the pods with pinned images
+func (p *podImagePins) known() []types.UID {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	uids := make([]types.UID, 0, len(p.pins))
+	for uid := range p.pins {
+		uids = append(uids, uid)
+	}
+	return uids
+}
+
+// save writes the pinned images atomically, the caller must hold the lock
+func (p *podImagePins) save() {
+	data, err := json.Marshal(p.pins)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(p.file), 0700)
+	}
+	if err == nil {
+		err = os.WriteFile(p.file+".tmp", data, 0600)
+	}
+	if err == nil {
+		err = os.Rename(p.file+".tmp", p.file)
+	}
+	if err != nil {
+		klog.Warningf("failed to save pinned images of pods %s: %v", p.file, err)
+	}
+}
+
+// podImages returns the images of all containers of the pod
+func podImages(pod *v1.Pod) []*string {
+	var images []*string
+	for i := range pod.Spec.InitContainers {
+		images = append(images, &pod.Spec.InitContainers[i].Image)
+	}
+	for i := range pod.Spec.Containers {
+		images = append(images, &pod.Spec.Containers[i].Image)
+	}
+	for i := range pod.Spec.EphemeralContainers {
+		images = append(images, &pod.Spec.EphemeralContainers[i].Image)
+	}
+	return images
+}
+
+// admitPods returns the pods admitted without verification, the other pods are verified in the background
+// and sent to updatesChan once they are admitted. The pinned images of the pods not listed are dropped.
+func (e *edged) admitPods(pods []*v1.Pod, updatesChan chan<- interface{}) []*v1.Pod {
+	if e.imageVerifier == nil {
+		return pods
+	}
+	listed := make(map[types.UID]bool, len(pods))
+	for _, pod := range pods {
+		listed[pod.UID] = true
+	}
+	var removed []types.UID
+	for _, uid := range e.imagePins.known() {
+		if !listed[uid] {
+			removed = append(removed, uid)
+		}
+	}
+	e.imagePins.forget(removed...)
+
+	var result []*v1.Pod
+	for _, pod := range pods {
+		if e.admitPod(pod, updatesChan) {
+			result = append(result, pod)
+		}
+	}
+	return result
+}
+
+// admitPod returns true if the pod is admitted without verification, that is the images of the pod are
+// verified before and the pod is pinned to the same digests. Otherwise the images of the pod are verified
+// in the background, so the registries do not hold the other pods, and the pod is sent to updatesChan
+// once it is admitted or rejected if any image fails the verification. The images of the admitted pod
+// are pinned to the verified digests, so the images run are the ones verified.
+func (e *edged) admitPod(pod *v1.Pod, updatesChan chan<- interface{}) bool {
+	if e.imageVerifier == nil {
+		return true
+	}
+	generation, pins := e.imagePins.admit(pod)
+	if pins != nil {
+		for _, image := range podImages(pod) {
+			*image = pins[*image]
+		}
+		return true
+	}
+	go func() {
+		pins, err := e.verifyPodImages(pod)
+		if err != nil {
+			e.rejectPod(pod, err.Error())
+			return
+		}
+		if !e.imagePins.pin(pod.UID, generation, pins) {
+			klog.V(4).Infof("admission of pod %s/%s is superseded", pod.Namespace, pod.Name)
+			return
+		}
+		for _, image := range podImages(pod) {
+			*image = pins[*image]
+		}
+		updatesChan <- kubelettypes.PodUpdate{Op: kubelettypes.UPDATE, Pods: []*v1.Pod{pod}, Source: kubelettypes.ApiserverSource}
+	}()
+	return false
+}
+
+// verifyPodImages verifies the images of the pod concurrently and returns the images pinned to the verified digests
+func (e *edged) verifyPodImages(pod *v1.Pod) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), admitTimeout)
+	defer cancel()
+	keyring := e.pullSecretsKeyring(ctx, pod)
+	images := podImages(pod)
+	pinned := make([]string, len(images))
+	errs := make([]error, len(images))
+	var wg sync.WaitGroup
+	for i, image := range images {
+		wg.Add(1)
+		go func(i int, image string) {
+			defer wg.Done()
+			pinned[i], errs[i] = e.imageVerifier.Verify(ctx, image, pullCredentials(keyring, image))
+		}(i, *image)
+	}
+	wg.Wait()
+
+	pins := make(map[string]string, len(images))
+	for i, image := range images {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		pins[*image] = pinned[i]
+	}
+	return pins, nil
+}
+
+// pullSecretsKeyring returns the keyring of the image pull secrets of the pod, the same as kubelet pulls images with
+func (e *edged) pullSecretsKeyring(ctx context.Context, pod *v1.Pod) credentialprovider.DockerKeyring {
+	var secrets []v1.Secret
+	for _, ref := range pod.Spec.ImagePullSecrets {
+		secret, err := e.KubeletDeps.KubeClient.CoreV1().Secrets(pod.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			klog.Warningf("failed to get image pull secret %s/%s of pod %s: %v", pod.Namespace, ref.Name, pod.Name, err)
+			continue
+		}
+		secrets = append(secrets, *secret)
+	}
+	keyring, err := credentialprovidersecrets.MakeDockerKeyring(secrets, &credentialprovider.BasicDockerKeyring{})
+	if err != nil {
+		klog.Warningf("failed to parse image pull secrets of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		return &credentialprovider.BasicDockerKeyring{}
+	}
+	return keyring
+}
+
+// pullCredentials returns the credentials of the keyring for the image, it returns nil if there are none
+func pullCredentials(keyring credentialprovider.DockerKeyring, image string) imagepolicy.Credentials {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return nil
+	}
+	auths, ok := keyring.Lookup(named.Name())
+	if !ok || len(auths) == 0 {
+		return nil
+	}
+	auth := auths[0]
+	return func(string) (string, string, error) {
+		if auth.Username == "" {
+			return "", auth.IdentityToken, nil
+		}
+		return auth.Username, auth.Password, nil
+	}
+}
+
+// rejectPod records the event of the rejected pod and reports the pod failed, the same as kubelet rejects pods
+func (e *edged) rejectPod(pod *v1.Pod, message string) {
+	klog.Warningf("pod %s/%s is rejected: %s", pod.Namespace, pod.Name, message)
+	if e.KubeletDeps.Recorder != nil {
+		e.KubeletDeps.Recorder.Eventf(pod, v1.EventTypeWarning, ReasonImageVerificationFailed, message)
+	}
+	if pod.Status.Phase == v1.PodFailed && pod.Status.Reason == ReasonImageVerificationFailed {
+		return
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": v1.PodStatus{
+			Phase:   v1.PodFailed,
+			Reason:  ReasonImageVerificationFailed,
+			Message: fmt.Sprintf("Pod was rejected: %s", message),
+		},
+	})
+	if err != nil {
+		klog.Errorf("failed to marshal status of rejected pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		return
+	}
+	// the status is reported asynchronously, so the pods from the cloud are not blocked when the cloud is unreachable
+	go func() {
+		_, err := e.KubeletDeps.KubeClient.CoreV1().Pods(pod.Namespace).Patch(context.Background(), pod.Name,
+			types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "status")
+		if err != nil {
+			klog.Errorf("failed to report status of rejected pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+	}()
+}
@@ -5,11 +5,14 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
 	"io"
+	"net"
 	nethttp "net/http"
 	"os"
+	"path/filepath"
 	"time"
 
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -21,6 +24,7 @@ import (
 
 	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
 	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/common/http"
 	"github.com/kubeedge/kubeedge/pkg/security/certs"
 	"github.com/kubeedge/kubeedge/pkg/security/token"
@@ -40,6 +44,9 @@ var jitteryDuration = func(totalDuration float64) time.Duration {
 
 var CleanupTokenChan = make(chan struct{}, 1)
 
+// caRefreshInterval is the interval to check whether the CA of cloudcore is rotated
+const caRefreshInterval = 10 * time.Minute
+
 type CertManager struct {
 	RotateCertificates bool
 	NodeName           string
@@ -109,18 +116,29 @@ func (cm *CertManager) applyCerts() error {
 		return fmt.Errorf("failed to get CA certificate, err: %v", err)
 	}
 
-	// validate the CA certificate by hashcode
-	realToken, err := token.VerifyCAAndGetRealToken(cm.token, cacert)
+	// during CA rotation cloudcore returns a trust bundle of the new CA and the old one
+	cas, err := x509.ParseCertificates(cacert)
+	if err != nil || len(cas) == 0 {
+		return fmt.Errorf("failed to parse CA certificate, err: %v", err)
+	}
+
+	// validate the CA certificate by hashcode, the token may be created with any CA of the bundle
+	var realToken string
+	for _, ca := range cas {
+		if realToken, err = token.VerifyCAAndGetRealToken(cm.token, ca.Raw); err == nil {
+			break
+		}
+	}
 	if err != nil {
 		return err
 	}
 
 	// save the ca.crt to file
-	caPem, err := certs.WriteDERToPEMFile(cm.caFile, cert.CertificateBlockType, cacert)
-	if err != nil {
+	caPem := encodeCABundle(cas)
+	if err := writeCABundle(cm.caFile, caPem); err != nil {
 		return fmt.Errorf("failed to save the CA certificate to file: %s, error: %v", cm.caFile, err)
 	}
-	certDER, keyDER, err := cm.GetEdgeCert(cm.certURL, pem.EncodeToMemory(caPem), tls.Certificate{}, realToken)
+	certDER, keyDER, err := cm.GetEdgeCert(cm.certURL, caPem, tls.Certificate{}, realToken)
 	if err != nil {
 		return fmt.Errorf("failed to get edge certificate from the cloudcore, error: %v", err)
 	}
@@ -149,8 +167,27 @@ func (cm *CertManager) rotate() {
 
 			timer := time.NewTimer(sleepInterval)
 			defer timer.Stop()
-
-			<-timer.C // unblock when deadline expires
+			ticker := time.NewTicker(caRefreshInterval)
+			defer ticker.Stop()
+
+		waiting:
+			for {
+				select {
+				case <-timer.C: // unblock when deadline expires
+					break waiting
+				case <-ticker.C:
+					// rotate the certificate immediately if the CA of cloudcore is rotated
+					rotated, err := cm.refreshCA()
+					if err != nil {
+						klog.Warningf("failed to refresh CA certificate: %v", err)
+						continue
+					}
+					if rotated {
+						klog.Infof("CA of CloudCore is rotated, rotate the certificate to be signed by the new CA")
+						break waiting
+					}
+				}
+			}
 		}
 
 		backoff := wait.Backoff{
@@ -217,6 +254,89 @@ func (cm *CertManager) rotateCert() (bool, error) {
 	return true, nil
 }
 
+// refreshCA gets the trust bundle from cloudcore and saves it if it changes.
+// It returns true if the current certificate is not signed by the signing CA,
+// which is the first one of the bundle, so the certificate needs to be rotated.
+func (cm *CertManager) refreshCA() (bool, error) {
+	tlsCert, err := cm.getCurrent()
+	if err != nil {
+		return false, fmt.Errorf("failed to get current certificate: %v", err)
+	}
+	caPem, err := cm.getCA()
+	if err != nil {
+		return false, fmt.Errorf("failed to get CA certificate locally: %v", err)
+	}
+	// the bundle is trusted because the connection is verified by the current CA
+	client, err := http.NewHTTPClientWithCA(caPem, *tlsCert)
+	if err != nil {
+		return false, fmt.Errorf("failed to create a http client, err: %v", err)
+	}
+	req, err := http.BuildRequest(nethttp.MethodGet, cm.caURL, nil, "", "")
+	if err != nil {
+		return false, err
+	}
+	res, err := http.SendRequest(req, client)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	content, err := io.ReadAll(io.LimitReader(res.Body, constants.MaxRespBodyLength))
+	if err != nil {
+		return false, err
+	}
+	if res.StatusCode != nethttp.StatusOK {
+		return false, fmt.Errorf("failed to call http, code: %d, message: %s", res.StatusCode, string(content))
+	}
+	cas, err := x509.ParseCertificates(content)
+	if err != nil || len(cas) == 0 {
+		return false, fmt.Errorf("failed to parse CA certificate, err: %v", err)
+	}
+
+	if bundle := encodeCABundle(cas); !bytes.Equal(bundle, caPem) {
+		if err := writeCABundle(cm.caFile, bundle); err != nil {
+			return false, fmt.Errorf("failed to save the CA certificate to file: %s, error: %v", cm.caFile, err)
+		}
+		klog.Infof("CA certificate is updated, %d CA certificates are trusted", len(cas))
+	}
+	return tlsCert.Leaf.CheckSignatureFrom(cas[0]) != nil, nil
+}
+
+// encodeCABundle encodes the CA certificates in pem format
+func encodeCABundle(cas []*x509.Certificate) []byte {
+	var bundle []byte
+	for _, ca := range cas {
+		bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: cert.CertificateBlockType, Bytes: ca.Raw})...)
+	}
+	return bundle
+}
+
+// writeCABundle saves the CA certificates in pem format to the file. The trust bundle must not be
+// writable by other users, it is written to a temporary file and renamed so that an existing file
+// gets the permissions as well and a crash does not leave a partial bundle behind.
+func writeCABundle(file string, bundle []byte) error {
+	dir := filepath.Dir(file)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(file)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(bundle); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
 // getCA returns the CA in pem format.
 func (cm *CertManager) getCA() ([]byte, error) {
 	return os.ReadFile(cm.caFile)
@@ -245,6 +365,28 @@ func GetCACert(url string) ([]byte, error) {
 // GetEdgeCert applies for the certificate from cloudcore
 func (cm *CertManager) GetEdgeCert(url string, capem []byte, tlscert tls.Certificate, token string,
 ) ([]byte, []byte, error) {
+	return cm.requestCert(url, capem, tlscert, token, nil, nil)
+}
+
+// GetServingCert applies for a certificate serving HTTPS on the IPs of the node from cloudcore,
+// the request is authenticated by the current edge certificate
+func (cm *CertManager) GetServingCert(ips []net.IP) ([]byte, []byte, error) {
+	tlsCert, err := cm.getCurrent()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get current certificate: %v", err)
+	}
+	caPem, err := cm.getCA()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get CA certificate locally: %v", err)
+	}
+	return cm.requestCert(cm.certURL, caPem, *tlsCert, "", &certutil.AltNames{IPs: ips},
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth})
+}
+
+// requestCert requests cloudcore to sign a certificate of the node, cloudcore signs it
+// for client auth if usages is empty
+func (cm *CertManager) requestCert(url string, capem []byte, tlscert tls.Certificate, token string,
+	alt *certutil.AltNames, usages []x509.ExtKeyUsage) ([]byte, []byte, error) {
 	h := certs.GetHandler(certs.HandlerTypeX509)
 	pkw, err := h.GenPrivateKey()
 	if err != nil {
@@ -256,7 +398,7 @@ func (cm *CertManager) GetEdgeCert(url string, capem []byte, tlscert tls.Certifi
 		Locality:     []string{"Hangzhou"},
 		Province:     []string{"Zhejiang"},
 		CommonName:   fmt.Sprintf("system:node:%s", cm.NodeName),
-	}, pkw, nil)
+	}, pkw, alt)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create a csr of edge cert, err %v", err)
 	}
@@ -270,6 +412,13 @@ func (cm *CertManager) GetEdgeCert(url string, capem []byte, tlscert tls.Certifi
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate a http request, err: %v", err)
 	}
+	if len(usages) > 0 {
+		usagesJSON, err := json.Marshal(usages)
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Set(types.HeaderExtKeyUsages, string(usagesJSON))
+	}
 
 	res, err := http.SendRequest(req, client)
 	if err != nil {
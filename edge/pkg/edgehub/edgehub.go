@@ -1,6 +1,7 @@
 package edgehub
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
 	"github.com/kubeedge/beehive/pkg/core"
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	connect "github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/certificate"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/clients"
@@ -32,6 +34,21 @@ var _ core.Module = (*EdgeHub)(nil)
 
 var certSync map[string]chan bool
 
+// forceReconnectChan receives the requests to reconnect to cloud
+var forceReconnectChan = make(chan struct{}, 1)
+
+// ForceReconnect asks EdgeHub to break the connection to cloud and connect again
+func ForceReconnect() error {
+	if !connect.IsConnected() {
+		return fmt.Errorf("edgehub is not connected to cloud")
+	}
+	select {
+	case forceReconnectChan <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
 func GetCertSyncChannel() map[string]chan bool {
 	return certSync
 }
@@ -102,6 +119,7 @@ func (eh *EdgeHub) Start() {
 
 		err = eh.chClient.Init()
 		if err != nil {
+			connect.SetLastError(err)
 			klog.Errorf("connection failed: %v, will reconnect after %s", err, waitTime.String())
 			time.Sleep(waitTime)
 			continue
@@ -114,7 +132,11 @@ func (eh *EdgeHub) Start() {
 
 		// wait the stop signal
 		// stop authinfo manager/websocket connection
-		<-eh.reconnectChan
+		select {
+		case <-eh.reconnectChan:
+		case <-forceReconnectChan:
+			klog.Warning("reconnect to cloud is requested")
+		}
 		eh.chClient.UnInit()
 
 		// execute hook fun after disconnect
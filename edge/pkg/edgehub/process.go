@@ -117,6 +117,7 @@ func (eh *EdgeHub) routeToEdge() {
 		message, err := eh.chClient.Receive()
 		if err != nil {
 			klog.Errorf("websocket read error: %v", err)
+			connect.SetLastError(err)
 			eh.reconnectChan <- struct{}{}
 			return
 		}
@@ -166,6 +167,7 @@ func (eh *EdgeHub) routeToCloud() {
 		err = eh.sendToCloud(message)
 		if err != nil {
 			klog.Errorf("failed to send message to cloud: %v", err)
+			connect.SetLastError(err)
 			eh.reconnectChan <- struct{}{}
 			return
 		}
@@ -188,6 +190,7 @@ func (eh *EdgeHub) keepalive() {
 		err := eh.sendToCloud(*msg)
 		if err != nil {
 			klog.Errorf("websocket write error: %v", err)
+			connect.SetLastError(err)
 			eh.reconnectChan <- struct{}{}
 			return
 		}
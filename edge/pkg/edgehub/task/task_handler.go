@@ -68,10 +68,11 @@ func (th *taskHandler) Process(message *model.Message, _ clients.Adapter) error
 	}
 
 	resp := commontypes.NodeTaskResponse{
-		NodeName: options.GetEdgeCoreConfig().Modules.Edged.HostnameOverride,
-		Event:    event.Type,
-		Action:   event.Action,
-		Reason:   event.Msg,
+		NodeName:        options.GetEdgeCoreConfig().Modules.Edged.HostnameOverride,
+		Event:           event.Type,
+		Action:          event.Action,
+		Reason:          event.Msg,
+		ExternalMessage: event.ExternalMessage,
 	}
 	util.ReportTaskResult(taskReq.Type, taskReq.TaskID, resp)
 	return nil
@@ -17,8 +17,11 @@ limitations under the License.
 package taskexecutor
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
@@ -31,7 +34,9 @@ import (
 	"github.com/kubeedge/kubeedge/common/types"
 	commontypes "github.com/kubeedge/kubeedge/common/types"
 	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/imagepolicy"
 	edgeutil "github.com/kubeedge/kubeedge/edge/pkg/common/util"
+	"github.com/kubeedge/kubeedge/edge/pkg/imagemirror"
 	metaclient "github.com/kubeedge/kubeedge/edge/pkg/metamanager/client"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
 	"github.com/kubeedge/kubeedge/pkg/util/fsm"
@@ -55,6 +60,9 @@ func NewPrePullExecutor() Executor {
 		string(api.TaskInit):     emptyInit,
 		"":                       emptyInit,
 		string(api.PullingState): pullImages,
+		// the seed node is told when the task finishes
+		string(api.TaskSuccessful): releaseMirror,
+		string(api.TaskFailed):     releaseMirror,
 	}
 	return &PrePull{
 		BaseExecutor: NewBaseExecutor(TaskPrePull, methods),
@@ -87,7 +95,7 @@ func pullImages(taskReq types.NodeTaskRequest) fsm.Event {
 	}
 
 	go func() {
-		errorStr, imageStatus := prePullImages(*prePullReq, container)
+		errorStr, imageStatus := prePullImages(taskReq.TaskID, *prePullReq, container)
 		if errorStr != "" {
 			event.Action = api.ActionFailure
 			event.Msg = errorStr
@@ -109,6 +117,12 @@ func pullImages(taskReq types.NodeTaskRequest) fsm.Event {
 	return fsm.Event{}
 }
 
+// releaseMirror makes the image mirror stop serving the images of the finished task
+func releaseMirror(taskReq types.NodeTaskRequest) fsm.Event {
+	imagemirror.Release(taskReq.TaskID)
+	return fsm.Event{}
+}
+
 func getImagePrePullJobRequest(taskReq commontypes.NodeTaskRequest) (*commontypes.ImagePrePullJobRequest, error) {
 	var prePullReq commontypes.ImagePrePullJobRequest
 	data, err := json.Marshal(taskReq.Item)
@@ -122,20 +136,84 @@ func getImagePrePullJobRequest(taskReq commontypes.NodeTaskRequest) (*commontype
 	return &prePullReq, err
 }
 
-func prePullImages(prePullReq commontypes.ImagePrePullJobRequest, container util.ContainerRuntime) (string, []v1alpha1.ImageStatus) {
+// runtimeHostsDir returns the directory of the registry host configs of the container runtime
+func runtimeHostsDir() string {
+	if m := options.GetEdgeCoreConfig().Modules.ImageMirror; m != nil && m.RuntimeHostsDir != "" {
+		return m.RuntimeHostsDir
+	}
+	return constants.DefaultContainerdHostsDir
+}
+
+// mirrorAddress returns the address of the image mirror on the seed node, which listens on
+// the port of the image mirror config
+func mirrorAddress(seedIP string) string {
+	if seedIP == "" {
+		return ""
+	}
+	port := int32(constants.DefaultImageMirrorPort)
+	if m := options.GetEdgeCoreConfig().Modules.ImageMirror; m != nil && m.Port != 0 {
+		port = m.Port
+	}
+	return net.JoinHostPort(seedIP, strconv.Itoa(int(port)))
+}
+
+func prePullImages(taskID string, prePullReq commontypes.ImagePrePullJobRequest, container util.ContainerRuntime) (string, []v1alpha1.ImageStatus) {
 	errorStr := ""
 	authConfig, err := makeAuthConfig(prePullReq.Secret)
 	if err != nil {
 		return errorStr, []v1alpha1.ImageStatus{}
 	}
 
+	edgeCoreConfig := options.GetEdgeCoreConfig()
+	edgeHub := edgeCoreConfig.Modules.EdgeHub
+	tlsFiles := imagemirror.TLSFiles{CAFile: edgeHub.TLSCAFile, CertFile: edgeHub.TLSCertFile, KeyFile: edgeHub.TLSPrivateKeyFile}
+	mirror, source := pullSource(taskID, prePullReq, mirrorAddress(prePullReq.Mirror), edgeCoreConfig.Modules.Edged.HostnameOverride, tlsFiles, authConfig)
+	hostsDir := runtimeHostsDir()
+	// the registry host configs are written once per task, they are unchanged while the seed node is the same
+	mirrored := map[string]bool{}
+	if mirror != "" {
+		for _, image := range prePullReq.Images {
+			registry := imageRegistry(image)
+			if _, ok := mirrored[registry]; ok {
+				continue
+			}
+			err := imagemirror.ConfigureRuntimeMirror(hostsDir, registry, mirror, tlsFiles)
+			if err != nil {
+				klog.Warningf("failed to configure image mirror %s for registry %s, pull images from the registry: %v", mirror, registry, err)
+			}
+			mirrored[registry] = err == nil
+		}
+	}
+
+	verify := imageVerification(prePullReq.VerificationPolicy, authConfig)
+
 	var imageStatus []v1alpha1.ImageStatus
 	for _, image := range prePullReq.Images {
+		pinned, err := verify(image)
+		if err != nil {
+			klog.Errorf("image %s is rejected, err: %v", image, err)
+			errorStr = fmt.Sprintf("image verification failed, err: %v", err)
+			imageStatus = append(imageStatus, v1alpha1.ImageStatus{
+				Image:  image,
+				State:  api.TaskFailed,
+				Reason: fmt.Sprintf("image verification failed: %v", err),
+			})
+			continue
+		}
 		prePullStatus := v1alpha1.ImageStatus{
-			Image: image,
+			Image:  image,
+			Source: v1alpha1.ImageSourceRegistry,
+		}
+		// the image is pulled from the registry unless the mirror serves it
+		if mirrored[imageRegistry(image)] && source != v1alpha1.ImageSourceRegistry {
+			if err := imagemirror.ProbeImage(mirror, pinned, tlsFiles); err != nil {
+				klog.Warningf("image %s is not served by image mirror %s, pull it from the registry: %v", image, mirror, err)
+			} else {
+				prePullStatus.Source = source
+			}
 		}
 		for i := 0; i <= int(prePullReq.RetryTimes); i++ {
-			err = container.PullImage(image, authConfig, nil)
+			err = container.PullImage(pinned, authConfig, nil)
 			if err == nil {
 				break
 			}
@@ -155,6 +233,73 @@ func prePullImages(prePullReq commontypes.ImagePrePullJobRequest, container util
 	return errorStr, imageStatus
 }
 
+// imageVerification returns the function verifying images against the verification policy of the task,
+// the credentials of the pull secret are used to fetch the signatures. The function returns the image to
+// pull, which is pinned to the verified digest.
+func imageVerification(policy *v1alpha1.ImageVerificationPolicy, authConfig *runtimeapi.AuthConfig) func(string) (string, error) {
+	p := imagepolicy.FromJobPolicy(policy)
+	if p.Empty() {
+		return func(image string) (string, error) { return image, nil }
+	}
+	verifier, err := imagepolicy.NewVerifier(p, imagepolicy.DefaultCacheFile)
+	if err != nil {
+		return func(string) (string, error) { return "", err }
+	}
+	var creds imagepolicy.Credentials
+	if authConfig != nil && authConfig.Username != "" {
+		creds = func(string) (string, string, error) {
+			return authConfig.Username, authConfig.Password, nil
+		}
+	}
+	return func(image string) (string, error) {
+		return verifier.Verify(context.Background(), image, creds)
+	}
+}
+
+// pullSource returns the address of the image mirror the node pulls images from and the source
+// reported in the image status of the images the mirror serves. The seed node pulls images from
+// the registry through its own mirror, so the mirror caches them for the other nodes of the task in
+// the node group, and it grants the images of the task to them with the credentials of the pull secret.
+// The node pulls images from the registry directly if the task is not in P2P mode or the mirror is unreachable.
+func pullSource(taskID string, prePullReq commontypes.ImagePrePullJobRequest, mirror, nodeName string,
+	tlsFiles imagemirror.TLSFiles, authConfig *runtimeapi.AuthConfig) (string, string) {
+	if mirror == "" {
+		return "", v1alpha1.ImageSourceRegistry
+	}
+	source := prePullReq.SeedNode
+	if prePullReq.SeedNode == nodeName {
+		source = v1alpha1.ImageSourceRegistry
+		grant := imagemirror.Grant{
+			Task:        taskID,
+			Clients:     prePullReq.MirrorClients,
+			RevokedAt:   prePullReq.RevokedClients,
+			Credentials: mirrorCredentials(authConfig),
+		}
+		if err := imagemirror.GrantImages(grant, prePullReq.Images); err != nil {
+			klog.Warningf("failed to grant the images to the image mirror, pull images from the registry: %v", err)
+			return "", v1alpha1.ImageSourceRegistry
+		}
+	}
+	if err := imagemirror.ProbeMirror(mirror, tlsFiles); err != nil {
+		klog.Warningf("image mirror %s on seed node %s is unreachable, pull images from the registry: %v", mirror, prePullReq.SeedNode, err)
+		return "", v1alpha1.ImageSourceRegistry
+	}
+	return mirror, source
+}
+
+// mirrorCredentials returns the credentials the image mirror pulls the images of the task with
+func mirrorCredentials(authConfig *runtimeapi.AuthConfig) imagemirror.Credentials {
+	if authConfig == nil || (authConfig.Username == "" && authConfig.IdentityToken == "") {
+		return nil
+	}
+	return func(string) (string, string, error) {
+		if authConfig.Username == "" {
+			return "", authConfig.IdentityToken, nil
+		}
+		return authConfig.Username, authConfig.Password, nil
+	}
+}
+
 func makeAuthConfig(pullsecret string) (*runtimeapi.AuthConfig, error) {
 	if pullsecret == "" {
 		return nil, nil
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"testing"
+
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/kubeedge/api/apis/operations/v1alpha1"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/pkg/imagemirror"
+)
+
+func TestImageVerification(t *testing.T) {
+	const pinned = "nginx@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+
+	verify := imageVerification(nil, nil)
+	if image, err := verify("nginx:1.25"); err != nil || image != "nginx:1.25" {
+		t.Errorf("expected images to be allowed without policy, got %s %v", image, err)
+	}
+
+	verify = imageVerification(&v1alpha1.ImageVerificationPolicy{
+		RequireDigest:     true,
+		AllowedRegistries: []string{"docker.io"},
+	}, nil)
+	if image, err := verify(pinned); err != nil || image != pinned {
+		t.Errorf("expected the pinned image to be allowed, got %s %v", image, err)
+	}
+	for _, image := range []string{"nginx:1.25", "quay.io/app@sha256:0000000000000000000000000000000000000000000000000000000000000000"} {
+		if _, err := verify(image); err == nil {
+			t.Errorf("expected image %s to be rejected", image)
+		}
+	}
+
+	verify = imageVerification(&v1alpha1.ImageVerificationPolicy{CosignPublicKeys: []string{"invalid"}}, nil)
+	if _, err := verify(pinned); err == nil {
+		t.Error("expected images to be rejected with invalid public keys")
+	}
+}
+
+func TestPullSource(t *testing.T) {
+	var files imagemirror.TLSFiles
+	mirror, source := pullSource("job", commontypes.ImagePrePullJobRequest{}, "", "edge-a", files, nil)
+	if mirror != "" || source != v1alpha1.ImageSourceRegistry {
+		t.Errorf("expected to pull from the registry without mirror, got %s %s", mirror, source)
+	}
+
+	// the mirror is unreachable, fall back to the registry
+	mirror, source = pullSource("job", commontypes.ImagePrePullJobRequest{SeedNode: "edge-b", Mirror: "127.0.0.1"}, "127.0.0.1:1", "edge-a", files, nil)
+	if mirror != "" || source != v1alpha1.ImageSourceRegistry {
+		t.Errorf("expected to fall back to the registry, got %s %s", mirror, source)
+	}
+
+	// the images can not be granted to the mirror which is not running on the seed node
+	mirror, source = pullSource("job", commontypes.ImagePrePullJobRequest{SeedNode: "edge-a", Mirror: "127.0.0.1", Images: []string{"nginx"}}, "127.0.0.1:1", "edge-a", files, nil)
+	if mirror != "" || source != v1alpha1.ImageSourceRegistry {
+		t.Errorf("expected the seed node to fall back to the registry, got %s %s", mirror, source)
+	}
+}
+
+func TestMirrorCredentials(t *testing.T) {
+	if mirrorCredentials(nil) != nil || mirrorCredentials(&runtimeapi.AuthConfig{}) != nil {
+		t.Error("expected no credentials without pull secret")
+	}
+	user, password, err := mirrorCredentials(&runtimeapi.AuthConfig{Username: "user", Password: "password"})("docker.io")
+	if err != nil || user != "user" || password != "password" {
+		t.Errorf("unexpected credentials %s %s %v", user, password, err)
+	}
+	user, token, err := mirrorCredentials(&runtimeapi.AuthConfig{IdentityToken: "token"})("docker.io")
+	if err != nil || user != "" || token != "token" {
+		t.Errorf("unexpected credentials %s %s %v", user, token, err)
+	}
+}
@@ -17,15 +17,9 @@ limitations under the License.
 package taskexecutor
 
 import (
-	"fmt"
-	"io"
-	"os"
 	"path/filepath"
 
-	"k8s.io/klog/v2"
-
 	api "github.com/kubeedge/api/apis/fsm/v1alpha1"
-	"github.com/kubeedge/kubeedge/common/constants"
 	commontypes "github.com/kubeedge/kubeedge/common/types"
 	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
@@ -45,62 +39,24 @@ func backupNode(commontypes.NodeTaskRequest) (event fsm.Event) {
 			event.Msg = err.Error()
 		}
 	}()
-	backupPath := filepath.Join(util.KubeEdgeBackupPath, version.Get().String())
-	err = backup(backupPath)
-	if err != nil {
-		cleanErr := os.Remove(backupPath)
-		if cleanErr != nil {
-			klog.Warningf("clean backup path failed: %s", err.Error())
-		}
-		return
-	}
+	err = backup(version.Get().String())
 	return event
 }
 
-func backup(backupPath string) error {
+// backup backs up the databases, config, encryption key and binary of the running edgecore, so the node can be
+// rolled back to this version if the upgrade fails
+func backup(backupVersion string) error {
 	config := options.GetEdgeCoreConfig()
-	klog.Infof("backup start, backup path: %s", backupPath)
-	if err := os.MkdirAll(backupPath, 0750); err != nil {
-		return fmt.Errorf("mkdirall failed: %v", err)
-	}
-
-	// backup edgecore.db: copy from origin path to backup path
-	if err := filecopy(config.DataBase.DataSource, filepath.Join(backupPath, "edgecore.db")); err != nil {
-		return fmt.Errorf("failed to backup db: %v", err)
-	}
-	// backup edgecore.yaml: copy from origin path to backup path
-	if err := filecopy(constants.DefaultConfigDir+"edgecore.yaml", filepath.Join(backupPath, "edgecore.yaml")); err != nil {
-		return fmt.Errorf("failed to back config: %v", err)
-	}
-	// backup edgecore: copy from origin path to backup path
-	if err := filecopy(filepath.Join(util.KubeEdgeUsrBinPath, util.KubeEdgeBinaryName), filepath.Join(backupPath, util.KubeEdgeBinaryName)); err != nil {
-		return fmt.Errorf("failed to backup edgecore: %v", err)
-	}
-	return nil
-}
-
-func filecopy(src, dst string) error {
-	sourceFileStat, err := os.Stat(src)
-	if err != nil {
-		return err
-	}
-
-	if !sourceFileStat.Mode().IsRegular() {
-		return fmt.Errorf("%s is not a regular file", src)
-	}
-
-	source, err := os.Open(src)
-	if err != nil {
-		return err
-	}
-	defer source.Close()
-
-	// copy file using src file mode
-	destination, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_TRUNC, sourceFileStat.Mode())
-	if err != nil {
-		return err
-	}
-	defer destination.Close()
-	_, err = io.Copy(destination, source)
+	boltDataSource, encryptionKeyFile := util.MetaDataFiles(config)
+	_, err := util.BackupEdgeNode(util.BackupOptions{
+		BackupPath:        util.KubeEdgeBackupPath,
+		Version:           backupVersion,
+		DataSource:        config.DataBase.DataSource,
+		ConfigFile:        options.GetEdgeCoreOptions().ConfigFile,
+		BinaryFile:        filepath.Join(util.KubeEdgeUsrBinPath, util.KubeEdgeBinaryName),
+		BoltDataSource:    boltDataSource,
+		EncryptionKeyFile: encryptionKeyFile,
+		Retention:         util.DefaultBackupRetention,
+	})
 	return err
 }
@@ -18,14 +18,17 @@ package taskexecutor
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/shirou/gopsutil/cpu"
 	"github.com/shirou/gopsutil/disk"
 	"github.com/shirou/gopsutil/v3/mem"
+	"k8s.io/klog/v2"
 
 	api "github.com/kubeedge/api/apis/fsm/v1alpha1"
+	"github.com/kubeedge/api/apis/operations/v1alpha1"
 	"github.com/kubeedge/kubeedge/common/types"
 	"github.com/kubeedge/kubeedge/pkg/util/fsm"
 )
@@ -34,8 +37,28 @@ const (
 	MaxCPUUsage  float64 = 80
 	MaxMemUsage  float64 = 80
 	MaxDiskUsage float64 = 80
+	// MaxInodeUsage is the max allowed usage of inodes on each partition
+	MaxInodeUsage float64 = 80
 )
 
+// PreCheck checks whether the edge node is ready to execute the task, it returns the reason if not ready
+type PreCheck func(types.NodePreCheckRequest) error
+
+var preChecks = map[string]PreCheck{
+	"cpu":   func(types.NodePreCheckRequest) error { return checkCPU() },
+	"mem":   func(types.NodePreCheckRequest) error { return checkMem() },
+	"disk":  func(types.NodePreCheckRequest) error { return checkDisk() },
+	"inode": func(types.NodePreCheckRequest) error { return checkInode() },
+}
+
+// RegisterPreCheck registers a check item which can be selected by name in the CheckItems of tasks
+func RegisterPreCheck(name string, check PreCheck) {
+	if _, ok := preChecks[name]; ok {
+		klog.Warningf("pre-check %s exists", name)
+	}
+	preChecks[name] = check
+}
+
 func preCheck(taskReq types.NodeTaskRequest) fsm.Event {
 	event := fsm.Event{
 		Type:   "Check",
@@ -49,39 +72,30 @@ func preCheck(taskReq types.NodeTaskRequest) fsm.Event {
 		event.Msg = err.Error()
 		return event
 	}
-	var checkItems types.NodePreCheckRequest
-	err = json.Unmarshal(data, &checkItems)
+	var checkReq types.NodePreCheckRequest
+	err = json.Unmarshal(data, &checkReq)
 	if err != nil {
 		event.Action = api.ActionFailure
 		event.Msg = err.Error()
 		return event
 	}
 
-	var failed bool
-	var checkResult = map[string]string{}
-	var checkFunc = map[string]func() error{
-		"cpu":  checkCPU,
-		"mem":  checkMem,
-		"disk": checkDisk,
-	}
-	for _, item := range checkItems.CheckItem {
-		f, ok := checkFunc[item]
-		if !ok {
-			checkResult[item] = "check item not support"
-			continue
-		}
-		err = f()
-		if err != nil {
-			failed = true
-			checkResult[item] = err.Error()
-			continue
-		}
-		checkResult[item] = "ok"
+	results, failed := runPreChecks(checkReq)
+	if data, err := json.Marshal(results); err != nil {
+		klog.Warningf("marshal check results failed: %v", err)
+	} else {
+		event.ExternalMessage = string(data)
 	}
 	if !failed {
 		return event
 	}
 	event.Action = api.ActionFailure
+	var checkResult = map[string]string{}
+	for _, result := range results {
+		if result.Result == v1alpha1.CheckResultFailed {
+			checkResult[result.Name] = result.Message
+		}
+	}
 	result, err := json.Marshal(checkResult)
 	if err != nil {
 		event.Msg = err.Error()
@@ -91,6 +105,41 @@ func preCheck(taskReq types.NodeTaskRequest) fsm.Event {
 	return event
 }
 
+// runPreChecks runs the check items in order, the registered checks take precedence over the hooks.
+// Unsupported check items are reported but do not fail the pre-check.
+func runPreChecks(checkReq types.NodePreCheckRequest) ([]v1alpha1.CheckResult, bool) {
+	var failed bool
+	results := make([]v1alpha1.CheckResult, 0, len(checkReq.CheckItem))
+	for _, item := range checkReq.CheckItem {
+		check, ok := preChecks[item]
+		if !ok {
+			check, ok = preCheckHook(item)
+		}
+		if !ok {
+			results = append(results, v1alpha1.CheckResult{
+				Name:    item,
+				Result:  v1alpha1.CheckResultUnsupported,
+				Message: "check item not support",
+			})
+			continue
+		}
+		if err := check(checkReq); err != nil {
+			failed = true
+			results = append(results, v1alpha1.CheckResult{
+				Name:    item,
+				Result:  v1alpha1.CheckResultFailed,
+				Message: err.Error(),
+			})
+			continue
+		}
+		results = append(results, v1alpha1.CheckResult{
+			Name:   item,
+			Result: v1alpha1.CheckResultPassed,
+		})
+	}
+	return results, failed
+}
+
 func checkCPU() error {
 	cpuUsage, err := cpu.Percent(100*time.Millisecond, false)
 	if err != nil {
@@ -149,6 +198,36 @@ func checkDisk() error {
 	return fmt.Errorf(string(result))
 }
 
+func checkInode() error {
+	partitions, err := disk.Partitions(false)
+	if err != nil {
+		return err
+	}
+	var inodeUsages = map[string]string{}
+	for _, part := range partitions {
+		usage, err := disk.Usage(part.Mountpoint)
+		if err != nil {
+			inodeUsages[part.Device] = err.Error()
+			continue
+		}
+		// some filesystems such as btrfs do not have a fixed number of inodes
+		if usage.InodesTotal == 0 {
+			continue
+		}
+		if usage.InodesUsedPercent > MaxInodeUsage {
+			inodeUsages[part.Device] = fmt.Sprintf("current inode usage is %f, which exceeds the maximum allowed usage %f", usage.InodesUsedPercent, MaxInodeUsage)
+		}
+	}
+	if len(inodeUsages) == 0 {
+		return nil
+	}
+	result, err := json.Marshal(inodeUsages)
+	if err != nil {
+		return err
+	}
+	return errors.New(string(result))
+}
+
 func normalInit(types.NodeTaskRequest) fsm.Event {
 	return fsm.Event{
 		Type:   "Init",
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+const (
+	// DefaultPreCheckHookDir is the directory of the user-defined pre-check hooks,
+	// a check item not registered is run by the executable with the same name in it
+	DefaultPreCheckHookDir = "/etc/kubeedge/precheck.d"
+	// preCheckHookTimeout is the max running time of a pre-check hook
+	preCheckHookTimeout = 30 * time.Second
+)
+
+var preCheckHookDir = DefaultPreCheckHookDir
+
+// preCheckHook returns the check running the executable hook of the check item,
+// it returns false if there is no such hook
+func preCheckHook(name string) (PreCheck, bool) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, false
+	}
+	path := filepath.Join(preCheckHookDir, name)
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+		return nil, false
+	}
+	return func(types.NodePreCheckRequest) error {
+		return runPreCheckHook(path)
+	}, true
+}
+
+// runPreCheckHook runs the hook, the check passes if the hook exits with 0,
+// otherwise the output of the hook is the reason of the failure
+func runPreCheckHook(path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), preCheckHookTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, path).CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("hook %s timed out after %v", path, preCheckHookTimeout)
+	}
+	if msg := strings.TrimSpace(string(output)); msg != "" {
+		return fmt.Errorf("hook %s failed: %v, output: %s", path, err, msg)
+	}
+	return fmt.Errorf("hook %s failed: %v", path, err)
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/common/http"
+	"github.com/kubeedge/kubeedge/edge/pkg/imagemirror"
+)
+
+const (
+	// MaxClockSkew is the max allowed difference between the clocks of the edge node and cloudcore
+	MaxClockSkew = 30 * time.Second
+	// MaxCloudLatency is the max allowed latency of the requests from the edge node to cloudcore
+	MaxCloudLatency = 2 * time.Second
+
+	// defaultRegistry is the registry of the images without registry domain
+	defaultRegistry = "docker.io"
+	// registryProbeTimeout is the timeout to reach an image registry
+	registryProbeTimeout = 5 * time.Second
+)
+
+func init() {
+	RegisterPreCheck("registry", checkRegistries)
+	RegisterPreCheck("clock", func(types.NodePreCheckRequest) error { return checkClockSkew() })
+	RegisterPreCheck("cloud", func(types.NodePreCheckRequest) error { return checkCloudLatency() })
+}
+
+// imageRegistry returns the registry domain of the image, the first component of the image
+// is the registry only if it looks like a host, the same as docker does
+func imageRegistry(image string) string {
+	i := strings.IndexRune(image, '/')
+	if i == -1 {
+		return defaultRegistry
+	}
+	domain := image[:i]
+	if !strings.ContainsAny(domain, ".:") && domain != "localhost" {
+		return defaultRegistry
+	}
+	return domain
+}
+
+// checkRegistries checks the registries of the images used by the task are reachable
+// through the endpoints configured for the container runtime
+func checkRegistries(checkReq types.NodePreCheckRequest) error {
+	return probeRegistries(runtimeHostsDir(), checkReq.Images)
+}
+
+// probeRegistries checks the registries of the images are reachable, a registry is reachable if
+// any of its endpoints in the registry host configs in hostsDir is. Any http response but server
+// errors means the endpoint is reachable, because registries may require authorization.
+func probeRegistries(hostsDir string, images []string) error {
+	registries := map[string]bool{}
+	for _, image := range images {
+		registries[imageRegistry(image)] = true
+	}
+	if len(registries) == 0 {
+		registries[defaultRegistry] = true
+	}
+	names := make([]string, 0, len(registries))
+	for name := range registries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var unreachable []string
+	for _, name := range names {
+		if err := probeRegistry(hostsDir, name); err != nil {
+			unreachable = append(unreachable, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	if len(unreachable) > 0 {
+		return fmt.Errorf("image registries are unreachable: %s", strings.Join(unreachable, "; "))
+	}
+	return nil
+}
+
+// probeRegistry checks any endpoint of the registry is reachable
+func probeRegistry(hostsDir, registry string) error {
+	endpoints, err := imagemirror.RegistryEndpoints(hostsDir, registry)
+	if err != nil {
+		return err
+	}
+	var errs []string
+	for _, endpoint := range endpoints {
+		client := &nethttp.Client{
+			Timeout: registryProbeTimeout,
+			Transport: &nethttp.Transport{
+				Proxy: nethttp.ProxyFromEnvironment,
+				// #nosec G402 the certificate is not verified only if the container runtime does not verify it
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: endpoint.SkipVerify, MinVersion: tls.VersionTLS12},
+			},
+		}
+		resp, err := client.Get(endpoint.URL + "/v2/")
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= nethttp.StatusInternalServerError {
+			errs = append(errs, fmt.Sprintf("%s responds %s", endpoint.URL, resp.Status))
+			continue
+		}
+		return nil
+	}
+	return errors.New(strings.Join(errs, ", "))
+}
+
+// probeCloud sends a request to the https server of cloudcore, it returns the latency of
+// the request and the time of cloudcore when the response is sent
+func probeCloud() (time.Duration, time.Time, error) {
+	edgeHub := options.GetEdgeCoreConfig().Modules.EdgeHub
+	capem, err := os.ReadFile(edgeHub.TLSCAFile)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to read ca file: %v", err)
+	}
+	cert, err := tls.LoadX509KeyPair(edgeHub.TLSCertFile, edgeHub.TLSPrivateKeyFile)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to load edge certificate: %v", err)
+	}
+	client, err := http.NewHTTPClientWithCA(capem, cert)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	req, err := http.BuildRequest(nethttp.MethodGet, edgeHub.HTTPServer+constants.DefaultCAURL, nil, "", "")
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	start := time.Now()
+	resp, err := http.SendRequest(req, client)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to reach cloudcore: %v", err)
+	}
+	latency := time.Since(start)
+	defer resp.Body.Close()
+	cloudTime, err := nethttp.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return latency, time.Time{}, fmt.Errorf("failed to get the time of cloudcore: %v", err)
+	}
+	return latency, cloudTime, nil
+}
+
+// checkClockSkew checks the clock of the edge node is in sync with cloudcore, the certificates
+// and tokens issued by cloudcore are rejected by the edge node if the clocks are far apart
+func checkClockSkew() error {
+	latency, cloudTime, err := probeCloud()
+	if err != nil {
+		return err
+	}
+	// the time in the Date header is truncated to seconds and taken somewhere during the request
+	skew := time.Since(cloudTime) - latency/2
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > MaxClockSkew+time.Second {
+		return fmt.Errorf("current clock skew with cloudcore is %v, which exceeds the maximum allowed skew %v", skew, MaxClockSkew)
+	}
+	return nil
+}
+
+// checkCloudLatency checks the edge node can reach cloudcore in time
+func checkCloudLatency() error {
+	latency, _, err := probeCloud()
+	if err != nil {
+		return err
+	}
+	if latency > MaxCloudLatency {
+		return fmt.Errorf("current latency to cloudcore is %v, which exceeds the maximum allowed latency %v", latency, MaxCloudLatency)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+	v1 "k8s.io/api/core/v1"
+	internalapi "k8s.io/cri-api/pkg/apis"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+	kubeletlabels "k8s.io/kubelet/pkg/types"
+	"k8s.io/kubernetes/pkg/kubelet/cri/remote"
+	kubelettypes "k8s.io/kubernetes/pkg/kubelet/types"
+
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+)
+
+// criTimeout is the timeout of the requests to the container runtime
+const criTimeout = 10 * time.Second
+
+func init() {
+	RegisterPreCheck("runtime", func(types.NodePreCheckRequest) error { return checkRuntime() })
+	RegisterPreCheck("pods", func(types.NodePreCheckRequest) error { return checkCriticalPods() })
+}
+
+func newRuntimeService() (internalapi.RuntimeService, error) {
+	endpoint := options.GetEdgeCoreConfig().Modules.Edged.TailoredKubeletConfig.ContainerRuntimeEndpoint
+	return remote.NewRemoteRuntimeService(endpoint, criTimeout, oteltrace.NewNoopTracerProvider())
+}
+
+// checkRuntime checks the container runtime is ready to run containers
+func checkRuntime() error {
+	runtimeService, err := newRuntimeService()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), criTimeout)
+	defer cancel()
+	status, err := runtimeService.Status(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to get container runtime status: %v", err)
+	}
+	return runtimeConditionsReady(status.GetStatus().GetConditions())
+}
+
+// runtimeConditionsReady checks the required conditions of the container runtime are true
+func runtimeConditionsReady(conditions []*runtimeapi.RuntimeCondition) error {
+	reported := make(map[string]*runtimeapi.RuntimeCondition, len(conditions))
+	for _, condition := range conditions {
+		reported[condition.Type] = condition
+	}
+	var notReady []string
+	for _, required := range []string{runtimeapi.RuntimeReady, runtimeapi.NetworkReady} {
+		condition, ok := reported[required]
+		if !ok {
+			notReady = append(notReady, fmt.Sprintf("%s is not reported", required))
+			continue
+		}
+		if !condition.Status {
+			notReady = append(notReady, fmt.Sprintf("%s is false, reason: %s, message: %s", required, condition.Reason, condition.Message))
+		}
+	}
+	if len(notReady) > 0 {
+		return fmt.Errorf("container runtime is not ready: %s", strings.Join(notReady, "; "))
+	}
+	return nil
+}
+
+// checkCriticalPods checks the sandboxes of the critical pods on the node are ready,
+// so the task does not make the node lose the pods it depends on
+func checkCriticalPods() error {
+	metas, err := dao.QueryMeta("type", "pod")
+	if err != nil {
+		return fmt.Errorf("failed to query pods: %v", err)
+	}
+	var critical []*v1.Pod
+	for _, meta := range *metas {
+		var pod v1.Pod
+		if err := json.Unmarshal([]byte(meta), &pod); err != nil {
+			continue
+		}
+		if kubelettypes.IsCriticalPod(&pod) {
+			critical = append(critical, &pod)
+		}
+	}
+	if len(critical) == 0 {
+		return nil
+	}
+
+	runtimeService, err := newRuntimeService()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), criTimeout)
+	defer cancel()
+	sandboxes, err := runtimeService.ListPodSandbox(ctx, &runtimeapi.PodSandboxFilter{
+		State: &runtimeapi.PodSandboxStateValue{State: runtimeapi.PodSandboxState_SANDBOX_READY},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pod sandboxes: %v", err)
+	}
+	return criticalPodsReady(critical, sandboxes)
+}
+
+// criticalPodsReady checks each critical pod has a ready sandbox
+func criticalPodsReady(pods []*v1.Pod, sandboxes []*runtimeapi.PodSandbox) error {
+	ready := make(map[string]bool, len(sandboxes))
+	for _, sandbox := range sandboxes {
+		if sandbox.State == runtimeapi.PodSandboxState_SANDBOX_READY {
+			ready[sandbox.Labels[kubeletlabels.KubernetesPodUIDLabel]] = true
+		}
+	}
+	var notRunning []string
+	for _, pod := range pods {
+		if !ready[string(pod.UID)] {
+			notRunning = append(notRunning, pod.Namespace+"/"+pod.Name)
+		}
+	}
+	if len(notRunning) > 0 {
+		return fmt.Errorf("critical pods are not running: %s", strings.Join(notRunning, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	api "github.com/kubeedge/api/apis/fsm/v1alpha1"
+	"github.com/kubeedge/api/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+func TestPreCheck(t *testing.T) {
+	dir := t.TempDir()
+	oldDir := preCheckHookDir
+	preCheckHookDir = dir
+	defer func() { preCheckHookDir = oldDir }()
+	writeHook := func(name, script string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeHook("hook-pass", "exit 0\n")
+	writeHook("hook-fail", "echo mounted volume missing\nexit 1\n")
+
+	RegisterPreCheck("test-pass", func(types.NodePreCheckRequest) error { return nil })
+	RegisterPreCheck("test-fail", func(types.NodePreCheckRequest) error { return errors.New("not ready") })
+	defer func() {
+		delete(preChecks, "test-pass")
+		delete(preChecks, "test-fail")
+	}()
+
+	event := preCheck(types.NodeTaskRequest{Item: types.NodePreCheckRequest{
+		CheckItem: []string{"test-pass", "hook-pass", "unknown", "../hook-pass"},
+	}})
+	if event.Action != api.ActionSuccess {
+		t.Fatalf("expected pre-check to succeed, got %s: %s", event.Action, event.Msg)
+	}
+	var results []v1alpha1.CheckResult
+	if err := json.Unmarshal([]byte(event.ExternalMessage), &results); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{v1alpha1.CheckResultPassed, v1alpha1.CheckResultPassed, v1alpha1.CheckResultUnsupported, v1alpha1.CheckResultUnsupported}
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d check results, got %+v", len(expected), results)
+	}
+	for i, result := range results {
+		if result.Result != expected[i] {
+			t.Errorf("expected check %s %s, got %s", result.Name, expected[i], result.Result)
+		}
+	}
+
+	event = preCheck(types.NodeTaskRequest{Item: types.NodePreCheckRequest{
+		CheckItem: []string{"test-fail", "hook-fail", "test-pass"},
+	}})
+	if event.Action != api.ActionFailure {
+		t.Fatalf("expected pre-check to fail, got %s", event.Action)
+	}
+	var failures map[string]string
+	if err := json.Unmarshal([]byte(event.Msg), &failures); err != nil {
+		t.Fatal(err)
+	}
+	if failures["test-fail"] != "not ready" || !strings.Contains(failures["hook-fail"], "mounted volume missing") {
+		t.Errorf("expected the reasons of failed checks, got %v", failures)
+	}
+	if _, ok := failures["test-pass"]; ok {
+		t.Errorf("expected passed check not to be reported as failure, got %v", failures)
+	}
+}
+
+func TestImageRegistry(t *testing.T) {
+	tests := map[string]string{
+		"nginx":                         "docker.io",
+		"kubeedge/installation-package": "docker.io",
+		"localhost/nginx":               "localhost",
+		"registry:5000/org/nginx:1.0":   "registry:5000",
+		"quay.io/org/nginx":             "quay.io",
+	}
+	for image, expected := range tests {
+		if got := imageRegistry(image); got != expected {
+			t.Errorf("expected registry of %s %s, got %s", image, expected, got)
+		}
+	}
+}
+
+func TestProbeRegistries(t *testing.T) {
+	handler := func(status int) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(status) })
+	}
+	insecure := httptest.NewServer(handler(http.StatusUnauthorized))
+	defer insecure.Close()
+	selfSigned := httptest.NewTLSServer(handler(http.StatusOK))
+	defer selfSigned.Close()
+	broken := httptest.NewServer(handler(http.StatusServiceUnavailable))
+	defer broken.Close()
+
+	hostsDir := t.TempDir()
+	writeHosts := func(registry, config string) {
+		dir := filepath.Join(hostsDir, registry)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "hosts.toml"), []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// registries are named after the test servers, so the images resolve to them without hosts.toml
+	registryOf := func(server *httptest.Server) string {
+		return strings.TrimPrefix(strings.TrimPrefix(server.URL, "https://"), "http://")
+	}
+	writeHosts(registryOf(insecure), fmt.Sprintf("server = %q\n", insecure.URL))
+	writeHosts("skip-verify.local:5000", fmt.Sprintf("server = %q\nskip_verify = true\n", selfSigned.URL))
+	writeHosts("mirrored.local:5000", fmt.Sprintf("server = %q\n\n[host.%q]\n  skip_verify = true\n", broken.URL, selfSigned.URL))
+	writeHosts("broken.local:5000", fmt.Sprintf("server = %q\n", broken.URL))
+
+	tests := []struct {
+		name    string
+		image   string
+		wantErr bool
+	}{
+		{name: "http registry", image: registryOf(insecure) + "/nginx"},
+		{name: "registry skipping verification", image: "skip-verify.local:5000/nginx"},
+		{name: "mirror reachable", image: "mirrored.local:5000/nginx"},
+		{name: "server error", image: "broken.local:5000/nginx", wantErr: true},
+		{name: "untrusted certificate", image: registryOf(selfSigned) + "/nginx", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := probeRegistries(hostsDir, []string{tt.image})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("probeRegistries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRuntimeConditionsReady(t *testing.T) {
+	ready := []*runtimeapi.RuntimeCondition{
+		{Type: runtimeapi.RuntimeReady, Status: true},
+		{Type: runtimeapi.NetworkReady, Status: true},
+	}
+	if err := runtimeConditionsReady(ready); err != nil {
+		t.Errorf("expected runtime ready, got %v", err)
+	}
+	notReady := []*runtimeapi.RuntimeCondition{
+		{Type: runtimeapi.RuntimeReady, Status: true},
+		{Type: runtimeapi.NetworkReady, Status: false, Reason: "NetworkPluginNotReady"},
+	}
+	if err := runtimeConditionsReady(notReady); err == nil || !strings.Contains(err.Error(), "NetworkPluginNotReady") {
+		t.Errorf("expected network not ready, got %v", err)
+	}
+	if err := runtimeConditionsReady(nil); err == nil {
+		t.Error("expected runtime not ready without conditions")
+	}
+}
+
+func TestCriticalPodsReady(t *testing.T) {
+	pod := &v1.Pod{}
+	pod.Namespace, pod.Name, pod.UID = "kube-system", "edgemesh", "uid-1"
+	sandbox := &runtimeapi.PodSandbox{
+		State:  runtimeapi.PodSandboxState_SANDBOX_READY,
+		Labels: map[string]string{"io.kubernetes.pod.uid": "uid-1"},
+	}
+	if err := criticalPodsReady([]*v1.Pod{pod}, []*runtimeapi.PodSandbox{sandbox}); err != nil {
+		t.Errorf("expected critical pods ready, got %v", err)
+	}
+	if err := criticalPodsReady([]*v1.Pod{pod}, nil); err == nil || !strings.Contains(err.Error(), "kube-system/edgemesh") {
+		t.Errorf("expected critical pod not running, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/beehive/pkg/core"
+	"github.com/kubeedge/beehive/pkg/core/socket/extension"
+)
+
+// Register registers the extension modules configured, each extension module is a beehive
+// module whose logic runs in a separate process connected to the unix socket of the module.
+// The process uses the client of github.com/kubeedge/beehive/pkg/core/socket/extension,
+// e.g. it joins group "meta" to receive the resources sent from cloud,
+// and sends messages to module "websocket" to publish them to cloud through EdgeHub.
+func Register(c *v1alpha2.ExtensionModules) {
+	if c == nil || !c.Enable {
+		return
+	}
+	for _, m := range c.Modules {
+		if _, ok := core.GetModules()[m.Name]; ok {
+			klog.Errorf("extension module %s conflicts with a registered module, skip it", m.Name)
+			continue
+		}
+		core.Register(extension.NewModule(m.Name, m.Group, m.Address, int(c.BufferSize)))
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagemirror
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/distribution/reference"
+)
+
+const (
+	// HostsFile is the name of the registry host config file of containerd
+	HostsFile = "hosts.toml"
+	// mirrorProbeTimeout is the timeout to reach the image mirror on the seed node
+	mirrorProbeTimeout = 3 * time.Second
+	// managedHostsHeader is the first line of the registry host configs written by edgecore,
+	// the registry host configs without it are managed by the user and never overwritten
+	managedHostsHeader = "# Managed by the image mirror of edgecore, do not edit.\n"
+)
+
+// ErrUserManagedHosts is returned when the registry host config is managed by the user
+var ErrUserManagedHosts = errors.New("registry host config is managed by the user")
+
+// TLSFiles are the files of the edge certificate used to authenticate to the image mirrors
+type TLSFiles struct {
+	CAFile   string
+	CertFile string
+	KeyFile  string
+}
+
+// mirrorClient returns the client authenticating to the image mirrors with the edge certificate
+func mirrorClient(files TLSFiles) (*http.Client, error) {
+	cert, err := tls.LoadX509KeyPair(files.CertFile, files.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	caPEM, err := os.ReadFile(files.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificate found in %s", files.CAFile)
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				Certificates: []tls.Certificate{cert},
+				RootCAs:      pool,
+				MinVersion:   tls.VersionTLS12,
+			},
+		},
+		Timeout: mirrorProbeTimeout,
+	}, nil
+}
+
+// ProbeMirror checks the image mirror is reachable with the edge certificate
+func ProbeMirror(mirror string, files TLSFiles) error {
+	client, err := mirrorClient(files)
+	if err != nil {
+		return err
+	}
+	return probe(client, http.MethodGet, fmt.Sprintf("https://%s/v2/", mirror))
+}
+
+// ProbeImage checks the image mirror serves the image to the edge node, containerd pulls
+// the image from the registry if it does not
+func ProbeImage(mirror, image string, files TLSFiles) error {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return fmt.Errorf("invalid image %s: %v", image, err)
+	}
+	ref := "latest"
+	if canonical, ok := named.(reference.Canonical); ok {
+		ref = canonical.Digest().String()
+	} else if tagged, ok := named.(reference.Tagged); ok {
+		ref = tagged.Tag()
+	}
+	client, err := mirrorClient(files)
+	if err != nil {
+		return err
+	}
+	return probe(client, http.MethodHead, fmt.Sprintf("https://%s/v2/%s/manifests/%s?ns=%s",
+		mirror, reference.Path(named), ref, url.QueryEscape(reference.Domain(named))))
+}
+
+func probe(client *http.Client, method, target string) error {
+	req, err := http.NewRequest(method, target, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("image mirror responds %s", resp.Status)
+	}
+	return nil
+}
+
+// ConfigureRuntimeMirror configures containerd to pull the images of the registry from the mirror,
+// containerd falls back to the registry if the mirror fails or does not serve the image. It only takes
+// effect if config_path of the containerd cri registry config is hostsDir. The config is written only
+// if it changes, and atomically, so containerd never reads a partial config. ErrUserManagedHosts is
+// returned if the config of the registry is not written by edgecore, it is left as it is then.
+func ConfigureRuntimeMirror(hostsDir, registry, mirror string, files TLSFiles) error {
+	dir := filepath.Join(hostsDir, registry)
+	path := filepath.Join(dir, HostsFile)
+	config := []byte(runtimeHostsConfig(registry, mirror, files))
+	current, err := os.ReadFile(path)
+	switch {
+	case err == nil && bytes.Equal(current, config):
+		return nil
+	case err == nil && !bytes.HasPrefix(current, []byte(managedHostsHeader)):
+		return fmt.Errorf("%w: %s", ErrUserManagedHosts, path)
+	case err != nil && !os.IsNotExist(err):
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, HostsFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(config); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// RegistryEndpoint is an endpoint containerd pulls images from
+type RegistryEndpoint struct {
+	// URL is the endpoint with scheme, e.g. https://registry-1.docker.io
+	URL string
+	// SkipVerify is true if the certificate of the endpoint is not verified
+	SkipVerify bool
+}
+
+// hostsConfig is the registry host config of containerd
+type hostsConfig struct {
+	Server     string                `toml:"server"`
+	SkipVerify bool                  `toml:"skip_verify"`
+	Hosts      map[string]hostConfig `toml:"host"`
+}
+
+type hostConfig struct {
+	SkipVerify bool `toml:"skip_verify"`
+}
+
+// RegistryEndpoints returns the endpoints containerd pulls the images of the registry from, in the order
+// containerd tries them: the hosts of the registry host config in hostsDir and then the registry server
+func RegistryEndpoints(hostsDir, registry string) ([]RegistryEndpoint, error) {
+	server := registry
+	if registry == DefaultRegistry {
+		server = "registry-1.docker.io"
+	}
+	data, err := os.ReadFile(filepath.Join(hostsDir, registry, HostsFile))
+	if os.IsNotExist(err) {
+		return []RegistryEndpoint{{URL: "https://" + server}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var config hostsConfig
+	meta, err := toml.Decode(string(data), &config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s of registry %s: %v", HostsFile, registry, err)
+	}
+	var endpoints []RegistryEndpoint
+	// the hosts are tried in the order they are configured
+	for _, key := range meta.Keys() {
+		if len(key) != 2 || key[0] != "host" {
+			continue
+		}
+		endpoints = append(endpoints, RegistryEndpoint{URL: endpointURL(key[1]), SkipVerify: config.Hosts[key[1]].SkipVerify})
+	}
+	if config.Server != "" {
+		server = config.Server
+	}
+	return append(endpoints, RegistryEndpoint{URL: endpointURL(server), SkipVerify: config.SkipVerify}), nil
+}
+
+// endpointURL adds the default scheme https to the host of a registry host config
+func endpointURL(host string) string {
+	if strings.Contains(host, "://") {
+		return strings.TrimSuffix(host, "/")
+	}
+	return "https://" + strings.TrimSuffix(host, "/")
+}
+
+// runtimeHostsConfig returns the hosts.toml of the registry using the mirror,
+// containerd authenticates to the mirror with the edge certificate
+func runtimeHostsConfig(registry, mirror string, files TLSFiles) string {
+	server := registry
+	if registry == DefaultRegistry {
+		server = "registry-1.docker.io"
+	}
+	return managedHostsHeader + fmt.Sprintf(`server = "https://%s"
+
+[host."https://%s"]
+  capabilities = ["pull", "resolve"]
+  ca = %q
+  client = [[%q, %q]]
+`, server, mirror, files.CAFile, files.CertFile, files.KeyFile)
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagemirror
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/beehive/pkg/core"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/certificate"
+	"github.com/kubeedge/kubeedge/edge/pkg/imagemirror/config"
+)
+
+const (
+	// servingCertRetryInterval is the interval to retry applying for the serving certificate
+	servingCertRetryInterval = time.Minute
+)
+
+// imagemirror serves the images pulled through it to the other edge nodes of the node group,
+// so the node group pulls each image from the registry only once. It serves HTTPS with a certificate
+// signed by cloudcore, and only serves the edge nodes presenting their edge certificates.
+type imagemirror struct {
+	enable   bool
+	edgeHub  *v1alpha2.EdgeHub
+	nodeName string
+	nodeIP   string
+
+	servingCert atomic.Pointer[tls.Certificate]
+	clientCAs   caFile
+}
+
+// caFile caches the CA pool parsed from a CA file, the file is parsed again
+// only after it is replaced, e.g. by CA rotation
+type caFile struct {
+	lock    sync.Mutex
+	modTime time.Time
+	size    int64
+	pool    *x509.CertPool
+}
+
+// Pool returns the CA pool of the file, the pool is shared and must not be modified
+func (c *caFile) Pool(file string) (*x509.CertPool, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.pool != nil && info.ModTime().Equal(c.modTime) && info.Size() == c.size {
+		return c.pool, nil
+	}
+	caPEM, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificate found in %s", file)
+	}
+	c.modTime, c.size, c.pool = info.ModTime(), info.Size(), pool
+	return pool, nil
+}
+
+var _ core.Module = (*imagemirror)(nil)
+
+// running is the mirror served by the running imagemirror module
+var running atomic.Pointer[Mirror]
+
+// FlushCache removes the contents cached by the running image mirror and returns the number of them
+func FlushCache(ctx context.Context) (int, error) {
+	mirror := running.Load()
+	if mirror == nil {
+		return 0, errors.New("image mirror is not running")
+	}
+	return mirror.Flush(ctx)
+}
+
+// GrantImages makes the running image mirror serve the repositories of the images, see Mirror.GrantImages
+func GrantImages(g Grant, images []string) error {
+	mirror := running.Load()
+	if mirror == nil {
+		return errors.New("image mirror is not running")
+	}
+	return mirror.GrantImages(g, images)
+}
+
+// Release makes the running image mirror stop serving the repositories granted by the task
+func Release(task string) {
+	if mirror := running.Load(); mirror != nil {
+		mirror.Release(task)
+	}
+}
+
+func newImageMirror(enable bool, edgeHub *v1alpha2.EdgeHub, nodeName, nodeIP string) *imagemirror {
+	return &imagemirror{
+		enable:   enable,
+		edgeHub:  edgeHub,
+		nodeName: nodeName,
+		nodeIP:   nodeIP,
+	}
+}
+
+// Register register imagemirror
+func Register(m *v1alpha2.ImageMirror, edgeHub *v1alpha2.EdgeHub, nodeName, nodeIP string) {
+	if m == nil {
+		m = &v1alpha2.ImageMirror{}
+	}
+	config.InitConfigure(m)
+	core.Register(newImageMirror(m.Enable, edgeHub, nodeName, nodeIP))
+}
+
+func (*imagemirror) Name() string {
+	return modules.ImageMirrorModuleName
+}
+
+func (*imagemirror) Group() string {
+	return modules.ImageMirrorGroup
+}
+
+func (m *imagemirror) Enable() bool {
+	return m.enable
+}
+
+func (m *imagemirror) Start() {
+	address := config.Config.Address
+	if address == "" {
+		address = m.nodeIP
+	}
+	ip := net.ParseIP(address)
+	if ip == nil || m.edgeHub == nil {
+		klog.Errorf("image mirror is not started, the address %q is not a valid IP", address)
+		return
+	}
+
+	mirror, err := NewMirror(m.nodeName, config.Config.CacheDir)
+	if err != nil {
+		klog.Errorf("failed to create image mirror: %v", err)
+		return
+	}
+	certManager := certificate.NewCertManager(*m.edgeHub, m.nodeName)
+	// the edge certificate authenticating the application may not be applied by edgehub yet
+	expires, err := m.renewServingCert(&certManager, ip)
+	for err != nil {
+		klog.Warningf("failed to apply for the serving certificate of image mirror, retry in %s: %v", servingCertRetryInterval, err)
+		select {
+		case <-beehiveContext.Done():
+			return
+		case <-time.After(servingCertRetryInterval):
+		}
+		expires, err = m.renewServingCert(&certManager, ip)
+	}
+	go m.rotateServingCert(&certManager, ip, expires)
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(int(config.Config.Port))))
+	if err != nil {
+		klog.Errorf("image mirror is not started: %v", err)
+		return
+	}
+	running.Store(mirror)
+	defer running.Store(nil)
+	server := &http.Server{
+		Handler:           mirror,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-beehiveContext.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			klog.Warningf("failed to shutdown image mirror: %v", err)
+		}
+	}()
+	klog.Infof("image mirror listens on %s", listener.Addr())
+	if err := server.Serve(tls.NewListener(listener, m.tlsConfig(mirror))); err != nil && err != http.ErrServerClosed {
+		klog.Errorf("image mirror stopped: %v", err)
+	}
+}
+
+// tlsConfig requires the clients to present edge certificates signed by cloudcore, which are issued
+// for the nodes pulling images from the mirror and not revoked. The CA file is checked on every
+// handshake, so the CAs rotated by cloudcore are trusted without restart.
+func (m *imagemirror) tlsConfig(mirror *Mirror) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			pool, err := m.clientCAs.Pool(m.edgeHub.TLSCAFile)
+			if err != nil {
+				return nil, err
+			}
+			return &tls.Config{
+				MinVersion: tls.VersionTLS12,
+				ClientAuth: tls.RequireAndVerifyClientCert,
+				ClientCAs:  pool,
+				GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+					return m.servingCert.Load(), nil
+				},
+				VerifyPeerCertificate: func(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+					if len(verifiedChains) == 0 {
+						return errors.New("the client certificate is not verified")
+					}
+					return mirror.Authorize(verifiedChains[0][0])
+				},
+			}, nil
+		},
+	}
+}
+
+// renewServingCert applies for a new serving certificate and returns when it expires
+func (m *imagemirror) renewServingCert(certManager *certificate.CertManager, ip net.IP) (time.Time, error) {
+	certDER, keyDER, err := certManager.GetServingCert([]net.IP{ip})
+	if err != nil {
+		return time.Time{}, err
+	}
+	leaf, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return time.Time{}, err
+	}
+	key, err := x509.ParseECPrivateKey(keyDER)
+	if err != nil {
+		return time.Time{}, err
+	}
+	m.servingCert.Store(&tls.Certificate{
+		Certificate: [][]byte{certDER},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	})
+	return leaf.NotAfter, nil
+}
+
+// rotateServingCert renews the serving certificate when 80% of its lifetime passes
+func (m *imagemirror) rotateServingCert(certManager *certificate.CertManager, ip net.IP, expires time.Time) {
+	for {
+		notBefore := m.servingCert.Load().Leaf.NotBefore
+		wait := time.Until(notBefore.Add(expires.Sub(notBefore) * 4 / 5))
+		select {
+		case <-beehiveContext.Done():
+			return
+		case <-time.After(wait):
+		}
+		renewed, err := m.renewServingCert(certManager, ip)
+		for err != nil {
+			klog.Errorf("failed to renew the serving certificate of image mirror: %v", err)
+			select {
+			case <-beehiveContext.Done():
+				return
+			case <-time.After(servingCertRetryInterval):
+			}
+			renewed, err = m.renewServingCert(certManager, ip)
+		}
+		expires = renewed
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagemirror
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	certutil "k8s.io/client-go/util/cert"
+)
+
+func TestCAFilePool(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "rootCA.crt")
+	writeCA := func(host string, modTime time.Time) {
+		caPEM, _, err := certutil.GenerateSelfSignedCertKey(host, nil, nil)
+		if err != nil {
+			t.Fatalf("failed to generate CA: %v", err)
+		}
+		if err := os.WriteFile(file, caPEM, 0600); err != nil {
+			t.Fatalf("failed to write CA: %v", err)
+		}
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatalf("failed to set the modification time of CA: %v", err)
+		}
+	}
+	now := time.Now()
+	writeCA("ca", now.Add(-time.Hour))
+
+	var ca caFile
+	pool, err := ca.Pool(file)
+	if err != nil {
+		t.Fatalf("Pool() error = %v", err)
+	}
+	if again, _ := ca.Pool(file); again != pool {
+		t.Error("Pool() parsed the unchanged CA file again")
+	}
+
+	// CA rotation replaces the file
+	writeCA("new-ca", now)
+	if again, _ := ca.Pool(file); again == pool {
+		t.Error("Pool() returned the pool of the replaced CA file")
+	}
+
+	if err := os.WriteFile(file, []byte("invalid"), 0600); err != nil {
+		t.Fatalf("failed to write CA: %v", err)
+	}
+	if _, err := ca.Pool(file); err == nil {
+		t.Error("Pool() of invalid CA file expected error")
+	}
+}
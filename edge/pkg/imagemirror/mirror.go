@@ -0,0 +1,483 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagemirror
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/sync/singleflight"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/pkg/security/revocation"
+)
+
+const (
+	// DefaultRegistry is the upstream registry of the requests without the ns parameter
+	DefaultRegistry = "docker.io"
+	// TagCacheTTL is how long the digest a tag resolves to is cached, the tags of the upstream
+	// registry may be moved, so the mirror resolves them again after it
+	TagCacheTTL = time.Minute
+	// GrantTTL is how long the repositories granted to the mirror are served after they are granted,
+	// they are released when the task granting them finishes, or after GrantTTL if the mirror is not told
+	GrantTTL = time.Hour
+	// upstreamTimeout is the timeout to fetch a manifest or blob from the upstream registry
+	upstreamTimeout = 30 * time.Minute
+)
+
+// Credentials returns the username and password, or an empty username and the identity token,
+// of the registry host
+type Credentials func(host string) (string, string, error)
+
+// Mirror is a read-only pull-through registry mirror, it implements the pull part of the OCI distribution
+// API that containerd uses to pull images from registry mirrors. The contents fetched from the upstream
+// registries are cached by digest, so they are pulled from the upstream registry only once.
+//
+// The mirror only serves the repositories granted to it by the tasks pulling them, to the nodes of the
+// tasks, with the credentials of the pull secret of the tasks. A cached content is served for a repository only after the upstream registry confirms
+// the repository has it, so the contents of a private repository are not served for another repository.
+type Mirror struct {
+	// nodeName is the node of the mirror, it is served all the granted repositories
+	nodeName string
+	store    content.Store
+	// newResolver returns the resolver of the upstream registry with the credentials
+	newResolver func(creds Credentials) remotes.Resolver
+
+	// fetching deduplicates the concurrent fetches of the same content
+	fetching singleflight.Group
+
+	lock sync.Mutex
+	tags map[string]resolvedTag
+	// grants are the grants of the tasks by task name
+	grants map[string]grant
+	// verified records the contents the upstream registry confirms for the repositories, by repository@digest
+	verified map[string]bool
+}
+
+// Grant is the repositories of a task the mirror serves to the nodes of the task
+type Grant struct {
+	// Task is the name of the task pulling the images
+	Task string
+	// Clients are the nodes of the task pulling the images from the mirror
+	Clients []string
+	// RevokedAt are the times the certificates of the clients are revoked at,
+	// the certificates issued not after them are not served
+	RevokedAt map[string]time.Time
+	// Credentials fetch the images from the upstream registry, nil for public repositories
+	Credentials Credentials
+}
+
+type grant struct {
+	Grant
+	repositories map[string]bool
+	expires      time.Time
+}
+
+// serves returns an error if the certificate of the node is not served by the grant
+func (g grant) serves(node string, cert *x509.Certificate) error {
+	for _, client := range g.Clients {
+		if client != node {
+			continue
+		}
+		if revokedAt, ok := g.RevokedAt[node]; ok && !cert.NotBefore.After(revokedAt) {
+			return fmt.Errorf("certificate of node %s is revoked at %s", node, revokedAt)
+		}
+		return nil
+	}
+	return fmt.Errorf("node %s does not pull the images of task %s", node, g.Task)
+}
+
+type resolvedTag struct {
+	desc    ocispec.Descriptor
+	expires time.Time
+}
+
+// NewMirror returns the mirror of the node caching the contents in cacheDir
+func NewMirror(nodeName, cacheDir string) (*Mirror, error) {
+	store, err := local.NewStore(cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create content store in %s: %v", cacheDir, err)
+	}
+	return &Mirror{
+		nodeName: nodeName,
+		store:    store,
+		newResolver: func(creds Credentials) remotes.Resolver {
+			var opts []docker.AuthorizerOpt
+			if creds != nil {
+				opts = append(opts, docker.WithAuthCreds(creds))
+			}
+			return docker.NewResolver(docker.ResolverOptions{
+				Hosts: docker.ConfigureDefaultRegistries(docker.WithAuthorizer(docker.NewDockerAuthorizer(opts...))),
+			})
+		},
+		tags:     map[string]resolvedTag{},
+		grants:   map[string]grant{},
+		verified: map[string]bool{},
+	}, nil
+}
+
+// GrantImages makes the mirror serve the repositories of the images, e.g. docker.io/library/nginx,
+// to the clients of the grant until the task of the grant is released or for GrantTTL.
+// The images granted by the task before are replaced.
+func (m *Mirror) GrantImages(g Grant, images []string) error {
+	repositories := make(map[string]bool, len(images))
+	for _, image := range images {
+		named, err := reference.ParseNormalizedNamed(image)
+		if err != nil {
+			return fmt.Errorf("invalid image %s: %v", image, err)
+		}
+		repositories[named.Name()] = true
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.grants[g.Task] = grant{Grant: g, repositories: repositories, expires: time.Now().Add(GrantTTL)}
+	return nil
+}
+
+// Release stops serving the repositories granted by the task
+func (m *Mirror) Release(task string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.grants, task)
+}
+
+// Authorize returns an error if the node of the client certificate is not served any repository,
+// the node of the mirror is always served
+func (m *Mirror) Authorize(cert *x509.Certificate) error {
+	node := revocation.NodeNameOf(cert)
+	if node == "" {
+		return errors.New("the client certificate is not an edge certificate")
+	}
+	if node == m.nodeName {
+		return nil
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	err := fmt.Errorf("node %s pulls no images from the image mirror", node)
+	for task, g := range m.grants {
+		if time.Now().After(g.expires) {
+			delete(m.grants, task)
+			continue
+		}
+		if err = g.serves(node, cert); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// granted returns whether the repository is granted to the client certificate and the credentials of it
+func (m *Mirror) granted(repository string, cert *x509.Certificate) (Credentials, bool) {
+	node := revocation.NodeNameOf(cert)
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for task, g := range m.grants {
+		if time.Now().After(g.expires) {
+			delete(m.grants, task)
+			continue
+		}
+		if !g.repositories[repository] {
+			continue
+		}
+		if node != "" && (node == m.nodeName || g.serves(node, cert) == nil) {
+			return g.Credentials, true
+		}
+	}
+	return nil, false
+}
+
+// cached returns whether the content is cached and confirmed for the repository
+func (m *Mirror) cached(ctx context.Context, repository string, dgst digest.Digest) (content.Info, bool) {
+	m.lock.Lock()
+	verified := m.verified[repository+"@"+dgst.String()]
+	m.lock.Unlock()
+	if !verified {
+		return content.Info{}, false
+	}
+	info, err := m.store.Info(ctx, dgst)
+	return info, err == nil
+}
+
+// Flush removes all the cached contents and returns the number of them,
+// the contents are fetched from the upstream registries again when they are pulled
+func (m *Mirror) Flush(ctx context.Context) (int, error) {
+	m.lock.Lock()
+	m.tags = map[string]resolvedTag{}
+	m.verified = map[string]bool{}
+	m.lock.Unlock()
+
+	var digests []digest.Digest
+	if err := m.store.Walk(ctx, func(info content.Info) error {
+		digests = append(digests, info.Digest)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, dgst := range digests {
+		if err := m.store.Delete(ctx, dgst); err != nil && !errdefs.IsNotFound(err) {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// ServeHTTP serves
+//
+//	GET /v2/
+//	GET|HEAD /v2/<name>/manifests/<reference>
+//	GET|HEAD /v2/<name>/blobs/<digest>
+//
+// the upstream registry is specified by the ns query parameter, the same as containerd does.
+// The repositories not granted to the node of the client certificate are forbidden, containerd pulls them from the upstream registry then.
+func (m *Mirror) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "the image mirror is read-only", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+	if r.URL.Path == "/v2/" || r.URL.Path == "/v2" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	name, kind, ref, ok := parsePath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	registry := r.URL.Query().Get("ns")
+	if registry == "" {
+		registry = DefaultRegistry
+	}
+	repository := registry + "/" + name
+	var cert *x509.Certificate
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		cert = r.TLS.VerifiedChains[0][0]
+	}
+	if cert == nil {
+		http.Error(w, "the client certificate is required", http.StatusUnauthorized)
+		return
+	}
+	creds, ok := m.granted(repository, cert)
+	if !ok {
+		http.Error(w, fmt.Sprintf("repository %s is not pulled through the image mirror", repository), http.StatusForbidden)
+		return
+	}
+
+	var desc ocispec.Descriptor
+	var err error
+	if kind == "manifests" {
+		desc, err = m.resolveManifest(r.Context(), repository, ref, creds)
+	} else {
+		desc, err = m.fetchBlob(r.Context(), repository, ref, creds)
+	}
+	if err != nil {
+		klog.Warningf("image mirror failed to serve %s of %s: %v", ref, repository, err)
+		if errdefs.IsNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	m.serveContent(w, r, desc)
+}
+
+// parsePath splits /v2/<name>/<manifests|blobs>/<reference>, name may contain slashes
+func parsePath(path string) (string, string, string, bool) {
+	if !strings.HasPrefix(path, "/v2/") {
+		return "", "", "", false
+	}
+	path = strings.TrimPrefix(path, "/v2/")
+	for _, kind := range []string{"manifests", "blobs"} {
+		i := strings.LastIndex(path, "/"+kind+"/")
+		if i <= 0 {
+			continue
+		}
+		ref := path[i+len(kind)+2:]
+		if ref == "" || strings.Contains(ref, "/") {
+			return "", "", "", false
+		}
+		return path[:i], kind, ref, true
+	}
+	return "", "", "", false
+}
+
+// resolveManifest makes sure the manifest referenced by the tag or digest is cached
+func (m *Mirror) resolveManifest(ctx context.Context, repository, ref string, creds Credentials) (ocispec.Descriptor, error) {
+	if dgst, err := digest.Parse(ref); err == nil {
+		desc := ocispec.Descriptor{Digest: dgst}
+		if info, ok := m.cached(ctx, repository, dgst); ok {
+			desc.Size = info.Size
+			desc.MediaType, err = m.manifestMediaType(ctx, desc)
+			return desc, err
+		}
+		return m.fetch(ctx, repository, repository+"@"+dgst.String(), true, creds)
+	}
+
+	key := repository + ":" + ref
+	m.lock.Lock()
+	tag, ok := m.tags[key]
+	m.lock.Unlock()
+	if ok && time.Now().Before(tag.expires) {
+		if _, ok := m.cached(ctx, repository, tag.desc.Digest); ok {
+			return tag.desc, nil
+		}
+	}
+	desc, err := m.fetch(ctx, repository, key, true, creds)
+	if err != nil {
+		return desc, err
+	}
+	m.lock.Lock()
+	m.tags[key] = resolvedTag{desc: desc, expires: time.Now().Add(TagCacheTTL)}
+	m.lock.Unlock()
+	return desc, nil
+}
+
+// fetchBlob makes sure the blob is cached
+func (m *Mirror) fetchBlob(ctx context.Context, repository, ref string, creds Credentials) (ocispec.Descriptor, error) {
+	dgst, err := digest.Parse(ref)
+	if err != nil {
+		return ocispec.Descriptor{}, errdefs.ErrNotFound
+	}
+	desc := ocispec.Descriptor{MediaType: "application/octet-stream", Digest: dgst}
+	if info, ok := m.cached(ctx, repository, dgst); ok {
+		desc.Size = info.Size
+		return desc, nil
+	}
+	return m.fetch(ctx, repository, repository+"@"+dgst.String(), false, creds)
+}
+
+// fetch fetches the content from the upstream registry into the cache, the concurrent fetches of the
+// same reference share one upstream request. The content is resolved in the upstream registry even if
+// it is cached, which confirms the repository has it and the credentials are allowed to pull it.
+func (m *Mirror) fetch(ctx context.Context, repository, ref string, manifest bool, creds Credentials) (ocispec.Descriptor, error) {
+	v, err, _ := m.fetching.Do(ref, func() (interface{}, error) {
+		// the fetch is shared, so it should not be canceled by the request that starts it
+		ctx, cancel := context.WithTimeout(context.Background(), upstreamTimeout)
+		defer cancel()
+
+		// the size of the content is resolved before fetching, the fetcher reads at most the size
+		resolver := m.newResolver(creds)
+		_, desc, err := resolver.Resolve(ctx, ref)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		if !manifest {
+			// the media type selects the blobs endpoint to fetch from
+			desc.MediaType = "application/octet-stream"
+		}
+		m.lock.Lock()
+		m.verified[repository+"@"+desc.Digest.String()] = true
+		m.lock.Unlock()
+		if _, err := m.store.Info(ctx, desc.Digest); err == nil {
+			return desc, nil
+		}
+		fetcher, err := resolver.Fetcher(ctx, repository+"@"+desc.Digest.String())
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		rc, err := fetcher.Fetch(ctx, desc)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		defer rc.Close()
+		// the content is verified against the digest when it is committed to the store
+		if err := content.WriteBlob(ctx, m.store, desc.Digest.String(), rc, desc); err != nil && !errdefs.IsAlreadyExists(err) {
+			return ocispec.Descriptor{}, err
+		}
+		info, err := m.store.Info(ctx, desc.Digest)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		desc.Size = info.Size
+		klog.V(4).Infof("image mirror cached %s of %s", desc.Digest, repository)
+		return desc, nil
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return v.(ocispec.Descriptor), nil
+}
+
+// manifestMediaType detects the media type of the cached manifest from its content
+func (m *Mirror) manifestMediaType(ctx context.Context, desc ocispec.Descriptor) (string, error) {
+	data, err := content.ReadBlob(ctx, m.store, desc)
+	if err != nil {
+		return "", err
+	}
+	var manifest struct {
+		MediaType     string            `json:"mediaType"`
+		SchemaVersion int               `json:"schemaVersion"`
+		Manifests     []json.RawMessage `json:"manifests"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", fmt.Errorf("content %s is not a manifest: %v", desc.Digest, err)
+	}
+	switch {
+	case manifest.MediaType != "":
+		return manifest.MediaType, nil
+	case manifest.SchemaVersion == 1:
+		return images.MediaTypeDockerSchema1Manifest, nil
+	case manifest.Manifests != nil:
+		return ocispec.MediaTypeImageIndex, nil
+	default:
+		return ocispec.MediaTypeImageManifest, nil
+	}
+}
+
+func (m *Mirror) serveContent(w http.ResponseWriter, r *http.Request, desc ocispec.Descriptor) {
+	w.Header().Set("Content-Type", desc.MediaType)
+	w.Header().Set("Content-Length", strconv.FormatInt(desc.Size, 10))
+	w.Header().Set("Docker-Content-Digest", desc.Digest.String())
+	w.Header().Set("Etag", `"`+desc.Digest.String()+`"`)
+	if r.Method == http.MethodHead {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	ra, err := m.store.ReaderAt(r.Context(), desc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer ra.Close()
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, content.NewReader(ra)); err != nil {
+		klog.V(4).Infof("image mirror failed to send %s: %v", desc.Digest, err)
+	}
+}
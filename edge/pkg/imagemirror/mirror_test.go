@@ -0,0 +1,333 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagemirror
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestParsePath(t *testing.T) {
+	cases := []struct {
+		path, name, kind, ref string
+		ok                    bool
+	}{
+		{"/v2/library/nginx/manifests/latest", "library/nginx", "manifests", "latest", true},
+		{"/v2/a/b/c/blobs/sha256:abc", "a/b/c", "blobs", "sha256:abc", true},
+		{"/v2/nginx/manifests/", "", "", "", false},
+		{"/v2/manifests/latest", "", "", "", false},
+		{"/v1/nginx/manifests/latest", "", "", "", false},
+	}
+	for _, c := range cases {
+		name, kind, ref, ok := parsePath(c.path)
+		if ok != c.ok || name != c.name || kind != c.kind || ref != c.ref {
+			t.Errorf("parsePath(%q) = %q, %q, %q, %v", c.path, name, kind, ref, ok)
+		}
+	}
+}
+
+func TestMirrorPullThrough(t *testing.T) {
+	blob := []byte("layer content")
+	blobDigest := digest.FromBytes(blob)
+	manifest := []byte(`{"schemaVersion":2,"mediaType":"` + ocispec.MediaTypeImageManifest + `","layers":[{"digest":"` + blobDigest.String() + `"}]}`)
+	manifestDigest := digest.FromBytes(manifest)
+
+	var upstreamRequests int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&upstreamRequests, 1)
+		switch r.URL.Path {
+		case "/v2/library/app/manifests/v1", "/v2/library/app/manifests/" + manifestDigest.String():
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", manifestDigest.String())
+			if r.Method == http.MethodGet {
+				w.Write(manifest)
+			}
+		case "/v2/library/app/blobs/" + blobDigest.String():
+			w.Write(blob)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer upstream.Close()
+	registry := strings.TrimPrefix(upstream.URL, "http://")
+
+	m, err := NewMirror("edge-seed", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var withCreds int32
+	m.newResolver = func(creds Credentials) remotes.Resolver {
+		if creds != nil {
+			atomic.AddInt32(&withCreds, 1)
+		}
+		return docker.NewResolver(docker.ResolverOptions{
+			Hosts: docker.ConfigureDefaultRegistries(docker.WithPlainHTTP(docker.MatchAllHosts)),
+		})
+	}
+	// the client certificate is verified by the TLS listener of the image mirror
+	client := "edge-client"
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{nodeCert(client, time.Now())}}}
+		m.ServeHTTP(w, r)
+	}))
+	defer mirror.Close()
+
+	get := func(path string) *http.Response {
+		resp, err := http.Get(mirror.URL + path + "?ns=" + url.QueryEscape(registry))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	// the repositories not granted are forbidden
+	resp := get("/v2/library/app/manifests/v1")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected the repository not granted to be forbidden, got %d", resp.StatusCode)
+	}
+	grant := Grant{
+		Task:    "job",
+		Clients: []string{client},
+		Credentials: func(string) (string, string, error) {
+			return "user", "password", nil
+		},
+	}
+	if err := m.GrantImages(grant, []string{registry + "/library/app:v1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp = get("/v2/library/app/manifests/v1")
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != string(manifest) {
+		t.Fatalf("unexpected manifest response %d: %s", resp.StatusCode, body)
+	}
+	if resp.Header.Get("Docker-Content-Digest") != manifestDigest.String() || resp.Header.Get("Content-Type") != ocispec.MediaTypeImageManifest {
+		t.Errorf("unexpected manifest headers %v", resp.Header)
+	}
+
+	for i := 0; i < 2; i++ {
+		resp = get("/v2/library/app/blobs/" + blobDigest.String())
+		body, _ = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || string(body) != string(blob) {
+			t.Fatalf("unexpected blob response %d: %s", resp.StatusCode, body)
+		}
+	}
+
+	// the cached contents are served without the upstream registry
+	upstream.Close()
+	requests := atomic.LoadInt32(&upstreamRequests)
+	resp = get("/v2/library/app/manifests/v1")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected the resolved tag to be served from the cache, got %d", resp.StatusCode)
+	}
+	resp = get("/v2/library/app/manifests/" + manifestDigest.String())
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != ocispec.MediaTypeImageManifest {
+		t.Errorf("expected the manifest to be served from the cache, got %d %v", resp.StatusCode, resp.Header)
+	}
+	if atomic.LoadInt32(&upstreamRequests) != requests {
+		t.Errorf("expected no more requests to the upstream registry")
+	}
+	if atomic.LoadInt32(&withCreds) == 0 {
+		t.Errorf("expected the upstream registry to be requested with the granted credentials")
+	}
+
+	// the cached contents are not served for another repository until the upstream registry confirms them
+	if err := m.GrantImages(Grant{Task: "other", Clients: []string{client}}, []string{registry + "/other/app"}); err != nil {
+		t.Fatal(err)
+	}
+	resp = get("/v2/other/app/blobs/" + blobDigest.String())
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		t.Errorf("expected the blob cached for another repository not to be served")
+	}
+
+	resp = get("/v2/library/app/blobs/" + digest.FromString("missing").String())
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		t.Errorf("expected the missing blob to fail")
+	}
+
+	req, _ := http.NewRequest(http.MethodPut, mirror.URL+"/v2/library/app/manifests/v1", nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected the mirror to be read-only, got %d", resp.StatusCode)
+	}
+
+	// the repositories are not served after the task is released
+	m.Release("job")
+	resp = get("/v2/library/app/manifests/v1")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected the released repository to be forbidden, got %d", resp.StatusCode)
+	}
+	if err := m.GrantImages(grant, []string{registry + "/library/app:v1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the flushed contents are fetched from the upstream registry again
+	removed, err := m.Flush(context.Background())
+	if err != nil || removed != 2 {
+		t.Fatalf("expected 2 contents flushed, got %d: %v", removed, err)
+	}
+	resp = get("/v2/library/app/manifests/" + manifestDigest.String())
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		t.Errorf("expected the flushed manifest to be fetched from the closed upstream registry")
+	}
+}
+
+// nodeCert returns the edge certificate of the node issued at notBefore
+func nodeCert(node string, notBefore time.Time) *x509.Certificate {
+	return &x509.Certificate{Subject: pkix.Name{CommonName: "system:node:" + node}, NotBefore: notBefore}
+}
+
+func TestMirrorAuthorize(t *testing.T) {
+	m, err := NewMirror("edge-seed", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if err := m.Authorize(nodeCert("edge-seed", now)); err != nil {
+		t.Errorf("expected the node of the mirror to be authorized: %v", err)
+	}
+	if err := m.Authorize(nodeCert("edge-a", now)); err == nil {
+		t.Error("expected the node not pulling images from the mirror to be rejected")
+	}
+	if err := m.Authorize(&x509.Certificate{Subject: pkix.Name{CommonName: "admin"}}); err == nil {
+		t.Error("expected the certificate not issued for a node to be rejected")
+	}
+
+	err = m.GrantImages(Grant{
+		Task:      "job",
+		Clients:   []string{"edge-a", "edge-b"},
+		RevokedAt: map[string]time.Time{"edge-b": now},
+	}, []string{"nginx"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		cert       *x509.Certificate
+		authorized bool
+	}{
+		{cert: nodeCert("edge-a", now), authorized: true},
+		{cert: nodeCert("edge-c", now)},
+		// the certificates issued not after the revocation are rejected
+		{cert: nodeCert("edge-b", now)},
+		{cert: nodeCert("edge-b", now.Add(time.Second)), authorized: true},
+	}
+	for _, c := range cases {
+		if err := m.Authorize(c.cert); (err == nil) != c.authorized {
+			t.Errorf("Authorize(%s issued at %s) = %v, want authorized %v", c.cert.Subject.CommonName, c.cert.NotBefore, err, c.authorized)
+		}
+		_, granted := m.granted("docker.io/library/nginx", c.cert)
+		if granted != c.authorized {
+			t.Errorf("granted(%s issued at %s) = %v, want %v", c.cert.Subject.CommonName, c.cert.NotBefore, granted, c.authorized)
+		}
+	}
+
+	m.Release("job")
+	if err := m.Authorize(nodeCert("edge-a", now)); err == nil {
+		t.Error("expected the node to be rejected after the task is released")
+	}
+}
+
+func TestConfigureRuntimeMirror(t *testing.T) {
+	hostsDir := t.TempDir()
+	path := filepath.Join(hostsDir, DefaultRegistry, HostsFile)
+	files := TLSFiles{CAFile: "/etc/kubeedge/ca/rootCA.crt", CertFile: "/etc/kubeedge/certs/server.crt", KeyFile: "/etc/kubeedge/certs/server.key"}
+
+	if err := ConfigureRuntimeMirror(hostsDir, DefaultRegistry, "10.0.0.2:10553", files); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`server = "https://registry-1.docker.io"`,
+		`[host."https://10.0.0.2:10553"]`,
+		`ca = "/etc/kubeedge/ca/rootCA.crt"`,
+		`client = [["/etc/kubeedge/certs/server.crt", "/etc/kubeedge/certs/server.key"]]`,
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("expected %s in hosts config: %s", expected, data)
+		}
+	}
+
+	// the unchanged config is not written again
+	before, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ConfigureRuntimeMirror(hostsDir, DefaultRegistry, "10.0.0.2:10553", files); err != nil {
+		t.Fatal(err)
+	}
+	if after, err := os.Stat(path); err != nil || !os.SameFile(before, after) {
+		t.Errorf("expected the unchanged hosts config not to be rewritten: %v", err)
+	}
+
+	// the config follows the seed node
+	if err := ConfigureRuntimeMirror(hostsDir, DefaultRegistry, "10.0.0.3:10553", files); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), `[host."https://10.0.0.3:10553"]`) {
+		t.Errorf("expected the hosts config to use the new mirror, got %s", data)
+	}
+
+	// the hosts config of the user is not overwritten
+	userPath := filepath.Join(hostsDir, "quay.io", HostsFile)
+	userConfig := []byte("server = \"https://quay.io\"\n")
+	if err := os.MkdirAll(filepath.Dir(userPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(userPath, userConfig, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ConfigureRuntimeMirror(hostsDir, "quay.io", "10.0.0.3:10553", files); !errors.Is(err, ErrUserManagedHosts) {
+		t.Errorf("expected the hosts config of the user to be left, got %v", err)
+	}
+	if data, _ := os.ReadFile(userPath); string(data) != string(userConfig) {
+		t.Errorf("expected the hosts config of the user to be unchanged, got %s", data)
+	}
+}
@@ -0,0 +1,444 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bolt implements the storage backends of table meta and meta_v2 on bbolt, a pure-Go
+// embedded key/value store. The writes of the concurrent callers are batched into one transaction,
+// which reduces the lock contention and the write amplification of sqlite on the SD cards.
+package bolt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	v2 "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
+)
+
+var (
+	// metaBucket saves the metas of table meta by key
+	metaBucket = []byte(dao.MetaTableName)
+	// metaTypeBucket indexes the keys of table meta by type
+	metaTypeBucket = []byte("meta_type")
+	// metaV2Bucket saves the objs of table meta_v2 by key
+	metaV2Bucket = []byte(v2.NewMetaTableName)
+	// metaV2IndexBucket indexes the keys of table meta_v2 by gvr, namespace and name
+	metaV2IndexBucket = []byte("meta_v2_gvr")
+)
+
+// separator separates the fields of the index keys, it is not used in the fields
+const separator = "\x00"
+
+// DB is the bbolt database of the metadata
+type DB struct {
+	db *bolt.DB
+}
+
+// Open opens the bbolt database file, it is created if not exists
+func Open(path string) (*DB, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{
+		// the bbolt database is locked by one process, fail fast if edgecore is running
+		Timeout:        5 * time.Second,
+		NoFreelistSync: true,
+		FreelistType:   bolt.FreelistMapType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bbolt database %s: %v", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{metaBucket, metaTypeBucket, metaV2Bucket, metaV2IndexBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &DB{db: db}, nil
+}
+
+// Close closes the database
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// WriteTo writes a consistent snapshot of the database to w while it is in use
+func (d *DB) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	err := d.db.View(func(tx *bolt.Tx) error {
+		var err error
+		n, err = tx.WriteTo(w)
+		return err
+	})
+	return n, err
+}
+
+// MetaStore returns the storage backend of table meta
+func (d *DB) MetaStore() dao.MetaStore {
+	return &metaStore{db: d.db}
+}
+
+// MetaV2Store returns the storage backend of table meta_v2
+func (d *DB) MetaV2Store() v2.Store {
+	return &metaV2Store{db: d.db}
+}
+
+type metaStore struct {
+	db *bolt.DB
+}
+
+var _ dao.MetaStore = (*metaStore)(nil)
+
+func metaTypeKey(meta *dao.Meta) []byte {
+	return []byte(meta.Type + separator + meta.Key)
+}
+
+func getMeta(tx *bolt.Tx, key string) (*dao.Meta, error) {
+	data := tx.Bucket(metaBucket).Get([]byte(key))
+	if data == nil {
+		return nil, nil
+	}
+	meta := &dao.Meta{}
+	if err := json.Unmarshal(data, meta); err != nil {
+		return nil, fmt.Errorf("invalid meta %s: %v", key, err)
+	}
+	return meta, nil
+}
+
+func putMeta(tx *bolt.Tx, meta *dao.Meta) error {
+	old, err := getMeta(tx, meta.Key)
+	if err != nil {
+		return err
+	}
+	if old != nil && old.Type != meta.Type {
+		if err := tx.Bucket(metaTypeBucket).Delete(metaTypeKey(old)); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	if err := tx.Bucket(metaBucket).Put([]byte(meta.Key), data); err != nil {
+		return err
+	}
+	return tx.Bucket(metaTypeBucket).Put(metaTypeKey(meta), nil)
+}
+
+func deleteMeta(tx *bolt.Tx, meta *dao.Meta) error {
+	if err := tx.Bucket(metaTypeBucket).Delete(metaTypeKey(meta)); err != nil {
+		return err
+	}
+	return tx.Bucket(metaBucket).Delete([]byte(meta.Key))
+}
+
+func (s *metaStore) Insert(meta *dao.Meta) error {
+	return s.db.Batch(func(tx *bolt.Tx) error {
+		if tx.Bucket(metaBucket).Get([]byte(meta.Key)) != nil {
+			return nil
+		}
+		return putMeta(tx, meta)
+	})
+}
+
+func (s *metaStore) InsertOrUpdate(meta *dao.Meta) error {
+	return s.db.Batch(func(tx *bolt.Tx) error {
+		return putMeta(tx, meta)
+	})
+}
+
+func (s *metaStore) Update(meta *dao.Meta) error {
+	return s.db.Batch(func(tx *bolt.Tx) error {
+		if tx.Bucket(metaBucket).Get([]byte(meta.Key)) == nil {
+			return nil
+		}
+		return putMeta(tx, meta)
+	})
+}
+
+func (s *metaStore) UpdateFields(key string, cols map[string]interface{}) error {
+	return s.db.Batch(func(tx *bolt.Tx) error {
+		old, err := getMeta(tx, key)
+		if err != nil || old == nil {
+			return err
+		}
+		meta := *old
+		for col, value := range cols {
+			str, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("value of column %s must be string", col)
+			}
+			switch strings.ToLower(col) {
+			case "type":
+				meta.Type = str
+			case "value":
+				meta.Value = str
+			default:
+				return fmt.Errorf("column %s can not be updated", col)
+			}
+		}
+		return putMeta(tx, &meta)
+	})
+}
+
+func (s *metaStore) Delete(key string) error {
+	return s.db.Batch(func(tx *bolt.Tx) error {
+		meta, err := getMeta(tx, key)
+		if err != nil || meta == nil {
+			return err
+		}
+		return deleteMeta(tx, meta)
+	})
+}
+
+func (s *metaStore) DeleteByKeyAndPodUID(key, podUID string) (int64, error) {
+	var num int64
+	err := s.db.Batch(func(tx *bolt.Tx) error {
+		num = 0
+		meta, err := getMeta(tx, key)
+		if err != nil || meta == nil || !strings.Contains(meta.Value, podUID) {
+			return err
+		}
+		num = 1
+		return deleteMeta(tx, meta)
+	})
+	if err != nil {
+		klog.Errorf("delete pod by key %s and podUID %s failed, err: %v", key, podUID, err)
+		return 0, err
+	}
+	return num, nil
+}
+
+func (s *metaStore) Query(column, condition string) ([]dao.Meta, error) {
+	var metas []dao.Meta
+	err := s.db.View(func(tx *bolt.Tx) error {
+		switch strings.ToLower(column) {
+		case "key":
+			meta, err := getMeta(tx, condition)
+			if err != nil || meta == nil {
+				return err
+			}
+			metas = append(metas, *meta)
+			return nil
+		case "type":
+			prefix := []byte(condition + separator)
+			c := tx.Bucket(metaTypeBucket).Cursor()
+			for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+				meta, err := getMeta(tx, string(k[len(prefix):]))
+				if err != nil {
+					return err
+				}
+				if meta != nil {
+					metas = append(metas, *meta)
+				}
+			}
+			return nil
+		default:
+			return fmt.Errorf("meta can not be queried by column %s", column)
+		}
+	})
+	return metas, err
+}
+
+func (s *metaStore) List() ([]dao.Meta, error) {
+	var metas []dao.Meta
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(metaBucket).ForEach(func(k, data []byte) error {
+			var meta dao.Meta
+			if err := json.Unmarshal(data, &meta); err != nil {
+				return fmt.Errorf("invalid meta %s: %v", k, err)
+			}
+			metas = append(metas, meta)
+			return nil
+		})
+	})
+	return metas, err
+}
+
+type metaV2Store struct {
+	db *bolt.DB
+}
+
+var _ v2.Store = (*metaV2Store)(nil)
+
+func metaV2IndexKey(m *v2.MetaV2) []byte {
+	return []byte(strings.Join([]string{m.GroupVersionResource, m.Namespace, m.Name, m.Key}, separator))
+}
+
+func getMetaV2(tx *bolt.Tx, key string) (*v2.MetaV2, error) {
+	data := tx.Bucket(metaV2Bucket).Get([]byte(key))
+	if data == nil {
+		return nil, nil
+	}
+	m := &v2.MetaV2{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("invalid obj %s: %v", key, err)
+	}
+	return m, nil
+}
+
+func putMetaV2(tx *bolt.Tx, m *v2.MetaV2) error {
+	old, err := getMetaV2(tx, m.Key)
+	if err != nil {
+		return err
+	}
+	if old != nil {
+		if err := tx.Bucket(metaV2IndexBucket).Delete(metaV2IndexKey(old)); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err := tx.Bucket(metaV2Bucket).Put([]byte(m.Key), data); err != nil {
+		return err
+	}
+	return tx.Bucket(metaV2IndexBucket).Put(metaV2IndexKey(m), nil)
+}
+
+func (s *metaV2Store) InsertOrReplace(m *v2.MetaV2) error {
+	return s.db.Batch(func(tx *bolt.Tx) error {
+		return putMetaV2(tx, m)
+	})
+}
+
+func (s *metaV2Store) Get(key string) (*v2.MetaV2, error) {
+	var m *v2.MetaV2
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		m, err = getMetaV2(tx, key)
+		return err
+	})
+	return m, err
+}
+
+func (s *metaV2Store) List(gvr schema.GroupVersionResource, namespace string, name string) ([]v2.MetaV2, error) {
+	if namespace == v2.NullNamespace {
+		namespace = ""
+	}
+	if name == v2.NullName {
+		name = ""
+	}
+	var objs []v2.MetaV2
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if gvr.Empty() {
+			return tx.Bucket(metaV2Bucket).ForEach(func(k, data []byte) error {
+				var m v2.MetaV2
+				if err := json.Unmarshal(data, &m); err != nil {
+					return fmt.Errorf("invalid obj %s: %v", k, err)
+				}
+				objs = append(objs, m)
+				return nil
+			})
+		}
+
+		// the index is sorted by gvr, namespace and name, seek the longest prefix given
+		prefix := gvr.String() + separator
+		if namespace != "" {
+			prefix += namespace + separator
+			if name != "" {
+				prefix += name + separator
+			}
+		}
+		c := tx.Bucket(metaV2IndexBucket).Cursor()
+		for k, _ := c.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, _ = c.Next() {
+			fields := strings.SplitN(string(k), separator, 4)
+			if len(fields) != 4 || (name != "" && fields[2] != name) {
+				continue
+			}
+			m, err := getMetaV2(tx, fields[3])
+			if err != nil {
+				return err
+			}
+			if m != nil {
+				objs = append(objs, *m)
+			}
+		}
+		return nil
+	})
+	return objs, err
+}
+
+func (s *metaV2Store) Delete(key string) error {
+	return s.db.Batch(func(tx *bolt.Tx) error {
+		m, err := getMetaV2(tx, key)
+		if err != nil || m == nil {
+			return err
+		}
+		if err := tx.Bucket(metaV2IndexBucket).Delete(metaV2IndexKey(m)); err != nil {
+			return err
+		}
+		return tx.Bucket(metaV2Bucket).Delete([]byte(key))
+	})
+}
+
+func (s *metaV2Store) MaxResourceVersion() (uint64, error) {
+	var rv uint64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(metaV2Bucket).ForEach(func(k, data []byte) error {
+			var m v2.MetaV2
+			if err := json.Unmarshal(data, &m); err != nil {
+				return fmt.Errorf("invalid obj %s: %v", k, err)
+			}
+			if m.ResourceVersion > rv {
+				rv = m.ResourceVersion
+			}
+			return nil
+		})
+	})
+	return rv, err
+}
+
+// Migrate copies the metas and objs of the source stores, e.g. the sqlite stores, to the database,
+// the ones of the same keys in the database are replaced
+func (d *DB) Migrate(metas dao.MetaStore, objs v2.Store) (int, int, error) {
+	metaList, err := metas.List()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list table meta: %v", err)
+	}
+	objList, err := objs.List(schema.GroupVersionResource{}, "", "")
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list table meta_v2: %v", err)
+	}
+	err = d.db.Update(func(tx *bolt.Tx) error {
+		for i := range metaList {
+			if err := putMeta(tx, &metaList[i]); err != nil {
+				return err
+			}
+		}
+		for i := range objList {
+			if err := putMetaV2(tx, &objList[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return len(metaList), len(objList), nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bolt
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	v2 "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
+)
+
+func openDB(t *testing.T) *DB {
+	db, err := Open(filepath.Join(t.TempDir(), "meta.bolt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func queryKeys(t *testing.T, store dao.MetaStore, column, condition string) []string {
+	metas, err := store.Query(column, condition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, meta := range metas {
+		keys = append(keys, meta.Key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestMetaStore(t *testing.T) {
+	store := openDB(t).MetaStore()
+	for _, meta := range []dao.Meta{
+		{Key: "default/pod/a", Type: "pod", Value: `{"uid":"uid-a"}`},
+		{Key: "default/pod/b", Type: "pod", Value: `{"uid":"uid-b"}`},
+		{Key: "default/configmap/c", Type: "configmap", Value: "c"},
+	} {
+		meta := meta
+		if err := store.InsertOrUpdate(&meta); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// insert does not replace the existing meta
+	if err := store.Insert(&dao.Meta{Key: "default/configmap/c", Type: "configmap", Value: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	if metas, _ := store.Query("key", "default/configmap/c"); len(metas) != 1 || metas[0].Value != "c" {
+		t.Errorf("unexpected meta after insert %+v", metas)
+	}
+	if keys := queryKeys(t, store, "type", "pod"); len(keys) != 2 {
+		t.Errorf("unexpected pods %v", keys)
+	}
+
+	// the type index follows the type of the meta
+	if err := store.UpdateFields("default/pod/b", map[string]interface{}{"type": "podpatch"}); err != nil {
+		t.Fatal(err)
+	}
+	if keys := queryKeys(t, store, "type", "pod"); len(keys) != 1 || keys[0] != "default/pod/a" {
+		t.Errorf("unexpected pods after updating type %v", keys)
+	}
+	if keys := queryKeys(t, store, "type", "podpatch"); len(keys) != 1 {
+		t.Errorf("unexpected podpatches %v", keys)
+	}
+
+	// the pod is deleted only if the uid matches
+	if num, err := store.DeleteByKeyAndPodUID("default/pod/a", "uid-b"); err != nil || num != 0 {
+		t.Errorf("expected no pod deleted, got %d, %v", num, err)
+	}
+	if num, err := store.DeleteByKeyAndPodUID("default/pod/a", "uid-a"); err != nil || num != 1 {
+		t.Errorf("expected pod deleted, got %d, %v", num, err)
+	}
+	if err := store.Delete("default/configmap/c"); err != nil {
+		t.Fatal(err)
+	}
+	if keys := queryKeys(t, store, "type", "pod"); len(keys) != 0 {
+		t.Errorf("unexpected pods after deleting %v", keys)
+	}
+	if metas, err := store.List(); err != nil || len(metas) != 1 {
+		t.Errorf("unexpected metas %+v: %v", metas, err)
+	}
+	if _, err := store.Query("value", "c"); err == nil {
+		t.Error("expected query by value to be unsupported")
+	}
+}
+
+func TestMetaV2Store(t *testing.T) {
+	store := openDB(t).MetaV2Store()
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	for i, obj := range []v2.MetaV2{
+		{Key: "/core/v1/pods/default/a", GroupVersionResource: pods.String(), Namespace: "default", Name: "a"},
+		{Key: "/core/v1/pods/kube-system/a", GroupVersionResource: pods.String(), Namespace: "kube-system", Name: "a"},
+		{Key: "/core/v1/pods/default/ab", GroupVersionResource: pods.String(), Namespace: "default", Name: "ab"},
+		{Key: "/core/v1/secrets/default/s", GroupVersionResource: secrets.String(), Namespace: "default", Name: "s"},
+	} {
+		obj := obj
+		obj.ResourceVersion = uint64(i + 10)
+		if err := store.InsertOrReplace(&obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		namespace, name string
+		expected        int
+	}{
+		{v2.NullNamespace, v2.NullName, 3},
+		{"default", "", 2},
+		{"default", "a", 1},
+		{"", "a", 2},
+		{"other", "", 0},
+	}
+	for _, c := range cases {
+		objs, err := store.List(pods, c.namespace, c.name)
+		if err != nil || len(objs) != c.expected {
+			t.Errorf("expected %d pods of %q/%q, got %d: %v", c.expected, c.namespace, c.name, len(objs), err)
+		}
+	}
+	if objs, err := store.List(schema.GroupVersionResource{}, "", ""); err != nil || len(objs) != 4 {
+		t.Errorf("expected all objs, got %d: %v", len(objs), err)
+	}
+	if rv, err := store.MaxResourceVersion(); err != nil || rv != 13 {
+		t.Errorf("unexpected max resource version %d: %v", rv, err)
+	}
+
+	if err := store.Delete("/core/v1/pods/default/a"); err != nil {
+		t.Fatal(err)
+	}
+	if obj, err := store.Get("/core/v1/pods/default/a"); err != nil || obj != nil {
+		t.Errorf("expected obj to be deleted, got %+v: %v", obj, err)
+	}
+	if objs, _ := store.List(pods, "default", ""); len(objs) != 1 {
+		t.Errorf("expected deleted obj to be removed from index, got %+v", objs)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	src := openDB(t)
+	if err := src.MetaStore().InsertOrUpdate(&dao.Meta{Key: "default/pod/a", Type: "pod", Value: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.MetaV2Store().InsertOrReplace(&v2.MetaV2{Key: "/core/v1/pods/default/a", GroupVersionResource: "/v1, Resource=pods"}); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := openDB(t)
+	metas, objs, err := dst.Migrate(src.MetaStore(), src.MetaV2Store())
+	if err != nil || metas != 1 || objs != 1 {
+		t.Fatalf("unexpected migration %d, %d: %v", metas, objs, err)
+	}
+	if keys := queryKeys(t, dst.MetaStore(), "type", "pod"); len(keys) != 1 {
+		t.Errorf("unexpected migrated pods %v", keys)
+	}
+	if obj, err := dst.MetaV2Store().Get("/core/v1/pods/default/a"); err != nil || obj == nil {
+		t.Errorf("expected obj to be migrated: %v", err)
+	}
+}
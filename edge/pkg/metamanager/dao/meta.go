@@ -11,7 +11,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/kubeedge/common/constants"
-	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/encryption"
 )
 
 // constant metatable name reference
@@ -28,8 +28,11 @@ type Meta struct {
 
 // SaveMeta save meta to db
 func SaveMeta(meta *Meta) error {
-	num, err := dbm.DBAccess.Insert(meta)
-	klog.V(4).Infof("Insert affected Num: %d, %v", num, err)
+	meta, err := encryptMeta(meta)
+	if err != nil {
+		return err
+	}
+	err = metaStore.Insert(meta)
 	if err == nil || IsNonUniqueNameError(err) {
 		return nil
 	}
@@ -48,74 +51,113 @@ func IsNonUniqueNameError(err error) bool {
 
 // DeleteMetaByKey delete meta by key
 func DeleteMetaByKey(key string) error {
-	num, err := dbm.DBAccess.QueryTable(MetaTableName).Filter("key", key).Delete()
-	klog.V(4).Infof("Delete affected Num: %d, %v", num, err)
-	return err
+	return metaStore.Delete(key)
 }
 
 // DeleteMetaByKeyAndPodUID delete meta by key and podUID
 func DeleteMetaByKeyAndPodUID(key, podUID string) (int64, error) {
-	sqlStr := fmt.Sprintf("DELETE FROM meta WHERE key = '%s' and value LIKE '%%%s%%'", key, podUID)
-	res, err := dbm.DBAccess.Raw(sqlStr).Exec()
-	if err != nil {
-		klog.Errorf("delete pod by key %s and podUID %s failed, err: %v", key, podUID, err)
-		return 0, err
-	}
-	return res.RowsAffected()
+	return metaStore.DeleteByKeyAndPodUID(key, podUID)
 }
 
 // UpdateMeta update meta
 func UpdateMeta(meta *Meta) error {
-	num, err := dbm.DBAccess.Update(meta) // will update all field
-	klog.V(4).Infof("Update affected Num: %d, %v", num, err)
-	return err
+	meta, err := encryptMeta(meta)
+	if err != nil {
+		return err
+	}
+	return metaStore.Update(meta)
 }
 
 // InsertOrUpdate insert or update meta
 func InsertOrUpdate(meta *Meta) error {
-	_, err := dbm.DBAccess.Raw("INSERT OR REPLACE INTO meta (key, type, value) VALUES (?,?,?)", meta.Key, meta.Type, meta.Value).Exec() // will update all field
-	klog.V(4).Infof("Update result %v", err)
-	return err
+	meta, err := encryptMeta(meta)
+	if err != nil {
+		return err
+	}
+	return metaStore.InsertOrUpdate(meta)
 }
 
 // UpdateMetaField update special field
 func UpdateMetaField(key string, col string, value interface{}) error {
-	num, err := dbm.DBAccess.QueryTable(MetaTableName).Filter("key", key).Update(map[string]interface{}{col: value})
-	klog.V(4).Infof("Update affected Num: %d, %v", num, err)
-	return err
+	return metaStore.UpdateFields(key, map[string]interface{}{col: value})
 }
 
 // UpdateMetaFields update special fields
 func UpdateMetaFields(key string, cols map[string]interface{}) error {
-	num, err := dbm.DBAccess.QueryTable(MetaTableName).Filter("key", key).Update(cols)
-	klog.V(4).Infof("Update affected Num: %d, %v", num, err)
-	return err
+	return metaStore.UpdateFields(key, cols)
 }
 
 // QueryMeta return only meta's value, if no error, Meta not null
 func QueryMeta(key string, condition string) (*[]string, error) {
-	meta := new([]Meta)
-	_, err := dbm.DBAccess.QueryTable(MetaTableName).Filter(key, condition).All(meta)
+	meta, err := metaStore.Query(key, condition)
 	if err != nil {
 		return nil, err
 	}
 
 	var result []string
-	for _, v := range *meta {
-		result = append(result, v.Value)
+	for _, v := range meta {
+		value, err := encryption.Decrypt(v.Key, v.Value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
 	}
 	return &result, nil
 }
 
 // QueryAllMeta return all meta, if no error, Meta not null
 func QueryAllMeta(key string, condition string) (*[]Meta, error) {
-	meta := new([]Meta)
-	_, err := dbm.DBAccess.QueryTable(MetaTableName).Filter(key, condition).All(meta)
+	meta, err := metaStore.Query(key, condition)
 	if err != nil {
 		return nil, err
 	}
+	for i := range meta {
+		if meta[i].Value, err = encryption.Decrypt(meta[i].Key, meta[i].Value); err != nil {
+			return nil, err
+		}
+	}
+
+	return &meta, nil
+}
+
+// encryptMeta returns the copy of meta with the value encrypted if its type is sensitive
+func encryptMeta(meta *Meta) (*Meta, error) {
+	value, err := encryption.Encrypt(meta.Type, meta.Key, meta.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt meta %s: %v", meta.Key, err)
+	}
+	if value == meta.Value {
+		return meta, nil
+	}
+	encrypted := *meta
+	encrypted.Value = value
+	return &encrypted, nil
+}
 
-	return meta, nil
+// MigrateEncryption encrypts the values of the sensitive types and decrypts the others in table meta,
+// the values encrypted with the previous key encryption keys are re-encrypted with the current one
+func MigrateEncryption() error {
+	metas, err := metaStore.List()
+	if err != nil {
+		return err
+	}
+	var migrated int
+	for _, meta := range metas {
+		value, changed, err := encryption.Migrate(meta.Type, "", meta.Key, meta.Value)
+		if err != nil {
+			return fmt.Errorf("failed to migrate meta %s: %v", meta.Key, err)
+		}
+		if !changed {
+			continue
+		}
+		meta.Value = value
+		if err := metaStore.InsertOrUpdate(&meta); err != nil {
+			return err
+		}
+		migrated++
+	}
+	klog.Infof("%d rows of table meta are migrated for encryption", migrated)
+	return nil
 }
 
 // SaveMQTTMeta saves mqtt container data in sqlites
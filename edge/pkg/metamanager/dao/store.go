@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dao
+
+import (
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
+)
+
+// MetaStore is the storage backend of table meta, the values are saved as they are given
+type MetaStore interface {
+	// Insert inserts the meta, the sqlite store returns the unique constraint error if the key exists,
+	// which is ignored by SaveMeta, and the other stores do nothing
+	Insert(meta *Meta) error
+	// InsertOrUpdate inserts the meta or replaces the meta of the key
+	InsertOrUpdate(meta *Meta) error
+	// Update replaces the meta of the key if it exists
+	Update(meta *Meta) error
+	// UpdateFields updates the columns of the meta of the key
+	UpdateFields(key string, cols map[string]interface{}) error
+	// Delete deletes the meta of the key
+	Delete(key string) error
+	// DeleteByKeyAndPodUID deletes the meta of the key if its value contains the pod uid
+	DeleteByKeyAndPodUID(key, podUID string) (int64, error)
+	// Query returns the metas whose column, key or type, equals to the condition
+	Query(column, condition string) ([]Meta, error)
+	// List returns all the metas
+	List() ([]Meta, error)
+}
+
+var metaStore MetaStore = NewSQLiteMetaStore()
+
+// NewSQLiteMetaStore returns the storage backend of table meta in the sqlite database, it is the default backend
+func NewSQLiteMetaStore() MetaStore {
+	return &sqliteMetaStore{}
+}
+
+// SetMetaStore sets the storage backend of table meta, it must be called before the modules start
+func SetMetaStore(store MetaStore) {
+	metaStore = store
+}
+
+// sqliteMetaStore saves table meta in the sqlite database by beego orm
+type sqliteMetaStore struct{}
+
+func (*sqliteMetaStore) Insert(meta *Meta) error {
+	num, err := dbm.DBAccess.Insert(meta)
+	klog.V(4).Infof("Insert affected Num: %d, %v", num, err)
+	return err
+}
+
+func (*sqliteMetaStore) InsertOrUpdate(meta *Meta) error {
+	_, err := dbm.DBAccess.Raw("INSERT OR REPLACE INTO meta (key, type, value) VALUES (?,?,?)", meta.Key, meta.Type, meta.Value).Exec() // will update all field
+	klog.V(4).Infof("Update result %v", err)
+	return err
+}
+
+func (*sqliteMetaStore) Update(meta *Meta) error {
+	num, err := dbm.DBAccess.Update(meta) // will update all field
+	klog.V(4).Infof("Update affected Num: %d, %v", num, err)
+	return err
+}
+
+func (*sqliteMetaStore) UpdateFields(key string, cols map[string]interface{}) error {
+	num, err := dbm.DBAccess.QueryTable(MetaTableName).Filter("key", key).Update(cols)
+	klog.V(4).Infof("Update affected Num: %d, %v", num, err)
+	return err
+}
+
+func (*sqliteMetaStore) Delete(key string) error {
+	num, err := dbm.DBAccess.QueryTable(MetaTableName).Filter("key", key).Delete()
+	klog.V(4).Infof("Delete affected Num: %d, %v", num, err)
+	return err
+}
+
+func (*sqliteMetaStore) DeleteByKeyAndPodUID(key, podUID string) (int64, error) {
+	sqlStr := fmt.Sprintf("DELETE FROM meta WHERE key = '%s' and value LIKE '%%%s%%'", key, podUID)
+	res, err := dbm.DBAccess.Raw(sqlStr).Exec()
+	if err != nil {
+		klog.Errorf("delete pod by key %s and podUID %s failed, err: %v", key, podUID, err)
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (*sqliteMetaStore) Query(column, condition string) ([]Meta, error) {
+	meta := new([]Meta)
+	_, err := dbm.DBAccess.QueryTable(MetaTableName).Filter(column, condition).All(meta)
+	if err != nil {
+		return nil, err
+	}
+	return *meta, nil
+}
+
+func (*sqliteMetaStore) List() ([]Meta, error) {
+	meta := new([]Meta)
+	_, err := dbm.DBAccess.QueryTable(MetaTableName).All(meta)
+	if err != nil {
+		return nil, err
+	}
+	return *meta, nil
+}
@@ -1,10 +1,14 @@
 package v2
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/beego/beego/v2/client/orm"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
 
-	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/encryption"
 )
 
 // constant metatable name reference
@@ -48,36 +52,11 @@ type MetaV2 struct {
 
 // List a slice of raw data by Group Version Resource Namespace Name
 func RawMetaByGVRNN(gvr schema.GroupVersionResource, namespace string, name string) (*[]MetaV2, error) {
-	objs := new([]MetaV2)
-	var err error
-	// TODO: use getCondition
-	//cond := getCondition(gvr,namespace,name)
-	//klog.Infof("cond:%+v",cond)
-	//_,err = dbm.DBAccess.QueryTable(NewMetaTableName).SetCond(cond).All(objs)
-	if gvr.Empty() {
-		_, err = dbm.DBAccess.QueryTable(NewMetaTableName).All(objs)
-	} else {
-		switch namespace {
-		case NullNamespace, "":
-			switch name {
-			case NullName, "":
-				_, err = dbm.DBAccess.QueryTable(NewMetaTableName).Filter(GVR, gvr.String()).All(objs)
-			default:
-				_, err = dbm.DBAccess.QueryTable(NewMetaTableName).Filter(GVR, gvr.String()).Filter(NAME, name).All(objs)
-			}
-		default:
-			switch name {
-			case NullName, "":
-				_, err = dbm.DBAccess.QueryTable(NewMetaTableName).Filter(GVR, gvr.String()).Filter(NS, namespace).All(objs)
-			default:
-				_, err = dbm.DBAccess.QueryTable(NewMetaTableName).Filter(GVR, gvr.String()).Filter(NS, namespace).Filter(NAME, name).All(objs)
-			}
-		}
-	}
+	objs, err := store.List(gvr, namespace, name)
 	if err != nil {
 		return nil, err
 	}
-	return objs, nil
+	return &objs, nil
 }
 
 func getCondition(gvr schema.GroupVersionResource, namespace string, name string) *orm.Condition {
@@ -91,3 +70,38 @@ func getCondition(gvr schema.GroupVersionResource, namespace string, name string
 	}
 	return cond
 }
+
+// MigrateEncryption encrypts the values of the sensitive resources and decrypts the others in table meta_v2,
+// the values encrypted with the previous key encryption keys are re-encrypted with the current one
+func MigrateEncryption() error {
+	objs, err := store.List(schema.GroupVersionResource{}, "", "")
+	if err != nil {
+		return err
+	}
+	var migrated int
+	for _, obj := range objs {
+		value, changed, err := encryption.Migrate("", resourceOfGVR(obj.GroupVersionResource), obj.Key, obj.Value)
+		if err != nil {
+			return fmt.Errorf("failed to migrate meta_v2 %s: %v", obj.Key, err)
+		}
+		if !changed {
+			continue
+		}
+		obj.Value = value
+		if err := store.InsertOrReplace(&obj); err != nil {
+			return err
+		}
+		migrated++
+	}
+	klog.Infof("%d rows of table meta_v2 are migrated for encryption", migrated)
+	return nil
+}
+
+// resourceOfGVR returns the resource of the GroupVersionResource column like "/v1, Resource=secrets"
+func resourceOfGVR(gvr string) string {
+	_, resource, found := strings.Cut(gvr, "Resource=")
+	if !found {
+		return ""
+	}
+	return resource
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
+)
+
+// Store is the storage backend of table meta_v2 the imitator storage is based on
+type Store interface {
+	// InsertOrReplace inserts the obj or replaces the obj of the key
+	InsertOrReplace(m *MetaV2) error
+	// Get returns the obj of the key, or nil if it does not exist
+	Get(key string) (*MetaV2, error)
+	// List returns the objs of the gvr, namespace and name, the empty or null namespace and name match all
+	List(gvr schema.GroupVersionResource, namespace string, name string) ([]MetaV2, error)
+	// Delete deletes the obj of the key
+	Delete(key string) error
+	// MaxResourceVersion returns the biggest resource version of the objs
+	MaxResourceVersion() (uint64, error)
+}
+
+var store Store = NewSQLiteStore()
+
+// NewSQLiteStore returns the storage backend of table meta_v2 in the sqlite database, it is the default backend
+func NewSQLiteStore() Store {
+	return &sqliteStore{}
+}
+
+// SetStore sets the storage backend of table meta_v2, it must be called before the modules start
+func SetStore(s Store) {
+	store = s
+}
+
+// GetStore returns the storage backend of table meta_v2
+func GetStore() Store {
+	return store
+}
+
+// sqliteStore saves table meta_v2 in the sqlite database by beego orm
+type sqliteStore struct{}
+
+func (*sqliteStore) InsertOrReplace(m *MetaV2) error {
+	_, err := dbm.DBAccess.Raw("INSERT OR REPLACE INTO meta_v2 (key, groupversionresource, namespace,name,resourceversion,value) VALUES (?,?,?,?,?,?)", m.Key, m.GroupVersionResource, m.Namespace, m.Name, m.ResourceVersion, m.Value).Exec()
+	return err
+}
+
+func (*sqliteStore) Get(key string) (*MetaV2, error) {
+	results := new([]MetaV2)
+	_, err := dbm.DBAccess.QueryTable(NewMetaTableName).Filter(KEY, key).All(results)
+	if err != nil || len(*results) == 0 {
+		return nil, err
+	}
+	return &(*results)[0], nil
+}
+
+func (*sqliteStore) List(gvr schema.GroupVersionResource, namespace string, name string) ([]MetaV2, error) {
+	objs := new([]MetaV2)
+	var err error
+	// TODO: use getCondition
+	//cond := getCondition(gvr,namespace,name)
+	//klog.Infof("cond:%+v",cond)
+	//_,err = dbm.DBAccess.QueryTable(NewMetaTableName).SetCond(cond).All(objs)
+	if gvr.Empty() {
+		_, err = dbm.DBAccess.QueryTable(NewMetaTableName).All(objs)
+	} else {
+		switch namespace {
+		case NullNamespace, "":
+			switch name {
+			case NullName, "":
+				_, err = dbm.DBAccess.QueryTable(NewMetaTableName).Filter(GVR, gvr.String()).All(objs)
+			default:
+				_, err = dbm.DBAccess.QueryTable(NewMetaTableName).Filter(GVR, gvr.String()).Filter(NAME, name).All(objs)
+			}
+		default:
+			switch name {
+			case NullName, "":
+				_, err = dbm.DBAccess.QueryTable(NewMetaTableName).Filter(GVR, gvr.String()).Filter(NS, namespace).All(objs)
+			default:
+				_, err = dbm.DBAccess.QueryTable(NewMetaTableName).Filter(GVR, gvr.String()).Filter(NS, namespace).Filter(NAME, name).All(objs)
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return *objs, nil
+}
+
+func (*sqliteStore) Delete(key string) error {
+	_, err := dbm.DBAccess.Delete(&MetaV2{Key: key})
+	return err
+}
+
+func (*sqliteStore) MaxResourceVersion() (uint64, error) {
+	m := new(MetaV2)
+	// get the most recent record
+	_, err := dbm.DBAccess.QueryTable(NewMetaTableName).OrderBy("-" + RV).Limit(1).All(m)
+	return m.ResourceVersion, err
+}
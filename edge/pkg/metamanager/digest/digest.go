@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package digest reports the digests of the objects cached in the database to cloud periodically,
+// cloud compares them with the cluster and fixes the objects drifted, e.g. the ones whose
+// delete events were missed
+package digest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/common/constants"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	connect "github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
+	messagepkg "github.com/kubeedge/kubeedge/edge/pkg/common/message"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	metaManagerConfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/config"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+)
+
+// Status is the state of the drift detection
+type Status struct {
+	LastSentTime time.Time
+	// LastFull indicates whether the last digest sent lists the objects
+	LastFull       bool
+	LastResultTime time.Time
+	LastResult     *commontypes.MetaDigestResult
+}
+
+type reporter struct {
+	resources []string
+	period    time.Duration
+	fullCh    chan struct{}
+
+	lock sync.Mutex
+	// sentChecksums are the checksums of the last full digest sent
+	sentChecksums map[string]string
+	status        Status
+}
+
+// running is the reporter of the running metamanager
+var running atomic.Pointer[reporter]
+
+// Run sends the digests to cloud periodically until beehive is stopped, only the checksums
+// are sent if the objects are not changed since the last full digest
+func Run(c *v1alpha2.MetaDriftDetection) {
+	r := &reporter{
+		resources: c.Resources,
+		period:    time.Duration(c.Period) * time.Second,
+		fullCh:    make(chan struct{}, 1),
+	}
+	running.Store(r)
+	defer running.Store(nil)
+
+	ticker := time.NewTicker(r.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-beehiveContext.Done():
+			klog.Info("stop sending digests")
+			return
+		case <-ticker.C:
+			r.send(false)
+		case <-r.fullCh:
+			r.send(true)
+		}
+	}
+}
+
+// RequestFull asks the running reporter to send the full digest to cloud right now
+func RequestFull() error {
+	r := running.Load()
+	if r == nil {
+		return errors.New("drift detection is not enabled")
+	}
+	if !connect.IsConnected() {
+		return errors.New("edgehub is not connected to cloud")
+	}
+	select {
+	case r.fullCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// HandleResult handles the result of the digest sent by cloud
+func HandleResult(message model.Message) {
+	r := running.Load()
+	if r == nil {
+		return
+	}
+	data, err := message.GetContentData()
+	if err != nil {
+		klog.Errorf("failed to get content of digest result %s: %v", message.GetID(), err)
+		return
+	}
+	var result commontypes.MetaDigestResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		klog.Errorf("failed to unmarshal digest result %s: %v", message.GetID(), err)
+		return
+	}
+
+	r.lock.Lock()
+	r.status.LastResultTime = time.Now()
+	r.status.LastResult = &result
+	r.lock.Unlock()
+
+	if result.Missing+result.Stale+result.Extra > 0 {
+		klog.Infof("cloud fixed the drifted objects, missing: %d, stale: %d, extra: %d", result.Missing, result.Stale, result.Extra)
+	}
+	if result.FullRequired {
+		if err := RequestFull(); err != nil {
+			klog.Warningf("failed to send full digest: %v", err)
+		}
+	}
+}
+
+// GetStatus returns the state of the drift detection, false if it is not enabled
+func GetStatus() (Status, bool) {
+	r := running.Load()
+	if r == nil {
+		return Status{}, false
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.status, true
+}
+
+func (r *reporter) send(full bool) {
+	if !connect.IsConnected() {
+		return
+	}
+	digests, err := Build(r.resources)
+	if err != nil {
+		klog.Errorf("failed to build digests: %v", err)
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	// the objects changed since the last full digest are sent
+	for resourceType, d := range digests {
+		if r.sentChecksums[resourceType] != d.Checksum {
+			full = true
+		}
+	}
+	checksums := make(map[string]string, len(digests))
+	for resourceType, d := range digests {
+		checksums[resourceType] = d.Checksum
+		if !full {
+			d.Objects = nil
+			digests[resourceType] = d
+		}
+	}
+
+	message := model.NewMessage("").
+		BuildRouter(modules.MetaManagerModuleName, messagepkg.ResourceGroupName, constants.ResourceTypeDigest, model.UpdateOperation).
+		FillBody(commontypes.MetaDigest{Digests: digests, Full: full})
+	beehiveContext.SendToGroup(string(metaManagerConfig.Config.ContextSendGroup), *message)
+
+	if full {
+		r.sentChecksums = checksums
+	}
+	r.status.LastSentTime = time.Now()
+	r.status.LastFull = full
+}
+
+// Build digests the objects of the resource types in the database
+func Build(resources []string) (map[string]commontypes.ResourceDigest, error) {
+	digests := make(map[string]commontypes.ResourceDigest, len(resources))
+	for _, resourceType := range resources {
+		metas, err := dao.QueryAllMeta("type", resourceType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s: %v", resourceType, err)
+		}
+		objects := make([]commontypes.ObjectDigest, 0, len(*metas))
+		for _, meta := range *metas {
+			o, err := digestObject(meta)
+			if err != nil {
+				klog.Warningf("failed to digest %s: %v", meta.Key, err)
+				continue
+			}
+			objects = append(objects, o)
+		}
+		digests[resourceType] = commontypes.ResourceDigest{
+			Checksum: commontypes.DigestChecksum(objects),
+			Objects:  objects,
+		}
+	}
+	return digests, nil
+}
+
+// digestObject reads the version of the object, the key of which is <namespace>/<restype>/<name>
+func digestObject(meta dao.Meta) (commontypes.ObjectDigest, error) {
+	var object struct {
+		metav1.ObjectMeta `json:"metadata"`
+	}
+	if err := json.Unmarshal([]byte(meta.Value), &object); err != nil {
+		return commontypes.ObjectDigest{}, err
+	}
+	o := commontypes.ObjectDigest{
+		Namespace:       object.Namespace,
+		Name:            object.Name,
+		UID:             string(object.UID),
+		ResourceVersion: object.ResourceVersion,
+	}
+	if tokens := strings.Split(meta.Key, constants.ResourceSep); len(tokens) == 3 {
+		o.Namespace, o.Name = tokens[0], tokens[2]
+	}
+	if o.Namespace == "" || o.Name == "" {
+		return o, fmt.Errorf("bad key %s", meta.Key)
+	}
+	return o, nil
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package digest
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/beehive/pkg/common"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/common/constants"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	connect "github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	metaManagerConfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/config"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+)
+
+// fakeMetaStore returns the metas of the type
+type fakeMetaStore struct {
+	dao.MetaStore
+	metas []dao.Meta
+}
+
+func (s *fakeMetaStore) Query(column, condition string) ([]dao.Meta, error) {
+	var metas []dao.Meta
+	for _, meta := range s.metas {
+		if column == "type" && meta.Type == condition {
+			metas = append(metas, meta)
+		}
+	}
+	return metas, nil
+}
+
+func newMeta(key, value string) dao.Meta {
+	return dao.Meta{Key: key, Type: "configmap", Value: value}
+}
+
+func TestBuild(t *testing.T) {
+	store := &fakeMetaStore{metas: []dao.Meta{
+		newMeta("default/configmap/a", `{"metadata":{"name":"a","namespace":"default","uid":"uid-a","resourceVersion":"2"}}`),
+		newMeta("default/configmap/b", `{"metadata":{"uid":"uid-b","resourceVersion":"3"}}`),
+		newMeta("default/configmap/c", `not json`),
+	}}
+	dao.SetMetaStore(store)
+	defer dao.SetMetaStore(dao.NewSQLiteMetaStore())
+
+	digests, err := Build([]string{"configmap", "secret"})
+	if err != nil {
+		t.Fatalf("failed to build digests: %v", err)
+	}
+	expected := []commontypes.ObjectDigest{
+		{Namespace: "default", Name: "a", UID: "uid-a", ResourceVersion: "2"},
+		{Namespace: "default", Name: "b", UID: "uid-b", ResourceVersion: "3"},
+	}
+	got := digests["configmap"]
+	if len(got.Objects) != len(expected) || got.Objects[0] != expected[0] || got.Objects[1] != expected[1] {
+		t.Errorf("expected objects %+v, got %+v", expected, got.Objects)
+	}
+	if got.Checksum != commontypes.DigestChecksum(expected) {
+		t.Errorf("unexpected checksum %s", got.Checksum)
+	}
+	if secrets := digests["secret"]; len(secrets.Objects) != 0 || secrets.Checksum != commontypes.DigestChecksum(nil) {
+		t.Errorf("unexpected secret digest %+v", secrets)
+	}
+}
+
+func TestReporter(t *testing.T) {
+	beehiveContext.InitContext([]string{common.MsgCtxTypeChannel})
+	beehiveContext.AddModule(&common.ModuleInfo{
+		ModuleName: modules.EdgeHubModuleName,
+		ModuleType: common.MsgCtxTypeChannel,
+	})
+	beehiveContext.AddModuleGroup(modules.EdgeHubModuleName, modules.HubGroup)
+	metaManagerConfig.Config.ContextSendGroup = modules.HubGroup
+
+	store := &fakeMetaStore{metas: []dao.Meta{
+		newMeta("default/configmap/a", `{"metadata":{"uid":"uid-a","resourceVersion":"2"}}`),
+	}}
+	dao.SetMetaStore(store)
+	defer dao.SetMetaStore(dao.NewSQLiteMetaStore())
+
+	if err := RequestFull(); err == nil {
+		t.Fatalf("expected error when drift detection is not running")
+	}
+	go Run(&v1alpha2.MetaDriftDetection{Enable: true, Period: 3600, Resources: []string{"configmap"}})
+	for running.Load() == nil {
+		time.Sleep(10 * time.Millisecond)
+	}
+	r := running.Load()
+	connect.SetConnected(true)
+	defer connect.SetConnected(false)
+
+	receiveDigest := func() commontypes.MetaDigest {
+		msg, err := beehiveContext.Receive(modules.EdgeHubModuleName)
+		if err != nil {
+			t.Fatalf("failed to receive digest: %v", err)
+		}
+		if msg.GetResource() != constants.ResourceTypeDigest {
+			t.Fatalf("unexpected message resource %s", msg.GetResource())
+		}
+		var digest commontypes.MetaDigest
+		data, _ := msg.GetContentData()
+		if err := json.Unmarshal(data, &digest); err != nil {
+			t.Fatalf("failed to unmarshal digest: %v", err)
+		}
+		return digest
+	}
+
+	// the first digest is full since nothing is sent before
+	r.send(false)
+	if digest := receiveDigest(); !digest.Full || len(digest.Digests["configmap"].Objects) != 1 {
+		t.Errorf("expected full digest, got %+v", digest)
+	}
+	// only the checksums are sent if the objects are not changed
+	r.send(false)
+	if digest := receiveDigest(); digest.Full || len(digest.Digests["configmap"].Objects) != 0 {
+		t.Errorf("expected checksums only, got %+v", digest)
+	}
+
+	// cloud asks for the full digest
+	result := model.NewMessage("").BuildRouter("synccontroller", "resource", constants.ResourceTypeDigest, model.UpdateOperation).
+		FillBody(commontypes.MetaDigestResult{FullRequired: true})
+	HandleResult(*result)
+	if digest := receiveDigest(); !digest.Full {
+		t.Errorf("expected full digest after cloud required, got %+v", digest)
+	}
+	if status, ok := GetStatus(); !ok || status.LastResult == nil || !status.LastResult.FullRequired || !status.LastFull {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+const (
+	// encryptedPrefix is the prefix of the encrypted values in the database
+	encryptedPrefix = "enc:v1:"
+	// maxDEKUses is the number of the values a data encryption key encrypts before it is rotated
+	maxDEKUses = 1 << 16
+	// maxCachedDEKs is the number of the decrypted data encryption keys cached
+	maxCachedDEKs = 1024
+)
+
+// envelope is the encrypted value and its data encryption key encrypted by the key provider
+type envelope struct {
+	Provider    string            `json:"provider"`
+	KeyID       string            `json:"keyID"`
+	Key         []byte            `json:"key"`
+	Annotations map[string][]byte `json:"annotations,omitempty"`
+	Data        []byte            `json:"data"`
+}
+
+// Encryptor encrypts the values with the envelope encryption, each value is encrypted by
+// AES-256-GCM with a data encryption key which is encrypted by the key provider
+type Encryptor struct {
+	provider KeyProvider
+	// keyID is the id of the current key encryption key of the provider
+	keyID     string
+	resources sets.Set[string]
+
+	lock    sync.Mutex
+	aead    cipher.AEAD
+	wrapped *WrappedKey
+	uses    int
+	// cache caches the data encryption keys decrypted by the provider
+	cache map[string]cipher.AEAD
+}
+
+// NewEncryptor returns the encryptor encrypting the resources by the key provider
+func NewEncryptor(provider KeyProvider, resources []string) (*Encryptor, error) {
+	keyID, err := provider.KeyID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &Encryptor{
+		provider:  provider,
+		keyID:     keyID,
+		resources: sets.New[string](resources...),
+		cache:     make(map[string]cipher.AEAD),
+	}, nil
+}
+
+// Sensitive returns whether the values of the resource type saved in table meta are encrypted
+func (e *Encryptor) Sensitive(resType string) bool {
+	return e.resources.Has(resType)
+}
+
+// SensitiveResource returns whether the values of the resource saved in table meta_v2 are encrypted,
+// the resources are the plural of the resource types, e.g. secrets for secret
+func (e *Encryptor) SensitiveResource(resource string) bool {
+	for resType := range e.resources {
+		if resource == resType+"s" || resource == resType+"es" {
+			return true
+		}
+	}
+	return false
+}
+
+// Encrypt encrypts the value saved with the key, the key is authenticated so the
+// encrypted value can not be moved to the other keys
+func (e *Encryptor) Encrypt(key, value string) (string, error) {
+	aead, wrapped, err := e.dataKey()
+	if err != nil {
+		return "", err
+	}
+	data, err := seal(aead, []byte(value), []byte(key))
+	if err != nil {
+		return "", err
+	}
+	env, err := json.Marshal(envelope{
+		Provider:    e.provider.Name(),
+		KeyID:       wrapped.KeyID,
+		Key:         wrapped.Ciphertext,
+		Annotations: wrapped.Annotations,
+		Data:        data,
+	})
+	if err != nil {
+		return "", err
+	}
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(env), nil
+}
+
+// Decrypt decrypts the value saved with the key, the values not encrypted are returned directly
+func (e *Encryptor) Decrypt(key, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	env, err := parseEnvelope(value)
+	if err != nil {
+		return "", err
+	}
+	if env.Provider != e.provider.Name() {
+		return "", fmt.Errorf("value of %s is encrypted by key provider %s, but %s is used", key, env.Provider, e.provider.Name())
+	}
+	aead, err := e.cachedKey(env)
+	if err != nil {
+		return "", err
+	}
+	data, err := open(aead, env.Data, []byte(key))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value of %s: %v", key, err)
+	}
+	return string(data), nil
+}
+
+// dataKey returns the current data encryption key, it is rotated after encrypting maxDEKUses values
+func (e *Encryptor) dataKey() (cipher.AEAD, *WrappedKey, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.aead != nil && e.uses < maxDEKUses {
+		e.uses++
+		return e.aead, e.wrapped, nil
+	}
+
+	dek := make([]byte, keySize)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, nil, err
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return nil, nil, err
+	}
+	wrapped, err := e.provider.WrapKey(context.Background(), dek)
+	if err != nil {
+		return nil, nil, err
+	}
+	e.aead, e.wrapped, e.uses = aead, wrapped, 1
+	return aead, wrapped, nil
+}
+
+func (e *Encryptor) cachedKey(env *envelope) (cipher.AEAD, error) {
+	cacheKey := env.KeyID + "/" + string(env.Key)
+	e.lock.Lock()
+	aead, ok := e.cache[cacheKey]
+	e.lock.Unlock()
+	if ok {
+		return aead, nil
+	}
+
+	dek, err := e.provider.UnwrapKey(context.Background(), &WrappedKey{
+		Ciphertext:  env.Key,
+		KeyID:       env.KeyID,
+		Annotations: env.Annotations,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if aead, err = newAEAD(dek); err != nil {
+		return nil, err
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if len(e.cache) >= maxCachedDEKs {
+		e.cache = make(map[string]cipher.AEAD)
+	}
+	e.cache[cacheKey] = aead
+	return aead, nil
+}
+
+// Migrate returns the value of the key in the form it is expected to be saved and whether it
+// is changed: the sensitive values are encrypted with the current key encryption key, and the others are decrypted
+func (e *Encryptor) Migrate(sensitive bool, key, value string) (string, bool, error) {
+	if !IsEncrypted(value) {
+		if !sensitive {
+			return value, false, nil
+		}
+		encrypted, err := e.Encrypt(key, value)
+		return encrypted, err == nil, err
+	}
+	env, err := parseEnvelope(value)
+	if err != nil {
+		return "", false, err
+	}
+	if sensitive && env.Provider == e.provider.Name() && env.KeyID == e.keyID {
+		return value, false, nil
+	}
+	plaintext, err := e.Decrypt(key, value)
+	if err != nil || !sensitive {
+		return plaintext, err == nil, err
+	}
+	encrypted, err := e.Encrypt(key, plaintext)
+	return encrypted, err == nil, err
+}
+
+// IsEncrypted returns whether the value is encrypted
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+func parseEnvelope(value string) (*envelope, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid encrypted value: %v", err)
+	}
+	env := &envelope{}
+	if err := json.Unmarshal(data, env); err != nil {
+		return nil, fmt.Errorf("invalid encrypted value: %v", err)
+	}
+	return env, nil
+}
+
+// encryptor is the encryptor of the database, it is nil if the encryption is not configured
+var encryptor *Encryptor
+
+// Init initializes the encryptor of the database. When the encryption is disabled but its key
+// is still available, the encryptor is initialized without resources to decrypt the values encrypted before
+func Init(cfg *v1alpha2.MetaEncryption) error {
+	if cfg == nil {
+		return nil
+	}
+	resources := cfg.Resources
+	if !cfg.Enable {
+		resources = nil
+		if !keyAvailable(cfg) {
+			return nil
+		}
+	}
+
+	var provider KeyProvider
+	var err error
+	switch cfg.KeyProvider {
+	case v1alpha2.MetaEncryptionProviderLocal:
+		provider, err = NewLocalKeyProvider(cfg.KeyFile)
+	case v1alpha2.MetaEncryptionProviderSealed:
+		provider, err = NewSealedKeyProvider(cfg.KeyFile, cfg.AllowMachineIDOnly)
+	case v1alpha2.MetaEncryptionProviderKMS:
+		provider, err = NewKMSKeyProvider(cfg.KMSEndpoint, time.Duration(cfg.KMSTimeout)*time.Second)
+	default:
+		err = fmt.Errorf("unsupported key provider %s", cfg.KeyProvider)
+	}
+	if err != nil {
+		return err
+	}
+	if encryptor, err = NewEncryptor(provider, resources); err != nil {
+		return err
+	}
+	klog.Infof("database encryption is initialized with key provider %s, resources %v", provider.Name(), resources)
+	return nil
+}
+
+func keyAvailable(cfg *v1alpha2.MetaEncryption) bool {
+	if cfg.KeyProvider == v1alpha2.MetaEncryptionProviderKMS {
+		return cfg.KMSEndpoint != ""
+	}
+	_, err := os.Stat(cfg.KeyFile)
+	return err == nil
+}
+
+// Encrypt encrypts the value of the resource type saved with the key in table meta if it is sensitive
+func Encrypt(resType, key, value string) (string, error) {
+	if encryptor == nil || !encryptor.Sensitive(resType) {
+		return value, nil
+	}
+	return encryptor.Encrypt(key, value)
+}
+
+// EncryptResource encrypts the value of the resource saved with the key in table meta_v2 if it is sensitive
+func EncryptResource(resource, key, value string) (string, error) {
+	if encryptor == nil || !encryptor.SensitiveResource(resource) {
+		return value, nil
+	}
+	return encryptor.Encrypt(key, value)
+}
+
+// Decrypt decrypts the value saved with the key, the values not encrypted are returned directly
+func Decrypt(key, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if encryptor == nil {
+		return "", errors.New("value is encrypted but the database encryption is not configured")
+	}
+	return encryptor.Decrypt(key, value)
+}
+
+// Migrate returns the value of the key in the form it is expected to be saved and whether it is changed,
+// resType is the resource type of table meta and resource is the resource of table meta_v2
+func Migrate(resType, resource, key, value string) (string, bool, error) {
+	if encryptor == nil {
+		if IsEncrypted(value) {
+			return "", false, errors.New("value is encrypted but the database encryption is not configured")
+		}
+		return value, false, nil
+	}
+	sensitive := encryptor.Sensitive(resType) || encryptor.SensitiveResource(resource)
+	return encryptor.Migrate(sensitive, key, value)
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	kmsapi "k8s.io/kms/apis/v2"
+)
+
+func newLocalEncryptor(t *testing.T, keyFile string, resources ...string) *Encryptor {
+	provider, err := NewLocalKeyProvider(keyFile)
+	if err != nil {
+		t.Fatalf("failed to create local key provider: %v", err)
+	}
+	e, err := NewEncryptor(provider, resources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "encryption", "key")
+	e := newLocalEncryptor(t, keyFile, "secret")
+	info, err := os.Stat(keyFile)
+	if err != nil {
+		t.Fatalf("expected key file to be generated: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("unexpected mode %v of key file", info.Mode())
+	}
+
+	value := `{"data":{"password":"cGFzc3dvcmQ="}}`
+	encrypted, err := e.Encrypt("default/secret/db", value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(encrypted) || strings.Contains(encrypted, "cGFzc3dvcmQ=") {
+		t.Fatalf("value is not encrypted: %s", encrypted)
+	}
+	if decrypted, err := e.Decrypt("default/secret/db", encrypted); err != nil || decrypted != value {
+		t.Errorf("unexpected decrypted value %q: %v", decrypted, err)
+	}
+	// the encrypted value is bound to its key
+	if _, err := e.Decrypt("default/secret/other", encrypted); err == nil {
+		t.Error("expected the value moved to another key to be rejected")
+	}
+	// the values saved before the encryption are returned directly
+	if decrypted, err := e.Decrypt("default/pod/nginx", "{}"); err != nil || decrypted != "{}" {
+		t.Errorf("unexpected plaintext value %q: %v", decrypted, err)
+	}
+
+	// the key is loaded from the key file after restart
+	restarted := newLocalEncryptor(t, keyFile, "secret")
+	if decrypted, err := restarted.Decrypt("default/secret/db", encrypted); err != nil || decrypted != value {
+		t.Errorf("unexpected decrypted value %q after restart: %v", decrypted, err)
+	}
+	// the key of another node can not decrypt the value
+	other := newLocalEncryptor(t, filepath.Join(t.TempDir(), "key"), "secret")
+	if _, err := other.Decrypt("default/secret/db", encrypted); err == nil {
+		t.Error("expected the value to be rejected with another key")
+	}
+}
+
+func TestSensitive(t *testing.T) {
+	e := newLocalEncryptor(t, filepath.Join(t.TempDir(), "key"), "secret", "serviceaccounttoken")
+	for resType, expected := range map[string]bool{"secret": true, "serviceaccounttoken": true, "configmap": false} {
+		if e.Sensitive(resType) != expected {
+			t.Errorf("expected sensitive of %s to be %v", resType, expected)
+		}
+	}
+	for resource, expected := range map[string]bool{"secrets": true, "configmaps": false, "secret": false} {
+		if e.SensitiveResource(resource) != expected {
+			t.Errorf("expected sensitive of resource %s to be %v", resource, expected)
+		}
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	e := newLocalEncryptor(t, keyFile, "secret")
+
+	encrypted, changed, err := e.Migrate(true, "k", "v")
+	if err != nil || !changed || !IsEncrypted(encrypted) {
+		t.Fatalf("expected the sensitive plaintext to be encrypted, got %q, %v, %v", encrypted, changed, err)
+	}
+	if _, changed, err := e.Migrate(true, "k", encrypted); err != nil || changed {
+		t.Errorf("expected the encrypted value to be unchanged, got %v, %v", changed, err)
+	}
+	if decrypted, changed, err := e.Migrate(false, "k", encrypted); err != nil || !changed || decrypted != "v" {
+		t.Errorf("expected the value not sensitive to be decrypted, got %q, %v, %v", decrypted, changed, err)
+	}
+
+	// the values encrypted with the previous key are re-encrypted with the current one
+	previous := newLocalEncryptor(t, keyFile, "secret")
+	previous.keyID = "previous"
+	reencrypted, changed, err := previous.Migrate(true, "k", encrypted)
+	if err != nil || !changed || reencrypted == encrypted {
+		t.Errorf("expected the value of previous key to be re-encrypted, got %v, %v", changed, err)
+	}
+}
+
+func TestSealedKeyProvider(t *testing.T) {
+	dir := t.TempDir()
+	productUUID := filepath.Join(dir, "product_uuid")
+	if err := os.WriteFile(productUUID, []byte("node-a\n"), 0400); err != nil {
+		t.Fatal(err)
+	}
+	originalUUID, originalID := productUUIDFile, machineIDFile
+	productUUIDFile, machineIDFile = productUUID, filepath.Join(dir, "not-exist")
+	defer func() { productUUIDFile, machineIDFile = originalUUID, originalID }()
+
+	keyFile := filepath.Join(dir, "sealed-key")
+	provider, err := NewSealedKeyProvider(keyFile, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped, err := provider.WrapKey(context.Background(), []byte("data key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := NewSealedKeyProvider(keyFile, false)
+	if err != nil {
+		t.Fatalf("failed to unseal key on the same node: %v", err)
+	}
+	if dek, err := reopened.UnwrapKey(context.Background(), wrapped); err != nil || string(dek) != "data key" {
+		t.Errorf("unexpected data key %q: %v", dek, err)
+	}
+
+	// the key file copied to another node can not be unsealed
+	if err := os.WriteFile(productUUID, []byte("node-b\n"), 0400); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSealedKeyProvider(keyFile, false); err == nil {
+		t.Error("expected the key file to be unsealed only on the node it is sealed")
+	}
+
+	// the key is not sealed with the machine id only unless it is allowed
+	machineID := filepath.Join(dir, "machine-id")
+	if err := os.WriteFile(machineID, []byte("machine-a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	productUUIDFile, machineIDFile = filepath.Join(dir, "not-exist"), machineID
+	machineKeyFile := filepath.Join(dir, "machine-sealed-key")
+	if _, err := NewSealedKeyProvider(machineKeyFile, false); err == nil {
+		t.Error("expected the key not to be sealed without product uuid")
+	}
+	if _, err := os.Stat(machineKeyFile); !os.IsNotExist(err) {
+		t.Errorf("expected no key file to be generated without product uuid: %v", err)
+	}
+	if _, err := NewSealedKeyProvider(machineKeyFile, true); err != nil {
+		t.Errorf("expected the key to be sealed with the machine id only when it is allowed: %v", err)
+	}
+}
+
+// fakeKMS wraps the data keys by reversing them
+type fakeKMS struct {
+	kmsapi.UnimplementedKeyManagementServiceServer
+}
+
+func reverse(data []byte) []byte {
+	out := make([]byte, len(data))
+	for i := range data {
+		out[len(data)-1-i] = data[i]
+	}
+	return out
+}
+
+func (*fakeKMS) Status(context.Context, *kmsapi.StatusRequest) (*kmsapi.StatusResponse, error) {
+	return &kmsapi.StatusResponse{Version: "v2", Healthz: "ok", KeyId: "kek-1"}, nil
+}
+
+func (*fakeKMS) Encrypt(_ context.Context, req *kmsapi.EncryptRequest) (*kmsapi.EncryptResponse, error) {
+	return &kmsapi.EncryptResponse{Ciphertext: reverse(req.Plaintext), KeyId: "kek-1"}, nil
+}
+
+func (*fakeKMS) Decrypt(_ context.Context, req *kmsapi.DecryptRequest) (*kmsapi.DecryptResponse, error) {
+	return &kmsapi.DecryptResponse{Plaintext: reverse(req.Ciphertext)}, nil
+}
+
+func TestKMSKeyProvider(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "kms.sock")
+	lis, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	kmsapi.RegisterKeyManagementServiceServer(server, &fakeKMS{})
+	go server.Serve(lis)
+	defer server.Stop()
+
+	provider, err := NewKMSKeyProvider("unix://"+socket, 3*time.Second)
+	if err != nil {
+		t.Fatalf("failed to connect to KMS plugin: %v", err)
+	}
+	e, err := NewEncryptor(provider, []string{"secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := e.Encrypt("default/secret/db", "value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted, err := e.Decrypt("default/secret/db", encrypted); err != nil || decrypted != "value" {
+		t.Errorf("unexpected decrypted value %q: %v", decrypted, err)
+	}
+}
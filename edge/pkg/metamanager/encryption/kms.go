@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	kmsapi "k8s.io/kms/apis/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+// kmsKeyProvider wraps the data encryption keys by the KMS v2 plugin, the same plugins
+// serving the encryption at rest of kube-apiserver can be used
+type kmsKeyProvider struct {
+	client  kmsapi.KeyManagementServiceClient
+	timeout time.Duration
+}
+
+// NewKMSKeyProvider returns the key provider connecting to the KMS v2 plugin listening on the unix socket endpoint
+func NewKMSKeyProvider(endpoint string, timeout time.Duration) (KeyProvider, error) {
+	conn, err := grpc.Dial(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to KMS plugin %s: %v", endpoint, err)
+	}
+	p := &kmsKeyProvider{
+		client:  kmsapi.NewKeyManagementServiceClient(conn),
+		timeout: timeout,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if _, err := p.KeyID(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *kmsKeyProvider) Name() string {
+	return v1alpha2.MetaEncryptionProviderKMS
+}
+
+func (p *kmsKeyProvider) KeyID(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	resp, err := p.client.Status(ctx, &kmsapi.StatusRequest{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get status of KMS plugin: %v", err)
+	}
+	if resp.Healthz != "ok" {
+		return "", fmt.Errorf("KMS plugin is unhealthy: %s", resp.Healthz)
+	}
+	if resp.KeyId == "" {
+		return "", fmt.Errorf("KMS plugin returns empty key id")
+	}
+	return resp.KeyId, nil
+}
+
+func (p *kmsKeyProvider) WrapKey(ctx context.Context, dek []byte) (*WrappedKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	resp, err := p.client.Encrypt(ctx, &kmsapi.EncryptRequest{Plaintext: dek, Uid: string(uuid.NewUUID())})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt data key by KMS plugin: %v", err)
+	}
+	return &WrappedKey{Ciphertext: resp.Ciphertext, KeyID: resp.KeyId, Annotations: resp.Annotations}, nil
+}
+
+func (p *kmsKeyProvider) UnwrapKey(ctx context.Context, key *WrappedKey) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	resp, err := p.client.Decrypt(ctx, &kmsapi.DecryptRequest{
+		Ciphertext:  key.Ciphertext,
+		Uid:         string(uuid.NewUUID()),
+		KeyId:       key.KeyID,
+		Annotations: key.Annotations,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key by KMS plugin: %v", err)
+	}
+	return resp.Plaintext, nil
+}
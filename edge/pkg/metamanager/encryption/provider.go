@@ -0,0 +1,244 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/hkdf"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+// keySize is the size of the key encryption keys and data encryption keys, AES-256 is used
+const keySize = 32
+
+// WrappedKey is a data encryption key encrypted by the key provider
+type WrappedKey struct {
+	Ciphertext []byte
+	// KeyID identifies the key encryption key the data encryption key is encrypted with
+	KeyID string
+	// Annotations are the additional metadata the key provider returns when encrypting
+	Annotations map[string][]byte
+}
+
+// KeyProvider encrypts and decrypts the data encryption keys with the key encryption key it holds
+type KeyProvider interface {
+	// Name returns the name of the key provider, it is saved with the encrypted data
+	Name() string
+	// KeyID returns the id of the current key encryption key, the data encrypted with
+	// the other keys are re-encrypted by the migration
+	KeyID(ctx context.Context) (string, error)
+	// WrapKey encrypts the data encryption key with the current key encryption key
+	WrapKey(ctx context.Context, dek []byte) (*WrappedKey, error)
+	// UnwrapKey decrypts the data encryption key
+	UnwrapKey(ctx context.Context, key *WrappedKey) ([]byte, error)
+}
+
+// aesKeyProvider wraps the data encryption keys with AES-GCM by a key encryption key on the node
+type aesKeyProvider struct {
+	name  string
+	keyID string
+	aead  cipher.AEAD
+}
+
+func newAESKeyProvider(name string, kek []byte) (*aesKeyProvider, error) {
+	aead, err := newAEAD(kek)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(kek)
+	return &aesKeyProvider{
+		name:  name,
+		keyID: hex.EncodeToString(sum[:8]),
+		aead:  aead,
+	}, nil
+}
+
+func (p *aesKeyProvider) Name() string {
+	return p.name
+}
+
+func (p *aesKeyProvider) KeyID(_ context.Context) (string, error) {
+	return p.keyID, nil
+}
+
+func (p *aesKeyProvider) WrapKey(_ context.Context, dek []byte) (*WrappedKey, error) {
+	ciphertext, err := seal(p.aead, dek, []byte(p.keyID))
+	if err != nil {
+		return nil, err
+	}
+	return &WrappedKey{Ciphertext: ciphertext, KeyID: p.keyID}, nil
+}
+
+func (p *aesKeyProvider) UnwrapKey(_ context.Context, key *WrappedKey) ([]byte, error) {
+	if key.KeyID != p.keyID {
+		return nil, fmt.Errorf("data key is encrypted with unknown key %s", key.KeyID)
+	}
+	return open(p.aead, key.Ciphertext, []byte(p.keyID))
+}
+
+// NewLocalKeyProvider returns the key provider with the key encryption key saved in the key file
+// in base64, the key is generated if the key file not exists
+func NewLocalKeyProvider(keyFile string) (KeyProvider, error) {
+	kek, err := loadOrGenerateKey(keyFile, func(data []byte) ([]byte, error) {
+		return base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	}, func(kek []byte) ([]byte, error) {
+		return []byte(base64.StdEncoding.EncodeToString(kek)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newAESKeyProvider(v1alpha2.MetaEncryptionProviderLocal, kek)
+}
+
+var (
+	// productUUIDFile identifies the hardware the sealed key is bound to, it is only readable by root
+	productUUIDFile = "/sys/class/dmi/id/product_uuid"
+	// machineIDFile identifies the installation the sealed key is bound to, it is readable by all users
+	// and cloned with the disk images, so it does not seal the key alone unless it is allowed explicitly
+	machineIDFile = "/etc/machine-id"
+	sealInfo      = []byte("kubeedge-metamanager-sealed-key")
+)
+
+// NewSealedKeyProvider returns the key provider with the key encryption key saved in the key file
+// sealed by the key derived from the identities of the node, so the key file is useless
+// if it is copied to the other nodes. It is the alternative to the TPM on the nodes without it.
+// The product uuid of the hardware is required unless allowMachineIDOnly is true.
+func NewSealedKeyProvider(keyFile string, allowMachineIDOnly bool) (KeyProvider, error) {
+	sealKey, err := deriveSealKey(allowMachineIDOnly)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(sealKey)
+	if err != nil {
+		return nil, err
+	}
+	kek, err := loadOrGenerateKey(keyFile, func(data []byte) ([]byte, error) {
+		kek, err := open(aead, data, sealInfo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unseal key, the key file may be sealed on another node: %v", err)
+		}
+		return kek, nil
+	}, func(kek []byte) ([]byte, error) {
+		return seal(aead, kek, sealInfo)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newAESKeyProvider(v1alpha2.MetaEncryptionProviderSealed, kek)
+}
+
+// deriveSealKey derives the seal key from the product uuid and the machine id of the node
+func deriveSealKey(allowMachineIDOnly bool) ([]byte, error) {
+	productUUID, err := os.ReadFile(productUUIDFile)
+	productUUID = bytes.TrimSpace(productUUID)
+	if err != nil || len(productUUID) == 0 {
+		if !allowMachineIDOnly {
+			return nil, fmt.Errorf("product uuid of the node is not found in %s to seal the key, use the kms or local key provider, "+
+				"or allow sealing the key with the machine id only: %v", productUUIDFile, err)
+		}
+		klog.Warningf("product uuid of the node is not found in %s, the key is sealed with the machine id only: %v", productUUIDFile, err)
+	}
+	secret := productUUID
+	machineID, err := os.ReadFile(machineIDFile)
+	if err != nil {
+		klog.V(4).Infof("skip machine id file %s: %v", machineIDFile, err)
+	}
+	secret = append(secret, bytes.TrimSpace(machineID)...)
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("no machine id is found in %s and %s to seal the key", productUUIDFile, machineIDFile)
+	}
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, sealInfo), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// loadOrGenerateKey reads the key encryption key from the key file, or generates it and
+// writes it to the key file only readable by the owner if the key file not exists
+func loadOrGenerateKey(keyFile string, decode, encode func([]byte) ([]byte, error)) ([]byte, error) {
+	data, err := os.ReadFile(keyFile)
+	if err == nil {
+		kek, err := decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key file %s: %v", keyFile, err)
+		}
+		if len(kek) != keySize {
+			return nil, fmt.Errorf("invalid key file %s: key must be %d bytes", keyFile, keySize)
+		}
+		return kek, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read key file %s: %v", keyFile, err)
+	}
+
+	kek := make([]byte, keySize)
+	if _, err := rand.Read(kek); err != nil {
+		return nil, err
+	}
+	if data, err = encode(kek); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(keyFile, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write key file %s: %v", keyFile, err)
+	}
+	klog.Infof("key of the database encryption is generated in %s", keyFile)
+	return kek, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts the plaintext and returns the nonce followed by the ciphertext
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, data, additionalData []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
@@ -11,6 +11,8 @@ import (
 	metamanagerconfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/config"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
 	v2 "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/digest"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/encryption"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver"
 	metaserverconfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/config"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage/sqlite/imitator"
@@ -33,6 +35,11 @@ func Register(metaManager *v1alpha2.MetaManager) {
 	metamanagerconfig.InitConfigure(metaManager)
 	meta := newMetaManager(metaManager.Enable)
 	initDBTable(meta)
+	if meta.Enable() {
+		if err := encryption.Init(metaManager.Encryption); err != nil {
+			klog.Exitf("failed to init database encryption: %v", err)
+		}
+	}
 	core.Register(meta)
 }
 
@@ -60,10 +67,24 @@ func (m *metaManager) Enable() bool {
 }
 
 func (m *metaManager) Start() {
+	migrateEncryption()
 	if metaserverconfig.Config.Enable {
 		imitator.StorageInit()
 		go metaserver.NewMetaServer().Start(beehiveContext.Done())
 	}
+	if c := metamanagerconfig.Config.DriftDetection; c != nil && c.Enable {
+		go digest.Run(c)
+	}
 
 	m.runMetaManager()
 }
+
+// migrateEncryption encrypts or decrypts the values saved before according to the current encryption config
+func migrateEncryption() {
+	if err := dao.MigrateEncryption(); err != nil {
+		klog.Errorf("failed to migrate encryption of table meta: %v", err)
+	}
+	if err := v2.MigrateEncryption(); err != nil {
+		klog.Errorf("failed to migrate encryption of table meta_v2: %v", err)
+	}
+}
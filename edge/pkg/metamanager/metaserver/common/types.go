@@ -1,6 +1,16 @@
 package common
 
+import "github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dttype"
+
 type RestartInfo struct {
 	Namespace string
 	PodNames  []string
 }
+
+// TwinHistoryResponse is the local history of reported twin values of a device
+type TwinHistoryResponse struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Twin, key is the twin property name, value is the samples sorted by time
+	Twin map[string][]dttype.TwinHistorySample `json:"twin"`
+}
@@ -2,9 +2,15 @@ package handlerfactory
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dtclient"
+	"github.com/kubeedge/kubeedge/edge/pkg/devicetwin/dttype"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/common"
+	"github.com/kubeedge/kubeedge/pkg/util"
 )
 
 func (f *Factory) Restart(namespace string) http.Handler {
@@ -46,3 +52,67 @@ func (f *Factory) Restart(namespace string) http.Handler {
 	})
 	return h
 }
+
+// TwinHistory serves the local twin history of the device, the query parameters are
+// property, start and end, start and end accept RFC3339 time or unix milliseconds
+func (f *Factory) TwinHistory(namespace, name string) http.Handler {
+	h := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		start, err := parseHistoryTime(query.Get("start"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid start: %v", err), http.StatusBadRequest)
+			return
+		}
+		end, err := parseHistoryTime(query.Get("end"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid end: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		history, err := dtclient.QueryDeviceTwinHistory(util.GetResourceID(namespace, name), query.Get("property"), start, end)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		historyResponse := common.TwinHistoryResponse{
+			Namespace: namespace,
+			Name:      name,
+			Twin:      make(map[string][]dttype.TwinHistorySample),
+		}
+		for _, sample := range *history {
+			historyResponse.Twin[sample.Name] = append(historyResponse.Twin[sample.Name], dttype.TwinHistorySample{
+				Value:     sample.Value,
+				Type:      sample.AttrType,
+				Timestamp: sample.Timestamp,
+			})
+		}
+		historyResBytes, err := json.Marshal(historyResponse)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if _, err = w.Write(historyResBytes); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+	return h
+}
+
+// parseHistoryTime parses RFC3339 time or unix milliseconds into unix milliseconds,
+// empty value returns 0
+func parseHistoryTime(v string) (int64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return ms, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return 0, err
+	}
+	return t.UnixMilli(), nil
+}
@@ -17,9 +17,9 @@ import (
 
 	"github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/common/constants"
-	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
 	v2 "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/encryption"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage/sqlite/imitator/watchhook"
 	"github.com/kubeedge/kubeedge/pkg/metaserver"
 )
@@ -75,13 +75,17 @@ func (s *imitator) InsertOrUpdateObj(_ context.Context, obj runtime.Object) erro
 		return err
 	}
 	objRv, err := s.versioner.ObjectResourceVersion(obj)
+	value, err := encryption.EncryptResource(gvr.Resource, key, buf.String())
+	if err != nil {
+		return fmt.Errorf("failed to encrypt obj %s: %v", key, err)
+	}
 	m := v2.MetaV2{
 		Key:                  key,
 		GroupVersionResource: gvr.String(),
 		Namespace:            ns,
 		Name:                 name,
 		ResourceVersion:      objRv,
-		Value:                buf.String(),
+		Value:                value,
 	}
 	return s.insertOrReplaceMetaV2(m, objRv)
 }
@@ -89,14 +93,14 @@ func (s *imitator) InsertOrUpdateObj(_ context.Context, obj runtime.Object) erro
 func (s *imitator) insertOrReplaceMetaV2(m v2.MetaV2, objRv uint64) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	_, err := dbm.DBAccess.Raw("INSERT OR REPLACE INTO meta_v2 (key, groupversionresource, namespace,name,resourceversion,value) VALUES (?,?,?,?,?,?)", m.Key, m.GroupVersionResource, m.Namespace, m.Name, m.ResourceVersion, m.Value).Exec()
+	err := v2.GetStore().InsertOrReplace(&m)
 	var maxRetryTimes = 3
 	for i := 1; err != nil; i++ {
 		klog.Errorf("failed to access database:%v", err)
 		if i == maxRetryTimes {
 			return fmt.Errorf("failed to access database after %v times try", i)
 		}
-		_, err = dbm.DBAccess.Raw("INSERT OR REPLACE INTO meta_v2 (key, groupversionresource, namespace,name,resourceversion,value) VALUES (?,?,?,?,?,?)", m.Key, m.GroupVersionResource, m.Namespace, m.Name, m.ResourceVersion, m.Value).Exec()
+		err = v2.GetStore().InsertOrReplace(&m)
 	}
 	if objRv > s.GetRevision() {
 		s.SetRevision(objRv)
@@ -116,27 +120,24 @@ func (s *imitator) InsertOrUpdatePassThroughObj(_ context.Context, obj []byte, k
 func (s *imitator) GetPassThroughObj(_ context.Context, key string) ([]byte, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	results := new([]v2.MetaV2)
-	_, err := dbm.DBAccess.QueryTable(v2.NewMetaTableName).Filter(v2.KEY, key).All(results)
+	result, err := v2.GetStore().Get(key)
 	if err != nil {
 		return nil, err
 	}
-
-	switch {
-	case len(*results) == 1:
-		klog.V(4).Infof("[metaserver]successfully insert or update obj:%v", key)
-		return []byte((*results)[0].Value), nil
-	default:
+	if result == nil {
 		return nil, fmt.Errorf("the server could not find the requested resource")
 	}
+	klog.V(4).Infof("[metaserver]successfully insert or update obj:%v", key)
+	value, err := encryption.Decrypt(key, result.Value)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(value), nil
 }
 
 func (s *imitator) Delete(_ context.Context, key string) error {
-	m := v2.MetaV2{
-		Key: key,
-	}
 	s.lock.Lock()
-	_, err := dbm.DBAccess.Delete(&m)
+	err := v2.GetStore().Delete(key)
 	if err != nil {
 		klog.Errorf("[imitator] delete error: %v", err)
 	}
@@ -162,6 +163,9 @@ func (s *imitator) Get(_ context.Context, key string) (Resp, error) {
 	}
 	switch {
 	case len(*results) == 1:
+		if err := decryptMetaV2(results); err != nil {
+			return Resp{}, err
+		}
 		resp.Kvs = results
 		return resp, nil
 	default:
@@ -183,10 +187,25 @@ func (s *imitator) List(_ context.Context, key string) (Resp, error) {
 	if err != nil {
 		return Resp{}, err
 	}
+	if err := decryptMetaV2(results); err != nil {
+		return Resp{}, err
+	}
 	resp.Kvs = results
 	return resp, nil
 }
 
+// decryptMetaV2 decrypts the values of the encrypted objs in place
+func decryptMetaV2(objs *[]v2.MetaV2) error {
+	for i := range *objs {
+		value, err := encryption.Decrypt((*objs)[i].Key, (*objs)[i].Value)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt obj %s: %v", (*objs)[i].Key, err)
+		}
+		(*objs)[i].Value = value
+	}
+	return nil
+}
+
 func (s *imitator) GetRevision() uint64 {
 	return s.revision
 }
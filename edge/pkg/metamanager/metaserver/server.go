@@ -178,6 +178,8 @@ func (ls *MetaServer) BuildBasicHandler() http.Handler {
 
 		if reqInfo.IsResourceRequest {
 			switch {
+			case reqInfo.Verb == "get" && reqInfo.Resource == "devices" && reqInfo.Subresource == "twinhistory":
+				ls.Factory.TwinHistory(reqInfo.Namespace, reqInfo.Name).ServeHTTP(w, req)
 			case reqInfo.Verb == "get":
 				ls.Factory.Get().ServeHTTP(w, req)
 			case reqInfo.Verb == "list", reqInfo.Verb == "watch":
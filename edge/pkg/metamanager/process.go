@@ -19,6 +19,7 @@ import (
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/client"
 	metaManagerConfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/config"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/digest"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage/sqlite/imitator"
 )
 
@@ -392,6 +393,12 @@ func (m *metaManager) processVolume(message model.Message) {
 	klog.Infof("process volume get: req[%+v], back[%+v], err[%+v]", message, back, err)
 	if err != nil {
 		klog.Errorf("process volume send to edged failed: %v", err)
+		feedbackError(err, message)
+		return
+	}
+	if back.GetOperation() == model.ResponseErrorOperation {
+		feedbackError(fmt.Errorf("%v", back.GetContent()), message)
+		return
 	}
 
 	resp := message.NewRespByMessage(&message, back.GetContent())
@@ -400,6 +407,10 @@ func (m *metaManager) processVolume(message model.Message) {
 }
 
 func (m *metaManager) process(message model.Message) {
+	if message.GetResource() == constants.ResourceTypeDigest {
+		digest.HandleResult(message)
+		return
+	}
 	operation := message.GetOperation()
 
 	switch operation {
@@ -418,7 +429,14 @@ func (m *metaManager) process(message model.Message) {
 	case constants.CSIOperationTypeCreateVolume,
 		constants.CSIOperationTypeDeleteVolume,
 		constants.CSIOperationTypeControllerPublishVolume,
-		constants.CSIOperationTypeControllerUnpublishVolume:
+		constants.CSIOperationTypeControllerUnpublishVolume,
+		constants.CSIOperationTypeControllerExpandVolume,
+		constants.CSIOperationTypeControllerGetVolume,
+		constants.CSIOperationTypeListVolumes,
+		constants.CSIOperationTypeGetCapacity,
+		constants.CSIOperationTypeCreateSnapshot,
+		constants.CSIOperationTypeDeleteSnapshot,
+		constants.CSIOperationTypeListSnapshots:
 		m.processVolume(message)
 	default:
 		klog.Errorf("metamanager not supported operation: %v", operation)
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metamanager
+
+import (
+	"errors"
+	"io"
+
+	"github.com/beego/beego/v2/client/orm"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
+	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/bolt"
+	v2 "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
+)
+
+// boltDB is the bbolt database of the metadata, it is nil if the metadata are stored in sqlite
+var boltDB *bolt.DB
+
+// BackupBolt writes a consistent snapshot of the bbolt database of the metadata to w,
+// the database is locked by edgecore so the other processes back it up through edgecore
+func BackupBolt(w io.Writer) (int64, error) {
+	if boltDB == nil {
+		return 0, errors.New("metadata are not stored in bbolt")
+	}
+	return boltDB.WriteTo(w)
+}
+
+// InitStorage sets the storage backend of the metadata, the metadata are stored in the sqlite
+// database by default. It must be called before the modules start.
+func InitStorage(db *v1alpha2.DataBase) {
+	if db.MetaBackend != v1alpha2.MetaBackendBolt {
+		return
+	}
+	var err error
+	boltDB, err = bolt.Open(db.BoltDataSource)
+	if err != nil {
+		klog.Exitf("failed to init bbolt storage of metadata: %v", err)
+	}
+	dao.SetMetaStore(boltDB.MetaStore())
+	v2.SetStore(boltDB.MetaV2Store())
+	klog.Infof("metadata are stored in bbolt database %s", db.BoltDataSource)
+}
+
+// MigrateToBolt copies the metadata in the sqlite database to the bbolt database, edgecore must be
+// stopped while migrating. The sqlite tables are kept so the backend can be switched back.
+func MigrateToBolt(db *v1alpha2.DataBase) error {
+	orm.RegisterModel(new(dao.Meta))
+	orm.RegisterModel(new(v2.MetaV2))
+	dbm.InitDBConfig(db.DriverName, db.AliasName, db.DataSource)
+
+	target, err := bolt.Open(db.BoltDataSource)
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+	metas, objs, err := target.Migrate(dao.NewSQLiteMetaStore(), v2.NewSQLiteStore())
+	if err != nil {
+		return err
+	}
+	klog.Infof("%d rows of table meta and %d rows of table meta_v2 are migrated from %s to %s",
+		metas, objs, db.DataSource, db.BoltDataSource)
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	cmdcommon "github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/image"
+)
+
+var bundleCreateExample = `
+keadm bundle create --kubeedge-version=v` + cmdcommon.DefaultKubeEdgeVersion + ` --arch=arm64 --output=/tmp
+
+  - This command saves the edgecore binary, the cloud and edge images, the CRDs and the charts to
+    /tmp/kubeedge-bundle-v` + cmdcommon.DefaultKubeEdgeVersion + `-linux-arm64.tar.gz, and its checksum to the .sha256 file next to it
+  - Copy both files to the nodes without internet access and install with the --bundle flag of
+    "keadm init", "keadm join" and "keadm upgrade"
+`
+
+// BundleCreateOptions defines the flags of "keadm bundle create"
+type BundleCreateOptions struct {
+	KubeEdgeVersion string
+	Arch            string
+	ImageRepository string
+	// Images are the additional images saved with the edge images
+	Images    []string
+	OutputDir string
+}
+
+// newCmdBundle returns cobra.Command for "keadm bundle" command
+func newCmdBundle() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Manage the offline installation bundle",
+		Long:  "Use this command to create the bundle to install KubeEdge on the nodes without internet access",
+	}
+
+	cmd.AddCommand(newCmdBundleCreate())
+	return cmd
+}
+
+// newCmdBundleCreate returns the "keadm bundle create" command
+func newCmdBundleCreate() *cobra.Command {
+	opts := &BundleCreateOptions{
+		Arch:            runtime.GOARCH,
+		ImageRepository: "kubeedge",
+		OutputDir:       ".",
+	}
+
+	cmd := &cobra.Command{
+		Use:     "create",
+		Short:   "Create the versioned offline installation bundle",
+		Example: bundleCreateExample,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			ver, err := util.GetCurrentVersion(opts.KubeEdgeVersion)
+			if err != nil {
+				return err
+			}
+			opts.KubeEdgeVersion = ver
+
+			bundle, err := util.CreateBundle(context.Background(), newBundleOptions(opts))
+			if err != nil {
+				return fmt.Errorf("failed to create bundle: %v", err)
+			}
+			fmt.Printf("Bundle is created: %s\n", bundle)
+			return nil
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().StringVar(&opts.KubeEdgeVersion, cmdcommon.FlagNameKubeEdgeVersion, opts.KubeEdgeVersion,
+		"Use this key to decide which a specific KubeEdge version to be bundled.")
+	cmd.Flags().StringVar(&opts.Arch, "arch", opts.Arch,
+		"Use this key to set the architecture of the nodes the bundle is installed on, e.g. amd64, arm64 and arm")
+	cmd.Flags().StringVar(&opts.ImageRepository, cmdcommon.FlagNameImageRepository, opts.ImageRepository,
+		"Use this key to decide which image repository to pull images from.")
+	cmd.Flags().StringSliceVar(&opts.Images, "images", opts.Images,
+		"Use this key to add images the edge nodes use to the bundle, e.g. the images of the applications")
+	cmd.Flags().StringVar(&opts.OutputDir, "output", opts.OutputDir,
+		"Use this key to set the directory the bundle is written to")
+	return cmd
+}
+
+// newBundleOptions returns the contents of the bundle of the options
+func newBundleOptions(opts *BundleCreateOptions) util.BundleOptions {
+	edgeSet := image.EdgeSet(&cmdcommon.JoinOptions{
+		KubeEdgeVersion: opts.KubeEdgeVersion,
+		ImageRepository: opts.ImageRepository,
+	})
+	cloudImages := image.CloudSet(opts.ImageRepository, opts.KubeEdgeVersion).List()
+	edgeImages := append(edgeSet.List(), constants.DefaultPodSandboxImage)
+	edgeImages = append(edgeImages, opts.Images...)
+	sort.Strings(cloudImages)
+	sort.Strings(edgeImages)
+
+	return util.BundleOptions{
+		Version:         opts.KubeEdgeVersion,
+		Arch:            opts.Arch,
+		ImageRepository: opts.ImageRepository,
+		Images: map[string][]string{
+			util.BundlePartCloud: cloudImages,
+			util.BundlePartEdge:  edgeImages,
+		},
+		EdgeCoreImage: edgeSet.Get(image.EdgeCore),
+		OutputDir:     opts.OutputDir,
+	}
+}
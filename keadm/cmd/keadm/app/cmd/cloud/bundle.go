@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	types "github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+func addBundleFlags(cmd *cobra.Command, base *types.CloudInitUpdateBase) {
+	cmd.Flags().StringVar(&base.Bundle, types.FlagNameBundle, base.Bundle,
+		"Use this key to install from the offline bundle created by 'keadm bundle create' without accessing the internet. "+
+			"The cloud images in the bundle are imported to the container runtime of this node, "+
+			"load them to the other nodes or a private registry if the cloud components may run on other nodes")
+
+	cmd.Flags().BoolVar(&base.SkipVerify, types.FlagNameSkipVerify, base.SkipVerify,
+		"Use this key to allow the offline bundle without its checksum file, only the files in the bundle are verified then")
+
+	cmd.Flags().StringVar(&base.RemoteRuntimeEndpoint, types.FlagNameRemoteRuntimeEndpoint, base.RemoteRuntimeEndpoint,
+		"The endpoint of the container runtime of this node the images in the bundle are imported to")
+}
+
+// openBundle verifies and extracts the bundle, imports the cloud images of it and sets the version and charts
+// of the options to the ones of the bundle. The caller must close the bundle after installing.
+func openBundle(base *types.CloudInitUpdateBase) (*util.Bundle, error) {
+	bundle, err := util.OpenBundle(base.Bundle, base.SkipVerify)
+	if err != nil {
+		return nil, err
+	}
+	version := bundle.Manifest.Version
+	if base.KubeEdgeVersion != "" && strings.TrimPrefix(base.KubeEdgeVersion, "v") != strings.TrimPrefix(version, "v") {
+		bundle.Close()
+		return nil, fmt.Errorf("version %s mismatches the version %s of the bundle", base.KubeEdgeVersion, version)
+	}
+	base.KubeEdgeVersion = version
+	if base.ImageRepository == "" {
+		base.ImageRepository = bundle.Manifest.ImageRepository
+	}
+	base.BundleChartsDir = bundle.Path(util.BundleChartsDir)
+
+	if !base.DryRun {
+		if err := bundle.ImportImages(base.RemoteRuntimeEndpoint, util.BundlePartCloud); err != nil {
+			bundle.Close()
+			return nil, fmt.Errorf("failed to import images of the bundle: %v", err)
+		}
+	}
+	return bundle, nil
+}
@@ -19,6 +19,7 @@ package cloud
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -26,6 +27,7 @@ import (
 	"github.com/kubeedge/kubeedge/common/constants"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/security/token"
 )
 
 var (
@@ -33,11 +35,20 @@ var (
 "keadm gettoken" command prints the token to use for establishing bidirectional trust between edge nodes and cloudcore.
 A token can be used when a edge node is about to join the cluster. With this token the cloudcore then approve the
 certificate request.
+By default the token shared by all edge nodes is printed. If a node name, node name pattern or node group is set,
+a bootstrap token is created instead, which can only be used by the specified nodes before it expires and for a
+limited number of times.
 `
 	gettokenExample = `
 keadm gettoken --kube-config /root/.kube/config
 - kube-config is the absolute path of kubeconfig which used to build secure connectivity between keadm and kube-apiserver
 to get the token.
+
+keadm gettoken --node-name edge-node-1 --ttl 2h
+- create a single-use bootstrap token for edge-node-1 which expires in 2 hours.
+
+keadm gettoken --node-group beijing --usages 10
+- create a bootstrap token which can be used 10 times by the nodes of node group beijing.
 `
 )
 
@@ -51,7 +62,13 @@ func NewGettoken() *cobra.Command {
 		Long:    gettokenLongDescription,
 		Example: gettokenExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := queryToken(constants.SystemNamespace, common.TokenSecretName, init.Kubeconfig)
+			var token []byte
+			var err error
+			if init.NodeName != "" || init.NodeNamePattern != "" || init.NodeGroup != "" {
+				token, err = createBootstrapToken(constants.SystemNamespace, init)
+			} else {
+				token, err = queryToken(constants.SystemNamespace, common.TokenSecretName, init.Kubeconfig)
+			}
 			if err != nil {
 				fmt.Printf("failed to get token, err is %s\n", err)
 				return err
@@ -66,12 +83,24 @@ func NewGettoken() *cobra.Command {
 func addGettokenFlags(cmd *cobra.Command, gettokenOptions *common.GettokenOptions) {
 	cmd.Flags().StringVar(&gettokenOptions.Kubeconfig, common.FlagNameKubeConfig, gettokenOptions.Kubeconfig,
 		"Use this key to set kube-config path, eg: $HOME/.kube/config")
+	cmd.Flags().StringVar(&gettokenOptions.NodeName, common.FlagNameNodeName, gettokenOptions.NodeName,
+		"Create a bootstrap token which can only be used by the node with this name")
+	cmd.Flags().StringVar(&gettokenOptions.NodeNamePattern, common.FlagNameNodeNamePattern, gettokenOptions.NodeNamePattern,
+		"Create a bootstrap token which can only be used by the nodes whose names match this shell pattern, eg: edge-*")
+	cmd.Flags().StringVar(&gettokenOptions.NodeGroup, common.FlagNameNodeGroup, gettokenOptions.NodeGroup,
+		"Create a bootstrap token which can only be used by the nodes listed in this node group")
+	cmd.Flags().DurationVar(&gettokenOptions.TTL, common.FlagNameTTL, gettokenOptions.TTL,
+		"The duration before the bootstrap token expires")
+	cmd.Flags().IntVar(&gettokenOptions.Usages, common.FlagNameUsages, gettokenOptions.Usages,
+		"The number of times the bootstrap token can be used")
 }
 
 // newGettokenOptions return common options
 func newGettokenOptions() *common.GettokenOptions {
 	opts := &common.GettokenOptions{}
 	opts.Kubeconfig = common.DefaultKubeConfig
+	opts.TTL = common.DefaultBootstrapTokenTTL
+	opts.Usages = 1
 	return opts
 }
 
@@ -88,6 +117,48 @@ func queryToken(namespace string, name string, kubeConfigPath string) ([]byte, e
 	return secret.Data[common.TokenDataName], nil
 }
 
+// createBootstrapToken creates a bootstrap token signed by the CA of cloudcore and saves it to k8s
+func createBootstrapToken(namespace string, opts *common.GettokenOptions) ([]byte, error) {
+	if opts.NodeName != "" && opts.NodeNamePattern != "" {
+		return nil, fmt.Errorf("only one of --%s and --%s can be set", common.FlagNameNodeName, common.FlagNameNodeNamePattern)
+	}
+	if opts.TTL <= 0 || opts.Usages <= 0 {
+		return nil, fmt.Errorf("--%s and --%s must be positive", common.FlagNameTTL, common.FlagNameUsages)
+	}
+	client, err := util.KubeClient(opts.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	caSecret, err := client.CoreV1().Secrets(namespace).Get(context.Background(), common.CaSecretName, metaV1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the CA of cloudcore, err: %v", err)
+	}
+
+	id, err := token.NewBootstrapTokenID()
+	if err != nil {
+		return nil, err
+	}
+	bt := &token.BootstrapToken{
+		ID:              id,
+		Expiration:      time.Now().Add(opts.TTL),
+		UsageLimit:      opts.Usages,
+		NodeNamePattern: opts.NodeNamePattern,
+		NodeGroup:       opts.NodeGroup,
+	}
+	if opts.NodeName != "" {
+		// node names can not contain shell pattern characters, so the name only matches itself
+		bt.NodeNamePattern = opts.NodeName
+	}
+	caHashToken, err := token.CreateBootstrap(caSecret.Data[common.CaDataName], caSecret.Data[common.CaKeyDataName], bt)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := client.CoreV1().Secrets(namespace).Create(context.Background(), bt.ToSecret(namespace), metaV1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to save the bootstrap token, err: %v", err)
+	}
+	return []byte(caHashToken), nil
+}
+
 // showToken prints the token
 func showToken(data []byte) error {
 	_, err := fmt.Println(string(data))
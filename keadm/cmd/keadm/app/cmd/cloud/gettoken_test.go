@@ -56,6 +56,20 @@ func TestAddGettokenFlags(t *testing.T) {
 	assert.NotNil(flag)
 	assert.Equal(common.DefaultKubeConfig, flag.DefValue)
 	assert.Equal(common.FlagNameKubeConfig, flag.Name)
+
+	for _, name := range []string{common.FlagNameNodeName, common.FlagNameNodeNamePattern, common.FlagNameNodeGroup} {
+		flag = cmd.Flags().Lookup(name)
+		assert.NotNil(flag)
+		assert.Equal("", flag.DefValue)
+	}
+
+	flag = cmd.Flags().Lookup(common.FlagNameTTL)
+	assert.NotNil(flag)
+	assert.Equal(common.DefaultBootstrapTokenTTL.String(), flag.DefValue)
+
+	flag = cmd.Flags().Lookup(common.FlagNameUsages)
+	assert.NotNil(flag)
+	assert.Equal("1", flag.DefValue)
 }
 
 func TestNewGettokenOptions(t *testing.T) {
@@ -65,6 +79,35 @@ func TestNewGettokenOptions(t *testing.T) {
 
 	assert.NotNil(opts)
 	assert.Equal(common.DefaultKubeConfig, opts.Kubeconfig)
+	assert.Equal(common.DefaultBootstrapTokenTTL, opts.TTL)
+	assert.Equal(1, opts.Usages)
+}
+
+func TestCreateBootstrapTokenInvalidOptions(t *testing.T) {
+	cases := []struct {
+		name string
+		opts *common.GettokenOptions
+	}{
+		{
+			name: "both node name and pattern",
+			opts: &common.GettokenOptions{NodeName: "edge-1", NodeNamePattern: "edge-*", TTL: common.DefaultBootstrapTokenTTL, Usages: 1},
+		},
+		{
+			name: "non-positive ttl",
+			opts: &common.GettokenOptions{NodeName: "edge-1", Usages: 1},
+		},
+		{
+			name: "non-positive usages",
+			opts: &common.GettokenOptions{NodeName: "edge-1", TTL: common.DefaultBootstrapTokenTTL},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := createBootstrapToken("kubeedge", test.opts)
+			assert.Error(t, err)
+		})
+	}
 }
 
 func TestShowToken(t *testing.T) {
@@ -53,6 +53,13 @@ func NewCloudInit() *cobra.Command {
 		Long:    cloudInitLongDescription,
 		Example: fmt.Sprintf(cloudInitExample, types.DefaultKubeEdgeVersion),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Bundle != "" {
+				bundle, err := openBundle(&opts.CloudInitUpdateBase)
+				if err != nil {
+					return err
+				}
+				defer bundle.Close()
+			}
 			tool := helm.NewCloudCoreHelmTool(opts.KubeConfig, opts.KubeEdgeVersion)
 			return tool.Install(opts)
 		},
@@ -61,6 +68,7 @@ func NewCloudInit() *cobra.Command {
 	addInitOtherFlags(cmd, opts)
 	addHelmValueOptionsFlags(cmd, opts)
 	addForceOptionsFlags(cmd, opts)
+	addBundleFlags(cmd, &opts.CloudInitUpdateBase)
 	return cmd
 }
 
@@ -68,6 +76,7 @@ func NewCloudInit() *cobra.Command {
 func newInitOptions() *types.InitOptions {
 	opts := &types.InitOptions{}
 	opts.KubeConfig = types.DefaultKubeConfig
+	opts.RemoteRuntimeEndpoint = constants.DefaultRemoteRuntimeEndpoint
 
 	return opts
 }
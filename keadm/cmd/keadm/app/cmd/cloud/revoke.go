@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/security/revocation"
+)
+
+var (
+	revokeLongDescription = `
+"keadm revoke" command revokes the certificates of an edge node.
+The revocation is recorded in the ConfigMap ` + revocation.ConfigMapName + `, cloudcore disconnects the node immediately,
+stamps the revocation with its own clock and rejects the certificates of the node issued before it. The node has to re-enroll with a new token by running
+"keadm reenroll" on the edge node.
+`
+	revokeExample = `
+keadm revoke --node-name edge-node-1 --reason "node is lost"
+- revoke the certificates of edge-node-1.
+
+keadm revoke --node-name edge-node-1 --undo
+- remove the revocation of edge-node-1, its old certificates are accepted again.
+`
+)
+
+// NewRevoke revokes the certificates of an edge node
+func NewRevoke() *cobra.Command {
+	opts := newRevokeOptions()
+
+	cmd := &cobra.Command{
+		Use:     "revoke",
+		Short:   "To revoke the certificates of an edge node",
+		Long:    revokeLongDescription,
+		Example: revokeExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.NodeName == "" {
+				return fmt.Errorf("--%s is required", common.FlagNameNodeName)
+			}
+			client, err := util.KubeClient(opts.Kubeconfig)
+			if err != nil {
+				return err
+			}
+			if err := revokeNode(context.Background(), client, constants.SystemNamespace, opts); err != nil {
+				fmt.Printf("failed to revoke node %s, err is %s\n", opts.NodeName, err)
+				return err
+			}
+			if opts.Undo {
+				fmt.Printf("revocation of node %s is removed\n", opts.NodeName)
+			} else {
+				fmt.Printf("certificates of node %s are revoked\n", opts.NodeName)
+			}
+			return nil
+		},
+	}
+	addRevokeFlags(cmd, opts)
+	return cmd
+}
+
+func addRevokeFlags(cmd *cobra.Command, revokeOptions *common.RevokeOptions) {
+	cmd.Flags().StringVar(&revokeOptions.Kubeconfig, common.FlagNameKubeConfig, revokeOptions.Kubeconfig,
+		"Use this key to set kube-config path, eg: $HOME/.kube/config")
+	cmd.Flags().StringVar(&revokeOptions.NodeName, common.FlagNameNodeName, revokeOptions.NodeName,
+		"The name of the edge node whose certificates are revoked")
+	cmd.Flags().StringVar(&revokeOptions.Reason, common.FlagNameReason, revokeOptions.Reason,
+		"The reason of the revocation")
+	cmd.Flags().BoolVar(&revokeOptions.Undo, common.FlagNameUndo, revokeOptions.Undo,
+		"Remove the revocation of the edge node instead")
+}
+
+// newRevokeOptions return common options
+func newRevokeOptions() *common.RevokeOptions {
+	opts := &common.RevokeOptions{}
+	opts.Kubeconfig = common.DefaultKubeConfig
+	return opts
+}
+
+// revokeNode records the revocation of the node in the revocation ConfigMap, or removes it if opts.Undo is set.
+// The revocation time is stamped by cloudcore, so the clock of the host running keadm does not matter.
+func revokeNode(ctx context.Context, client kubernetes.Interface, namespace string, opts *common.RevokeOptions) error {
+	value, err := revocation.NewPendingEntry(opts.Reason).Encode()
+	if err != nil {
+		return err
+	}
+	configMaps := client.CoreV1().ConfigMaps(namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(ctx, revocation.ConfigMapName, metaV1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			if opts.Undo {
+				return nil
+			}
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metaV1.ObjectMeta{Name: revocation.ConfigMapName, Namespace: namespace},
+				Data:       map[string]string{opts.NodeName: value},
+			}
+			_, err = configMaps.Create(ctx, cm, metaV1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		if opts.Undo {
+			if _, ok := cm.Data[opts.NodeName]; !ok {
+				return nil
+			}
+			delete(cm.Data, opts.NodeName)
+		} else {
+			if cm.Data == nil {
+				cm.Data = make(map[string]string)
+			}
+			cm.Data[opts.NodeName] = value
+		}
+		_, err = configMaps.Update(ctx, cm, metaV1.UpdateOptions{})
+		return err
+	})
+}
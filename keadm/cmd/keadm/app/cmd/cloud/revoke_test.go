@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/pkg/security/revocation"
+)
+
+func TestNewRevoke(t *testing.T) {
+	assert := assert.New(t)
+
+	cmd := NewRevoke()
+	assert.Equal("revoke", cmd.Use)
+	assert.NotNil(cmd.RunE)
+
+	for _, name := range []string{common.FlagNameKubeConfig, common.FlagNameNodeName, common.FlagNameReason, common.FlagNameUndo} {
+		assert.NotNil(cmd.Flags().Lookup(name), name)
+	}
+	assert.Equal(common.DefaultKubeConfig, cmd.Flags().Lookup(common.FlagNameKubeConfig).DefValue)
+}
+
+func TestRevokeNode(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+
+	for _, node := range []string{"edge-1", "edge-2"} {
+		err := revokeNode(ctx, client, constants.SystemNamespace, &common.RevokeOptions{NodeName: node, Reason: "lost"})
+		assert.NoError(err)
+	}
+	cm, err := client.CoreV1().ConfigMaps(constants.SystemNamespace).Get(ctx, revocation.ConfigMapName, metaV1.GetOptions{})
+	assert.NoError(err)
+	entries, errs := revocation.Parse(cm.Data)
+	assert.Empty(errs)
+	assert.Len(entries, 2)
+	assert.Equal("lost", entries["edge-1"].Reason)
+	assert.True(entries["edge-1"].Pending(), "the revocation time is stamped by cloudcore")
+
+	err = revokeNode(ctx, client, constants.SystemNamespace, &common.RevokeOptions{NodeName: "edge-1", Undo: true})
+	assert.NoError(err)
+	cm, err = client.CoreV1().ConfigMaps(constants.SystemNamespace).Get(ctx, revocation.ConfigMapName, metaV1.GetOptions{})
+	assert.NoError(err)
+	assert.NotContains(cm.Data, "edge-1")
+	assert.Contains(cm.Data, "edge-2")
+}
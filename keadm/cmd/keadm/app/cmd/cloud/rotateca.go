@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/security/carotation"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+var (
+	rotateCALongDescription = `
+"keadm rotate-ca" command starts the rotation of the CA of cloudcore without downtime.
+A new CA is created and saved in the CA secret, the old CA is kept as the retiring CA. Cloudcore
+signs certificates by the new CA and publishes both CAs to edge nodes, edge nodes rotate their
+certificates, and the old CA is retired once all registered nodes use certificates signed by the new CA.
+Offline nodes hold the rotation back until they connect, or until the rotation is forced, in which case
+they have to join again with a token. The progress is recorded in the ConfigMap ` + carotation.ConfigMapName + `.
+`
+	rotateCAExample = `
+keadm rotate-ca --kube-config /root/.kube/config
+- start the rotation of the CA.
+
+keadm rotate-ca --status
+- print the progress of the CA rotation.
+
+keadm rotate-ca --force
+- retire the old CA of the rotation in progress without waiting for the offline nodes.
+`
+)
+
+// NewRotateCA starts the rotation of the CA of cloudcore
+func NewRotateCA() *cobra.Command {
+	opts := newRotateCAOptions()
+
+	cmd := &cobra.Command{
+		Use:     "rotate-ca",
+		Short:   "To rotate the CA of cloudcore",
+		Long:    rotateCALongDescription,
+		Example: rotateCAExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := util.KubeClient(opts.Kubeconfig)
+			if err != nil {
+				return err
+			}
+			if opts.Status {
+				return showRotationStatus(context.Background(), client, constants.SystemNamespace)
+			}
+			if opts.Force {
+				if err := forceCARotation(context.Background(), client, constants.SystemNamespace); err != nil {
+					fmt.Printf("failed to force the CA rotation, err is %s\n", err)
+					return err
+				}
+				fmt.Println("CA rotation is forced, the old CA is retired once all connected nodes are rotated")
+				return nil
+			}
+			if err := startCARotation(context.Background(), client, constants.SystemNamespace, time.Now()); err != nil {
+				fmt.Printf("failed to rotate the CA, err is %s\n", err)
+				return err
+			}
+			fmt.Println("CA rotation is started")
+			return nil
+		},
+	}
+	addRotateCAFlags(cmd, opts)
+	return cmd
+}
+
+func addRotateCAFlags(cmd *cobra.Command, rotateCAOptions *common.RotateCAOptions) {
+	cmd.Flags().StringVar(&rotateCAOptions.Kubeconfig, common.FlagNameKubeConfig, rotateCAOptions.Kubeconfig,
+		"Use this key to set kube-config path, eg: $HOME/.kube/config")
+	cmd.Flags().BoolVar(&rotateCAOptions.Status, common.FlagNameStatus, rotateCAOptions.Status,
+		"Print the progress of the CA rotation instead")
+	cmd.Flags().BoolVar(&rotateCAOptions.Force, common.FlagNameForce, rotateCAOptions.Force,
+		"Retire the old CA of the rotation in progress without waiting for the offline nodes")
+}
+
+// newRotateCAOptions return common options
+func newRotateCAOptions() *common.RotateCAOptions {
+	opts := &common.RotateCAOptions{}
+	opts.Kubeconfig = common.DefaultKubeConfig
+	return opts
+}
+
+// startCARotation creates a new CA, saves it in the CA secret with the old CA as the retiring CA,
+// and records the start of the rotation in the status ConfigMap
+func startCARotation(ctx context.Context, client kubernetes.Interface, namespace string, now time.Time) error {
+	secrets := client.CoreV1().Secrets(namespace)
+	caSecret, err := secrets.Get(ctx, common.CaSecretName, metaV1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get the CA of cloudcore, err: %v", err)
+	}
+	if _, ok := caSecret.Data[carotation.RetiringCaDataName]; ok {
+		return errors.New("a CA rotation is in progress")
+	}
+
+	h := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	pk, err := h.GenPrivateKey()
+	if err != nil {
+		return err
+	}
+	caPem, err := h.NewSelfSigned(pk)
+	if err != nil {
+		return fmt.Errorf("failed to create Certificate Authority, error: %v", err)
+	}
+
+	caSecret.Data[carotation.RetiringCaDataName] = caSecret.Data[common.CaDataName]
+	caSecret.Data[carotation.RetiringCaKeyDataName] = caSecret.Data[common.CaKeyDataName]
+	caSecret.Data[common.CaDataName] = caPem.Bytes
+	caSecret.Data[common.CaKeyDataName] = pk.DER()
+	// the update fails if the secret is changed since it is read, so two rotations can not be started at once
+	if _, err := secrets.Update(ctx, caSecret, metaV1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to save the new CA, err: %v", err)
+	}
+
+	status := &carotation.Status{Phase: carotation.PhaseRotating, StartedAt: now}
+	cm, err := status.ToConfigMap(namespace)
+	if err != nil {
+		return err
+	}
+	configMaps := client.CoreV1().ConfigMaps(namespace)
+	if _, err = configMaps.Create(ctx, cm, metaV1.CreateOptions{}); apierrors.IsAlreadyExists(err) {
+		_, err = configMaps.Update(ctx, cm, metaV1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save the status of CA rotation, err: %v", err)
+	}
+	return nil
+}
+
+// forceCARotation marks the rotation in progress as forced, so cloudcore retires the old CA
+// without waiting for the offline nodes
+func forceCARotation(ctx context.Context, client kubernetes.Interface, namespace string) error {
+	configMaps := client.CoreV1().ConfigMaps(namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(ctx, carotation.ConfigMapName, metaV1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return errors.New("no CA rotation is in progress")
+		}
+		if err != nil {
+			return err
+		}
+		status, err := carotation.StatusFromConfigMap(cm)
+		if err != nil {
+			return err
+		}
+		if status.Phase != carotation.PhaseRotating {
+			return errors.New("no CA rotation is in progress")
+		}
+		status.Force = true
+		newCM, err := status.ToConfigMap(namespace)
+		if err != nil {
+			return err
+		}
+		newCM.ResourceVersion = cm.ResourceVersion
+		_, err = configMaps.Update(ctx, newCM, metaV1.UpdateOptions{})
+		return err
+	})
+}
+
+// showRotationStatus prints the progress of the CA rotation
+func showRotationStatus(ctx context.Context, client kubernetes.Interface, namespace string) error {
+	cm, err := client.CoreV1().ConfigMaps(namespace).Get(ctx, carotation.ConfigMapName, metaV1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		fmt.Println("CA has never been rotated")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	status, err := carotation.StatusFromConfigMap(cm)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Phase: %s\nStarted at: %s\n", status.Phase, status.StartedAt.Format(time.RFC3339))
+	if status.Phase == carotation.PhaseCompleted {
+		fmt.Printf("Completed at: %s\n", status.CompletedAt.Format(time.RFC3339))
+		return nil
+	}
+	pending := status.PendingNodes()
+	fmt.Printf("Rotated nodes: %d/%d\n", len(status.Nodes)-len(pending), len(status.Nodes))
+	if len(pending) > 0 {
+		fmt.Printf("Pending nodes: %s\n", strings.Join(pending, ", "))
+	}
+	if offline := status.OfflineNodes(); len(offline) > 0 {
+		fmt.Printf("Offline nodes: %s\n", strings.Join(offline, ", "))
+		if status.Force {
+			fmt.Println("The rotation is forced, offline nodes have to join again after it completes")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/pkg/security/carotation"
+)
+
+func TestStartCARotation(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: common.CaSecretName, Namespace: constants.SystemNamespace},
+		Data: map[string][]byte{
+			common.CaDataName:    []byte("old-ca"),
+			common.CaKeyDataName: []byte("old-key"),
+		},
+	})
+	now := time.Now().UTC().Truncate(time.Second)
+
+	assert.NoError(startCARotation(ctx, client, constants.SystemNamespace, now))
+
+	secret, err := client.CoreV1().Secrets(constants.SystemNamespace).Get(ctx, common.CaSecretName, metaV1.GetOptions{})
+	assert.NoError(err)
+	assert.Equal([]byte("old-ca"), secret.Data[carotation.RetiringCaDataName])
+	assert.Equal([]byte("old-key"), secret.Data[carotation.RetiringCaKeyDataName])
+	assert.NotEqual([]byte("old-ca"), secret.Data[common.CaDataName])
+	assert.NotEmpty(secret.Data[common.CaKeyDataName])
+
+	cm, err := client.CoreV1().ConfigMaps(constants.SystemNamespace).Get(ctx, carotation.ConfigMapName, metaV1.GetOptions{})
+	assert.NoError(err)
+	status, err := carotation.StatusFromConfigMap(cm)
+	assert.NoError(err)
+	assert.Equal(carotation.PhaseRotating, status.Phase)
+	assert.True(now.Equal(status.StartedAt))
+
+	assert.Error(startCARotation(ctx, client, constants.SystemNamespace, now),
+		"expected an error when a CA rotation is in progress")
+
+	assert.NoError(forceCARotation(ctx, client, constants.SystemNamespace))
+	cm, err = client.CoreV1().ConfigMaps(constants.SystemNamespace).Get(ctx, carotation.ConfigMapName, metaV1.GetOptions{})
+	assert.NoError(err)
+	status, err = carotation.StatusFromConfigMap(cm)
+	assert.NoError(err)
+	assert.True(status.Force)
+	assert.True(now.Equal(status.StartedAt))
+}
+
+func TestForceCARotationWithoutRotation(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	assert.Error(t, forceCARotation(context.Background(), client, constants.SystemNamespace))
+}
+
+func TestNewRotateCA(t *testing.T) {
+	assert := assert.New(t)
+
+	cmd := NewRotateCA()
+	assert.Equal("rotate-ca", cmd.Use)
+	assert.NotNil(cmd.RunE)
+	assert.NotNil(cmd.Flags().Lookup(common.FlagNameStatus))
+	assert.NotNil(cmd.Flags().Lookup(common.FlagNameForce))
+	assert.Equal(common.DefaultKubeConfig, cmd.Flags().Lookup(common.FlagNameKubeConfig).DefValue)
+}
@@ -19,6 +19,7 @@ package cloud
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/kubeedge/kubeedge/common/constants"
 	types "github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/helm"
 )
@@ -32,18 +33,27 @@ func NewCloudUpgrade() *cobra.Command {
 		Long: "Upgrade the cloud components to the desired version, " +
 			"it uses helm to upgrade the installed release of cloudcore chart, which includes all the cloud components",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Bundle != "" {
+				bundle, err := openBundle(&opts.CloudInitUpdateBase)
+				if err != nil {
+					return err
+				}
+				defer bundle.Close()
+			}
 			tool := helm.NewCloudCoreHelmTool(opts.KubeConfig, opts.KubeEdgeVersion)
 			return tool.Upgrade(opts)
 		},
 	}
 
 	addUpgradeOptionFlags(cmd, opts)
+	addBundleFlags(cmd, &opts.CloudInitUpdateBase)
 	return cmd
 }
 
 func newCloudUpgradeOptions() *types.CloudUpgradeOptions {
 	opts := &types.CloudUpgradeOptions{}
 	opts.KubeConfig = types.DefaultKubeConfig
+	opts.RemoteRuntimeEndpoint = constants.DefaultRemoteRuntimeEndpoint
 	return opts
 }
 
@@ -77,6 +77,8 @@ func NewKubeedgeCommand() *cobra.Command {
 
 	cmds.AddCommand(NewCmdVersion())
 	cmds.AddCommand(cloud.NewGettoken())
+	cmds.AddCommand(cloud.NewRevoke())
+	cmds.AddCommand(cloud.NewRotateCA())
 	cmds.AddCommand(debug.NewEdgeDebug())
 
 	// recommended cmds
@@ -84,6 +86,7 @@ func NewKubeedgeCommand() *cobra.Command {
 	cmds.AddCommand(cloud.NewCloudInit())
 	cmds.AddCommand(cloud.NewManifestGenerate())
 	cmds.AddCommand(newCmdConfig())
+	cmds.AddCommand(newCmdBundle())
 	cmds.AddCommand(NewKubeEdgeReset())
 
 	// beta cmds
@@ -91,6 +94,7 @@ func NewKubeedgeCommand() *cobra.Command {
 	cmds.AddCommand(NewUpgradeCommand())
 
 	cmds.AddCommand(edge.NewEdgeRollback())
+	cmds.AddCommand(edge.NewEdgeReenroll())
 
 	cmds.AddCommand(ctl.NewCtl())
 
@@ -16,6 +16,8 @@ limitations under the License.
 
 package common
 
+import "time"
+
 // Common flag names
 const (
 	// FlagNameForce force install
@@ -23,6 +25,12 @@ const (
 
 	// FlagNameKubeEdgeVersion sets the version of KubeEdge to be used
 	FlagNameKubeEdgeVersion = "kubeedge-version"
+
+	// FlagNameBundle sets the path of the offline installation bundle created by "keadm bundle create"
+	FlagNameBundle = "bundle"
+
+	// FlagNameSkipVerify allows the offline installation bundle without its checksum file
+	FlagNameSkipVerify = "skip-verify"
 )
 
 // Cloud init and upgrade common flag names
@@ -74,6 +82,39 @@ const (
 	FlagNameReuseValues = "reuse-values"
 )
 
+// Cloud gettoken flag names
+const (
+	// FlagNameNodeName creates a bootstrap token for the node
+	FlagNameNodeName = "node-name"
+
+	// FlagNameNodeNamePattern creates a bootstrap token for the nodes matching the pattern
+	FlagNameNodeNamePattern = "node-name-pattern"
+
+	// FlagNameNodeGroup creates a bootstrap token for the nodes of the node group
+	FlagNameNodeGroup = "node-group"
+
+	// FlagNameTTL sets the time to live of the bootstrap token
+	FlagNameTTL = "ttl"
+
+	// FlagNameUsages sets the number of times the bootstrap token can be used
+	FlagNameUsages = "usages"
+)
+
+// Cloud rotate-ca flag names
+const (
+	// FlagNameStatus prints the status of the CA rotation
+	FlagNameStatus = "status"
+)
+
+// Cloud revoke flag names
+const (
+	// FlagNameReason sets the reason of the certificate revocation
+	FlagNameReason = "reason"
+
+	// FlagNameUndo removes the certificate revocation of the node
+	FlagNameUndo = "undo"
+)
+
 // Edge join flag names
 const (
 	// FlagNameImageRepository sets the image repository to pull images
@@ -130,6 +171,14 @@ const (
 	TokenSecretName = "tokensecret"
 	TokenDataName   = "tokendata"
 
+	// CA secret
+	CaSecretName  = "casecret"
+	CaDataName    = "cadata"
+	CaKeyDataName = "cakeydata"
+
+	// DefaultBootstrapTokenTTL is the default time to live of bootstrap tokens
+	DefaultBootstrapTokenTTL = 24 * time.Hour
+
 	StrCheck    = "check"
 	StrDiagnose = "diagnose"
 
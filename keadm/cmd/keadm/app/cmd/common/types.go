@@ -19,6 +19,7 @@ package common
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/blang/semver"
 )
@@ -36,6 +37,15 @@ type CloudInitUpdateBase struct {
 	DryRun           bool
 	PrintFinalValues bool
 	ImageRepository  string
+
+	// Bundle is the path of the offline installation bundle, the charts and images are installed from it
+	Bundle string
+	// SkipVerify allows the bundle without its checksum file
+	SkipVerify bool
+	// RemoteRuntimeEndpoint is the endpoint of the container runtime the images in the bundle are imported to
+	RemoteRuntimeEndpoint string
+	// BundleChartsDir is the directory of the charts extracted from the bundle, it is set when the bundle is opened
+	BundleChartsDir string
 }
 
 const requiredSetSplitLen = 2
@@ -69,6 +79,15 @@ func (b CloudInitUpdateBase) HasSets(key string) bool {
 	return false
 }
 
+// HelmRoot returns the root directory of the charts, the external helm root takes precedence over the bundle,
+// the builtin charts are used if both are empty
+func (b CloudInitUpdateBase) HelmRoot() string {
+	if b.ExternalHelmRoot != "" {
+		return b.ExternalHelmRoot
+	}
+	return b.BundleChartsDir
+}
+
 // InitOptions defines cloud init flags
 type InitOptions struct {
 	Manifests string
@@ -102,6 +121,10 @@ type JoinOptions struct {
 	ImageRepository string
 	HubProtocol     string
 	TarballPath     string
+	// Bundle is the path of the offline installation bundle, edgecore and images are installed from it
+	Bundle string
+	// SkipVerify allows the bundle without its checksum file
+	SkipVerify bool
 }
 
 type CheckOptions struct {
@@ -135,7 +158,30 @@ type ResetOptions struct {
 }
 
 type GettokenOptions struct {
+	Kubeconfig      string
+	NodeName        string
+	NodeNamePattern string
+	NodeGroup       string
+	TTL             time.Duration
+	Usages          int
+}
+
+type RevokeOptions struct {
 	Kubeconfig string
+	NodeName   string
+	Reason     string
+	Undo       bool
+}
+
+type RotateCAOptions struct {
+	Kubeconfig string
+	Status     bool
+	Force      bool
+}
+
+type ReenrollOptions struct {
+	Token  string
+	Config string
 }
 
 type DiagnoseOptions struct {
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/admin/api"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/ctl/client"
+)
+
+var (
+	edgeReconnectShortDescription  = `Force edgecore to reconnect to cloud`
+	edgeResyncShortDescription     = `Resync the configmaps and secrets stored in edge node from cloud`
+	edgeFlushCacheShortDescription = `Flush the image mirror cache in edge node`
+	edgeReconcileShortDescription  = `Send the digests of the objects cached in edge node to cloud to fix the drifted ones`
+)
+
+// NewEdgeReconnect returns KubeEdge edgecore reconnect command.
+func NewEdgeReconnect() *cobra.Command {
+	return newAction("reconnect", edgeReconnectShortDescription, api.ReconnectPath)
+}
+
+// NewEdgeResync returns KubeEdge edgecore resync command.
+func NewEdgeResync() *cobra.Command {
+	return newAction("resync", edgeResyncShortDescription, api.ResyncPath)
+}
+
+// NewEdgeFlushCache returns KubeEdge edgecore flush cache command.
+func NewEdgeFlushCache() *cobra.Command {
+	return newAction("flush-cache", edgeFlushCacheShortDescription, api.FlushCachePath)
+}
+
+// NewEdgeReconcile returns KubeEdge edgecore reconcile command.
+func NewEdgeReconcile() *cobra.Command {
+	return newAction("reconcile", edgeReconcileShortDescription, api.ReconcilePath)
+}
+
+func newAction(use, description, path string) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: description,
+		Long:  description,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdutil.CheckErr(run(path))
+			return nil
+		},
+	}
+}
+
+func run(path string) error {
+	adminClient, err := client.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	var result api.ActionResult
+	if err := adminClient.Post(context.Background(), path, &result); err != nil {
+		return err
+	}
+	fmt.Println(formatResult(result))
+	return nil
+}
+
+func formatResult(result api.ActionResult) string {
+	if result.Succeeded == 0 && result.Failed == 0 {
+		return result.Message
+	}
+	return fmt.Sprintf("%s, succeeded: %d, failed: %d", result.Message, result.Succeeded, result.Failed)
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/admin/api"
+)
+
+func TestNewActions(t *testing.T) {
+	assert := assert.New(t)
+	cases := []struct {
+		cmd         *cobra.Command
+		use         string
+		description string
+	}{
+		{cmd: NewEdgeReconnect(), use: "reconnect", description: edgeReconnectShortDescription},
+		{cmd: NewEdgeResync(), use: "resync", description: edgeResyncShortDescription},
+		{cmd: NewEdgeFlushCache(), use: "flush-cache", description: edgeFlushCacheShortDescription},
+		{cmd: NewEdgeReconcile(), use: "reconcile", description: edgeReconcileShortDescription},
+	}
+	for _, c := range cases {
+		assert.NotNil(c.cmd)
+		assert.Equal(c.use, c.cmd.Use)
+		assert.Equal(c.description, c.cmd.Short)
+		assert.Equal(c.description, c.cmd.Long)
+	}
+}
+
+func TestFormatResult(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("reconnecting to cloud", formatResult(api.ActionResult{Message: "reconnecting to cloud"}))
+	assert.Equal("resources resynced from cloud, succeeded: 2, failed: 1",
+		formatResult(api.ActionResult{Message: "resources resynced from cloud", Succeeded: 2, Failed: 1}))
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/edge/pkg/admin/api"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	keadutil "github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+// AdminClient requests the local admin API of edgecore
+type AdminClient struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewAdminClient returns the client of the admin API configured in the edgecore config,
+// the unix socket is preferred over the HTTPS address
+func NewAdminClient() (*AdminClient, error) {
+	config, err := keadutil.ParseEdgecoreConfig(common.EdgecoreConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("get edge config failed with err:%v", err)
+	}
+	return NewAdminClientWithConfig(config.Modules.Admin)
+}
+
+// NewAdminClientWithConfig returns the client of the admin API configured by c
+func NewAdminClientWithConfig(c *v1alpha2.Admin) (*AdminClient, error) {
+	if c == nil || !c.Enable {
+		return nil, fmt.Errorf("admin API of edgecore is not enabled")
+	}
+
+	if c.UnixSocket != "" {
+		return &AdminClient{
+			client: &http.Client{
+				Transport: &http.Transport{
+					DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+						var d net.Dialer
+						return d.DialContext(ctx, "unix", c.UnixSocket)
+					},
+				},
+				Timeout: 1 * time.Minute,
+			},
+			baseURL: "http://localhost",
+		}, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSPrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	caPEM, err := os.ReadFile(c.TLSCAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificate found in %s", c.TLSCAFile)
+	}
+	return &AdminClient{
+		client: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					Certificates: []tls.Certificate{cert},
+					RootCAs:      pool,
+					MinVersion:   tls.VersionTLS12,
+				},
+			},
+			Timeout: 1 * time.Minute,
+		},
+		baseURL: "https://" + c.Address,
+	}, nil
+}
+
+// Get requests the path and decodes the response into out
+func (c *AdminClient) Get(ctx context.Context, path string, out interface{}) error {
+	return c.do(ctx, http.MethodGet, path, out)
+}
+
+// Post requests the action of the path and decodes the response into out
+func (c *AdminClient) Post(ctx context.Context, path string, out interface{}) error {
+	return c.do(ctx, http.MethodPost, path, out)
+}
+
+// Download requests the path and writes the response body to w
+func (c *AdminClient) Download(ctx context.Context, path string, w io.Writer) error {
+	body, err := c.request(ctx, http.MethodGet, path)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	_, err = io.Copy(w, body)
+	return err
+}
+
+func (c *AdminClient) do(ctx context.Context, method, path string, out interface{}) error {
+	body, err := c.request(ctx, method, path)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(body).Decode(out)
+}
+
+// request returns the body of the successful response
+func (c *AdminClient) request(ctx context.Context, method, path string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request admin API of edgecore: %v", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		var errResp api.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Error == "" {
+			return nil, fmt.Errorf("admin API of edgecore returns %s", resp.Status)
+		}
+		return nil, fmt.Errorf("%s", errResp.Error)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/edge/pkg/admin/api"
+)
+
+func TestNewAdminClientDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewAdminClientWithConfig(nil)
+	assert.Error(err)
+
+	_, err = NewAdminClientWithConfig(&v1alpha2.Admin{Enable: false, UnixSocket: "/tmp/admin.sock"})
+	assert.Error(err)
+}
+
+func TestAdminClientUnixSocket(t *testing.T) {
+	assert := assert.New(t)
+
+	socket := filepath.Join(t.TempDir(), "admin.sock")
+	listener, err := net.Listen("unix", socket)
+	assert.NoError(err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(api.StatusPath, func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(api.Status{PendingUpstream: 3})
+	})
+	mux.HandleFunc(api.ReconnectPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(api.ErrorResponse{Error: "edgehub is not connected to cloud"})
+	})
+	server := httptest.NewUnstartedServer(mux)
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	c, err := NewAdminClientWithConfig(&v1alpha2.Admin{Enable: true, UnixSocket: socket})
+	assert.NoError(err)
+
+	var status api.Status
+	assert.NoError(c.Get(context.Background(), api.StatusPath, &status))
+	assert.Equal(3, status.PendingUpstream)
+
+	var result api.ActionResult
+	err = c.Post(context.Background(), api.ReconnectPath, &result)
+	assert.EqualError(err, "edgehub is not connected to cloud")
+}
@@ -19,8 +19,10 @@ package ctl
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/ctl/action"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/ctl/get"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/ctl/restart"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/ctl/status"
 )
 
 var (
@@ -37,5 +39,10 @@ func NewCtl() *cobra.Command {
 
 	cmd.AddCommand(get.NewEdgeGet())
 	cmd.AddCommand(restart.NewEdgeRestart())
+	cmd.AddCommand(status.NewEdgeStatus())
+	cmd.AddCommand(action.NewEdgeReconnect())
+	cmd.AddCommand(action.NewEdgeResync())
+	cmd.AddCommand(action.NewEdgeFlushCache())
+	cmd.AddCommand(action.NewEdgeReconcile())
 	return cmd
 }
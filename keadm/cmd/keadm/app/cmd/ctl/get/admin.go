@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package get
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/admin/api"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/ctl/client"
+)
+
+var (
+	edgeQueueGetShortDescription  = `Get the message queues of the modules in edge node`
+	edgeDeviceGetShortDescription = `Get the devices stored in edge node`
+	edgeMapperGetShortDescription = `Get the mappers registered to edge node`
+)
+
+// AdminGetOptions are the options of the resources got from the admin API of edgecore
+type AdminGetOptions struct {
+	Output string
+}
+
+// NewEdgeQueueGet returns KubeEdge edge message queue get command.
+func NewEdgeQueueGet() *cobra.Command {
+	return newAdminGet("queue", edgeQueueGetShortDescription, api.QueuesPath, &api.Queues{}, printQueues)
+}
+
+// NewEdgeDeviceGet returns KubeEdge edge device get command.
+func NewEdgeDeviceGet() *cobra.Command {
+	return newAdminGet("device", edgeDeviceGetShortDescription, api.DevicesPath, &[]api.Device{}, printDevices)
+}
+
+// NewEdgeMapperGet returns KubeEdge edge mapper get command.
+func NewEdgeMapperGet() *cobra.Command {
+	return newAdminGet("mapper", edgeMapperGetShortDescription, api.MappersPath, &api.Mappers{}, printMappers)
+}
+
+func newAdminGet(use, description, path string, out interface{}, print func(io.Writer, interface{}) error) *cobra.Command {
+	options := &AdminGetOptions{}
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: description,
+		Long:  description,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdutil.CheckErr(options.get(path, out, print))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&options.Output, common.FlagNameOutput, "o", options.Output,
+		"Output format. One of: (json). Print a table if not set")
+	return cmd
+}
+
+func (o *AdminGetOptions) get(path string, out interface{}, print func(io.Writer, interface{}) error) error {
+	adminClient, err := client.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	if err := adminClient.Get(context.Background(), path, out); err != nil {
+		return err
+	}
+	switch o.Output {
+	case "":
+		return print(os.Stdout, out)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(out)
+	default:
+		return fmt.Errorf("unsupported output format %q", o.Output)
+	}
+}
+
+func printQueues(out io.Writer, v interface{}) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "MODULE\tPOLICY\tCAPACITY\tDEPTH\tSPILLED\tENQUEUED\tDROPPED\tMAX-WAIT")
+	for _, q := range *v.(*api.Queues) {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%s\n",
+			q.Module, q.Policy, q.Capacity, q.Depth, q.Spilled, q.Enqueued, q.Dropped, q.MaxEnqueueWait)
+	}
+	return w.Flush()
+}
+
+func printDevices(out io.Writer, v interface{}) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATE\tLAST-ONLINE")
+	for _, d := range *v.(*[]api.Device) {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", d.ID, d.Name, d.State, d.LastOnline)
+	}
+	return w.Flush()
+}
+
+func printMappers(out io.Writer, v interface{}) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPROTOCOL\tSTATE\tLAST-HEARTBEAT\tREASON")
+	for _, m := range *v.(*api.Mappers) {
+		heartbeat := ""
+		if m.LastHeartbeatTime > 0 {
+			heartbeat = time.UnixMilli(m.LastHeartbeatTime).Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", m.Name, m.Protocol, m.State, heartbeat, m.Reason)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package get
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kubeedge/beehive/pkg/core/channel"
+	"github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/types"
+	"github.com/kubeedge/kubeedge/edge/pkg/admin/api"
+)
+
+func TestNewEdgeAdminGet(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("queue", NewEdgeQueueGet().Use)
+	assert.Equal("device", NewEdgeDeviceGet().Use)
+	assert.Equal("mapper", NewEdgeMapperGet().Use)
+	assert.NotNil(NewEdgeQueueGet().Flags().Lookup("output"))
+}
+
+func TestPrintAdminResources(t *testing.T) {
+	assert := assert.New(t)
+
+	var out bytes.Buffer
+	assert.NoError(printQueues(&out, &api.Queues{channel.QueueStats{Module: "websocket", Policy: channel.OverflowBlock, Capacity: 1024, Depth: 3}}))
+	assert.Contains(out.String(), "websocket   Block    1024       3")
+
+	out.Reset()
+	assert.NoError(printDevices(&out, &[]api.Device{{ID: "dev1", Name: "sensor", State: "online"}}))
+	assert.Contains(out.String(), "dev1   sensor   online")
+
+	out.Reset()
+	assert.NoError(printMappers(&out, &api.Mappers{types.MapperStatus{Name: "modbus", Protocol: "modbus", State: "offline", Reason: "heartbeat timeout"}}))
+	assert.Contains(out.String(), "modbus   modbus     offline")
+	assert.Contains(out.String(), "heartbeat timeout")
+}
@@ -31,5 +31,8 @@ func NewEdgeGet() *cobra.Command {
 	}
 
 	cmd.AddCommand(NewEdgePodGet())
+	cmd.AddCommand(NewEdgeQueueGet())
+	cmd.AddCommand(NewEdgeDeviceGet())
+	cmd.AddCommand(NewEdgeMapperGet())
 	return cmd
 }
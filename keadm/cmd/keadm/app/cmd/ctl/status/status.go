@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/kubeedge/kubeedge/edge/pkg/admin/api"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/ctl/client"
+)
+
+var (
+	edgeStatusShortDescription = `Show the status of edgecore in edge node`
+)
+
+type StatusOptions struct {
+	Output string
+}
+
+// NewEdgeStatus returns KubeEdge edgecore status command.
+func NewEdgeStatus() *cobra.Command {
+	options := &StatusOptions{}
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: edgeStatusShortDescription,
+		Long:  edgeStatusShortDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdutil.CheckErr(options.status())
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&options.Output, common.FlagNameOutput, "o", options.Output,
+		"Output format. One of: (json). Print a summary if not set")
+	return cmd
+}
+
+func (o *StatusOptions) status() error {
+	adminClient, err := client.NewAdminClient()
+	if err != nil {
+		return err
+	}
+	var status api.Status
+	if err := adminClient.Get(context.Background(), api.StatusPath, &status); err != nil {
+		return err
+	}
+	switch o.Output {
+	case "":
+		return printStatus(os.Stdout, &status)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(&status)
+	default:
+		return fmt.Errorf("unsupported output format %q", o.Output)
+	}
+}
+
+func printStatus(out io.Writer, status *api.Status) error {
+	connection := "disconnected"
+	since := status.Connection.LastDisconnectedTime
+	if status.Connection.Connected {
+		connection = "connected"
+		since = status.Connection.LastConnectedTime
+	}
+	fmt.Fprintf(out, "Cloud connection:  %s%s\n", connection, formatSince(since))
+	fmt.Fprintf(out, "Pending upstream:  %d\n", status.PendingUpstream)
+	if drift := status.Drift; drift != nil {
+		fmt.Fprintf(out, "Drift detection:   last digest sent at %s", formatTime(drift.LastSentTime))
+		if r := drift.LastResult; r != nil {
+			fmt.Fprintf(out, ", cloud fixed %d missing, %d stale, %d extra objects at %s",
+				r.Missing, r.Stale, r.Extra, formatTime(drift.LastResultTime))
+		}
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "MODULE\tGROUP\tRUNNING\tRESTARTS\tSTARTED")
+	for _, m := range status.Modules {
+		fmt.Fprintf(w, "%s\t%s\t%t\t%d\t%s\n", m.Name, m.Group, m.Running, m.Restarts, formatTime(m.StartTime))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(status.LastErrors) == 0 {
+		return nil
+	}
+	fmt.Fprintln(out, "\nLast errors:")
+	for _, e := range status.LastErrors {
+		fmt.Fprintf(out, "  %s  %s: %s\n", formatTime(e.Time), e.Component, e.Error)
+	}
+	return nil
+}
+
+func formatSince(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return " since " + formatTime(t)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
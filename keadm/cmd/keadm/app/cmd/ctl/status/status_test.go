@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kubeedge/beehive/pkg/core"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/pkg/admin/api"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
+)
+
+func TestNewEdgeStatus(t *testing.T) {
+	assert := assert.New(t)
+	cmd := NewEdgeStatus()
+
+	assert.NotNil(cmd)
+	assert.Equal("status", cmd.Use)
+	assert.Equal(edgeStatusShortDescription, cmd.Short)
+	assert.Equal(edgeStatusShortDescription, cmd.Long)
+	assert.NotNil(cmd.Flags().Lookup("output"))
+}
+
+func TestPrintStatus(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	status := &api.Status{
+		Modules: []core.ModuleStatus{{Name: "websocket", Group: "hub", Running: true, StartTime: now}},
+		Connection: cloudconnection.ConnectionStatus{
+			Connected:         true,
+			LastConnectedTime: now,
+		},
+		PendingUpstream: 2,
+		LastErrors:      []api.LastError{{Component: "websocket", Error: "i/o timeout", Time: now}},
+		Drift: &api.DriftStatus{
+			LastSentTime:   now,
+			LastResultTime: now,
+			LastResult:     &commontypes.MetaDigestResult{Stale: 1, Extra: 2},
+		},
+	}
+
+	var out bytes.Buffer
+	assert.NoError(printStatus(&out, status))
+	assert.Contains(out.String(), "Cloud connection:  connected since 2024-01-02T03:04:05Z")
+	assert.Contains(out.String(), "Pending upstream:  2")
+	assert.Contains(out.String(), "Drift detection:   last digest sent at 2024-01-02T03:04:05Z, cloud fixed 0 missing, 1 stale, 2 extra objects")
+	assert.Contains(out.String(), "websocket   hub     true")
+	assert.Contains(out.String(), "2024-01-02T03:04:05Z  websocket: i/o timeout")
+}
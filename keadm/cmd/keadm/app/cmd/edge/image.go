@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
@@ -57,6 +58,34 @@ func request(opt *common.JoinOptions, step *common.Step) error {
 	return nil
 }
 
+// requestFromBundle imports the edge images and copies edgecore from the offline bundle
+func requestFromBundle(opt *common.JoinOptions, step *common.Step) error {
+	if opt.WithMQTT {
+		return fmt.Errorf("the deprecated mqtt broker can not be installed from the bundle")
+	}
+	step.Printf("Verify the offline bundle")
+	bundle, err := util.OpenBundle(opt.Bundle, opt.SkipVerify)
+	if err != nil {
+		return err
+	}
+	defer bundle.Close()
+	if opt.KubeEdgeVersion != "" && strings.TrimPrefix(opt.KubeEdgeVersion, "v") != strings.TrimPrefix(bundle.Manifest.Version, "v") {
+		return fmt.Errorf("version %s mismatches the version %s of the bundle", opt.KubeEdgeVersion, bundle.Manifest.Version)
+	}
+	opt.KubeEdgeVersion = bundle.Manifest.Version
+
+	step.Printf("Import Images")
+	if err := bundle.ImportImages(opt.RemoteRuntimeEndpoint, util.BundlePartEdge); err != nil {
+		return fmt.Errorf("import images failed: %v", err)
+	}
+
+	step.Printf("Copy resources from the bundle to the management directory")
+	if err := copyFile(bundle.Path(util.BundleEdgeCoreFile), filepath.Join(util.KubeEdgeUsrBinPath, util.KubeEdgeBinaryName)); err != nil {
+		return fmt.Errorf("copy resources failed: %v", err)
+	}
+	return nil
+}
+
 func createMQTTConfigFile() error {
 	dir := filepath.Join(util.KubeEdgeSocketPath, image.EdgeMQTT, "config")
 	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
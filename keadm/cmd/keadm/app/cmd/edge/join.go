@@ -85,11 +85,14 @@ func NewEdgeJoin() *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ver, err := util.GetCurrentVersion(joinOptions.KubeEdgeVersion)
-			if err != nil {
-				return fmt.Errorf("edge node join failed: %v", err)
+			// the version is taken from the bundle if it is specified
+			if joinOptions.Bundle == "" {
+				ver, err := util.GetCurrentVersion(joinOptions.KubeEdgeVersion)
+				if err != nil {
+					return fmt.Errorf("edge node join failed: %v", err)
+				}
+				joinOptions.KubeEdgeVersion = ver
 			}
-			joinOptions.KubeEdgeVersion = ver
 
 			if err := join(joinOptions, step); err != nil {
 				return fmt.Errorf("edge node join failed: %v", err)
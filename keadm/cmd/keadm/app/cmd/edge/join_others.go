@@ -75,6 +75,12 @@ func AddJoinOtherFlags(cmd *cobra.Command, joinOptions *common.JoinOptions) {
 	cmd.Flags().StringVar(&joinOptions.TarballPath, common.FlagNameTarballPath, joinOptions.TarballPath,
 		"Use this key to set the temp directory path for KubeEdge tarball, if not exist, download it")
 
+	cmd.Flags().StringVar(&joinOptions.Bundle, common.FlagNameBundle, joinOptions.Bundle,
+		"Use this key to install edgecore and images from the offline bundle created by 'keadm bundle create' without accessing the internet")
+
+	cmd.Flags().BoolVar(&joinOptions.SkipVerify, common.FlagNameSkipVerify, joinOptions.SkipVerify,
+		"Use this key to allow the offline bundle without its checksum file, only the files in the bundle are verified then")
+
 	cmd.Flags().StringSliceVarP(&joinOptions.Labels, common.FlagNameLabels, "l", joinOptions.Labels,
 		`Use this key to set the customized labels for node, you can input customized labels like key1=value1,key2=value2`)
 
@@ -188,7 +194,11 @@ func join(opt *common.JoinOptions, step *common.Step) error {
 
 	// Do not create any files in the management directory,
 	// you need to mount the contents of the mirror first.
-	if err := request(opt, step); err != nil {
+	if opt.Bundle != "" {
+		if err := requestFromBundle(opt, step); err != nil {
+			return err
+		}
+	} else if err := request(opt, step); err != nil {
 		return err
 	}
 	step.Printf("Generate systemd service file")
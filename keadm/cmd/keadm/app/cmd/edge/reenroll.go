@@ -0,0 +1,123 @@
+//go:build !windows
+
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edge
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+var (
+	edgeReenrollDescription = `
+"keadm reenroll" command forces the edge node to run the bootstrap flow again.
+The certificate of edgecore is removed and edgecore is restarted with the token, so it applies
+for a new certificate from cloudcore. It is used after the certificates of the node are revoked
+by "keadm revoke".
+`
+	edgeReenrollExample = `
+keadm reenroll --token=<token created by keadm gettoken>
+`
+)
+
+// NewEdgeReenroll returns KubeEdge edge reenroll command.
+func NewEdgeReenroll() *cobra.Command {
+	opts := &common.ReenrollOptions{
+		Config: constants.DefaultConfigDir + "edgecore.yaml",
+	}
+	step := common.NewStep()
+
+	cmd := &cobra.Command{
+		Use:          "reenroll",
+		Short:        "Re-enroll the edge node. Apply for a new certificate with the token and restart edgecore",
+		Long:         edgeReenrollDescription,
+		Example:      edgeReenrollExample,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Token == "" {
+				return fmt.Errorf("--%s is required", common.FlagNameToken)
+			}
+			return reenroll(opts, step)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Token, common.FlagNameToken, "t", opts.Token,
+		"Use this key to set the token used to apply for the new certificate")
+	cmd.Flags().StringVar(&opts.Config, "config", opts.Config,
+		"Use this key to specify the path to the edgecore configuration file.")
+	return cmd
+}
+
+func reenroll(opts *common.ReenrollOptions, step *common.Step) error {
+	config, err := util.ParseEdgecoreConfig(opts.Config)
+	if err != nil {
+		return fmt.Errorf("failed to parse edgecore config %s: %v", opts.Config, err)
+	}
+	hub := config.Modules.EdgeHub
+
+	step.Printf("Stop EdgeCore")
+	if err := util.KillKubeEdgeBinary(util.KubeEdgeBinaryName); err != nil {
+		return fmt.Errorf("failed to stop edgecore: %v", err)
+	}
+
+	step.Printf("Remove the revoked certificate")
+	for _, file := range []string{hub.TLSCertFile, hub.TLSPrivateKeyFile} {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %v", file, err)
+		}
+	}
+
+	// write token to bootstrap configure file
+	if err := createBootstrapFile(&common.JoinOptions{Token: opts.Token}); err != nil {
+		return fmt.Errorf("create bootstrap file failed: %v", err)
+	}
+	// Delete the bootstrap file, so the credential used for TLS bootstrap is removed from disk
+	defer os.Remove(constants.BootstrapFile)
+
+	// the service file is removed when edgecore is stopped
+	if util.HasSystemd() {
+		err = common.GenerateServiceFile(util.KubeEdgeBinaryName, fmt.Sprintf("%s --config %s", filepath.Join(util.KubeEdgeUsrBinPath, util.KubeEdgeBinaryName), opts.Config), false)
+		if err != nil {
+			return fmt.Errorf("failed to create edgecore.service file: %v", err)
+		}
+	}
+
+	step.Printf("Run EdgeCore daemon")
+	if err := runEdgeCore(false); err != nil {
+		return fmt.Errorf("start edgecore failed: %v", err)
+	}
+
+	// wait for edgecore to apply for the new certificate with the token
+	err = wait.Poll(10*time.Second, 300*time.Second, func() (bool, error) {
+		return util.FileExists(hub.TLSCertFile) && util.FileExists(hub.TLSPrivateKeyFile), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wait for the new certificate: %v", err)
+	}
+	step.Printf("Re-enroll Complete!")
+	return nil
+}
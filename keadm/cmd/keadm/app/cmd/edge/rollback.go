@@ -18,7 +18,10 @@ package edge
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
@@ -41,6 +44,9 @@ func NewEdgeRollback() *cobra.Command {
 		Short: "rollback edge component. Rollback the edge node to the desired version.",
 		Long:  "Rollback edge component. Rollback the edge node to the desired version.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if rollbackOptions.List {
+				return listBackups(cmd.OutOrStdout())
+			}
 			// rollback edge core
 			return rollbackEdgeCore(rollbackOptions)
 		},
@@ -82,7 +88,7 @@ func rollbackEdgeCore(ro *RollbackOptions) error {
 		}
 	}()
 
-	rbErr := rollback(ro.HistoryVersion, configure.DataBase.DataSource, ro.Config)
+	rbErr := rollback(ro.HistoryVersion, configure, ro.Config)
 	if rbErr != nil {
 		event.Action = api.ActionFailure
 		event.Msg = fmt.Sprintf("upgrade error: %v, rollback error: %v", err, rbErr)
@@ -91,16 +97,40 @@ func rollbackEdgeCore(ro *RollbackOptions) error {
 	return nil
 }
 
+// listBackups prints the retained backups which the edge node can be rolled back to
+func listBackups(out io.Writer) error {
+	manifests, err := util.ListBackups(util.KubeEdgeBackupPath)
+	if err != nil {
+		return fmt.Errorf("failed to list backups: %v", err)
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tCREATED\tVERIFIED")
+	for _, manifest := range manifests {
+		verified := "yes"
+		if _, err := util.VerifyBackup(util.KubeEdgeBackupPath, manifest.Version); err != nil {
+			verified = "no: " + err.Error()
+		} else if len(manifest.Checksums) == 0 {
+			verified = "no checksums"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", manifest.Version, manifest.CreatedAt.Format(time.RFC3339), verified)
+	}
+	return w.Flush()
+}
+
 type RollbackOptions struct {
 	HistoryVersion string
 	TaskType       string
 	TaskName       string
 	Config         string
+	List           bool
 }
 
 func addRollbackFlags(cmd *cobra.Command, rollbackOptions *RollbackOptions) {
 	cmd.Flags().StringVar(&rollbackOptions.HistoryVersion, "history", rollbackOptions.HistoryVersion,
-		"Use this key to specify the origin version before upgrade")
+		"Use this key to specify the origin version before upgrade, it can be any retained backup version listed by --list")
+
+	cmd.Flags().BoolVar(&rollbackOptions.List, "list", rollbackOptions.List,
+		"Use this key to list the retained backup versions instead of rolling back.")
 
 	cmd.Flags().StringVar(&rollbackOptions.Config, "config", rollbackOptions.Config,
 		"Use this key to specify the path to the edgecore configuration file.")
@@ -17,10 +17,12 @@ limitations under the License.
 package edge
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
@@ -29,11 +31,16 @@ import (
 	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
 	api "github.com/kubeedge/api/apis/fsm/v1alpha1"
 	"github.com/kubeedge/kubeedge/common/constants"
+	adminapi "github.com/kubeedge/kubeedge/edge/pkg/admin/api"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/ctl/client"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
 	"github.com/kubeedge/kubeedge/pkg/util/fsm"
 )
 
+// adminProbeTimeout is the timeout to check the admin API of edgecore is reachable
+const adminProbeTimeout = 5 * time.Second
+
 var (
 	// idempotencyRecord is a file that is used to avoid upgrading node twice once a time.
 	// If the file exist, we don't allow upgrade node again
@@ -64,6 +71,7 @@ func NewUpgradeOptions() *UpgradeOptions {
 	opts := &UpgradeOptions{}
 	opts.ToVersion = "v" + common.DefaultKubeEdgeVersion
 	opts.Config = constants.DefaultConfigDir + "edgecore.yaml"
+	opts.BackupRetention = util.DefaultBackupRetention
 
 	return opts
 }
@@ -90,8 +98,12 @@ func (up *UpgradeOptions) Upgrade() error {
 		TaskType:       up.TaskType,
 		Image:          up.Image,
 		DisableBackup:  up.DisableBackup,
+		Bundle:         up.Bundle,
+		SkipVerify:     up.SkipVerify,
 		ConfigFilePath: up.Config,
 		EdgeCoreConfig: configure,
+
+		BackupRetention: up.BackupRetention,
 	}
 
 	event := &fsm.Event{
@@ -154,27 +166,53 @@ func (up *UpgradeOptions) Upgrade() error {
 	return nil
 }
 
+// boltSnapshot returns the function getting the snapshot of the bbolt database from the admin API of
+// the running edgecore holding it. It returns nil if the admin API is unreachable, the database is
+// copied directly then, which fails if edgecore is running and holds the database.
+func boltSnapshot(config *v1alpha2.EdgeCoreConfig, boltDataSource string) func(w io.Writer) error {
+	if boltDataSource == "" {
+		return nil
+	}
+	adminClient, err := client.NewAdminClientWithConfig(config.Modules.Admin)
+	if err != nil {
+		klog.Warningf("back up bbolt database without edgecore: %v", err)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), adminProbeTimeout)
+	defer cancel()
+	var status adminapi.Status
+	if err := adminClient.Get(ctx, adminapi.StatusPath, &status); err != nil {
+		klog.Warningf("back up bbolt database without edgecore: %v", err)
+		return nil
+	}
+	return func(w io.Writer) error {
+		return adminClient.Download(context.Background(), adminapi.BackupBoltPath, w)
+	}
+}
+
 func (up *Upgrade) PreProcess() error {
 	// download the request version edgecore
 	klog.Infof("Begin to download version %s edgecore", up.ToVersion)
 	if !up.DisableBackup {
-		backupPath := filepath.Join(util.KubeEdgeBackupPath, up.FromVersion)
-		if err := os.MkdirAll(backupPath, 0750); err != nil {
-			return fmt.Errorf("mkdirall failed: %v", err)
+		boltDataSource, encryptionKeyFile := util.MetaDataFiles(up.EdgeCoreConfig)
+		_, err := util.BackupEdgeNode(util.BackupOptions{
+			BackupPath:        util.KubeEdgeBackupPath,
+			Version:           up.FromVersion,
+			DataSource:        up.EdgeCoreConfig.DataBase.DataSource,
+			ConfigFile:        up.ConfigFilePath,
+			BinaryFile:        filepath.Join(util.KubeEdgeUsrBinPath, util.KubeEdgeBinaryName),
+			BoltDataSource:    boltDataSource,
+			BoltSnapshot:      boltSnapshot(up.EdgeCoreConfig, boltDataSource),
+			EncryptionKeyFile: encryptionKeyFile,
+			Retention:         up.BackupRetention,
+		})
+		if err != nil {
+			return err
 		}
+	}
 
-		// backup edgecore.db: copy from origin path to backup path
-		if err := copyFile(up.EdgeCoreConfig.DataBase.DataSource, filepath.Join(backupPath, "edgecore.db")); err != nil {
-			return fmt.Errorf("failed to backup db: %v", err)
-		}
-		// backup edgecore.yaml: copy from origin path to backup path
-		if err := copyFile(up.ConfigFilePath, filepath.Join(backupPath, "edgecore.yaml")); err != nil {
-			return fmt.Errorf("failed to back config: %v", err)
-		}
-		// backup edgecore: copy from origin path to backup path
-		if err := copyFile(filepath.Join(util.KubeEdgeUsrBinPath, util.KubeEdgeBinaryName), filepath.Join(backupPath, util.KubeEdgeBinaryName)); err != nil {
-			return fmt.Errorf("failed to backup edgecore: %v", err)
-		}
+	if up.Bundle != "" {
+		return up.preProcessFromBundle()
 	}
 
 	upgradePath := filepath.Join(util.KubeEdgeUpgradePath, up.ToVersion)
@@ -201,6 +239,30 @@ func (up *Upgrade) PreProcess() error {
 	return nil
 }
 
+// preProcessFromBundle imports the edge images and copies edgecore from the offline bundle,
+// the edge node is upgraded to the version of the bundle
+func (up *Upgrade) preProcessFromBundle() error {
+	bundle, err := util.OpenBundle(up.Bundle, up.SkipVerify)
+	if err != nil {
+		return err
+	}
+	defer bundle.Close()
+	up.ToVersion = bundle.Manifest.Version
+
+	endpoint := up.EdgeCoreConfig.Modules.Edged.TailoredKubeletConfig.ContainerRuntimeEndpoint
+	if err := bundle.ImportImages(endpoint, util.BundlePartEdge); err != nil {
+		return fmt.Errorf("failed to import images of the bundle: %v", err)
+	}
+	upgradePath := filepath.Join(util.KubeEdgeUpgradePath, up.ToVersion)
+	if err := os.MkdirAll(upgradePath, 0750); err != nil {
+		return err
+	}
+	if err := copyFile(bundle.Path(util.BundleEdgeCoreFile), filepath.Join(upgradePath, util.KubeEdgeBinaryName)); err != nil {
+		return fmt.Errorf("failed to cp file from bundle to host: %v", err)
+	}
+	return nil
+}
+
 func copyFile(src, dst string) error {
 	sourceFileStat, err := os.Stat(src)
 	if err != nil {
@@ -262,32 +324,36 @@ func (up *Upgrade) Process() error {
 }
 
 func (up *Upgrade) Rollback() error {
-	return rollback(up.FromVersion, up.EdgeCoreConfig.DataBase.DataSource, up.ConfigFilePath)
+	return rollback(up.FromVersion, up.EdgeCoreConfig, up.ConfigFilePath)
 }
 
-func rollback(HistoryVersion, dataSource, configFilePath string) error {
+func rollback(HistoryVersion string, config *v1alpha2.EdgeCoreConfig, configFilePath string) error {
 	klog.Infof("upgrade rollback process start")
 
+	// verify the backup before stopping edgecore, so the node keeps running if the backup is corrupted
+	if _, err := util.VerifyBackup(util.KubeEdgeBackupPath, HistoryVersion); err != nil {
+		return err
+	}
+
 	// stop edgecore
 	err := util.KillKubeEdgeBinary(util.KubeEdgeBinaryName)
 	if err != nil {
 		return fmt.Errorf("failed to stop edgecore: %v", err)
 	}
 
-	// rollback origin config/db/binary
-
-	// backup edgecore.db: copy from backup path to origin path
-	backupPath := filepath.Join(util.KubeEdgeBackupPath, HistoryVersion)
-	if err := copyFile(filepath.Join(backupPath, "edgecore.db"), dataSource); err != nil {
-		return fmt.Errorf("failed to rollback db: %v", err)
-	}
-	// backup edgecore.yaml: copy from backup path to origin path
-	if err := copyFile(filepath.Join(backupPath, "edgecore.yaml"), configFilePath); err != nil {
-		return fmt.Errorf("failed to back config: %v", err)
-	}
-	// backup edgecore: copy from backup path to origin path
-	if err := copyFile(filepath.Join(backupPath, util.KubeEdgeBinaryName), filepath.Join(util.KubeEdgeUsrBinPath, util.KubeEdgeBinaryName)); err != nil {
-		return fmt.Errorf("failed to backup edgecore: %v", err)
+	// rollback origin config/db/binary, and the bbolt db and encryption key if they are backed up
+	boltDataSource, encryptionKeyFile := util.MetaDataFiles(config)
+	err = util.RestoreBackup(util.BackupOptions{
+		BackupPath:        util.KubeEdgeBackupPath,
+		Version:           HistoryVersion,
+		DataSource:        config.DataBase.DataSource,
+		ConfigFile:        configFilePath,
+		BinaryFile:        filepath.Join(util.KubeEdgeUsrBinPath, util.KubeEdgeBinaryName),
+		BoltDataSource:    boltDataSource,
+		EncryptionKeyFile: encryptionKeyFile,
+	})
+	if err != nil {
+		return err
 	}
 
 	// generate edgecore.service
@@ -323,6 +389,12 @@ type UpgradeOptions struct {
 	Image         string
 	DisableBackup bool
 	TaskType      string
+	// Bundle is the path of the offline installation bundle, edgecore and images are upgraded from it
+	Bundle string
+	// SkipVerify allows the bundle without its checksum file
+	SkipVerify bool
+
+	BackupRetention int
 }
 
 type Upgrade struct {
@@ -332,9 +404,13 @@ type Upgrade struct {
 	ToVersion      string
 	Image          string
 	DisableBackup  bool
+	Bundle         string
+	SkipVerify     bool
 	ConfigFilePath string
 	TaskType       string
 	EdgeCoreConfig *v1alpha2.EdgeCoreConfig
+	// BackupRetention is the number of backups retained after the backup before upgrade
+	BackupRetention int
 
 	Status string
 	Reason string
@@ -365,4 +441,13 @@ func AddUpgradeFlags(cmd *cobra.Command, upgradeOptions *UpgradeOptions) {
 
 	cmd.Flags().BoolVar(&upgradeOptions.DisableBackup, "disable-backup", upgradeOptions.DisableBackup,
 		"Use this key to specify the backup enable for upgrade.")
+
+	cmd.Flags().StringVar(&upgradeOptions.Bundle, common.FlagNameBundle, upgradeOptions.Bundle,
+		"Use this key to upgrade to the version of the offline bundle created by 'keadm bundle create' without accessing the internet")
+
+	cmd.Flags().BoolVar(&upgradeOptions.SkipVerify, common.FlagNameSkipVerify, upgradeOptions.SkipVerify,
+		"Use this key to allow the offline bundle without its checksum file, only the files in the bundle are verified then")
+
+	cmd.Flags().IntVar(&upgradeOptions.BackupRetention, "backup-retention", upgradeOptions.BackupRetention,
+		"Use this key to specify the number of backups retained on the edge node, older backups are pruned.")
 }
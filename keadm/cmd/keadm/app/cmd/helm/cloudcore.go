@@ -150,7 +150,7 @@ func (c *CloudCoreHelmTool) Install(opts *types.InitOptions) error {
 	componentName := cloudCoreHelmComponent
 
 	// Build a new renderer instance
-	renderer := NewGenericRenderer(kecharts.BuiltinOrDir(opts.ExternalHelmRoot),
+	renderer := NewGenericRenderer(kecharts.BuiltinOrDir(opts.HelmRoot()),
 		subDir, componentName, constants.SystemNamespace, vals, opts.SkipCRDs)
 	// Load the charts to this renderer
 	if err := renderer.LoadChart(); err != nil {
@@ -278,7 +278,7 @@ func (c *CloudCoreHelmTool) Upgrade(opts *types.CloudUpgradeOptions) error {
 	}
 
 	// Build a new renderer instance
-	renderer := NewGenericRenderer(kecharts.BuiltinOrDir(""),
+	renderer := NewGenericRenderer(kecharts.BuiltinOrDir(opts.BundleChartsDir),
 		subDir, componentName, constants.SystemNamespace, vals, false)
 	// Load the charts to this renderer
 	if err := renderer.LoadChart(); err != nil {
@@ -0,0 +1,479 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	bolt "go.etcd.io/bbolt"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+const (
+	// BackupManifestFile is the name of the manifest file in each backup
+	BackupManifestFile = "manifest.json"
+	// DefaultBackupRetention is the default number of backups retained on the edge node
+	DefaultBackupRetention = 3
+
+	// names of the files in each backup
+	BackupDBFile     = "edgecore.db"
+	BackupConfigFile = "edgecore.yaml"
+	// names of the optional files, they are in the backup if the node is configured with them
+	BackupBoltFile          = "edgecore-meta.bolt"
+	BackupEncryptionKeyFile = "encryption.key"
+
+	// boltOpenTimeout is the timeout to open the bbolt database, it is locked while edgecore is running
+	boltOpenTimeout = time.Second
+)
+
+// BackupManifest describes a backup of the edge node, it is stored with the backup files
+type BackupManifest struct {
+	// Version is the version of edgecore backed up, it is also the name of the backup directory
+	Version string `json:"version"`
+	// CreatedAt is the time the backup is created
+	CreatedAt time.Time `json:"createdAt"`
+	// Checksums are the sha256 checksums of the backup files
+	Checksums map[string]string `json:"checksums"`
+}
+
+// BackupOptions specifies the files of the edge node to back up
+type BackupOptions struct {
+	// BackupPath is the directory holding all backups
+	BackupPath string
+	// Version is the version of edgecore to back up
+	Version string
+	// DataSource is the path of the edgecore database
+	DataSource string
+	// ConfigFile is the path of edgecore.yaml
+	ConfigFile string
+	// BinaryFile is the path of the edgecore binary
+	BinaryFile string
+	// BoltDataSource is the path of the bbolt database of the metadata, it is empty if the metadata are stored in sqlite
+	BoltDataSource string
+	// BoltSnapshot writes a snapshot of the bbolt database through the running edgecore holding it,
+	// it is nil if edgecore is stopped
+	BoltSnapshot func(w io.Writer) error
+	// EncryptionKeyFile is the path of the key file of the database encryption, it is empty if there is none
+	EncryptionKeyFile string
+	// Retention is the number of backups to retain, older backups are pruned
+	Retention int
+}
+
+// BackupEdgeNode backs up the database, config and binary of edgecore, and the bbolt database and the
+// encryption key file if they are configured. The database is copied with the SQLite online backup API
+// so it is consistent even if edgecore is running.
+// The backup is written to a temporary directory and renamed, so a failed backup never replaces a good one.
+func BackupEdgeNode(opts BackupOptions) (*BackupManifest, error) {
+	if opts.Version == "" || opts.Version != filepath.Base(opts.Version) || strings.HasPrefix(opts.Version, ".") {
+		return nil, fmt.Errorf("invalid backup version %q", opts.Version)
+	}
+	klog.Infof("backup start, backup path: %s", filepath.Join(opts.BackupPath, opts.Version))
+	if err := os.MkdirAll(opts.BackupPath, 0750); err != nil {
+		return nil, fmt.Errorf("mkdirall failed: %v", err)
+	}
+	tmpPath, err := os.MkdirTemp(opts.BackupPath, "."+opts.Version+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary backup directory: %v", err)
+	}
+	defer os.RemoveAll(tmpPath)
+
+	if err := BackupSQLite(opts.DataSource, filepath.Join(tmpPath, BackupDBFile)); err != nil {
+		return nil, fmt.Errorf("failed to backup db: %v", err)
+	}
+	if err := copyBackupFile(opts.ConfigFile, filepath.Join(tmpPath, BackupConfigFile)); err != nil {
+		return nil, fmt.Errorf("failed to backup config: %v", err)
+	}
+	if err := copyBackupFile(opts.BinaryFile, filepath.Join(tmpPath, KubeEdgeBinaryName)); err != nil {
+		return nil, fmt.Errorf("failed to backup edgecore: %v", err)
+	}
+	names := []string{BackupDBFile, BackupConfigFile, KubeEdgeBinaryName}
+	if opts.BoltDataSource != "" {
+		if err := BackupBolt(opts.BoltDataSource, filepath.Join(tmpPath, BackupBoltFile), opts.BoltSnapshot); err != nil {
+			return nil, fmt.Errorf("failed to backup bbolt db: %v", err)
+		}
+		names = append(names, BackupBoltFile)
+	}
+	if opts.EncryptionKeyFile != "" {
+		if err := copyBackupFile(opts.EncryptionKeyFile, filepath.Join(tmpPath, BackupEncryptionKeyFile)); err != nil {
+			return nil, fmt.Errorf("failed to backup encryption key: %v", err)
+		}
+		names = append(names, BackupEncryptionKeyFile)
+	}
+
+	manifest := &BackupManifest{
+		Version:   opts.Version,
+		CreatedAt: time.Now().UTC(),
+		Checksums: map[string]string{},
+	}
+	for _, name := range names {
+		if manifest.Checksums[name], err = fileChecksum(filepath.Join(tmpPath, name)); err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(tmpPath, BackupManifestFile), data, 0640); err != nil {
+		return nil, fmt.Errorf("failed to write backup manifest: %v", err)
+	}
+
+	backupPath := filepath.Join(opts.BackupPath, opts.Version)
+	if err := os.RemoveAll(backupPath); err != nil {
+		return nil, fmt.Errorf("failed to remove the old backup of version %s: %v", opts.Version, err)
+	}
+	if err := os.Rename(tmpPath, backupPath); err != nil {
+		return nil, fmt.Errorf("failed to save backup: %v", err)
+	}
+
+	retention := opts.Retention
+	if retention <= 0 {
+		retention = DefaultBackupRetention
+	}
+	if err := PruneBackups(opts.BackupPath, retention); err != nil {
+		klog.Warningf("failed to prune backups: %v", err)
+	}
+	return manifest, nil
+}
+
+// BackupSQLite copies the SQLite database with the online backup API
+func BackupSQLite(src, dst string) error {
+	if _, err := os.Stat(src); err != nil {
+		return err
+	}
+	srcDB, err := sql.Open("sqlite3", src)
+	if err != nil {
+		return err
+	}
+	defer srcDB.Close()
+	dstDB, err := sql.Open("sqlite3", dst)
+	if err != nil {
+		return err
+	}
+	defer dstDB.Close()
+
+	ctx := context.Background()
+	srcConn, err := srcDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close()
+	dstConn, err := dstDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer dstConn.Close()
+
+	return dstConn.Raw(func(dstDriverConn interface{}) error {
+		return srcConn.Raw(func(srcDriverConn interface{}) error {
+			dstSQLite, ok := dstDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected connection type %T", dstDriverConn)
+			}
+			srcSQLite, ok := srcDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected connection type %T", srcDriverConn)
+			}
+			backup, err := dstSQLite.Backup("main", srcSQLite, "main")
+			if err != nil {
+				return err
+			}
+			// copy all pages in one step, so the snapshot is consistent
+			if _, err := backup.Step(-1); err != nil {
+				backup.Close()
+				return err
+			}
+			return backup.Finish()
+		})
+	})
+}
+
+// BackupBolt backs up the bbolt database. The database is locked by edgecore while it is running,
+// so the snapshot is written by edgecore through snapshot and checked. If snapshot is nil edgecore
+// must be stopped, the database is opened read-only and copied in a read transaction.
+func BackupBolt(src, dst string, snapshot func(w io.Writer) error) error {
+	if snapshot == nil {
+		db, err := bolt.Open(src, 0600, &bolt.Options{ReadOnly: true, Timeout: boltOpenTimeout})
+		if err != nil {
+			return fmt.Errorf("failed to open bbolt database %s, it is locked if edgecore is running: %v", src, err)
+		}
+		defer db.Close()
+		return db.View(func(tx *bolt.Tx) error {
+			return tx.CopyFile(dst, 0600)
+		})
+	}
+
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if err := snapshot(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to get the snapshot of bbolt database %s from edgecore: %v", src, err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := checkBolt(dst); err != nil {
+		return fmt.Errorf("snapshot of bbolt database %s is inconsistent: %v", src, err)
+	}
+	return nil
+}
+
+// checkBolt checks the consistency of the pages of the bbolt database
+func checkBolt(path string) error {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.View(func(tx *bolt.Tx) error {
+		var errs []error
+		for err := range tx.Check() {
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// ListBackups returns the manifests of the backups sorted from the newest to the oldest.
+// Backups created before manifests were introduced have only the version.
+func ListBackups(backupPath string) ([]BackupManifest, error) {
+	entries, err := os.ReadDir(backupPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var manifests []BackupManifest
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		manifest, err := readBackupManifest(filepath.Join(backupPath, entry.Name()))
+		if err != nil {
+			klog.Warningf("skip invalid backup %s: %v", entry.Name(), err)
+			continue
+		}
+		manifests = append(manifests, *manifest)
+	}
+	sort.SliceStable(manifests, func(i, j int) bool {
+		return manifests[i].CreatedAt.After(manifests[j].CreatedAt)
+	})
+	return manifests, nil
+}
+
+// PruneBackups removes the oldest backups until at most retention backups are left
+func PruneBackups(backupPath string, retention int) error {
+	manifests, err := ListBackups(backupPath)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i := retention; i < len(manifests); i++ {
+		klog.Infof("prune backup of version %s created at %s", manifests[i].Version, manifests[i].CreatedAt.Format(time.RFC3339))
+		if err := os.RemoveAll(filepath.Join(backupPath, manifests[i].Version)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// VerifyBackup checks the files of the backup match the checksums in its manifest
+func VerifyBackup(backupPath, version string) (*BackupManifest, error) {
+	dir := filepath.Join(backupPath, version)
+	manifest, err := readBackupManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{BackupDBFile, BackupConfigFile, KubeEdgeBinaryName}
+	for _, name := range []string{BackupBoltFile, BackupEncryptionKeyFile} {
+		if _, ok := manifest.Checksums[name]; ok {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return nil, fmt.Errorf("backup of version %s is incomplete: %v", version, err)
+		}
+		expected, ok := manifest.Checksums[name]
+		if !ok {
+			// the backup is created before manifests were introduced
+			continue
+		}
+		checksum, err := fileChecksum(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if checksum != expected {
+			return nil, fmt.Errorf("checksum of %s in backup of version %s mismatches, the backup is corrupted", name, version)
+		}
+	}
+	return manifest, nil
+}
+
+// RestoreBackup verifies the backup of opts.Version and copies its files back to the paths in opts,
+// edgecore must be stopped. The bbolt database and the encryption key file are restored if they are backed up.
+func RestoreBackup(opts BackupOptions) error {
+	manifest, err := VerifyBackup(opts.BackupPath, opts.Version)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(opts.BackupPath, opts.Version)
+	dataSource := opts.DataSource
+	if err := copyBackupFile(filepath.Join(dir, BackupDBFile), dataSource); err != nil {
+		return fmt.Errorf("failed to rollback db: %v", err)
+	}
+	// remove the journal files of the corrupted database, otherwise they are replayed to the restored one
+	for _, suffix := range []string{"-journal", "-wal", "-shm"} {
+		if err := os.Remove(dataSource + suffix); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %v", dataSource+suffix, err)
+		}
+	}
+	if err := copyBackupFile(filepath.Join(dir, BackupConfigFile), opts.ConfigFile); err != nil {
+		return fmt.Errorf("failed to rollback config: %v", err)
+	}
+	if err := copyBackupFile(filepath.Join(dir, KubeEdgeBinaryName), opts.BinaryFile); err != nil {
+		return fmt.Errorf("failed to rollback edgecore: %v", err)
+	}
+	if _, ok := manifest.Checksums[BackupBoltFile]; ok {
+		if opts.BoltDataSource == "" {
+			return errors.New("failed to rollback bbolt db: the path of the bbolt db is not configured")
+		}
+		if err := copyBackupFile(filepath.Join(dir, BackupBoltFile), opts.BoltDataSource); err != nil {
+			return fmt.Errorf("failed to rollback bbolt db: %v", err)
+		}
+	}
+	if _, ok := manifest.Checksums[BackupEncryptionKeyFile]; ok {
+		if opts.EncryptionKeyFile == "" {
+			return errors.New("failed to rollback encryption key: the path of the key file is not configured")
+		}
+		// the data encrypted by the key can not be decrypted without it
+		if err := os.MkdirAll(filepath.Dir(opts.EncryptionKeyFile), 0700); err != nil {
+			return fmt.Errorf("failed to rollback encryption key: %v", err)
+		}
+		if err := copyBackupFile(filepath.Join(dir, BackupEncryptionKeyFile), opts.EncryptionKeyFile); err != nil {
+			return fmt.Errorf("failed to rollback encryption key: %v", err)
+		}
+	}
+	return nil
+}
+
+// MetaDataFiles returns the bbolt database and the encryption key file of the metadata configured
+// for edgecore, they are empty if the metadata are stored in sqlite or not encrypted by a key file
+func MetaDataFiles(config *v1alpha2.EdgeCoreConfig) (boltDataSource, encryptionKeyFile string) {
+	if config.DataBase != nil && config.DataBase.MetaBackend == v1alpha2.MetaBackendBolt {
+		boltDataSource = config.DataBase.BoltDataSource
+		if boltDataSource == "" {
+			boltDataSource = v1alpha2.DataBaseBoltDataSource
+		}
+	}
+	if config.Modules == nil || config.Modules.MetaManager == nil {
+		return boltDataSource, ""
+	}
+	encryption := config.Modules.MetaManager.Encryption
+	if encryption == nil || !encryption.Enable {
+		return boltDataSource, ""
+	}
+	switch encryption.KeyProvider {
+	case "", v1alpha2.MetaEncryptionProviderLocal, v1alpha2.MetaEncryptionProviderSealed:
+		encryptionKeyFile = encryption.KeyFile
+		if encryptionKeyFile == "" {
+			encryptionKeyFile = constants.DefaultMetaEncryptionKeyFile
+		}
+	}
+	return boltDataSource, encryptionKeyFile
+}
+
+// readBackupManifest reads the manifest of the backup in dir
+func readBackupManifest(dir string) (*BackupManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, BackupManifestFile))
+	if os.IsNotExist(err) {
+		info, statErr := os.Stat(dir)
+		if statErr != nil {
+			return nil, statErr
+		}
+		return &BackupManifest{Version: filepath.Base(dir), CreatedAt: info.ModTime().UTC()}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	manifest := &BackupManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse backup manifest: %v", err)
+	}
+	if manifest.Version != filepath.Base(dir) {
+		return nil, fmt.Errorf("version %s in backup manifest mismatches the backup directory", manifest.Version)
+	}
+	return manifest, nil
+}
+
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// copyBackupFile copies the regular file with its file mode
+func copyBackupFile(src, dst string) error {
+	sourceFileStat, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !sourceFileStat.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", src)
+	}
+	source, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	destination, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_TRUNC, sourceFileStat.Mode())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(destination, source); err != nil {
+		destination.Close()
+		return err
+	}
+	return destination.Close()
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"database/sql"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
+)
+
+func TestBackupAndRestoreEdgeNode(t *testing.T) {
+	dir := t.TempDir()
+	dataSource := filepath.Join(dir, "edgecore.db")
+	configFile := filepath.Join(dir, "edgecore.yaml")
+	binaryFile := filepath.Join(dir, "edgecore")
+	backupPath := filepath.Join(dir, "backup")
+
+	db, err := sql.Open("sqlite3", dataSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE meta (key TEXT PRIMARY KEY, value TEXT); INSERT INTO meta VALUES ('pod', 'v1')`); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configFile, []byte("apiVersion: edgecore.config.kubeedge.io/v1alpha2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(binaryFile, []byte("edgecore v1.17.0"), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := BackupOptions{
+		BackupPath: backupPath,
+		DataSource: dataSource,
+		ConfigFile: configFile,
+		BinaryFile: binaryFile,
+		Retention:  2,
+	}
+	for _, version := range []string{"v1.15.0", "v1.16.0", "v1.17.0"} {
+		opts.Version = version
+		manifest, err := BackupEdgeNode(opts)
+		if err != nil {
+			t.Fatalf("failed to backup version %s: %v", version, err)
+		}
+		if len(manifest.Checksums) != 3 {
+			t.Errorf("expected checksums of 3 files, got %v", manifest.Checksums)
+		}
+	}
+
+	manifests, err := ListBackups(backupPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != 2 || manifests[0].Version != "v1.17.0" || manifests[1].Version != "v1.16.0" {
+		t.Fatalf("expected the 2 newest backups to be retained, got %+v", manifests)
+	}
+
+	// corrupt the database and the config after backup
+	if _, err := db.Exec(`UPDATE meta SET value = 'corrupted'`); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configFile, []byte("corrupted"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	opts.Version = "v1.16.0"
+	if err := RestoreBackup(opts); err != nil {
+		t.Fatalf("failed to restore backup: %v", err)
+	}
+	db, err = sql.Open("sqlite3", dataSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var value string
+	if err := db.QueryRow(`SELECT value FROM meta WHERE key = 'pod'`).Scan(&value); err != nil {
+		t.Fatal(err)
+	}
+	if value != "v1" {
+		t.Errorf("expected the restored db to have value v1, got %s", value)
+	}
+	if data, _ := os.ReadFile(configFile); string(data) == "corrupted" {
+		t.Error("expected the config to be restored")
+	}
+
+	// a corrupted backup can not be restored
+	if err := os.WriteFile(filepath.Join(backupPath, "v1.17.0", BackupConfigFile), []byte("changed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyBackup(backupPath, "v1.17.0"); err == nil {
+		t.Error("expected the corrupted backup to fail verification")
+	}
+	opts.Version = "v1.15.0"
+	if err := RestoreBackup(opts); err == nil {
+		t.Error("expected the pruned backup not to be restored")
+	}
+}
+
+func TestBackupAndRestoreMetaDataFiles(t *testing.T) {
+	dir := t.TempDir()
+	opts := BackupOptions{
+		BackupPath:        filepath.Join(dir, "backup"),
+		Version:           "v1.17.0",
+		DataSource:        filepath.Join(dir, "edgecore.db"),
+		ConfigFile:        filepath.Join(dir, "edgecore.yaml"),
+		BinaryFile:        filepath.Join(dir, "edgecore"),
+		BoltDataSource:    filepath.Join(dir, "edgecore-meta.bolt"),
+		EncryptionKeyFile: filepath.Join(dir, "encryption", "key"),
+	}
+	db, err := sql.Open("sqlite3", opts.DataSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`CREATE TABLE meta (key TEXT PRIMARY KEY, value TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	for _, file := range []string{opts.ConfigFile, opts.BinaryFile} {
+		if err := os.WriteFile(file, []byte(file), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	boltDB, err := bolt.Open(opts.BoltDataSource, 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	putBolt := func(value string) {
+		err := boltDB.Update(func(tx *bolt.Tx) error {
+			bucket, err := tx.CreateBucketIfNotExists([]byte("meta"))
+			if err != nil {
+				return err
+			}
+			return bucket.Put([]byte("pod"), []byte(value))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	putBolt("v1")
+	if err := os.MkdirAll(filepath.Dir(opts.EncryptionKeyFile), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(opts.EncryptionKeyFile, []byte("key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// the bbolt database is locked while it is opened, as edgecore keeps it open
+	if _, err := BackupEdgeNode(opts); err == nil {
+		t.Fatal("expected an error backing up the locked bbolt database without edgecore")
+	}
+	// it is backed up through the open database
+	opts.BoltSnapshot = func(w io.Writer) error {
+		return boltDB.View(func(tx *bolt.Tx) error {
+			_, err := tx.WriteTo(w)
+			return err
+		})
+	}
+	manifest, err := BackupEdgeNode(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.BoltSnapshot = nil
+	if _, ok := manifest.Checksums[BackupBoltFile]; !ok {
+		t.Error("expected the bbolt database to be backed up")
+	}
+	if _, ok := manifest.Checksums[BackupEncryptionKeyFile]; !ok {
+		t.Error("expected the encryption key to be backed up")
+	}
+	putBolt("corrupted")
+	if err := boltDB.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Dir(opts.EncryptionKeyFile)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RestoreBackup(opts); err != nil {
+		t.Fatalf("failed to restore backup: %v", err)
+	}
+	if data, err := os.ReadFile(opts.EncryptionKeyFile); err != nil || string(data) != "key" {
+		t.Errorf("expected the encryption key to be restored, got %q, %v", data, err)
+	}
+	boltDB, err = bolt.Open(opts.BoltDataSource, 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var value string
+	err = boltDB.View(func(tx *bolt.Tx) error {
+		value = string(tx.Bucket([]byte("meta")).Get([]byte("pod")))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "v1" {
+		t.Errorf("expected the restored bbolt db to have value v1, got %s", value)
+	}
+
+	// the bbolt database is copied directly when edgecore is stopped
+	if err := boltDB.Close(); err != nil {
+		t.Fatal(err)
+	}
+	opts.Version = "v1.18.0"
+	if _, err := BackupEdgeNode(opts); err != nil {
+		t.Fatalf("failed to backup the bbolt database of stopped edgecore: %v", err)
+	}
+
+	// the backed up files can not be restored without their paths
+	opts.BoltDataSource = ""
+	if err := RestoreBackup(opts); err == nil {
+		t.Error("expected an error restoring the bbolt db without its path")
+	}
+}
+
+func TestMetaDataFiles(t *testing.T) {
+	config := v1alpha2.NewDefaultEdgeCoreConfig()
+	if bolt, key := MetaDataFiles(config); bolt != "" || key != "" {
+		t.Errorf("expected no bbolt db and key file by default, got %q and %q", bolt, key)
+	}
+	config.DataBase.MetaBackend = v1alpha2.MetaBackendBolt
+	config.Modules.MetaManager.Encryption.Enable = true
+	if bolt, key := MetaDataFiles(config); bolt != config.DataBase.BoltDataSource || key != config.Modules.MetaManager.Encryption.KeyFile {
+		t.Errorf("expected the configured bbolt db and key file, got %q and %q", bolt, key)
+	}
+	config.Modules.MetaManager.Encryption.KeyProvider = v1alpha2.MetaEncryptionProviderKMS
+	if _, key := MetaDataFiles(config); key != "" {
+		t.Errorf("expected no key file of the kms provider, got %q", key)
+	}
+}
+
+func TestBackupEdgeNodeInvalidVersion(t *testing.T) {
+	for _, version := range []string{"", "../v1.17.0", ".hidden"} {
+		if _, err := BackupEdgeNode(BackupOptions{BackupPath: t.TempDir(), Version: version}); err == nil {
+			t.Errorf("expected backup of version %q to fail", version)
+		}
+	}
+}
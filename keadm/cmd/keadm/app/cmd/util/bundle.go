@@ -0,0 +1,516 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/platforms"
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"k8s.io/klog/v2"
+
+	kecharts "github.com/kubeedge/kubeedge/manifests"
+)
+
+const (
+	// BundleManifestFile is the name of the manifest file in the bundle
+	BundleManifestFile = "manifest.json"
+	// BundleChecksumSuffix is the suffix of the checksum file written next to the bundle
+	BundleChecksumSuffix = ".sha256"
+
+	// paths of the contents in the bundle
+	BundleEdgeCoreFile = "bin/edgecore"
+	BundleChartsDir    = "charts"
+	BundleCRDsDir      = "crds"
+	BundleImagesDir    = "images"
+
+	// parts of the images in the bundle, the images of each part are in the OCI archive images/<part>.tar
+	BundlePartCloud = "cloud"
+	BundlePartEdge  = "edge"
+
+	// bundleCRDsPath is the path of the CRDs in the charts
+	bundleCRDsPath = "charts/cloudcore/crds"
+)
+
+// BundleManifest describes an offline installation bundle, it is the first file of the bundle
+type BundleManifest struct {
+	// Version is the KubeEdge version of the bundle, e.g. v1.17.0
+	Version string `json:"version"`
+	// OS and Arch are the platform of the binaries and images in the bundle
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+	// ImageRepository is the repository the images in the bundle come from
+	ImageRepository string `json:"imageRepository,omitempty"`
+	// CreatedAt is the time the bundle is created
+	CreatedAt time.Time `json:"createdAt"`
+	// Images are the images in the bundle of each part
+	Images map[string][]string `json:"images"`
+	// Checksums are the sha256 checksums of the files in the bundle
+	Checksums map[string]string `json:"checksums"`
+}
+
+// BundleOptions specifies the contents of the bundle to create
+type BundleOptions struct {
+	// Version is the KubeEdge version of the bundle, e.g. v1.17.0
+	Version string
+	// Arch is the architecture of the binaries and images, the bundle is always for linux
+	Arch string
+	// ImageRepository is the repository the images come from, it is recorded in the manifest
+	ImageRepository string
+	// Images are the images to save of each part
+	Images map[string][]string
+	// EdgeCoreImage is the image the edgecore binary is copied from, it must be one of the edge images
+	EdgeCoreImage string
+	// OutputDir is the directory the bundle is written to
+	OutputDir string
+}
+
+// BundleFileName returns the file name of the bundle of the version and arch
+func BundleFileName(version, arch string) string {
+	return fmt.Sprintf("kubeedge-bundle-%s-linux-%s.tar.gz", version, arch)
+}
+
+// CreateBundle pulls the images and the edgecore binary from the registries and writes them with the charts
+// and CRDs to a versioned bundle, the sha256 checksum of the bundle is written next to it.
+// It returns the path of the bundle.
+func CreateBundle(ctx context.Context, opts BundleOptions) (string, error) {
+	resolver := docker.NewResolver(docker.ResolverOptions{Hosts: docker.ConfigureDefaultRegistries()})
+	return createBundle(ctx, opts, resolver)
+}
+
+func createBundle(ctx context.Context, opts BundleOptions, resolver remotes.Resolver) (string, error) {
+	if opts.Version == "" || opts.Arch == "" {
+		return "", fmt.Errorf("version and arch of the bundle must be specified")
+	}
+	workDir, err := os.MkdirTemp("", "kubeedge-bundle-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(workDir)
+
+	store, err := local.NewStore(filepath.Join(workDir, "content"))
+	if err != nil {
+		return "", err
+	}
+	bundleDir := filepath.Join(workDir, "bundle")
+	if err := os.MkdirAll(filepath.Join(bundleDir, BundleImagesDir), 0750); err != nil {
+		return "", err
+	}
+
+	platform := ocispec.Platform{OS: "linux", Architecture: opts.Arch}
+	exporter := &imageExporter{store: store, resolver: resolver, matcher: platforms.Only(platform)}
+	manifest := BundleManifest{
+		Version:         opts.Version,
+		OS:              platform.OS,
+		Arch:            platform.Architecture,
+		ImageRepository: opts.ImageRepository,
+		CreatedAt:       time.Now().UTC(),
+		Images:          map[string][]string{},
+		Checksums:       map[string]string{},
+	}
+	for part, images := range opts.Images {
+		if len(images) == 0 {
+			continue
+		}
+		klog.Infof("Saving %s images %v", part, images)
+		if err := exporter.export(ctx, images, filepath.Join(bundleDir, BundleImagesDir, part+".tar")); err != nil {
+			return "", fmt.Errorf("failed to save %s images: %v", part, err)
+		}
+		manifest.Images[part] = images
+	}
+
+	if opts.EdgeCoreImage != "" {
+		src := strings.TrimPrefix(filepath.Join(KubeEdgeUsrBinPath, KubeEdgeBinaryName), "/")
+		if err := exporter.extractFile(ctx, opts.EdgeCoreImage, src, filepath.Join(bundleDir, BundleEdgeCoreFile)); err != nil {
+			return "", fmt.Errorf("failed to copy edgecore from image %s: %v", opts.EdgeCoreImage, err)
+		}
+	}
+	if err := copyFS(kecharts.FS, ".", filepath.Join(bundleDir, BundleChartsDir)); err != nil {
+		return "", fmt.Errorf("failed to copy charts: %v", err)
+	}
+	if err := copyFS(kecharts.FS, bundleCRDsPath, filepath.Join(bundleDir, BundleCRDsDir)); err != nil {
+		return "", fmt.Errorf("failed to copy CRDs: %v", err)
+	}
+
+	err = filepath.WalkDir(bundleDir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(bundleDir, file)
+		if err != nil {
+			return err
+		}
+		sum, err := fileChecksum(file)
+		if err != nil {
+			return err
+		}
+		manifest.Checksums[filepath.ToSlash(rel)] = sum
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(bundleDir, BundleManifestFile), data, 0640); err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(opts.OutputDir, 0750); err != nil {
+		return "", err
+	}
+	bundle := filepath.Join(opts.OutputDir, BundleFileName(opts.Version, opts.Arch))
+	if err := writeBundleArchive(bundleDir, bundle+".tmp"); err != nil {
+		os.Remove(bundle + ".tmp")
+		return "", err
+	}
+	if err := os.Rename(bundle+".tmp", bundle); err != nil {
+		return "", err
+	}
+	sum, err := fileChecksum(bundle)
+	if err != nil {
+		return "", err
+	}
+	checksum := fmt.Sprintf("%s  %s\n", sum, filepath.Base(bundle))
+	if err := os.WriteFile(bundle+BundleChecksumSuffix, []byte(checksum), 0640); err != nil {
+		return "", err
+	}
+	return bundle, nil
+}
+
+// copyFS copies the directory dir of fsys to dst
+func copyFS(fsys fs.FS, dir, dst string) error {
+	return fs.WalkDir(fsys, dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(file, dir), "/")
+		target := filepath.Join(dst, filepath.FromSlash(rel))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0750)
+		}
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0640)
+	})
+}
+
+// writeBundleArchive writes the files in dir to a tar.gz archive, the manifest is written first
+func writeBundleArchive(dir, file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	var files []string
+	err = filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if files[i] == BundleManifestFile || files[j] == BundleManifestFile {
+			return files[i] == BundleManifestFile
+		}
+		return files[i] < files[j]
+	})
+	for _, name := range files {
+		if err := addArchiveFile(tw, filepath.Join(dir, filepath.FromSlash(name)), name); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func addArchiveFile(tw *tar.Writer, file, name string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     info.Size(),
+		Mode:     int64(info.Mode().Perm()),
+		ModTime:  info.ModTime(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// Bundle is an offline installation bundle extracted and verified
+type Bundle struct {
+	// Dir is the directory the bundle is extracted to
+	Dir      string
+	Manifest BundleManifest
+}
+
+// OpenBundle verifies the bundle with the checksum file next to it, extracts it to a temporary directory
+// and verifies the checksums of the files in it. The bundle without the checksum file is rejected unless
+// skipVerify is true. The caller must close the bundle to remove the directory.
+func OpenBundle(file string, skipVerify bool) (*Bundle, error) {
+	if err := verifyBundleChecksum(file, skipVerify); err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "kubeedge-bundle-")
+	if err != nil {
+		return nil, err
+	}
+	bundle := &Bundle{Dir: dir}
+	if err := bundle.extract(file); err != nil {
+		bundle.Close()
+		return nil, fmt.Errorf("failed to extract bundle %s: %v", file, err)
+	}
+	if err := bundle.verify(); err != nil {
+		bundle.Close()
+		return nil, fmt.Errorf("failed to verify bundle %s: %v", file, err)
+	}
+	return bundle, nil
+}
+
+// verifyBundleChecksum verifies the bundle with the checksum file written by CreateBundle,
+// the checksum file is only allowed to be missing if skipVerify is true
+func verifyBundleChecksum(file string, skipVerify bool) error {
+	data, err := os.ReadFile(file + BundleChecksumSuffix)
+	if os.IsNotExist(err) {
+		if !skipVerify {
+			return fmt.Errorf("checksum file %s of bundle %s is not found, copy it with the bundle or use --skip-verify",
+				file+BundleChecksumSuffix, file)
+		}
+		klog.Warningf("checksum file of bundle %s is not found, only the files in the bundle are verified", file)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return fmt.Errorf("invalid checksum file of bundle %s", file)
+	}
+	sum, err := fileChecksum(file)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(fields[0], sum) {
+		return fmt.Errorf("checksum of bundle %s mismatches, expected %s, got %s", file, fields[0], sum)
+	}
+	return nil
+}
+
+func (b *Bundle) extract(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(bufio.NewReader(f))
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := path.Clean(hdr.Name)
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return fmt.Errorf("invalid file %s in bundle", hdr.Name)
+		}
+		target := filepath.Join(b.Dir, filepath.FromSlash(name))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0750); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+				return err
+			}
+			if err := writeArchiveFile(tr, target, os.FileMode(hdr.Mode).Perm()); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported type of file %s in bundle", hdr.Name)
+		}
+	}
+}
+
+func writeArchiveFile(r io.Reader, target string, mode os.FileMode) error {
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// verify checks all files in the bundle are in the manifest and match their checksums
+func (b *Bundle) verify() error {
+	data, err := os.ReadFile(filepath.Join(b.Dir, BundleManifestFile))
+	if err != nil {
+		return fmt.Errorf("failed to read bundle manifest: %v", err)
+	}
+	if err := json.Unmarshal(data, &b.Manifest); err != nil {
+		return fmt.Errorf("failed to parse bundle manifest: %v", err)
+	}
+	if b.Manifest.Version == "" {
+		return fmt.Errorf("version is not specified in bundle manifest")
+	}
+
+	found := map[string]bool{}
+	err = filepath.WalkDir(b.Dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(b.Dir, file)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if name == BundleManifestFile {
+			return nil
+		}
+		expected, ok := b.Manifest.Checksums[name]
+		if !ok {
+			return fmt.Errorf("file %s is not in bundle manifest", name)
+		}
+		sum, err := fileChecksum(file)
+		if err != nil {
+			return err
+		}
+		if sum != expected {
+			return fmt.Errorf("checksum of file %s mismatches, expected %s, got %s", name, expected, sum)
+		}
+		found[name] = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for name := range b.Manifest.Checksums {
+		if !found[name] {
+			return fmt.Errorf("file %s in bundle manifest is missing", name)
+		}
+	}
+	return nil
+}
+
+// Path returns the path of the file in the bundle
+func (b *Bundle) Path(name string) string {
+	return filepath.Join(b.Dir, filepath.FromSlash(name))
+}
+
+// ImportImages imports the images of the part in the bundle to the container runtime of the endpoint
+func (b *Bundle) ImportImages(endpoint, part string) error {
+	if len(b.Manifest.Images[part]) == 0 {
+		return nil
+	}
+	if b.Manifest.Arch != runtime.GOARCH {
+		return fmt.Errorf("bundle is built for %s/%s, not for the node %s/%s",
+			b.Manifest.OS, b.Manifest.Arch, runtime.GOOS, runtime.GOARCH)
+	}
+	command, err := importImagesCommand(endpoint, b.Path(path.Join(BundleImagesDir, part+".tar")))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Importing %s images %v ...\n", part, b.Manifest.Images[part])
+	cmd := NewCommand(command)
+	if err := cmd.Exec(); err != nil {
+		return err
+	}
+	fmt.Printf("Successfully imported %s images\n", part)
+	return nil
+}
+
+// importImagesCommand returns the command to import the OCI archive to the container runtime of the endpoint,
+// the CRI has no API to import images, so the CLI of the container runtime is used
+func importImagesCommand(endpoint, archive string) (string, error) {
+	address := strings.TrimPrefix(endpoint, "unix://")
+	switch {
+	case strings.Contains(address, "containerd"):
+		return fmt.Sprintf("ctr --address %s -n k8s.io images import %s", address, archive), nil
+	case strings.Contains(address, "docker"):
+		return fmt.Sprintf("docker load -i %s", archive), nil
+	case strings.Contains(address, "crio"):
+		return fmt.Sprintf("podman load -i %s", archive), nil
+	case strings.Contains(address, "isulad"):
+		return fmt.Sprintf("isula load -i %s", archive), nil
+	default:
+		return "", fmt.Errorf("importing images to the container runtime of %s is not supported", endpoint)
+	}
+}
+
+// Close removes the extracted bundle
+func (b *Bundle) Close() error {
+	return os.RemoveAll(b.Dir)
+}
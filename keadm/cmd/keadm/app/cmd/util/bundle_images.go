@@ -0,0 +1,240 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"github.com/containerd/containerd/archive/compression"
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/platforms"
+	"github.com/containerd/containerd/remotes"
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// imageExporter pulls images of a platform to a content store and writes them to OCI archives
+type imageExporter struct {
+	store    content.Store
+	resolver remotes.Resolver
+	matcher  platforms.MatchComparer
+}
+
+// pulledImage is an image pulled to the content store
+type pulledImage struct {
+	// manifest is the manifest of the platform, the index of the image is not exported
+	manifest ocispec.Descriptor
+	// blobs are the manifest, config and layers of the image
+	blobs []ocispec.Descriptor
+}
+
+// pull pulls the manifest, config and layers of the platform of the image
+func (e *imageExporter) pull(ctx context.Context, ref string) (*pulledImage, error) {
+	name, desc, err := e.resolver.Resolve(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve image %s: %v", ref, err)
+	}
+	fetcher, err := e.resolver.Fetcher(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock sync.Mutex
+	image := &pulledImage{}
+	record := images.HandlerFunc(func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		if images.IsIndexType(desc.MediaType) {
+			return nil, nil
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		if images.IsManifestType(desc.MediaType) {
+			image.manifest = desc
+		}
+		image.blobs = append(image.blobs, desc)
+		return nil, nil
+	})
+	handler := images.Handlers(
+		remotes.FetchHandler(e.store, fetcher),
+		record,
+		images.LimitManifests(images.FilterPlatforms(images.ChildrenHandler(e.store), e.matcher), e.matcher, 1),
+	)
+	if err := images.Dispatch(ctx, handler, nil, desc); err != nil {
+		return nil, fmt.Errorf("failed to pull image %s: %v", ref, err)
+	}
+	if image.manifest.Digest == "" {
+		return nil, fmt.Errorf("image %s has no manifest of the platform", ref)
+	}
+	return image, nil
+}
+
+// export pulls the images and writes them to the OCI archive file, the images are named by
+// the annotations of index.json, so the container runtimes import them with their names
+func (e *imageExporter) export(ctx context.Context, refs []string, file string) error {
+	index := ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+	}
+	var blobs []ocispec.Descriptor
+	exported := map[digest.Digest]bool{}
+	for _, ref := range refs {
+		named, err := reference.ParseDockerRef(ref)
+		if err != nil {
+			return fmt.Errorf("invalid image reference %s: %v", ref, err)
+		}
+		image, err := e.pull(ctx, named.String())
+		if err != nil {
+			return err
+		}
+		manifest := image.manifest
+		manifest.Annotations = map[string]string{images.AnnotationImageName: named.String()}
+		if tagged, ok := named.(reference.Tagged); ok {
+			manifest.Annotations[ocispec.AnnotationRefName] = tagged.Tag()
+		}
+		index.Manifests = append(index.Manifests, manifest)
+		for _, blob := range image.blobs {
+			if !exported[blob.Digest] {
+				exported[blob.Digest] = true
+				blobs = append(blobs, blob)
+			}
+		}
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	tw := tar.NewWriter(f)
+	layout, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, ocispec.ImageLayoutFile, int64(len(layout)), bytes.NewReader(layout)); err != nil {
+		return err
+	}
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, "index.json", int64(len(data)), bytes.NewReader(data)); err != nil {
+		return err
+	}
+	for _, blob := range blobs {
+		ra, err := e.store.ReaderAt(ctx, blob)
+		if err != nil {
+			return err
+		}
+		name := path.Join("blobs", blob.Digest.Algorithm().String(), blob.Digest.Encoded())
+		err = writeTarFile(tw, name, ra.Size(), content.NewReader(ra))
+		ra.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// extractFile copies the regular file src from the layers of the image to dst
+func (e *imageExporter) extractFile(ctx context.Context, ref, src, dst string) error {
+	named, err := reference.ParseDockerRef(ref)
+	if err != nil {
+		return err
+	}
+	image, err := e.pull(ctx, named.String())
+	if err != nil {
+		return err
+	}
+	data, err := content.ReadBlob(ctx, e.store, image.manifest)
+	if err != nil {
+		return err
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return err
+	}
+	// the upper layers override the lower layers
+	for i := len(manifest.Layers) - 1; i >= 0; i-- {
+		found, err := e.extractLayerFile(ctx, manifest.Layers[i], src, dst)
+		if err != nil {
+			return err
+		}
+		if found {
+			return nil
+		}
+	}
+	return fmt.Errorf("file %s is not found", src)
+}
+
+func (e *imageExporter) extractLayerFile(ctx context.Context, layer ocispec.Descriptor, src, dst string) (bool, error) {
+	ra, err := e.store.ReaderAt(ctx, layer)
+	if err != nil {
+		return false, err
+	}
+	defer ra.Close()
+	r, err := compression.DecompressStream(content.NewReader(ra))
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if hdr.Typeflag != tar.TypeReg || path.Clean("/"+hdr.Name) != path.Clean("/"+src) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+			return false, err
+		}
+		return true, writeArchiveFile(tr, dst, 0755)
+	}
+}
+
+func writeTarFile(tw *tar.Writer, name string, size int64, r io.Reader) error {
+	hdr := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0644,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, r)
+	return err
+}
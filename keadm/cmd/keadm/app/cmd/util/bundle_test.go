@@ -0,0 +1,237 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// tarGz returns a tar.gz archive of the files
+func tarGz(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Size: int64(len(data)), Mode: 0755}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// fakeRegistry serves the image installation-package with the edgecore binary in any tag
+func fakeRegistry(t *testing.T) *httptest.Server {
+	layer := tarGz(t, map[string]string{"usr/local/bin/edgecore": "edgecore binary"})
+	config := []byte(`{"architecture":"amd64","os":"linux"}`)
+	manifest, _ := json.Marshal(ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.Descriptor{MediaType: ocispec.MediaTypeImageConfig, Digest: digest.FromBytes(config), Size: int64(len(config))},
+		Layers:    []ocispec.Descriptor{{MediaType: ocispec.MediaTypeImageLayerGzip, Digest: digest.FromBytes(layer), Size: int64(len(layer))}},
+	})
+	blobs := map[string][]byte{
+		digest.FromBytes(layer).String():  layer,
+		digest.FromBytes(config).String(): config,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v2/kubeedge/installation-package/manifests/") {
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", digest.FromBytes(manifest).String())
+			w.Header().Set("Content-Length", strconv.Itoa(len(manifest)))
+			if r.Method != http.MethodHead {
+				w.Write(manifest)
+			}
+			return
+		}
+		if ref, ok := strings.CutPrefix(r.URL.Path, "/v2/kubeedge/installation-package/blobs/"); ok {
+			if data, ok := blobs[ref]; ok {
+				w.Write(data)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}))
+}
+
+func TestCreateAndOpenBundle(t *testing.T) {
+	registry := fakeRegistry(t)
+	defer registry.Close()
+	image := strings.TrimPrefix(registry.URL, "http://") + "/kubeedge/installation-package:v1.17.0"
+	resolver := docker.NewResolver(docker.ResolverOptions{
+		Hosts: docker.ConfigureDefaultRegistries(docker.WithPlainHTTP(docker.MatchAllHosts)),
+	})
+
+	output := t.TempDir()
+	bundlePath, err := createBundle(context.Background(), BundleOptions{
+		Version:       "v1.17.0",
+		Arch:          "amd64",
+		Images:        map[string][]string{BundlePartEdge: {image}},
+		EdgeCoreImage: image,
+		OutputDir:     output,
+	}, resolver)
+	if err != nil {
+		t.Fatalf("failed to create bundle: %v", err)
+	}
+	if filepath.Base(bundlePath) != "kubeedge-bundle-v1.17.0-linux-amd64.tar.gz" {
+		t.Errorf("unexpected bundle name %s", bundlePath)
+	}
+
+	bundle, err := OpenBundle(bundlePath, false)
+	if err != nil {
+		t.Fatalf("failed to open bundle: %v", err)
+	}
+	defer bundle.Close()
+	if bundle.Manifest.Version != "v1.17.0" || len(bundle.Manifest.Images[BundlePartEdge]) != 1 {
+		t.Errorf("unexpected bundle manifest %+v", bundle.Manifest)
+	}
+	info, err := os.Stat(bundle.Path(BundleEdgeCoreFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(bundle.Path(BundleEdgeCoreFile)); string(data) != "edgecore binary" || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("unexpected edgecore binary %q with mode %v", data, info.Mode())
+	}
+	for _, file := range []string{"charts/charts/cloudcore/Chart.yaml", "charts/profiles/version.yaml"} {
+		if _, err := os.Stat(bundle.Path(file)); err != nil {
+			t.Errorf("expected %s in bundle: %v", file, err)
+		}
+	}
+	if entries, err := os.ReadDir(bundle.Path(BundleCRDsDir)); err != nil || len(entries) == 0 {
+		t.Errorf("expected CRDs in bundle: %v", err)
+	}
+
+	// the images are named in index.json of the OCI archive
+	index := readArchiveIndex(t, bundle.Path("images/edge.tar"))
+	if len(index.Manifests) != 1 || index.Manifests[0].Annotations[images.AnnotationImageName] != image {
+		t.Errorf("unexpected index of images %+v", index)
+	}
+
+	// the bundle is rejected if its checksum mismatches
+	if err := os.WriteFile(bundlePath+BundleChecksumSuffix, []byte(digest.FromString("").Encoded()+"  bundle\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenBundle(bundlePath, true); err == nil {
+		t.Error("expected the bundle with mismatched checksum to be rejected")
+	}
+
+	// the bundle without the checksum file is only allowed with skipVerify
+	if err := os.Remove(bundlePath + BundleChecksumSuffix); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenBundle(bundlePath, false); err == nil {
+		t.Error("expected the bundle without checksum file to be rejected")
+	}
+	skipped, err := OpenBundle(bundlePath, true)
+	if err != nil {
+		t.Fatalf("expected the bundle without checksum file to be opened with skipVerify: %v", err)
+	}
+	skipped.Close()
+}
+
+func readArchiveIndex(t *testing.T, file string) ocispec.Index {
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			t.Fatalf("index.json is not found: %v", err)
+		}
+		if hdr.Name == "index.json" {
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var index ocispec.Index
+			if err := json.Unmarshal(data, &index); err != nil {
+				t.Fatal(err)
+			}
+			return index
+		}
+	}
+}
+
+func TestOpenInvalidBundle(t *testing.T) {
+	manifest := `{"version":"v1.17.0","checksums":{"bin/edgecore":"` + digest.FromString("edgecore").Encoded() + `"}}`
+	cases := map[string]map[string]string{
+		"path traversal":   {BundleManifestFile: manifest, "../edgecore": "edgecore"},
+		"modified file":    {BundleManifestFile: manifest, "bin/edgecore": "modified"},
+		"missing file":     {BundleManifestFile: manifest},
+		"unexpected file":  {BundleManifestFile: manifest, "bin/edgecore": "edgecore", "bin/other": "other"},
+		"missing manifest": {"bin/edgecore": "edgecore"},
+		"unversioned":      {BundleManifestFile: `{"checksums":{}}`},
+	}
+	for name, files := range cases {
+		t.Run(name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "bundle.tar.gz")
+			if err := os.WriteFile(file, tarGz(t, files), 0640); err != nil {
+				t.Fatal(err)
+			}
+			// the files in the bundle are verified even if the checksum file is skipped
+			if bundle, err := OpenBundle(file, true); err == nil {
+				bundle.Close()
+				t.Error("expected the invalid bundle to be rejected")
+			}
+		})
+	}
+}
+
+func TestImportImagesCommand(t *testing.T) {
+	cases := map[string]string{
+		"unix:///run/containerd/containerd.sock": "ctr --address /run/containerd/containerd.sock -n k8s.io images import /tmp/edge.tar",
+		"unix:///var/run/cri-dockerd.sock":       "docker load -i /tmp/edge.tar",
+		"unix:///var/run/crio/crio.sock":         "podman load -i /tmp/edge.tar",
+	}
+	for endpoint, expected := range cases {
+		command, err := importImagesCommand(endpoint, "/tmp/edge.tar")
+		if err != nil || command != expected {
+			t.Errorf("unexpected command %q of endpoint %s: %v", command, endpoint, err)
+		}
+	}
+	if _, err := importImagesCommand("unix:///run/unknown.sock", "/tmp/edge.tar"); err == nil {
+		t.Error("expected the unknown container runtime to be unsupported")
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package carotation defines the status of the rotation of the CA of cloudcore.
+//
+// A rotation is started by saving a new CA in the CA secret, the previous CA is kept in the
+// secret as the retiring CA, and setting the phase of the status ConfigMap to Rotating.
+// Cloudcore then signs certificates by the new CA and publishes both CAs as the trust bundle,
+// edge nodes rotate their certificates, and the retiring CA is removed once all registered
+// nodes use certificates signed by the new CA. Nodes which are offline hold the rotation back
+// unless it is forced.
+package carotation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap holding the rotation status
+	ConfigMapName = "cloudcore-ca-rotation"
+
+	// RetiringCaDataName and RetiringCaKeyDataName are the keys of the retiring CA in the CA secret
+	RetiringCaDataName    = "retiringcadata"
+	RetiringCaKeyDataName = "retiringcakeydata"
+
+	// PhaseRotating means edge nodes are rotating their certificates to the new CA
+	PhaseRotating = "Rotating"
+	// PhaseCompleted means the retiring CA has been removed
+	PhaseCompleted = "Completed"
+
+	// NodeRotated means the node connects with a certificate signed by the new CA
+	NodeRotated = "Rotated"
+	// NodePending means the node still connects with a certificate signed by the retiring CA
+	NodePending = "Pending"
+	// NodeOffline means the node is registered but not connected to any cloudcore,
+	// it has not rotated its certificate yet
+	NodeOffline = "Offline"
+
+	phaseKey       = "phase"
+	startedAtKey   = "startedAt"
+	completedAtKey = "completedAt"
+	nodesKey       = "nodes"
+	forceKey       = "force"
+)
+
+// Status is the progress of a CA rotation
+type Status struct {
+	Phase       string
+	StartedAt   time.Time
+	CompletedAt time.Time
+	// Nodes maps the registered edge nodes to NodeRotated, NodePending or NodeOffline
+	Nodes map[string]string
+	// Force retires the retiring CA without waiting for the offline nodes,
+	// they have to join again with a token after the rotation
+	Force bool
+}
+
+// Complete returns true if all the registered nodes have rotated their certificates,
+// the offline nodes are ignored if the rotation is forced
+func (s *Status) Complete() bool {
+	for _, state := range s.Nodes {
+		if state == NodeRotated || (s.Force && state == NodeOffline) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// OfflineNodes returns the sorted names of the offline nodes which have not rotated their certificates
+func (s *Status) OfflineNodes() []string {
+	var nodes []string
+	for node, state := range s.Nodes {
+		if state == NodeOffline {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// PendingNodes returns the sorted names of the nodes which have not rotated their certificates
+func (s *Status) PendingNodes() []string {
+	var nodes []string
+	for node, state := range s.Nodes {
+		if state != NodeRotated {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// StatusFromConfigMap parses the status held by the ConfigMap
+func StatusFromConfigMap(cm *corev1.ConfigMap) (*Status, error) {
+	status := &Status{
+		Phase: cm.Data[phaseKey],
+		Nodes: make(map[string]string),
+	}
+	var err error
+	if v := cm.Data[forceKey]; v != "" {
+		if status.Force, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid force of CA rotation: %v", err)
+		}
+	}
+	if v := cm.Data[startedAtKey]; v != "" {
+		if status.StartedAt, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, fmt.Errorf("invalid start time of CA rotation: %v", err)
+		}
+	}
+	if v := cm.Data[completedAtKey]; v != "" {
+		if status.CompletedAt, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, fmt.Errorf("invalid completion time of CA rotation: %v", err)
+		}
+	}
+	if v := cm.Data[nodesKey]; v != "" {
+		if err := json.Unmarshal([]byte(v), &status.Nodes); err != nil {
+			return nil, fmt.Errorf("invalid nodes of CA rotation: %v", err)
+		}
+	}
+	return status, nil
+}
+
+// ToConfigMap converts the status to the ConfigMap holding it
+func (s *Status) ToConfigMap(namespace string) (*corev1.ConfigMap, error) {
+	data := map[string]string{phaseKey: s.Phase}
+	if !s.StartedAt.IsZero() {
+		data[startedAtKey] = s.StartedAt.UTC().Format(time.RFC3339)
+	}
+	if s.Force {
+		data[forceKey] = strconv.FormatBool(s.Force)
+	}
+	if !s.CompletedAt.IsZero() {
+		data[completedAtKey] = s.CompletedAt.UTC().Format(time.RFC3339)
+	}
+	if len(s.Nodes) > 0 {
+		nodes, err := json.Marshal(s.Nodes)
+		if err != nil {
+			return nil, err
+		}
+		data[nodesKey] = string(nodes)
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigMapName,
+			Namespace: namespace,
+		},
+		Data: data,
+	}, nil
+}
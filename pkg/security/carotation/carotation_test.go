@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package carotation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStatusConfigMap(t *testing.T) {
+	status := &Status{
+		Phase:     PhaseRotating,
+		StartedAt: time.Now().UTC().Truncate(time.Second),
+		Nodes: map[string]string{
+			"edge-1": NodeRotated,
+			"edge-2": NodePending,
+			"edge-3": NodePending,
+		},
+	}
+	cm, err := status.ToConfigMap("kubeedge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cm.Name != ConfigMapName || cm.Namespace != "kubeedge" {
+		t.Errorf("unexpected ConfigMap %s/%s", cm.Namespace, cm.Name)
+	}
+	if _, ok := cm.Data[completedAtKey]; ok {
+		t.Error("expected no completion time of a rotation in progress")
+	}
+
+	parsed, err := StatusFromConfigMap(cm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, status) {
+		t.Errorf("expected status %+v, got %+v", status, parsed)
+	}
+	if parsed.Complete() {
+		t.Error("expected the rotation not complete with pending nodes")
+	}
+	if pending := parsed.PendingNodes(); !reflect.DeepEqual(pending, []string{"edge-2", "edge-3"}) {
+		t.Errorf("expected pending nodes edge-2 and edge-3, got %v", pending)
+	}
+
+	parsed.Nodes["edge-2"] = NodeRotated
+	delete(parsed.Nodes, "edge-3")
+	if !parsed.Complete() {
+		t.Error("expected the rotation complete when all registered nodes are rotated")
+	}
+
+	parsed.Nodes["edge-3"] = NodeOffline
+	if parsed.Complete() {
+		t.Error("expected the rotation not complete with offline nodes")
+	}
+	if offline := parsed.OfflineNodes(); !reflect.DeepEqual(offline, []string{"edge-3"}) {
+		t.Errorf("expected offline node edge-3, got %v", offline)
+	}
+	parsed.Force = true
+	forced, err := parsed.ToConfigMap("kubeedge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed, err = StatusFromConfigMap(forced); err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Force || !parsed.Complete() {
+		t.Error("expected the forced rotation complete without waiting for offline nodes")
+	}
+
+	cm.Data[startedAtKey] = "invalid"
+	if _, err := StatusFromConfigMap(cm); err == nil {
+		t.Error("expected an error of the invalid start time")
+	}
+}
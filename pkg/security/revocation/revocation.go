@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package revocation defines the revocation list of edge node certificates.
+// The list is stored in a ConfigMap, the key is the node name and the value is an Entry in json.
+// All certificates of the node issued before the revocation time are revoked, so a revoked node
+// has to join again with a token to get a new certificate.
+package revocation
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap holding the revocation list
+	ConfigMapName = "edge-cert-revocation"
+
+	nodeUserPrefix = "system:node:"
+)
+
+// Entry is the revocation of the certificates of a node
+type Entry struct {
+	// RevokedAt is the time the certificates are revoked, certificates issued not after it are revoked.
+	// It is zero until cloudcore stamps the revocation, meanwhile all certificates of the node are revoked.
+	RevokedAt time.Time `json:"revokedAt"`
+	// Reason is the reason of the revocation
+	Reason string `json:"reason,omitempty"`
+}
+
+// NewEntry creates a revocation entry at the time now. Certificate times are in seconds,
+// so the time is truncated to make the certificates issued in the same second revoked.
+func NewEntry(now time.Time, reason string) Entry {
+	return Entry{RevokedAt: now.UTC().Truncate(time.Second), Reason: reason}
+}
+
+// NewPendingEntry creates a revocation entry to be stamped by cloudcore. cloudcore sets the revocation
+// time with the clock it issues certificates with, so the clock skew of the client creating
+// the revocation can not backdate or postdate it.
+func NewPendingEntry(reason string) Entry {
+	return Entry{Reason: reason}
+}
+
+// Pending returns true if the revocation time is not stamped by cloudcore yet
+func (e Entry) Pending() bool {
+	return e.RevokedAt.IsZero()
+}
+
+// Encode encodes the entry as the value of the ConfigMap
+func (e Entry) Encode() (string, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Revokes returns true if the certificate is revoked by the entry,
+// a pending entry revokes all certificates
+func (e Entry) Revokes(cert *x509.Certificate) bool {
+	return e.Pending() || !cert.NotBefore.After(e.RevokedAt)
+}
+
+// Parse parses the data of the ConfigMap, invalid entries are returned as errors
+// and skipped so that they do not hide the valid ones
+func Parse(data map[string]string) (map[string]Entry, []error) {
+	entries := make(map[string]Entry, len(data))
+	var errs []error
+	for node, value := range data {
+		var e Entry
+		if err := json.Unmarshal([]byte(value), &e); err != nil {
+			errs = append(errs, fmt.Errorf("invalid revocation of node %s: %v", node, err))
+			continue
+		}
+		entries[node] = e
+	}
+	return entries, errs
+}
+
+// NodeNameOf returns the node name in the common name of the edge certificate,
+// it is empty if the certificate is not issued for a node
+func NodeNameOf(cert *x509.Certificate) string {
+	if !strings.HasPrefix(cert.Subject.CommonName, nodeUserPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(cert.Subject.CommonName, nodeUserPrefix)
+}
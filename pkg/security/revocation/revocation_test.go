@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revocation
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+)
+
+func TestEntryRevokes(t *testing.T) {
+	now := time.Now()
+	entry := NewEntry(now, "node is lost")
+	value, err := entry.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, errs := Parse(map[string]string{"edge-1": value, "edge-2": "invalid"})
+	if len(errs) != 1 {
+		t.Errorf("expected 1 invalid entry, got %v", errs)
+	}
+	parsed, ok := entries["edge-1"]
+	if !ok || !parsed.RevokedAt.Equal(entry.RevokedAt) || parsed.Reason != entry.Reason {
+		t.Fatalf("expected entry %+v, got %+v", entry, parsed)
+	}
+
+	tests := []struct {
+		name      string
+		notBefore time.Time
+		revoked   bool
+	}{
+		{name: "issued before revocation", notBefore: now.Add(-time.Hour), revoked: true},
+		{name: "issued in the second of revocation", notBefore: now.UTC(), revoked: true},
+		{name: "issued after revocation", notBefore: now.Add(time.Second), revoked: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// certificate times are encoded in seconds
+			cert := &x509.Certificate{NotBefore: tt.notBefore.Truncate(time.Second)}
+			if got := parsed.Revokes(cert); got != tt.revoked {
+				t.Errorf("Revokes() = %v, want %v", got, tt.revoked)
+			}
+		})
+	}
+}
+
+func TestPendingEntryRevokes(t *testing.T) {
+	value, err := NewPendingEntry("node is lost").Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, errs := Parse(map[string]string{"edge-1": value})
+	if len(errs) != 0 {
+		t.Fatalf("expected no invalid entry, got %v", errs)
+	}
+	entry := entries["edge-1"]
+	if !entry.Pending() {
+		t.Fatalf("expected a pending entry, got %+v", entry)
+	}
+	// the certificates issued at any time are revoked until cloudcore stamps the revocation
+	if cert := (&x509.Certificate{NotBefore: time.Now().Add(time.Hour)}); !entry.Revokes(cert) {
+		t.Error("expected all certificates revoked by a pending entry")
+	}
+}
+
+func TestNodeNameOf(t *testing.T) {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "system:node:edge-1"}}
+	if got := NodeNameOf(cert); got != "edge-1" {
+		t.Errorf("expected node edge-1, got %q", got)
+	}
+	cert = &x509.Certificate{Subject: pkix.Name{CommonName: "kubeedge.io"}}
+	if got := NodeNameOf(cert); got != "" {
+		t.Errorf("expected no node, got %q", got)
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package token
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// BootstrapTokenSecretType is the type of secrets holding bootstrap tokens
+	BootstrapTokenSecretType corev1.SecretType = "kubeedge.io/bootstrap-token"
+	// BootstrapTokenSecretPrefix is the name prefix of secrets holding bootstrap tokens
+	BootstrapTokenSecretPrefix = "bootstrap-token-"
+
+	// keys of the data of bootstrap token secrets
+	BootstrapTokenIDKey              = "token-id"
+	BootstrapTokenExpirationKey      = "expiration"
+	BootstrapTokenUsageLimitKey      = "usage-limit"
+	BootstrapTokenUsageCountKey      = "usage-count"
+	BootstrapTokenNodeNamePatternKey = "node-name-pattern"
+	BootstrapTokenNodeGroupKey       = "node-group"
+)
+
+// BootstrapToken restricts the edge nodes that can join the cluster with the token.
+// Unlike the shared token refreshed by cloudcore, a bootstrap token is minted for a node or
+// a node group, expires, and can be used a limited number of times.
+type BootstrapToken struct {
+	// ID identifies the token, it is the jwt id of the token and the suffix of the secret name
+	ID string
+	// Expiration is the time after which the token can not be used
+	Expiration time.Time
+	// UsageLimit is the max number of times the token can be used
+	UsageLimit int
+	// UsageCount is the number of times the token has been used
+	UsageCount int
+	// NodeNamePattern is the shell pattern of the names of nodes allowed to join, e.g. "edge-*"
+	NodeNamePattern string
+	// NodeGroup is the name of the node group whose nodes are allowed to join
+	NodeGroup string
+}
+
+// NewBootstrapTokenID generates a random token id
+func NewBootstrapTokenID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// BootstrapTokenSecretName returns the name of the secret holding the bootstrap token
+func BootstrapTokenSecretName(id string) string {
+	return BootstrapTokenSecretPrefix + id
+}
+
+// CreateBootstrap creates a token consisting of caHash and a jwt token carrying the id of the bootstrap token.
+// The token has the same format as the token created by Create, so edge nodes join with it in the same way.
+func CreateBootstrap(ca, caKey []byte, bt *BootstrapToken) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{
+		Id:        bt.ID,
+		ExpiresAt: bt.Expiration.Unix(),
+	})
+	tokenString, err := token.SignedString(caKey)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{hashCA(ca), tokenString}, "."), nil
+}
+
+// VerifyAndGetID verifies the token like Verify and returns the id of the bootstrap token,
+// the id is empty if the token is the shared token created by Create.
+func VerifyAndGetID(token string, caKey []byte) (string, error) {
+	claims := jwt.MapClaims{}
+	jwtToken, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("invalid token method type, want *jwt.SigningMethodHMAC, but is %T", token.Method)
+		}
+		return caKey, nil
+	})
+	if err != nil {
+		// return the original error for the caller to determine.
+		return "", err
+	}
+	if !jwtToken.Valid {
+		return "", errors.New("token is invalid")
+	}
+	id, _ := claims["jti"].(string)
+	return id, nil
+}
+
+// Allows checks whether the node can join with the token at the time now.
+// The node group restriction is checked by the caller because it needs the node group.
+func (bt *BootstrapToken) Allows(nodeName string, now time.Time) error {
+	if !bt.Expiration.IsZero() && now.After(bt.Expiration) {
+		return fmt.Errorf("bootstrap token %s expired at %s", bt.ID, bt.Expiration.Format(time.RFC3339))
+	}
+	if bt.UsageLimit > 0 && bt.UsageCount >= bt.UsageLimit {
+		return fmt.Errorf("bootstrap token %s has been used %d times", bt.ID, bt.UsageCount)
+	}
+	if bt.NodeNamePattern != "" {
+		matched, err := path.Match(bt.NodeNamePattern, nodeName)
+		if err != nil {
+			return fmt.Errorf("invalid node name pattern %q of bootstrap token %s: %v", bt.NodeNamePattern, bt.ID, err)
+		}
+		if !matched {
+			return fmt.Errorf("node %s does not match the node name pattern %q of bootstrap token %s",
+				nodeName, bt.NodeNamePattern, bt.ID)
+		}
+	}
+	return nil
+}
+
+// ToSecret converts the bootstrap token to the secret holding it
+func (bt *BootstrapToken) ToSecret(namespace string) *corev1.Secret {
+	data := map[string][]byte{
+		BootstrapTokenIDKey:         []byte(bt.ID),
+		BootstrapTokenUsageLimitKey: []byte(strconv.Itoa(bt.UsageLimit)),
+		BootstrapTokenUsageCountKey: []byte(strconv.Itoa(bt.UsageCount)),
+	}
+	if !bt.Expiration.IsZero() {
+		data[BootstrapTokenExpirationKey] = []byte(bt.Expiration.UTC().Format(time.RFC3339))
+	}
+	if bt.NodeNamePattern != "" {
+		data[BootstrapTokenNodeNamePatternKey] = []byte(bt.NodeNamePattern)
+	}
+	if bt.NodeGroup != "" {
+		data[BootstrapTokenNodeGroupKey] = []byte(bt.NodeGroup)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      BootstrapTokenSecretName(bt.ID),
+			Namespace: namespace,
+		},
+		Data: data,
+		Type: BootstrapTokenSecretType,
+	}
+}
+
+// BootstrapTokenFromSecret parses the bootstrap token held by the secret
+func BootstrapTokenFromSecret(secret *corev1.Secret) (*BootstrapToken, error) {
+	if secret.Type != BootstrapTokenSecretType {
+		return nil, fmt.Errorf("secret %s is not a bootstrap token", secret.Name)
+	}
+	bt := &BootstrapToken{
+		ID:              string(secret.Data[BootstrapTokenIDKey]),
+		NodeNamePattern: string(secret.Data[BootstrapTokenNodeNamePatternKey]),
+		NodeGroup:       string(secret.Data[BootstrapTokenNodeGroupKey]),
+	}
+	if bt.ID == "" || BootstrapTokenSecretName(bt.ID) != secret.Name {
+		return nil, fmt.Errorf("secret %s has an invalid bootstrap token id %q", secret.Name, bt.ID)
+	}
+	var err error
+	if v := secret.Data[BootstrapTokenExpirationKey]; len(v) > 0 {
+		if bt.Expiration, err = time.Parse(time.RFC3339, string(v)); err != nil {
+			return nil, fmt.Errorf("invalid expiration of bootstrap token %s: %v", bt.ID, err)
+		}
+	}
+	if v := secret.Data[BootstrapTokenUsageLimitKey]; len(v) > 0 {
+		if bt.UsageLimit, err = strconv.Atoi(string(v)); err != nil {
+			return nil, fmt.Errorf("invalid usage limit of bootstrap token %s: %v", bt.ID, err)
+		}
+	}
+	if v := secret.Data[BootstrapTokenUsageCountKey]; len(v) > 0 {
+		if bt.UsageCount, err = strconv.Atoi(string(v)); err != nil {
+			return nil, fmt.Errorf("invalid usage count of bootstrap token %s: %v", bt.ID, err)
+		}
+	}
+	return bt, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package token
+
+import (
+	"encoding/pem"
+	"testing"
+	"time"
+)
+
+func TestBootstrapToken(t *testing.T) {
+	_, caDer := pem.Decode([]byte(testCA))
+	_, cakeyDer := pem.Decode([]byte(testCAKey))
+
+	bt := &BootstrapToken{
+		ID:              "abc",
+		Expiration:      time.Now().UTC().Truncate(time.Second).Add(time.Hour),
+		UsageLimit:      1,
+		NodeNamePattern: "edge-*",
+	}
+
+	t.Run("test CreateBootstrap and VerifyAndGetID", func(t *testing.T) {
+		token, err := CreateBootstrap(caDer, cakeyDer, bt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		realToken, err := VerifyCAAndGetRealToken(token, caDer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := VerifyAndGetID(realToken, cakeyDer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != bt.ID {
+			t.Fatalf("expected token id %s, got %s", bt.ID, id)
+		}
+	})
+
+	t.Run("test VerifyAndGetID with shared token", func(t *testing.T) {
+		token, err := Create(caDer, cakeyDer, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		realToken, err := VerifyCAAndGetRealToken(token, caDer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := VerifyAndGetID(realToken, cakeyDer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != "" {
+			t.Fatalf("expected no token id of shared token, got %s", id)
+		}
+	})
+
+	t.Run("test secret conversion", func(t *testing.T) {
+		got, err := BootstrapTokenFromSecret(bt.ToSecret("kubeedge"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *got != *bt {
+			t.Fatalf("expected bootstrap token %+v, got %+v", bt, got)
+		}
+	})
+
+	t.Run("test Allows", func(t *testing.T) {
+		now := time.Now()
+		if err := bt.Allows("edge-1", now); err != nil {
+			t.Fatal(err)
+		}
+		if err := bt.Allows("cloud-1", now); err == nil {
+			t.Fatal("expected node not matching the pattern to be rejected")
+		}
+		if err := bt.Allows("edge-1", now.Add(2*time.Hour)); err == nil {
+			t.Fatal("expected expired token to be rejected")
+		}
+		used := *bt
+		used.UsageCount = 1
+		if err := used.Allows("edge-1", now); err == nil {
+			t.Fatal("expected used up token to be rejected")
+		}
+	})
+}
@@ -30,6 +30,7 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
+		"github.com/kubeedge/api/apis/apps/v1alpha1.AffinityOverrider":              schema_api_apis_apps_v1alpha1_AffinityOverrider(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.CommandArgsOverrider":           schema_api_apis_apps_v1alpha1_CommandArgsOverrider(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.EdgeApplication":                schema_api_apis_apps_v1alpha1_EdgeApplication(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.EdgeApplicationList":            schema_api_apis_apps_v1alpha1_EdgeApplicationList(ref),
@@ -38,18 +39,28 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/kubeedge/api/apis/apps/v1alpha1.EnvOverrider":                   schema_api_apis_apps_v1alpha1_EnvOverrider(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.ImageOverrider":                 schema_api_apis_apps_v1alpha1_ImageOverrider(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.ImagePredicate":                 schema_api_apis_apps_v1alpha1_ImagePredicate(ref),
+		"github.com/kubeedge/api/apis/apps/v1alpha1.JSONPatchOverrider":             schema_api_apis_apps_v1alpha1_JSONPatchOverrider(ref),
+		"github.com/kubeedge/api/apis/apps/v1alpha1.LabelAnnotationOverrider":       schema_api_apis_apps_v1alpha1_LabelAnnotationOverrider(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.Manifest":                       schema_api_apis_apps_v1alpha1_Manifest(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.ManifestStatus":                 schema_api_apis_apps_v1alpha1_ManifestStatus(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.NodeGroup":                      schema_api_apis_apps_v1alpha1_NodeGroup(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.NodeGroupList":                  schema_api_apis_apps_v1alpha1_NodeGroupList(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.NodeGroupSpec":                  schema_api_apis_apps_v1alpha1_NodeGroupSpec(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.NodeGroupStatus":                schema_api_apis_apps_v1alpha1_NodeGroupStatus(ref),
+		"github.com/kubeedge/api/apis/apps/v1alpha1.NodeGroupWorkloadStatus":        schema_api_apis_apps_v1alpha1_NodeGroupWorkloadStatus(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.NodeStatus":                     schema_api_apis_apps_v1alpha1_NodeStatus(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.Overriders":                     schema_api_apis_apps_v1alpha1_Overriders(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.ResourceIdentifier":             schema_api_apis_apps_v1alpha1_ResourceIdentifier(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.ResourceTemplate":               schema_api_apis_apps_v1alpha1_ResourceTemplate(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.ResourcesOverrider":             schema_api_apis_apps_v1alpha1_ResourcesOverrider(ref),
+		"github.com/kubeedge/api/apis/apps/v1alpha1.RollbackConfig":                 schema_api_apis_apps_v1alpha1_RollbackConfig(ref),
+		"github.com/kubeedge/api/apis/apps/v1alpha1.RollingUpdateNodeGroups":        schema_api_apis_apps_v1alpha1_RollingUpdateNodeGroups(ref),
+		"github.com/kubeedge/api/apis/apps/v1alpha1.RolloutStrategy":                schema_api_apis_apps_v1alpha1_RolloutStrategy(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.TargetNodeGroup":                schema_api_apis_apps_v1alpha1_TargetNodeGroup(ref),
+		"github.com/kubeedge/api/apis/apps/v1alpha1.TolerationsOverrider":           schema_api_apis_apps_v1alpha1_TolerationsOverrider(ref),
+		"github.com/kubeedge/api/apis/apps/v1alpha1.VolumeMountsOverrider":          schema_api_apis_apps_v1alpha1_VolumeMountsOverrider(ref),
+		"github.com/kubeedge/api/apis/apps/v1alpha1.VolumesOverrider":               schema_api_apis_apps_v1alpha1_VolumesOverrider(ref),
+		"github.com/kubeedge/api/apis/apps/v1alpha1.WorkloadReplicas":               schema_api_apis_apps_v1alpha1_WorkloadReplicas(ref),
 		"github.com/kubeedge/api/apis/apps/v1alpha1.WorkloadScope":                  schema_api_apis_apps_v1alpha1_WorkloadScope(ref),
 		"github.com/kubeedge/api/apis/devices/v1alpha2.BluetoothOperations":         schema_api_apis_devices_v1alpha2_BluetoothOperations(ref),
 		"github.com/kubeedge/api/apis/devices/v1alpha2.BluetoothReadConverter":      schema_api_apis_devices_v1alpha2_BluetoothReadConverter(ref),
@@ -114,6 +125,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/kubeedge/api/apis/devices/v1beta1.Twin":                         schema_api_apis_devices_v1beta1_Twin(ref),
 		"github.com/kubeedge/api/apis/devices/v1beta1.TwinProperty":                 schema_api_apis_devices_v1beta1_TwinProperty(ref),
 		"github.com/kubeedge/api/apis/devices/v1beta1.VisitorConfig":                schema_api_apis_devices_v1beta1_VisitorConfig(ref),
+		"github.com/kubeedge/api/apis/operations/v1alpha1.CheckResult":              schema_api_apis_operations_v1alpha1_CheckResult(ref),
 		"github.com/kubeedge/api/apis/operations/v1alpha1.ImagePrePullJob":          schema_api_apis_operations_v1alpha1_ImagePrePullJob(ref),
 		"github.com/kubeedge/api/apis/operations/v1alpha1.ImagePrePullJobList":      schema_api_apis_operations_v1alpha1_ImagePrePullJobList(ref),
 		"github.com/kubeedge/api/apis/operations/v1alpha1.ImagePrePullJobSpec":      schema_api_apis_operations_v1alpha1_ImagePrePullJobSpec(ref),
@@ -121,6 +133,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/kubeedge/api/apis/operations/v1alpha1.ImagePrePullStatus":       schema_api_apis_operations_v1alpha1_ImagePrePullStatus(ref),
 		"github.com/kubeedge/api/apis/operations/v1alpha1.ImagePrePullTemplate":     schema_api_apis_operations_v1alpha1_ImagePrePullTemplate(ref),
 		"github.com/kubeedge/api/apis/operations/v1alpha1.ImageStatus":              schema_api_apis_operations_v1alpha1_ImageStatus(ref),
+		"github.com/kubeedge/api/apis/operations/v1alpha1.ImageVerificationPolicy":  schema_api_apis_operations_v1alpha1_ImageVerificationPolicy(ref),
 		"github.com/kubeedge/api/apis/operations/v1alpha1.NodeUpgradeJob":           schema_api_apis_operations_v1alpha1_NodeUpgradeJob(ref),
 		"github.com/kubeedge/api/apis/operations/v1alpha1.NodeUpgradeJobList":       schema_api_apis_operations_v1alpha1_NodeUpgradeJobList(ref),
 		"github.com/kubeedge/api/apis/operations/v1alpha1.NodeUpgradeJobSpec":       schema_api_apis_operations_v1alpha1_NodeUpgradeJobSpec(ref),
@@ -435,6 +448,36 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 	}
 }
 
+func schema_api_apis_apps_v1alpha1_AffinityOverrider(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AffinityOverrider represents the rules dedicated to handling affinity overrides.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"operator": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Operator represents the operator which will apply on the affinity.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value to be applied to affinity. Must not be empty when operator is 'replace'.",
+							Ref:         ref("k8s.io/api/core/v1.Affinity"),
+						},
+					},
+				},
+				Required: []string{"operator"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.Affinity"},
+	}
+}
+
 func schema_api_apis_apps_v1alpha1_CommandArgsOverrider(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -599,12 +642,24 @@ func schema_api_apis_apps_v1alpha1_EdgeApplicationSpec(ref common.ReferenceCallb
 							Ref:         ref("github.com/kubeedge/api/apis/apps/v1alpha1.WorkloadScope"),
 						},
 					},
+					"rolloutStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RolloutStrategy represents how to roll out the changes of the workload to the target node groups. Defaults to update all the node groups at once.",
+							Ref:         ref("github.com/kubeedge/api/apis/apps/v1alpha1.RolloutStrategy"),
+						},
+					},
+					"rollbackTo": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RollbackTo is the revision that the workload will be rolled back to. It will be cleared after the spec is restored from the revision.",
+							Ref:         ref("github.com/kubeedge/api/apis/apps/v1alpha1.RollbackConfig"),
+						},
+					},
 				},
 				Required: []string{"workloadScope"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubeedge/api/apis/apps/v1alpha1.ResourceTemplate", "github.com/kubeedge/api/apis/apps/v1alpha1.WorkloadScope"},
+			"github.com/kubeedge/api/apis/apps/v1alpha1.ResourceTemplate", "github.com/kubeedge/api/apis/apps/v1alpha1.RollbackConfig", "github.com/kubeedge/api/apis/apps/v1alpha1.RolloutStrategy", "github.com/kubeedge/api/apis/apps/v1alpha1.WorkloadScope"},
 	}
 }
 
@@ -629,11 +684,39 @@ func schema_api_apis_apps_v1alpha1_EdgeApplicationStatus(ref common.ReferenceCal
 							},
 						},
 					},
+					"nodeGroupStatus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NodeGroupStatus contains the aggregated statuses of the workloads in each target node group.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubeedge/api/apis/apps/v1alpha1.NodeGroupWorkloadStatus"),
+									},
+								},
+							},
+						},
+					},
+					"updateRevision": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UpdateRevision is the name of the ControllerRevision of the current spec, which all the target node groups will be updated to.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"collisionCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CollisionCount is the count of hash collisions of the revisions of the EdgeApplication. It is used as a collision avoidance mechanism when the name of a new revision is computed.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubeedge/api/apis/apps/v1alpha1.ManifestStatus"},
+			"github.com/kubeedge/api/apis/apps/v1alpha1.ManifestStatus", "github.com/kubeedge/api/apis/apps/v1alpha1.NodeGroupWorkloadStatus"},
 	}
 }
 
@@ -750,6 +833,89 @@ func schema_api_apis_apps_v1alpha1_ImagePredicate(ref common.ReferenceCallback)
 	}
 }
 
+func schema_api_apis_apps_v1alpha1_JSONPatchOverrider(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "JSONPatchOverrider represents a JSON patch (RFC 6902) operation that would apply on the resource.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"operator": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Operator represents the operation of the patch.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path is the JSON pointer of the target field, e.g. /spec/template/spec/hostNetwork. The apiVersion, kind, name, namespace and ownerReferences of the resource cannot be patched.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"from": {
+						SchemaProps: spec.SchemaProps{
+							Description: "From is the JSON pointer of the source field. Must not be empty when operator is 'move' or 'copy'.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value to be applied to the target field. Must not be empty when operator is 'add', 'replace' or 'test'.",
+							Ref:         ref("k8s.io/apimachinery/pkg/runtime.RawExtension"),
+						},
+					},
+				},
+				Required: []string{"operator", "path"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/runtime.RawExtension"},
+	}
+}
+
+func schema_api_apis_apps_v1alpha1_LabelAnnotationOverrider(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LabelAnnotationOverrider represents the rules dedicated to handling labels/annotations overrides.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"operator": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Operator represents the operator which will apply on the labels/annotations.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value to be applied to labels/annotations. Items in Value will be added or overwritten when Operator is 'add' or 'replace'. Keys in Value will be deleted when Operator is 'remove', and the values are ignored.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"operator"},
+			},
+		},
+	}
+}
+
 func schema_api_apis_apps_v1alpha1_Manifest(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -782,12 +948,25 @@ func schema_api_apis_apps_v1alpha1_ManifestStatus(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"nodeGroup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NodeGroup is the node group which the resource is deployed to. It is empty if the resource is shared by all the target node groups.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"replicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Replicas contains the replica counts of the workload. It is nil if the resource does not have replicas, such as Service and Job.",
+							Ref:         ref("github.com/kubeedge/api/apis/apps/v1alpha1.WorkloadReplicas"),
+						},
+					},
 				},
 				Required: []string{"identifier"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubeedge/api/apis/apps/v1alpha1.ResourceIdentifier"},
+			"github.com/kubeedge/api/apis/apps/v1alpha1.ResourceIdentifier", "github.com/kubeedge/api/apis/apps/v1alpha1.WorkloadReplicas"},
 	}
 }
 
@@ -927,9 +1106,32 @@ func schema_api_apis_apps_v1alpha1_NodeGroupSpec(ref common.ReferenceCallback) c
 							},
 						},
 					},
+					"labelSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LabelSelector is used to select nodes by labels, including matchExpressions. Nodes selected by Nodes, MatchLabels or LabelSelector are all members of the nodegroup. An empty LabelSelector selects all nodes.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"excludeNodes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExcludeNodes contains names of nodes that will never be members of the nodegroup, even if they are selected by Nodes, MatchLabels or LabelSelector.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
 	}
 }
 
@@ -954,11 +1156,100 @@ func schema_api_apis_apps_v1alpha1_NodeGroupStatus(ref common.ReferenceCallback)
 							},
 						},
 					},
+					"totalNodes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TotalNodes is the number of nodes that are members of this NodeGroup.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"readyNodes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadyNodes is the number of ready nodes that are members of this NodeGroup.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"allocatable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Allocatable is the sum of allocatable cpu and memory of the ready nodes that are members of this NodeGroup.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/api/resource.Quantity"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubeedge/api/apis/apps/v1alpha1.NodeStatus"},
+			"github.com/kubeedge/api/apis/apps/v1alpha1.NodeStatus", "k8s.io/apimachinery/pkg/api/resource.Quantity"},
+	}
+}
+
+func schema_api_apis_apps_v1alpha1_NodeGroupWorkloadStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeGroupWorkloadStatus contains the aggregated status of the workloads in a node group.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"nodeGroup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NodeGroup is the name of the target node group.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ready": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ready represents whether all the manifests deployed to this node group, including the ones shared by all the node groups, are available.",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"replicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Replicas is the sum of desired replicas of the workloads in this node group.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"readyReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadyReplicas is the sum of ready replicas of the workloads in this node group.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"availableReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AvailableReplicas is the sum of available replicas of the workloads in this node group.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"revision": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Revision is the name of the ControllerRevision that is applied to this node group.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"nodeGroup"},
+			},
+		},
 	}
 }
 
@@ -1091,11 +1382,101 @@ func schema_api_apis_apps_v1alpha1_Overriders(ref common.ReferenceCallback) comm
 							},
 						},
 					},
+					"labelsOverriders": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LabelsOverriders represents the rules dedicated to handling labels of the resource",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubeedge/api/apis/apps/v1alpha1.LabelAnnotationOverrider"),
+									},
+								},
+							},
+						},
+					},
+					"annotationsOverriders": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AnnotationsOverriders represents the rules dedicated to handling annotations of the resource",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubeedge/api/apis/apps/v1alpha1.LabelAnnotationOverrider"),
+									},
+								},
+							},
+						},
+					},
+					"tolerationsOverriders": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TolerationsOverriders represents the rules dedicated to handling tolerations of the pod",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubeedge/api/apis/apps/v1alpha1.TolerationsOverrider"),
+									},
+								},
+							},
+						},
+					},
+					"volumesOverriders": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VolumesOverriders represents the rules dedicated to handling volumes of the pod",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubeedge/api/apis/apps/v1alpha1.VolumesOverrider"),
+									},
+								},
+							},
+						},
+					},
+					"volumeMountsOverriders": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VolumeMountsOverriders represents the rules dedicated to handling volumeMounts of the container",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubeedge/api/apis/apps/v1alpha1.VolumeMountsOverrider"),
+									},
+								},
+							},
+						},
+					},
+					"affinityOverrider": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AffinityOverrider will override the affinity field of the pod",
+							Ref:         ref("github.com/kubeedge/api/apis/apps/v1alpha1.AffinityOverrider"),
+						},
+					},
+					"jsonPatchOverriders": {
+						SchemaProps: spec.SchemaProps{
+							Description: "JSONPatchOverriders represents the JSON patches (RFC 6902) that would apply on the resource. They are applied after all the other overriders.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubeedge/api/apis/apps/v1alpha1.JSONPatchOverrider"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubeedge/api/apis/apps/v1alpha1.CommandArgsOverrider", "github.com/kubeedge/api/apis/apps/v1alpha1.EnvOverrider", "github.com/kubeedge/api/apis/apps/v1alpha1.ImageOverrider", "github.com/kubeedge/api/apis/apps/v1alpha1.ResourcesOverrider"},
+			"github.com/kubeedge/api/apis/apps/v1alpha1.AffinityOverrider", "github.com/kubeedge/api/apis/apps/v1alpha1.CommandArgsOverrider", "github.com/kubeedge/api/apis/apps/v1alpha1.EnvOverrider", "github.com/kubeedge/api/apis/apps/v1alpha1.ImageOverrider", "github.com/kubeedge/api/apis/apps/v1alpha1.JSONPatchOverrider", "github.com/kubeedge/api/apis/apps/v1alpha1.LabelAnnotationOverrider", "github.com/kubeedge/api/apis/apps/v1alpha1.ResourcesOverrider", "github.com/kubeedge/api/apis/apps/v1alpha1.TolerationsOverrider", "github.com/kubeedge/api/apis/apps/v1alpha1.VolumeMountsOverrider", "github.com/kubeedge/api/apis/apps/v1alpha1.VolumesOverrider"},
 	}
 }
 
@@ -1116,86 +1497,268 @@ func schema_api_apis_apps_v1alpha1_ResourceIdentifier(ref common.ReferenceCallba
 					},
 					"group": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Group is the group of the resource.",
+							Description: "Group is the group of the resource.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"version": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Version is the version of the resource.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is the kind of the resource.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resource is the resource type of the resource",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace is the namespace of the resource",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the resource",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"ordinal"},
+			},
+		},
+	}
+}
+
+func schema_api_apis_apps_v1alpha1_ResourceTemplate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ResourceTemplate represents original templates of resources to be deployed as an EdgeApplication.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"manifests": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Manifests represent a list of Kubernetes resources to be deployed on the managed node groups.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/kubeedge/api/apis/apps/v1alpha1.Manifest"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubeedge/api/apis/apps/v1alpha1.Manifest"},
+	}
+}
+
+func schema_api_apis_apps_v1alpha1_ResourcesOverrider(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ResourcesOverrider represents the rules dedicated to handling resources overrides.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"containerName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of container",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value to be applied to resources. Must not be empty",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/api/core/v1.ResourceRequirements"),
+						},
+					},
+				},
+				Required: []string{"containerName"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.ResourceRequirements"},
+	}
+}
+
+func schema_api_apis_apps_v1alpha1_RollbackConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RollbackConfig represents the revision to roll back to.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"revision": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Revision to roll back to. If it is 0, the workload will be rolled back to the last revision.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_api_apis_apps_v1alpha1_RollingUpdateNodeGroups(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RollingUpdateNodeGroups contains the parameters of the RollingUpdate strategy.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"maxUnavailableGroups": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxUnavailableGroups is the maximum number of node groups that can be updated at the same time, which is the size of a wave. Defaults to 1, which updates the node groups one by one.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_api_apis_apps_v1alpha1_RolloutStrategy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RolloutStrategy represents how to roll out the changes of the workload to the target node groups.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type of the rollout strategy, can be \"AllAtOnce\" or \"RollingUpdate\". Defaults to AllAtOnce.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"version": {
+					"rollingUpdate": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Version is the version of the resource.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "RollingUpdate contains the parameters of the RollingUpdate strategy.",
+							Ref:         ref("github.com/kubeedge/api/apis/apps/v1alpha1.RollingUpdateNodeGroups"),
 						},
 					},
-					"kind": {
+					"paused": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is the kind of the resource.",
-							Type:        []string{"string"},
+							Description: "Paused indicates that the rollout is paused. The node groups that have not been updated keep running the previous revision until the rollout is resumed.",
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
-					"resource": {
+					"revisionHistoryLimit": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Resource is the resource type of the resource",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "RevisionHistoryLimit is the number of old revisions to retain to allow rollback. Defaults to 10.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"namespace": {
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubeedge/api/apis/apps/v1alpha1.RollingUpdateNodeGroups"},
+	}
+}
+
+func schema_api_apis_apps_v1alpha1_TargetNodeGroup(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TargetNodeGroup represents the target node group of workload to be deployed, including override rules to apply for this node group.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Namespace is the namespace of the resource",
+							Description: "Name represents the name of target node group",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"name": {
+					"overriders": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name is the name of the resource",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Overriders represents the override rules that would apply on workload.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/kubeedge/api/apis/apps/v1alpha1.Overriders"),
 						},
 					},
 				},
-				Required: []string{"ordinal"},
+				Required: []string{"name"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/kubeedge/api/apis/apps/v1alpha1.Overriders"},
 	}
 }
 
-func schema_api_apis_apps_v1alpha1_ResourceTemplate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_api_apis_apps_v1alpha1_TolerationsOverrider(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ResourceTemplate represents original templates of resources to be deployed as an EdgeApplication.",
+				Description: "TolerationsOverrider represents the rules dedicated to handling tolerations overrides.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"manifests": {
+					"operator": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Manifests represent a list of Kubernetes resources to be deployed on the managed node groups.",
+							Description: "Operator represents the operator which will apply on the tolerations.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value to be applied to tolerations. Items in Value will be appended when Operator is 'add'. Tolerations with the same key as items in Value will be deleted when Operator is 'remove'. The tolerations will be replaced with Value when Operator is 'replace'.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("github.com/kubeedge/api/apis/apps/v1alpha1.Manifest"),
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/api/core/v1.Toleration"),
 									},
 								},
 							},
 						},
 					},
 				},
+				Required: []string{"operator"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubeedge/api/apis/apps/v1alpha1.Manifest"},
+			"k8s.io/api/core/v1.Toleration"},
 	}
 }
 
-func schema_api_apis_apps_v1alpha1_ResourcesOverrider(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_api_apis_apps_v1alpha1_VolumeMountsOverrider(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ResourcesOverrider represents the rules dedicated to handling resources overrides.",
+				Description: "VolumeMountsOverrider represents the rules dedicated to handling volumeMounts overrides.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"containerName": {
@@ -1206,50 +1769,109 @@ func schema_api_apis_apps_v1alpha1_ResourcesOverrider(ref common.ReferenceCallba
 							Format:      "",
 						},
 					},
+					"operator": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Operator represents the operator which will apply on the volumeMounts.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"value": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Value to be applied to resources. Must not be empty",
-							Default:     map[string]interface{}{},
-							Ref:         ref("k8s.io/api/core/v1.ResourceRequirements"),
+							Description: "Value to be applied to volumeMounts, volumeMounts are matched by mountPath. Items in Value will be added or overwritten when Operator is 'add' or 'replace'. The matched volumeMounts will be deleted when Operator is 'remove'.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/api/core/v1.VolumeMount"),
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"containerName"},
+				Required: []string{"containerName", "operator"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.ResourceRequirements"},
+			"k8s.io/api/core/v1.VolumeMount"},
 	}
 }
 
-func schema_api_apis_apps_v1alpha1_TargetNodeGroup(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_api_apis_apps_v1alpha1_VolumesOverrider(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "TargetNodeGroup represents the target node group of workload to be deployed, including override rules to apply for this node group.",
+				Description: "VolumesOverrider represents the rules dedicated to handling volumes overrides.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"operator": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name represents the name of target node group",
+							Description: "Operator represents the operator which will apply on the volumes.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"overriders": {
+					"value": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Overriders represents the override rules that would apply on workload.",
-							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/kubeedge/api/apis/apps/v1alpha1.Overriders"),
+							Description: "Value to be applied to volumes, volumes are matched by name. Items in Value will be added or overwritten when Operator is 'add' or 'replace'. The matched volumes will be deleted when Operator is 'remove'.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/api/core/v1.Volume"),
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"name"},
+				Required: []string{"operator"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubeedge/api/apis/apps/v1alpha1.Overriders"},
+			"k8s.io/api/core/v1.Volume"},
+	}
+}
+
+func schema_api_apis_apps_v1alpha1_WorkloadReplicas(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "WorkloadReplicas contains the replica counts of a workload.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"replicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Replicas is the number of desired replicas. For DaemonSet, it is the number of nodes that should be running the daemon pod.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"readyReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadyReplicas is the number of replicas whose pods are ready.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"availableReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AvailableReplicas is the number of replicas whose pods are available.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -3195,6 +3817,13 @@ func schema_api_apis_devices_v1beta1_DeviceStatus(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Optional: The reason of the state, e.g. why the device is offline.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -3673,6 +4302,40 @@ func schema_api_apis_devices_v1beta1_VisitorConfig(ref common.ReferenceCallback)
 	}
 }
 
+func schema_api_apis_operations_v1alpha1_CheckResult(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CheckResult stores the result of a check item on an edge node.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the check item.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"result": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Result represents for the result of the check item. There are three possible result values: Passed, Failed, Unsupported.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message represents for the details of the check item, such as the reason of failure.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_api_apis_operations_v1alpha1_ImagePrePullJob(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3940,7 +4603,7 @@ func schema_api_apis_operations_v1alpha1_ImagePrePullTemplate(ref common.Referen
 					},
 					"checkItems": {
 						SchemaProps: spec.SchemaProps{
-							Description: "CheckItems specifies the items need to be checked before the task is executed. The default CheckItems value is disk.",
+							Description: "CheckItems specifies the items need to be checked before the task is executed. The built-in check items are cpu, mem, disk, inode, runtime, registry, clock, cloud and pods, other items are user-defined checks run by the executable hooks of the same names on edge nodes. The default CheckItems value is disk.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -3988,11 +4651,40 @@ func schema_api_apis_operations_v1alpha1_ImagePrePullTemplate(ref common.Referen
 							Format:      "int32",
 						},
 					},
+					"distributionMode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DistributionMode specifies how images are distributed to edge nodes. There are two possible values: Registry, P2P. In Registry mode, every edge node pulls images from the registry. In P2P mode, edge nodes of a node group pull images through the image mirror of the seed node of the node group, so images are pulled from the registry once for each node group. Edge nodes fall back to the registry if the seed node is unreachable, and edge nodes not in any node group pull from the registry. The default DistributionMode value is Registry.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"seedNodes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SeedNodes specifies the seed node of node groups in P2P mode, the key is the name of the node group and the value is the name of the seed node. The image mirror of edgecore must be enabled on seed nodes. The seed node of a node group not specified is the first node of the node group by name.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"verificationPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VerificationPolicy specifies how images are verified on edge nodes before they are pulled. Images failing the verification are not pulled, and the reasons are reported in ImageStatus.",
+							Ref:         ref("github.com/kubeedge/api/apis/operations/v1alpha1.ImageVerificationPolicy"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+			"github.com/kubeedge/api/apis/operations/v1alpha1.ImageVerificationPolicy", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
 	}
 }
 
@@ -4024,6 +4716,63 @@ func schema_api_apis_operations_v1alpha1_ImageStatus(ref common.ReferenceCallbac
 							Format:      "",
 						},
 					},
+					"source": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Source represents where the image is pulled from, it is Registry or the name of the seed node the image is pulled through.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_api_apis_operations_v1alpha1_ImageVerificationPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ImageVerificationPolicy specifies how images are verified on edge nodes before they are used.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"requireDigest": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RequireDigest requires images to be referenced by digest, e.g. nginx@sha256:<digest>.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"allowedRegistries": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AllowedRegistries is the list of registries images are allowed to be pulled from. Each item is a registry host such as docker.io, or a registry host with a repository prefix such as docker.io/library. Images from any registry are allowed if it is empty.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"cosignPublicKeys": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CosignPublicKeys is the list of PEM encoded public keys. If it is not empty, images must have a cosign signature in the registry that is verified by one of the keys.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -4187,7 +4936,7 @@ func schema_api_apis_operations_v1alpha1_NodeUpgradeJobSpec(ref common.Reference
 					},
 					"checkItems": {
 						SchemaProps: spec.SchemaProps{
-							Description: "CheckItems specifies the items need to be checked before the task is executed. The default CheckItems value is nil.",
+							Description: "CheckItems specifies the items need to be checked before the task is executed. The built-in check items are cpu, mem, disk, inode, runtime, registry, clock, cloud and pods, other items are user-defined checks run by the executable hooks of the same names on edge nodes. The default CheckItems value is nil.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -4342,9 +5091,25 @@ func schema_api_apis_operations_v1alpha1_TaskStatus(ref common.ReferenceCallback
 							Format:      "",
 						},
 					},
+					"checkResults": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CheckResults represents for the result of each check item on the edge node.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kubeedge/api/apis/operations/v1alpha1.CheckResult"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"github.com/kubeedge/api/apis/operations/v1alpha1.CheckResult"},
 	}
 }
 
@@ -5097,7 +5862,7 @@ func schema_api_apis_rules_v1_RuleSpec(ref common.ReferenceCallback) common.Open
 					},
 					"sourceResource": {
 						SchemaProps: spec.SchemaProps{
-							Description: "SourceResource is a map representing the resource info of source. For rest ruleendpoint type its value is {\"path\":\"/a/b\"}. For eventbus ruleendpoint type its value is {\"topic\":\"<user define string>\",\"node_name\":\"xxxx\"}",
+							Description: "SourceResource is a map representing the resource info of source. For rest ruleendpoint type its value is {\"path\":\"/a/b\"}. For eventbus ruleendpoint type its value is {\"topic\":\"<user define string>\",\"node_name\":\"xxxx\"} or {\"topic\":\"<user define string>\",\"node_group\":\"xxxx\"} to subscribe the topic on every node of the nodegroup.",
 							Type:        []string{"object"},
 							AdditionalProperties: &spec.SchemaOrBool{
 								Allows: true,
@@ -5121,7 +5886,7 @@ func schema_api_apis_rules_v1_RuleSpec(ref common.ReferenceCallback) common.Open
 					},
 					"targetResource": {
 						SchemaProps: spec.SchemaProps{
-							Description: "targetResource is a map representing the resource info of target. For api ruleendpoint type its value is {\"resource\":\"http://a.com\"}. For eventbus ruleendpoint type its value is {\"topic\":\"/xxxx\"}. For servicebus ruleendpoint type its value is {\"path\":\"/request_path\"}.",
+							Description: "targetResource is a map representing the resource info of target. For api ruleendpoint type its value is {\"resource\":\"http://a.com\"}. For eventbus ruleendpoint type its value is {\"topic\":\"/xxxx\"}. For servicebus ruleendpoint type its value is {\"path\":\"/request_path\"}. Eventbus and servicebus targets may also address edge nodes directly with \"node_name\" (comma separated node names), \"node_group\" and \"node_selector\" (label selector of nodes), messages are then fanned out to all the addressed nodes except the node the message comes from.",
 							Type:        []string{"object"},
 							AdditionalProperties: &spec.SchemaOrBool{
 								Allows: true,
@@ -29,6 +29,62 @@ type EdgeApplicationSpec struct {
 	WorkloadTemplate ResourceTemplate `json:"workloadTemplate,omitempty"`
 	// WorkloadScope represents which node groups the workload will be deployed in.
 	WorkloadScope WorkloadScope `json:"workloadScope"`
+	// RolloutStrategy represents how to roll out the changes of the workload to the target node groups.
+	// Defaults to update all the node groups at once.
+	// +optional
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+	// RollbackTo is the revision that the workload will be rolled back to. It will be
+	// cleared after the spec is restored from the revision.
+	// +optional
+	RollbackTo *RollbackConfig `json:"rollbackTo,omitempty"`
+}
+
+// RolloutStrategyType is the type of the rollout strategy.
+type RolloutStrategyType string
+
+const (
+	// AllAtOnceRolloutStrategyType updates all the node groups at once.
+	AllAtOnceRolloutStrategyType RolloutStrategyType = "AllAtOnce"
+	// RollingUpdateRolloutStrategyType updates the node groups in waves, the next wave
+	// starts after the workloads in the node groups of the previous wave are available.
+	RollingUpdateRolloutStrategyType RolloutStrategyType = "RollingUpdate"
+)
+
+// RolloutStrategy represents how to roll out the changes of the workload to the target node groups.
+type RolloutStrategy struct {
+	// Type of the rollout strategy, can be "AllAtOnce" or "RollingUpdate". Defaults to AllAtOnce.
+	// +kubebuilder:validation:Enum=AllAtOnce;RollingUpdate
+	// +optional
+	Type RolloutStrategyType `json:"type,omitempty"`
+	// RollingUpdate contains the parameters of the RollingUpdate strategy.
+	// +optional
+	RollingUpdate *RollingUpdateNodeGroups `json:"rollingUpdate,omitempty"`
+	// Paused indicates that the rollout is paused. The node groups that have not been
+	// updated keep running the previous revision until the rollout is resumed.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+	// RevisionHistoryLimit is the number of old revisions to retain to allow rollback.
+	// Defaults to 10.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+}
+
+// RollingUpdateNodeGroups contains the parameters of the RollingUpdate strategy.
+type RollingUpdateNodeGroups struct {
+	// MaxUnavailableGroups is the maximum number of node groups that can be updated at the same time,
+	// which is the size of a wave. Defaults to 1, which updates the node groups one by one.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxUnavailableGroups *int32 `json:"maxUnavailableGroups,omitempty"`
+}
+
+// RollbackConfig represents the revision to roll back to.
+type RollbackConfig struct {
+	// Revision to roll back to. If it is 0, the workload will be rolled back to the last revision.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Revision int64 `json:"revision,omitempty"`
 }
 
 // WorkloadScope represents which node groups the workload should be deployed in.
@@ -75,8 +131,150 @@ type Overriders struct {
 	// ResourcesOverriders will override the resources field of the container
 	// +optional
 	ResourcesOverriders []ResourcesOverrider `json:"resourcesOverriders,omitempty"`
+	// LabelsOverriders represents the rules dedicated to handling labels of the resource
+	// +optional
+	LabelsOverriders []LabelAnnotationOverrider `json:"labelsOverriders,omitempty"`
+	// AnnotationsOverriders represents the rules dedicated to handling annotations of the resource
+	// +optional
+	AnnotationsOverriders []LabelAnnotationOverrider `json:"annotationsOverriders,omitempty"`
+	// TolerationsOverriders represents the rules dedicated to handling tolerations of the pod
+	// +optional
+	TolerationsOverriders []TolerationsOverrider `json:"tolerationsOverriders,omitempty"`
+	// VolumesOverriders represents the rules dedicated to handling volumes of the pod
+	// +optional
+	VolumesOverriders []VolumesOverrider `json:"volumesOverriders,omitempty"`
+	// VolumeMountsOverriders represents the rules dedicated to handling volumeMounts of the container
+	// +optional
+	VolumeMountsOverriders []VolumeMountsOverrider `json:"volumeMountsOverriders,omitempty"`
+	// AffinityOverrider will override the affinity field of the pod
+	// +optional
+	AffinityOverrider *AffinityOverrider `json:"affinityOverrider,omitempty"`
+	// JSONPatchOverriders represents the JSON patches (RFC 6902) that would apply on the resource.
+	// They are applied after all the other overriders.
+	// +optional
+	JSONPatchOverriders []JSONPatchOverrider `json:"jsonPatchOverriders,omitempty"`
+}
+
+// LabelAnnotationOverrider represents the rules dedicated to handling labels/annotations overrides.
+type LabelAnnotationOverrider struct {
+	// Operator represents the operator which will apply on the labels/annotations.
+	// +kubebuilder:validation:Enum=add;remove;replace
+	// +required
+	Operator OverriderOperator `json:"operator"`
+
+	// Value to be applied to labels/annotations.
+	// Items in Value will be added or overwritten when Operator is 'add' or 'replace'.
+	// Keys in Value will be deleted when Operator is 'remove', and the values are ignored.
+	// +optional
+	Value map[string]string `json:"value,omitempty"`
+}
+
+// TolerationsOverrider represents the rules dedicated to handling tolerations overrides.
+type TolerationsOverrider struct {
+	// Operator represents the operator which will apply on the tolerations.
+	// +kubebuilder:validation:Enum=add;remove;replace
+	// +required
+	Operator OverriderOperator `json:"operator"`
+
+	// Value to be applied to tolerations.
+	// Items in Value will be appended when Operator is 'add'.
+	// Tolerations with the same key as items in Value will be deleted when Operator is 'remove'.
+	// The tolerations will be replaced with Value when Operator is 'replace'.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Value []corev1.Toleration `json:"value,omitempty"`
+}
+
+// VolumesOverrider represents the rules dedicated to handling volumes overrides.
+type VolumesOverrider struct {
+	// Operator represents the operator which will apply on the volumes.
+	// +kubebuilder:validation:Enum=add;remove;replace
+	// +required
+	Operator OverriderOperator `json:"operator"`
+
+	// Value to be applied to volumes, volumes are matched by name.
+	// Items in Value will be added or overwritten when Operator is 'add' or 'replace'.
+	// The matched volumes will be deleted when Operator is 'remove'.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Value []corev1.Volume `json:"value,omitempty"`
+}
+
+// VolumeMountsOverrider represents the rules dedicated to handling volumeMounts overrides.
+type VolumeMountsOverrider struct {
+	// The name of container
+	// +required
+	ContainerName string `json:"containerName"`
+
+	// Operator represents the operator which will apply on the volumeMounts.
+	// +kubebuilder:validation:Enum=add;remove;replace
+	// +required
+	Operator OverriderOperator `json:"operator"`
+
+	// Value to be applied to volumeMounts, volumeMounts are matched by mountPath.
+	// Items in Value will be added or overwritten when Operator is 'add' or 'replace'.
+	// The matched volumeMounts will be deleted when Operator is 'remove'.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Value []corev1.VolumeMount `json:"value,omitempty"`
 }
 
+// AffinityOverrider represents the rules dedicated to handling affinity overrides.
+type AffinityOverrider struct {
+	// Operator represents the operator which will apply on the affinity.
+	// +kubebuilder:validation:Enum=remove;replace
+	// +required
+	Operator OverriderOperator `json:"operator"`
+
+	// Value to be applied to affinity.
+	// Must not be empty when operator is 'replace'.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Value *corev1.Affinity `json:"value,omitempty"`
+}
+
+// JSONPatchOverrider represents a JSON patch (RFC 6902) operation that would apply on the resource.
+type JSONPatchOverrider struct {
+	// Operator represents the operation of the patch.
+	// +kubebuilder:validation:Enum=add;remove;replace;move;copy;test
+	// +required
+	Operator JSONPatchOperator `json:"operator"`
+
+	// Path is the JSON pointer of the target field, e.g. /spec/template/spec/hostNetwork.
+	// The apiVersion, kind, name, namespace and ownerReferences of the resource cannot be patched.
+	// +required
+	Path string `json:"path"`
+
+	// From is the JSON pointer of the source field.
+	// Must not be empty when operator is 'move' or 'copy'.
+	// +optional
+	From string `json:"from,omitempty"`
+
+	// Value to be applied to the target field.
+	// Must not be empty when operator is 'add', 'replace' or 'test'.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Value *runtime.RawExtension `json:"value,omitempty"`
+}
+
+// JSONPatchOperator is the set of operations defined in RFC 6902.
+type JSONPatchOperator string
+
+// These are valid JSON patch operators.
+const (
+	JSONPatchOpAdd     JSONPatchOperator = "add"
+	JSONPatchOpRemove  JSONPatchOperator = "remove"
+	JSONPatchOpReplace JSONPatchOperator = "replace"
+	JSONPatchOpMove    JSONPatchOperator = "move"
+	JSONPatchOpCopy    JSONPatchOperator = "copy"
+	JSONPatchOpTest    JSONPatchOperator = "test"
+)
+
 // CommandArgsOverrider represents the rules dedicated to handling command/args overrides.
 type CommandArgsOverrider struct {
 	// The name of container
@@ -215,6 +413,20 @@ type EdgeApplicationStatus struct {
 	// WorkloadStatus contains running statuses of generated resources.
 	// +optional
 	WorkloadStatus []ManifestStatus `json:"workloadStatus,omitempty"`
+
+	// NodeGroupStatus contains the aggregated statuses of the workloads in each target node group.
+	// +optional
+	NodeGroupStatus []NodeGroupWorkloadStatus `json:"nodeGroupStatus,omitempty"`
+
+	// UpdateRevision is the name of the ControllerRevision of the current spec, which
+	// all the target node groups will be updated to.
+	// +optional
+	UpdateRevision string `json:"updateRevision,omitempty"`
+
+	// CollisionCount is the count of hash collisions of the revisions of the EdgeApplication.
+	// It is used as a collision avoidance mechanism when the name of a new revision is computed.
+	// +optional
+	CollisionCount *int32 `json:"collisionCount,omitempty"`
 }
 
 // ManifestStatus contains running status of a specific manifest in spec.
@@ -230,6 +442,60 @@ type ManifestStatus struct {
 	// +kubebuilder:validation:Enum=Processing;Available
 	// +optional
 	Condition ManifestCondition `json:"conditions,omitempty"`
+
+	// NodeGroup is the node group which the resource is deployed to. It is empty
+	// if the resource is shared by all the target node groups.
+	// +optional
+	NodeGroup string `json:"nodeGroup,omitempty"`
+
+	// Replicas contains the replica counts of the workload. It is nil if the
+	// resource does not have replicas, such as Service and Job.
+	// +optional
+	Replicas *WorkloadReplicas `json:"replicas,omitempty"`
+}
+
+// WorkloadReplicas contains the replica counts of a workload.
+type WorkloadReplicas struct {
+	// Replicas is the number of desired replicas. For DaemonSet, it is the
+	// number of nodes that should be running the daemon pod.
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas is the number of replicas whose pods are ready.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// AvailableReplicas is the number of replicas whose pods are available.
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas"`
+}
+
+// NodeGroupWorkloadStatus contains the aggregated status of the workloads in a node group.
+type NodeGroupWorkloadStatus struct {
+	// NodeGroup is the name of the target node group.
+	// +required
+	NodeGroup string `json:"nodeGroup"`
+
+	// Ready represents whether all the manifests deployed to this node group,
+	// including the ones shared by all the node groups, are available.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// Replicas is the sum of desired replicas of the workloads in this node group.
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas is the sum of ready replicas of the workloads in this node group.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// AvailableReplicas is the sum of available replicas of the workloads in this node group.
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas"`
+
+	// Revision is the name of the ControllerRevision that is applied to this node group.
+	// +optional
+	Revision string `json:"revision,omitempty"`
 }
 
 // ResourceIdentifier provides the identifiers needed to interact with any arbitrary object.
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -29,6 +30,17 @@ type NodeGroupSpec struct {
 	// MatchLabels are used to select nodes that have these labels.
 	// +optional
 	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+
+	// LabelSelector is used to select nodes by labels, including matchExpressions.
+	// Nodes selected by Nodes, MatchLabels or LabelSelector are all members of the nodegroup.
+	// An empty LabelSelector selects all nodes.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// ExcludeNodes contains names of nodes that will never be members of the nodegroup,
+	// even if they are selected by Nodes, MatchLabels or LabelSelector.
+	// +optional
+	ExcludeNodes []string `json:"excludeNodes,omitempty"`
 }
 
 // NodeGroupStatus contains the observed status of all selected nodes in
@@ -38,6 +50,19 @@ type NodeGroupStatus struct {
 	// NodeStatuses is a status list of all selected nodes.
 	// +optional
 	NodeStatuses []NodeStatus `json:"nodeStatuses,omitempty"`
+
+	// TotalNodes is the number of nodes that are members of this NodeGroup.
+	// +optional
+	TotalNodes int32 `json:"totalNodes,omitempty"`
+
+	// ReadyNodes is the number of ready nodes that are members of this NodeGroup.
+	// +optional
+	ReadyNodes int32 `json:"readyNodes,omitempty"`
+
+	// Allocatable is the sum of allocatable cpu and memory of the ready nodes
+	// that are members of this NodeGroup.
+	// +optional
+	Allocatable corev1.ResourceList `json:"allocatable,omitempty"`
 }
 
 // NodeStatus contains status of node that selected by this NodeGroup.
@@ -86,6 +111,9 @@ const (
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster,shortName=ng
+// +kubebuilder:printcolumn:name="Total",type=integer,JSONPath=`.status.totalNodes`
+// +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.readyNodes`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // NodeGroup is the Schema for the nodegroups API
 type NodeGroup struct {
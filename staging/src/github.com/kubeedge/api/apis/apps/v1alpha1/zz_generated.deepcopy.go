@@ -23,9 +23,31 @@ package v1alpha1
 
 import (
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AffinityOverrider) DeepCopyInto(out *AffinityOverrider) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AffinityOverrider.
+func (in *AffinityOverrider) DeepCopy() *AffinityOverrider {
+	if in == nil {
+		return nil
+	}
+	out := new(AffinityOverrider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommandArgsOverrider) DeepCopyInto(out *CommandArgsOverrider) {
 	*out = *in
@@ -113,6 +135,16 @@ func (in *EdgeApplicationSpec) DeepCopyInto(out *EdgeApplicationSpec) {
 	*out = *in
 	in.WorkloadTemplate.DeepCopyInto(&out.WorkloadTemplate)
 	in.WorkloadScope.DeepCopyInto(&out.WorkloadScope)
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(RolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RollbackTo != nil {
+		in, out := &in.RollbackTo, &out.RollbackTo
+		*out = new(RollbackConfig)
+		**out = **in
+	}
 	return
 }
 
@@ -132,8 +164,20 @@ func (in *EdgeApplicationStatus) DeepCopyInto(out *EdgeApplicationStatus) {
 	if in.WorkloadStatus != nil {
 		in, out := &in.WorkloadStatus, &out.WorkloadStatus
 		*out = make([]ManifestStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeGroupStatus != nil {
+		in, out := &in.NodeGroupStatus, &out.NodeGroupStatus
+		*out = make([]NodeGroupWorkloadStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.CollisionCount != nil {
+		in, out := &in.CollisionCount, &out.CollisionCount
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -207,6 +251,50 @@ func (in *ImagePredicate) DeepCopy() *ImagePredicate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JSONPatchOverrider) DeepCopyInto(out *JSONPatchOverrider) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONPatchOverrider.
+func (in *JSONPatchOverrider) DeepCopy() *JSONPatchOverrider {
+	if in == nil {
+		return nil
+	}
+	out := new(JSONPatchOverrider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LabelAnnotationOverrider) DeepCopyInto(out *LabelAnnotationOverrider) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelAnnotationOverrider.
+func (in *LabelAnnotationOverrider) DeepCopy() *LabelAnnotationOverrider {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelAnnotationOverrider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Manifest) DeepCopyInto(out *Manifest) {
 	*out = *in
@@ -228,6 +316,11 @@ func (in *Manifest) DeepCopy() *Manifest {
 func (in *ManifestStatus) DeepCopyInto(out *ManifestStatus) {
 	*out = *in
 	out.Identifier = in.Identifier
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(WorkloadReplicas)
+		**out = **in
+	}
 	return
 }
 
@@ -317,6 +410,16 @@ func (in *NodeGroupSpec) DeepCopyInto(out *NodeGroupSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExcludeNodes != nil {
+		in, out := &in.ExcludeNodes, &out.ExcludeNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -338,6 +441,13 @@ func (in *NodeGroupStatus) DeepCopyInto(out *NodeGroupStatus) {
 		*out = make([]NodeStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.Allocatable != nil {
+		in, out := &in.Allocatable, &out.Allocatable
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -351,6 +461,22 @@ func (in *NodeGroupStatus) DeepCopy() *NodeGroupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupWorkloadStatus) DeepCopyInto(out *NodeGroupWorkloadStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupWorkloadStatus.
+func (in *NodeGroupWorkloadStatus) DeepCopy() *NodeGroupWorkloadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupWorkloadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
 	*out = *in
@@ -410,6 +536,53 @@ func (in *Overriders) DeepCopyInto(out *Overriders) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LabelsOverriders != nil {
+		in, out := &in.LabelsOverriders, &out.LabelsOverriders
+		*out = make([]LabelAnnotationOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AnnotationsOverriders != nil {
+		in, out := &in.AnnotationsOverriders, &out.AnnotationsOverriders
+		*out = make([]LabelAnnotationOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TolerationsOverriders != nil {
+		in, out := &in.TolerationsOverriders, &out.TolerationsOverriders
+		*out = make([]TolerationsOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumesOverriders != nil {
+		in, out := &in.VolumesOverriders, &out.VolumesOverriders
+		*out = make([]VolumesOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMountsOverriders != nil {
+		in, out := &in.VolumeMountsOverriders, &out.VolumeMountsOverriders
+		*out = make([]VolumeMountsOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AffinityOverrider != nil {
+		in, out := &in.AffinityOverrider, &out.AffinityOverrider
+		*out = new(AffinityOverrider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JSONPatchOverriders != nil {
+		in, out := &in.JSONPatchOverriders, &out.JSONPatchOverriders
+		*out = make([]JSONPatchOverrider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -479,6 +652,69 @@ func (in *ResourcesOverrider) DeepCopy() *ResourcesOverrider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollbackConfig) DeepCopyInto(out *RollbackConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackConfig.
+func (in *RollbackConfig) DeepCopy() *RollbackConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RollbackConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollingUpdateNodeGroups) DeepCopyInto(out *RollingUpdateNodeGroups) {
+	*out = *in
+	if in.MaxUnavailableGroups != nil {
+		in, out := &in.MaxUnavailableGroups, &out.MaxUnavailableGroups
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpdateNodeGroups.
+func (in *RollingUpdateNodeGroups) DeepCopy() *RollingUpdateNodeGroups {
+	if in == nil {
+		return nil
+	}
+	out := new(RollingUpdateNodeGroups)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
+	*out = *in
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(RollingUpdateNodeGroups)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
+func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetNodeGroup) DeepCopyInto(out *TargetNodeGroup) {
 	*out = *in
@@ -496,6 +732,91 @@ func (in *TargetNodeGroup) DeepCopy() *TargetNodeGroup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TolerationsOverrider) DeepCopyInto(out *TolerationsOverrider) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TolerationsOverrider.
+func (in *TolerationsOverrider) DeepCopy() *TolerationsOverrider {
+	if in == nil {
+		return nil
+	}
+	out := new(TolerationsOverrider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeMountsOverrider) DeepCopyInto(out *VolumeMountsOverrider) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeMountsOverrider.
+func (in *VolumeMountsOverrider) DeepCopy() *VolumeMountsOverrider {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeMountsOverrider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumesOverrider) DeepCopyInto(out *VolumesOverrider) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumesOverrider.
+func (in *VolumesOverrider) DeepCopy() *VolumesOverrider {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumesOverrider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadReplicas) DeepCopyInto(out *WorkloadReplicas) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadReplicas.
+func (in *WorkloadReplicas) DeepCopy() *WorkloadReplicas {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadReplicas)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadScope) DeepCopyInto(out *WorkloadScope) {
 	*out = *in
@@ -117,6 +117,9 @@ const (
 	DefaultDeviceEventBuffer         = 1
 	DefaultDeviceModelEventBuffer    = 1
 	DefaultUpdateDeviceStatusWorkers = 1
+	DefaultTwinHistoryStore          = "memory"
+	DefaultTwinHistoryDataSource     = "/var/lib/kubeedge/twinhistory.db"
+	DefaultTwinHistoryMaxSamples     = 1024
 
 	// TaskManager
 	DefaultNodeUpgradeJobStatusBuffer = 1024
@@ -133,6 +136,8 @@ const (
 
 	ResourceTypeService   = "service"
 	ResourceTypeEndpoints = "endpoints"
+	// ResourceTypeDigest is the resource of the digests of the objects cached in the edge database
+	ResourceTypeDigest = "digest"
 
 	ResourceTypePersistentVolume      = "persistentvolume"
 	ResourceTypePersistentVolumeClaim = "persistentvolumeclaim"
@@ -143,6 +148,13 @@ const (
 	CSIOperationTypeDeleteVolume              = "deletevolume"
 	CSIOperationTypeControllerPublishVolume   = "controllerpublishvolume"
 	CSIOperationTypeControllerUnpublishVolume = "controllerunpublishvolume"
+	CSIOperationTypeControllerExpandVolume    = "controllerexpandvolume"
+	CSIOperationTypeControllerGetVolume       = "controllergetvolume"
+	CSIOperationTypeListVolumes               = "listvolumes"
+	CSIOperationTypeGetCapacity               = "getcapacity"
+	CSIOperationTypeCreateSnapshot            = "createsnapshot"
+	CSIOperationTypeDeleteSnapshot            = "deletesnapshot"
+	CSIOperationTypeListSnapshots             = "listsnapshots"
 	CSISyncMsgRespTimeout                     = 1 * time.Minute
 
 	ServerAddress = "127.0.0.1"
@@ -172,7 +184,36 @@ const (
 	DefaultQuicPort      = 10001
 
 	// DeviceTwin
-	DefaultDMISockPath = "/etc/kubeedge/dmi.sock"
+	DefaultDMISockPath                = "/etc/kubeedge/dmi.sock"
+	DefaultEdgeTwinHistoryMaxSamples  = 100
+	DefaultEdgeTwinHistoryReportBatch = 100
+	DefaultEdgeTwinHistoryFlushPeriod = 10
+
+	// ImageMirror
+	DefaultImageMirrorPort     = 10553
+	DefaultImageMirrorCacheDir = "/var/lib/kubeedge/imagemirror"
+	DefaultContainerdHostsDir  = "/etc/containerd/certs.d"
+
+	// ExtensionModules
+	DefaultExtensionModuleBufferSize = 2 * 1024 * 1024
+
+	// MessageQueues
+	DefaultMessageQueueCapacity = 1024
+	DefaultMessageQueueSpillDir = "/var/lib/kubeedge/beehive"
+	// DefaultMessageQueueBlockTimeout is the time in seconds the sender waits with Block policy
+	DefaultMessageQueueBlockTimeout = 30
+	// DefaultMessageQueueMaxSpillSize is the max size in MB of the spill file of a module
+	DefaultMessageQueueMaxSpillSize = 100
+
+	// Admin
+	DefaultAdminUnixSocket = "/var/lib/kubeedge/admin.sock"
+
+	// MetaManager encryption
+	DefaultMetaEncryptionKeyFile = "/etc/kubeedge/encryption/key"
+	DefaultKMSTimeout            = 3
+
+	// MetaManager drift detection
+	DefaultMetaDigestPeriod = 600
 )
 
 const ISO8601UTC = "2006-01-02T15:04:05Z"
@@ -109,6 +109,12 @@ func NewDefaultCloudCoreConfig() *CloudCoreConfig {
 				Load: &DeviceControllerLoad{
 					UpdateDeviceStatusWorkers: constants.DefaultUpdateDeviceStatusWorkers,
 				},
+				TwinHistory: &DeviceTwinHistory{
+					Enable:     false,
+					Store:      constants.DefaultTwinHistoryStore,
+					DataSource: constants.DefaultTwinHistoryDataSource,
+					MaxSamples: constants.DefaultTwinHistoryMaxSamples,
+				},
 			},
 			TaskManager: &TaskManager{
 				Enable: false,
@@ -144,8 +150,9 @@ func NewDefaultCloudCoreConfig() *CloudCoreConfig {
 				RestTimeout: 60,
 			},
 			IptablesManager: &IptablesManager{
-				Enable: true,
-				Mode:   InternalMode,
+				Enable:  true,
+				Mode:    InternalMode,
+				Backend: IptablesBackend,
 			},
 		},
 	}
@@ -295,8 +302,9 @@ func NewMinCloudCoreConfig() *CloudCoreConfig {
 				RestTimeout: 60,
 			},
 			IptablesManager: &IptablesManager{
-				Enable: true,
-				Mode:   InternalMode,
+				Enable:  true,
+				Mode:    InternalMode,
+				Backend: IptablesBackend,
 			},
 		},
 	}
@@ -32,6 +32,13 @@ const (
 	ExternalMode IptablesMgrMode = "external"
 )
 
+type IptablesMgrBackend string
+
+const (
+	IptablesBackend IptablesMgrBackend = "iptables"
+	NftablesBackend IptablesMgrBackend = "nftables"
+)
+
 func (c *CloudCoreConfig) Parse(filename string) error {
 	data, err := os.ReadFile(filename)
 	if err != nil {
@@ -62,9 +69,15 @@ func (in *IptablesManager) UnmarshalJSON(data []byte) error {
 	// Validate the valid enum values
 	switch in.Mode {
 	case InternalMode, ExternalMode:
-		return nil
 	default:
 		in.Mode = ""
 		return errors.New("invalid value for iptablesmgr mode")
 	}
+	switch in.Backend {
+	case "", IptablesBackend, NftablesBackend:
+		return nil
+	default:
+		in.Backend = ""
+		return errors.New("invalid value for iptablesmgr backend")
+	}
 }
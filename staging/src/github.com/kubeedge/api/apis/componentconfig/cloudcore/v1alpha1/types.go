@@ -151,6 +151,10 @@ type CloudHub struct {
 	// TokenRefreshDuration indicates the interval of cloudcore token refresh, unit is hour
 	// default 12h
 	TokenRefreshDuration time.Duration `json:"tokenRefreshDuration,omitempty"`
+	// RequireBootstrapToken indicates whether edge nodes must join with a bootstrap token created by
+	// "keadm gettoken" for a node or node group, the shared token refreshed by cloudcore is rejected if true
+	// default false
+	RequireBootstrapToken bool `json:"requireBootstrapToken,omitempty"`
 	// Authorization authz configurations
 	Authorization *CloudHubAuthorization `json:"authorization,omitempty"`
 }
@@ -392,6 +396,28 @@ type DeviceController struct {
 	Buffer *DeviceControllerBuffer `json:"buffer,omitempty"`
 	// Load indicates DeviceController Load
 	Load *DeviceControllerLoad `json:"load,omitempty"`
+	// TwinHistory indicates the history of reported device twin values retained by deviceController
+	TwinHistory *DeviceTwinHistory `json:"twinHistory,omitempty"`
+}
+
+// DeviceTwinHistory indicates the config of device twin history. The history is queried through
+// the HTTPS server of cloudHub, authenticated by the bearer tokens of the Kubernetes users, who must
+// be allowed to get the devices.
+type DeviceTwinHistory struct {
+	// Enable indicates whether the history of reported twin values is retained
+	// default false
+	Enable bool `json:"enable"`
+	// Store indicates the backend of the history store, "memory" or "sqlite",
+	// backends registered by external adapters can also be used
+	// default "memory"
+	Store string `json:"store,omitempty"`
+	// DataSource indicates the data source of the history store,
+	// e.g. the database file of the sqlite store
+	// default "/var/lib/kubeedge/twinhistory.db"
+	DataSource string `json:"dataSource,omitempty"`
+	// MaxSamples indicates the max number of samples retained for each twin property of a device
+	// default 1024
+	MaxSamples int32 `json:"maxSamples,omitempty"`
 }
 
 // DeviceControllerBuffer indicates deviceController buffer
@@ -543,4 +569,9 @@ type IptablesManager struct {
 	// default internal.
 	// +kubebuilder:validation:Enum=internal;external
 	Mode IptablesMgrMode `json:"mode,omitempty"`
+	// Backend indicates how the rules are programmed, valid backend can use "iptables" or "nftables".
+	// The nftables backend is for hosts shipping without iptables-legacy, it requires the nft command.
+	// default iptables.
+	// +kubebuilder:validation:Enum=iptables;nftables
+	Backend IptablesMgrBackend `json:"backend,omitempty"`
 }
@@ -149,6 +149,11 @@ func ValidateModuleDeviceController(d v1alpha1.DeviceController) field.ErrorList
 	}
 
 	allErrs := field.ErrorList{}
+	if h := d.TwinHistory; h != nil && h.Enable {
+		if h.MaxSamples <= 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("twinHistory.maxSamples"), h.MaxSamples, "maxSamples need > 0"))
+		}
+	}
 	return allErrs
 }
 
@@ -294,6 +294,28 @@ func TestValidateModuleDeviceController(t *testing.T) {
 			},
 			expected: field.ErrorList{},
 		},
+		{
+			name: "case3 twin history enabled",
+			input: v1alpha1.DeviceController{
+				Enable: true,
+				TwinHistory: &v1alpha1.DeviceTwinHistory{
+					Enable:     true,
+					MaxSamples: 1024,
+				},
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case4 invalid twin history maxSamples",
+			input: v1alpha1.DeviceController{
+				Enable: true,
+				TwinHistory: &v1alpha1.DeviceTwinHistory{
+					Enable:     true,
+					MaxSamples: 0,
+				},
+			},
+			expected: field.ErrorList{field.Invalid(field.NewPath("twinHistory.maxSamples"), int32(0), "maxSamples need > 0")},
+		},
 	}
 
 	for _, c := range cases {
@@ -43,9 +43,11 @@ func NewDefaultEdgeCoreConfig() (config *EdgeCoreConfig) {
 			APIVersion: path.Join(GroupName, APIVersion),
 		},
 		DataBase: &DataBase{
-			DriverName: DataBaseDriverName,
-			AliasName:  DataBaseAliasName,
-			DataSource: DataBaseDataSource,
+			DriverName:     DataBaseDriverName,
+			AliasName:      DataBaseAliasName,
+			DataSource:     DataBaseDataSource,
+			MetaBackend:    MetaBackendSQLite,
+			BoltDataSource: DataBaseBoltDataSource,
 		},
 		Modules: &Modules{
 			Edged: &Edged{
@@ -130,6 +132,18 @@ func NewDefaultEdgeCoreConfig() (config *EdgeCoreConfig) {
 					ServiceAccountIssuers: []string{constants.DefaultServiceAccountIssuer},
 					DummyServer:           constants.DefaultDummyServerAddr,
 				},
+				Encryption: &MetaEncryption{
+					Enable:      false,
+					Resources:   []string{"secret", "serviceaccounttoken", "serviceaccountaccess"},
+					KeyProvider: MetaEncryptionProviderLocal,
+					KeyFile:     constants.DefaultMetaEncryptionKeyFile,
+					KMSTimeout:  constants.DefaultKMSTimeout,
+				},
+				DriftDetection: &MetaDriftDetection{
+					Enable:    false,
+					Period:    constants.DefaultMetaDigestPeriod,
+					Resources: []string{"configmap", "secret"},
+				},
 			},
 			ServiceBus: &ServiceBus{
 				Enable:  false,
@@ -140,6 +154,12 @@ func NewDefaultEdgeCoreConfig() (config *EdgeCoreConfig) {
 			DeviceTwin: &DeviceTwin{
 				Enable:      true,
 				DMISockPath: constants.DefaultDMISockPath,
+				TwinHistory: &DeviceTwinHistory{
+					Enable:          false,
+					MaxSamples:      constants.DefaultEdgeTwinHistoryMaxSamples,
+					ReportBatchSize: constants.DefaultEdgeTwinHistoryReportBatch,
+					FlushPeriod:     constants.DefaultEdgeTwinHistoryFlushPeriod,
+				},
 			},
 			DBTest: &DBTest{
 				Enable: false,
@@ -154,6 +174,30 @@ func NewDefaultEdgeCoreConfig() (config *EdgeCoreConfig) {
 				TunnelServer:            net.JoinHostPort("127.0.0.1", strconv.Itoa(constants.DefaultTunnelPort)),
 				WriteDeadline:           15,
 			},
+			ImageMirror: &ImageMirror{
+				Enable:          false,
+				Port:            constants.DefaultImageMirrorPort,
+				CacheDir:        constants.DefaultImageMirrorCacheDir,
+				RuntimeHostsDir: constants.DefaultContainerdHostsDir,
+			},
+			ExtensionModules: &ExtensionModules{
+				Enable:     false,
+				BufferSize: constants.DefaultExtensionModuleBufferSize,
+			},
+			Admin: &Admin{
+				Enable:     false,
+				UnixSocket: constants.DefaultAdminUnixSocket,
+				TLSCAFile:  constants.DefaultCAFile,
+			},
+		},
+		MessageQueues: &MessageQueues{
+			Default: MessageQueue{
+				Capacity:       constants.DefaultMessageQueueCapacity,
+				OverflowPolicy: OverflowPolicyBlock,
+				BlockTimeout:   constants.DefaultMessageQueueBlockTimeout,
+				MaxSpillSize:   constants.DefaultMessageQueueMaxSpillSize,
+			},
+			SpillDir: constants.DefaultMessageQueueSpillDir,
 		},
 	}
 	return
@@ -10,6 +10,8 @@ const (
 
 	// DataBaseDataSource is edge.db
 	DataBaseDataSource = "/var/lib/kubeedge/edgecore.db"
+	// DataBaseBoltDataSource is the bbolt database of the metadata
+	DataBaseBoltDataSource = "/var/lib/kubeedge/edgecore-meta.bolt"
 
 	DefaultCgroupDriver         = "cgroupfs"
 	DefaultCgroupsPerQOS        = true
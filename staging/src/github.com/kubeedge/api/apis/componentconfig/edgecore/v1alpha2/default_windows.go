@@ -8,6 +8,8 @@ const (
 
 	// DataBaseDataSource is edge.db
 	DataBaseDataSource = "C:\\var\\lib\\kubeedge\\edgecore.db"
+	// DataBaseBoltDataSource is the bbolt database of the metadata
+	DataBaseBoltDataSource = "C:\\var\\lib\\kubeedge\\edgecore-meta.bolt"
 
 	DefaultCgroupDriver         = ""
 	DefaultCgroupsPerQOS        = false
@@ -36,11 +36,29 @@ const (
 	DataBaseDriverName = "sqlite3"
 	// DataBaseAliasName is default
 	DataBaseAliasName = "default"
+	// MetaBackendSQLite stores the metadata in the sqlite database of DataSource
+	MetaBackendSQLite = "sqlite"
+	// MetaBackendBolt stores the metadata in the bbolt database of BoltDataSource
+	MetaBackendBolt = "bbolt"
 )
 
 type ProtocolName string
 type MqttMode int
 
+// MessageQueueOverflowPolicy indicates what the sender does when the message channel of a module is full
+type MessageQueueOverflowPolicy string
+
+const (
+	// OverflowPolicyBlock blocks the sender until there is room, or until BlockTimeout and then drops the message
+	OverflowPolicyBlock MessageQueueOverflowPolicy = "Block"
+	// OverflowPolicyDropOldest drops the oldest message in the channel
+	OverflowPolicyDropOldest MessageQueueOverflowPolicy = "DropOldest"
+	// OverflowPolicyDropNewest drops the new message
+	OverflowPolicyDropNewest MessageQueueOverflowPolicy = "DropNewest"
+	// OverflowPolicySpillToDisk spills the messages to a file under SpillDir until the module catches up
+	OverflowPolicySpillToDisk MessageQueueOverflowPolicy = "SpillToDisk"
+)
+
 // EdgeCoreConfig indicates the EdgeCore config which read from EdgeCore config file
 type EdgeCoreConfig struct {
 	metav1.TypeMeta
@@ -52,6 +70,8 @@ type EdgeCoreConfig struct {
 	Modules *Modules `json:"modules,omitempty"`
 	// FeatureGates is a map of feature names to bools that enable or disable alpha/experimental features.
 	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// MessageQueues indicates the config of the beehive message channels of the modules
+	MessageQueues *MessageQueues `json:"messageQueues,omitempty"`
 }
 
 // DataBase indicates the database info
@@ -65,6 +85,14 @@ type DataBase struct {
 	// DataSource indicates the data source path
 	// default "/var/lib/kubeedge/edgecore.db"
 	DataSource string `json:"dataSource,omitempty"`
+	// MetaBackend indicates the storage backend of the metadata of metamanager: sqlite or bbolt,
+	// the data of the other modules are always stored in the sqlite database.
+	// Run "edgecore --migrate-meta-to-bolt" once to migrate the metadata before switching to bbolt
+	// default "sqlite"
+	MetaBackend string `json:"metaBackend,omitempty"`
+	// BoltDataSource indicates the bbolt database file path when MetaBackend is bbolt
+	// default "/var/lib/kubeedge/edgecore-meta.bolt"
+	BoltDataSource string `json:"boltDataSource,omitempty"`
 }
 
 // Modules indicates the modules which edgeCore will be used
@@ -90,6 +118,12 @@ type Modules struct {
 	// EdgeStream indicates edgestream module config
 	// +Required
 	EdgeStream *EdgeStream `json:"edgeStream,omitempty"`
+	// ImageMirror indicates imagemirror module config
+	ImageMirror *ImageMirror `json:"imageMirror,omitempty"`
+	// ExtensionModules indicates the config of the modules running out of edgecore process
+	ExtensionModules *ExtensionModules `json:"extensionModules,omitempty"`
+	// Admin indicates the local admin API config
+	Admin *Admin `json:"admin,omitempty"`
 }
 
 // Edged indicates the config fo edged module
@@ -111,6 +145,24 @@ type Edged struct {
 	// RegisterNodeNamespace indicates register node namespace
 	// default "default"
 	RegisterNodeNamespace string `json:"registerNodeNamespace,omitempty"`
+	// ImageVerification indicates the verification policy of the images of pods,
+	// pods using images failing the verification are rejected before they are created
+	// default nil, images are not verified
+	ImageVerification *ImageVerification `json:"imageVerification,omitempty"`
+}
+
+// ImageVerification indicates how the images of pods are verified
+type ImageVerification struct {
+	// RequireDigest indicates whether images must be referenced by digest
+	// default false
+	RequireDigest bool `json:"requireDigest,omitempty"`
+	// AllowedRegistries indicates the registry hosts, or registry hosts with repository prefixes,
+	// images are allowed to come from, images from any registry are allowed if it is empty
+	AllowedRegistries []string `json:"allowedRegistries,omitempty"`
+	// CosignPublicKeyFiles indicates the files of the PEM encoded cosign public keys, images must
+	// have a cosign signature verified by one of the keys if it is not empty. The images of pods are
+	// pinned to the verified digests, and the signatures are fetched with the image pull secrets of pods.
+	CosignPublicKeyFiles []string `json:"cosignPublicKeyFiles,omitempty"`
 }
 
 // TailoredKubeletConfiguration indicates the tailored kubelet configuration.
@@ -973,6 +1025,62 @@ type MetaManager struct {
 	RemoteQueryTimeout int32 `json:"remoteQueryTimeout,omitempty"`
 	// The config of MetaServer
 	MetaServer *MetaServer `json:"metaServer,omitempty"`
+	// Encryption indicates the config of encrypting the sensitive resources stored in the database
+	Encryption *MetaEncryption `json:"encryption,omitempty"`
+	// DriftDetection indicates the config of detecting the objects in the database drifted from cloud
+	DriftDetection *MetaDriftDetection `json:"driftDetection,omitempty"`
+}
+
+// MetaDriftDetection indicates the config of the drift detection between the database and cloud.
+// The digests of the cached objects are sent to cloud periodically, cloud compares them with
+// the cluster and pushes the missing and stale objects and deletes the extra ones.
+type MetaDriftDetection struct {
+	// Enable indicates whether the digests are sent to cloud
+	// default false
+	Enable bool `json:"enable"`
+	// Period indicates the interval of sending the digests (second)
+	// default 600
+	Period int32 `json:"period,omitempty"`
+	// Resources indicates the resource types digested, only configmap and secret are supported
+	// default ["configmap", "secret"]
+	Resources []string `json:"resources,omitempty"`
+}
+
+const (
+	// MetaEncryptionProviderLocal uses the key in the key file to encrypt the data keys
+	MetaEncryptionProviderLocal = "local"
+	// MetaEncryptionProviderSealed uses the key in the key file sealed to the hardware of the node
+	MetaEncryptionProviderSealed = "sealed"
+	// MetaEncryptionProviderKMS uses the KMS v2 plugin to encrypt the data keys
+	MetaEncryptionProviderKMS = "kms"
+)
+
+// MetaEncryption indicates the config of the envelope encryption of the sensitive resources,
+// each value is encrypted by a data key which is encrypted by the key of the key provider
+type MetaEncryption struct {
+	// Enable indicates whether the sensitive resources are encrypted,
+	// the encrypted resources are decrypted by the migration when it is disabled later
+	// default false
+	Enable bool `json:"enable"`
+	// Resources are the resource types encrypted
+	// default [secret, serviceaccounttoken, serviceaccountaccess]
+	Resources []string `json:"resources,omitempty"`
+	// KeyProvider indicates the provider of the key encrypting the data keys: local, sealed or kms
+	// default local
+	KeyProvider string `json:"keyProvider,omitempty"`
+	// KeyFile is the key file of the local and sealed key provider, the key is generated if the file not exists
+	// default "/etc/kubeedge/encryption/key"
+	KeyFile string `json:"keyFile,omitempty"`
+	// AllowMachineIDOnly allows the sealed key provider to seal the key with /etc/machine-id only on the nodes
+	// without the product uuid of the hardware. The machine id is readable by all users and cloned with the
+	// disk images, so the key sealed with it only is weaker.
+	// default false
+	AllowMachineIDOnly bool `json:"allowMachineIDOnly,omitempty"`
+	// KMSEndpoint is the unix socket of the KMS v2 plugin of the kms key provider, e.g. unix:///var/run/kmsplugin/socket.sock
+	KMSEndpoint string `json:"kmsEndpoint,omitempty"`
+	// KMSTimeout indicates the timeout of the requests to the KMS plugin (second)
+	// default 3
+	KMSTimeout int32 `json:"kmsTimeout,omitempty"`
 }
 
 type MetaServer struct {
@@ -1012,6 +1120,26 @@ type DeviceTwin struct {
 	// DMISockPath sets the path to dmi.sock
 	// default "/etc/kubeedge/dmi.sock"
 	DMISockPath string `json:"dmiSockPath,omitempty"`
+	// TwinHistory indicates the config of the local history of reported twin values
+	TwinHistory *DeviceTwinHistory `json:"twinHistory,omitempty"`
+}
+
+// DeviceTwinHistory indicates the config of the local device twin history
+type DeviceTwinHistory struct {
+	// Enable indicates whether reported twin values are kept in the local database,
+	// values reported while disconnected from cloud are buffered and reported after reconnecting.
+	// default false
+	Enable bool `json:"enable"`
+	// MaxSamples indicates the max number of samples kept per twin property, older samples are dropped
+	// default 100
+	MaxSamples int32 `json:"maxSamples,omitempty"`
+	// ReportBatchSize indicates the max number of buffered samples reported to cloud in one batch
+	// default 100
+	ReportBatchSize int32 `json:"reportBatchSize,omitempty"`
+	// FlushPeriod indicates the period in seconds reported values are buffered in memory before they
+	// are written to the local database in one transaction
+	// default 10
+	FlushPeriod int32 `json:"flushPeriod,omitempty"`
 }
 
 // DBTest indicates the DBTest module config
@@ -1052,3 +1180,112 @@ type EdgeStream struct {
 	// default 15
 	WriteDeadline int32 `json:"writeDeadline,omitempty"`
 }
+
+// ImageMirror indicates the image mirror config, the image mirror of the seed node serves
+// the images it pulled to the other edge nodes of the node group over the local network
+type ImageMirror struct {
+	// Enable indicates whether imagemirror is enabled, if set to false, skip checking other configs.
+	// default false
+	Enable bool `json:"enable"`
+	// Address indicates the IP address the image mirror listens on, it must be reachable by the other
+	// edge nodes of the node group. The image mirror serves HTTPS with a certificate signed by cloudcore
+	// and only serves the edge nodes presenting their edge certificates.
+	// default the node IP of edged
+	Address string `json:"address,omitempty"`
+	// Port indicates the port the image mirror listens on
+	// default 10553
+	Port int32 `json:"port,omitempty"`
+	// CacheDir indicates the directory the image mirror caches the image contents in
+	// default /var/lib/kubeedge/imagemirror
+	CacheDir string `json:"cacheDir,omitempty"`
+	// RuntimeHostsDir indicates the directory of the registry host configs of containerd,
+	// the edge node configures containerd to pull images from the seed node with it
+	// default /etc/containerd/certs.d
+	RuntimeHostsDir string `json:"runtimeHostsDir,omitempty"`
+}
+
+// ExtensionModules indicates the config of the extension modules, an extension module runs
+// in a separate process and exchanges beehive messages with edgecore over a unix socket
+type ExtensionModules struct {
+	// Enable indicates whether extension modules are enabled, if set to false, skip checking other configs.
+	// default false
+	Enable bool `json:"enable"`
+	// BufferSize indicates the max size in bytes of one message on the socket
+	// default 2097152
+	BufferSize int32 `json:"bufferSize,omitempty"`
+	// Modules indicates the extension modules edgecore registers
+	Modules []ExtensionModule `json:"modules,omitempty"`
+}
+
+// ExtensionModule indicates the config of one extension module
+type ExtensionModule struct {
+	// Name indicates the module name, the extension process must register with the same name
+	// +Required
+	Name string `json:"name"`
+	// Group indicates the module group, join group "meta" to receive the resources sent from cloud
+	// default the module name
+	Group string `json:"group,omitempty"`
+	// Address indicates the absolute path of the unix socket edgecore listens on for the module
+	// +Required
+	Address string `json:"address"`
+}
+
+// MessageQueues indicates the config of the beehive message channels of the modules
+type MessageQueues struct {
+	// Default indicates the channel config of the modules not listed in Modules
+	Default MessageQueue `json:"default,omitempty"`
+	// Modules indicates the channel config by module name, e.g. "twin", "metaManager"
+	Modules map[string]MessageQueue `json:"modules,omitempty"`
+	// SpillDir indicates the directory of the spill files of SpillToDisk policy
+	// default /var/lib/kubeedge/beehive
+	SpillDir string `json:"spillDir,omitempty"`
+	// DebugAddress indicates the local address of the debug endpoint,
+	// GET /debug/beehive/queues on it dumps the states of the channels.
+	// empty means the endpoint is disabled
+	// default ""
+	DebugAddress string `json:"debugAddress,omitempty"`
+}
+
+// MessageQueue indicates the config of the message channel of a module
+type MessageQueue struct {
+	// Capacity indicates the number of messages the channel holds
+	// default 1024
+	Capacity int32 `json:"capacity,omitempty"`
+	// OverflowPolicy indicates what the sender does when the channel is full:
+	// Block, DropOldest, DropNewest or SpillToDisk
+	// default Block
+	OverflowPolicy MessageQueueOverflowPolicy `json:"overflowPolicy,omitempty"`
+	// BlockTimeout indicates how long the sender waits with Block policy (second), 0 means waiting forever
+	// default 30
+	BlockTimeout int32 `json:"blockTimeout,omitempty"`
+	// MaxSpillSize indicates the max size of the spill file with SpillToDisk policy (MB),
+	// the messages are dropped once it is reached. 0 means unlimited
+	// default 100
+	MaxSpillSize int32 `json:"maxSpillSize,omitempty"`
+}
+
+// Admin indicates the config of the local admin API of the edge node, it serves the status of
+// the modules, the cloud connection, the message queues and the devices, and the actions like
+// reconnecting to cloud for the operators on site. keadm ctl is built on it.
+type Admin struct {
+	// Enable indicates whether the admin API is enabled, if set to false, skip checking other configs.
+	// default false
+	Enable bool `json:"enable"`
+	// UnixSocket indicates the unix socket the admin API listens on, it is accessible only by root
+	// default /var/lib/kubeedge/admin.sock
+	UnixSocket string `json:"unixSocket,omitempty"`
+	// Address indicates the loopback address the admin API listens on over HTTPS,
+	// the clients must present a certificate signed by TLSCAFile. empty means disabled
+	// default ""
+	Address string `json:"address,omitempty"`
+	// TLSCAFile indicates the CA file verifying the client certificates
+	// default "/etc/kubeedge/ca/rootCA.crt"
+	TLSCAFile string `json:"tlsCaFile,omitempty"`
+	// TLSCertFile indicates the serving certificate file of the HTTPS server, it is required when
+	// Address is set. The client certificate of edgehub is not a serving certificate, do not use it
+	// default ""
+	TLSCertFile string `json:"tlsCertFile,omitempty"`
+	// TLSPrivateKeyFile indicates the private key file of the HTTPS server, it is required when Address is set
+	// default ""
+	TLSPrivateKeyFile string `json:"tlsPrivateKeyFile,omitempty"`
+}
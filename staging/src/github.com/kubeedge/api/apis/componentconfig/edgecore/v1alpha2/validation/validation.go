@@ -18,8 +18,10 @@ package validation
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"path"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
@@ -41,6 +43,18 @@ func ValidateEdgeCoreConfiguration(c *v1alpha2.EdgeCoreConfig) field.ErrorList {
 	allErrs = append(allErrs, ValidateModuleDeviceTwin(*c.Modules.DeviceTwin)...)
 	allErrs = append(allErrs, ValidateModuleDBTest(*c.Modules.DBTest)...)
 	allErrs = append(allErrs, ValidateModuleEdgeStream(*c.Modules.EdgeStream)...)
+	if c.Modules.ImageMirror != nil {
+		allErrs = append(allErrs, ValidateModuleImageMirror(*c.Modules.ImageMirror)...)
+	}
+	if c.Modules.ExtensionModules != nil {
+		allErrs = append(allErrs, ValidateModuleExtensionModules(*c.Modules.ExtensionModules)...)
+	}
+	if c.Modules.Admin != nil {
+		allErrs = append(allErrs, ValidateModuleAdmin(*c.Modules.Admin)...)
+	}
+	if c.MessageQueues != nil {
+		allErrs = append(allErrs, ValidateMessageQueues(*c.MessageQueues)...)
+	}
 	return allErrs
 }
 
@@ -54,6 +68,21 @@ func ValidateDataBase(db v1alpha2.DataBase) field.ErrorList {
 				fmt.Sprintf("create DataSoure dir %v error ", sourceDir)))
 		}
 	}
+	switch db.MetaBackend {
+	case "", v1alpha2.MetaBackendSQLite:
+	case v1alpha2.MetaBackendBolt:
+		if db.BoltDataSource == "" {
+			allErrs = append(allErrs, field.Required(field.NewPath("BoltDataSource"), "required by bbolt backend"))
+		} else if boltDir := path.Dir(db.BoltDataSource); !utilvalidation.FileIsExist(boltDir) {
+			if err := os.MkdirAll(boltDir, os.ModePerm); err != nil {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("BoltDataSource"), db.BoltDataSource,
+					fmt.Sprintf("create BoltDataSource dir %v error ", boltDir)))
+			}
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("MetaBackend"), db.MetaBackend,
+			[]string{v1alpha2.MetaBackendSQLite, v1alpha2.MetaBackendBolt}))
+	}
 	return allErrs
 }
 
@@ -73,6 +102,14 @@ func ValidateModuleEdged(e v1alpha2.Edged) field.ErrorList {
 	if err := ValidateCgroupDriver(e.TailoredKubeletConfig.CgroupDriver); err != nil {
 		allErrs = append(allErrs, err)
 	}
+	if e.ImageVerification != nil {
+		for i, file := range e.ImageVerification.CosignPublicKeyFiles {
+			if !utilvalidation.FileIsExist(file) {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("ImageVerification").Child("CosignPublicKeyFiles").Index(i),
+					file, "cosign public key file not exist"))
+			}
+		}
+	}
 	return allErrs
 }
 
@@ -121,6 +158,54 @@ func ValidateModuleMetaManager(m v1alpha2.MetaManager) field.ErrorList {
 		return field.ErrorList{}
 	}
 	allErrs := field.ErrorList{}
+	if m.Encryption != nil {
+		allErrs = append(allErrs, ValidateMetaEncryption(*m.Encryption)...)
+	}
+	if m.DriftDetection != nil {
+		allErrs = append(allErrs, ValidateMetaDriftDetection(*m.DriftDetection)...)
+	}
+	return allErrs
+}
+
+// ValidateMetaDriftDetection validates `d` and returns an errorList if it is invalid
+func ValidateMetaDriftDetection(d v1alpha2.MetaDriftDetection) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !d.Enable {
+		return allErrs
+	}
+	if d.Period <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("Period"), d.Period, "must be greater than 0"))
+	}
+	for _, resource := range d.Resources {
+		if resource != "configmap" && resource != "secret" {
+			allErrs = append(allErrs, field.NotSupported(field.NewPath("Resources"), resource, []string{"configmap", "secret"}))
+		}
+	}
+	return allErrs
+}
+
+// ValidateMetaEncryption validates `e` and returns an errorList if it is invalid
+func ValidateMetaEncryption(e v1alpha2.MetaEncryption) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !e.Enable {
+		return allErrs
+	}
+	switch e.KeyProvider {
+	case v1alpha2.MetaEncryptionProviderLocal, v1alpha2.MetaEncryptionProviderSealed:
+		if !path.IsAbs(e.KeyFile) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("KeyFile"), e.KeyFile, "must be an absolute path"))
+		}
+	case v1alpha2.MetaEncryptionProviderKMS:
+		if !strings.HasPrefix(e.KMSEndpoint, "unix://") {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("KMSEndpoint"), e.KMSEndpoint, "must be a unix socket"))
+		}
+		if e.KMSTimeout <= 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("KMSTimeout"), e.KMSTimeout, "must be greater than 0"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("KeyProvider"), e.KeyProvider,
+			[]string{v1alpha2.MetaEncryptionProviderLocal, v1alpha2.MetaEncryptionProviderSealed, v1alpha2.MetaEncryptionProviderKMS}))
+	}
 	return allErrs
 }
 
@@ -139,6 +224,20 @@ func ValidateModuleDeviceTwin(d v1alpha2.DeviceTwin) field.ErrorList {
 		return field.ErrorList{}
 	}
 	allErrs := field.ErrorList{}
+	if d.TwinHistory != nil && d.TwinHistory.Enable {
+		if d.TwinHistory.MaxSamples <= 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("twinHistory", "maxSamples"),
+				d.TwinHistory.MaxSamples, "maxSamples must be greater than 0"))
+		}
+		if d.TwinHistory.ReportBatchSize <= 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("twinHistory", "reportBatchSize"),
+				d.TwinHistory.ReportBatchSize, "reportBatchSize must be greater than 0"))
+		}
+		if d.TwinHistory.FlushPeriod <= 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("twinHistory", "flushPeriod"),
+				d.TwinHistory.FlushPeriod, "flushPeriod must be greater than 0"))
+		}
+	}
 	return allErrs
 }
 
@@ -159,3 +258,117 @@ func ValidateModuleEdgeStream(m v1alpha2.EdgeStream) field.ErrorList {
 	}
 	return allErrs
 }
+
+// ValidateModuleImageMirror validates `m` and returns an errorList if it is invalid
+func ValidateModuleImageMirror(m v1alpha2.ImageMirror) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !m.Enable {
+		return allErrs
+	}
+	if m.Address != "" && net.ParseIP(m.Address) == nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("Address"), m.Address, "must be an IP address"))
+	}
+	if m.Port <= 0 || m.Port > 65535 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("Port"), m.Port, "must be between 1 and 65535"))
+	}
+	if !path.IsAbs(m.CacheDir) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("CacheDir"), m.CacheDir, "must be an absolute path"))
+	}
+	return allErrs
+}
+
+// ValidateModuleExtensionModules validates `m` and returns an errorList if it is invalid
+func ValidateModuleExtensionModules(m v1alpha2.ExtensionModules) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !m.Enable {
+		return allErrs
+	}
+	if m.BufferSize <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("BufferSize"), m.BufferSize, "must be greater than 0"))
+	}
+	names := make(map[string]struct{}, len(m.Modules))
+	for i, module := range m.Modules {
+		fldPath := field.NewPath("Modules").Index(i)
+		if module.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("Name"), "module name is required"))
+		} else if _, ok := names[module.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("Name"), module.Name))
+		}
+		names[module.Name] = struct{}{}
+		if !path.IsAbs(module.Address) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Address"), module.Address, "must be an absolute path"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateModuleAdmin validates `m` and returns an errorList if it is invalid
+func ValidateModuleAdmin(m v1alpha2.Admin) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !m.Enable {
+		return allErrs
+	}
+	if m.UnixSocket == "" && m.Address == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("UnixSocket"), "either UnixSocket or Address is required"))
+	}
+	if m.UnixSocket != "" && !path.IsAbs(m.UnixSocket) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("UnixSocket"), m.UnixSocket, "must be an absolute path"))
+	}
+	if m.Address != "" {
+		host, _, err := net.SplitHostPort(m.Address)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Address"), m.Address, err.Error()))
+		} else if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Address"), m.Address, "must be a loopback address"))
+		}
+		if m.TLSCertFile == "" {
+			allErrs = append(allErrs, field.Required(field.NewPath("TLSCertFile"), "the serving certificate is required when Address is set"))
+		}
+		if m.TLSPrivateKeyFile == "" {
+			allErrs = append(allErrs, field.Required(field.NewPath("TLSPrivateKeyFile"), "the private key is required when Address is set"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateMessageQueues validates `m` and returns an errorList if it is invalid
+func ValidateMessageQueues(m v1alpha2.MessageQueues) field.ErrorList {
+	allErrs := field.ErrorList{}
+	spill := m.Default.OverflowPolicy == v1alpha2.OverflowPolicySpillToDisk
+	allErrs = append(allErrs, validateMessageQueue(m.Default, field.NewPath("Default"))...)
+	for name, queue := range m.Modules {
+		spill = spill || queue.OverflowPolicy == v1alpha2.OverflowPolicySpillToDisk
+		allErrs = append(allErrs, validateMessageQueue(queue, field.NewPath("Modules").Key(name))...)
+	}
+	if spill && !path.IsAbs(m.SpillDir) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("SpillDir"), m.SpillDir, "must be an absolute path"))
+	}
+	if m.DebugAddress != "" {
+		if _, _, err := net.SplitHostPort(m.DebugAddress); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("DebugAddress"), m.DebugAddress, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateMessageQueue(q v1alpha2.MessageQueue, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if q.Capacity < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("Capacity"), q.Capacity, "must be greater than or equal to 0"))
+	}
+	switch q.OverflowPolicy {
+	case "", v1alpha2.OverflowPolicyBlock, v1alpha2.OverflowPolicyDropOldest,
+		v1alpha2.OverflowPolicyDropNewest, v1alpha2.OverflowPolicySpillToDisk:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("OverflowPolicy"), q.OverflowPolicy,
+			[]string{string(v1alpha2.OverflowPolicyBlock), string(v1alpha2.OverflowPolicyDropOldest),
+				string(v1alpha2.OverflowPolicyDropNewest), string(v1alpha2.OverflowPolicySpillToDisk)}))
+	}
+	if q.BlockTimeout < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("BlockTimeout"), q.BlockTimeout, "must be greater than or equal to 0"))
+	}
+	if q.MaxSpillSize < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("MaxSpillSize"), q.MaxSpillSize, "must be greater than or equal to 0"))
+	}
+	return allErrs
+}
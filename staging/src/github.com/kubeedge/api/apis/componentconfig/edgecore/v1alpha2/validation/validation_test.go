@@ -330,6 +330,33 @@ func TestValidateModuleDeviceTwin(t *testing.T) {
 			},
 			expected: field.ErrorList{},
 		},
+		{
+			name: "case3 twin history enabled",
+			input: v1alpha2.DeviceTwin{
+				Enable: true,
+				TwinHistory: &v1alpha2.DeviceTwinHistory{
+					Enable:          true,
+					MaxSamples:      100,
+					ReportBatchSize: 100,
+					FlushPeriod:     10,
+				},
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case4 twin history with invalid sizes",
+			input: v1alpha2.DeviceTwin{
+				Enable: true,
+				TwinHistory: &v1alpha2.DeviceTwinHistory{
+					Enable: true,
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("twinHistory", "maxSamples"), int32(0), "maxSamples must be greater than 0"),
+				field.Invalid(field.NewPath("twinHistory", "reportBatchSize"), int32(0), "reportBatchSize must be greater than 0"),
+				field.Invalid(field.NewPath("twinHistory", "flushPeriod"), int32(0), "flushPeriod must be greater than 0"),
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -396,3 +423,201 @@ func TestValidateModuleEdgeStream(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateModuleExtensionModules(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    v1alpha2.ExtensionModules
+		expected field.ErrorList
+	}{
+		{
+			name: "case1 not enabled",
+			input: v1alpha2.ExtensionModules{
+				Enable: false,
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case2 all ok",
+			input: v1alpha2.ExtensionModules{
+				Enable:     true,
+				BufferSize: 1024,
+				Modules: []v1alpha2.ExtensionModule{
+					{Name: "ext1", Address: "/var/lib/kubeedge/ext1.sock"},
+					{Name: "ext2", Group: "meta", Address: "/var/lib/kubeedge/ext2.sock"},
+				},
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case3 invalid buffer size",
+			input: v1alpha2.ExtensionModules{
+				Enable:     true,
+				BufferSize: 0,
+			},
+			expected: field.ErrorList{field.Invalid(field.NewPath("BufferSize"), int32(0), "must be greater than 0")},
+		},
+		{
+			name: "case4 invalid modules",
+			input: v1alpha2.ExtensionModules{
+				Enable:     true,
+				BufferSize: 1024,
+				Modules: []v1alpha2.ExtensionModule{
+					{Name: "", Address: "/var/lib/kubeedge/ext.sock"},
+					{Name: "ext", Address: "ext.sock"},
+					{Name: "ext", Address: "/var/lib/kubeedge/ext.sock"},
+				},
+			},
+			expected: field.ErrorList{
+				field.Required(field.NewPath("Modules").Index(0).Child("Name"), "module name is required"),
+				field.Invalid(field.NewPath("Modules").Index(1).Child("Address"), "ext.sock", "must be an absolute path"),
+				field.Duplicate(field.NewPath("Modules").Index(2).Child("Name"), "ext"),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		if result := ValidateModuleExtensionModules(c.input); !reflect.DeepEqual(result, c.expected) {
+			t.Errorf("%v: expected %v, but got %v", c.name, c.expected, result)
+		}
+	}
+}
+
+func TestValidateMessageQueues(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    v1alpha2.MessageQueues
+		expected field.ErrorList
+	}{
+		{
+			name: "case1 all ok",
+			input: v1alpha2.MessageQueues{
+				Default: v1alpha2.MessageQueue{Capacity: 1024, OverflowPolicy: v1alpha2.OverflowPolicyBlock},
+				Modules: map[string]v1alpha2.MessageQueue{
+					"twin": {Capacity: 128, OverflowPolicy: v1alpha2.OverflowPolicySpillToDisk},
+				},
+				SpillDir:     "/var/lib/kubeedge/beehive",
+				DebugAddress: "127.0.0.1:10354",
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case2 invalid queue",
+			input: v1alpha2.MessageQueues{
+				Modules: map[string]v1alpha2.MessageQueue{
+					"twin": {Capacity: -1, OverflowPolicy: "Unknown", BlockTimeout: -1, MaxSpillSize: -1},
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("Modules").Key("twin").Child("Capacity"), int32(-1), "must be greater than or equal to 0"),
+				field.NotSupported(field.NewPath("Modules").Key("twin").Child("OverflowPolicy"), v1alpha2.MessageQueueOverflowPolicy("Unknown"),
+					[]string{"Block", "DropOldest", "DropNewest", "SpillToDisk"}),
+				field.Invalid(field.NewPath("Modules").Key("twin").Child("BlockTimeout"), int32(-1), "must be greater than or equal to 0"),
+				field.Invalid(field.NewPath("Modules").Key("twin").Child("MaxSpillSize"), int32(-1), "must be greater than or equal to 0"),
+			},
+		},
+		{
+			name: "case3 invalid spill dir and debug address",
+			input: v1alpha2.MessageQueues{
+				Default:      v1alpha2.MessageQueue{OverflowPolicy: v1alpha2.OverflowPolicySpillToDisk},
+				SpillDir:     "beehive",
+				DebugAddress: "10354",
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("SpillDir"), "beehive", "must be an absolute path"),
+				field.Invalid(field.NewPath("DebugAddress"), "10354", "address 10354: missing port in address"),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		if result := ValidateMessageQueues(c.input); !reflect.DeepEqual(result, c.expected) {
+			t.Errorf("%v: expected %v, but got %v", c.name, c.expected, result)
+		}
+	}
+}
+
+func TestValidateModuleAdmin(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    v1alpha2.Admin
+		expected field.ErrorList
+	}{
+		{
+			name:     "case1 not enabled",
+			input:    v1alpha2.Admin{Enable: false},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case2 all ok",
+			input: v1alpha2.Admin{
+				Enable:            true,
+				UnixSocket:        "/var/lib/kubeedge/admin.sock",
+				Address:           "127.0.0.1:10356",
+				TLSCertFile:       "/etc/kubeedge/admin/server.crt",
+				TLSPrivateKeyFile: "/etc/kubeedge/admin/server.key",
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name:  "case3 no listener",
+			input: v1alpha2.Admin{Enable: true},
+			expected: field.ErrorList{
+				field.Required(field.NewPath("UnixSocket"), "either UnixSocket or Address is required"),
+			},
+		},
+		{
+			name: "case4 invalid listener",
+			input: v1alpha2.Admin{
+				Enable:     true,
+				UnixSocket: "admin.sock",
+				Address:    "0.0.0.0:10356",
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("UnixSocket"), "admin.sock", "must be an absolute path"),
+				field.Invalid(field.NewPath("Address"), "0.0.0.0:10356", "must be a loopback address"),
+				field.Required(field.NewPath("TLSCertFile"), "the serving certificate is required when Address is set"),
+				field.Required(field.NewPath("TLSPrivateKeyFile"), "the private key is required when Address is set"),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		if result := ValidateModuleAdmin(c.input); !reflect.DeepEqual(result, c.expected) {
+			t.Errorf("%v: expected %v, but got %v", c.name, c.expected, result)
+		}
+	}
+}
+
+func TestValidateMetaDriftDetection(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    v1alpha2.MetaDriftDetection
+		expected field.ErrorList
+	}{
+		{
+			name:     "case1 not enabled",
+			input:    v1alpha2.MetaDriftDetection{Enable: false, Period: -1},
+			expected: field.ErrorList{},
+		},
+		{
+			name:     "case2 all ok",
+			input:    v1alpha2.MetaDriftDetection{Enable: true, Period: 600, Resources: []string{"configmap", "secret"}},
+			expected: field.ErrorList{},
+		},
+		{
+			name:  "case3 invalid period and resource",
+			input: v1alpha2.MetaDriftDetection{Enable: true, Period: 0, Resources: []string{"pod"}},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("Period"), int32(0), "must be greater than 0"),
+				field.NotSupported(field.NewPath("Resources"), "pod", []string{"configmap", "secret"}),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		if result := ValidateMetaDriftDetection(c.input); !reflect.DeepEqual(result, c.expected) {
+			t.Errorf("%v: expected %v, but got %v", c.name, c.expected, result)
+		}
+	}
+}
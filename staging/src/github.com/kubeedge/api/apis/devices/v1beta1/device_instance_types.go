@@ -53,6 +53,9 @@ type DeviceStatus struct {
 	// Optional: The last time the device was online.
 	// +optional
 	LastOnlineTime string `json:"lastOnlineTime,omitempty"`
+	// Optional: The reason of the state, e.g. why the device is offline.
+	// +optional
+	Reason string `json:"reason,omitempty"`
 }
 
 // Twin provides a logical representation of control properties (writable properties in the
@@ -48,8 +48,18 @@ type DeviceManagerServiceClient interface {
 	// When the mapper collects some properties of a device, it can make them a map of device twins
 	// and report it to the device manager through the interface of ReportDeviceStatus.
 	ReportDeviceStatus(ctx context.Context, in *ReportDeviceStatusRequest, opts ...grpc.CallOption) (*ReportDeviceStatusResponse, error)
+	// TODO Rename ReportDeviceStatus to ReportDeviceTwins
 	// ReportDeviceStates reports the state of devices to device manager.
 	ReportDeviceStates(ctx context.Context, in *ReportDeviceStatesRequest, opts ...grpc.CallOption) (*ReportDeviceStatesResponse, error)
+	// ReportDeviceStatusStream reports the status of devices to device manager through a stream.
+	// It is intended for devices sampling at high frequency. The mapper sends batches of device status
+	// with increasing sequence numbers and the device manager acknowledges every batch with its sequence number
+	// after handling it. The mapper should limit the number of unacknowledged batches to apply backpressure.
+	ReportDeviceStatusStream(ctx context.Context, opts ...grpc.CallOption) (DeviceManagerService_ReportDeviceStatusStreamClient, error)
+	// MapperHeartbeat reports the liveness and health of the mapper to device manager periodically.
+	// The device manager marks the mapper offline when it misses several heartbeats and
+	// marks the devices served by the mapper offline as well.
+	MapperHeartbeat(ctx context.Context, in *MapperHeartbeatRequest, opts ...grpc.CallOption) (*MapperHeartbeatResponse, error)
 }
 
 type deviceManagerServiceClient struct {
@@ -87,6 +97,46 @@ func (c *deviceManagerServiceClient) ReportDeviceStates(ctx context.Context, in
 	return out, nil
 }
 
+func (c *deviceManagerServiceClient) ReportDeviceStatusStream(ctx context.Context, opts ...grpc.CallOption) (DeviceManagerService_ReportDeviceStatusStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DeviceManagerService_ServiceDesc.Streams[0], "/v1beta1.DeviceManagerService/ReportDeviceStatusStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &deviceManagerServiceReportDeviceStatusStreamClient{stream}
+	return x, nil
+}
+
+type DeviceManagerService_ReportDeviceStatusStreamClient interface {
+	Send(*ReportDeviceStatusBatch) error
+	Recv() (*ReportDeviceStatusAck, error)
+	grpc.ClientStream
+}
+
+type deviceManagerServiceReportDeviceStatusStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *deviceManagerServiceReportDeviceStatusStreamClient) Send(m *ReportDeviceStatusBatch) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *deviceManagerServiceReportDeviceStatusStreamClient) Recv() (*ReportDeviceStatusAck, error) {
+	m := new(ReportDeviceStatusAck)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *deviceManagerServiceClient) MapperHeartbeat(ctx context.Context, in *MapperHeartbeatRequest, opts ...grpc.CallOption) (*MapperHeartbeatResponse, error) {
+	out := new(MapperHeartbeatResponse)
+	err := c.cc.Invoke(ctx, "/v1beta1.DeviceManagerService/MapperHeartbeat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DeviceManagerServiceServer is the server API for DeviceManagerService service.
 // All implementations must embed UnimplementedDeviceManagerServiceServer
 // for forward compatibility
@@ -99,8 +149,18 @@ type DeviceManagerServiceServer interface {
 	// When the mapper collects some properties of a device, it can make them a map of device twins
 	// and report it to the device manager through the interface of ReportDeviceStatus.
 	ReportDeviceStatus(context.Context, *ReportDeviceStatusRequest) (*ReportDeviceStatusResponse, error)
+	// TODO Rename ReportDeviceStatus to ReportDeviceTwins
 	// ReportDeviceStates reports the state of devices to device manager.
 	ReportDeviceStates(context.Context, *ReportDeviceStatesRequest) (*ReportDeviceStatesResponse, error)
+	// ReportDeviceStatusStream reports the status of devices to device manager through a stream.
+	// It is intended for devices sampling at high frequency. The mapper sends batches of device status
+	// with increasing sequence numbers and the device manager acknowledges every batch with its sequence number
+	// after handling it. The mapper should limit the number of unacknowledged batches to apply backpressure.
+	ReportDeviceStatusStream(DeviceManagerService_ReportDeviceStatusStreamServer) error
+	// MapperHeartbeat reports the liveness and health of the mapper to device manager periodically.
+	// The device manager marks the mapper offline when it misses several heartbeats and
+	// marks the devices served by the mapper offline as well.
+	MapperHeartbeat(context.Context, *MapperHeartbeatRequest) (*MapperHeartbeatResponse, error)
 	mustEmbedUnimplementedDeviceManagerServiceServer()
 }
 
@@ -117,6 +177,12 @@ func (UnimplementedDeviceManagerServiceServer) ReportDeviceStatus(context.Contex
 func (UnimplementedDeviceManagerServiceServer) ReportDeviceStates(context.Context, *ReportDeviceStatesRequest) (*ReportDeviceStatesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReportDeviceStates not implemented")
 }
+func (UnimplementedDeviceManagerServiceServer) ReportDeviceStatusStream(DeviceManagerService_ReportDeviceStatusStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReportDeviceStatusStream not implemented")
+}
+func (UnimplementedDeviceManagerServiceServer) MapperHeartbeat(context.Context, *MapperHeartbeatRequest) (*MapperHeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MapperHeartbeat not implemented")
+}
 func (UnimplementedDeviceManagerServiceServer) mustEmbedUnimplementedDeviceManagerServiceServer() {}
 
 // UnsafeDeviceManagerServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -184,6 +250,50 @@ func _DeviceManagerService_ReportDeviceStates_Handler(srv interface{}, ctx conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DeviceManagerService_ReportDeviceStatusStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DeviceManagerServiceServer).ReportDeviceStatusStream(&deviceManagerServiceReportDeviceStatusStreamServer{stream})
+}
+
+type DeviceManagerService_ReportDeviceStatusStreamServer interface {
+	Send(*ReportDeviceStatusAck) error
+	Recv() (*ReportDeviceStatusBatch, error)
+	grpc.ServerStream
+}
+
+type deviceManagerServiceReportDeviceStatusStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *deviceManagerServiceReportDeviceStatusStreamServer) Send(m *ReportDeviceStatusAck) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *deviceManagerServiceReportDeviceStatusStreamServer) Recv() (*ReportDeviceStatusBatch, error) {
+	m := new(ReportDeviceStatusBatch)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _DeviceManagerService_MapperHeartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MapperHeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeviceManagerServiceServer).MapperHeartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1beta1.DeviceManagerService/MapperHeartbeat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeviceManagerServiceServer).MapperHeartbeat(ctx, req.(*MapperHeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DeviceManagerService_ServiceDesc is the grpc.ServiceDesc for DeviceManagerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -203,8 +313,19 @@ var DeviceManagerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ReportDeviceStates",
 			Handler:    _DeviceManagerService_ReportDeviceStates_Handler,
 		},
+		{
+			MethodName: "MapperHeartbeat",
+			Handler:    _DeviceManagerService_MapperHeartbeat_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReportDeviceStatusStream",
+			Handler:       _DeviceManagerService_ReportDeviceStatusStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api.proto",
 }
 
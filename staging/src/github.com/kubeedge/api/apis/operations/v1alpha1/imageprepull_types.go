@@ -82,6 +82,8 @@ type ImagePrePullTemplate struct {
 	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
 
 	// CheckItems specifies the items need to be checked before the task is executed.
+	// The built-in check items are cpu, mem, disk, inode, runtime, registry, clock, cloud and pods,
+	// other items are user-defined checks run by the executable hooks of the same names on edge nodes.
 	// The default CheckItems value is disk.
 	// +optional
 	CheckItems []string `json:"checkItems,omitempty"`
@@ -111,8 +113,58 @@ type ImagePrePullTemplate struct {
 	// Default to 0
 	// +optional
 	RetryTimes int32 `json:"retryTimes,omitempty"`
+
+	// DistributionMode specifies how images are distributed to edge nodes.
+	// There are two possible values: Registry, P2P.
+	// In Registry mode, every edge node pulls images from the registry.
+	// In P2P mode, edge nodes of a node group pull images through the image mirror of the seed node of the
+	// node group, so images are pulled from the registry once for each node group. Edge nodes fall back to
+	// the registry if the seed node is unreachable, and edge nodes not in any node group pull from the registry.
+	// The default DistributionMode value is Registry.
+	// +optional
+	DistributionMode ImageDistributionMode `json:"distributionMode,omitempty"`
+
+	// SeedNodes specifies the seed node of node groups in P2P mode, the key is the name of the node group
+	// and the value is the name of the seed node. The image mirror of edgecore must be enabled on seed nodes.
+	// The seed node of a node group not specified is the first node of the node group by name.
+	// +optional
+	SeedNodes map[string]string `json:"seedNodes,omitempty"`
+
+	// VerificationPolicy specifies how images are verified on edge nodes before they are pulled.
+	// Images failing the verification are not pulled, and the reasons are reported in ImageStatus.
+	// +optional
+	VerificationPolicy *ImageVerificationPolicy `json:"verificationPolicy,omitempty"`
 }
 
+// ImageVerificationPolicy specifies how images are verified on edge nodes before they are used.
+type ImageVerificationPolicy struct {
+	// RequireDigest requires images to be referenced by digest, e.g. nginx@sha256:<digest>.
+	// +optional
+	RequireDigest bool `json:"requireDigest,omitempty"`
+
+	// AllowedRegistries is the list of registries images are allowed to be pulled from. Each item is a
+	// registry host such as docker.io, or a registry host with a repository prefix such as docker.io/library.
+	// Images from any registry are allowed if it is empty.
+	// +optional
+	AllowedRegistries []string `json:"allowedRegistries,omitempty"`
+
+	// CosignPublicKeys is the list of PEM encoded public keys. If it is not empty, images must have
+	// a cosign signature in the registry that is verified by one of the keys.
+	// +optional
+	CosignPublicKeys []string `json:"cosignPublicKeys,omitempty"`
+}
+
+// ImageDistributionMode is the mode images are distributed to edge nodes.
+// +kubebuilder:validation:Enum=Registry;P2P
+type ImageDistributionMode string
+
+const (
+	// ImageDistributionRegistry makes every edge node pull images from the registry.
+	ImageDistributionRegistry ImageDistributionMode = "Registry"
+	// ImageDistributionP2P makes edge nodes pull images through the seed node of their node group.
+	ImageDistributionP2P ImageDistributionMode = "P2P"
+)
+
 // ImagePrePullJobStatus stores the status of ImagePrePullJob.
 // contains images prepull status on multiple edge nodes.
 // +kubebuilder:validation:Type=object
@@ -161,4 +213,12 @@ type ImageStatus struct {
 	// Reason represents the fail reason if image pull failed
 	// +optional
 	Reason string `json:"reason,omitempty"`
+
+	// Source represents where the image is pulled from, it is Registry
+	// or the name of the seed node the image is pulled through.
+	// +optional
+	Source string `json:"source,omitempty"`
 }
+
+// ImageSourceRegistry is the source of images pulled from the registry directly.
+const ImageSourceRegistry = "Registry"
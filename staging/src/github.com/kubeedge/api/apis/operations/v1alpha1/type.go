@@ -91,6 +91,8 @@ type NodeUpgradeJobSpec struct {
 	Concurrency int32 `json:"concurrency,omitempty"`
 
 	// CheckItems specifies the items need to be checked before the task is executed.
+	// The built-in check items are cpu, mem, disk, inode, runtime, registry, clock, cloud and pods,
+	// other items are user-defined checks run by the executable hooks of the same names on edge nodes.
 	// The default CheckItems value is nil.
 	// +optional
 	CheckItems []string `json:"checkItems,omitempty"`
@@ -145,4 +147,26 @@ type TaskStatus struct {
 	Reason string `json:"reason,omitempty"`
 	// Time represents for the running time of the ImagePrePullJob.
 	Time string `json:"time,omitempty"`
+	// CheckResults represents for the result of each check item on the edge node.
+	// +optional
+	CheckResults []CheckResult `json:"checkResults,omitempty"`
+}
+
+const (
+	CheckResultPassed      = "Passed"
+	CheckResultFailed      = "Failed"
+	CheckResultUnsupported = "Unsupported"
+)
+
+// CheckResult stores the result of a check item on an edge node.
+// +kubebuilder:validation:Type=object
+type CheckResult struct {
+	// Name is the name of the check item.
+	Name string `json:"name,omitempty"`
+	// Result represents for the result of the check item.
+	// There are three possible result values: Passed, Failed, Unsupported.
+	Result string `json:"result,omitempty"`
+	// Message represents for the details of the check item, such as the reason of failure.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
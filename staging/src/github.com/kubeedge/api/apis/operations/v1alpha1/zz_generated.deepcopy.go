@@ -26,6 +26,22 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheckResult) DeepCopyInto(out *CheckResult) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckResult.
+func (in *CheckResult) DeepCopy() *CheckResult {
+	if in == nil {
+		return nil
+	}
+	out := new(CheckResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImagePrePullJob) DeepCopyInto(out *ImagePrePullJob) {
 	*out = *in
@@ -133,7 +149,7 @@ func (in *ImagePrePullStatus) DeepCopyInto(out *ImagePrePullStatus) {
 	if in.TaskStatus != nil {
 		in, out := &in.TaskStatus, &out.TaskStatus
 		*out = new(TaskStatus)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.ImageStatus != nil {
 		in, out := &in.ImageStatus, &out.ImageStatus
@@ -181,6 +197,18 @@ func (in *ImagePrePullTemplate) DeepCopyInto(out *ImagePrePullTemplate) {
 		*out = new(uint32)
 		**out = **in
 	}
+	if in.SeedNodes != nil {
+		in, out := &in.SeedNodes, &out.SeedNodes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.VerificationPolicy != nil {
+		in, out := &in.VerificationPolicy, &out.VerificationPolicy
+		*out = new(ImageVerificationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -210,6 +238,32 @@ func (in *ImageStatus) DeepCopy() *ImageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageVerificationPolicy) DeepCopyInto(out *ImageVerificationPolicy) {
+	*out = *in
+	if in.AllowedRegistries != nil {
+		in, out := &in.AllowedRegistries, &out.AllowedRegistries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CosignPublicKeys != nil {
+		in, out := &in.CosignPublicKeys, &out.CosignPublicKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVerificationPolicy.
+func (in *ImageVerificationPolicy) DeepCopy() *ImageVerificationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageVerificationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeUpgradeJob) DeepCopyInto(out *NodeUpgradeJob) {
 	*out = *in
@@ -313,7 +367,9 @@ func (in *NodeUpgradeJobStatus) DeepCopyInto(out *NodeUpgradeJobStatus) {
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
 		*out = make([]TaskStatus, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
@@ -331,6 +387,11 @@ func (in *NodeUpgradeJobStatus) DeepCopy() *NodeUpgradeJobStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TaskStatus) DeepCopyInto(out *TaskStatus) {
 	*out = *in
+	if in.CheckResults != nil {
+		in, out := &in.CheckResults, &out.CheckResults
+		*out = make([]CheckResult, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
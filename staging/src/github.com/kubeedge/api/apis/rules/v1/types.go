@@ -11,7 +11,8 @@ type RuleSpec struct {
 	Source string `json:"source"`
 	// SourceResource is a map representing the resource info of source. For rest
 	// ruleendpoint type its value is {"path":"/a/b"}. For eventbus ruleendpoint type its
-	// value is {"topic":"<user define string>","node_name":"xxxx"}
+	// value is {"topic":"<user define string>","node_name":"xxxx"} or
+	// {"topic":"<user define string>","node_group":"xxxx"} to subscribe the topic on every node of the nodegroup.
 	SourceResource map[string]string `json:"sourceResource"`
 	// Target represents where the messages go to. its value is the same with ruleendpoint name.
 	// For example, eventbus or api or servicebus.
@@ -19,6 +20,9 @@ type RuleSpec struct {
 	// targetResource is a map representing the resource info of target. For api
 	// ruleendpoint type its value is {"resource":"http://a.com"}. For eventbus ruleendpoint
 	// type its value is {"topic":"/xxxx"}. For servicebus ruleendpoint type its value is {"path":"/request_path"}.
+	// Eventbus and servicebus targets may also address edge nodes directly with "node_name" (comma separated
+	// node names), "node_group" and "node_selector" (label selector of nodes), messages are then fanned out
+	// to all the addressed nodes except the node the message comes from.
 	TargetResource map[string]string `json:"targetResource"`
 }
 
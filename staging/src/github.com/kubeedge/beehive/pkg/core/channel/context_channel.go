@@ -2,6 +2,7 @@ package channel
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -24,12 +25,16 @@ const (
 // Context is object for Context channel
 type Context struct {
 	//ConfigFactory goarchaius.ConfigurationFactory
-	channels     map[string]chan model.Message
+	channels     map[string]*moduleQueue
 	chsLock      sync.RWMutex
-	typeChannels map[string]map[string]chan model.Message
+	typeChannels map[string]map[string]*moduleQueue
 	typeChsLock  sync.RWMutex
 	anonChannels map[string]chan model.Message
 	anonChsLock  sync.RWMutex
+
+	defaultQueueConfig QueueConfig
+	queueConfigs       map[string]QueueConfig
+	queueConfigsLock   sync.RWMutex
 }
 
 var channelContext *Context
@@ -38,13 +43,14 @@ var once sync.Once
 // NewChannelContext creates and returns object of new channel context
 func NewChannelContext() *Context {
 	once.Do(func() {
-		channelMap := make(map[string]chan model.Message)
-		moduleChannels := make(map[string]map[string]chan model.Message)
+		channelMap := make(map[string]*moduleQueue)
+		moduleChannels := make(map[string]map[string]*moduleQueue)
 		anonChannels := make(map[string]chan model.Message)
 		channelContext = &Context{
 			channels:     channelMap,
 			typeChannels: moduleChannels,
 			anonChannels: anonChannels,
+			queueConfigs: make(map[string]QueueConfig),
 		}
 	})
 	return channelContext
@@ -56,11 +62,12 @@ func (ctx *Context) Cleanup(module string) {
 		ctx.delChannel(module)
 		// decrease probable exception of channel closing
 		time.Sleep(20 * time.Millisecond)
-		close(channel)
+		channel.close()
 	}
 }
 
-// Send send msg to a module. Todo: do not stuck
+// Send send msg to a module, it blocks or drops the message according to
+// the overflow policy of the module when the channel of the module is full
 func (ctx *Context) Send(module string, message model.Message) {
 	// avoid exception because of channel closing
 	// TODO: need reconstruction
@@ -71,7 +78,9 @@ func (ctx *Context) Send(module string, message model.Message) {
 	}()
 
 	if channel := ctx.getChannel(module); channel != nil {
-		channel <- message
+		if err := channel.push(message); err != nil {
+			klog.Warning(err)
+		}
 		return
 	}
 	klog.Warningf("Get bad module name :%s when send message, do nothing", module)
@@ -80,7 +89,7 @@ func (ctx *Context) Send(module string, message model.Message) {
 // Receive msg from channel of module
 func (ctx *Context) Receive(module string) (model.Message, error) {
 	if channel := ctx.getChannel(module); channel != nil {
-		content := <-channel
+		content := <-channel.ch
 		return content, nil
 	}
 
@@ -129,9 +138,7 @@ func (ctx *Context) SendSync(module string, message model.Message, timeout time.
 		ctx.anonChsLock.Unlock()
 	}()
 
-	select {
-	case reqChannel <- message:
-	case <-time.After(timeout):
+	if err := reqChannel.pushWait(message, timeout); err != nil {
 		return model.Message{}, fmt.Errorf("timeout to send message %s", message.GetID())
 	}
 
@@ -164,9 +171,10 @@ func (ctx *Context) SendResp(message model.Message) {
 	klog.Warningf("Get bad anonName:%s when sendresp message, do nothing", anonName)
 }
 
-// SendToGroup send msg to modules. Todo: do not stuck
+// SendToGroup send msg to modules, only the modules with OverflowBlock policy
+// are sent to in goroutines since the other policies never block
 func (ctx *Context) SendToGroup(moduleType string, message model.Message) {
-	send := func(module string, ch chan model.Message) {
+	send := func(ch *moduleQueue) {
 		// avoid exception because of channel closing
 		// TODO: need reconstruction
 		defer func() {
@@ -174,16 +182,17 @@ func (ctx *Context) SendToGroup(moduleType string, message model.Message) {
 				klog.Warningf("Recover when sendToGroup message, exception: %+v", exception)
 			}
 		}()
-		select {
-		case ch <- message:
-		default:
-			klog.Warningf("The module %s message channel is full, message: %+v", module, message)
-			ch <- message
+		if err := ch.push(message); err != nil {
+			klog.Warning(err)
 		}
 	}
 	if channelList := ctx.getTypeChannel(moduleType); channelList != nil {
-		for module, channel := range channelList {
-			go send(module, channel)
+		for _, channel := range channelList {
+			if channel.config.Policy == OverflowBlock {
+				go send(channel)
+			} else {
+				send(channel)
+			}
 		}
 		return
 	}
@@ -236,7 +245,7 @@ func (ctx *Context) SendToGroupSync(moduleType string, message model.Message, ti
 	message.Header.Sync = true
 
 	var timeoutCounter int32
-	send := func(ch chan model.Message) {
+	send := func(ch *moduleQueue) {
 		// avoid exception because of channel closing
 		// TODO: need reconstruction
 		defer func() {
@@ -244,11 +253,12 @@ func (ctx *Context) SendToGroupSync(moduleType string, message model.Message, ti
 				klog.Warningf("Recover when sendToGroupsync message, exception: %+v", exception)
 			}
 		}()
-		sendTimer := time.NewTimer(time.Until(deadline))
-		select {
-		case ch <- message:
-			sendTimer.Stop()
-		case <-sendTimer.C:
+		timeout := time.Until(deadline)
+		if timeout <= 0 {
+			atomic.AddInt32(&timeoutCounter, 1)
+			return
+		}
+		if err := ch.pushWait(message, timeout); err != nil {
 			atomic.AddInt32(&timeoutCounter, 1)
 		}
 	}
@@ -282,13 +292,18 @@ func (ctx *Context) SendToGroupSync(moduleType string, message model.Message, ti
 }
 
 // New Channel
-func (ctx *Context) newChannel() chan model.Message {
-	channel := make(chan model.Message, ChannelSizeDefault)
-	return channel
+func (ctx *Context) newChannel(module string) *moduleQueue {
+	ctx.queueConfigsLock.RLock()
+	config, exist := ctx.queueConfigs[module]
+	if !exist {
+		config = ctx.defaultQueueConfig
+	}
+	ctx.queueConfigsLock.RUnlock()
+	return newModuleQueue(module, config)
 }
 
 // getChannel return chan
-func (ctx *Context) getChannel(module string) chan model.Message {
+func (ctx *Context) getChannel(module string) *moduleQueue {
 	ctx.chsLock.RLock()
 	defer ctx.chsLock.RUnlock()
 
@@ -301,7 +316,7 @@ func (ctx *Context) getChannel(module string) chan model.Message {
 }
 
 // addChannel return chan
-func (ctx *Context) addChannel(module string, moduleCh chan model.Message) {
+func (ctx *Context) addChannel(module string, moduleCh *moduleQueue) {
 	ctx.chsLock.Lock()
 	defer ctx.chsLock.Unlock()
 
@@ -332,7 +347,7 @@ func (ctx *Context) delChannel(module string) {
 }
 
 // getTypeChannel return chan
-func (ctx *Context) getTypeChannel(moduleType string) map[string]chan model.Message {
+func (ctx *Context) getTypeChannel(moduleType string) map[string]*moduleQueue {
 	ctx.typeChsLock.RLock()
 	defer ctx.typeChsLock.RUnlock()
 
@@ -344,7 +359,7 @@ func (ctx *Context) getTypeChannel(moduleType string) map[string]chan model.Mess
 	return nil
 }
 
-func (ctx *Context) getModuleByChannel(ch chan model.Message) string {
+func (ctx *Context) getModuleByChannel(ch *moduleQueue) string {
 	ctx.chsLock.RLock()
 	defer ctx.chsLock.RUnlock()
 
@@ -359,19 +374,19 @@ func (ctx *Context) getModuleByChannel(ch chan model.Message) string {
 }
 
 // addTypeChannel put modules into moduleType map
-func (ctx *Context) addTypeChannel(module, group string, moduleCh chan model.Message) {
+func (ctx *Context) addTypeChannel(module, group string, moduleCh *moduleQueue) {
 	ctx.typeChsLock.Lock()
 	defer ctx.typeChsLock.Unlock()
 
 	if _, exist := ctx.typeChannels[group]; !exist {
-		ctx.typeChannels[group] = make(map[string]chan model.Message)
+		ctx.typeChannels[group] = make(map[string]*moduleQueue)
 	}
 	ctx.typeChannels[group][module] = moduleCh
 }
 
 // AddModule adds module into module context
 func (ctx *Context) AddModule(info *common.ModuleInfo) {
-	channel := ctx.newChannel()
+	channel := ctx.newChannel(info.ModuleName)
 	ctx.addChannel(info.ModuleName, channel)
 }
 
@@ -383,3 +398,33 @@ func (ctx *Context) AddModuleGroup(module, group string) {
 	}
 	klog.Warningf("Get bad module name %s when addmodulegroup", module)
 }
+
+// SetDefaultQueueConfig sets the channel config of the modules without their own config,
+// it takes effect on the modules added afterwards
+func (ctx *Context) SetDefaultQueueConfig(config QueueConfig) {
+	ctx.queueConfigsLock.Lock()
+	defer ctx.queueConfigsLock.Unlock()
+	ctx.defaultQueueConfig = config
+}
+
+// SetQueueConfig sets the channel config of the module, it takes effect when the module is added
+func (ctx *Context) SetQueueConfig(module string, config QueueConfig) {
+	ctx.queueConfigsLock.Lock()
+	defer ctx.queueConfigsLock.Unlock()
+	ctx.queueConfigs[module] = config
+}
+
+// QueueStats returns the states of the channels of all modules
+func (ctx *Context) QueueStats() []QueueStats {
+	ctx.chsLock.RLock()
+	defer ctx.chsLock.RUnlock()
+
+	stats := make([]QueueStats, 0, len(ctx.channels))
+	for _, channel := range ctx.channels {
+		stats = append(stats, channel.stats())
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Module < stats[j].Module
+	})
+	return stats
+}
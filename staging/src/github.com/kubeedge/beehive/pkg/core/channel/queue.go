@@ -0,0 +1,225 @@
+package channel
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+)
+
+// OverflowPolicy decides what Send does when the channel of a module is full
+type OverflowPolicy string
+
+const (
+	// OverflowBlock blocks the sender until there is room, or until BlockTimeout and then drops the message
+	OverflowBlock OverflowPolicy = "Block"
+	// OverflowDropOldest drops the oldest message in the channel to make room for the new one
+	OverflowDropOldest OverflowPolicy = "DropOldest"
+	// OverflowDropNewest drops the new message
+	OverflowDropNewest OverflowPolicy = "DropNewest"
+	// OverflowSpillToDisk appends the messages to a file under SpillDir and feeds them
+	// back to the channel in order once the module catches up.
+	// The content of a spilled message is decoded from json, so the module should read it
+	// with GetContentData instead of asserting the original type.
+	OverflowSpillToDisk OverflowPolicy = "SpillToDisk"
+)
+
+// QueueConfig is the config of the message channel of a module
+type QueueConfig struct {
+	// Capacity is the size of the channel, ChannelSizeDefault if not set
+	Capacity int
+	// Policy is the overflow policy, OverflowBlock if not set
+	Policy OverflowPolicy
+	// BlockTimeout is how long Send waits with OverflowBlock, 0 means waiting forever
+	BlockTimeout time.Duration
+	// SpillDir is the directory of the spill files with OverflowSpillToDisk
+	SpillDir string
+	// MaxSpillSize is the max size of the spill file in bytes with OverflowSpillToDisk,
+	// the messages are dropped once it is reached, 0 means unlimited
+	MaxSpillSize int64
+}
+
+// QueueStats is the state of the message channel of a module
+type QueueStats struct {
+	Module   string         `json:"module"`
+	Policy   OverflowPolicy `json:"policy"`
+	Capacity int            `json:"capacity"`
+	// Depth is the number of messages in the channel
+	Depth int `json:"depth"`
+	// Spilled is the number of messages in the spill file
+	Spilled int64 `json:"spilled"`
+	// Enqueued is the number of messages put into the channel or the spill file
+	Enqueued uint64 `json:"enqueued"`
+	// Dropped is the number of messages dropped because the channel or the spill file is full
+	Dropped uint64 `json:"dropped"`
+	// AvgEnqueueWait and MaxEnqueueWait are how long the senders waited for room in the channel
+	AvgEnqueueWait time.Duration `json:"avgEnqueueWait"`
+	MaxEnqueueWait time.Duration `json:"maxEnqueueWait"`
+}
+
+// moduleQueue is the bounded message channel of a module
+type moduleQueue struct {
+	module string
+	config QueueConfig
+	ch     chan model.Message
+	spill  *spillQueue
+
+	enqueued    uint64
+	dropped     uint64
+	totalWaitNs int64
+	maxWaitNs   int64
+}
+
+func newModuleQueue(module string, config QueueConfig) *moduleQueue {
+	if config.Capacity <= 0 {
+		config.Capacity = ChannelSizeDefault
+	}
+	if config.Policy == "" {
+		config.Policy = OverflowBlock
+	}
+	q := &moduleQueue{
+		module: module,
+		config: config,
+		ch:     make(chan model.Message, config.Capacity),
+	}
+	if config.Policy == OverflowSpillToDisk {
+		spill, err := newSpillQueue(filepath.Join(config.SpillDir, module+".spill"), config.MaxSpillSize)
+		if err != nil {
+			klog.Errorf("failed to create spill file for module %s, fall back to %s: %v", module, OverflowDropNewest, err)
+			q.config.Policy = OverflowDropNewest
+		} else {
+			q.spill = spill
+			go q.drainSpill()
+		}
+	}
+	return q
+}
+
+// push puts the message into the channel according to the overflow policy
+func (q *moduleQueue) push(message model.Message) error {
+	if q.spill == nil || q.spill.Len() == 0 {
+		select {
+		case q.ch <- message:
+			atomic.AddUint64(&q.enqueued, 1)
+			return nil
+		default:
+		}
+	}
+
+	switch q.config.Policy {
+	case OverflowDropNewest:
+		return q.drop(message)
+	case OverflowDropOldest:
+		for {
+			select {
+			case q.ch <- message:
+				atomic.AddUint64(&q.enqueued, 1)
+				return nil
+			default:
+			}
+			select {
+			case oldest := <-q.ch:
+				if err := q.drop(oldest); err != nil {
+					klog.Warning(err)
+				}
+			default:
+			}
+		}
+	case OverflowSpillToDisk:
+		if err := q.spill.Push(message); err != nil {
+			if !errors.Is(err, errSpillFull) {
+				klog.Errorf("failed to spill message %s of module %s: %v", message.GetID(), q.module, err)
+			}
+			return q.drop(message)
+		}
+		atomic.AddUint64(&q.enqueued, 1)
+		return nil
+	default:
+		return q.pushWait(message, q.config.BlockTimeout)
+	}
+}
+
+// pushWait blocks until there is room in the channel, timeout <= 0 means waiting forever
+func (q *moduleQueue) pushWait(message model.Message, timeout time.Duration) error {
+	start := time.Now()
+	defer q.observeWait(start)
+
+	if timeout <= 0 {
+		q.ch <- message
+		atomic.AddUint64(&q.enqueued, 1)
+		return nil
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case q.ch <- message:
+		atomic.AddUint64(&q.enqueued, 1)
+		return nil
+	case <-timer.C:
+		return q.drop(message)
+	}
+}
+
+func (q *moduleQueue) drop(message model.Message) error {
+	atomic.AddUint64(&q.dropped, 1)
+	return fmt.Errorf("the message channel of module %s is full, drop message %s", q.module, message.GetID())
+}
+
+func (q *moduleQueue) observeWait(start time.Time) {
+	wait := int64(time.Since(start))
+	atomic.AddInt64(&q.totalWaitNs, wait)
+	for {
+		max := atomic.LoadInt64(&q.maxWaitNs)
+		if wait <= max || atomic.CompareAndSwapInt64(&q.maxWaitNs, max, wait) {
+			return
+		}
+	}
+}
+
+// drainSpill feeds the spilled messages back to the channel in order
+func (q *moduleQueue) drainSpill() {
+	defer func() {
+		if exception := recover(); exception != nil {
+			klog.Warningf("Recover when drain spilled messages of module %s, exception: %+v", q.module, exception)
+		}
+	}()
+	for {
+		message, ok := q.spill.Peek()
+		if !ok {
+			return
+		}
+		q.ch <- message
+		q.spill.Pop()
+	}
+}
+
+func (q *moduleQueue) close() {
+	if q.spill != nil {
+		q.spill.Close()
+	}
+	close(q.ch)
+}
+
+func (q *moduleQueue) stats() QueueStats {
+	stats := QueueStats{
+		Module:         q.module,
+		Policy:         q.config.Policy,
+		Capacity:       q.config.Capacity,
+		Depth:          len(q.ch),
+		Enqueued:       atomic.LoadUint64(&q.enqueued),
+		Dropped:        atomic.LoadUint64(&q.dropped),
+		MaxEnqueueWait: time.Duration(atomic.LoadInt64(&q.maxWaitNs)),
+	}
+	if q.spill != nil {
+		stats.Spilled = q.spill.Len()
+	}
+	if stats.Enqueued > 0 {
+		stats.AvgEnqueueWait = time.Duration(atomic.LoadInt64(&q.totalWaitNs) / int64(stats.Enqueued))
+	}
+	return stats
+}
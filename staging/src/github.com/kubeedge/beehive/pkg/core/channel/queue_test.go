@@ -0,0 +1,163 @@
+package channel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+)
+
+func newTestMessages(n int) []model.Message {
+	messages := make([]model.Message, 0, n)
+	for i := 0; i < n; i++ {
+		messages = append(messages, *model.NewMessage("").BuildRouter("src", "", "res", "op").FillBody(i))
+	}
+	return messages
+}
+
+func receiveIDs(q *moduleQueue, n int) []string {
+	ids := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		select {
+		case message := <-q.ch:
+			ids = append(ids, message.GetID())
+		case <-time.After(time.Second):
+			return ids
+		}
+	}
+	return ids
+}
+
+func TestModuleQueueOverflow(t *testing.T) {
+	messages := newTestMessages(3)
+	cases := []struct {
+		name     string
+		config   QueueConfig
+		expected []model.Message
+		dropped  uint64
+		// lastErr indicates whether pushing the last message fails
+		lastErr bool
+	}{
+		{
+			name:     "block with timeout",
+			config:   QueueConfig{Capacity: 2, Policy: OverflowBlock, BlockTimeout: 10 * time.Millisecond},
+			expected: messages[:2],
+			dropped:  1,
+			lastErr:  true,
+		},
+		{
+			name:     "drop newest",
+			config:   QueueConfig{Capacity: 2, Policy: OverflowDropNewest},
+			expected: messages[:2],
+			dropped:  1,
+			lastErr:  true,
+		},
+		{
+			name:     "drop oldest",
+			config:   QueueConfig{Capacity: 2, Policy: OverflowDropOldest},
+			expected: messages[1:],
+			dropped:  1,
+			lastErr:  false,
+		},
+		{
+			name:     "spill to disk",
+			config:   QueueConfig{Capacity: 2, Policy: OverflowSpillToDisk, SpillDir: t.TempDir()},
+			expected: messages,
+			dropped:  0,
+			lastErr:  false,
+		},
+		{
+			name:     "spill to disk until the max size",
+			config:   QueueConfig{Capacity: 2, Policy: OverflowSpillToDisk, SpillDir: t.TempDir(), MaxSpillSize: 1},
+			expected: messages[:2],
+			dropped:  1,
+			lastErr:  true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			q := newModuleQueue("test", c.config)
+			defer q.close()
+			for i, message := range messages {
+				err := q.push(message)
+				if expectErr := c.lastErr && i == len(messages)-1; (err != nil) != expectErr {
+					t.Errorf("unexpected push error of message %d: %v", i, err)
+				}
+			}
+
+			stats := q.stats()
+			if stats.Dropped != c.dropped {
+				t.Errorf("expected %d dropped, got %d", c.dropped, stats.Dropped)
+			}
+			if stats.Depth+int(stats.Spilled) != len(c.expected) {
+				t.Errorf("expected depth %d, got %+v", len(c.expected), stats)
+			}
+
+			ids := receiveIDs(q, len(c.expected))
+			if len(ids) != len(c.expected) {
+				t.Fatalf("expected %d messages, got %d", len(c.expected), len(ids))
+			}
+			for i, message := range c.expected {
+				if ids[i] != message.GetID() {
+					t.Errorf("expected message %v at %d, got %s", message.GetContent(), i, ids[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSpillQueue(t *testing.T) {
+	q, err := newSpillQueue(t.TempDir()+"/test.spill", 0)
+	if err != nil {
+		t.Fatalf("failed to create spill queue: %v", err)
+	}
+	defer q.Close()
+
+	messages := newTestMessages(3)
+	for _, message := range messages {
+		if err := q.Push(message); err != nil {
+			t.Fatalf("failed to push: %v", err)
+		}
+	}
+	for i, expected := range messages {
+		message, ok := q.Peek()
+		if !ok || message.GetID() != expected.GetID() {
+			t.Fatalf("unexpected message at %d: %v", i, message)
+		}
+		if content, _ := message.GetContentData(); string(content) != string(rune('0'+i)) {
+			t.Errorf("unexpected content at %d: %s", i, content)
+		}
+		q.Pop()
+	}
+	if q.Len() != 0 || q.writeOffset != 0 {
+		t.Errorf("expected the spill queue to be reset, len %d offset %d", q.Len(), q.writeOffset)
+	}
+
+	go q.Close()
+	if _, ok := q.Peek(); ok {
+		t.Errorf("expected peek to return false once closed")
+	}
+}
+
+func TestSendToGroupNotBlocked(t *testing.T) {
+	ctx := &Context{
+		channels:     make(map[string]*moduleQueue),
+		typeChannels: make(map[string]map[string]*moduleQueue),
+		anonChannels: make(map[string]chan model.Message),
+		queueConfigs: make(map[string]QueueConfig),
+	}
+	ctx.SetQueueConfig("stuck", QueueConfig{Capacity: 1, Policy: OverflowDropNewest})
+	ctx.addChannel("stuck", ctx.newChannel("stuck"))
+	ctx.addTypeChannel("stuck", "group", ctx.getChannel("stuck"))
+
+	for _, message := range newTestMessages(10) {
+		ctx.SendToGroup("group", message)
+		ctx.Send("stuck", message)
+	}
+
+	stats := ctx.QueueStats()
+	if len(stats) != 1 || stats[0].Depth != 1 || stats[0].Dropped != 19 {
+		t.Errorf("unexpected queue stats: %+v", stats)
+	}
+}
@@ -0,0 +1,165 @@
+package channel
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+)
+
+const spillHeaderLen = 4
+
+// errSpillFull is returned by Push when the spill file reaches its max size
+var errSpillFull = errors.New("spill file is full")
+
+// spillQueue is a file backed FIFO of messages, the records are
+// appended as a 4 bytes big endian length followed by the json message
+type spillQueue struct {
+	lock sync.Mutex
+	cond *sync.Cond
+	file *os.File
+	// maxSize is the max size of the spill file in bytes, 0 means unlimited
+	maxSize int64
+
+	readOffset  int64
+	writeOffset int64
+	// count is the number of messages not popped yet
+	count  int64
+	closed bool
+}
+
+func newSpillQueue(path string, maxSize int64) (*spillQueue, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	// the messages spilled by the previous process are not replayed
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	q := &spillQueue{file: file, maxSize: maxSize}
+	q.cond = sync.NewCond(&q.lock)
+	return q, nil
+}
+
+// Push appends the message to the file, it returns errSpillFull if the file would exceed the max size
+func (q *spillQueue) Push(message model.Message) error {
+	data, err := json.Marshal(&message)
+	if err != nil {
+		return err
+	}
+	record := make([]byte, spillHeaderLen+len(data))
+	binary.BigEndian.PutUint32(record, uint32(len(data)))
+	copy(record[spillHeaderLen:], data)
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.closed {
+		return fmt.Errorf("spill queue is closed")
+	}
+	if q.maxSize > 0 && q.writeOffset+int64(len(record)) > q.maxSize {
+		return errSpillFull
+	}
+	if _, err := q.file.WriteAt(record, q.writeOffset); err != nil {
+		return err
+	}
+	q.writeOffset += int64(len(record))
+	q.count++
+	q.cond.Signal()
+	return nil
+}
+
+// Peek blocks until there is a message in the file and returns it without removing it,
+// it returns false once the queue is closed
+func (q *spillQueue) Peek() (model.Message, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for {
+		for q.count == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if q.closed {
+			return model.Message{}, false
+		}
+
+		message, err := q.read()
+		if err == nil {
+			return message, true
+		}
+		klog.Errorf("failed to read spill file %s, discard %d messages: %v", q.file.Name(), q.count, err)
+		q.reset()
+	}
+}
+
+// read reads the message at readOffset
+func (q *spillQueue) read() (model.Message, error) {
+	var message model.Message
+	header := make([]byte, spillHeaderLen)
+	if _, err := q.file.ReadAt(header, q.readOffset); err != nil {
+		return message, err
+	}
+	data := make([]byte, binary.BigEndian.Uint32(header))
+	if _, err := q.file.ReadAt(data, q.readOffset+spillHeaderLen); err != nil {
+		return message, err
+	}
+	if err := json.Unmarshal(data, &message); err != nil {
+		return message, err
+	}
+	return message, nil
+}
+
+func (q *spillQueue) reset() {
+	q.count = 0
+	if err := q.file.Truncate(0); err != nil {
+		klog.Errorf("failed to truncate spill file %s: %v", q.file.Name(), err)
+	}
+	q.readOffset, q.writeOffset = 0, 0
+}
+
+// Pop removes the message returned by Peek
+func (q *spillQueue) Pop() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.count == 0 {
+		return
+	}
+
+	header := make([]byte, spillHeaderLen)
+	if _, err := q.file.ReadAt(header, q.readOffset); err != nil {
+		q.reset()
+		return
+	}
+	q.readOffset += spillHeaderLen + int64(binary.BigEndian.Uint32(header))
+	q.count--
+	// reuse the file once it is drained
+	if q.count == 0 {
+		q.reset()
+	}
+}
+
+// Len returns the number of messages not popped yet
+func (q *spillQueue) Len() int64 {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.count
+}
+
+// Close closes and removes the file
+func (q *spillQueue) Close() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	q.cond.Broadcast()
+	q.file.Close()
+	os.Remove(q.file.Name())
+}
@@ -80,7 +80,9 @@ func Run() {
 
 func moduleKeeper(name string, moduleInfo *ModuleInfo, m common.ModuleInfo) {
 	for {
+		moduleInfo.setStarted()
 		moduleInfo.module.Start()
+		moduleInfo.setExited(nil)
 		// local modules are always online
 		if !moduleInfo.remote {
 			return
@@ -95,7 +97,9 @@ func moduleKeeper(name string, moduleInfo *ModuleInfo, m common.ModuleInfo) {
 // Call EnableModuleRestart() to enable auto-restarting feature in alpha version.
 func localModuleKeeper(m *ModuleInfo) {
 	if !moduleRestartEnabled {
+		m.setStarted()
 		m.module.Start()
+		m.setExited(nil)
 		return
 	}
 
@@ -104,15 +108,18 @@ func localModuleKeeper(m *ModuleInfo) {
 
 	// do if module exits
 	afterFunc := func() {
-		if r := recover(); r != nil {
+		r := recover()
+		if r != nil {
 			klog.Errorf("module %s panicking: %v", m.module.Name(), r)
 		}
+		m.setExited(r)
 		klog.Errorf("module %s exited, will restart in %ds", m.module.Name(), int(backoffDuration.Seconds()))
 	}
 
 	for {
 		func() {
 			defer afterFunc()
+			m.setStarted()
 			m.module.Start()
 		}()
 
@@ -1,6 +1,11 @@
 package core
 
 import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/beehive/pkg/common"
@@ -33,6 +38,22 @@ type ModuleInfo struct {
 	contextType string
 	remote      bool
 	module      Module
+
+	statusLock sync.RWMutex
+	status     ModuleStatus
+}
+
+// ModuleStatus is the running state of a module
+type ModuleStatus struct {
+	Name    string `json:"name"`
+	Group   string `json:"group"`
+	Running bool   `json:"running"`
+	// Restarts is the number of times the module is restarted after exiting
+	Restarts  int       `json:"restarts"`
+	StartTime time.Time `json:"startTime"`
+	ExitTime  time.Time `json:"exitTime"`
+	// LastError is the panic of the last exit of the module, if any
+	LastError string `json:"lastError,omitempty"`
 }
 
 // Register register module
@@ -68,6 +89,46 @@ func (m *ModuleInfo) GetModule() Module {
 	return m.module
 }
 
+// GetStatus returns the running state of the module
+func (m *ModuleInfo) GetStatus() ModuleStatus {
+	m.statusLock.RLock()
+	defer m.statusLock.RUnlock()
+	status := m.status
+	status.Name = m.module.Name()
+	status.Group = m.module.Group()
+	return status
+}
+
+func (m *ModuleInfo) setStarted() {
+	m.statusLock.Lock()
+	defer m.statusLock.Unlock()
+	if !m.status.StartTime.IsZero() {
+		m.status.Restarts++
+	}
+	m.status.Running = true
+	m.status.StartTime = time.Now()
+}
+
+func (m *ModuleInfo) setExited(exception interface{}) {
+	m.statusLock.Lock()
+	defer m.statusLock.Unlock()
+	m.status.Running = false
+	m.status.ExitTime = time.Now()
+	if exception != nil {
+		m.status.LastError = fmt.Sprintf("%v", exception)
+	}
+}
+
+// GetModuleStatus returns the running states of the enabled modules sorted by name
+func GetModuleStatus() []ModuleStatus {
+	list := make([]ModuleStatus, 0, len(modules))
+	for _, moduleInfo := range modules {
+		list = append(list, moduleInfo.GetStatus())
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
 // GetModuleExchange return module exchange
 func GetModuleExchange() *socket.ModuleExchange {
 	exchange := socket.ModuleExchange{
@@ -122,13 +122,15 @@ func (broker *RemoteBroker) SendSync(conn wrapper.Conn, message model.Message, t
 	// make sure to set sync flag
 	message.Header.Sync = true
 
+	// keep the channel before writing, the response may arrive at once
+	tempChannel := broker.keeper.AddKeepChannel(message.GetID())
 	err := conn.WriteJSON(&message)
 	if err != nil {
+		broker.keeper.DeleteKeepChannel(message.GetID())
 		klog.Errorf("failed to write with error %+v", err)
 		return model.Message{}, fmt.Errorf("failed to write, error: %+v", err)
 	}
 
-	tempChannel := broker.keeper.AddKeepChannel(message.GetID())
 	sendTimer := time.NewTimer(time.Until(deadline))
 	select {
 	case response := <-tempChannel:
@@ -0,0 +1,113 @@
+package extension
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/common"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/beehive/pkg/core/socket"
+	"github.com/kubeedge/beehive/pkg/core/socket/broker"
+	"github.com/kubeedge/beehive/pkg/core/socket/wrapper"
+)
+
+// Client is used by an extension module process to exchange
+// beehive messages with the module server of edgecore
+type Client struct {
+	name     string
+	conn     wrapper.Conn
+	broker   *broker.RemoteBroker
+	exchange socket.ModuleExchange
+}
+
+// Dial connects to the socket of the extension module and registers as the module name,
+// the name must be the same as the one configured in edgecore
+func Dial(name, address string, buffSize int) (*Client, error) {
+	if buffSize <= 0 {
+		buffSize = BufferSizeDefault
+	}
+	c, err := net.Dial(SocketType, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s, error: %v", address, err)
+	}
+
+	client := &Client{
+		name:   name,
+		conn:   wrapper.NewPackageWrapper(c, buffSize),
+		broker: broker.NewRemoteBroker(),
+	}
+	if err := client.handshake(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *Client) handshake() error {
+	moduleMsg := model.NewMessage("").
+		BuildRouter(c.name, "", common.ResourceTypeModule, common.OperationTypeModule).
+		FillBody("")
+	resp, err := c.broker.SendSyncInternal(c.conn, *moduleMsg, 0)
+	if err != nil {
+		return fmt.Errorf("failed to register module %s, error: %v", c.name, err)
+	}
+	// reset the deadline set by the sync request
+	if err := c.conn.SetReadDeadline(time.Time{}); err != nil {
+		return err
+	}
+
+	bytes, err := json.Marshal(resp.GetContent())
+	if err != nil {
+		return fmt.Errorf("failed to marshal module exchange, error: %v", err)
+	}
+	if err := json.Unmarshal(bytes, &c.exchange); err != nil {
+		return fmt.Errorf("bad module exchange %s, error: %v", string(bytes), err)
+	}
+	klog.Infof("module %s registered, modules: %v", c.name, c.exchange.Modules)
+	return nil
+}
+
+// Modules returns the modules and groups of edgecore at the time of registering
+func (c *Client) Modules() socket.ModuleExchange {
+	return c.exchange
+}
+
+// Send sends the message to the module
+func (c *Client) Send(module string, message model.Message) error {
+	message.Router.Destination = module
+	return c.broker.Send(c.conn, message)
+}
+
+// SendToGroup sends the message to all the modules of the group
+func (c *Client) SendToGroup(group string, message model.Message) error {
+	message.Router.Destination = ""
+	message.Router.Group = group
+	return c.broker.Send(c.conn, message)
+}
+
+// SendSync sends the message to the module and waits for the response,
+// Receive must be called in a loop meanwhile to read the response from the socket
+func (c *Client) SendSync(module string, message model.Message, timeout time.Duration) (model.Message, error) {
+	message.Router.Destination = module
+	return c.broker.SendSync(c.conn, message, timeout)
+}
+
+// SendResp sends the response of a sync message received
+func (c *Client) SendResp(resp model.Message) error {
+	return c.broker.Send(c.conn, resp)
+}
+
+// Receive blocks until a message is sent to the module,
+// the responses of SendSync are not returned
+func (c *Client) Receive() (model.Message, error) {
+	return c.broker.Receive(c.conn)
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,135 @@
+package extension
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kubeedge/beehive/pkg/common"
+	"github.com/kubeedge/beehive/pkg/core"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+)
+
+func dialWithRetry(t *testing.T, name, address string) *Client {
+	var err error
+	for i := 0; i < 50; i++ {
+		var client *Client
+		client, err = Dial(name, address, 0)
+		if err == nil {
+			return client
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("failed to dial %s: %v", address, err)
+	return nil
+}
+
+func TestExtensionModule(t *testing.T) {
+	beehiveContext.InitContext([]string{common.MsgCtxTypeChannel})
+	for _, name := range []string{"ext", "peer"} {
+		beehiveContext.AddModule(&common.ModuleInfo{
+			ModuleName: name,
+			ModuleType: common.MsgCtxTypeChannel,
+		})
+	}
+	beehiveContext.AddModuleGroup("peer", "peergroup")
+
+	address := filepath.Join(t.TempDir(), "ext.sock")
+	module := NewModule("ext", "", address, 0)
+	core.Register(module)
+	go module.Start()
+	t.Cleanup(module.Stop)
+
+	if _, err := Dial("other", address, 0); err == nil {
+		t.Fatalf("expected module with another name to be rejected")
+	}
+
+	client := dialWithRetry(t, "ext", address)
+	defer client.Close()
+	if got := client.Modules().Groups["ext"]; len(got) != 1 || got[0] != "ext" {
+		t.Fatalf("unexpected module exchange: %+v", client.Modules())
+	}
+
+	if _, err := Dial("ext", address, 0); err == nil {
+		t.Fatalf("expected second connection to be rejected")
+	}
+
+	t.Run("send from process", func(t *testing.T) {
+		msg := model.NewMessage("").BuildRouter("fake", "", "configmap", model.UpdateOperation)
+		if err := client.Send("peer", *msg); err != nil {
+			t.Fatalf("failed to send: %v", err)
+		}
+		got, err := beehiveContext.Receive("peer")
+		if err != nil {
+			t.Fatalf("failed to receive: %v", err)
+		}
+		if got.GetID() != msg.GetID() || got.GetSource() != "ext" {
+			t.Errorf("unexpected message: %s", got.String())
+		}
+
+		if err := client.SendToGroup("peergroup", *msg.UpdateID()); err != nil {
+			t.Fatalf("failed to send to group: %v", err)
+		}
+		if got, _ = beehiveContext.Receive("peer"); got.GetID() != msg.GetID() {
+			t.Errorf("unexpected group message: %s", got.String())
+		}
+	})
+
+	t.Run("send to process", func(t *testing.T) {
+		msg := model.NewMessage("").BuildRouter("peer", "", "configmap", model.UpdateOperation)
+		beehiveContext.Send("ext", *msg)
+		got, err := client.Receive()
+		if err != nil {
+			t.Fatalf("failed to receive: %v", err)
+		}
+		if got.GetID() != msg.GetID() {
+			t.Errorf("unexpected message: %s", got.String())
+		}
+	})
+
+	t.Run("sync from beehive", func(t *testing.T) {
+		go func() {
+			req, err := client.Receive()
+			if err != nil {
+				return
+			}
+			client.SendResp(*model.NewMessage("").NewRespByMessage(&req, "pong"))
+		}()
+
+		msg := model.NewMessage("").BuildRouter("peer", "", "ping", model.QueryOperation)
+		resp, err := beehiveContext.SendSync("ext", *msg, 5*time.Second)
+		if err != nil {
+			t.Fatalf("failed to send sync: %v", err)
+		}
+		if resp.GetParentID() != msg.GetID() || resp.GetContent() != "pong" {
+			t.Errorf("unexpected response: %s", resp.String())
+		}
+	})
+
+	t.Run("sync from process", func(t *testing.T) {
+		go func() {
+			req, err := beehiveContext.Receive("peer")
+			if err != nil {
+				return
+			}
+			beehiveContext.SendResp(*model.NewMessage("").NewRespByMessage(&req, "pong"))
+		}()
+		go func() {
+			for {
+				if _, err := client.Receive(); err != nil {
+					return
+				}
+			}
+		}()
+
+		msg := model.NewMessage("").BuildRouter("ext", "", "ping", model.QueryOperation)
+		resp, err := client.SendSync("peer", *msg, 5*time.Second)
+		if err != nil {
+			t.Fatalf("failed to send sync: %v", err)
+		}
+		if resp.GetParentID() != msg.GetID() || resp.GetContent() != "pong" {
+			t.Errorf("unexpected response: %s", resp.String())
+		}
+	})
+}
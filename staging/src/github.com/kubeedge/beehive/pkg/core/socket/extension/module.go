@@ -0,0 +1,300 @@
+package extension
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/common"
+	"github.com/kubeedge/beehive/pkg/core"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/beehive/pkg/core/socket/broker"
+	"github.com/kubeedge/beehive/pkg/core/socket/wrapper"
+)
+
+const (
+	// SocketType is the socket type used between the extension module and the module server
+	SocketType = "unix"
+	// BufferSizeDefault is the default max size of one message
+	BufferSizeDefault = 2 * 1024 * 1024
+)
+
+// Module is a beehive module whose logic runs in another process.
+// It listens on a unix socket, messages sent to the module in beehive are
+// forwarded to the connected process, and messages written by the process
+// are routed into beehive on behalf of the module.
+type Module struct {
+	name     string
+	group    string
+	address  string
+	buffSize int
+	broker   *broker.RemoteBroker
+
+	lock sync.Mutex
+	conn wrapper.Conn
+	// pending holds the ids of the sync messages waiting for a response from the process
+	pending map[string]struct{}
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	// wg tracks the goroutines which use the beehive context, Stop waits for them
+	wg sync.WaitGroup
+}
+
+// NewModule new extension module
+func NewModule(name, group, address string, buffSize int) *Module {
+	if group == "" {
+		group = name
+	}
+	if buffSize <= 0 {
+		buffSize = BufferSizeDefault
+	}
+	return &Module{
+		name:     name,
+		group:    group,
+		address:  address,
+		buffSize: buffSize,
+		broker:   broker.NewRemoteBroker(),
+		pending:  make(map[string]struct{}),
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Name name
+func (m *Module) Name() string {
+	return m.name
+}
+
+// Group group
+func (m *Module) Group() string {
+	return m.group
+}
+
+// Enable enable
+func (m *Module) Enable() bool {
+	return true
+}
+
+// Start start
+func (m *Module) Start() {
+	m.wg.Add(1)
+	defer m.wg.Done()
+
+	listener, err := m.listen()
+	if err != nil {
+		klog.Errorf("extension module %s failed to listen on %s, error: %v", m.name, m.address, err)
+		return
+	}
+	go func() {
+		select {
+		case <-beehiveContext.Done():
+		case <-m.stopCh:
+		}
+		if err := listener.Close(); err != nil {
+			klog.Errorf("extension module %s failed to close listener, error: %v", m.name, err)
+		}
+	}()
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.accept(listener)
+	}()
+
+	m.pump()
+}
+
+// Stop stops the module: it closes the listener and the connection of the process,
+// cleans up the channel of the module to unblock the receiving, and waits until
+// the module stops using the beehive context.
+func (m *Module) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+
+		m.lock.Lock()
+		if m.conn != nil {
+			m.conn.Close()
+		}
+		m.lock.Unlock()
+
+		beehiveContext.Cleanup(m.name)
+	})
+	m.wg.Wait()
+}
+
+func (m *Module) stopped() bool {
+	select {
+	case <-beehiveContext.Done():
+		return true
+	case <-m.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *Module) listen() (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(m.address), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create socket dir, error: %v", err)
+	}
+	if err := os.Remove(m.address); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove stale socket, error: %v", err)
+	}
+	listener, err := net.Listen(SocketType, m.address)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(m.address, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to chmod socket, error: %v", err)
+	}
+	klog.Infof("extension module %s listening on %s", m.name, m.address)
+	return listener, nil
+}
+
+func (m *Module) accept(listener net.Listener) {
+	for {
+		c, err := listener.Accept()
+		if err != nil {
+			if !m.stopped() {
+				klog.Errorf("extension module %s failed to accept, error: %v", m.name, err)
+			}
+			return
+		}
+
+		conn := wrapper.NewPackageWrapper(c, m.buffSize)
+		if err := m.handshake(conn); err != nil {
+			klog.Errorf("extension module %s rejected connection, error: %v", m.name, err)
+			conn.Close()
+			continue
+		}
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			m.serve(conn)
+		}()
+	}
+}
+
+// handshake reads the module message of the process, and replies
+// with the modules and groups known by beehive
+func (m *Module) handshake(conn wrapper.Conn) error {
+	var message model.Message
+	if err := conn.ReadJSON(&message); err != nil {
+		return fmt.Errorf("failed to read module message, error: %v", err)
+	}
+	if message.GetResource() != common.ResourceTypeModule ||
+		message.GetOperation() != common.OperationTypeModule {
+		return fmt.Errorf("unexpected first message: %s", message.String())
+	}
+	if message.GetSource() != m.name {
+		return fmt.Errorf("module %s is not allowed on this socket", message.GetSource())
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.stopped() {
+		return fmt.Errorf("module %s is stopped", m.name)
+	}
+	if m.conn != nil {
+		return fmt.Errorf("module %s is already connected", m.name)
+	}
+	resp := model.NewMessage("").NewRespByMessage(&message, core.GetModuleExchange())
+	if err := m.broker.Send(conn, *resp); err != nil {
+		return err
+	}
+	m.conn = conn
+	klog.Infof("extension module %s connected", m.name)
+	return nil
+}
+
+// serve routes the messages written by the process into beehive
+func (m *Module) serve(conn wrapper.Conn) {
+	defer func() {
+		m.lock.Lock()
+		m.conn = nil
+		m.pending = make(map[string]struct{})
+		m.lock.Unlock()
+		conn.Close()
+		klog.Infof("extension module %s disconnected", m.name)
+	}()
+
+	for {
+		message, err := m.broker.Receive(conn)
+		if err != nil {
+			return
+		}
+		m.dispatch(conn, message)
+	}
+}
+
+func (m *Module) dispatch(conn wrapper.Conn, message model.Message) {
+	// the process can only speak for the module it connected as
+	message.Router.Source = m.name
+	message.Header.MessageType = ""
+
+	m.lock.Lock()
+	_, isResp := m.pending[message.GetParentID()]
+	delete(m.pending, message.GetParentID())
+	m.lock.Unlock()
+
+	switch {
+	case isResp:
+		beehiveContext.SendResp(message)
+	case message.IsSync() && message.GetDestination() != "":
+		go func() {
+			resp, err := beehiveContext.SendSync(message.GetDestination(), message, 0)
+			if err != nil {
+				resp = *model.NewErrorMessage(&message, err.Error())
+			}
+			if err := m.broker.Send(conn, resp); err != nil {
+				klog.Errorf("extension module %s failed to send response, error: %v", m.name, err)
+			}
+		}()
+	case message.GetDestination() != "":
+		beehiveContext.Send(message.GetDestination(), message)
+	case message.GetGroup() != "":
+		beehiveContext.SendToGroup(message.GetGroup(), message)
+	default:
+		klog.Warningf("extension module %s drops message without destination: %s", m.name, message.String())
+	}
+}
+
+// pump forwards the messages sent to the module in beehive to the process
+func (m *Module) pump() {
+	for {
+		if m.stopped() {
+			klog.Warningf("extension module %s stop", m.name)
+			return
+		}
+
+		message, err := beehiveContext.Receive(m.name)
+		if err != nil {
+			klog.Errorf("extension module %s failed to receive message, error: %v", m.name, err)
+			continue
+		}
+		if message.GetID() == "" {
+			klog.Warningf("extension module %s channel is closed, stop", m.name)
+			return
+		}
+
+		m.lock.Lock()
+		conn := m.conn
+		if conn != nil && message.IsSync() {
+			m.pending[message.GetID()] = struct{}{}
+		}
+		m.lock.Unlock()
+
+		if conn == nil {
+			klog.Warningf("extension module %s is not connected, drop message %s", m.name, message.GetID())
+			continue
+		}
+		if err := m.broker.Send(conn, message); err != nil {
+			klog.Errorf("extension module %s failed to forward message, error: %v", m.name, err)
+		}
+	}
+}
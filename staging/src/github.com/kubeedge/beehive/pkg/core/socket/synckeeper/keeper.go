@@ -47,7 +47,8 @@ func (k *Keeper) SendToKeepChannel(message model.Message) error {
 func (k *Keeper) AddKeepChannel(msgID string) chan model.Message {
 	k.keeperLock.Lock()
 	defer k.keeperLock.Unlock()
-	tempChannel := make(chan model.Message)
+	// buffered so that the response is not dropped if it arrives before the sender waits for it
+	tempChannel := make(chan model.Message, 1)
 	k.syncKeeper[msgID] = tempChannel
 	return tempChannel
 }
@@ -59,12 +59,13 @@ func (p *Packer) Validate(data []byte) bool {
 
 // Write write
 func (p *Packer) Write(writer io.Writer) error {
-	// fill message len
-	headerTags[MessageLenOffest] = byte(uint32(p.Length) >> 24)
-	headerTags[MessageLenOffest+1] = byte(uint32(p.Length) >> 16)
-	headerTags[MessageLenOffest+2] = byte(uint32(p.Length) >> 8)
-	headerTags[MessageLenOffest+3] = byte(uint32(p.Length))
-	err := binary.Write(writer, binary.BigEndian, &headerTags)
+	// fill message len, copy the header tags since packers may write concurrently
+	header := headerTags
+	header[MessageLenOffest] = byte(uint32(p.Length) >> 24)
+	header[MessageLenOffest+1] = byte(uint32(p.Length) >> 16)
+	header[MessageLenOffest+2] = byte(uint32(p.Length) >> 8)
+	header[MessageLenOffest+3] = byte(uint32(p.Length))
+	err := binary.Write(writer, binary.BigEndian, &header)
 	if err != nil {
 		return err
 	}
@@ -42,6 +42,16 @@ func NewWrapper(_ string, conn interface{}, buffSize int) Conn {
 	}
 }
 
+// NewPackageWrapper new wrapper which frames the messages with packer,
+// it is used for stream sockets which do not preserve message boundaries
+func NewPackageWrapper(conn interface{}, buffSize int) Conn {
+	return &ConnWrapper{
+		conn:   conn,
+		reader: reader.NewReader(reader.ReaderTypePackage, conn, buffSize),
+		writer: writer.NewWriter(writer.WriterTypePackage, conn),
+	}
+}
+
 // Read read
 func (w *ConnWrapper) Read() ([]byte, error) {
 	return w.reader.Read()
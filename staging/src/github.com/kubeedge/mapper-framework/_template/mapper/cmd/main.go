@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 
 	"k8s.io/klog/v2"
@@ -31,6 +32,8 @@ func main() {
 		klog.Fatal(err)
 	}
 	klog.Infoln("Mapper register finished")
+	go grpcclient.StartHeartbeat(context.Background(), common.DefaultHeartbeatCycle, nil)
+	go grpcclient.StartStatusStream(context.Background(), grpcclient.NewStatusStreamReporter(0, 0, 0))
 
 	panel := device.NewDevPanel()
 	err = panel.DevInit(deviceList, deviceModelList)
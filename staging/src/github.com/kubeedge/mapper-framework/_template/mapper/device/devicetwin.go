@@ -3,6 +3,7 @@ package device
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -60,7 +61,7 @@ func (td *TwinData) GetPayLoad() ([]byte, error) {
 	return payload, nil
 }
 
-func (td *TwinData) PushToEdgeCore() {
+func (td *TwinData) PushToEdgeCore(ctx context.Context) {
 	payload, err := td.GetPayLoad()
 	if err != nil {
 		klog.Errorf("twindata %s unmarshal failed, err: %s", td.Name, err)
@@ -84,7 +85,11 @@ func (td *TwinData) PushToEdgeCore() {
 		},
 	}
 
-	if err := grpcclient.ReportDeviceStatus(rdsr); err != nil {
+	err = grpcclient.ReportDeviceStatusByStream(ctx, rdsr)
+	if errors.Is(err, grpcclient.ErrStatusStreamNotRunning) {
+		err = grpcclient.ReportDeviceStatus(rdsr)
+	}
+	if err != nil {
 		klog.Errorf("fail to report device status of %s with err: %+v", rdsr.DeviceName, err)
 	}
 }
@@ -100,7 +105,7 @@ func (td *TwinData) Run(ctx context.Context) {
 	for {
 		select {
 		case <-ticker.C:
-			td.PushToEdgeCore()
+			td.PushToEdgeCore(ctx)
 		case <-ctx.Done():
 			return
 		}
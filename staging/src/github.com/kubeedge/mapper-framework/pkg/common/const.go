@@ -38,6 +38,7 @@ const (
 
 const DefaultCollectCycle = time.Second
 const DefaultReportCycle = time.Second
+const DefaultHeartbeatCycle = 10 * time.Second
 
 const (
 	DevInitModeRegister  = "register"
@@ -0,0 +1,72 @@
+package grpcclient
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	dmiapi "github.com/kubeedge/api/apis/dmi/v1beta1"
+	"github.com/kubeedge/mapper-framework/pkg/common"
+	"github.com/kubeedge/mapper-framework/pkg/config"
+)
+
+// HealthFunc returns the health state of the mapper and the reason if it is not healthy
+type HealthFunc func() (state string, reason string)
+
+// MapperHeartbeat sends a heartbeat to edgecore, it returns true if edgecore asks the mapper to register again
+func MapperHeartbeat(ctx context.Context, state, reason string, interval time.Duration) (bool, error) {
+	conn, err := dialEdgeCore(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	c := dmiapi.NewDeviceManagerServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	resp, err := c.MapperHeartbeat(ctx, &dmiapi.MapperHeartbeatRequest{
+		MapperName:      config.Cfg().Common.Name,
+		State:           state,
+		Reason:          reason,
+		IntervalSeconds: int64(interval / time.Second),
+	})
+	if err != nil {
+		return false, err
+	}
+	return resp.Reregister, nil
+}
+
+// StartHeartbeat sends heartbeats to edgecore every interval until ctx is done.
+// The mapper is reported healthy if health is nil. If edgecore does not know the mapper,
+// e.g. its data is lost, the mapper registers again.
+func StartHeartbeat(ctx context.Context, interval time.Duration, health HealthFunc) {
+	if interval < time.Second {
+		interval = common.DefaultHeartbeatCycle
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		state, reason := common.DeviceStatusOK, ""
+		if health != nil {
+			state, reason = health()
+		}
+		reregister, err := MapperHeartbeat(ctx, state, reason, interval)
+		if err != nil {
+			klog.Errorf("fail to send heartbeat to edgecore: %v", err)
+		} else if reregister {
+			klog.Infoln("edgecore asks mapper to register again")
+			if _, _, err := RegisterMapper(false); err != nil {
+				klog.Errorf("fail to register mapper again: %v", err)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
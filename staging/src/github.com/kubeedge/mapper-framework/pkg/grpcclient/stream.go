@@ -0,0 +1,292 @@
+package grpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+
+	dmiapi "github.com/kubeedge/api/apis/dmi/v1beta1"
+	"github.com/kubeedge/mapper-framework/pkg/config"
+)
+
+const (
+	// DefaultStreamBatchSize is the default max number of device status in a batch
+	DefaultStreamBatchSize = 100
+	// DefaultStreamFlushInterval is the default interval a partial batch is sent after
+	DefaultStreamFlushInterval = 100 * time.Millisecond
+	// DefaultStreamMaxInFlight is the default max number of unacknowledged batches
+	DefaultStreamMaxInFlight = 4
+	// streamRetryInterval is the interval to reconnect after the stream is broken
+	streamRetryInterval = time.Second
+)
+
+// ErrStatusStreamNotRunning is returned by ReportDeviceStatusByStream if no StatusStreamReporter is running
+var ErrStatusStreamNotRunning = errors.New("device status stream reporter is not running")
+
+// statusStream is the reporter started by StartStatusStream
+var statusStream atomic.Pointer[StatusStreamReporter]
+
+// StatusStreamReporter reports device status to edgecore through ReportDeviceStatusStream.
+// Device status are sent in batches of at most batchSize, a partial batch is sent after flushInterval.
+// At most maxInFlight batches wait for the acknowledgement of edgecore, when the window is full
+// Report blocks until edgecore catches up, so fast devices can not saturate the socket.
+// If edgecore does not support the stream, device status are reported one by one through ReportDeviceStatus.
+type StatusStreamReporter struct {
+	batchSize     int
+	flushInterval time.Duration
+	maxInFlight   int
+	requests      chan *dmiapi.ReportDeviceStatusRequest
+	// dial connects to the DMI server of edgecore
+	dial func(ctx context.Context) (*grpc.ClientConn, error)
+}
+
+// StartStatusStream runs r until ctx is done, device status reported by ReportDeviceStatusByStream
+// are sent through it meanwhile
+func StartStatusStream(ctx context.Context, r *StatusStreamReporter) {
+	statusStream.Store(r)
+	defer statusStream.CompareAndSwap(r, nil)
+	r.Run(ctx)
+}
+
+// ReportDeviceStatusByStream queues the device status on the reporter started by StartStatusStream,
+// it returns ErrStatusStreamNotRunning if there is none, so the caller can fall back to ReportDeviceStatus
+func ReportDeviceStatusByStream(ctx context.Context, request *dmiapi.ReportDeviceStatusRequest) error {
+	r := statusStream.Load()
+	if r == nil {
+		return ErrStatusStreamNotRunning
+	}
+	return r.Report(ctx, request)
+}
+
+// NewStatusStreamReporter creates a StatusStreamReporter, non-positive values fall back to defaults
+func NewStatusStreamReporter(batchSize int, flushInterval time.Duration, maxInFlight int) *StatusStreamReporter {
+	if batchSize <= 0 {
+		batchSize = DefaultStreamBatchSize
+	}
+	if flushInterval <= 0 {
+		flushInterval = DefaultStreamFlushInterval
+	}
+	if maxInFlight <= 0 {
+		maxInFlight = DefaultStreamMaxInFlight
+	}
+	return &StatusStreamReporter{
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		maxInFlight:   maxInFlight,
+		requests:      make(chan *dmiapi.ReportDeviceStatusRequest, batchSize*maxInFlight),
+		dial:          dialEdgeCore,
+	}
+}
+
+// Report queues the device status to be sent, it blocks while the queue is full
+func (r *StatusStreamReporter) Report(ctx context.Context, request *dmiapi.ReportDeviceStatusRequest) error {
+	select {
+	case r.requests <- request:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run sends the queued device status to edgecore until ctx is done, the stream is reopened if it breaks.
+// Batches sent but not acknowledged when the stream breaks are sent again through the next stream,
+// a batch handled by edgecore whose acknowledgement was lost may be reported twice.
+func (r *StatusStreamReporter) Run(ctx context.Context) {
+	var pending []*dmiapi.ReportDeviceStatusBatch
+	for {
+		var err error
+		pending, err = r.runStream(ctx, pending)
+		if ctx.Err() != nil {
+			return
+		}
+		if status.Code(err) == codes.Unimplemented {
+			klog.Warningf("edgecore does not support the device status stream, report device status one by one")
+			r.runUnary(ctx, pending)
+			return
+		}
+		klog.Errorf("device status stream is broken, reconnect after %v: %v", streamRetryInterval, err)
+		select {
+		case <-time.After(streamRetryInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runStream opens a stream and sends batches until it breaks. It returns the batches not acknowledged
+// by edgecore, in the order they were queued, so they can be sent again through the next stream.
+func (r *StatusStreamReporter) runStream(ctx context.Context, pending []*dmiapi.ReportDeviceStatusBatch) ([]*dmiapi.ReportDeviceStatusBatch, error) {
+	conn, err := r.dial(ctx)
+	if err != nil {
+		return pending, err
+	}
+	defer conn.Close()
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := dmiapi.NewDeviceManagerServiceClient(conn).ReportDeviceStatusStream(streamCtx)
+	if err != nil {
+		return pending, err
+	}
+
+	inFlight := make(chan *dmiapi.ReportDeviceStatusBatch, r.maxInFlight)
+	recvErr := make(chan error, 1)
+	recvDone := make(chan struct{})
+	go func() {
+		defer close(recvDone)
+		for {
+			ack, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			if ack.Error != "" {
+				klog.Warningf("device status batch %d is partially rejected, accepted %d: %s", ack.Sequence, ack.Accepted, ack.Error)
+			}
+			<-inFlight
+		}
+	}()
+	// unacknowledged closes the stream and returns the batches in flight followed by pending
+	unacknowledged := func(pending []*dmiapi.ReportDeviceStatusBatch) []*dmiapi.ReportDeviceStatusBatch {
+		cancel()
+		<-recvDone
+		var batches []*dmiapi.ReportDeviceStatusBatch
+		for {
+			select {
+			case batch := <-inFlight:
+				batches = append(batches, batch)
+			default:
+				return append(batches, pending...)
+			}
+		}
+	}
+
+	var sequence uint64
+	for {
+		if len(pending) == 0 {
+			batch, err := r.nextBatch(streamCtx, recvErr)
+			if err != nil {
+				return unacknowledged(nil), err
+			}
+			pending = append(pending, batch)
+		}
+		// wait for a slot in the window of unacknowledged batches
+		select {
+		case inFlight <- pending[0]:
+		case err := <-recvErr:
+			return unacknowledged(pending), err
+		case <-streamCtx.Done():
+			return unacknowledged(pending), streamCtx.Err()
+		}
+		sequence++
+		pending[0].Sequence = sequence
+		if err := stream.Send(pending[0]); err != nil {
+			// the batch is in flight, it is returned by unacknowledged
+			pending = pending[1:]
+			if errors.Is(err, io.EOF) {
+				// the stream is closed by edgecore, the reason is returned by Recv
+				err = <-recvErr
+			}
+			return unacknowledged(pending), err
+		}
+		pending = pending[1:]
+	}
+}
+
+// runUnary reports the pending batches and then the queued device status one by one through
+// ReportDeviceStatus until ctx is done, it is used if edgecore does not support the stream
+func (r *StatusStreamReporter) runUnary(ctx context.Context, pending []*dmiapi.ReportDeviceStatusBatch) {
+	report := func(request *dmiapi.ReportDeviceStatusRequest) {
+		if err := r.reportUnary(ctx, request); err != nil {
+			klog.Errorf("fail to report device status of %s with err: %+v", request.DeviceName, err)
+		}
+	}
+	for _, batch := range pending {
+		for _, request := range batch.Items {
+			report(request)
+		}
+	}
+	for {
+		select {
+		case request := <-r.requests:
+			report(request)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *StatusStreamReporter) reportUnary(ctx context.Context, request *dmiapi.ReportDeviceStatusRequest) error {
+	conn, err := r.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	_, err = dmiapi.NewDeviceManagerServiceClient(conn).ReportDeviceStatus(ctx, request)
+	return err
+}
+
+// nextBatch collects the queued device status into a batch, it waits for the first one
+// and returns when the batch is full or flushInterval passed
+func (r *StatusStreamReporter) nextBatch(ctx context.Context, recvErr <-chan error) (*dmiapi.ReportDeviceStatusBatch, error) {
+	batch := &dmiapi.ReportDeviceStatusBatch{}
+	select {
+	case request := <-r.requests:
+		batch.Items = append(batch.Items, request)
+	case err := <-recvErr:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	timer := time.NewTimer(r.flushInterval)
+	defer timer.Stop()
+	for len(batch.Items) < r.batchSize {
+		select {
+		case request := <-r.requests:
+			batch.Items = append(batch.Items, request)
+		case <-timer.C:
+			return batch, nil
+		case <-ctx.Done():
+			return batch, nil
+		}
+	}
+	return batch, nil
+}
+
+// dialEdgeCore connects to the DMI server of edgecore
+func dialEdgeCore(ctx context.Context) (*grpc.ClientConn, error) {
+	cfg := config.Cfg()
+
+	dialCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(dialCtx, cfg.Common.EdgeCoreSock,
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+		grpc.WithContextDialer(
+			func(ctx context.Context, s string) (net.Conn, error) {
+				unixAddress, err := net.ResolveUnixAddr("unix", cfg.Common.EdgeCoreSock)
+				if err != nil {
+					return nil, err
+				}
+				return net.DialUnix("unix", nil, unixAddress)
+			},
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("did not connect: %v", err)
+	}
+	return conn, nil
+}
@@ -0,0 +1,170 @@
+package grpcclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	dmiapi "github.com/kubeedge/api/apis/dmi/v1beta1"
+)
+
+// fakeDeviceManager records the device status reported by the mapper
+type fakeDeviceManager struct {
+	dmiapi.UnimplementedDeviceManagerServiceServer
+	streaming bool
+	// breakStream breaks the first stream after receiving a batch without acknowledging it
+	breakStream bool
+
+	mu      sync.Mutex
+	devices []string
+	batches int
+}
+
+func (f *fakeDeviceManager) ReportDeviceStatus(_ context.Context, request *dmiapi.ReportDeviceStatusRequest) (*dmiapi.ReportDeviceStatusResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.devices = append(f.devices, request.DeviceName)
+	return &dmiapi.ReportDeviceStatusResponse{}, nil
+}
+
+func (f *fakeDeviceManager) ReportDeviceStatusStream(stream dmiapi.DeviceManagerService_ReportDeviceStatusStreamServer) error {
+	if !f.streaming {
+		return f.UnimplementedDeviceManagerServiceServer.ReportDeviceStatusStream(stream)
+	}
+	for {
+		batch, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		f.mu.Lock()
+		if f.breakStream {
+			f.breakStream = false
+			f.mu.Unlock()
+			return status.Error(codes.Unavailable, "stream is broken")
+		}
+		f.batches++
+		for _, item := range batch.Items {
+			f.devices = append(f.devices, item.DeviceName)
+		}
+		f.mu.Unlock()
+		if err := stream.Send(&dmiapi.ReportDeviceStatusAck{Sequence: batch.Sequence, Accepted: int32(len(batch.Items))}); err != nil {
+			return err
+		}
+	}
+}
+
+func (f *fakeDeviceManager) reported() ([]string, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.devices...), f.batches
+}
+
+// startFakeDeviceManager serves f on a unix socket and returns a dial function connecting to it
+func startFakeDeviceManager(t *testing.T, f *fakeDeviceManager) func(ctx context.Context) (*grpc.ClientConn, error) {
+	sock := filepath.Join(t.TempDir(), "dmi.sock")
+	lis, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := grpc.NewServer()
+	dmiapi.RegisterDeviceManagerServiceServer(server, f)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+	return func(ctx context.Context) (*grpc.ClientConn, error) {
+		return grpc.DialContext(ctx, "unix://"+sock, grpc.WithInsecure(), grpc.WithBlock())
+	}
+}
+
+func runReporter(t *testing.T, f *fakeDeviceManager, devices []string) []string {
+	r := NewStatusStreamReporter(2, 10*time.Millisecond, 1)
+	r.dial = startFakeDeviceManager(t, f)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	defer func() {
+		cancel()
+		<-stopped
+	}()
+	go func() {
+		StartStatusStream(ctx, r)
+		close(stopped)
+	}()
+	// the reporter is registered by StartStatusStream in its own goroutine
+	for statusStream.Load() != r {
+		time.Sleep(time.Millisecond)
+	}
+
+	for _, name := range devices {
+		request := &dmiapi.ReportDeviceStatusRequest{DeviceName: name, DeviceNamespace: "default"}
+		if err := ReportDeviceStatusByStream(ctx, request); err != nil {
+			t.Fatalf("ReportDeviceStatusByStream() error = %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if got, _ := f.reported(); len(got) == len(devices) {
+			return got
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	got, _ := f.reported()
+	t.Fatalf("reported devices = %v, want %v", got, devices)
+	return nil
+}
+
+func TestStatusStreamReporter(t *testing.T) {
+	f := &fakeDeviceManager{streaming: true}
+	devices := []string{"sensor-a", "sensor-b", "sensor-c", "sensor-d", "sensor-e"}
+	got := runReporter(t, f, devices)
+	for i := range devices {
+		if got[i] != devices[i] {
+			t.Fatalf("reported devices = %v, want %v", got, devices)
+		}
+	}
+	if _, batches := f.reported(); batches < 3 {
+		t.Errorf("expected at least 3 batches of at most 2 items, got %d", batches)
+	}
+}
+
+func TestStatusStreamReporterResend(t *testing.T) {
+	f := &fakeDeviceManager{streaming: true, breakStream: true}
+	devices := []string{"sensor-a", "sensor-b", "sensor-c"}
+	got := runReporter(t, f, devices)
+	for i := range devices {
+		if got[i] != devices[i] {
+			t.Fatalf("reported devices = %v, want %v", got, devices)
+		}
+	}
+}
+
+func TestStatusStreamReporterFallback(t *testing.T) {
+	f := &fakeDeviceManager{streaming: false}
+	devices := []string{"sensor-a", "sensor-b", "sensor-c"}
+	got := runReporter(t, f, devices)
+	for i := range devices {
+		if got[i] != devices[i] {
+			t.Fatalf("reported devices = %v, want %v", got, devices)
+		}
+	}
+}
+
+func TestReportDeviceStatusByStreamNotRunning(t *testing.T) {
+	err := ReportDeviceStatusByStream(context.Background(), &dmiapi.ReportDeviceStatusRequest{DeviceName: "sensor-a"})
+	if !errors.Is(err, ErrStatusStreamNotRunning) {
+		t.Errorf("ReportDeviceStatusByStream() error = %v, want %v", err, ErrStatusStreamNotRunning)
+	}
+}